@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/config"
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/db/callback"
+	"github.com/engineeringinflow/inflow-backend/pkg/locker"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/spf13/cobra"
+)
+
+// backfillPaymentMilestonesCmd copies the legacy payment columns of the orders without a schedule into payment_milestones, it is safe to run again
+var backfillPaymentMilestonesCmd = &cobra.Command{
+	Use:   "backfill_payment_milestones",
+	Short: "backfill the payment milestones of the bulk purchase orders",
+	Run: func(cmd *cobra.Command, args []string) {
+		var config = config.New(cfgFile, config.BuildInfo{
+			BuildEnv:         Env,
+			BuildServiceName: Name,
+			BuildVersion:     Version,
+		})
+
+		var logger = logger.Init(
+			logger.WithLogDir("logs/backend"),
+			logger.WithDebug(true),
+			logger.WithConsole(true),
+		)
+		defer logger.Sync()
+
+		locker.New(config)
+
+		var db = db.New(config, callback.New(), nil)
+
+		if err := repo.NewPaymentMilestoneRepo(db).BackfillPaymentMilestones(); err != nil {
+			logger.Errorf("Backfill payment milestones error: %+v", err)
+			os.Exit(1)
+		}
+
+		logger.Infof("Backfill payment milestones done")
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(backfillPaymentMilestonesCmd)
+}
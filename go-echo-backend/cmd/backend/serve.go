@@ -15,6 +15,7 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/mailer"
 	"github.com/engineeringinflow/inflow-backend/pkg/migration"
 	"github.com/engineeringinflow/inflow-backend/pkg/oauth"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
 	"github.com/engineeringinflow/inflow-backend/pkg/s3"
 	"github.com/engineeringinflow/inflow-backend/pkg/seeder"
 	"github.com/engineeringinflow/inflow-backend/pkg/shopify"
@@ -60,6 +61,10 @@ var serveCmd = &cobra.Command{
 
 		migration.New(db).AutoMigrate()
 
+		if err := repo.NewSearchRepo(db).SetupSearchIndexes(); err != nil {
+			db.CustomLogger.Errorf("Setup search indexes error: %+v", err)
+		}
+
 		seeder.New(db).SeedAccounts()
 
 		var mailer = mailer.New(config)
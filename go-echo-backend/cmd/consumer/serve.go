@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/app"
 	"github.com/engineeringinflow/inflow-backend/pkg/caching"
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
@@ -73,8 +75,12 @@ var serveCmd = &cobra.Command{
 
 		go func() {
 			var c = consumer.New(app, true)
+			if err := c.CheckQueuedTasks(); err != nil {
+				c.Logger.PanicAny(err, "Check queued tasks")
+			}
 			c.ServeMonitoringRoutes(router.Echo)
 			c.Run()
+			c.RunOutboxRelay(context.Background())
 		}()
 
 		router.ListenAndServe()
@@ -3,6 +3,7 @@ package caching
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/eko/gocache/lib/v4/cache"
@@ -10,13 +11,23 @@ import (
 	gocache_store "github.com/eko/gocache/store/go_cache/v4"
 	redis_store "github.com/eko/gocache/store/redis/v4"
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
 	gocache "github.com/patrickmn/go-cache"
+	"github.com/rs/xid"
+	"golang.org/x/sync/singleflight"
 
 	redis "github.com/redis/go-redis/v9"
 )
 
+// Client two tier cache, the in-memory tier of each pod is checked first and kept in sync through redis pub/sub
 type Client struct {
-	cache *cache.ChainCache[string]
+	cache     *cache.ChainCache[string]
+	redis     *redis.Client
+	local     *gocache.Cache
+	namespace string
+	nodeID    string
+	group     *singleflight.Group
+	logger    *logger.Logger
 }
 
 var client *Client
@@ -26,22 +37,37 @@ func New(cnf *config.Configuration) *Client {
 		return client
 	}
 
-	redisStore := redis_store.NewRedis(redis.NewClient(&redis.Options{
-		Addr: cnf.RedisAddress[0],
-	}))
+	client = NewClient(redis.NewClient(&redis.Options{
+		Addr:     cnf.RedisAddress[0],
+		Password: cnf.RedisPassword,
+		DB:       cnf.RedisDBMode,
+	}), cnf.RedisNamespace)
 
-	gocacheStore := gocache_store.NewGoCache(gocache.New(5*time.Minute, 10*time.Minute))
+	go client.subscribe(context.Background())
 
-	cacheManager := cache.NewChain[string](
-		cache.New[string](redisStore),
-		cache.New[string](gocacheStore),
-	)
+	return client
+}
 
-	client = &Client{
-		cache: cacheManager,
+// NewClient client over the redis connection, New also listens to the invalidations of other pods
+func NewClient(redisClient *redis.Client, namespace string) *Client {
+	if namespace == "" {
+		namespace = "inflow"
 	}
 
-	return client
+	var local = gocache.New(5*time.Minute, 10*time.Minute)
+
+	return &Client{
+		cache: cache.NewChain[string](
+			cache.New[string](gocache_store.NewGoCache(local)),
+			cache.New[string](redis_store.NewRedis(redisClient)),
+		),
+		redis:     redisClient,
+		local:     local,
+		namespace: namespace,
+		nodeID:    xid.New().String(),
+		group:     &singleflight.Group{},
+		logger:    logger.New("caching"),
+	}
 }
 
 func GetInstance() *Client {
@@ -71,16 +97,41 @@ func (c *Client) Set(key string, data any, options ...store.Option) error {
 
 }
 
+// Delete removes the key from redis and from the in-memory tier of every pod
 func (c *Client) Delete(key string) error {
-	return c.cache.Delete(context.Background(), key)
+	var err = c.cache.Delete(context.Background(), key)
+	if err != nil {
+		return err
+	}
 
+	return c.publishInvalidation(context.Background(), invalidation{Keys: []string{key}})
 }
 
 func (c *Client) Clear() error {
-	return c.cache.Clear(context.Background())
+	var err = c.cache.Clear(context.Background())
+	if err != nil {
+		return err
+	}
 
+	return c.publishInvalidation(context.Background(), invalidation{Clear: true})
 }
 
 func (c *Client) GetClient() *cache.ChainCache[string] {
 	return c.cache
 }
+
+func (c *Client) getRedisKey(key string) string {
+	return fmt.Sprintf("%s_cache_%s", c.namespace, key)
+}
+
+func (c *Client) getTagKey(tag string) string {
+	return fmt.Sprintf("%s_cache_tag_%s", c.namespace, tag)
+}
+
+func (c *Client) getTagVersionKey(tag string) string {
+	return fmt.Sprintf("%s_cache_tag_version_%s", c.namespace, tag)
+}
+
+func (c *Client) getInvalidationChannel() string {
+	return fmt.Sprintf("%s_cache_invalidation", c.namespace)
+}
@@ -0,0 +1,127 @@
+package caching
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Tagger models whose writes invalidate the cached reads tagged with CacheTags
+type Tagger interface {
+	CacheTags() []string
+}
+
+// RegisterCallbacks invalidates the tags of the created, updated and deleted rows.
+// Updates without a loaded row, like Model(&Category{}).Where(...).Updates(...), invalidate the tags of the zero model.
+// Inside a transaction started by Transaction the tags are invalidated after the commit, a read before it would cache the old rows.
+func (c *Client) RegisterCallbacks(db *gorm.DB) {
+	db.Callback().Create().After("gorm:create").Register("app:invalidate_cache_tags_when_create", c.invalidateStatementTags)
+	db.Callback().Update().After("gorm:update").Register("app:invalidate_cache_tags_when_update", c.invalidateStatementTags)
+	db.Callback().Delete().After("gorm:delete").Register("app:invalidate_cache_tags_when_delete", c.invalidateStatementTags)
+}
+
+func (c *Client) invalidateStatementTags(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.RowsAffected == 0 {
+		return
+	}
+
+	var tags = GetStatementTags(db.Statement.Schema.ModelType, db.Statement.ReflectValue)
+	if len(tags) == 0 {
+		return
+	}
+
+	var ctx = db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if pending, ok := ctx.Value(txTagsKey{}).(*txTags); ok {
+		if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+			pending.add(tags...)
+			return
+		}
+	}
+
+	if err := c.InvalidateTags(ctx, tags...); err != nil {
+		c.logger.Errorf("Invalidate cache tags=%v err=%+v", tags, err)
+	}
+}
+
+type txTagsKey struct{}
+
+// txTags tags written inside a transaction, invalidated once it commits
+type txTags struct {
+	mu   sync.Mutex
+	tags []string
+}
+
+func (t *txTags) add(tags ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tags = append(t.tags, tags...)
+}
+
+// Transaction runs fc in a transaction of db and invalidates the tags of the rows it wrote after the commit.
+// A rolled back transaction invalidates nothing, nested transactions leave the invalidation to the outer one.
+func (c *Client) Transaction(db *gorm.DB, fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	var ctx = db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, ok := ctx.Value(txTagsKey{}).(*txTags); ok {
+		return db.Transaction(fc, opts...)
+	}
+
+	var pending = &txTags{}
+	var err = db.WithContext(context.WithValue(ctx, txTagsKey{}, pending)).Transaction(fc, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := c.InvalidateTags(ctx, pending.tags...); err != nil {
+		c.logger.Errorf("Invalidate cache tags=%v err=%+v", pending.tags, err)
+	}
+
+	return nil
+}
+
+// GetStatementTags tags of the rows of a statement, the tags of the zero model are always included
+func GetStatementTags(modelType reflect.Type, rv reflect.Value) (tags []string) {
+	var appendTags = func(v reflect.Value) {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct || !v.CanAddr() {
+			return
+		}
+		if tagger, ok := v.Addr().Interface().(Tagger); ok {
+			tags = append(tags, tagger.CacheTags()...)
+		}
+	}
+
+	if modelType != nil {
+		appendTags(reflect.New(modelType))
+	}
+
+	if !rv.IsValid() {
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			appendTags(rv.Index(i))
+		}
+	default:
+		appendTags(rv)
+	}
+
+	return
+}
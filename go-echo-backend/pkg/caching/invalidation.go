@@ -0,0 +1,69 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rotisserie/eris"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// invalidation published to the other pods so they drop the keys from their in-memory tier
+type invalidation struct {
+	NodeID string   `json:"node_id"`
+	Keys   []string `json:"keys,omitempty"`
+	Clear  bool     `json:"clear,omitempty"`
+}
+
+func (c *Client) publishInvalidation(ctx context.Context, message invalidation) error {
+	message.NodeID = c.nodeID
+	data, err := json.Marshal(message)
+	if err != nil {
+		return eris.Wrap(err, "marshal cache invalidation")
+	}
+
+	if err := c.redis.Publish(ctx, c.getInvalidationChannel(), data).Err(); err != nil {
+		return eris.Wrapf(err, "publish cache invalidation keys=%v", message.Keys)
+	}
+
+	return nil
+}
+
+// subscribe applies the invalidations of the other pods until the context is cancelled.
+// Messages missed while disconnected are not replayed, the in-memory TTL bounds how long those values stay stale.
+func (c *Client) subscribe(ctx context.Context) {
+	var pubsub = c.redis.Subscribe(ctx, c.getInvalidationChannel())
+	defer pubsub.Close()
+
+	var messages = pubsub.Channel(redis.WithChannelSize(1024))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var message invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				c.logger.Errorf("Decode cache invalidation payload=%s err=%+v", msg.Payload, err)
+				continue
+			}
+
+			if message.NodeID == c.nodeID {
+				continue
+			}
+
+			if message.Clear {
+				c.local.Flush()
+				continue
+			}
+
+			for _, key := range message.Keys {
+				c.local.Delete(key)
+			}
+		}
+	}
+}
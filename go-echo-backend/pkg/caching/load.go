@@ -0,0 +1,237 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLoadTTL = time.Minute * 10
+	localMaxTTL    = time.Minute
+	tagTTL         = time.Hour * 24
+)
+
+// setScript stores the value only when none of its tags were invalidated while it was loading,
+// otherwise a load started before a write could put the old rows back in the cache.
+// KEYS: value key, tag keys..., tag version keys... ARGV: value, ttl seconds, tag ttl seconds, tag versions...
+var setScript = redis.NewScript(`
+local n = (#KEYS - 1) / 2
+for i = 1, n do
+	local version = redis.call('GET', KEYS[1 + n + i]) or '0'
+	if version ~= ARGV[3 + i] then
+		return 0
+	end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+for i = 1, n do
+	redis.call('SADD', KEYS[1 + i], KEYS[1])
+	redis.call('EXPIRE', KEYS[1 + i], ARGV[3])
+end
+return 1
+`)
+
+// invalidateScript bumps the tag versions and deletes the tagged keys, it returns the deleted keys.
+// KEYS: tag keys..., tag version keys...
+var invalidateScript = redis.NewScript(`
+local n = #KEYS / 2
+local keys = {}
+for i = 1, n do
+	redis.call('INCR', KEYS[n + i])
+	redis.call('EXPIRE', KEYS[n + i], ARGV[1])
+	for _, key in ipairs(redis.call('SMEMBERS', KEYS[i])) do
+		table.insert(keys, key)
+	end
+	redis.call('DEL', KEYS[i])
+end
+for _, key in ipairs(keys) do
+	redis.call('DEL', key)
+end
+return keys
+`)
+
+type loadOptions struct {
+	ttl  time.Duration
+	tags []string
+}
+
+type LoadOption func(*loadOptions)
+
+// WithTTL how long the loaded value stays in redis, the in-memory tier keeps it at most a minute
+func WithTTL(ttl time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithTags tags of the value, InvalidateTags with any of them drops it
+func WithTags(tags ...string) LoadOption {
+	return func(o *loadOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// GetOrLoad returns the cached value of the key or the result of loader, which runs once per key and pod
+// for concurrent callers. The cache never fails the read: on a redis error the loader result is returned uncached.
+func GetOrLoad[T any](ctx context.Context, c *Client, key string, loader func() (T, error), opts ...LoadOption) (result T, err error) {
+	if c == nil {
+		return loader()
+	}
+
+	var options = loadOptions{ttl: defaultLoadTTL}
+	for _, apply := range opts {
+		apply(&options)
+	}
+	options.tags = lo.Uniq(lo.Compact(options.tags))
+
+	var redisKey = c.getRedisKey(key)
+	if value, found := c.local.Get(redisKey); found {
+		if err := json.Unmarshal(value.([]byte), &result); err == nil {
+			return result, nil
+		}
+	}
+
+	value, err, _ := c.group.Do(redisKey, func() (any, error) {
+		if data, err := c.redis.Get(ctx, redisKey).Bytes(); err == nil {
+			var cached T
+			if err := json.Unmarshal(data, &cached); err == nil {
+				c.local.Set(redisKey, data, lo.Min([]time.Duration{options.ttl, localMaxTTL}))
+				return cached, nil
+			}
+		} else if err != redis.Nil {
+			c.logger.Debugf("Get cache key=%s err=%+v", key, err)
+		}
+
+		// Versions are read before loading so an invalidation during the load is detected
+		versions, versionErr := c.getTagVersions(ctx, options.tags)
+
+		loaded, err := loader()
+		if err != nil {
+			return loaded, err
+		}
+
+		if versionErr != nil {
+			c.logger.Debugf("Get cache tag versions key=%s err=%+v", key, versionErr)
+			return loaded, nil
+		}
+
+		if err := c.store(ctx, redisKey, loaded, options, versions); err != nil {
+			c.logger.Debugf("Set cache key=%s err=%+v", key, err)
+		}
+
+		return loaded, nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if v, ok := value.(T); ok {
+		result = v
+	}
+
+	return result, nil
+}
+
+func (c *Client) getTagVersions(ctx context.Context, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	var keys = lo.Map(tags, func(tag string, _ int) string { return c.getTagVersionKey(tag) })
+	values, err := c.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, eris.Wrapf(err, "get tag versions tags=%v", tags)
+	}
+
+	return lo.Map(values, func(v any, _ int) string {
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return "0"
+	}), nil
+}
+
+func (c *Client) store(ctx context.Context, redisKey string, value any, options loadOptions, versions []string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return eris.Wrapf(err, "marshal key=%s", redisKey)
+	}
+
+	var keys = []string{redisKey}
+	// The tag sets outlive the values they reference
+	var args = []any{data, int(options.ttl.Seconds()), int(lo.Max([]time.Duration{options.ttl, tagTTL}).Seconds())}
+	for _, tag := range options.tags {
+		keys = append(keys, c.getTagKey(tag))
+	}
+	for i, tag := range options.tags {
+		keys = append(keys, c.getTagVersionKey(tag))
+		args = append(args, versions[i])
+	}
+
+	stored, err := setScript.Run(ctx, c.redis, keys, args...).Int()
+	if err != nil {
+		return eris.Wrapf(err, "store key=%s", redisKey)
+	}
+
+	// Only values that made it to redis are kept in memory, so every cached value can be invalidated
+	if stored == 1 {
+		c.local.Set(redisKey, data, lo.Min([]time.Duration{options.ttl, localMaxTTL}))
+	}
+
+	return nil
+}
+
+// InvalidateTags drops the values loaded with any of the tags, on every pod
+func (c *Client) InvalidateTags(ctx context.Context, tags ...string) error {
+	if c == nil {
+		return nil
+	}
+
+	tags = lo.Uniq(lo.Compact(tags))
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var keys = lo.Map(tags, func(tag string, _ int) string { return c.getTagKey(tag) })
+	for _, tag := range tags {
+		keys = append(keys, c.getTagVersionKey(tag))
+	}
+
+	deleted, err := invalidateScript.Run(ctx, c.redis, keys, int(tagTTL.Seconds())).StringSlice()
+	if err != nil {
+		return eris.Wrapf(err, "invalidate tags=%v", tags)
+	}
+
+	return c.invalidateLocal(ctx, deleted)
+}
+
+// Invalidate drops the values of the keys passed to GetOrLoad, on every pod
+func (c *Client) Invalidate(ctx context.Context, keys ...string) error {
+	if c == nil || len(keys) == 0 {
+		return nil
+	}
+
+	var redisKeys = lo.Map(keys, func(key string, _ int) string { return c.getRedisKey(key) })
+	if err := c.redis.Del(ctx, redisKeys...).Err(); err != nil {
+		return eris.Wrapf(err, "invalidate keys=%v", keys)
+	}
+
+	return c.invalidateLocal(ctx, redisKeys)
+}
+
+func (c *Client) invalidateLocal(ctx context.Context, redisKeys []string) error {
+	if len(redisKeys) == 0 {
+		return nil
+	}
+
+	for _, key := range redisKeys {
+		c.local.Delete(key)
+	}
+
+	return c.publishInvalidation(ctx, invalidation{Keys: redisKeys})
+}
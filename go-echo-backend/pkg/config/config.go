@@ -164,6 +164,12 @@ type Configuration struct {
 
 	ResetPasswordResendInterval time.Duration `mapstructure:"RESET_PASSWORD_RESEND_INTERVAL" json:"reset_password_resend_interval"`
 
+	// IdempotencyKeyWindow how long the responses of requests with an Idempotency-Key are replayed, 24h when unset
+	IdempotencyKeyWindow time.Duration `mapstructure:"IDEMPOTENCY_KEY_WINDOW" json:"idempotency_key_window"`
+
+	// DeadLetterAlertThreshold archived tasks within the alert window which raise an alert, 20 when unset
+	DeadLetterAlertThreshold int `mapstructure:"DEAD_LETTER_ALERT_THRESHOLD" json:"dead_letter_alert_threshold"`
+
 	CasbinModelConfURL    string `mapstructure:"CASBIN_MODEL_CONF_URL" json:"casbin_model_conf_url"`
 	CasbinPolicyCSVURL    string `mapstructure:"CASBIN_POLICY_CSV_URL" json:"casbin_policy_csv_url"`
 	GoogleClientSecretURL string `mapstructure:"GOOGLE_CLIENT_SECRET_URL" json:"google_client_secret_url"`
@@ -33,6 +33,7 @@ var (
 	EventAdminPurchaseOrderAssignPIC     Event = "admin_purchase_order_assign_pic"
 	EventAdminBulkPurchaseOrderAssignPIC Event = "admin_bulk_purchase_order_assign_pic"
 	EventAdminSendTNANotification        Event = "admin_send_tna_notification"
+	EventAdminTNASlippage                Event = "admin_tna_slippage"
 
 	EventSellerNewRFQRequest                 Event = "seller_new_rfq_request"
 	EventSellerNewBulkPurchaseOrderQuotation Event = "seller_new_bulk_purchase_order_quotation"
@@ -107,6 +108,9 @@ var (
 	EventBulkPoBuyerDepositSucceeded Event = "bulk_po_buyer_deposit_succeeded"
 	EventBulkPoDepositSucceeded      Event = "bulk_po_deposit_succeeded"
 
+	EventBulkPoBuyerMilestonePaymentSucceeded Event = "bulk_po_buyer_milestone_payment_succeeded"
+	EventBulkPoMilestonePaymentSucceeded      Event = "bulk_po_milestone_payment_succeeded"
+
 	EventAdminInquiryNewNotes Event = "admin_inquiry_new_notes"
 	EventAdminPONewNotes      Event = "admin_po_new_notes"
 	EventAdminPoCanceled      Event = "admin_po_canceled"
@@ -89,6 +89,10 @@ func New(config *config.Configuration, callback CallbackInterface, caching *cach
 		callback.Register(db)
 	}
 
+	if caching != nil {
+		caching.RegisterCallbacks(db)
+	}
+
 	sqlDB.SetMaxIdleConns(50)
 	sqlDB.SetMaxOpenConns(200)
 	sqlDB.SetConnMaxLifetime(1 * time.Hour)
@@ -210,6 +214,15 @@ func (db *DB) WithContext(ctx context.Context) *DB {
 
 }
 
+// Transaction runs fc in a transaction, the cached reads of the written rows are invalidated after the commit
+func (db *DB) Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	if db.Cache == nil {
+		return db.DB.Transaction(fc, opts...)
+	}
+
+	return db.Cache.Transaction(db.DB, fc, opts...)
+}
+
 // IsRecordNotFoundError check record not found
 func (db *DB) IsRecordNotFoundError(err error) bool {
 	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, gorm.ErrRecordNotFound)
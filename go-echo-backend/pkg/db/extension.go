@@ -2,4 +2,5 @@ package db
 
 func (db *DB) setupExtensions() {
 	db.Exec("CREATE EXTENSION IF NOT EXISTS citext;")
+	db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm;")
 }
@@ -13,9 +13,21 @@ var arrayUniq string
 //go:embed sql/create_unaccent_extensions.sql
 var createUnaccent string
 
+//go:embed sql/search_unaccent.sql
+var searchUnaccent string
+
+//go:embed sql/search_text.sql
+var searchText string
+
+//go:embed sql/search_config.sql
+var searchConfig string
+
 func (db *DB) setupFunctions() {
 
 	db.Exec(countElement)
 	db.Exec(createUnaccent)
+	db.Exec(searchUnaccent)
+	db.Exec(searchText)
+	db.Exec(searchConfig)
 	db.Exec(arrayUniq)
 }
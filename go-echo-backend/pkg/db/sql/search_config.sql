@@ -0,0 +1,11 @@
+-- inflow_search simple config ignoring diacritics, ts_headline highlights the original text with it
+DO
+$$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'inflow_search') THEN
+        CREATE TEXT SEARCH CONFIGURATION inflow_search (COPY = simple);
+        ALTER TEXT SEARCH CONFIGURATION inflow_search
+            ALTER MAPPING FOR hword, hword_part, word WITH public.unaccent, simple;
+    END IF;
+END
+$$;
@@ -0,0 +1,7 @@
+-- search_text the searched document, lower case and without diacritics
+CREATE OR REPLACE FUNCTION search_text(VARIADIC text[]) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS
+$$
+SELECT lower(f_unaccent(array_to_string($1, ' ')))
+$$;
@@ -0,0 +1,7 @@
+-- unaccent is STABLE, the wrapper is IMMUTABLE so it can be used by the search indexes
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS
+$$
+SELECT public.unaccent('public.unaccent', $1)
+$$;
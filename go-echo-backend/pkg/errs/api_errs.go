@@ -204,6 +204,10 @@ var (
 	ErrBulkPoNotAbleToConfirm                   = New(400015, "Bulk order is not able to confirm", http.StatusUnprocessableEntity)
 	ErrBulkPoNotAbleToUpdatePO                  = New(400016, "Bulk order is not able to update PO", http.StatusUnprocessableEntity)
 	ErrBulkQuotationEmpty                       = New(400017, "Bulk quotation is required", http.StatusUnprocessableEntity)
+	ErrBulkPoInvalidTransition                  = New(400018, "Bulk order is not able to perform this action in its current status", http.StatusUnprocessableEntity)
+	ErrBulkPoTransitionForbidden                = New(400019, "You are not allowed to perform this action on the bulk order", http.StatusForbidden)
+	ErrBulkPoRawMaterialNotApproved             = New(400020, "Bulk order raw materials must be approved by buyer first", http.StatusUnprocessableEntity)
+	ErrBulkPoTrackingStatusChanged              = New(400021, "Bulk order status was changed by another request, please reload", http.StatusConflict)
 )
 
 var (
@@ -212,6 +216,10 @@ var (
 	ErrPaymentTransactionInvoiceAlreadyGenerated = New(500002, "The transaction's invoice is already generated", http.StatusUnprocessableEntity)
 	ErrPaymentTransactionNotFound                = New(500002, "The transaction is not found", http.StatusUnprocessableEntity)
 	ErrPaymentTransactionNotPaid                 = New(500003, "The transaction is not paid", http.StatusUnprocessableEntity)
+	ErrPaymentMilestoneNotFound                  = New(500004, "Payment milestone is not found", http.StatusNotFound)
+	ErrPaymentMilestoneAlreadyPaid               = New(500005, "Payment milestone is already paid", http.StatusUnprocessableEntity)
+	ErrPaymentMilestoneInvalidSchedule           = New(500006, "Payment milestones must add up to the order total", http.StatusUnprocessableEntity)
+	ErrPaymentMilestoneScheduleLocked            = New(500007, "Payment schedule can't be changed after a milestone is paid", http.StatusUnprocessableEntity)
 )
 
 var (
@@ -241,5 +249,71 @@ var (
 )
 
 var (
-	ErrInvoiceGeneratePdfError = New(900001, "Generate invoice's pdf failed", http.StatusUnprocessableEntity)
+	ErrInvoiceGeneratePdfError    = New(900001, "Generate invoice's pdf failed", http.StatusUnprocessableEntity)
+	ErrInvoiceSeriesAlreadyIssued = New(900002, "Invoice series has already issued numbers", http.StatusUnprocessableEntity)
+	ErrInvoiceNotFound            = New(900003, "Invoice not found", http.StatusNotFound)
+	ErrInvoiceNotRefundable       = New(900004, "Only paid invoices can be credited", http.StatusUnprocessableEntity)
+	ErrInvoiceRefundExceedBalance = New(900005, "Credit amount exceeds the invoice balance", http.StatusUnprocessableEntity)
+	ErrInvoiceCannotVoid          = New(900006, "Only unpaid invoices can be voided, credit paid invoices instead", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrOutboxMessageNotFound       = New(1000000, "Outbox message not found", http.StatusNotFound)
+	ErrOutboxMessageNotRetryable   = New(1000001, "Outbox message is already dispatched", http.StatusUnprocessableEntity)
+	ErrOutboxMessageAlreadyPending = New(1000002, "Another outbox message with the same dedupe key is pending", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrFxRateInvalidCSV   = New(1100000, "FX rate file is invalid", http.StatusUnprocessableEntity)
+	ErrFxRateInvalidPair  = New(1100001, "FX rate base and quote currency must differ", http.StatusBadRequest)
+	ErrFxRateDeleteLocked = New(1100002, "FX rate is already snapshotted on documents", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrJournalEntryUnbalanced    = New(1200000, "Journal entry is unbalanced", http.StatusUnprocessableEntity)
+	ErrLedgerExportFormatInvalid = New(1200001, "Ledger export format is invalid", http.StatusBadRequest)
+)
+
+var (
+	ErrBankStatementInvalidFile        = New(1300000, "Bank statement file is invalid", http.StatusUnprocessableEntity)
+	ErrBankStatementLineNotFound       = New(1300001, "Bank statement line not found", http.StatusNotFound)
+	ErrBankStatementLineNotApprovable  = New(1300002, "Bank statement line is already reconciled or ignored", http.StatusUnprocessableEntity)
+	ErrBankStatementLineNoPaymentMatch = New(1300003, "Bank statement line has no pending bank transfer to approve", http.StatusUnprocessableEntity)
+	ErrBankStatementLineAmountMismatch = New(1300004, "Bank statement line amount or currency does not match the bank transfer, approve with force and a note", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrTNADependencyCycle   = New(1400000, "TNA dependencies form a cycle", http.StatusUnprocessableEntity)
+	ErrTNADependencyInvalid = New(1400001, "TNA depends on a task outside of its plan", http.StatusUnprocessableEntity)
+	ErrTNATemplateNotFound  = New(1400002, "TNA template not found", http.StatusNotFound)
+	ErrTNATemplateInvalid   = New(1400003, "TNA template is invalid", http.StatusUnprocessableEntity)
+	ErrTNAPlanAlreadyExists = New(1400004, "TNA plan already exists", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrTopicInvalid   = New(1500000, "Topic is invalid", http.StatusBadRequest)
+	ErrTopicForbidden = New(1500001, "Not allowed to subscribe to the topic", http.StatusForbidden)
+	ErrTopicLimit     = New(1500002, "Too many topic subscriptions", http.StatusUnprocessableEntity)
+)
+
+var (
+	ErrIdempotencyKeyInvalid    = New(1600000, "Idempotency-Key is invalid", http.StatusBadRequest)
+	ErrIdempotencyKeyReused     = New(1600001, "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity)
+	ErrIdempotencyKeyInProgress = New(1600002, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+)
+
+var (
+	ErrDeadLetterTaskNotFound      = New(1700000, "Dead letter task not found", http.StatusNotFound)
+	ErrDeadLetterTaskNotReplayable = New(1700001, "Dead letter task was already replayed or discarded", http.StatusUnprocessableEntity)
+	ErrDeadLetterTaskPayload       = New(1700002, "Dead letter task payload must be a JSON object", http.StatusBadRequest)
+)
+
+var (
+	ErrChatMessageNotFound          = New(1800000, "Chat message not found", http.StatusNotFound)
+	ErrChatMessageNotSender         = New(1800001, "Only the sender can change the message", http.StatusForbidden)
+	ErrChatMessageEditWindowExpired = New(1800002, "The message can no longer be changed", http.StatusUnprocessableEntity)
+	ErrChatMessageRemoved           = New(1800003, "The message was deleted", http.StatusUnprocessableEntity)
+	ErrChatMessageReplyInvalid      = New(1800004, "The replied message is not in the chat room", http.StatusBadRequest)
+	ErrChatMessageEmpty             = New(1800005, "Message or attachments are required", http.StatusBadRequest)
+	ErrChatRoomUserForbidden        = New(1800006, "Not a member of the chat room", http.StatusForbidden)
 )
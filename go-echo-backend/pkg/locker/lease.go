@@ -0,0 +1,130 @@
+package locker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
+)
+
+// Lease a held lock. The watchdog extends it while the holder runs, Context is cancelled
+// as soon as the lease is lost so the holder can stop before another one takes over.
+type Lease struct {
+	locker     *Locker
+	key        string
+	value      string
+	token      int64
+	ttl        time.Duration
+	acquiredAt time.Time
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	released int32
+}
+
+func (l *Lease) Key() string {
+	return l.key
+}
+
+// Token fencing token, each holder of the key gets a greater one
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Context done when the lease is lost or released
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// Extend resets the TTL of the lease, ErrLockLost when another holder took it
+func (l *Lease) Extend(ctx context.Context) error {
+	extended, err := extendScript.Run(ctx, l.locker.redis, []string{l.locker.getLockKey(l.key)}, l.value, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return eris.Wrapf(err, "extend lock key=%s", l.key)
+	}
+	if extended == 0 {
+		return eris.Wrapf(ErrLockLost, "key=%s token=%d", l.key, l.token)
+	}
+
+	atomic.AddInt64(&l.locker.stats.extended, 1)
+	return nil
+}
+
+// Release frees the lock, ErrLockLost when it had already expired
+func (l *Lease) Release() error {
+	if !atomic.CompareAndSwapInt32(&l.released, 0, 1) {
+		return nil
+	}
+
+	var lost = l.ctx.Err() != nil
+	l.cancel()
+	<-l.done
+	atomic.AddInt64(&l.locker.stats.held, -1)
+	atomic.AddInt64(&l.locker.stats.released, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	deleted, err := releaseScript.Run(ctx, l.locker.redis, []string{l.locker.getLockKey(l.key)}, l.value).Int64()
+	if err != nil {
+		var e = eris.Wrapf(err, "release lock key=%s", l.key)
+		l.locker.logger.ErrorAny(e)
+		return e
+	}
+	if deleted == 0 {
+		var e = eris.Wrapf(ErrLockLost, "release lock key=%s token=%d lost=%t", l.key, l.token, lost)
+		l.locker.logger.ErrorAny(e)
+		return e
+	}
+
+	l.locker.logger.WithSkipCaller(1).Debugf("Release lock key=%s token=%d after %0.2fs", l.key, l.token, time.Since(l.acquiredAt).Seconds())
+	return nil
+}
+
+// Fence scope of the writes guarded by the lease: rows last written by a later holder are skipped,
+// writers set the column to Token() and treat RowsAffected == 0 as a lost lease.
+func (l *Lease) Fence(column string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(fmt.Sprintf("COALESCE(%s, 0) <= ?", column), l.token)
+	}
+}
+
+func (l *Lease) watchdog() {
+	defer close(l.done)
+
+	var interval = l.ttl / 3
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var extendedAt = time.Now()
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(l.ctx, interval)
+			var err = l.Extend(ctx)
+			cancel()
+			if err == nil {
+				extendedAt = time.Now()
+				continue
+			}
+			if l.ctx.Err() != nil {
+				return
+			}
+
+			// Redis errors are retried until the lease would have expired anyway
+			if eris.Is(err, ErrLockLost) || time.Since(extendedAt) >= l.ttl {
+				atomic.AddInt64(&l.locker.stats.lost, 1)
+				l.locker.logger.Errorf("Lease lost key=%s token=%d held=%s err=%+v", l.key, l.token, time.Since(l.acquiredAt), err)
+				l.cancel()
+				return
+			}
+			l.locker.logger.Debugf("Extend lease key=%s token=%d err=%+v", l.key, l.token, err)
+		}
+	}
+}
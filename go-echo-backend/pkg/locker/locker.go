@@ -1,14 +1,15 @@
 package locker
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
-	"github.com/go-redsync/redsync/v4"
-	redsyncgoredis "github.com/go-redsync/redsync/v4/redis/goredis/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
 	"github.com/rs/xid"
@@ -16,13 +17,57 @@ import (
 
 var instance *Locker
 
+var (
+	ErrLockNotAcquired = eris.New("lock not acquired")
+	ErrLockLost        = eris.New("lock lost")
+)
+
+const (
+	defaultLeaseTTL = time.Second * 30
+	fenceTTL        = time.Hour * 24 // Idle token keys expire, the next token is seeded from the clock
+	minRetryDelay   = time.Millisecond * 50
+	maxRetryDelay   = time.Second
+)
+
+// acquireScript sets the lock when it is free and returns the next fencing token of the key, 0 when it is held.
+// The token starts from the redis clock in µs, so tokens keep increasing across holders even after the idle token key expired.
+var acquireScript = redis.NewScript(`
+if not redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 0
+end
+local now = redis.call('TIME')
+local floor = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local token = redis.call('INCR', KEYS[2])
+if token < floor then
+	token = floor
+	redis.call('SET', KEYS[2], token)
+end
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return token
+`)
+
+var extendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
 type LockReleaseFunc = func() error
 
 type Locker struct {
 	workspace     string
-	redisSync     *redsync.Redsync
+	redis         *redis.Client
 	logger        *logger.Logger
 	defaultExpiry time.Duration
+	stats         stats
 }
 
 func New(config *config.Configuration) *Locker {
@@ -31,57 +76,144 @@ func New(config *config.Configuration) *Locker {
 		Password: config.RedisPassword,
 		DB:       config.RedisDBMode,
 	})
-	var pool = redsyncgoredis.NewPool(redisClient)
-	var redisSync = redsync.New(pool)
 
-	instance = &Locker{
+	instance = NewLocker(redisClient)
+	return instance
+}
+
+// NewLocker locker over the redis connection
+func NewLocker(redisClient *redis.Client) *Locker {
+	return &Locker{
 		workspace:     "inflow_locker",
-		redisSync:     redisSync,
+		redis:         redisClient,
 		logger:        logger.New("utils/locker"),
-		defaultExpiry: time.Minute,
+		defaultExpiry: defaultLeaseTTL,
 	}
-	return instance
 }
 
+type acquireOptions struct {
+	ttl      time.Duration
+	watchdog bool
+}
+
+type AcquireOption func(*acquireOptions)
+
+// WithTTL expiry of the lease, the watchdog extends it every third of the TTL
+func WithTTL(ttl time.Duration) AcquireOption {
+	return func(o *acquireOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithoutWatchdog the lease expires after its TTL even if the holder is still running
+func WithoutWatchdog() AcquireOption {
+	return func(o *acquireOptions) {
+		o.watchdog = false
+	}
+}
+
+// AcquireLock waits up to the timeout for the lock, the returned func releases it
 func (locker *Locker) AcquireLock(key string, timeoutDuration time.Duration) (LockReleaseFunc, error) {
-	if key == "" {
-		key = xid.New().String()
+	ctx, cancel := context.WithTimeout(context.Background(), helper.GetTimeout(timeoutDuration, time.Minute))
+	defer cancel()
+
+	lease, err := locker.Acquire(ctx, key)
+	if err != nil {
+		var fn = func() error {
+			return nil
+		}
+		return fn, err
 	}
 
-	var timeout = time.NewTimer(helper.GetTimeout(timeoutDuration, time.Minute))
+	return lease.Release, nil
+}
+
+// Acquire waits for the lock until the context is done
+func (locker *Locker) Acquire(ctx context.Context, key string, opts ...AcquireOption) (*Lease, error) {
 	var start = time.Now()
+	var delay = minRetryDelay
+	var attempts int
+
 	for {
-		select {
-		case <-timeout.C:
-			var fn = func() error {
-				return nil
-			}
-			return fn, eris.Errorf("timeout reached after %v", time.Since(start))
-		default:
-			var lockKey = fmt.Sprintf("%s_%s", locker.workspace, key)
-			var mutex = locker.redisSync.NewMutex(lockKey, redsync.WithExpiry(locker.defaultExpiry))
-			var err = mutex.Lock()
-			if err != nil {
-				locker.logger.Errorf("Acquire lock for key %s error: %+v", lockKey, err)
-				time.Sleep(time.Millisecond * 200)
-			} else {
-				locker.logger.WithSkipCaller(1).Infof("Acquire lock for key = %s expiry = %0.2fs success", lockKey, locker.defaultExpiry.Seconds())
-				var fn = func() error {
-					if ok, err := mutex.Unlock(); !ok || err != nil {
-						var e = eris.Errorf("Release lock for key %s error: %+v", mutex.Name(), err)
-						locker.logger.ErrorAny(e)
-						return eris.Wrap(e, "")
-					}
-					locker.logger.WithSkipCaller(1).Infof("Release lock for key = %s success after %0.2fs", lockKey, time.Since(start).Seconds())
-					return nil
-				}
-				return fn, nil
-			}
+		lease, err := locker.TryAcquire(ctx, key, opts...)
+		if err == nil {
+			atomic.AddInt64(&locker.stats.waitNanos, int64(time.Since(start)))
+			return lease, nil
+		}
+		if !eris.Is(err, ErrLockNotAcquired) {
+			locker.logger.Debugf("Acquire lock key=%s attempt=%d err=%+v", key, attempts, err)
+		}
+		attempts++
 
+		var timer = time.NewTimer(delay + time.Duration(rand.Int63n(int64(delay))))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			atomic.AddInt64(&locker.stats.timeouts, 1)
+			atomic.AddInt64(&locker.stats.waitNanos, int64(time.Since(start)))
+			locker.logger.Warnf("Acquire lock key=%s gave up after %s attempts=%d", key, time.Since(start), attempts)
+			return nil, eris.Wrapf(ErrLockNotAcquired, "key=%s after %v: %v", key, time.Since(start), ctx.Err())
+		case <-timer.C:
 		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// TryAcquire takes the lock only if it is free, ErrLockNotAcquired otherwise
+func (locker *Locker) TryAcquire(ctx context.Context, key string, opts ...AcquireOption) (*Lease, error) {
+	if key == "" {
+		key = xid.New().String()
+	}
+
+	var options = acquireOptions{ttl: locker.defaultExpiry, watchdog: true}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	var lease = &Lease{
+		locker: locker,
+		key:    key,
+		value:  xid.New().String(),
+		ttl:    options.ttl,
+	}
+
+	token, err := acquireScript.Run(ctx, locker.redis, []string{locker.getLockKey(key), locker.getTokenKey(key)}, lease.value, options.ttl.Milliseconds(), fenceTTL.Milliseconds()).Int64()
+	if err != nil {
+		atomic.AddInt64(&locker.stats.errors, 1)
+		return nil, eris.Wrapf(err, "acquire lock key=%s", key)
+	}
+	if token == 0 {
+		atomic.AddInt64(&locker.stats.contended, 1)
+		return nil, eris.Wrapf(ErrLockNotAcquired, "key=%s", key)
+	}
+
+	atomic.AddInt64(&locker.stats.acquired, 1)
+	atomic.AddInt64(&locker.stats.held, 1)
+
+	lease.token = token
+	lease.acquiredAt = time.Now()
+	lease.ctx, lease.cancel = context.WithCancel(context.Background())
+	lease.done = make(chan struct{})
+	if options.watchdog {
+		go lease.watchdog()
+	} else {
+		close(lease.done)
 	}
 
+	locker.logger.WithSkipCaller(1).Debugf("Acquire lock key=%s token=%d ttl=%s", key, token, options.ttl)
+
+	return lease, nil
+}
+
+func (locker *Locker) getLockKey(key string) string {
+	return fmt.Sprintf("%s_%s", locker.workspace, key)
 }
+
+func (locker *Locker) getTokenKey(key string) string {
+	return fmt.Sprintf("%s_%s_fence", locker.workspace, key)
+}
+
 func GetInstance() *Locker {
 	return instance
 }
@@ -0,0 +1,45 @@
+package locker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type stats struct {
+	acquired  int64
+	contended int64
+	timeouts  int64
+	errors    int64
+	extended  int64
+	released  int64
+	lost      int64
+	held      int64
+	waitNanos int64
+}
+
+// Stats contention counters of the locker since the process started
+type Stats struct {
+	Acquired  int64 `json:"acquired"`
+	Contended int64 `json:"contended"`
+	Timeouts  int64 `json:"timeouts"`
+	Errors    int64 `json:"errors"`
+	Extended  int64 `json:"extended"`
+	Released  int64 `json:"released"`
+	Lost      int64 `json:"lost"`
+	Held      int64 `json:"held"`
+	WaitTime  int64 `json:"wait_time_ms"`
+}
+
+func (locker *Locker) Stats() Stats {
+	return Stats{
+		Acquired:  atomic.LoadInt64(&locker.stats.acquired),
+		Contended: atomic.LoadInt64(&locker.stats.contended),
+		Timeouts:  atomic.LoadInt64(&locker.stats.timeouts),
+		Errors:    atomic.LoadInt64(&locker.stats.errors),
+		Extended:  atomic.LoadInt64(&locker.stats.extended),
+		Released:  atomic.LoadInt64(&locker.stats.released),
+		Lost:      atomic.LoadInt64(&locker.stats.lost),
+		Held:      atomic.LoadInt64(&locker.stats.held),
+		WaitTime:  time.Duration(atomic.LoadInt64(&locker.stats.waitNanos)).Milliseconds(),
+	}
+}
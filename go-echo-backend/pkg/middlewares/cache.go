@@ -0,0 +1,202 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/caching"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderXCache = "X-Cache"
+
+	cacheHit  = "HIT"
+	cacheMiss = "MISS"
+)
+
+// ResponseCacheEntry a response replayed to the requests with the same route, query and language
+type ResponseCacheEntry struct {
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag"`
+	LastModified int64       `json:"last_modified"`
+}
+
+// uncachedResponse a response which is not 200, the requests waiting on the same load still get it
+type uncachedResponse struct {
+	entry *ResponseCacheEntry
+}
+
+func (e *uncachedResponse) Error() string {
+	return fmt.Sprintf("response status=%d is not cached", e.entry.Status)
+}
+
+// CacheResponse caches the GET responses of public routes per route, query and language.
+// Clients revalidate with If-None-Match or If-Modified-Since and receive 304 while the entry is unchanged,
+// PurgeCache on the admin routes drops the entries with the same tags.
+func (m *Middleware) CacheResponse(ttl time.Duration, tags ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			var status = cacheHit
+			var key = GetResponseCacheKey(c)
+			entry, err := caching.GetOrLoad(c.Request().Context(), m.App.Cache, key, func() (*ResponseCacheEntry, error) {
+				status = cacheMiss
+				return recordResponse(c, next)
+			}, caching.WithTTL(ttl), caching.WithTags(tags...))
+			if err != nil {
+				if uncached, ok := err.(*uncachedResponse); ok {
+					return writeResponseCacheEntry(c, uncached.entry, status)
+				}
+				return err
+			}
+
+			return writeResponseCacheEntry(c, entry, status)
+		}
+	}
+}
+
+// PurgeCache drops the cached responses with the tags once the mutation succeeded
+func (m *Middleware) PurgeCache(tags ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err = next(c)
+			if err != nil || c.Request().Method == http.MethodGet || c.Response().Status >= http.StatusBadRequest {
+				return err
+			}
+
+			if err := m.App.Cache.InvalidateTags(context.Background(), tags...); err != nil {
+				m.App.DB.CustomLogger.Errorf("Purge response cache tags=%v err=%+v", tags, err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// GetResponseCacheKey route, path, sorted query and language of the request
+func GetResponseCacheKey(c echo.Context) string {
+	var req = c.Request()
+	var language = enums.LanguageCode(req.Header.Get(models.LanguageKey)).DefaultIfInvalid()
+
+	// Encode sorts the query by key
+	var hash = sha1.Sum([]byte(strings.Join([]string{c.Path(), req.URL.Path, req.URL.Query().Encode(), string(language)}, "\n")))
+
+	return fmt.Sprintf("http_response_%s", hex.EncodeToString(hash[:]))
+}
+
+// NewResponseETag weak etag of the body, gzip changes the bytes on the wire but not the representation
+func NewResponseETag(body []byte) string {
+	var hash = sha1.Sum(body)
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(hash[:]))
+}
+
+// MatchETag weak comparison of If-None-Match against the etag
+func MatchETag(ifNoneMatch string, etag string) bool {
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// responseRecorder buffers the response of the handler so it can be cached before being written
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) Flush() {}
+
+func recordResponse(c echo.Context, next echo.HandlerFunc) (*ResponseCacheEntry, error) {
+	var res = c.Response()
+	var writer = res.Writer
+	var recorder = &responseRecorder{header: http.Header{}, status: http.StatusOK}
+
+	res.Writer = recorder
+	var err = next(c)
+	res.Writer = writer
+
+	// The response is written again from the entry
+	var committed = res.Committed
+	res.Committed = false
+	res.Size = 0
+
+	if err != nil && !committed {
+		return nil, err
+	}
+
+	var entry = ResponseCacheEntry{
+		Status:       recorder.status,
+		Header:       recorder.header.Clone(),
+		Body:         recorder.body.Bytes(),
+		ETag:         NewResponseETag(recorder.body.Bytes()),
+		LastModified: time.Now().Unix(),
+	}
+	entry.Header.Del(echo.HeaderSetCookie)
+	entry.Header.Del(echo.HeaderContentLength)
+
+	if err != nil || entry.Status != http.StatusOK {
+		return nil, &uncachedResponse{entry: &entry}
+	}
+
+	return &entry, nil
+}
+
+func writeResponseCacheEntry(c echo.Context, entry *ResponseCacheEntry, status string) error {
+	var header = c.Response().Header()
+	for key, values := range entry.Header {
+		header[key] = values
+	}
+
+	if entry.Status != http.StatusOK {
+		return c.Blob(entry.Status, header.Get(echo.HeaderContentType), entry.Body)
+	}
+
+	var lastModified = time.Unix(entry.LastModified, 0).UTC()
+	header.Set(HeaderXCache, status)
+	header.Set("ETag", entry.ETag)
+	header.Set(echo.HeaderLastModified, lastModified.Format(http.TimeFormat))
+	// Clients always revalidate, so purged entries are not served from their cache
+	header.Set(echo.HeaderCacheControl, "no-cache")
+
+	var req = c.Request()
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if MatchETag(ifNoneMatch, entry.ETag) {
+			return c.NoContent(http.StatusNotModified)
+		}
+	} else if since, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince)); err == nil && !lastModified.After(since) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, header.Get(echo.HeaderContentType), entry.Body)
+}
@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"context"
+
 	"github.com/labstack/echo/v4"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
@@ -11,6 +13,11 @@ import (
 func (m *Middleware) RegisterCustomContext() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			// The tasks dispatched with the request context are traced by the request ID
+			if reqID := c.Response().Header().Get(echo.HeaderXRequestID); reqID != "" {
+				c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), logger.RequestIDKey, reqID)))
+			}
+
 			var cc = &models.CustomContext{
 				Context:      c,
 				App:          m.App,
@@ -0,0 +1,160 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	idempotencyKeyMaxLength = 255
+)
+
+// Idempotency replays the stored response when a POST, PUT or PATCH is retried with the same Idempotency-Key.
+// Keys are scoped per user, so it must run after IsAuthorized. Reusing a key with another body is rejected,
+// failed requests release the key so the client can retry.
+func (m *Middleware) Idempotency() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var req = c.Request()
+			var key = req.Header.Get(HeaderIdempotencyKey)
+			if key == "" || !IsIdempotentMethod(req.Method) {
+				return next(c)
+			}
+
+			if err := ValidateIdempotencyKey(key); err != nil {
+				return err
+			}
+
+			var cc = c.(*models.CustomContext)
+			claims, err := cc.GetJwtClaimsInfo()
+			if err != nil {
+				return err
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return eris.Wrap(err, "read request body")
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			var idempotencyKeyRepo = repo.NewIdempotencyKeyRepo(m.App.DB)
+			claimed, completed, err := idempotencyKeyRepo.BeginIdempotentRequest(repo.BeginIdempotentRequestParams{
+				UserID:      claims.GetUserID(),
+				Key:         key,
+				Method:      req.Method,
+				Path:        req.URL.Path,
+				Fingerprint: GetRequestFingerprint(req.Method, req.URL.Path, body),
+			})
+			if err != nil {
+				return err
+			}
+
+			if completed != nil {
+				return writeIdempotentResponse(c, completed)
+			}
+
+			var res = c.Response()
+			var writer = &teeResponseWriter{ResponseWriter: res.Writer}
+			res.Writer = writer
+			err = next(c)
+			res.Writer = writer.ResponseWriter
+
+			// Errors are written by the error handler after this returns, retries run the request again
+			if err != nil || !res.Committed || res.Status >= http.StatusInternalServerError {
+				if e := idempotencyKeyRepo.ReleaseIdempotentRequest(claimed.ID); e != nil {
+					m.App.DB.CustomLogger.Errorf("Release idempotency key=%s err=%+v", key, e)
+				}
+				return err
+			}
+
+			header, _ := json.Marshal(map[string]string{
+				echo.HeaderContentType: res.Header().Get(echo.HeaderContentType),
+			})
+			if e := idempotencyKeyRepo.CompleteIdempotentRequest(claimed.ID, res.Status, header, writer.body.Bytes()); e != nil {
+				m.App.DB.CustomLogger.Errorf("Complete idempotency key=%s err=%+v", key, e)
+			}
+
+			return nil
+		}
+	}
+}
+
+// IsIdempotentMethod methods which honour the Idempotency-Key header
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+
+	return false
+}
+
+// ValidateIdempotencyKey printable ASCII of at most 255 characters
+func ValidateIdempotencyKey(key string) error {
+	if key == "" || len(key) > idempotencyKeyMaxLength {
+		return errs.ErrIdempotencyKeyInvalid
+	}
+
+	if strings.IndexFunc(key, func(r rune) bool { return r < 0x21 || r > 0x7e }) >= 0 {
+		return errs.ErrIdempotencyKeyInvalid
+	}
+
+	return nil
+}
+
+// GetRequestFingerprint sha256 of the method, path and body, a retry must send the same request
+func GetRequestFingerprint(method string, path string, body []byte) string {
+	var hash = sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte("\n"))
+	hash.Write([]byte(path))
+	hash.Write([]byte("\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeIdempotentResponse(c echo.Context, record *models.IdempotencyKey) error {
+	var header map[string]string
+	if len(record.ResponseHeader) > 0 {
+		_ = json.Unmarshal(record.ResponseHeader, &header)
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	if len(record.ResponseBody) == 0 {
+		return c.NoContent(record.ResponseStatus)
+	}
+
+	return c.Blob(record.ResponseStatus, header[echo.HeaderContentType], record.ResponseBody)
+}
+
+// teeResponseWriter keeps a copy of the body written to the client
+type teeResponseWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *teeResponseWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -23,6 +23,8 @@ var schemas = []interface{}{
 	&models.ChatMessage{},
 	&models.ChatRoom{},
 	&models.ChatRoomUser{},
+	&models.ChatMessageReaction{},
+	&models.ChatMessageEdit{},
 	&models.Cart{},
 	&models.CartItem{},
 
@@ -67,6 +69,12 @@ var schemas = []interface{}{
 	&models.BrandTeam{},
 	&models.AdsVideo{},
 	&models.Invoice{},
+	&models.InvoiceSeries{},
+	&models.FxRate{},
+	&models.JournalEntry{},
+	&models.JournalLine{},
+	&models.BankStatement{},
+	&models.BankStatementLine{},
 	&models.Fabric{},
 	&models.FabricCollection{},
 	&models.FabricInCollection{},
@@ -79,6 +87,7 @@ var schemas = []interface{}{
 	&models.PurchaseOrderItem{},
 	&models.ZaloConfig{},
 	&models.TNA{},
+	&models.TNATemplate{},
 	&models.ReleaseNote{},
 	&models.Document{},
 	&models.DocumentCategory{},
@@ -94,6 +103,23 @@ var schemas = []interface{}{
 	&models.BulkPurchaseOrderSellerQuotation{},
 	&models.Trending{},
 	&models.ProductFileUploadInfo{},
+	&models.OutboxMessage{},
+	&models.IdempotencyKey{},
+	&models.DeadLetterTask{},
+	&models.PaymentMilestone{},
+}
+
+// cursorIndexes keyset indexes of the lists paged by cursor, the filter column first then the sort keys
+var cursorIndexes = []string{
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_chat_messages_cursor ON chat_messages (receiver_id, created_at DESC, id DESC)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_user_notifications_cursor ON user_notifications (user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL AND seen_at IS NULL",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_purchase_order_trackings_cursor ON purchase_order_trackings (purchase_order_id, created_at DESC, id DESC)",
+	"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bulk_purchase_order_trackings_cursor ON bulk_purchase_order_trackings (purchase_order_id, created_at DESC, id DESC)",
+}
+
+// outboxIndexes the dedupe key is unique among pending rows only, the task can be written again once relayed
+var outboxIndexes = []string{
+	"CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_outbox_messages_pending_dedupe_key ON outbox_messages (dedupe_key) WHERE status = 'pending'",
 }
 
 type Migrator struct {
@@ -112,4 +138,16 @@ func (m *Migrator) AutoMigrate() {
 			m.db.CustomLogger.Errorf("Auto-migrate %T error: %+v", schema, err)
 		}
 	}
+
+	for _, statement := range cursorIndexes {
+		if err := m.db.Exec(statement).Error; err != nil {
+			m.db.CustomLogger.Errorf("Setup cursor index error: %+v", err)
+		}
+	}
+
+	for _, statement := range outboxIndexes {
+		if err := m.db.Exec(statement).Error; err != nil {
+			m.db.CustomLogger.Errorf("Setup outbox index error: %+v", err)
+		}
+	}
 }
@@ -0,0 +1,413 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	bankMatchScoreExact     = 100
+	bankMatchScoreReference = 60
+	bankMatchScoreAmount    = 40
+)
+
+var (
+	mt940TagRegex        = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	mt940BalanceRegex    = regexp.MustCompile(`^[CD](\d{6})([A-Z]{3})([\d,]+)`)
+	mt940StatementRegex  = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)[A-Z]?([\d,]+)[A-Z][A-Z0-9]{3}([^/]*)(?://(.*))?$`)
+	nonAlphanumericRegex = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+// DetectBankStatementFormat sniffs the file content, anything that is neither CAMT.053 nor MT940 is read as CSV
+func DetectBankStatementFormat(content []byte) enums.BankStatementFormat {
+	var head = string(content[:min(len(content), 2048)])
+	if strings.Contains(head, "BkToCstmrStmt") {
+		return enums.BankStatementFormatCAMT053
+	}
+
+	if strings.Contains(head, ":20:") && strings.Contains(string(content), ":61:") {
+		return enums.BankStatementFormatMT940
+	}
+
+	return enums.BankStatementFormatCSV
+}
+
+// ParseBankStatement parses content in format into statement lines, the account number is returned when the format carries it
+func ParseBankStatement(format enums.BankStatementFormat, content []byte) (lines BankStatementLines, accountNumber string, err error) {
+	switch format {
+	case enums.BankStatementFormatCSV:
+		lines, err = ParseBankStatementCSV(content)
+
+	case enums.BankStatementFormatMT940:
+		lines, accountNumber, err = ParseBankStatementMT940(content)
+
+	case enums.BankStatementFormatCAMT053:
+		lines, accountNumber, err = ParseBankStatementCAMT053(content)
+
+	default:
+		err = fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Identical transfers booked the same day are told apart by their position among each other,
+	// an overlapping statement lists them in the same order so they still dedupe
+	var occurrences = map[string]int{}
+	for _, line := range lines {
+		var fingerprint = line.GetFingerprint(accountNumber, 0)
+		line.Fingerprint = line.GetFingerprint(accountNumber, occurrences[fingerprint])
+		occurrences[fingerprint]++
+		line.Status = enums.BankStatementLineStatusUnmatched
+		if !line.Amount.GreaterThan(0) {
+			line.Status = enums.BankStatementLineStatusIgnored
+			line.MatchReason = "Outgoing payment"
+		}
+	}
+
+	return lines, accountNumber, nil
+}
+
+// ParseBankStatementCSV reads a CSV with a header row, date and amount columns are required,
+// currency, reference, description, counterparty and bank_ref are optional
+func ParseBankStatementCSV(content []byte) (BankStatementLines, error) {
+	var csvReader = csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var columns = make(map[string]int, len(rows[0]))
+	for index, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	for _, required := range []string{"date", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var column = func(row []string, name string) string {
+		var index, ok = columns[name]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	var result BankStatementLines
+	for index, row := range rows[1:] {
+		if len(strings.Join(row, "")) == 0 {
+			continue
+		}
+
+		bookingDate, err := parseBankStatementDate(column(row, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid date %q", index+2, column(row, "date"))
+		}
+
+		amount, err := decimal.NewFromString(strings.ReplaceAll(column(row, "amount"), ",", ""))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount %q", index+2, column(row, "amount"))
+		}
+
+		var currency = enums.Currency(strings.ToUpper(column(row, "currency")))
+		if currency == "" {
+			currency = enums.USD
+		}
+
+		result = append(result, &BankStatementLine{
+			BookingDate:  bookingDate,
+			Amount:       price.NewFromDecimal(amount),
+			Currency:     currency,
+			Reference:    column(row, "reference"),
+			Description:  column(row, "description"),
+			Counterparty: column(row, "counterparty"),
+			BankRef:      column(row, "bank_ref"),
+		})
+	}
+
+	return result, nil
+}
+
+// ParseBankStatementMT940 reads SWIFT MT940 :61: statement lines, each followed by its optional :86: information
+func ParseBankStatementMT940(content []byte) (BankStatementLines, string, error) {
+	type field struct {
+		tag   string
+		value string
+	}
+
+	var fields []*field
+	for _, raw := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		var line = strings.TrimRight(raw, " ")
+		if matches := mt940TagRegex.FindStringSubmatch(line); matches != nil {
+			fields = append(fields, &field{tag: matches[1], value: matches[2]})
+			continue
+		}
+		if len(fields) > 0 && line != "" && line != "-" && !strings.HasPrefix(line, "{") {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+
+	var result BankStatementLines
+	var accountNumber string
+	var currency enums.Currency
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			accountNumber = strings.TrimSpace(f.value)
+
+		case "60F", "60M":
+			if matches := mt940BalanceRegex.FindStringSubmatch(f.value); matches != nil {
+				currency = enums.Currency(matches[2])
+			}
+
+		case "61":
+			var parts = strings.SplitN(f.value, "\n", 2)
+			var matches = mt940StatementRegex.FindStringSubmatch(strings.TrimSpace(parts[0]))
+			if matches == nil {
+				return nil, "", fmt.Errorf("invalid :61: line %q", parts[0])
+			}
+
+			bookingDate, err := time.Parse("060102", matches[1])
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid :61: date %q", matches[1])
+			}
+
+			amount, err := decimal.NewFromString(strings.ReplaceAll(matches[4], ",", "."))
+			if err != nil {
+				return nil, "", fmt.Errorf("invalid :61: amount %q", matches[4])
+			}
+			// RC reverses a credit, RD reverses a debit
+			if matches[3] == "D" || matches[3] == "RC" {
+				amount = amount.Neg()
+			}
+
+			var item = &BankStatementLine{
+				BookingDate: bookingDate.UTC().Unix(),
+				Amount:      price.NewFromDecimal(amount),
+				Currency:    currency,
+				BankRef:     strings.TrimSpace(matches[6]),
+			}
+			if reference := strings.TrimSpace(matches[5]); reference != "NONREF" {
+				item.Reference = reference
+			}
+			if len(parts) > 1 {
+				item.Description = strings.TrimSpace(parts[1])
+			}
+			result = append(result, item)
+
+		case "86":
+			if len(result) > 0 {
+				var item = result[len(result)-1]
+				item.Description = strings.TrimSpace(strings.Join([]string{item.Description, strings.ReplaceAll(f.value, "\n", "")}, " "))
+			}
+		}
+	}
+
+	return result, accountNumber, nil
+}
+
+type camt053Document struct {
+	Statements []struct {
+		IBAN    string `xml:"Acct>Id>IBAN"`
+		OtherID string `xml:"Acct>Id>Othr>Id"`
+		Entries []struct {
+			Amount struct {
+				Value    string `xml:",chardata"`
+				Currency string `xml:"Ccy,attr"`
+			} `xml:"Amt"`
+			CreditDebit   string `xml:"CdtDbtInd"`
+			BookingDate   string `xml:"BookgDt>Dt"`
+			BookingDtTm   string `xml:"BookgDt>DtTm"`
+			ServicerRef   string `xml:"AcctSvcrRef"`
+			AdditionalInf string `xml:"AddtlNtryInf"`
+			Details       []struct {
+				EndToEndID   string   `xml:"Refs>EndToEndId"`
+				Unstructured []string `xml:"RmtInf>Ustrd"`
+				CreditorRef  string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+				DebtorName   string   `xml:"RltdPties>Dbtr>Nm"`
+				DebtorPtyNm  string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+			} `xml:"NtryDtls>TxDtls"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// ParseBankStatementCAMT053 reads ISO 20022 camt.053 Ntry entries, remittance info of all transaction details is kept as the reference
+func ParseBankStatementCAMT053(content []byte) (BankStatementLines, string, error) {
+	var document camt053Document
+	if err := xml.Unmarshal(content, &document); err != nil {
+		return nil, "", err
+	}
+
+	var result BankStatementLines
+	var accountNumber string
+	for _, statement := range document.Statements {
+		if accountNumber == "" {
+			accountNumber = lo.Ternary(statement.IBAN != "", statement.IBAN, statement.OtherID)
+		}
+
+		for index, entry := range statement.Entries {
+			amount, err := decimal.NewFromString(strings.TrimSpace(entry.Amount.Value))
+			if err != nil {
+				return nil, "", fmt.Errorf("entry %d: invalid amount %q", index+1, entry.Amount.Value)
+			}
+			if entry.CreditDebit == "DBIT" {
+				amount = amount.Neg()
+			}
+
+			bookingDate, err := parseBankStatementDate(lo.Ternary(entry.BookingDate != "", entry.BookingDate, entry.BookingDtTm))
+			if err != nil {
+				return nil, "", fmt.Errorf("entry %d: invalid booking date", index+1)
+			}
+
+			var references []string
+			var counterparty string
+			for _, detail := range entry.Details {
+				references = append(references, detail.CreditorRef)
+				references = append(references, detail.Unstructured...)
+				if detail.EndToEndID != "NOTPROVIDED" {
+					references = append(references, detail.EndToEndID)
+				}
+				if counterparty == "" {
+					counterparty = lo.Ternary(detail.DebtorName != "", detail.DebtorName, detail.DebtorPtyNm)
+				}
+			}
+
+			result = append(result, &BankStatementLine{
+				BookingDate:  bookingDate,
+				Amount:       price.NewFromDecimal(amount),
+				Currency:     enums.Currency(entry.Amount.Currency),
+				Reference:    helper.JoinNonEmptyStrings(" ", references...),
+				Description:  strings.TrimSpace(entry.AdditionalInf),
+				Counterparty: counterparty,
+				BankRef:      strings.TrimSpace(entry.ServicerRef),
+			})
+		}
+	}
+
+	return result, accountNumber, nil
+}
+
+// GetFingerprint identifies the booked transaction regardless of the statement file it came from,
+// occurrence is the position of the line among the identical lines of the account
+func (line *BankStatementLine) GetFingerprint(accountNumber string, occurrence int) string {
+	var hash = sha1.Sum([]byte(strings.Join([]string{
+		accountNumber,
+		fmt.Sprint(occurrence),
+		fmt.Sprint(line.BookingDate),
+		line.Amount.Decimal().StringFixed(4),
+		string(line.Currency),
+		line.Reference,
+		line.Description,
+		line.BankRef,
+	}, "|")))
+	return hex.EncodeToString(hash[:])
+}
+
+// MatchBankStatementLine finds the pending transfer a credit line pays.
+// Reference plus amount and currency is an exact match, a reference or a unique amount alone is only suggested for review.
+func MatchBankStatementLine(line *BankStatementLine, candidates PaymentTransactions) BankStatementMatch {
+	var result = BankStatementMatch{Status: enums.BankStatementLineStatusUnmatched}
+	if !line.Amount.GreaterThan(0) {
+		return result
+	}
+
+	var text = normalizeBankReference(line.Reference + " " + line.Description)
+	var referenceMatches, amountMatches PaymentTransactions
+	for _, candidate := range candidates {
+		var sameAmount = line.PaysAmountOf(candidate)
+		var sameReference = lo.SomeBy([]string{candidate.ReferenceID, candidate.TransactionRefID}, func(reference string) bool {
+			var normalized = normalizeBankReference(reference)
+			return len(normalized) >= 4 && strings.Contains(text, normalized)
+		})
+
+		if sameReference && sameAmount {
+			result.PaymentTransaction = candidate
+			result.Status = enums.BankStatementLineStatusMatched
+			result.Score = bankMatchScoreExact
+			result.Reason = "Reference, amount and currency match"
+			return result
+		}
+		if sameReference {
+			referenceMatches = append(referenceMatches, candidate)
+		}
+		if sameAmount {
+			amountMatches = append(amountMatches, candidate)
+		}
+	}
+
+	if len(referenceMatches) > 0 {
+		result.PaymentTransaction = referenceMatches[0]
+		result.Status = enums.BankStatementLineStatusSuggested
+		result.Score = bankMatchScoreReference
+		result.Reason = fmt.Sprintf("Reference matches, expected %s %s",
+			price.NewFromPtr(referenceMatches[0].PaidAmount).Decimal().StringFixed(2), referenceMatches[0].Currency)
+		return result
+	}
+
+	if len(amountMatches) == 1 {
+		result.PaymentTransaction = amountMatches[0]
+		result.Status = enums.BankStatementLineStatusSuggested
+		result.Score = bankMatchScoreAmount
+		result.Reason = "Amount and currency match, reference not found"
+		return result
+	}
+
+	if len(amountMatches) > 1 {
+		result.Reason = fmt.Sprintf("%d pending transfers have the same amount", len(amountMatches))
+	}
+
+	return result
+}
+
+// PaysAmountOf the line credits the amount of the transfer in its currency
+func (line *BankStatementLine) PaysAmountOf(transaction *PaymentTransaction) bool {
+	return transaction.Currency == line.Currency && line.Amount.Decimal().Equal(price.NewFromPtr(transaction.PaidAmount).Decimal())
+}
+
+// ApplyMatch stores the match outcome on the line
+func (line *BankStatementLine) ApplyMatch(match BankStatementMatch) {
+	line.Status = match.Status
+	line.MatchScore = match.Score
+	line.MatchReason = match.Reason
+	line.PaymentTransaction = match.PaymentTransaction
+	line.PaymentTransactionID = ""
+	if match.PaymentTransaction != nil {
+		line.PaymentTransactionID = match.PaymentTransaction.ID
+	}
+}
+
+func normalizeBankReference(value string) string {
+	return nonAlphanumericRegex.ReplaceAllString(strings.ToUpper(value), "")
+}
+
+func parseBankStatementDate(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", time.RFC3339, "02/01/2006", "20060102"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.UTC().Unix(), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid date %q", value)
+}
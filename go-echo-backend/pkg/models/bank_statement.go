@@ -0,0 +1,56 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+)
+
+// BankStatement an uploaded bank statement file, its lines are auto-matched against pending bank transfers
+type BankStatement struct {
+	Model
+
+	FileName         string                    `json:"file_name"`
+	Format           enums.BankStatementFormat `gorm:"size:20" json:"format"`
+	AccountNumber    string                    `gorm:"size:100" json:"account_number,omitempty"`
+	UploadedByUserID string                    `gorm:"size:100" json:"uploaded_by_user_id,omitempty"`
+	LineCount        int                       `json:"line_count"`
+
+	Lines BankStatementLines `gorm:"-" json:"lines,omitempty"`
+}
+
+type BankStatements []*BankStatement
+
+type BankStatementLine struct {
+	Model
+
+	BankStatementID string `gorm:"size:100;index" json:"bank_statement_id"`
+	// Fingerprint keeps a line imported once when overlapping statements are uploaded
+	Fingerprint string `gorm:"size:100;unique" json:"-"`
+
+	BookingDate  int64          `json:"booking_date"`
+	Amount       price.Price    `gorm:"type:decimal(20,4)" json:"amount"` // Negative for debits
+	Currency     enums.Currency `gorm:"size:10" json:"currency"`
+	Reference    string         `json:"reference,omitempty"`
+	Description  string         `json:"description,omitempty"`
+	Counterparty string         `json:"counterparty,omitempty"`
+	BankRef      string         `gorm:"size:100" json:"bank_ref,omitempty"`
+
+	Status               enums.BankStatementLineStatus `gorm:"size:20;index;default:'unmatched'" json:"status"`
+	PaymentTransactionID string                        `gorm:"size:100;index" json:"payment_transaction_id,omitempty"`
+	PaymentTransaction   *PaymentTransaction           `gorm:"-" json:"payment_transaction,omitempty"`
+	MatchScore           int                           `json:"match_score"`
+	MatchReason          string                        `json:"match_reason,omitempty"`
+
+	ReconciledByUserID string `gorm:"size:100" json:"reconciled_by_user_id,omitempty"`
+	ReconciledAt       *int64 `json:"reconciled_at,omitempty"`
+}
+
+type BankStatementLines []*BankStatementLine
+
+// BankStatementMatch outcome of matching one statement line against pending transfers
+type BankStatementMatch struct {
+	PaymentTransaction *PaymentTransaction
+	Status             enums.BankStatementLineStatus
+	Score              int
+	Reason             string
+}
@@ -437,7 +437,7 @@ func (bulkPO *BulkPurchaseOrder) IsFinalPaymentPaid() bool {
 
 func (records BulkPurchaseOrders) ToExcel() ([]byte, error) {
 	var data = [][]interface{}{
-		{"Reference ID", "Inquiry ID", "Buyer", "Product", "Tracking Status", "Assignee", "Posted Date"},
+		{"Reference ID", "Inquiry ID", "Buyer", "Product", "Tracking Status", "Total Price", "FX Rate", "Total Price (USD)", "Assignee", "Posted Date"},
 	}
 	var sb strings.Builder
 	sb.WriteString("id,user,product,tracking status,assignee,created date\n")
@@ -463,6 +463,14 @@ func (records BulkPurchaseOrders) ToExcel() ([]byte, error) {
 				return ""
 			}(),
 			record.TrackingStatus.DisplayName(),
+			func() string {
+				if record.TotalPrice != nil {
+					return record.TotalPrice.FormatMoney(record.Currency)
+				}
+				return ""
+			}(),
+			record.FxSnapshot.FormatRate(),
+			record.FxSnapshot.FormatBasePrice(record.BaseTotalPrice),
 			func() interface{} {
 				if len(record.Assignees) > 0 {
 					var names = lo.Map(record.Assignees, func(item *User, index int) string {
@@ -162,6 +162,7 @@ type BulkPurchaseOrder struct {
 	ApproveQCAt          *int64              `json:"approve_qc_at,omitempty"`
 
 	PaymentTransactions []*PaymentTransaction `gorm:"-" json:"payment_transactions,omitempty"`
+	PaymentMilestones   PaymentMilestones     `gorm:"-" json:"payment_milestones,omitempty"`
 
 	LogisticInfo        *PoLogisticMeta `json:"logistic_info,omitempty"`
 	ReceiverConfirmedAt *int64          `json:"receiver_confirmed_at,omitempty"`
@@ -319,6 +320,9 @@ type BulkPurchaseOrderMarkAsPaidParams struct {
 
 	BulkPurchaseOrderID string                 `json:"bulk_purchase_order_id" query:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
 	Milestone           enums.PaymentMilestone `json:"milestone" query:"milestone" param:"milestone"`
+	PaymentMilestoneID  string                 `json:"payment_milestone_id" query:"payment_milestone_id" param:"payment_milestone_id"`
+
+	OutboxTasks []OutboxTask `json:"-"`
 }
 
 type BulkPurchaseOrderMarkAsUnpaidParams struct {
@@ -0,0 +1,53 @@
+package models
+
+import "fmt"
+
+// Tags of the cached reads, writes of the models invalidate them through the gorm callbacks of caching
+const (
+	CacheTagCategory   = "category"
+	CacheTagPage       = "page"
+	CacheTagSettingSEO = "setting_seo"
+
+	// Tags of the cached website responses only, purged by the admin routes
+	CacheTagCollection     = "collection"
+	CacheTagPost           = "post"
+	CacheTagProduct        = "product"
+	CacheTagSEOTranslation = "seo_translation"
+)
+
+func GetCategoryCacheTag(categoryID string) string {
+	return fmt.Sprintf("%s:%s", CacheTagCategory, categoryID)
+}
+
+func GetPageCacheTag(pageID string) string {
+	return fmt.Sprintf("%s:%s", CacheTagPage, pageID)
+}
+
+func (c *Category) CacheTags() []string {
+	var tags = []string{CacheTagCategory}
+	if c.ID != "" {
+		tags = append(tags, GetCategoryCacheTag(c.ID))
+	}
+	return tags
+}
+
+func (p *Page) CacheTags() []string {
+	var tags = []string{CacheTagPage}
+	if p.ID != "" {
+		tags = append(tags, GetPageCacheTag(p.ID))
+	}
+	return tags
+}
+
+// CacheTags sections are read through their page
+func (s *PageSection) CacheTags() []string {
+	var tags = []string{CacheTagPage}
+	if s.PageID != "" {
+		tags = append(tags, GetPageCacheTag(s.PageID))
+	}
+	return tags
+}
+
+func (s *SettingSEO) CacheTags() []string {
+	return []string{CacheTagSettingSEO}
+}
@@ -2,6 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 )
 
 func (msg ChatMessage) ToJSONRaw() json.RawMessage {
@@ -15,3 +19,85 @@ func (msg WSMessagePayload) ToJSONRaw() json.RawMessage {
 
 	return bytes
 }
+
+// ChatMessageEditWindow the sender can edit or delete the message until this long after sending it
+const ChatMessageEditWindow = time.Minute * 15
+
+// CheckChange the sender changes the message within the edit window, admins can delete any message
+func (msg *ChatMessage) CheckChange(userID string, role enums.Role, action enums.ChatMessageEditAction, now time.Time) error {
+	if msg.RemovedAt != nil {
+		return errs.ErrChatMessageRemoved
+	}
+
+	if action == enums.ChatMessageEditActionDelete && role.IsAdmin() {
+		return nil
+	}
+
+	if msg.SenderID != userID {
+		return errs.ErrChatMessageNotSender
+	}
+
+	if now.Unix()-msg.CreatedAt > int64(ChatMessageEditWindow.Seconds()) {
+		return errs.ErrChatMessageEditWindowExpired
+	}
+
+	return nil
+}
+
+// GetThreadID root message of the thread of the replies to the message
+func (msg *ChatMessage) GetThreadID() string {
+	if msg.ThreadID != "" {
+		return msg.ThreadID
+	}
+
+	return msg.ID
+}
+
+// GetUpdatedFields columns of the edit, the text or the attachments left out of the request are kept
+func (req *UpdateChatMessageRequest) GetUpdatedFields() []string {
+	var fields = []string{"EditedAt"}
+	if req.Message != "" {
+		fields = append(fields, "Message")
+	}
+	if req.Attachments != nil {
+		fields = append(fields, "Attachments")
+	}
+
+	return fields
+}
+
+type ChatMessageReactions []*ChatMessageReaction
+
+// Summaries reactions grouped by emoji in the order of the first reaction with it
+func (reactions ChatMessageReactions) Summaries() []*ChatMessageReactionSummary {
+	var result []*ChatMessageReactionSummary
+	var summaries = map[string]*ChatMessageReactionSummary{}
+	for _, reaction := range reactions {
+		var summary, ok = summaries[reaction.Emoji]
+		if !ok {
+			summary = &ChatMessageReactionSummary{Emoji: reaction.Emoji}
+			summaries[reaction.Emoji] = summary
+			result = append(result, summary)
+		}
+
+		summary.Count++
+		summary.UserIDs = append(summary.UserIDs, reaction.UserID)
+	}
+
+	return result
+}
+
+// GroupByMessageID reaction summaries of each message
+func (reactions ChatMessageReactions) GroupByMessageID() map[string][]*ChatMessageReactionSummary {
+	var groups = map[string]ChatMessageReactions{}
+	for _, reaction := range reactions {
+		groups[reaction.MessageID] = append(groups[reaction.MessageID], reaction)
+	}
+
+	var result = make(map[string][]*ChatMessageReactionSummary, len(groups))
+	for messageID, group := range groups {
+		result[messageID] = group.Summaries()
+	}
+
+	return result
+}
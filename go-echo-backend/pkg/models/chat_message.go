@@ -20,6 +20,21 @@ type ChatMessage struct {
 	Attachments *Attachments `json:"attachments,omitempty"`
 
 	SeenAt *int64 `json:"seen_at,omitempty"`
+
+	// ReplyToID message replied to, ThreadID root message of the thread, ReplyCount replies of a root message
+	ReplyToID  string       `gorm:"index" json:"reply_to_id,omitempty"`
+	ReplyTo    *ChatMessage `gorm:"-" json:"reply_to,omitempty"`
+	ThreadID   string       `gorm:"index" json:"thread_id,omitempty"`
+	ReplyCount int          `gorm:"default:0" json:"reply_count,omitempty"`
+
+	Reactions []*ChatMessageReactionSummary `gorm:"-" json:"reactions,omitempty"`
+
+	// EditedAt last edit, the previous versions are kept in chat_message_edits
+	EditedAt *int64 `json:"edited_at,omitempty"`
+
+	// RemovedAt deleted for everyone, the message and attachments are cleared
+	RemovedAt       *int64 `json:"removed_at,omitempty"`
+	RemovedByUserID string `json:"removed_by_user_id,omitempty"`
 }
 
 type CreateChatMessageRequest struct {
@@ -28,12 +43,38 @@ type CreateChatMessageRequest struct {
 	SenderID    string       `json:"sender_id"`
 	Message     string       `json:"message"`
 	Attachments *Attachments `json:"attachments,omitempty"`
+	ReplyToID   string       `json:"reply_to_id"`
 }
 
 type GetMessageListRequest struct {
 	PaginationParams
+	CursorParams
+	JwtClaimsInfo
+	RoomID   string `json:"room_id" query:"room_id"`
+	ThreadID string `json:"thread_id" query:"thread_id"`
+}
+
+type UpdateChatMessageRequest struct {
+	JwtClaimsInfo
+	ChatMessageID string       `json:"chat_message_id" param:"chat_message_id" validate:"required"`
+	Message       string       `json:"message"`
+	Attachments   *Attachments `json:"attachments,omitempty"`
+}
+
+type DeleteChatMessageRequest struct {
+	JwtClaimsInfo
+	ChatMessageID string `json:"chat_message_id" param:"chat_message_id" validate:"required"`
+}
+
+type ReactChatMessageRequest struct {
+	JwtClaimsInfo
+	ChatMessageID string `json:"chat_message_id" param:"chat_message_id" validate:"required"`
+	Emoji         string `json:"emoji" query:"emoji" validate:"required,max=32"`
+}
+
+type GetChatMessageEditsRequest struct {
 	JwtClaimsInfo
-	RoomID string `json:"room_id" query:"room_id"`
+	ChatMessageID string `json:"chat_message_id" param:"chat_message_id" validate:"required"`
 }
 
 type GetChatUserRelevantStageRequest struct {
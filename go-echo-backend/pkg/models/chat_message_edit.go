@@ -0,0 +1,15 @@
+package models
+
+import "github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+
+// ChatMessageEdit previous content of an edited or deleted message, kept for disputes
+type ChatMessageEdit struct {
+	Model
+
+	MessageID   string                      `gorm:"size:200;not null;index" json:"message_id"`
+	UserID      string                      `gorm:"size:200;not null" json:"user_id"`
+	User        *User                       `gorm:"-" json:"user,omitempty"`
+	Action      enums.ChatMessageEditAction `gorm:"size:50;not null" json:"action"`
+	Message     string                      `json:"message,omitempty"`
+	Attachments *Attachments                `json:"attachments,omitempty"`
+}
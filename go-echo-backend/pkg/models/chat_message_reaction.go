@@ -0,0 +1,17 @@
+package models
+
+// ChatMessageReaction emoji of a user on a message, a user reacts once with each emoji
+type ChatMessageReaction struct {
+	Model
+
+	MessageID string `gorm:"size:200;not null;uniqueIndex:idx_chat_message_reaction" json:"message_id"`
+	UserID    string `gorm:"size:200;not null;uniqueIndex:idx_chat_message_reaction" json:"user_id"`
+	Emoji     string `gorm:"size:32;not null;uniqueIndex:idx_chat_message_reaction" json:"emoji"`
+}
+
+// ChatMessageReactionSummary users who reacted to the message with the emoji
+type ChatMessageReactionSummary struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"user_ids"`
+}
@@ -26,3 +26,8 @@ func (c *Comment) GetCustomerIOMetadata(extras map[string]interface{}) map[strin
 	return result
 
 }
+
+// IsCursorPaging the client opted in to keyset paging
+func (p CursorParams) IsCursorPaging() bool {
+	return p.Paging == "cursor" || p.Cursor != ""
+}
@@ -32,6 +32,13 @@ type PaginationParams struct {
 	WithoutCount bool   `json:"-"`
 }
 
+// CursorParams opaque cursor of the lists paged by keyset, next_cursor of the previous page.
+// Lists are paged by offset unless paging=cursor or a cursor is sent.
+type CursorParams struct {
+	Paging string `json:"paging" query:"paging" form:"paging" validate:"omitempty,oneof=offset cursor"`
+	Cursor string `json:"cursor" query:"cursor" form:"cursor" validate:"max=500"`
+}
+
 type AssetCustomClaims struct {
 	FileName string `json:"file_name"`
 	jwt.StandardClaims
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+)
+
+// DeadLetterTask a task archived by asynq after it exhausted its retries
+type DeadLetterTask struct {
+	Model
+
+	TaskID          string                 `gorm:"size:300;uniqueIndex:idx_dead_letter_tasks_queue_task" json:"task_id"`
+	Queue           string                 `gorm:"size:50;uniqueIndex:idx_dead_letter_tasks_queue_task" json:"queue"`
+	TaskName        string                 `gorm:"size:200;not null;index" json:"task_name"`
+	Payload         json.RawMessage        `gorm:"type:jsonb" json:"payload,omitempty" swaggertype:"object"`
+	OriginalPayload json.RawMessage        `gorm:"type:jsonb" json:"original_payload,omitempty" swaggertype:"object"`
+	Status          enums.DeadLetterStatus `gorm:"size:50;default:'archived';index" json:"status"`
+
+	Retried      int             `json:"retried"`
+	MaxRetry     int             `json:"max_retry"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	ErrorStack   json.RawMessage `gorm:"type:jsonb" json:"error_stack,omitempty" swaggertype:"object"`
+	FailedAt     int64           `gorm:"index" json:"failed_at"`
+
+	ReferenceType string `gorm:"size:100" json:"reference_type,omitempty"`
+	ReferenceID   string `gorm:"size:200;index" json:"reference_id,omitempty"`
+
+	EditedByUserID   string `gorm:"size:200" json:"edited_by_user_id,omitempty"`
+	EditedAt         *int64 `json:"edited_at,omitempty"`
+	ReplayCount      int    `gorm:"default:0" json:"replay_count"`
+	ReplayedByUserID string `gorm:"size:200" json:"replayed_by_user_id,omitempty"`
+	ReplayedAt       *int64 `json:"replayed_at,omitempty"`
+	ReplayTaskID     string `gorm:"size:300" json:"replay_task_id,omitempty"`
+}
+
+type DeadLetterTasks []*DeadLetterTask
+
+type ReplayDeadLetterTasksForm struct {
+	IDs        []string `json:"ids" validate:"required_without=TaskName"`
+	TaskName   string   `json:"task_name" validate:"required_without=IDs"`
+	FailedFrom int64    `json:"failed_from"`
+	FailedTo   int64    `json:"failed_to"`
+}
@@ -0,0 +1,55 @@
+package enums
+
+type BankStatementFormat string
+
+var (
+	BankStatementFormatCSV     BankStatementFormat = "csv"
+	BankStatementFormatMT940   BankStatementFormat = "mt940"
+	BankStatementFormatCAMT053 BankStatementFormat = "camt053"
+)
+
+func (p BankStatementFormat) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case BankStatementFormatCSV:
+		return "CSV"
+
+	case BankStatementFormatMT940:
+		return "MT940"
+
+	case BankStatementFormatCAMT053:
+		return "CAMT.053"
+	}
+	return name
+}
+
+type BankStatementLineStatus string
+
+var (
+	BankStatementLineStatusUnmatched  BankStatementLineStatus = "unmatched"
+	BankStatementLineStatusMatched    BankStatementLineStatus = "matched"   // Reference, amount and currency all agree
+	BankStatementLineStatusSuggested  BankStatementLineStatus = "suggested" // Fuzzy match waiting for review
+	BankStatementLineStatusReconciled BankStatementLineStatus = "reconciled"
+	BankStatementLineStatusIgnored    BankStatementLineStatus = "ignored"
+)
+
+func (p BankStatementLineStatus) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case BankStatementLineStatusUnmatched:
+		return "Unmatched"
+
+	case BankStatementLineStatusMatched:
+		return "Matched"
+
+	case BankStatementLineStatusSuggested:
+		return "Needs review"
+
+	case BankStatementLineStatusReconciled:
+		return "Reconciled"
+
+	case BankStatementLineStatusIgnored:
+		return "Ignored"
+	}
+	return name
+}
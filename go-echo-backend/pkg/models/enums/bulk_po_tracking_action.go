@@ -26,6 +26,9 @@ var (
 	BulkPoTrackingActionFirstPaymentDenied      BulkPoTrackingAction = "first_payment_denied"
 	BulkPoTrackingActionFinalPaymentDenied      BulkPoTrackingAction = "final_payment_denied"
 	BulkPoTrackingActionStageComment            BulkPoTrackingAction = "stage_comment"
+	BulkPoTrackingActionUpdateOrder             BulkPoTrackingAction = "update_order"
+	BulkPoTrackingActionResetOrder              BulkPoTrackingAction = "reset_order"
+	BulkPoTrackingActionSkipFirstPayment        BulkPoTrackingAction = "skip_first_payment"
 
 	BulkPoTrackingActionAdminApproveSellerQuotation BulkPoTrackingAction = "admin_approve_seller_quotation"
 	BulkPoTrackingActionAdminRejectSellerQuotation  BulkPoTrackingAction = "admin_reject_seller_quotation"
@@ -40,4 +43,9 @@ var (
 	BulkPoTrackingActionSellerMarkRawMaterial       BulkPoTrackingAction = "seller_mark_raw_material"
 	BulkPoTrackingActionSellerMarkInspection        BulkPoTrackingAction = "seller_mark_inspection"
 	BulkPoTrackingActionSellerUpdatePps             BulkPoTrackingAction = "seller_update_pps"
+	BulkPoTrackingActionAdminUploadSellerPo         BulkPoTrackingAction = "admin_upload_seller_po"
+	BulkPoTrackingActionAdminFirstPayout            BulkPoTrackingAction = "admin_first_payout"
+	BulkPoTrackingActionAdminSkipFirstPayout        BulkPoTrackingAction = "admin_skip_first_payout"
+	BulkPoTrackingActionAdminFinalPayout            BulkPoTrackingAction = "admin_final_payout"
+	BulkPoTrackingActionStartWithoutFirstPayment    BulkPoTrackingAction = "start_without_first_payment"
 )
@@ -0,0 +1,41 @@
+package enums
+
+type BulkPoTransitionActor string
+
+var (
+	BulkPoTransitionActorAdmin  BulkPoTransitionActor = "admin"
+	BulkPoTransitionActorBuyer  BulkPoTransitionActor = "buyer"
+	BulkPoTransitionActorSeller BulkPoTransitionActor = "seller"
+	BulkPoTransitionActorSystem BulkPoTransitionActor = "system"
+)
+
+// BulkPoTransitionActorFromRole empty role means the call comes from a worker or a webhook
+func BulkPoTransitionActorFromRole(role Role) BulkPoTransitionActor {
+	switch {
+	case role == "":
+		return BulkPoTransitionActorSystem
+	case role.IsAdmin():
+		return BulkPoTransitionActorAdmin
+	case role.IsSeller():
+		return BulkPoTransitionActorSeller
+	}
+	return BulkPoTransitionActorBuyer
+}
+
+func (p BulkPoTransitionActor) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case BulkPoTransitionActorAdmin:
+		return "Admin"
+
+	case BulkPoTransitionActorBuyer:
+		return "Buyer"
+
+	case BulkPoTransitionActorSeller:
+		return "Seller"
+
+	case BulkPoTransitionActorSystem:
+		return "System"
+	}
+	return name
+}
@@ -0,0 +1,20 @@
+package enums
+
+type ChatMessageEditAction string
+
+var (
+	ChatMessageEditActionEdit   ChatMessageEditAction = "edit"
+	ChatMessageEditActionDelete ChatMessageEditAction = "delete"
+)
+
+func (p ChatMessageEditAction) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case ChatMessageEditActionEdit:
+		return "Edit"
+
+	case ChatMessageEditActionDelete:
+		return "Delete"
+	}
+	return name
+}
@@ -7,6 +7,10 @@ var (
 	ChatMessageWsTypeSeenRoom     ChatMessageWsType = "mark_seen"
 	ChatMessageWsTypeTyping       ChatMessageWsType = "typing"
 	ChatMessageWsTypeCancelTyping ChatMessageWsType = "cancel_typing"
+
+	ChatMessageWsTypeMessageUpdated  ChatMessageWsType = "message_updated"
+	ChatMessageWsTypeMessageDeleted  ChatMessageWsType = "message_deleted"
+	ChatMessageWsTypeReactionUpdated ChatMessageWsType = "reaction_updated"
 )
 
 func (p ChatMessageWsType) String() string {
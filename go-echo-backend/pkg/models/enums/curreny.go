@@ -17,9 +17,24 @@ func (c Currency) DefaultIfInvalid() Currency {
 	return result
 }
 
-func (c Currency) GetCountryCode() CountryCode {
+// GetRegionCode region segment of the formatted invoice number
+func (c Currency) GetRegionCode() string {
 	switch c {
+	case VND:
+		return "VN"
+	case SGD:
+		return "SG"
+	default:
+		return "US"
+	}
+}
 
+func (c Currency) GetCountryCode() CountryCode {
+	switch c {
+	case VND:
+		return CountryCodeVN
+	case SGD:
+		return CountryCodeSG
 	default:
 		return CountryCodeUS
 	}
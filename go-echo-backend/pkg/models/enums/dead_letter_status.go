@@ -0,0 +1,24 @@
+package enums
+
+type DeadLetterStatus string
+
+var (
+	DeadLetterStatusArchived  DeadLetterStatus = "archived"
+	DeadLetterStatusReplayed  DeadLetterStatus = "replayed"
+	DeadLetterStatusDiscarded DeadLetterStatus = "discarded"
+)
+
+func (p DeadLetterStatus) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case DeadLetterStatusArchived:
+		return "Archived"
+
+	case DeadLetterStatusReplayed:
+		return "Replayed"
+
+	case DeadLetterStatusDiscarded:
+		return "Discarded"
+	}
+	return name
+}
@@ -0,0 +1,48 @@
+package enums
+
+type FilterPriceTier string
+
+var (
+	FilterPriceTierUnder5 FilterPriceTier = "under_5"
+	FilterPriceTier5To10  FilterPriceTier = "5_to_10"
+	FilterPriceTier10To20 FilterPriceTier = "10_to_20"
+	FilterPriceTier20To50 FilterPriceTier = "20_to_50"
+	FilterPriceTierOver50 FilterPriceTier = "over_50"
+)
+
+func (p FilterPriceTier) DisplayName() string {
+	var name string
+
+	switch p {
+	case FilterPriceTierUnder5:
+		name = "Under $5"
+	case FilterPriceTier5To10:
+		name = "$5 - $10"
+	case FilterPriceTier10To20:
+		name = "$10 - $20"
+	case FilterPriceTier20To50:
+		name = "$20 - $50"
+	case FilterPriceTierOver50:
+		name = "Over $50"
+	}
+
+	return name
+}
+
+// Range lower bound included, upper bound excluded, 0 when unbounded
+func (p FilterPriceTier) Range() (float64, float64) {
+	switch p {
+	case FilterPriceTierUnder5:
+		return 0, 5
+	case FilterPriceTier5To10:
+		return 5, 10
+	case FilterPriceTier10To20:
+		return 10, 20
+	case FilterPriceTier20To50:
+		return 20, 50
+	case FilterPriceTierOver50:
+		return 50, 0
+	}
+
+	return 0, 0
+}
@@ -0,0 +1,20 @@
+package enums
+
+type FxRateSource string
+
+var (
+	FxRateSourceManual FxRateSource = "manual"
+	FxRateSourceCSV    FxRateSource = "csv"
+)
+
+func (p FxRateSource) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case FxRateSourceManual:
+		return "Manual"
+
+	case FxRateSourceCSV:
+		return "CSV import"
+	}
+	return name
+}
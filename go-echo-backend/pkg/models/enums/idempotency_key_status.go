@@ -0,0 +1,20 @@
+package enums
+
+type IdempotencyKeyStatus string
+
+var (
+	IdempotencyKeyStatusProcessing IdempotencyKeyStatus = "processing"
+	IdempotencyKeyStatusCompleted  IdempotencyKeyStatus = "completed"
+)
+
+func (p IdempotencyKeyStatus) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case IdempotencyKeyStatusProcessing:
+		return "Processing"
+
+	case IdempotencyKeyStatusCompleted:
+		return "Completed"
+	}
+	return name
+}
@@ -3,6 +3,31 @@ package enums
 type InvoiceStatus string
 
 var (
-	InvoiceStatusPaid   InvoiceStatus = "paid"
-	InvoiceStatusUnpaid InvoiceStatus = "unpaid"
+	InvoiceStatusPaid              InvoiceStatus = "paid"
+	InvoiceStatusUnpaid            InvoiceStatus = "unpaid"
+	InvoiceStatusPartiallyRefunded InvoiceStatus = "partially_refunded"
+	InvoiceStatusRefunded          InvoiceStatus = "refunded"
+	InvoiceStatusVoid              InvoiceStatus = "void"
+	InvoiceStatusCredited          InvoiceStatus = "credited" // Credit note issued without a refund
 )
+
+func (s InvoiceStatus) DisplayName() string {
+	var name = string(s)
+
+	switch s {
+	case InvoiceStatusPaid:
+		name = "Paid"
+	case InvoiceStatusUnpaid:
+		name = "Unpaid"
+	case InvoiceStatusPartiallyRefunded:
+		name = "Partially refunded"
+	case InvoiceStatusRefunded:
+		name = "Refunded"
+	case InvoiceStatusVoid:
+		name = "Void"
+	case InvoiceStatusCredited:
+		name = "Credited"
+	}
+
+	return name
+}
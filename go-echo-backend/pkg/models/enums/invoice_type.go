@@ -9,6 +9,10 @@ var (
 	InvoiceTypeBulkPOFirstPayment   InvoiceType = "bulk_po_first_payment"
 	InvoiceTypeBulkPOSecondPayment  InvoiceType = "bulk_po_first_payment"
 	InvoiceTypeBulkPOFinalPayment   InvoiceType = "bulk_po_final_payment"
+
+	InvoiceTypeBulkPOMilestonePayment InvoiceType = "bulk_po_milestone_payment"
+
+	InvoiceTypeCreditNote InvoiceType = "credit_note"
 )
 
 func (it InvoiceType) DisplayName() string {
@@ -29,7 +33,25 @@ func (it InvoiceType) DisplayName() string {
 
 	case InvoiceTypeBulkPOFinalPayment:
 		name = "Bulk Order"
+
+	case InvoiceTypeBulkPOMilestonePayment:
+		name = "Debit Note (Milestone)"
+
+	case InvoiceTypeCreditNote:
+		name = "Credit Note"
 	}
 
 	return name
 }
+
+// SeriesPrefix prefix of the formatted invoice number, debit notes and invoices are numbered in separate series
+func (it InvoiceType) SeriesPrefix() string {
+	switch it {
+	case InvoiceTypeBulkPODepositPayment, InvoiceTypeBulkPOFirstPayment, InvoiceTypeBulkPOMilestonePayment:
+		return "DN"
+	case InvoiceTypeCreditNote:
+		return "CN"
+	default:
+		return "INV"
+	}
+}
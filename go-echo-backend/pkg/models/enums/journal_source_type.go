@@ -0,0 +1,51 @@
+package enums
+
+type JournalSourceType string
+
+var (
+	JournalSourceTypeInvoice               JournalSourceType = "invoice"
+	JournalSourceTypeCreditNote            JournalSourceType = "credit_note"
+	JournalSourceTypePayment               JournalSourceType = "payment"
+	JournalSourceTypeProcessingFee         JournalSourceType = "processing_fee"
+	JournalSourceTypeRefund                JournalSourceType = "refund"
+	JournalSourceTypeSellerPayout          JournalSourceType = "seller_payout"
+	JournalSourceTypeSampleDeduction       JournalSourceType = "sample_deduction"
+	JournalSourceTypeFinalPaymentDeduction JournalSourceType = "final_payment_deduction"
+)
+
+func (p JournalSourceType) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case JournalSourceTypeInvoice:
+		return "Invoice"
+
+	case JournalSourceTypeCreditNote:
+		return "Credit Note"
+
+	case JournalSourceTypePayment:
+		return "Payment"
+
+	case JournalSourceTypeProcessingFee:
+		return "Processing Fee"
+
+	case JournalSourceTypeRefund:
+		return "Refund"
+
+	case JournalSourceTypeSellerPayout:
+		return "Seller Payout"
+
+	case JournalSourceTypeSampleDeduction:
+		return "Sample Deduction"
+
+	case JournalSourceTypeFinalPaymentDeduction:
+		return "Final Payment Deduction"
+	}
+	return name
+}
+
+type LedgerExportFormat string
+
+var (
+	LedgerExportFormatXero       LedgerExportFormat = "xero"
+	LedgerExportFormatQuickBooks LedgerExportFormat = "quickbooks"
+)
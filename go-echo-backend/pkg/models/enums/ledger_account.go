@@ -0,0 +1,66 @@
+package enums
+
+// LedgerAccount chart of accounts code, matching the account codes set up in Xero/QuickBooks
+type LedgerAccount string
+
+var (
+	LedgerAccountBank               LedgerAccount = "1100"
+	LedgerAccountStripeClearing     LedgerAccount = "1110"
+	LedgerAccountAccountsReceivable LedgerAccount = "1200"
+	LedgerAccountTaxPayable         LedgerAccount = "2200"
+	LedgerAccountSalesRevenue       LedgerAccount = "4000"
+	LedgerAccountShippingRevenue    LedgerAccount = "4100"
+	LedgerAccountPaymentFeeRevenue  LedgerAccount = "4200"
+	LedgerAccountSalesDeductions    LedgerAccount = "4900"
+	LedgerAccountSalesReturns       LedgerAccount = "4910"
+	LedgerAccountCostOfGoodsSold    LedgerAccount = "5000"
+	LedgerAccountProcessingFees     LedgerAccount = "6100"
+)
+
+func (p LedgerAccount) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case LedgerAccountBank:
+		return "Bank"
+
+	case LedgerAccountStripeClearing:
+		return "Stripe Clearing"
+
+	case LedgerAccountAccountsReceivable:
+		return "Accounts Receivable"
+
+	case LedgerAccountTaxPayable:
+		return "Sales Tax Payable"
+
+	case LedgerAccountSalesRevenue:
+		return "Sales Revenue"
+
+	case LedgerAccountShippingRevenue:
+		return "Shipping Revenue"
+
+	case LedgerAccountPaymentFeeRevenue:
+		return "Payment Fee Revenue"
+
+	case LedgerAccountSalesDeductions:
+		return "Sales Deductions"
+
+	case LedgerAccountSalesReturns:
+		return "Sales Returns"
+
+	case LedgerAccountCostOfGoodsSold:
+		return "Cost of Goods Sold"
+
+	case LedgerAccountProcessingFees:
+		return "Payment Processing Fees"
+	}
+	return name
+}
+
+// GetCashLedgerAccount account the money of a payment type lands on
+func (v PaymentType) GetCashLedgerAccount() LedgerAccount {
+	if v == PaymentTypeCard {
+		return LedgerAccountStripeClearing
+	}
+
+	return LedgerAccountBank
+}
@@ -0,0 +1,24 @@
+package enums
+
+type OutboxStatus string
+
+var (
+	OutboxStatusPending    OutboxStatus = "pending"
+	OutboxStatusDispatched OutboxStatus = "dispatched"
+	OutboxStatusFailed     OutboxStatus = "failed"
+)
+
+func (p OutboxStatus) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case OutboxStatusPending:
+		return "Pending"
+
+	case OutboxStatusDispatched:
+		return "Dispatched"
+
+	case OutboxStatusFailed:
+		return "Failed"
+	}
+	return name
+}
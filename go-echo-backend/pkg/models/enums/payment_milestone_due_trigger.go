@@ -0,0 +1,31 @@
+package enums
+
+type PaymentMilestoneDueTrigger string
+
+var (
+	PaymentMilestoneDueTriggerOnQuotation      PaymentMilestoneDueTrigger = "on_quotation"
+	PaymentMilestoneDueTriggerBeforeProduction PaymentMilestoneDueTrigger = "before_production"
+	PaymentMilestoneDueTriggerAfterQc          PaymentMilestoneDueTrigger = "after_qc"
+	PaymentMilestoneDueTriggerBeforeDelivery   PaymentMilestoneDueTrigger = "before_delivery"
+	PaymentMilestoneDueTriggerAfterDelivery    PaymentMilestoneDueTrigger = "after_delivery"
+	PaymentMilestoneDueTriggerFixedDate        PaymentMilestoneDueTrigger = "fixed_date"
+)
+
+func (p PaymentMilestoneDueTrigger) DisplayName() string {
+	switch p {
+	case PaymentMilestoneDueTriggerOnQuotation:
+		return "On quotation"
+	case PaymentMilestoneDueTriggerBeforeProduction:
+		return "Before production"
+	case PaymentMilestoneDueTriggerAfterQc:
+		return "After QC"
+	case PaymentMilestoneDueTriggerBeforeDelivery:
+		return "Before delivery"
+	case PaymentMilestoneDueTriggerAfterDelivery:
+		return "After delivery"
+	case PaymentMilestoneDueTriggerFixedDate:
+		return "Fixed date"
+	}
+
+	return string(p)
+}
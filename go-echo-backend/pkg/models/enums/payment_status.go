@@ -3,6 +3,7 @@ package enums
 type PaymentStatus string
 
 var (
+	PaymentStatusPending        PaymentStatus = "pending"
 	PaymentStatusRefunded       PaymentStatus = "refunded"
 	PaymentStatusPaid           PaymentStatus = "paid"
 	PaymentStatusUnpaid         PaymentStatus = "unpaid"
@@ -13,6 +14,9 @@ var (
 func (p PaymentStatus) DisplayName() string {
 	var name = string(p)
 	switch p {
+	case PaymentStatusPending:
+		return "Pending"
+
 	case PaymentStatusPaid:
 		return "Paid"
 
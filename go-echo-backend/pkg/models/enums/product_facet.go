@@ -0,0 +1,32 @@
+package enums
+
+type ProductFacet string
+
+var (
+	ProductFacetCategory  ProductFacet = "category"
+	ProductFacetFabric    ProductFacet = "fabric"
+	ProductFacetMinOrder  ProductFacet = "min_order"
+	ProductFacetRating    ProductFacet = "rating"
+	ProductFacetPriceTier ProductFacet = "price_tier"
+)
+
+func (p ProductFacet) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case ProductFacetCategory:
+		return "Category"
+
+	case ProductFacetFabric:
+		return "Fabric"
+
+	case ProductFacetMinOrder:
+		return "Minimum order"
+
+	case ProductFacetRating:
+		return "Rating"
+
+	case ProductFacetPriceTier:
+		return "Price"
+	}
+	return name
+}
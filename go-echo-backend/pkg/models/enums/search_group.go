@@ -0,0 +1,36 @@
+package enums
+
+type SearchGroup string
+
+var (
+	SearchGroupUsers              SearchGroup = "users"
+	SearchGroupInquiries          SearchGroup = "inquiries"
+	SearchGroupPurchaseOrders     SearchGroup = "purchase_orders"
+	SearchGroupBulkPurchaseOrders SearchGroup = "bulk_purchase_orders"
+	SearchGroupProducts           SearchGroup = "products"
+	SearchGroupInvoices           SearchGroup = "invoices"
+)
+
+func (p SearchGroup) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case SearchGroupUsers:
+		return "Users"
+
+	case SearchGroupInquiries:
+		return "Inquiries"
+
+	case SearchGroupPurchaseOrders:
+		return "Purchase Orders"
+
+	case SearchGroupBulkPurchaseOrders:
+		return "Bulk Purchase Orders"
+
+	case SearchGroupProducts:
+		return "Products"
+
+	case SearchGroupInvoices:
+		return "Invoices"
+	}
+	return name
+}
@@ -0,0 +1,41 @@
+package enums
+
+type TopicKind string
+
+var (
+	TopicKindInquiry           TopicKind = "inquiry"
+	TopicKindPurchaseOrder     TopicKind = "purchase_order"
+	TopicKindBulkPurchaseOrder TopicKind = "bulk_purchase_order"
+	TopicKindNotifications     TopicKind = "notifications"
+	TopicKindInbox             TopicKind = "inbox" // Chat payloads sent to the user
+)
+
+func (p TopicKind) DisplayName() string {
+	var name = string(p)
+	switch p {
+	case TopicKindInquiry:
+		return "Inquiry"
+
+	case TopicKindPurchaseOrder:
+		return "Purchase order"
+
+	case TopicKindBulkPurchaseOrder:
+		return "Bulk purchase order"
+
+	case TopicKindNotifications:
+		return "Notifications"
+
+	case TopicKindInbox:
+		return "Inbox"
+	}
+	return name
+}
+
+type TopicEventType string
+
+var (
+	TopicEventTypeTrackingCreated     TopicEventType = "tracking_created"
+	TopicEventTypeAuditCreated        TopicEventType = "audit_created"
+	TopicEventTypeNotificationCreated TopicEventType = "notification_created"
+	TopicEventTypeMessage             TopicEventType = "message"
+)
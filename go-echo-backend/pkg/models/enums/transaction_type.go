@@ -5,4 +5,5 @@ type TransactionType string
 var (
 	TransactionTypeDebit  TransactionType = "debit"
 	TransactionTypeCredit TransactionType = "credit"
+	TransactionTypeRefund TransactionType = "refund" // Money returned to the buyer against a credit note
 )
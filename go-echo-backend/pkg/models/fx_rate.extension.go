@@ -0,0 +1,170 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"gorm.io/gorm"
+)
+
+// IsEmpty no rate was snapshotted
+func (s *FxSnapshot) IsEmpty() bool {
+	return s == nil || s.FxRate == nil || *s.FxRate <= 0
+}
+
+// Convert converts an amount of the document currency into FxBaseCurrency
+func (s *FxSnapshot) Convert(amount *price.Price) *price.Price {
+	if s.IsEmpty() || amount == nil {
+		return nil
+	}
+
+	return amount.Div(price.NewFromFloat(*s.FxRate)).ToPtr()
+}
+
+// ApplyTo stores the snapshot and the converted totals of src on dst
+func (s *FxSnapshot) ApplyTo(dst *Pricing, src Pricing) {
+	if s.IsEmpty() || dst == nil {
+		return
+	}
+
+	dst.FxSnapshot = *s
+	dst.BaseSubTotal = s.Convert(src.SubTotal)
+	dst.BaseTotalPrice = s.Convert(src.TotalPrice)
+}
+
+// FormatRate rate as shown in exports, empty when no rate was snapshotted
+func (s *FxSnapshot) FormatRate() string {
+	if s.IsEmpty() {
+		return ""
+	}
+
+	return strconv.FormatFloat(*s.FxRate, 'f', -1, 64)
+}
+
+// FormatBasePrice formats an amount already converted into FxBaseCurrency
+func (s *FxSnapshot) FormatBasePrice(amount *price.Price) string {
+	if s.IsEmpty() || amount == nil {
+		return ""
+	}
+
+	return amount.FormatMoney(s.FxBaseCurrency.DefaultIfInvalid())
+}
+
+// GetFxRateTx latest rate of the pair effective at the given time, falling back to the inverted pair
+func GetFxRateTx(tx *gorm.DB, base, quote enums.Currency, at int64) (*FxRate, error) {
+	base = base.DefaultIfInvalid()
+	quote = quote.DefaultIfInvalid()
+	if base == quote {
+		return &FxRate{BaseCurrency: base, QuoteCurrency: quote, Rate: 1, EffectiveAt: at}, nil
+	}
+
+	var rate FxRate
+	var err = tx.Where("base_currency = ? AND quote_currency = ? AND effective_at <= ?", base, quote, at).
+		Order("effective_at DESC").
+		First(&rate).Error
+	if err == nil {
+		return &rate, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = tx.Where("base_currency = ? AND quote_currency = ? AND effective_at <= ?", quote, base, at).
+		Order("effective_at DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &FxRate{
+		Model:         rate.Model,
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          price.NewFromInt(1).Div(price.NewFromFloat(rate.Rate), true).Round(8).ToFloat64(),
+		EffectiveAt:   rate.EffectiveAt,
+		Source:        rate.Source,
+	}, nil
+}
+
+// NewFxSnapshotTx snapshots the rate of currency against FxBaseCurrency, gorm.ErrRecordNotFound when no rate is maintained
+func NewFxSnapshotTx(tx *gorm.DB, currency enums.Currency, at int64) (*FxSnapshot, error) {
+	rate, err := GetFxRateTx(tx, FxBaseCurrency, currency, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FxSnapshot{
+		FxRateID:       rate.ID,
+		FxBaseCurrency: FxBaseCurrency,
+		FxRate:         &rate.Rate,
+		FxRatedAt:      &at,
+	}, nil
+}
+
+// ParseFxRatesCSV reads base_currency,quote_currency,rate,effective_date rows.
+// The header row is optional, effective_date is either YYYY-MM-DD (UTC) or a unix timestamp.
+func ParseFxRatesCSV(reader io.Reader) (FxRates, error) {
+	var csvReader = csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var result FxRates
+	for index, row := range rows {
+		if len(row) < 4 {
+			return nil, fmt.Errorf("row %d: expected 4 columns, got %d", index+1, len(row))
+		}
+		if index == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "base_currency") {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("row %d: invalid rate %q", index+1, row[2])
+		}
+
+		effectiveAt, err := parseFxEffectiveDate(strings.TrimSpace(row[3]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid effective date %q", index+1, row[3])
+		}
+
+		var base = enums.Currency(strings.ToUpper(strings.TrimSpace(row[0])))
+		var quote = enums.Currency(strings.ToUpper(strings.TrimSpace(row[1])))
+		if base == "" || quote == "" || base == quote {
+			return nil, fmt.Errorf("row %d: invalid currency pair %s/%s", index+1, base, quote)
+		}
+
+		result = append(result, &FxRate{
+			BaseCurrency:  base,
+			QuoteCurrency: quote,
+			Rate:          rate,
+			EffectiveAt:   effectiveAt,
+			Source:        enums.FxRateSourceCSV,
+		})
+	}
+
+	return result, nil
+}
+
+func parseFxEffectiveDate(value string) (int64, error) {
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return unix, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return date.UTC().Unix(), nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+)
+
+// FxBaseCurrency settlement currency margins are reported in
+var FxBaseCurrency = enums.USD
+
+// FxRate one unit of BaseCurrency is worth Rate units of QuoteCurrency from EffectiveAt on, e.g. USD/VND 25350
+type FxRate struct {
+	Model
+
+	BaseCurrency  enums.Currency `gorm:"size:10;uniqueIndex:idx_fx_rate_pair" json:"base_currency"`
+	QuoteCurrency enums.Currency `gorm:"size:10;uniqueIndex:idx_fx_rate_pair" json:"quote_currency"`
+	EffectiveAt   int64          `gorm:"uniqueIndex:idx_fx_rate_pair" json:"effective_at"`
+
+	Rate            float64            `gorm:"type:decimal(20,8)" json:"rate"`
+	Source          enums.FxRateSource `gorm:"size:20;default:'manual'" json:"source"`
+	CreatedByUserID string             `gorm:"size:100" json:"created_by_user_id,omitempty"`
+}
+
+type FxRates []*FxRate
+
+// FxSnapshot rate frozen on a document at quotation/checkout, so converted totals stay reproducible
+// after the rate table moves on. FxRate is the number of units of the document currency per FxBaseCurrency.
+type FxSnapshot struct {
+	FxRateID       string         `gorm:"size:100" json:"fx_rate_id,omitempty"`
+	FxBaseCurrency enums.Currency `gorm:"size:10" json:"fx_base_currency,omitempty"`
+	FxRate         *float64       `gorm:"type:decimal(20,8)" json:"fx_rate,omitempty"`
+	FxRatedAt      *int64         `json:"fx_rated_at,omitempty"`
+}
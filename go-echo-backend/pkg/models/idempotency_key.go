@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+)
+
+// IdempotencyKey a request sent with the Idempotency-Key header and the response replayed to its retries
+type IdempotencyKey struct {
+	Model
+
+	Key         string                     `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	UserID      string                     `gorm:"size:200;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Method      string                     `gorm:"size:10" json:"method"`
+	Path        string                     `json:"path"`
+	Fingerprint string                     `gorm:"size:64" json:"fingerprint"`
+	Status      enums.IdempotencyKeyStatus `gorm:"size:50;default:'processing'" json:"status"`
+	ExpiresAt   int64                      `gorm:"index" json:"expires_at"`
+
+	ResponseStatus int             `json:"response_status,omitempty"`
+	ResponseHeader json.RawMessage `gorm:"type:jsonb" json:"response_header,omitempty" swaggertype:"object"`
+	ResponseBody   []byte          `json:"-"`
+}
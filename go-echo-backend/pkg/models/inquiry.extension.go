@@ -202,7 +202,7 @@ func (r Inquiries) GetCustomerIOMetadata() (list []map[string]interface{}) {
 
 func (records Inquiries) ToExcel() ([]byte, error) {
 	var data = [][]interface{}{
-		{"Reference ID", "User", "Expected Price", "Quantity", "Product", "Status", "Buyer Status", "Assignee", "Posted Date"},
+		{"Reference ID", "User", "Expected Price", "FX Rate", "Expected Price (USD)", "Quantity", "Product", "Status", "Buyer Status", "Assignee", "Posted Date"},
 	}
 	for _, record := range records {
 		data = append(data, []interface{}{
@@ -219,6 +219,8 @@ func (records Inquiries) ToExcel() ([]byte, error) {
 				}
 				return ""
 			}(),
+			record.FxSnapshot.FormatRate(),
+			record.FxSnapshot.FormatBasePrice(record.FxSnapshot.Convert(record.ExpectedPrice)),
 			values.Int64Value(record.Quantity),
 			record.Title,
 			record.Status.DisplayName(),
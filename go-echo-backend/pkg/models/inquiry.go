@@ -57,6 +57,8 @@ type Inquiry struct {
 	CollectionID           string                        `json:"collection_id,omitempty"`
 	Collection             *InquiryCollection            `gorm:"-" json:"collection,omitempty"`
 
+	FxSnapshot // Rate frozen when the quotation is submitted, carried over to the purchase order
+
 	ShippingAddressID string       `gorm:"size:100" json:"shipping_address_id,omitempty"`
 	ShippingAddress   *Address     `gorm:"-" json:"shipping_address,omitempty"`
 	ShippingFee       *price.Price `gorm:"type:decimal(20,4);default:0.0" json:"shipping_fee,omitempty"`
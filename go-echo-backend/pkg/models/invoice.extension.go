@@ -1,8 +1,16 @@
 package models
 
+import (
+	"fmt"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+)
+
 func (m *Invoice) GetCustomerIOMetadata() map[string]interface{} {
 	var result = map[string]interface{}{
 		"invoice_number": m.InvoiceNumber,
+		"display_number": m.GetDisplayNumber(),
 	}
 
 	if m.DueDate > 0 {
@@ -61,3 +69,33 @@ func (m *Invoice) GetCustomerIOMetadata() map[string]interface{} {
 
 	return result
 }
+
+// GetDisplayNumber formatted series number, invoices issued before the series existed fall back to the internal number
+func (m *Invoice) GetDisplayNumber() string {
+	if m.DisplayNumber != "" {
+		return m.DisplayNumber
+	}
+	return fmt.Sprintf("%d", m.InvoiceNumber)
+}
+
+// IsCreditNote credit notes reverse all or part of OriginalInvoiceNumber
+func (m *Invoice) IsCreditNote() bool {
+	return m.InvoiceType == enums.InvoiceTypeCreditNote
+}
+
+// GetRefundableAmount balance of the invoice that is not credited yet
+func (m *Invoice) GetRefundableAmount() price.Price {
+	return price.NewFromPtr(m.TotalPrice).Sub(price.NewFromPtr(m.RefundedAmount))
+}
+
+// GetPDFMarker marker printed across the document
+func (m *Invoice) GetPDFMarker() string {
+	switch {
+	case m.Status == enums.InvoiceStatusVoid:
+		return "VOID"
+	case m.IsCreditNote():
+		return "CREDIT NOTE"
+	default:
+		return ""
+	}
+}
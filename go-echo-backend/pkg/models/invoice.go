@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
 	"gorm.io/datatypes"
 )
 
@@ -19,17 +20,27 @@ type Invoice struct {
 	UserID string `json:"user_id"`
 
 	InvoiceNumber int                 `gorm:"primaryKey;autoIncrement" json:"invoice_number"`
+	SeriesID      string              `gorm:"size:100;index" json:"series_id,omitempty"`
+	SeriesNumber  int                 `json:"series_number,omitempty"`
+	DisplayNumber string              `gorm:"size:50;index:idx_invoice_display_number,unique,where:display_number <> ''" json:"display_number,omitempty"`
 	InvoiceType   enums.InvoiceType   `json:"invoice_type,omitempty"`
 	CreatedBy     string              `json:"created_by,omitempty"`
 	Document      *Attachment         `json:"document,omitempty"`
+	DocumentFence int64               `gorm:"default:0" json:"-"` // Fencing token of the lease that wrote the document
 	Metadata      InvoiceMetadata     `json:"metadata,omitempty"`
 	DueDate       int64               `json:"due_date,omitempty"`
 	IssuedDate    int64               `json:"issued_date,omitempty"`
 	Currency      enums.Currency      `json:"currency,omitempty"`
 	CountryCode   enums.CountryCode   `json:"country_code,omitempty"`
-	Status        enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid"`
+	Status        enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid partially_refunded refunded credited void"`
 	Note          string              `json:"note,omitempty"`
 
+	OriginalInvoiceNumber int          `gorm:"index" json:"original_invoice_number,omitempty"` // Invoice reversed by this credit note
+	RefundedAmount        *price.Price `gorm:"type:decimal(20,4);default:0.0" json:"refunded_amount,omitempty"`
+	VoidedAt              *int64       `json:"voided_at,omitempty"`
+	VoidedBy              string       `json:"voided_by,omitempty"`
+	VoidReason            string       `json:"void_reason,omitempty"`
+
 	Vendor    *InvoiceParty `json:"vendor,omitempty"`
 	Consignee *InvoiceParty `json:"consignee,omitempty"`
 	Shipper   *InvoiceParty `json:"shipper,omitempty"`
@@ -55,7 +66,7 @@ type CreateInvoiceParams struct {
 	IssuedDate  int64               `json:"issued_date,omitempty"`
 	Currency    string              `json:"currency,omitempty"`
 	CountryCode string              `json:"country_code,omitempty"`
-	Status      enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid"`
+	Status      enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid partially_refunded refunded credited void"`
 	Note        string              `json:"note,omitempty"`
 
 	Vendor    *InvoiceParty `json:"vendor,omitempty"`
@@ -81,7 +92,7 @@ type UpdateInvoiceParams struct {
 	IssuedDate  int64               `json:"issued_date,omitempty"`
 	Currency    string              `json:"currency,omitempty"`
 	CountryCode string              `json:"country_code,omitempty"`
-	Status      enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid"`
+	Status      enums.InvoiceStatus `json:"status" validate:"omitempty,oneof=paid unpaid partially_refunded refunded credited void"`
 	Note        string              `json:"note,omitempty"`
 	Tax         float64             `json:"tax,omitempty"`
 	Total       float64             `json:"total,omitempty"`
@@ -91,3 +102,22 @@ type UpdateInvoiceParams struct {
 	Shipper   *InvoiceParty `json:"shipper,omitempty"`
 	Items     InvoiceItems  `json:"items,omitempty"`
 }
+
+type CreateCreditNoteParams struct {
+	JwtClaimsInfo
+
+	InvoiceNumber int          `json:"invoice_number" param:"invoice_number" validate:"required"`
+	Amount        *price.Price `json:"amount"` // Empty to credit the whole remaining balance
+	Reason        string       `json:"reason" validate:"required"`
+
+	// Refund the payment of the original invoice, card payments are refunded through Stripe
+	Refund           bool   `json:"refund"`
+	TransactionRefID string `json:"transaction_ref_id"` // Bank transfer reference of a manual refund
+}
+
+type VoidInvoiceParams struct {
+	JwtClaimsInfo
+
+	InvoiceNumber int    `json:"invoice_number" param:"invoice_number" validate:"required"`
+	Reason        string `json:"reason" validate:"required"`
+}
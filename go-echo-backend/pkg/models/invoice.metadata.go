@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
 )
@@ -29,6 +30,7 @@ type InvoiceMetadata struct {
 	BulkPurchaseOrderID          string   `json:"bulk_purchase_order_id,omitempty"`
 	BulkPurchaseOrderIDs         []string `json:"bulk_purchase_order_ids,omitempty"`
 	BulkPurchaseOrderReferenceID string   `json:"bulk_purchase_order_reference_id,omitempty"`
+	PaymentMilestoneID           string   `json:"payment_milestone_id,omitempty"`
 
 	CheckoutSessionID string `json:"checkout_session_id,omitempty"`
 
@@ -36,6 +38,8 @@ type InvoiceMetadata struct {
 	BulkPurchaseOrderCommercialInvoiceAttachment *Attachment `json:"bulk_purchase_order_commercial_invoice_attachment,omitempty"`
 
 	BulkEstimatedProductionLeadTime *int64 `json:"bulk_estimated_production_lead_time,omitempty"`
+
+	VoidedTotalPrice *price.Price `json:"voided_total_price,omitempty"` // Total before the invoice was voided
 }
 
 // Value return json value, implement driver.Valuer interface
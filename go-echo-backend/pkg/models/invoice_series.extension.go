@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewInvoiceSeries default series of an invoice type, currency and year
+func NewInvoiceSeries(invoiceType enums.InvoiceType, currency enums.Currency, year int) *InvoiceSeries {
+	currency = currency.DefaultIfInvalid()
+	return &InvoiceSeries{
+		InvoiceType: invoiceType,
+		Currency:    currency,
+		Year:        year,
+		Prefix:      invoiceType.SeriesPrefix(),
+		Region:      currency.GetRegionCode(),
+		Padding:     5,
+	}
+}
+
+// Format formats the number the way it's printed, e.g. INV-VN-2026-00042
+func (s *InvoiceSeries) Format(number int) string {
+	var padding = s.Padding
+	if padding <= 0 {
+		padding = 5
+	}
+	return fmt.Sprintf("%s-%s-%d-%0*d", s.Prefix, s.Region, s.Year, padding, number)
+}
+
+// NextInvoiceSeriesNumberTx locks the series row and takes its next number.
+// It must run in the transaction that inserts the invoice, so a rollback gives the number back.
+// The database skips the default transaction, outside of one the lock would be released right away.
+func NextInvoiceSeriesNumberTx(tx *gorm.DB, invoiceType enums.InvoiceType, currency enums.Currency, year int) (*InvoiceSeries, int, error) {
+	if _, ok := tx.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return nil, 0, eris.Errorf("invoice series %s %s %d: the number must be taken in the transaction inserting the invoice", invoiceType, currency, year)
+	}
+
+	var series = NewInvoiceSeries(invoiceType, currency, year)
+	var err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(series).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(series, "invoice_type = ? AND currency = ? AND year = ?", series.InvoiceType, series.Currency, series.Year).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	series.LastNumber++
+	err = tx.Model(&InvoiceSeries{}).Where("id = ?", series.ID).UpdateColumn("LastNumber", series.LastNumber).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return series, series.LastNumber, nil
+}
+
+// BeforeCreate numbers the invoice in its series. Deleted invoices are soft deleted and keep their number,
+// so every number of a series stays accounted for.
+// It also converts the totals at the FX rate of the issue date unless the invoice already carries one.
+func (m *Invoice) BeforeCreate(tx *gorm.DB) (err error) {
+	if m == nil {
+		return
+	}
+
+	var issuedAt = time.Now().UTC()
+	if m.IssuedDate > 0 {
+		issuedAt = time.Unix(m.IssuedDate, 0).UTC()
+	}
+
+	var snapshot = m.FxSnapshot
+	if snapshot.IsEmpty() {
+		fx, err := NewFxSnapshotTx(tx, m.Currency, issuedAt.Unix())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if fx != nil {
+			snapshot = *fx
+		}
+	}
+	snapshot.ApplyTo(&m.Pricing, m.Pricing)
+
+	if m.DisplayNumber != "" {
+		return
+	}
+
+	series, number, err := NextInvoiceSeriesNumberTx(tx, m.InvoiceType, m.Currency, issuedAt.Year())
+	if err != nil {
+		return err
+	}
+
+	m.SeriesID = series.ID
+	m.SeriesNumber = number
+	m.DisplayNumber = series.Format(number)
+	return
+}
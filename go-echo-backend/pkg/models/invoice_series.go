@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+)
+
+// InvoiceSeries one gap-free numbering sequence per invoice type, currency and year
+type InvoiceSeries struct {
+	Model
+
+	InvoiceType enums.InvoiceType `gorm:"size:100;uniqueIndex:idx_invoice_series_key" json:"invoice_type"`
+	Currency    enums.Currency    `gorm:"size:10;uniqueIndex:idx_invoice_series_key" json:"currency"`
+	Year        int               `gorm:"uniqueIndex:idx_invoice_series_key" json:"year"`
+
+	Prefix     string `gorm:"size:20" json:"prefix"`
+	Region     string `gorm:"size:10" json:"region"`
+	Padding    int    `gorm:"default:5" json:"padding"`
+	LastNumber int    `gorm:"default:0" json:"last_number"` // Last issued number, the next invoice takes LastNumber + 1
+
+	NextDisplayNumber string `gorm:"-" json:"next_display_number,omitempty"`
+}
+
+type InvoiceSeriesList []*InvoiceSeries
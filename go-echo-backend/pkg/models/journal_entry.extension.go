@@ -0,0 +1,260 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+)
+
+func newJournalEntry(sourceType enums.JournalSourceType, sourceID string, entryDate int64, currency enums.Currency) *JournalEntry {
+	return &JournalEntry{
+		SourceType: sourceType,
+		SourceID:   sourceID,
+		EntryDate:  entryDate,
+		Currency:   currency.DefaultIfInvalid(),
+	}
+}
+
+// Debit adds a debit line, zero amounts are skipped
+func (e *JournalEntry) Debit(account enums.LedgerAccount, amount price.Price) *JournalEntry {
+	return e.addLine(account, amount, price.NewFromFloat(0))
+}
+
+// Credit adds a credit line, zero amounts are skipped
+func (e *JournalEntry) Credit(account enums.LedgerAccount, amount price.Price) *JournalEntry {
+	return e.addLine(account, price.NewFromFloat(0), amount)
+}
+
+func (e *JournalEntry) addLine(account enums.LedgerAccount, debit, credit price.Price) *JournalEntry {
+	// A negative amount is booked on the other side
+	if debit.LessThan(0) {
+		debit, credit = price.NewFromFloat(0), credit.Sub(debit)
+	}
+	if credit.LessThan(0) {
+		debit, credit = debit.Sub(credit), price.NewFromFloat(0)
+	}
+	if debit.Equal(0) && credit.Equal(0) {
+		return e
+	}
+
+	e.Lines = append(e.Lines, &JournalLine{
+		EntryDate:   e.EntryDate,
+		AccountCode: account,
+		Description: e.Description,
+		Currency:    e.Currency,
+		Debit:       debit.ToPtr(),
+		Credit:      credit.ToPtr(),
+	})
+	return e
+}
+
+// IsBalanced an entry needs two lines at least and debits equal to credits
+func (e *JournalEntry) IsBalanced() bool {
+	var totalDebit, totalCredit = e.Lines.Totals()
+	return len(e.Lines) >= 2 && totalDebit.Sub(totalCredit).Equal(0)
+}
+
+func (lines JournalLines) Totals() (debit price.Price, credit price.Price) {
+	for _, line := range lines {
+		debit = debit.AddPtr(line.Debit)
+		credit = credit.AddPtr(line.Credit)
+	}
+	return
+}
+
+// NewInvoiceJournalEntry books a paid invoice: receivable against revenue, shipping, charged fees and tax.
+// Revenue takes the remainder so deposit and milestone invoices stay balanced.
+func NewInvoiceJournalEntry(invoice *Invoice) *JournalEntry {
+	var entry = newJournalEntry(enums.JournalSourceTypeInvoice, strconv.Itoa(invoice.InvoiceNumber), invoice.IssuedDate, invoice.Currency)
+	entry.Reference = invoice.GetDisplayNumber()
+	entry.Description = fmt.Sprintf("%s %s", invoice.InvoiceType.DisplayName(), entry.Reference)
+	entry.UserID = invoice.UserID
+
+	var total = price.NewFromPtr(invoice.TotalPrice)
+	var shippingFee = price.NewFromPtr(invoice.ShippingFee)
+	var transactionFee = price.NewFromPtr(invoice.TransactionFee)
+	var tax = price.NewFromPtr(invoice.Tax)
+	var revenue = total.Sub(shippingFee).Sub(transactionFee).Sub(tax)
+
+	return entry.
+		Debit(enums.LedgerAccountAccountsReceivable, total).
+		Credit(enums.LedgerAccountSalesRevenue, revenue).
+		Credit(enums.LedgerAccountShippingRevenue, shippingFee).
+		Credit(enums.LedgerAccountPaymentFeeRevenue, transactionFee).
+		Credit(enums.LedgerAccountTaxPayable, tax)
+}
+
+// NewCreditNoteJournalEntry reverses the credited share of revenue and tax against the receivable
+func NewCreditNoteJournalEntry(creditNote *Invoice) *JournalEntry {
+	var entry = newJournalEntry(enums.JournalSourceTypeCreditNote, strconv.Itoa(creditNote.InvoiceNumber), creditNote.IssuedDate, creditNote.Currency)
+	entry.Reference = creditNote.GetDisplayNumber()
+	entry.Description = fmt.Sprintf("Credit note %s for invoice %d", entry.Reference, creditNote.OriginalInvoiceNumber)
+	entry.UserID = creditNote.UserID
+
+	var total = price.NewFromPtr(creditNote.TotalPrice)
+	var tax = price.NewFromPtr(creditNote.Tax)
+
+	return entry.
+		Debit(enums.LedgerAccountSalesReturns, total.Sub(tax)).
+		Debit(enums.LedgerAccountTaxPayable, tax).
+		Credit(enums.LedgerAccountAccountsReceivable, total)
+}
+
+// NewPaymentTransactionJournalEntries books a buyer payment (and its processor fee), a refund or a seller payout
+func NewPaymentTransactionJournalEntries(transaction *PaymentTransaction) JournalEntries {
+	var entryDate = transaction.CreatedAt
+	if transaction.MarkAsPaidAt != nil && *transaction.MarkAsPaidAt > 0 {
+		entryDate = *transaction.MarkAsPaidAt
+	}
+
+	var amount = price.NewFromPtr(transaction.PaidAmount)
+	var cashAccount = transaction.PaymentType.GetCashLedgerAccount()
+	var newEntry = func(sourceType enums.JournalSourceType, description string) *JournalEntry {
+		var entry = newJournalEntry(sourceType, transaction.ID, entryDate, transaction.Currency)
+		entry.Reference = transaction.ReferenceID
+		entry.Description = description
+		entry.UserID = transaction.UserID
+		return entry
+	}
+
+	var result JournalEntries
+	switch transaction.TransactionType {
+	case enums.TransactionTypeRefund:
+		result = append(result, newEntry(enums.JournalSourceTypeRefund, fmt.Sprintf("Refund %s", transaction.ReferenceID)).
+			Debit(enums.LedgerAccountAccountsReceivable, amount).
+			Credit(cashAccount, amount))
+
+	case enums.TransactionTypeDebit:
+		result = append(result, newEntry(enums.JournalSourceTypeSellerPayout, fmt.Sprintf("Seller payout %s", transaction.ReferenceID)).
+			Debit(enums.LedgerAccountCostOfGoodsSold, amount).
+			Credit(cashAccount, amount))
+
+	default:
+		result = append(result, newEntry(enums.JournalSourceTypePayment, fmt.Sprintf("Payment %s", transaction.ReferenceID)).
+			Debit(cashAccount, amount).
+			Credit(enums.LedgerAccountAccountsReceivable, amount))
+
+		if fee := price.NewFromPtr(transaction.Fee); fee.GreaterThan(0) {
+			result = append(result, newEntry(enums.JournalSourceTypeProcessingFee, fmt.Sprintf("Processing fee %s", transaction.ReferenceID)).
+				Debit(enums.LedgerAccountProcessingFees, fee).
+				Credit(cashAccount, fee))
+		}
+	}
+
+	return result
+}
+
+// NewBulkDeductionJournalEntries grosses revenue up by the deductions granted on the final payment,
+// so deductions show on their own contra revenue account
+func NewBulkDeductionJournalEntries(order *BulkPurchaseOrder) JournalEntries {
+	var entryDate int64
+	if order.FinalPaymentMarkAsPaidAt != nil {
+		entryDate = *order.FinalPaymentMarkAsPaidAt
+	}
+
+	var deductions = []struct {
+		sourceType enums.JournalSourceType
+		amount     *price.Price
+	}{
+		{enums.JournalSourceTypeSampleDeduction, order.SampleDeductionAmount},
+		{enums.JournalSourceTypeFinalPaymentDeduction, order.FinalPaymentDeductionAmount},
+	}
+
+	var result JournalEntries
+	for _, deduction := range deductions {
+		var sourceType, amount = deduction.sourceType, deduction.amount
+		if !price.NewFromPtr(amount).GreaterThan(0) {
+			continue
+		}
+
+		var entry = newJournalEntry(sourceType, order.ID, entryDate, order.Currency)
+		entry.Reference = order.ReferenceID
+		entry.Description = fmt.Sprintf("%s %s", sourceType.DisplayName(), order.ReferenceID)
+		entry.UserID = order.UserID
+		result = append(result, entry.
+			Debit(enums.LedgerAccountSalesDeductions, *amount).
+			Credit(enums.LedgerAccountSalesRevenue, *amount))
+	}
+
+	return result
+}
+
+func (entries JournalEntries) formatDate(unix int64, layout string) string {
+	return time.Unix(unix, 0).UTC().Format(layout)
+}
+
+// ToXeroCSV Xero manual journal import, positive amounts are debits and negative amounts credits
+func (entries JournalEntries) ToXeroCSV() ([]byte, error) {
+	var buffer bytes.Buffer
+	var writer = csv.NewWriter(&buffer)
+	var err = writer.Write([]string{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount", "TrackingName1", "TrackingOption1"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			var amount = price.NewFromPtr(line.Debit).SubPtr(line.Credit)
+			err = writer.Write([]string{
+				fmt.Sprintf("%s %s", entry.Reference, entry.Description),
+				entries.formatDate(entry.EntryDate, "02/01/2006"),
+				line.AccountCode.DisplayName(),
+				string(line.AccountCode),
+				"Tax Exempt",
+				amount.Decimal().StringFixed(2),
+				"Source",
+				entry.SourceType.DisplayName(),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// ToQuickBooksCSV QuickBooks Online journal entry import, lines of one entry share the journal number
+func (entries JournalEntries) ToQuickBooksCSV() ([]byte, error) {
+	var buffer bytes.Buffer
+	var writer = csv.NewWriter(&buffer)
+	var err = writer.Write([]string{"JournalNo", "JournalDate", "Currency", "Memo", "AccountName", "Debits", "Credits", "Description"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			err = writer.Write([]string{
+				entry.ID,
+				entries.formatDate(entry.EntryDate, "01/02/2006"),
+				string(line.Currency),
+				fmt.Sprintf("%s %s", entry.Reference, entry.Description),
+				fmt.Sprintf("%s %s", line.AccountCode, line.AccountCode.DisplayName()),
+				formatJournalAmount(line.Debit),
+				formatJournalAmount(line.Credit),
+				line.Description,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+func formatJournalAmount(amount *price.Price) string {
+	if !price.NewFromPtr(amount).GreaterThan(0) {
+		return ""
+	}
+
+	return amount.Decimal().StringFixed(2)
+}
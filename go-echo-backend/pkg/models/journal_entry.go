@@ -0,0 +1,56 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+)
+
+// JournalEntry balanced double-entry posting derived from one source record.
+// SourceType and SourceID are unique so re-posting a period never books a source twice.
+type JournalEntry struct {
+	Model
+
+	SourceType  enums.JournalSourceType `gorm:"size:50;uniqueIndex:idx_journal_entry_source" json:"source_type"`
+	SourceID    string                  `gorm:"size:100;uniqueIndex:idx_journal_entry_source" json:"source_id"`
+	EntryDate   int64                   `gorm:"index" json:"entry_date"`
+	Reference   string                  `gorm:"size:200" json:"reference,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Currency    enums.Currency          `gorm:"size:10" json:"currency"`
+	UserID      string                  `gorm:"size:100" json:"user_id,omitempty"`
+
+	Lines JournalLines `gorm:"-" json:"lines,omitempty"`
+}
+
+type JournalEntries []*JournalEntry
+
+type JournalLine struct {
+	Model
+
+	JournalEntryID string              `gorm:"size:100;index" json:"journal_entry_id"`
+	EntryDate      int64               `gorm:"index" json:"entry_date"`
+	AccountCode    enums.LedgerAccount `gorm:"size:20;index" json:"account_code"`
+	Description    string              `json:"description,omitempty"`
+	Currency       enums.Currency      `gorm:"size:10" json:"currency"`
+	Debit          *price.Price        `gorm:"type:decimal(20,4);default:0.0" json:"debit"`
+	Credit         *price.Price        `gorm:"type:decimal(20,4);default:0.0" json:"credit"`
+}
+
+type JournalLines []*JournalLine
+
+type TrialBalanceRow struct {
+	AccountCode enums.LedgerAccount `json:"account_code"`
+	AccountName string              `json:"account_name"`
+	Debit       *price.Price        `json:"debit"`
+	Credit      *price.Price        `json:"credit"`
+	Balance     *price.Price        `json:"balance"` // Debit - Credit
+}
+
+type TrialBalance struct {
+	DateFrom    int64              `json:"date_from"`
+	DateTo      int64              `json:"date_to"`
+	Currency    enums.Currency     `json:"currency"`
+	Rows        []*TrialBalanceRow `json:"rows"`
+	TotalDebit  *price.Price       `json:"total_debit"`
+	TotalCredit *price.Price       `json:"total_credit"`
+	IsBalanced  bool               `json:"is_balanced"`
+}
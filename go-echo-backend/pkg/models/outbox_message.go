@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+)
+
+// OutboxTask is built by the Outbox method of a consumer task type, it's all the outbox needs to re-create the asynq task later
+type OutboxTask interface {
+	TaskName() string
+	GetPayload() []byte
+}
+
+// OutboxDeduper lets a task payload share a dedupe key across writes, by default each outbox row is its own key.
+// The key is only unique among pending rows, the same task can be written again once the previous one is relayed
+type OutboxDeduper interface {
+	DedupeKey() string
+}
+
+// OutboxMessage a task waiting to be relayed to the queue.
+// Invoices, payment confirmations and HubSpot syncs go through the outbox, the in-app user and CMS notifications
+// dispatched by the controllers are still enqueued directly, a notification lost while redis is down is accepted
+type OutboxMessage struct {
+	Model
+
+	TaskName    string             `gorm:"size:200;not null;index" json:"task_name"`
+	Payload     json.RawMessage    `gorm:"type:jsonb" json:"payload,omitempty" swaggertype:"object"`
+	Queue       string             `gorm:"size:50" json:"queue,omitempty"`
+	DedupeKey   string             `gorm:"size:300" json:"dedupe_key"` // Unique while pending, see migration.outboxIndexes
+	ReferenceID string             `gorm:"size:200;index" json:"reference_id,omitempty"`
+	Status      enums.OutboxStatus `gorm:"size:50;default:'pending';index" json:"status"`
+
+	Attempts      int    `gorm:"default:0" json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+	ProcessAt     int64  `json:"process_at,omitempty"`
+	NextAttemptAt int64  `gorm:"index" json:"next_attempt_at,omitempty"`
+	DispatchedAt  *int64 `json:"dispatched_at,omitempty"`
+	TaskID        string `gorm:"size:300" json:"task_id,omitempty"`
+}
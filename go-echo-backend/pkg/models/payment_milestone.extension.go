@@ -0,0 +1,221 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/thaitanloi365/go-utils/values"
+)
+
+func (m *PaymentMilestone) IsPaid() bool {
+	return m.Status == enums.PaymentStatusPaid || m.MarkAsPaidAt != nil
+}
+
+func (m *PaymentMilestone) GetDisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Milestone.DisplayName()
+}
+
+func (m *PaymentMilestone) updateStatusFromTimestamps() {
+	switch {
+	case m.MarkAsPaidAt != nil:
+		m.Status = enums.PaymentStatusPaid
+	case m.MarkAsUnpaidAt != nil:
+		m.Status = enums.PaymentStatusUnpaid
+	case m.TransferedAt != nil:
+		m.Status = enums.PaymentStatusWaitingConfirm
+	default:
+		m.Status = enums.PaymentStatusPending
+	}
+}
+
+// LegacyPaymentMilestones maps the Deposit*, FirstPayment*, SecondPayment* and FinalPayment* columns to a schedule
+func (order *BulkPurchaseOrder) LegacyPaymentMilestones() (result PaymentMilestones) {
+	var isPositive = func(p *price.Price) bool {
+		return p != nil && p.GreaterThan(0)
+	}
+
+	if isPositive(order.DepositAmount) {
+		var m = &PaymentMilestone{
+			Milestone:                     enums.PaymentMilestoneDeposit,
+			DueTrigger:                    enums.PaymentMilestoneDueTriggerOnQuotation,
+			Amount:                        order.DepositAmount,
+			Total:                         order.DepositAmount,
+			PaidAmount:                    order.DepositPaidAmount,
+			TransactionFee:                order.DepositTransactionFee,
+			Note:                          order.DepositNote,
+			InvoiceNumber:                 order.DepositInvoiceNumber,
+			PaymentType:                   enums.PaymentTypeBankTransfer,
+			PaymentIntentID:               order.DepositPaymentIntentID,
+			ChargeID:                      order.DepositChargeID,
+			TxnID:                         order.DepositTxnID,
+			ReceiptURL:                    order.DepositReceiptURL,
+			PaymentLink:                   order.DepositPaymentLink,
+			PaymentLinkID:                 order.DepositPaymentLinkID,
+			TransactionRefID:              order.DepositTransactionRefID,
+			TransactionAttachment:         order.DepositTransactionAttachment,
+			PaymentTransactionReferenceID: order.DepositPaymentTransactionReferenceID,
+			TransferedAt:                  order.DepositTransferedAt,
+			MarkAsPaidAt:                  order.DepositMarkAsPaidAt,
+		}
+		if m.PaymentIntentID != "" {
+			m.PaymentType = enums.PaymentTypeCard
+		}
+		result = append(result, m)
+	}
+
+	if isPositive(order.FirstPaymentTotal) {
+		result = append(result, &PaymentMilestone{
+			Milestone:                     enums.PaymentMilestoneFirstPayment,
+			DueTrigger:                    enums.PaymentMilestoneDueTriggerBeforeProduction,
+			Percentage:                    order.FirstPaymentPercentage,
+			SubTotal:                      order.FirstPaymentSubTotal,
+			Tax:                           order.FirstPaymentTax,
+			TransactionFee:                order.FirstPaymentTransactionFee,
+			Total:                         order.FirstPaymentTotal,
+			InvoiceNumber:                 order.FirstPaymentInvoiceNumber,
+			PaymentType:                   order.FirstPaymentType,
+			PaymentIntentID:               order.FirstPaymentIntentID,
+			ChargeID:                      order.FirstPaymentChargeID,
+			TxnID:                         order.FirstPaymentTxnID,
+			ReceiptURL:                    order.FirstPaymentReceiptURL,
+			CheckoutSessionID:             order.FirstPaymentCheckoutSessionID,
+			PaymentLink:                   order.FirstPaymentLink,
+			PaymentLinkID:                 order.FirstPaymentLinkID,
+			TransactionRefID:              order.FirstPaymentTransactionRefID,
+			TransactionAttachment:         order.FirstPaymentTransactionAttachment,
+			PaymentTransactionReferenceID: order.FirstPaymentTransactionReferenceID,
+			TransferedAt:                  order.FirstPaymentTransferedAt,
+			ReceivedAt:                    order.FirstPaymentReceivedAt,
+			MarkAsPaidAt:                  order.FirstPaymentMarkAsPaidAt,
+			MarkAsUnpaidAt:                order.FirstPaymentMarkAsUnpaidAt,
+		})
+	}
+
+	if isPositive(order.SecondPaymentTotal) {
+		result = append(result, &PaymentMilestone{
+			Milestone:                     enums.PaymentMilestoneSecondPayment,
+			DueTrigger:                    enums.PaymentMilestoneDueTriggerAfterQc,
+			Percentage:                    values.Float64(order.SecondPaymentPercentage),
+			SubTotal:                      order.SecondPaymentSubTotal,
+			Tax:                           order.SecondPaymentTax,
+			TransactionFee:                order.SecondPaymentTransactionFee,
+			Total:                         order.SecondPaymentTotal,
+			InvoiceNumber:                 order.SecondPaymentInvoiceNumber,
+			PaymentType:                   order.SecondPaymentType,
+			PaymentIntentID:               order.SecondPaymentIntentID,
+			ChargeID:                      order.SecondPaymentChargeID,
+			TxnID:                         order.SecondPaymentTxnID,
+			ReceiptURL:                    order.SecondPaymentReceiptURL,
+			PaymentLink:                   order.SecondPaymentLink,
+			PaymentLinkID:                 order.SecondPaymentLinkID,
+			TransactionRefID:              order.SecondPaymentTransactionRefID,
+			TransactionAttachment:         order.SecondPaymentTransactionAttachment,
+			PaymentTransactionReferenceID: order.SecondPaymentTransactionReferenceID,
+			TransferedAt:                  order.SecondPaymentTransferedAt,
+			ReceivedAt:                    order.SecondPaymentReceivedAt,
+			MarkAsPaidAt:                  order.SecondPaymentMarkAsPaidAt,
+			MarkAsUnpaidAt:                order.SecondPaymentMarkAsUnpaidAt,
+		})
+	}
+
+	if isPositive(order.FinalPaymentTotal) {
+		var m = &PaymentMilestone{
+			Milestone:                     enums.PaymentMilestoneFinalPayment,
+			DueTrigger:                    enums.PaymentMilestoneDueTriggerBeforeDelivery,
+			SubTotal:                      order.FinalPaymentSubTotal,
+			Tax:                           order.FinalPaymentTax,
+			TransactionFee:                order.FinalPaymentTransactionFee,
+			Total:                         order.FinalPaymentTotal,
+			InvoiceNumber:                 order.FinalPaymentInvoiceNumber,
+			PaymentType:                   order.FinalPaymentType,
+			PaymentIntentID:               order.FinalPaymentIntentID,
+			ChargeID:                      order.FinalPaymentChargeID,
+			TxnID:                         order.FinalPaymentTxnID,
+			ReceiptURL:                    order.FinalPaymentReceiptURL,
+			CheckoutSessionID:             order.FinalPaymentCheckoutSessionID,
+			PaymentLink:                   order.FinalPaymentLink,
+			PaymentLinkID:                 order.FinalPaymentLinkID,
+			TransactionRefID:              order.FinalPaymentTransactionRefID,
+			TransactionAttachment:         order.FinalPaymentTransactionAttachment,
+			PaymentTransactionReferenceID: order.FinalPaymentTransactionReferenceID,
+			TransferedAt:                  order.FinalPaymentTransferedAt,
+			ReceivedAt:                    order.FinalPaymentReceivedAt,
+			MarkAsPaidAt:                  order.FinalPaymentMarkAsPaidAt,
+			MarkAsUnpaidAt:                order.FinalPaymentMarkAsUnpaidAt,
+		}
+		if order.FirstPaymentPercentage != nil {
+			m.Percentage = values.Float64(100 - *order.FirstPaymentPercentage - order.SecondPaymentPercentage)
+		}
+		result = append(result, m)
+	}
+
+	for index, m := range result {
+		m.BulkPurchaseOrderID = order.ID
+		m.Sequence = index + 1
+		m.Name = m.Milestone.DisplayName()
+		m.Currency = order.Currency
+		if m.IsPaid() && m.PaidAmount == nil {
+			m.PaidAmount = m.Total
+		}
+		m.updateStatusFromTimestamps()
+	}
+
+	return
+}
+
+// LegacyColumnUpdates mirrors the milestone back to the BulkPurchaseOrder columns of the same kind,
+// so screens and tasks that still read Deposit*, FirstPayment*, SecondPayment* and FinalPayment* keep working
+func (m *PaymentMilestone) LegacyColumnUpdates() map[string]interface{} {
+	var prefix, intentColumn, linkColumn, referenceColumn string
+	switch m.Milestone {
+	case enums.PaymentMilestoneDeposit:
+		prefix = "deposit_"
+		intentColumn = prefix + "payment_intent_id"
+		linkColumn = prefix + "payment_link_id"
+		referenceColumn = prefix + "payment_transaction_reference_id"
+	case enums.PaymentMilestoneFirstPayment, enums.PaymentMilestoneSecondPayment, enums.PaymentMilestoneFinalPayment:
+		prefix = string(m.Milestone) + "_"
+		intentColumn = prefix + "intent_id"
+		linkColumn = prefix + "link_id"
+		referenceColumn = prefix + "transaction_reference_id"
+	default:
+		return nil
+	}
+
+	var updates = map[string]interface{}{}
+	var setString = func(column, value string) {
+		if value != "" {
+			updates[column] = value
+		}
+	}
+	var setTime = func(column string, value *int64) {
+		if value != nil {
+			updates[column] = *value
+		}
+	}
+
+	if m.InvoiceNumber > 0 {
+		updates[prefix+"invoice_number"] = m.InvoiceNumber
+	}
+	if m.Milestone == enums.PaymentMilestoneDeposit {
+		if m.PaidAmount != nil {
+			updates["deposit_paid_amount"] = m.PaidAmount
+		}
+	} else {
+		setString(prefix+"type", string(m.PaymentType))
+	}
+
+	setString(intentColumn, m.PaymentIntentID)
+	setString(linkColumn, m.PaymentLinkID)
+	setString(referenceColumn, m.PaymentTransactionReferenceID)
+	setString(prefix+"charge_id", m.ChargeID)
+	setString(prefix+"txn_id", m.TxnID)
+	setString(prefix+"receipt_url", m.ReceiptURL)
+	setString(prefix+"transaction_ref_id", m.TransactionRefID)
+	setTime(prefix+"transfered_at", m.TransferedAt)
+	setTime(prefix+"mark_as_paid_at", m.MarkAsPaidAt)
+
+	return updates
+}
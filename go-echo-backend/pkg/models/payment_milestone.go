@@ -0,0 +1,106 @@
+package models
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+)
+
+type PaymentMilestones []*PaymentMilestone
+
+// PaymentMilestone one installment of a bulk purchase order payment schedule
+type PaymentMilestone struct {
+	Model
+
+	BulkPurchaseOrderID string                 `gorm:"size:100;uniqueIndex:idx_payment_milestone_sequence" json:"bulk_purchase_order_id"`
+	Sequence            int                    `gorm:"uniqueIndex:idx_payment_milestone_sequence" json:"sequence"`
+	Milestone           enums.PaymentMilestone `gorm:"size:50" json:"milestone,omitempty"` // Legacy kind, empty for custom milestones
+	Name                string                 `gorm:"size:200" json:"name,omitempty"`
+	Note                string                 `gorm:"size:2000" json:"note,omitempty"`
+
+	Currency       enums.Currency `gorm:"default:'USD'" json:"currency,omitempty"`
+	Percentage     *float64       `gorm:"type:decimal(20,4)" json:"percentage,omitempty"`
+	Amount         *price.Price   `gorm:"type:decimal(20,4)" json:"amount,omitempty"` // Fixed amount, takes precedence over percentage
+	SubTotal       *price.Price   `gorm:"type:decimal(20,4);default:0.0" json:"sub_total,omitempty"`
+	Tax            *price.Price   `gorm:"type:decimal(20,4);default:0.0" json:"tax,omitempty"`
+	TransactionFee *price.Price   `gorm:"type:decimal(20,4);default:0.0" json:"transaction_fee,omitempty"`
+	Total          *price.Price   `gorm:"type:decimal(20,4);default:0.0" json:"total,omitempty"`
+	PaidAmount     *price.Price   `gorm:"type:decimal(20,4);default:0.0" json:"paid_amount,omitempty"`
+
+	DueTrigger enums.PaymentMilestoneDueTrigger `gorm:"size:50" json:"due_trigger,omitempty"`
+	DueDays    int                              `gorm:"default:0" json:"due_days,omitempty"` // Net days after the trigger
+	DueAt      *int64                           `json:"due_at,omitempty"`
+
+	Status enums.PaymentStatus `gorm:"size:50;default:'pending'" json:"status,omitempty"`
+
+	InvoiceNumber int      `json:"invoice_number,omitempty"`
+	Invoice       *Invoice `gorm:"-" json:"invoice,omitempty"`
+
+	PaymentType                   enums.PaymentType `gorm:"size:50;default:'bank_transfer'" json:"payment_type,omitempty"`
+	PaymentIntentID               string            `gorm:"size:100;index" json:"payment_intent_id,omitempty"`
+	ChargeID                      string            `gorm:"size:100" json:"charge_id,omitempty"`
+	TxnID                         string            `gorm:"size:100" json:"txn_id,omitempty"`
+	ReceiptURL                    string            `gorm:"size:2000" json:"receipt_url,omitempty"`
+	CheckoutSessionID             string            `gorm:"size:100" json:"checkout_session_id,omitempty"`
+	PaymentLink                   string            `gorm:"size:2000" json:"payment_link,omitempty"`
+	PaymentLinkID                 string            `gorm:"size:100;index" json:"payment_link_id,omitempty"`
+	TransactionRefID              string            `gorm:"size:100" json:"transaction_ref_id,omitempty"`
+	TransactionAttachment         *Attachment       `json:"transaction_attachment,omitempty"`
+	PaymentTransactionReferenceID string            `gorm:"size:100" json:"payment_transaction_reference_id,omitempty"`
+
+	TransferedAt   *int64 `json:"transfered_at,omitempty"`
+	ReceivedAt     *int64 `json:"received_at,omitempty"`
+	MarkAsPaidAt   *int64 `json:"mark_as_paid_at,omitempty"`
+	MarkAsUnpaidAt *int64 `json:"mark_as_unpaid_at,omitempty"`
+}
+
+type PaymentMilestoneForm struct {
+	Name       string                           `json:"name" validate:"required"`
+	Milestone  enums.PaymentMilestone           `json:"milestone"`
+	Note       string                           `json:"note"`
+	Percentage *float64                         `json:"percentage" validate:"required_without=Amount"`
+	Amount     *price.Price                     `json:"amount" validate:"required_without=Percentage"`
+	DueTrigger enums.PaymentMilestoneDueTrigger `json:"due_trigger" validate:"required"`
+	DueDays    int                              `json:"due_days" validate:"min=0"`
+	DueAt      *int64                           `json:"due_at" validate:"required_if=DueTrigger fixed_date"`
+}
+
+type UpdatePaymentMilestoneScheduleParams struct {
+	JwtClaimsInfo
+
+	BulkPurchaseOrderID string                  `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	Milestones          []*PaymentMilestoneForm `json:"milestones" validate:"required,min=1,dive"`
+}
+
+type PaymentMilestoneCheckoutParams struct {
+	JwtClaimsInfo
+
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	PaymentMilestoneID  string `json:"payment_milestone_id" param:"payment_milestone_id" validate:"required"`
+
+	PaymentType     enums.PaymentType `json:"payment_type" validate:"oneof=bank_transfer card"`
+	PaymentMethodID string            `json:"payment_method_id" validate:"required_if=PaymentType card"`
+	Note            string            `json:"note"`
+
+	TransactionRefID      string      `json:"transaction_ref_id" validate:"required_if=PaymentType bank_transfer"`
+	TransactionAttachment *Attachment `json:"transaction_attachment" validate:"required_if=PaymentType bank_transfer"`
+}
+
+type PaymentMilestoneMarkAsPaidParams struct {
+	JwtClaimsInfo
+
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	PaymentMilestoneID  string `json:"payment_milestone_id" param:"payment_milestone_id" validate:"required"`
+
+	// Gateway fields, empty for bank transfers confirmed by admin
+	PaymentType       enums.PaymentType `json:"-"`
+	PaymentIntentID   string            `json:"-"`
+	ChargeID          string            `json:"-"`
+	TxnID             string            `json:"-"`
+	ReceiptURL        string            `json:"-"`
+	PaymentLinkID     string            `json:"-"`
+	CheckoutSessionID string            `json:"-"`
+	Net               *price.Price      `json:"-"`
+	Fee               *price.Price      `json:"-"`
+
+	OutboxTasks []OutboxTask `json:"-"`
+}
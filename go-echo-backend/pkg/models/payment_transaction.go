@@ -19,6 +19,7 @@ type PaymentTransaction struct {
 
 	BulkPurchaseOrder   *BulkPurchaseOrder `gorm:"-" json:"bulk_purchase_order,omitempty"`
 	BulkPurchaseOrderID string             `json:"bulk_purchase_order_id,omitempty"`
+	PaymentMilestoneID  string             `gorm:"size:100;index" json:"payment_milestone_id,omitempty"`
 
 	CheckoutSessionID    string               `json:"checkout_session_id,omitempty"`
 	PurchaseOrderIDs     pq.StringArray       `gorm:"type:varchar(100)[]" json:"purchase_order_ids,omitempty"`
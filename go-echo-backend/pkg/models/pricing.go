@@ -12,6 +12,10 @@ type Pricing struct {
 	Tax                    *price.Price `gorm:"type:decimal(20,4);default:0.0" json:"tax,omitempty"`
 	TotalPrice             *price.Price `gorm:"type:decimal(20,4);default:0.0" json:"total_price,omitempty"`
 	TaxPercentage          *float64     `gorm:"type:decimal(20,4);default:0.0" json:"tax_percentage,omitempty"`
+
+	FxSnapshot
+	BaseSubTotal   *price.Price `gorm:"type:decimal(20,4)" json:"base_sub_total,omitempty"`   // SubTotal in FxBaseCurrency at FxRate
+	BaseTotalPrice *price.Price `gorm:"type:decimal(20,4)" json:"base_total_price,omitempty"` // TotalPrice in FxBaseCurrency at FxRate
 }
 
 type SellerPricing struct {
@@ -16,7 +16,7 @@ type Product struct {
 	Description      string            `gorm:"size:5000" json:"description,omitempty"`
 	Vi               *ProductContent   `json:"vi,omitempty"`
 	QRCode           string            `gorm:"size:200" json:"qr_code,omitempty"`
-	CategoryID       string            `gorm:"size:100" json:"category_id,omitempty"`
+	CategoryID       string            `gorm:"size:100;index:idx_products_category_id" json:"category_id,omitempty"`
 	Category         *Category         `gorm:"-"  json:"category"`
 	Sku              string            `gorm:"size:100" json:"sku,omitempty"`
 	Price            price.Price       `gorm:"type:decimal(20,4);default:0.0" json:"price"`
@@ -42,7 +42,7 @@ type Product struct {
 	MinOrder  int               `gorm:"default:0" json:"min_order,omitempty"`
 
 	Attachments *Attachments   `json:"attachments,omitempty"`
-	FabricIDs   pq.StringArray `gorm:"type:varchar(200)[]" json:"fabric_ids,omitempty"`
+	FabricIDs   pq.StringArray `gorm:"type:varchar(200)[];index:idx_products_fabric_ids,type:gin" json:"fabric_ids,omitempty"`
 
 	SourceProductID string       `gorm:"default:null;unique;size:100" json:"source_product_id"`
 	Source          enums.Source `gorm:"default:'inflow'" json:"source"`
@@ -14,3 +14,17 @@ type ProductFilterOption struct {
 	Name  string      `json:"name"`
 	Value interface{} `json:"value"`
 }
+
+// ProductFacetOption option of a facet, the count is the products matching the option and the filters of the other facets
+type ProductFacetOption struct {
+	Name     string      `json:"name"`
+	Value    interface{} `json:"value"`
+	Count    int         `json:"count"`
+	Selected bool        `json:"selected"`
+}
+
+type ProductFacetCount struct {
+	Facet string `json:"facet"`
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
@@ -218,7 +218,7 @@ func (record PurchaseOrder) GenerateRawMaterialRefID(items *PoRawMaterialMetas)
 
 func (records PurchaseOrders) ToExcel() ([]byte, error) {
 	var data = [][]interface{}{
-		{"Reference ID", "Inquiry ID", "Buyer", "Product", "Tracking Status", "Assignee", "Sample Room", "Posted Date"},
+		{"Reference ID", "Inquiry ID", "Buyer", "Product", "Tracking Status", "Total Price", "FX Rate", "Total Price (USD)", "Assignee", "Sample Room", "Posted Date"},
 	}
 	for _, record := range records {
 		data = append(data, []interface{}{
@@ -242,6 +242,14 @@ func (records PurchaseOrders) ToExcel() ([]byte, error) {
 				return ""
 			}(),
 			record.TrackingStatus.DisplayName(),
+			func() string {
+				if record.TotalPrice != nil {
+					return record.TotalPrice.FormatMoney(record.Currency)
+				}
+				return ""
+			}(),
+			record.FxSnapshot.FormatRate(),
+			record.FxSnapshot.FormatBasePrice(record.BaseTotalPrice),
 			func() interface{} {
 				if len(record.Assignees) > 0 {
 					var names = lo.Map(record.Assignees, func(item *User, index int) string {
@@ -0,0 +1,27 @@
+package models
+
+import "github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+
+// SearchHit a ranked record, the snippet wraps the matched words in <mark>
+type SearchHit struct {
+	ID          string  `json:"id"`
+	ReferenceID string  `json:"reference_id,omitempty"`
+	Title       string  `json:"title,omitempty"`
+	Subtitle    string  `json:"subtitle,omitempty"`
+	Status      string  `json:"status,omitempty"`
+	Snippet     string  `json:"snippet,omitempty"`
+	Rank        float64 `json:"rank"`
+	CreatedAt   int64   `json:"created_at,omitempty"`
+}
+
+type SearchGroupResult struct {
+	Group       enums.SearchGroup `json:"group"`
+	DisplayName string            `json:"display_name"`
+	Hits        []*SearchHit      `json:"hits"`
+	HasMore     bool              `json:"has_more"`
+}
+
+type SearchResponse struct {
+	Keyword string               `json:"keyword"`
+	Groups  []*SearchGroupResult `json:"groups"`
+}
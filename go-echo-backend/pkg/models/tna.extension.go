@@ -1,6 +1,15 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+)
 
 func (tna *TNA) GetLink() string {
 	if tna.Inquiry != nil && tna.Inquiry.ID != "" {
@@ -67,3 +76,179 @@ func (tna *TNA) GetCustomerIOMetadata(extras map[string]interface{}) map[string]
 
 	return result
 }
+
+func (tna *TNA) GetDuration() int64 {
+	if tna.DateTo > tna.DateFrom {
+		return tna.DateTo - tna.DateFrom
+	}
+	return 0
+}
+
+// SortTopologically orders tasks so every task comes after its dependencies, ties are ordered by start date.
+// Dependencies outside of the tasks and cycles are rejected.
+func (tnas TNAs) SortTopologically() (TNAs, error) {
+	var mapTNAs = make(map[string]*TNA, len(tnas))
+	for _, tna := range tnas {
+		mapTNAs[tna.ID] = tna
+	}
+
+	var inDegree = make(map[string]int, len(tnas))
+	var successors = make(map[string][]*TNA, len(tnas))
+	for _, tna := range tnas {
+		for _, dependencyID := range tna.Dependencies {
+			if _, ok := mapTNAs[dependencyID]; !ok {
+				return nil, eris.Wrapf(errs.ErrTNADependencyInvalid, "%s depends on %s", tna.ID, dependencyID)
+			}
+			inDegree[tna.ID]++
+			successors[dependencyID] = append(successors[dependencyID], tna)
+		}
+	}
+
+	var less = func(a, b *TNA) bool {
+		if a.DateFrom != b.DateFrom {
+			return a.DateFrom < b.DateFrom
+		}
+		return a.ID < b.ID
+	}
+
+	var ready TNAs
+	for _, tna := range tnas {
+		if inDegree[tna.ID] == 0 {
+			ready = append(ready, tna)
+		}
+	}
+
+	var result = make(TNAs, 0, len(tnas))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		var tna = ready[0]
+		ready = ready[1:]
+		result = append(result, tna)
+
+		for _, successor := range successors[tna.ID] {
+			inDegree[successor.ID]--
+			if inDegree[successor.ID] == 0 {
+				ready = append(ready, successor)
+			}
+		}
+	}
+
+	if len(result) != len(tnas) {
+		var cycle []string
+		for _, tna := range tnas {
+			if inDegree[tna.ID] > 0 {
+				cycle = append(cycle, tna.ID)
+			}
+		}
+		return nil, eris.Wrapf(errs.ErrTNADependencyCycle, "tasks %s", strings.Join(cycle, ", "))
+	}
+
+	return result, nil
+}
+
+// NewTNAPlan schedules the tasks with the critical path method.
+// A task starting before one of its dependencies finishes is pushed later together with its end date,
+// tasks are never pulled earlier so manually planned gaps are kept. The shifted tasks are listed in ShiftedTNAIDs.
+func NewTNAPlan(referenceID string, tnas TNAs, requestedDeliveryAt *int64) (*TNAPlan, error) {
+	sorted, err := tnas.SortTopologically()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan = TNAPlan{
+		ReferenceID:         referenceID,
+		Items:               sorted,
+		CriticalPath:        []string{},
+		RequestedDeliveryAt: requestedDeliveryAt,
+	}
+
+	var mapTNAs = make(map[string]*TNA, len(sorted))
+	var successors = make(map[string]TNAs, len(sorted))
+	for _, tna := range sorted {
+		mapTNAs[tna.ID] = tna
+		for _, dependencyID := range tna.Dependencies {
+			successors[dependencyID] = append(successors[dependencyID], tna)
+		}
+	}
+
+	// Forward pass
+	for _, tna := range sorted {
+		var duration = tna.GetDuration()
+		tna.EarliestStart = tna.DateFrom
+		for _, dependencyID := range tna.Dependencies {
+			if finish := mapTNAs[dependencyID].EarliestFinish; finish > tna.EarliestStart {
+				tna.EarliestStart = finish
+			}
+		}
+		tna.EarliestFinish = tna.EarliestStart + duration
+
+		if tna.EarliestStart > tna.DateFrom {
+			tna.DateFrom = tna.EarliestStart
+			tna.DateTo = tna.EarliestFinish
+			plan.ShiftedTNAIDs = append(plan.ShiftedTNAIDs, tna.ID)
+		}
+
+		if tna.EarliestFinish > plan.ProjectedFinishAt {
+			plan.ProjectedFinishAt = tna.EarliestFinish
+		}
+	}
+
+	// Backward pass
+	for index := len(sorted) - 1; index >= 0; index-- {
+		var tna = sorted[index]
+		tna.LatestFinish = plan.ProjectedFinishAt
+		for _, successor := range successors[tna.ID] {
+			if successor.LatestStart < tna.LatestFinish {
+				tna.LatestFinish = successor.LatestStart
+			}
+		}
+		tna.LatestStart = tna.LatestFinish - tna.GetDuration()
+		tna.TotalFloat = tna.LatestStart - tna.EarliestStart
+		tna.IsCritical = tna.TotalFloat <= 0
+		if tna.IsCritical {
+			plan.CriticalPath = append(plan.CriticalPath, tna.ID)
+		}
+	}
+	for i, j := 0, len(plan.CriticalPath)-1; i < j; i, j = i+1, j-1 {
+		plan.CriticalPath[i], plan.CriticalPath[j] = plan.CriticalPath[j], plan.CriticalPath[i]
+	}
+
+	if plan.IsSlipped() {
+		plan.SlippageDays = int(math.Ceil(float64(plan.ProjectedFinishAt-*plan.RequestedDeliveryAt) / 86400))
+	}
+
+	return &plan, nil
+}
+
+// IsSlipped the projected ex-factory date is past the buyer's requested date
+func (plan *TNAPlan) IsSlipped() bool {
+	return plan != nil && plan.RequestedDeliveryAt != nil && *plan.RequestedDeliveryAt > 0 && plan.ProjectedFinishAt > *plan.RequestedDeliveryAt
+}
+
+// GetAssigneeIDs the task assignees and the order PICs
+func (plan *TNAPlan) GetAssigneeIDs() []string {
+	var result = append([]string{}, plan.PicIDs...)
+	for _, tna := range plan.Items {
+		result = append(result, tna.AssigneeIDs...)
+	}
+	return lo.Uniq(lo.Compact(result))
+}
+
+func (plan *TNAPlan) GetCustomerIOMetadata(extras map[string]interface{}) map[string]interface{} {
+	var result = map[string]interface{}{
+		"reference_id":        plan.ReferenceID,
+		"projected_finish_at": plan.ProjectedFinishAt,
+		"slippage_days":       plan.SlippageDays,
+		"critical_path":       plan.CriticalPath,
+	}
+
+	if plan.RequestedDeliveryAt != nil {
+		result["requested_delivery_at"] = *plan.RequestedDeliveryAt
+	}
+
+	for k, v := range extras {
+		result[k] = v
+	}
+
+	return result
+}
@@ -22,4 +22,31 @@ type TNA struct { // Time And Action
 	Inquiry           *Inquiry           `gorm:"-" json:"inquiry,omitempty"`
 	PurchaseOrder     *PurchaseOrder     `gorm:"-" json:"purchase_order,omitempty"`
 	BulkPurchaseOrder *BulkPurchaseOrder `gorm:"-" json:"bulk_purchase_order,omitempty"`
+
+	// Computed by the plan schedule, Dependencies must finish before the task starts
+	EarliestStart  int64 `gorm:"-" json:"earliest_start,omitempty"`
+	EarliestFinish int64 `gorm:"-" json:"earliest_finish,omitempty"`
+	LatestStart    int64 `gorm:"-" json:"latest_start,omitempty"`
+	LatestFinish   int64 `gorm:"-" json:"latest_finish,omitempty"`
+	TotalFloat     int64 `gorm:"-" json:"total_float"` // Seconds the task can slip without moving the plan finish
+	IsCritical     bool  `gorm:"-" json:"is_critical"`
+
+	Plan *TNAPlan `gorm:"-" json:"plan,omitempty"`
+}
+
+type TNAs []*TNA
+
+// TNAPlan all Time-and-Action tasks of one inquiry, PO or bulk PO scheduled as a DAG
+type TNAPlan struct {
+	ReferenceID  string   `json:"reference_id"`
+	Items        TNAs     `json:"items"`
+	CriticalPath []string `json:"critical_path"`
+
+	ProjectedFinishAt   int64    `json:"projected_finish_at"` // Projected ex-factory date
+	RequestedDeliveryAt *int64   `json:"requested_delivery_at,omitempty"`
+	SlippageDays        int      `json:"slippage_days"`
+	PicIDs              []string `json:"pic_ids,omitempty"`
+
+	ShiftedTNAIDs  []string `json:"shifted_tna_ids,omitempty"`
+	NotifySlippage bool     `json:"-"`
 }
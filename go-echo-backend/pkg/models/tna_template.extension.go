@@ -0,0 +1,96 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/rotisserie/eris"
+	"github.com/rs/xid"
+	"github.com/samber/lo"
+)
+
+const tnaTemplateDay = int64(86400)
+
+// NewTNAs backward-schedules the template so every task finishes OffsetDays before anchorAt at the latest
+// and before the tasks depending on it start.
+func (template *TNATemplate) NewTNAs(referenceID string, anchorAt int64) (TNAs, error) {
+	if len(template.Items) == 0 {
+		return nil, eris.Wrap(errs.ErrTNATemplateInvalid, "template has no items")
+	}
+
+	var tnas = make(TNAs, 0, len(template.Items))
+	var mapItems = make(map[string]*TNATemplateItem, len(template.Items))
+	for _, item := range template.Items {
+		var key = strings.TrimSpace(item.Key)
+		if key == "" {
+			return nil, eris.Wrap(errs.ErrTNATemplateInvalid, "item key is required")
+		}
+		if _, ok := mapItems[key]; ok {
+			return nil, eris.Wrapf(errs.ErrTNATemplateInvalid, "duplicated item key %s", key)
+		}
+		if item.DurationDays < 0 || item.OffsetDays < 0 {
+			return nil, eris.Wrapf(errs.ErrTNATemplateInvalid, "item %s has a negative offset or duration", key)
+		}
+		mapItems[key] = item
+
+		tnas = append(tnas, &TNA{
+			Model:        Model{ID: key},
+			ReferenceID:  referenceID,
+			Title:        item.Title,
+			SubTitle:     item.SubTitle,
+			Comment:      item.Comment,
+			OrderType:    template.OrderType,
+			AssigneeIDs:  item.AssigneeIDs,
+			Dependencies: lo.Map(item.Dependencies, func(dependency string, _ int) string { return strings.TrimSpace(dependency) }),
+		})
+	}
+
+	sorted, err := tnas.SortTopologically()
+	if err != nil {
+		return nil, eris.Wrap(errs.ErrTNATemplateInvalid, err.Error())
+	}
+
+	var successors = make(map[string]TNAs, len(sorted))
+	for _, tna := range sorted {
+		for _, dependency := range tna.Dependencies {
+			successors[dependency] = append(successors[dependency], tna)
+		}
+	}
+
+	// Backward pass, successors are scheduled first
+	for index := len(sorted) - 1; index >= 0; index-- {
+		var tna = sorted[index]
+		var item = mapItems[tna.ID]
+		tna.DateTo = anchorAt - int64(item.OffsetDays)*tnaTemplateDay
+		for _, successor := range successors[tna.ID] {
+			if successor.DateFrom < tna.DateTo {
+				tna.DateTo = successor.DateFrom
+			}
+		}
+		tna.DateFrom = tna.DateTo - int64(item.DurationDays)*tnaTemplateDay
+	}
+
+	var ids = make(map[string]string, len(tnas))
+	for _, tna := range tnas {
+		ids[tna.ID] = xid.New().String()
+	}
+	for _, tna := range tnas {
+		tna.ID = ids[tna.ID]
+		for index, dependency := range tna.Dependencies {
+			tna.Dependencies[index] = ids[dependency]
+		}
+	}
+
+	return tnas, nil
+}
+
+// GetSpanDays days between the first task start and the anchor date
+func (template *TNATemplate) GetSpanDays() (int, error) {
+	tnas, err := template.NewTNAs("", 0)
+	if err != nil {
+		return 0, err
+	}
+
+	var start = lo.MinBy(tnas, func(a, b *TNA) bool { return a.DateFrom < b.DateFrom })
+	return int(-start.DateFrom / tnaTemplateDay), nil
+}
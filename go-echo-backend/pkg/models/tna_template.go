@@ -0,0 +1,85 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TNATemplate reusable Time-and-Action plan, e.g. "Knit tee bulk, 45 days"
+type TNATemplate struct {
+	Model
+
+	Name            string            `json:"name"`
+	OrderType       enums.InquiryType `gorm:"size:50;index" json:"order_type"`
+	ProductClass    string            `gorm:"size:200;index" json:"product_class,omitempty"` // Empty matches any product class
+	Items           TNATemplateItems  `json:"items"`
+	CreatedByUserID string            `gorm:"size:100" json:"created_by_user_id,omitempty"`
+}
+
+type TNATemplates []*TNATemplate
+
+// TNATemplateItem one task of a template, positioned relative to the anchor (delivery) date
+type TNATemplateItem struct {
+	Key          string         `json:"key" validate:"required"` // Referenced by Dependencies of other items
+	Title        string         `json:"title" validate:"required"`
+	SubTitle     string         `json:"sub_title,omitempty"`
+	Comment      string         `json:"comment,omitempty"`
+	OffsetDays   int            `json:"offset_days"` // Days before the anchor date the task must finish at the latest
+	DurationDays int            `json:"duration_days"`
+	Dependencies []string       `json:"dependencies,omitempty"`
+	AssigneeIDs  pq.StringArray `json:"assignee_ids,omitempty"`
+}
+
+type TNATemplateItems []*TNATemplateItem
+
+// Value return json value, implement driver.Valuer interface
+func (m TNATemplateItems) Value() (driver.Value, error) {
+	ba, err := json.Marshal([]*TNATemplateItem(m))
+	return string(ba), err
+}
+
+// Scan scan value into jsonb, implements sql.Scanner interface
+func (m *TNATemplateItems) Scan(val interface{}) error {
+	if val == nil {
+		*m = *new(TNATemplateItems)
+		return nil
+	}
+	var ba []byte
+	switch v := val.(type) {
+	case []byte:
+		ba = v
+	case string:
+		ba = []byte(v)
+	default:
+		return errors.New(fmt.Sprint("Failed to unmarshal jsonB value:", val))
+	}
+	var t []*TNATemplateItem
+	err := json.Unmarshal(ba, &t)
+	*m = TNATemplateItems(t)
+	return err
+}
+
+// GormDataType gorm common data type
+func (m TNATemplateItems) GormDataType() string {
+	return "TNATemplateItems"
+}
+
+// GormDBDataType gorm db data type
+func (TNATemplateItems) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return "json"
+	case "mysql":
+		return "json"
+	case "postgres":
+		return "jsonB"
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+)
+
+// Topic a stream of domain events websocket clients can subscribe to, formatted as <kind>:<id>
+type Topic struct {
+	Kind enums.TopicKind
+	ID   string
+}
+
+func NewTopic(kind enums.TopicKind, id string) Topic {
+	return Topic{Kind: kind, ID: id}
+}
+
+func (t Topic) String() string {
+	return fmt.Sprintf("%s:%s", t.Kind, t.ID)
+}
+
+// ParseTopic parses a topic sent by a client, the notifications and inbox topics without ID are the ones of the user
+func ParseTopic(value string, userID string) (Topic, error) {
+	var kind, id, _ = strings.Cut(strings.TrimSpace(value), ":")
+	var topic = Topic{Kind: enums.TopicKind(kind), ID: id}
+
+	switch topic.Kind {
+	case enums.TopicKindNotifications, enums.TopicKindInbox:
+		if topic.ID == "" {
+			topic.ID = userID
+		}
+	case enums.TopicKindInquiry, enums.TopicKindPurchaseOrder, enums.TopicKindBulkPurchaseOrder:
+	default:
+		return topic, eris.Wrapf(errs.ErrTopicInvalid, "topic=%s", value)
+	}
+
+	if topic.ID == "" {
+		return topic, eris.Wrapf(errs.ErrTopicInvalid, "topic=%s", value)
+	}
+
+	return topic, nil
+}
+
+// TopicEvent domain event pushed to the clients subscribed to the topic.
+// It is also the outbox task publishing it, so repositories can emit events inside their transaction.
+type TopicEvent struct {
+	Topic string               `json:"topic" validate:"required"`
+	Event enums.TopicEventType `json:"event" validate:"required"`
+	Data  json.RawMessage      `json:"data,omitempty" swaggertype:"object"`
+}
+
+func NewTopicEvent(topic Topic, event enums.TopicEventType, data interface{}) TopicEvent {
+	raw, _ := json.Marshal(data)
+
+	return TopicEvent{
+		Topic: topic.String(),
+		Event: event,
+		Data:  raw,
+	}
+}
+
+func (e TopicEvent) GetPayload() []byte {
+	data, _ := json.Marshal(&e)
+
+	return data
+}
+
+// TaskName task name
+func (e TopicEvent) TaskName() string {
+	return "publish_topic_event"
+}
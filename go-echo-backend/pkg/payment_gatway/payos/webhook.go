@@ -0,0 +1,65 @@
+package payos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+)
+
+type WebhookRequest struct {
+	Code      string          `json:"code"`
+	Desc      string          `json:"desc"`
+	Success   bool            `json:"success"`
+	Data      json.RawMessage `json:"data"`
+	Signature string          `json:"signature"`
+}
+
+type WebhookData struct {
+	OrderCode           int    `json:"orderCode"`
+	Amount              int    `json:"amount"`
+	Description         string `json:"description"`
+	AccountNumber       string `json:"accountNumber"`
+	Reference           string `json:"reference"`
+	TransactionDateTime string `json:"transactionDateTime"`
+	Currency            string `json:"currency"`
+	PaymentLinkID       string `json:"paymentLinkId"`
+	Code                string `json:"code"`
+	Desc                string `json:"desc"`
+}
+
+// VerifyWebhookData checks the signature of the webhook data, the keys are signed in alphabet order
+func (c *Client) VerifyWebhookData(req *WebhookRequest) (*WebhookData, error) {
+	var obj map[string]interface{}
+	var decoder = json.NewDecoder(bytes.NewReader(req.Data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, eris.Wrap(err, "Invalid webhook data")
+	}
+
+	var keys = lo.Keys(obj)
+	sort.Strings(keys)
+
+	var params = lo.Map(keys, func(key string, index int) string {
+		var value = obj[key]
+		if value == nil {
+			value = ""
+		}
+		return fmt.Sprintf("%s=%v", key, value)
+	})
+
+	if c.generateHMAC(strings.Join(params, "&")) != req.Signature {
+		return nil, eris.New("Invalid webhook signature")
+	}
+
+	var data WebhookData
+	if err := json.Unmarshal(req.Data, &data); err != nil {
+		return nil, eris.Wrap(err, "Invalid webhook data")
+	}
+
+	return &data, nil
+}
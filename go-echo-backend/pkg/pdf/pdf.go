@@ -1,6 +1,7 @@
 package pdf
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,6 +22,9 @@ type GetPDFParams struct {
 	DisableLocker     bool `json:"disable_locker"`
 	PrintBackground   bool `json:"print_background"`
 	PreferCssPageSize bool `json:"prefer_css_page_size"`
+
+	// Context of the caller's lease when the caller holds the lock and disables the locker
+	Context context.Context `json:"-"`
 }
 
 type Client struct {
@@ -29,19 +33,35 @@ type Client struct {
 }
 
 func New(cfg *config.Configuration) *Client {
+	var l = locker.GetInstance()
+	if l == nil {
+		l = locker.New(cfg)
+	}
+
 	return &Client{
-		locker: locker.New(cfg),
+		locker: l,
 		config: cfg,
 	}
 }
 
+// GetPDF renders the url, the rendering of the same url is serialized across workers.
+// The lease is extended while rendering and the request is cancelled if it is lost, so it never runs twice at once.
 func (c *Client) GetPDF(params GetPDFParams) (result []byte, err error) {
+	var ctx = context.Background()
+	if params.Context != nil {
+		ctx = params.Context
+	}
 	if c.locker != nil && !params.DisableLocker {
-		cancel, err := c.locker.AcquireLock(fmt.Sprintf("processing_pdf_%s", params.URL), time.Minute*5)
+		waitCtx, cancel := context.WithTimeout(ctx, time.Minute*5)
+		defer cancel()
+
+		lease, err := c.locker.Acquire(waitCtx, fmt.Sprintf("processing_pdf_%s", params.URL))
 		if err != nil {
 			return nil, err
 		}
-		defer cancel()
+		defer lease.Release()
+
+		ctx = lease.Context()
 	}
 
 	var claims = sjwt.New()
@@ -68,7 +88,7 @@ func (c *Client) GetPDF(params GetPDFParams) (result []byte, err error) {
 	u.RawQuery = q.Encode()
 
 	link := u.String()
-	req, err := http.NewRequest("GET", link, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
 	if err != nil {
 		err = fmt.Errorf("error creating request: %v", err)
 		return
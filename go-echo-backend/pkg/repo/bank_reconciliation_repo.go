@@ -0,0 +1,411 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+	"github.com/thaitanloi365/go-utils/values"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BankReconciliationRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewBankReconciliationRepo(db *db.DB) *BankReconciliationRepo {
+	return &BankReconciliationRepo{
+		db:     db,
+		logger: logger.New("repo/BankReconciliation"),
+	}
+}
+
+type ImportBankStatementParams struct {
+	models.JwtClaimsInfo
+
+	Format enums.BankStatementFormat `json:"format" query:"format" form:"format" validate:"omitempty,oneof=csv mt940 camt053"` // Detected from the content when empty
+	File   *multipart.FileHeader     `json:"-"`
+}
+
+// ImportBankStatement stores the statement lines and auto-matches incoming ones against pending bank transfers.
+// Lines already imported from an overlapping statement are skipped.
+func (r *BankReconciliationRepo) ImportBankStatement(params ImportBankStatementParams) (*models.BankStatement, error) {
+	file, err := params.File.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var format = params.Format
+	if format == "" {
+		format = models.DetectBankStatementFormat(content)
+	}
+
+	lines, accountNumber, err := models.ParseBankStatement(format, content)
+	if err != nil {
+		return nil, eris.Wrap(errs.ErrBankStatementInvalidFile, err.Error())
+	}
+	if len(lines) == 0 {
+		return nil, eris.Wrap(errs.ErrBankStatementInvalidFile, "file has no lines")
+	}
+
+	var statement = models.BankStatement{
+		FileName:         params.File.Filename,
+		Format:           format,
+		AccountNumber:    accountNumber,
+		UploadedByUserID: params.GetUserID(),
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&statement).Error; err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			line.BankStatementID = statement.ID
+		}
+
+		var result = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "fingerprint"}},
+			DoNothing: true,
+		}).Create(&lines)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		statement.LineCount = int(result.RowsAffected)
+		return tx.Model(&statement).UpdateColumn("LineCount", statement.LineCount).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Duplicated lines have no ID since the insert was skipped
+	statement.Lines = lo.Filter(lines, func(line *models.BankStatementLine, _ int) bool {
+		return line.ID != "" && line.Status == enums.BankStatementLineStatusUnmatched
+	})
+	if err := r.matchLines(statement.Lines); err != nil {
+		return nil, err
+	}
+
+	return &statement, nil
+}
+
+type GetBankStatementsParams struct {
+	models.JwtClaimsInfo
+
+	DateFrom int64 `json:"date_from" query:"date_from"`
+	DateTo   int64 `json:"date_to" query:"date_to"`
+}
+
+func (r *BankReconciliationRepo) GetBankStatements(params GetBankStatementsParams) (models.BankStatements, error) {
+	var result models.BankStatements
+	var query = r.db.Model(&models.BankStatement{})
+	if params.DateFrom > 0 {
+		query = query.Where("created_at >= ?", params.DateFrom)
+	}
+	if params.DateTo > 0 {
+		query = query.Where("created_at <= ?", params.DateTo)
+	}
+
+	var err = query.Order("created_at DESC").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type PaginateBankStatementLinesParams struct {
+	models.PaginationParams
+	models.JwtClaimsInfo
+
+	BankStatementID string                          `json:"bank_statement_id" query:"bank_statement_id" param:"bank_statement_id"`
+	Statuses        []enums.BankStatementLineStatus `json:"statuses" query:"statuses"`
+	Currency        enums.Currency                  `json:"currency" query:"currency"`
+}
+
+// PaginateBankStatementLines the review queue, filter statuses by matched and suggested for lines waiting for approval
+func (r *BankReconciliationRepo) PaginateBankStatementLines(params PaginateBankStatementLinesParams) *query.Pagination {
+	var builder = queryfunc.NewBankStatementLineBuilder(queryfunc.BankStatementLineBuilderOptions{
+		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+			Role: params.GetRole(),
+		},
+	})
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var result = query.New(r.db, builder).
+		WhereFunc(func(builder *query.Builder) {
+			if params.BankStatementID != "" {
+				builder.Where("bsl.bank_statement_id = ?", params.BankStatementID)
+			}
+
+			if len(params.Statuses) > 0 {
+				builder.Where("bsl.status IN ?", params.Statuses)
+			}
+
+			if params.Currency != "" {
+				builder.Where("bsl.currency = ?", params.Currency)
+			}
+		}).
+		Page(params.Page).
+		Limit(params.Limit).
+		PagingFunc()
+
+	if records, ok := result.Records.(*[]*models.BankStatementLine); ok {
+		if err := r.loadPaymentTransactions(*records); err != nil {
+			r.logger.ErrorAny(err)
+		}
+	}
+
+	return result
+}
+
+type MatchBankStatementParams struct {
+	models.JwtClaimsInfo
+
+	BankStatementID string `json:"bank_statement_id" param:"bank_statement_id" validate:"required"`
+}
+
+// MatchBankStatement re-runs auto-matching for the unmatched lines, e.g. after buyers submitted new transfers
+func (r *BankReconciliationRepo) MatchBankStatement(params MatchBankStatementParams) (models.BankStatementLines, error) {
+	var lines models.BankStatementLines
+	var err = r.db.Find(&lines, "bank_statement_id = ? AND status = ?", params.BankStatementID, enums.BankStatementLineStatusUnmatched).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.matchLines(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+type BankStatementLineParams struct {
+	models.JwtClaimsInfo
+
+	BankStatementLineID string `json:"bank_statement_line_id" param:"bank_statement_line_id" validate:"required"`
+	// PaymentTransactionID overrides the suggested transfer when the reviewer picks another one
+	PaymentTransactionID string `json:"payment_transaction_id"`
+	// Force approves an override whose amount or currency differs from the line, the note gives the reason
+	Force bool   `json:"force"`
+	Note  string `json:"note"`
+}
+
+// GetApprovableBankStatementLine returns the line with the pending transfer it will confirm,
+// or with its own transfer already confirmed by an approval that did not reconcile the line
+func (r *BankReconciliationRepo) GetApprovableBankStatementLine(params BankStatementLineParams) (*models.BankStatementLine, error) {
+	var line models.BankStatementLine
+	var err = r.db.First(&line, "id = ?", params.BankStatementLineID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrBankStatementLineNotFound
+		}
+		return nil, err
+	}
+
+	if line.Status == enums.BankStatementLineStatusReconciled || line.Status == enums.BankStatementLineStatusIgnored {
+		return nil, errs.ErrBankStatementLineNotApprovable
+	}
+
+	var paymentTransactionID = line.PaymentTransactionID
+	if params.PaymentTransactionID != "" {
+		paymentTransactionID = params.PaymentTransactionID
+	}
+	if paymentTransactionID == "" {
+		return nil, errs.ErrBankStatementLineNoPaymentMatch
+	}
+
+	var transaction models.PaymentTransaction
+	err = r.db.First(&transaction, "id = ?", paymentTransactionID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrPaymentTransactionNotFound
+		}
+		return nil, err
+	}
+
+	switch transaction.Status {
+	case enums.PaymentStatusWaitingConfirm:
+	case enums.PaymentStatusPaid:
+		// A previous approval confirmed the transfer but failed before the line was reconciled,
+		// the retry only reconciles the line unless another line already claimed the transfer
+		var claimed int64
+		err = r.db.Model(&models.BankStatementLine{}).
+			Where("payment_transaction_id = ? AND status = ? AND id <> ?", transaction.ID, enums.BankStatementLineStatusReconciled, line.ID).
+			Count(&claimed).Error
+		if err != nil {
+			return nil, err
+		}
+		if transaction.ID != line.PaymentTransactionID || claimed > 0 {
+			return nil, errs.ErrBankStatementLineNoPaymentMatch
+		}
+	default:
+		return nil, errs.ErrBankStatementLineNoPaymentMatch
+	}
+
+	// The reviewer's pick is held to the amount and currency check of the automatic match
+	if transaction.ID != line.PaymentTransactionID && !line.PaysAmountOf(&transaction) &&
+		(!params.Force || strings.TrimSpace(params.Note) == "") {
+		return nil, errs.ErrBankStatementLineAmountMismatch
+	}
+
+	line.PaymentTransactionID = transaction.ID
+	line.PaymentTransaction = &transaction
+	return &line, nil
+}
+
+// MarkBankStatementLineReconciled records the approval once the transfer was confirmed
+func (r *BankReconciliationRepo) MarkBankStatementLineReconciled(params BankStatementLineParams, line *models.BankStatementLine) (*models.BankStatementLine, error) {
+	var updates = models.BankStatementLine{
+		Status:               enums.BankStatementLineStatusReconciled,
+		PaymentTransactionID: line.PaymentTransactionID,
+		ReconciledByUserID:   params.GetUserID(),
+		ReconciledAt:         values.Int64(time.Now().Unix()),
+	}
+	if params.PaymentTransactionID != "" && params.PaymentTransactionID != line.PaymentTransactionID {
+		updates.MatchReason = "Matched manually"
+	}
+	if params.Force && line.PaymentTransaction != nil && !line.PaysAmountOf(line.PaymentTransaction) {
+		updates.MatchReason = fmt.Sprintf("Approved with amount mismatch: %s", strings.TrimSpace(params.Note))
+	}
+
+	var result = r.db.Model(&models.BankStatementLine{}).
+		Where("id = ? AND status NOT IN ?", line.ID, []enums.BankStatementLineStatus{enums.BankStatementLineStatusReconciled, enums.BankStatementLineStatusIgnored}).
+		Updates(&updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errs.ErrBankStatementLineNotApprovable
+	}
+
+	line.Status = updates.Status
+	line.ReconciledByUserID = updates.ReconciledByUserID
+	line.ReconciledAt = updates.ReconciledAt
+	return line, nil
+}
+
+// IgnoreBankStatementLine takes a line out of the review queue, e.g. interest or transfers unrelated to orders
+func (r *BankReconciliationRepo) IgnoreBankStatementLine(params BankStatementLineParams) (*models.BankStatementLine, error) {
+	var line models.BankStatementLine
+	var err = r.db.First(&line, "id = ?", params.BankStatementLineID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrBankStatementLineNotFound
+		}
+		return nil, err
+	}
+
+	if line.Status == enums.BankStatementLineStatusReconciled {
+		return nil, errs.ErrBankStatementLineNotApprovable
+	}
+
+	err = r.db.Model(&line).Where("id = ?", line.ID).Updates(map[string]interface{}{
+		"status":                 enums.BankStatementLineStatusIgnored,
+		"payment_transaction_id": "",
+		"match_reason":           params.Note,
+		"reconciled_by_user_id":  params.GetUserID(),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &line, nil
+}
+
+// matchLines matches lines against pending bank transfers not already claimed by another statement line
+func (r *BankReconciliationRepo) matchLines(lines models.BankStatementLines) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	cancel, err := r.db.Locker.AcquireLock("bank_statement_matching", time.Second*30)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	var candidates models.PaymentTransactions
+	err = r.db.Model(&models.PaymentTransaction{}).
+		Where("status = ? AND payment_type = ?", enums.PaymentStatusWaitingConfirm, enums.PaymentTypeBankTransfer).
+		Where("id NOT IN (SELECT payment_transaction_id FROM bank_statement_lines WHERE status IN ? AND payment_transaction_id <> '')",
+			[]enums.BankStatementLineStatus{enums.BankStatementLineStatusMatched, enums.BankStatementLineStatusSuggested, enums.BankStatementLineStatusReconciled}).
+		Order("created_at ASC").
+		Find(&candidates).Error
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		var match = models.MatchBankStatementLine(line, candidates)
+		if match.PaymentTransaction == nil && match.Reason == line.MatchReason {
+			continue
+		}
+
+		line.ApplyMatch(match)
+		err = r.db.Model(&models.BankStatementLine{}).Where("id = ?", line.ID).Updates(map[string]interface{}{
+			"status":                 line.Status,
+			"payment_transaction_id": line.PaymentTransactionID,
+			"match_score":            line.MatchScore,
+			"match_reason":           line.MatchReason,
+		}).Error
+		if err != nil {
+			return eris.Wrap(err, fmt.Sprintf("update bank statement line %s", line.ID))
+		}
+
+		if match.PaymentTransaction != nil {
+			candidates = lo.Filter(candidates, func(item *models.PaymentTransaction, _ int) bool {
+				return item.ID != match.PaymentTransaction.ID
+			})
+		}
+	}
+
+	return nil
+}
+
+func (r *BankReconciliationRepo) loadPaymentTransactions(lines []*models.BankStatementLine) error {
+	var ids = lo.Uniq(lo.FilterMap(lines, func(line *models.BankStatementLine, _ int) (string, bool) {
+		return line.PaymentTransactionID, line.PaymentTransactionID != ""
+	}))
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var transactions models.PaymentTransactions
+	var err = r.db.Find(&transactions, "id IN ?", ids).Error
+	if err != nil {
+		return err
+	}
+
+	var mapTransactions = lo.KeyBy(transactions, func(item *models.PaymentTransaction) string {
+		return item.ID
+	})
+	for _, line := range lines {
+		line.PaymentTransaction = mapTransactions[line.PaymentTransactionID]
+	}
+
+	return nil
+}
@@ -269,11 +269,17 @@ func (r *BulkPurchaseOrderRepo) UpdateBulkPurchaseOrder(form models.BulkPurchase
 			}
 		}
 
+		// The first edit of a new order moves it to waiting for submit
 		if order.TrackingStatus == enums.BulkPoTrackingStatusNew {
-			updates.TrackingStatus = enums.BulkPoTrackingStatusWaitingForSubmitOrder
+			err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: form.JwtClaimsInfo,
+				Order:         order,
+				Action:        enums.BulkPoTrackingActionUpdateOrder,
+				Updates:       &updates,
+			})
+		} else {
+			err = tx.Model(&models.BulkPurchaseOrder{}).Omit("TrackingStatus").Where("id = ?", form.BulkPurchaseOrderID).Updates(&updates).Error
 		}
-
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", form.BulkPurchaseOrderID).Updates(&updates).Error
 		if err != nil {
 			return err
 		}
@@ -349,25 +355,21 @@ func (r *BulkPurchaseOrderRepo) SubmitBulkPurchaseOrder(form models.BulkPurchase
 
 	var updates models.BulkPurchaseOrder
 
-	updates.SubmittedAt = values.Int64(time.Now().Unix())
-	updates.TrackingStatus = enums.BulkPoTrackingStatusWaitingForQuotation
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionSubmitOrder,
-			UserID:          order.UserID,
-			CreatedByUserID: form.JwtClaimsInfo.GetUserID(),
-			Description: func() string {
-				if order.User != nil && order.User.Name != "" {
-					return fmt.Sprintf("%s submitted order and waiting for quotation", order.User.Name)
-				}
-				return "User submitted order and waiting for quotation"
-			}(),
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: form.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSubmitOrder,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Description: func() string {
+					if order.User != nil && order.User.Name != "" {
+						return fmt.Sprintf("%s submitted order and waiting for quotation", order.User.Name)
+					}
+					return "User submitted order and waiting for quotation"
+				}(),
+			},
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", form.BulkPurchaseOrderID).Updates(&updates).Error
 	})
 	if err != nil {
 		return nil, err
@@ -391,23 +393,22 @@ func (r *BulkPurchaseOrderRepo) AdminSubmitBulkPurchaseOrder(form models.BulkPur
 
 	var updates models.BulkPurchaseOrder
 
-	updates.SubmittedAt = values.Int64(time.Now().Unix())
-	updates.TrackingStatus = enums.BulkPoTrackingStatusWaitingForQuotation
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if admin, err := NewUserRepo(r.db).GetShortUserInfo(form.GetUserID()); err == nil {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: order.ID,
-				ActionType:      enums.BulkPoTrackingActionSubmitOrder,
-				UserID:          form.JwtClaimsInfo.GetUserID(),
-				CreatedByUserID: form.JwtClaimsInfo.GetUserID(),
-				Description:     fmt.Sprintf("%s submitted order and waiting for quotation", admin.Name),
-			})
-			if err != nil {
-				return err
-			}
-		}
+	var tracking = models.BulkPurchaseOrderTrackingCreateForm{
+		UserID:      form.JwtClaimsInfo.GetUserID(),
+		Description: "Admin submitted order and waiting for quotation",
+	}
+	if admin, err := NewUserRepo(r.db).GetShortUserInfo(form.GetUserID()); err == nil {
+		tracking.Description = fmt.Sprintf("%s submitted order and waiting for quotation", admin.Name)
+	}
 
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", form.BulkPurchaseOrderID).Updates(&updates).Error
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: form.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSubmitOrder,
+			Updates:       &updates,
+			Tracking:      tracking,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -442,14 +443,12 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderCheckout(params BulkPurchaseOrd
 		return nil, err
 	}
 
+	var checkoutAction = enums.BulkPoTrackingActionMakeFirstPayment
 	if params.Milestone == enums.PaymentMilestoneFinalPayment {
-		if order.TrackingStatus != enums.BulkPoTrackingStatusFinalPayment {
-			return nil, errs.ErrBulkPoInvalidToCheckout
-		}
-	} else {
-		if order.TrackingStatus != enums.BulkPoTrackingStatusFirstPayment {
-			return nil, errs.ErrBulkPoInvalidToCheckout
-		}
+		checkoutAction = enums.BulkPoTrackingActionMakeFinalPayment
+	}
+	if _, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, checkoutAction); err != nil {
+		return nil, errs.ErrBulkPoInvalidToCheckout
 	}
 
 	if params.PaymentType == enums.PaymentTypeBankTransfer {
@@ -491,14 +490,13 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderCheckout(params BulkPurchaseOrd
 
 		var updates models.BulkPurchaseOrder
 		updates.TaxPercentage = order.Inquiry.TaxPercentage
+		NewFxRateRepo(r.db).GetFxSnapshot(order.Currency, &order.FxSnapshot).ApplyTo(&updates.Pricing, order.Pricing)
 		if params.Milestone == enums.PaymentMilestoneFinalPayment {
-			updates.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirm
 			updates.FinalPaymentTransferedAt = values.Int64(time.Now().Unix())
 			updates.FinalPaymentTransactionRefID = params.TransactionRefID
 			updates.FinalPaymentTransactionAttachment = params.TransactionAttachment
 			updates.FinalPaymentTransactionReferenceID = transaction.ReferenceID
 		} else {
-			updates.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirm
 			updates.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
 			updates.FirstPaymentTransactionRefID = params.TransactionRefID
 			updates.FirstPaymentTransactionAttachment = params.TransactionAttachment
@@ -506,30 +504,28 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderCheckout(params BulkPurchaseOrd
 		}
 
 		err = r.db.Transaction(func(tx *gorm.DB) error {
-			err = r.db.Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(&updates).Error
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-			var tracking = models.BulkPurchaseOrderTrackingCreateForm{
-				ActionType:      enums.BulkPoTrackingActionMakeFirstPayment,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Description:     "Processed payment",
-				PurchaseOrderID: order.ID,
-				Metadata: &models.PoTrackingMetadata{
-					After: map[string]interface{}{
-						"payment_transaction_id": transaction.ID,
+			var err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         order,
+				Action:        checkoutAction,
+				Updates:       &updates,
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Description: "Processed payment",
+					Metadata: &models.PoTrackingMetadata{
+						After: map[string]interface{}{
+							"payment_transaction_id": transaction.ID,
+						},
 					},
 				},
+			})
+			if err != nil {
+				return err
 			}
-			if params.Milestone == enums.PaymentMilestoneFinalPayment {
-				tracking.ActionType = enums.BulkPoTrackingActionMakeFinalPayment
-			}
-			return NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, tracking)
+
+			return NewPaymentMilestoneRepo(r.db).SyncLegacyPaymentMilestoneTx(tx, order.ID, transaction.Milestone)
 		})
 		if err != nil {
-			return nil, eris.Wrap(err, err.Error())
+			return nil, err
 		}
 
 		return order, err
@@ -647,14 +643,14 @@ PaymentSuccess:
 		transaction.PaymentPercentage = values.Float64(100 - values.Float64Value(order.FirstPaymentPercentage))
 	}
 
-	r.db.Transaction(func(tx *gorm.DB) error {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
 		err = tx.Create(&transaction).Error
 		if err != nil {
 			return eris.Wrap(err, err.Error())
 		}
 
+		var action = enums.BulkPoTrackingActionFirstPaymentConfirmed
 		var updates = models.BulkPurchaseOrder{
-			TrackingStatus:                     enums.BulkPoTrackingStatusFirstPaymentConfirmed,
 			FirstPaymentTransferedAt:           values.Int64(time.Now().Unix()),
 			FirstPaymentMarkAsPaidAt:           values.Int64(time.Now().Unix()),
 			FirstPaymentTransactionReferenceID: transaction.ReferenceID,
@@ -670,16 +666,34 @@ PaymentSuccess:
 		}
 
 		updates.TaxPercentage = order.Inquiry.TaxPercentage
+		NewFxRateRepo(r.db).GetFxSnapshot(order.Currency, &order.FxSnapshot).ApplyTo(&updates.Pricing, order.Pricing)
 
 		if params.Milestone == enums.PaymentMilestoneFinalPayment {
-			updates.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
+			action = enums.BulkPoTrackingActionFinalPaymentConfirmed
 			updates.FinalPaymentTransferedAt = values.Int64(time.Now().Unix())
 			updates.FinalPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 			updates.FinalPaymentTransactionReferenceID = transaction.ReferenceID
 		}
 
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(&updates).Error
-		return err
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        action,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Description: "Processed card payment",
+				Metadata: &models.PoTrackingMetadata{
+					After: map[string]interface{}{
+						"payment_transaction_id": transaction.ID,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return NewPaymentMilestoneRepo(r.db).SyncLegacyPaymentMilestoneTx(tx, order.ID, transaction.Milestone)
 	})
 	if err != nil {
 		return nil, err
@@ -774,7 +788,6 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderPreviewCheckout(params BulkPurc
 
 	if params.IsSkipFirstPayment {
 		bulkPurchaseOrder.FirstPaymentPercentage = values.Float64(0)
-		bulkPurchaseOrder.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
 		bulkPurchaseOrder.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
 		bulkPurchaseOrder.FirstPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 	}
@@ -784,8 +797,21 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderPreviewCheckout(params BulkPurc
 	}
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if params.IsSkipFirstPayment {
+			if err := r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         bulkPurchaseOrder,
+				Action:        enums.BulkPoTrackingActionSkipFirstPayment,
+				Updates: &models.BulkPurchaseOrder{
+					FirstPaymentTransferedAt: bulkPurchaseOrder.FirstPaymentTransferedAt,
+					FirstPaymentMarkAsPaidAt: bulkPurchaseOrder.FirstPaymentMarkAsPaidAt,
+				},
+			}); err != nil {
+				return err
+			}
+		}
 		if !params.GetRole().IsAdmin() || params.UpdatePricing {
-			if err := tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPurchaseOrder.ID).Updates(bulkPurchaseOrder).Error; err != nil {
+			if err := tx.Model(&models.BulkPurchaseOrder{}).Omit("TrackingStatus").Where("id = ?", bulkPurchaseOrder.ID).Updates(bulkPurchaseOrder).Error; err != nil {
 				return err
 			}
 		}
@@ -833,6 +859,23 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrdersPreviewCheckout(params BulkPur
 }
 
 func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkAsPaid(params models.BulkPurchaseOrderMarkAsPaidParams) (*models.BulkPurchaseOrder, error) {
+	if params.PaymentMilestoneID != "" {
+		milestone, err := NewPaymentMilestoneRepo(r.db).MarkPaymentMilestoneAsPaid(models.PaymentMilestoneMarkAsPaidParams{
+			JwtClaimsInfo:       params.JwtClaimsInfo,
+			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+			PaymentMilestoneID:  params.PaymentMilestoneID,
+			OutboxTasks:         params.OutboxTasks,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.BulkPurchaseOrder{
+			Model:             models.Model{ID: params.BulkPurchaseOrderID},
+			PaymentMilestones: models.PaymentMilestones{milestone},
+		}, nil
+	}
+
 	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_payment_%s", params.BulkPurchaseOrderID), time.Second*20)
 	if err != nil {
 		return nil, err
@@ -847,7 +890,6 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkAsPaid(params models.BulkPu
 
 	var updates = models.BulkPurchaseOrder{
 		FirstPaymentMarkAsPaidAt: values.Int64(time.Now().Unix()),
-		TrackingStatus:           enums.BulkPoTrackingStatusFirstPaymentConfirmed,
 	}
 
 	var paymentTransactionUpdates = models.PaymentTransaction{
@@ -871,28 +913,49 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkAsPaid(params models.BulkPu
 
 	if params.Milestone == enums.PaymentMilestoneFinalPayment {
 		updates.FinalPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
-		updates.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
 
 		var err = r.db.Transaction(func(tx *gorm.DB) error {
-			var err = tx.Model(&models.BulkPurchaseOrder{}).
-				Where("id = ? AND final_payment_mark_as_paid_at IS NULL", params.BulkPurchaseOrderID).
-				Updates(&updates).Error
+			var err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         &bulkPurchaseOrder,
+				Action:        enums.BulkPoTrackingActionFinalPaymentConfirmed,
+				Updates:       &updates,
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Description: "Final payment is marked as paid",
+				},
+			})
 			if err != nil {
 				return err
 			}
 
-			return tx.Model(&models.PaymentTransaction{}).
+			err = tx.Model(&models.PaymentTransaction{}).
 				Where("bulk_purchase_order_id = ? AND payment_type = ? AND mark_as_paid_at IS NULL AND milestone = ?", params.BulkPurchaseOrderID, enums.PaymentTypeBankTransfer, enums.PaymentMilestoneFinalPayment).
 				Updates(&paymentTransactionUpdates).Error
+			if err != nil {
+				return err
+			}
+
+			err = NewPaymentMilestoneRepo(r.db).SyncLegacyPaymentMilestoneTx(tx, params.BulkPurchaseOrderID, enums.PaymentMilestoneFinalPayment)
+			if err != nil {
+				return err
+			}
+
+			return NewOutboxRepo(r.db).AddTasksTx(tx, params.BulkPurchaseOrderID, params.OutboxTasks...)
 		})
 
 		return &bulkPurchaseOrder, err
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ? AND first_payment_mark_as_paid_at IS NULL", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         &bulkPurchaseOrder,
+			Action:        enums.BulkPoTrackingActionFirstPaymentConfirmed,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Description: "First payment is marked as paid",
+			},
+		})
 		if err != nil {
 			return err
 		}
@@ -900,8 +963,16 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkAsPaid(params models.BulkPu
 		err = tx.Model(&models.PaymentTransaction{}).
 			Where("bulk_purchase_order_id = ? AND payment_type = ? AND mark_as_paid_at IS NULL AND milestone = ?", params.BulkPurchaseOrderID, enums.PaymentTypeBankTransfer, enums.PaymentMilestoneFirstPayment).
 			Updates(&paymentTransactionUpdates).Error
+		if err != nil {
+			return err
+		}
 
-		return err
+		err = NewPaymentMilestoneRepo(r.db).SyncLegacyPaymentMilestoneTx(tx, params.BulkPurchaseOrderID, enums.PaymentMilestoneFirstPayment)
+		if err != nil {
+			return err
+		}
+
+		return NewOutboxRepo(r.db).AddTasksTx(tx, params.BulkPurchaseOrderID, params.OutboxTasks...)
 	})
 
 	if err != nil {
@@ -994,15 +1065,26 @@ func (r *BulkPurchaseOrderRepo) UpdatePurchaseOrderTrackingStatus(params UpdateB
 		return nil, err
 	}
 
-	err = r.db.Model(&models.BulkPurchaseOrder{}).
-		Where("id = ?", params.BulkPurchaseOrderID).
-		Updates(&updates).Error
+	// Only the target status is known here so the action is looked up from it
+	var fromStatus = order.TrackingStatus
+	transition, err := FindBulkPurchaseOrderTransitionTo(fromStatus, enums.BulkPoTrackingStatus(params.TrackingStatus))
+	if err != nil {
+		return nil, err
+	}
 
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        transition.Action,
+			Updates:       &updates,
+		})
+	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	if order.TrackingStatus != updates.TrackingStatus {
+	if fromStatus != updates.TrackingStatus {
 		err = NewInquiryAuditRepo(r.db).CreateInquiryAudit(models.InquiryAuditCreateForm{
 			InquiryID:           order.InquiryID,
 			ActionType:          enums.AuditActionTypeInquiryBulkPoCreated,
@@ -1011,7 +1093,7 @@ func (r *BulkPurchaseOrderRepo) UpdatePurchaseOrderTrackingStatus(params UpdateB
 			BulkPurchaseOrderID: order.ID,
 			Metadata: &models.InquiryAuditMetadata{
 				Before: map[string]interface{}{
-					"tracking_status": order.TrackingStatus,
+					"tracking_status": fromStatus,
 				},
 				After: map[string]interface{}{
 					"tracking_status": updates.TrackingStatus,
@@ -1024,7 +1106,6 @@ func (r *BulkPurchaseOrderRepo) UpdatePurchaseOrderTrackingStatus(params UpdateB
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	return order, err
 }
 
@@ -1046,36 +1127,32 @@ func (r *BulkPurchaseOrderRepo) SendQuotationToBuyer(form models.SendBulkPurchas
 	order.FirstPaymentPercentage = &form.FirstPaymentPercentage
 	updates.FirstPaymentPercentage = &form.FirstPaymentPercentage
 
-	updates.TrackingStatus = enums.BulkPoTrackingStatusFirstPayment
 	updates.QuotationAt = values.Int64(time.Now().Unix())
+	if fx := NewFxRateRepo(r.db).GetFxSnapshot(order.Currency, nil); fx != nil {
+		updates.FxSnapshot = *fx
+		order.FxSnapshot = *fx
+	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionSubmitQuotation,
-			UserID:          order.UserID,
-			CreatedByUserID: form.JwtClaimsInfo.GetUserID(),
-			Description:     "Admin has sent quotation to buyer",
-			Metadata: &models.PoTrackingMetadata{
-				After: map[string]interface{}{
-					"quotations": form.AdminQuotations,
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: form.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSubmitQuotation,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Description: "Admin has sent quotation to buyer",
+				Metadata: &models.PoTrackingMetadata{
+					After: map[string]interface{}{
+						"quotations": form.AdminQuotations,
+					},
 				},
 			},
 		})
-		if err != nil {
-			return eris.Wrap(err, err.Error())
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", form.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	order.QuotationAt = updates.QuotationAt
 
 	if form.FirstPaymentPercentage == 0 {
@@ -1113,16 +1190,22 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderCreateQcReport(params AdminCrea
 	}
 
 	var updates models.BulkPurchaseOrder
-	updates.TrackingStatus = enums.BulkPoTrackingStatusQc
-
 	var reports models.PoReportMetas = params.PoQcReports
 	updates.PoQcReports = &reports
 	updates.ApproveQCAt = params.ApproveQCAt
 
+	transition, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionCreateQcReport)
+	if err != nil {
+		return nil, err
+	}
+
+	// One tracking row per report, so the state machine is told not to add its own
 	var trackings = lo.Map(params.PoQcReports, func(item *models.PoReportMeta, index int) *models.BulkPurchaseOrderTracking {
 		return &models.BulkPurchaseOrderTracking{
 			PurchaseOrderID: order.ID,
 			ActionType:      enums.BulkPoTrackingActionCreateQcReport,
+			FromStatus:      order.TrackingStatus,
+			ToStatus:        transition.ToStatus(order.TrackingStatus),
 			UserID:          order.UserID,
 			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
 			ReportStatus:    item.Status,
@@ -1137,18 +1220,26 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderCreateQcReport(params AdminCrea
 	})
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Create(&trackings).Error
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionCreateQcReport,
+			Updates:       &updates,
+			SkipTracking:  true,
+		})
 		if err != nil {
 			return err
 		}
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
 
+		if len(trackings) == 0 {
+			return nil
+		}
+		return tx.Create(&trackings).Error
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	order.PoQcReports = updates.PoQcReports
 
 	return order, err
@@ -1180,35 +1271,28 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderUpdateRawMaterial(params BulkPu
 
 	_ = updates.GenerateRawMaterialRefID(updates.PoRawMaterials)
 
-	updates.TrackingStatus = enums.BulkPoTrackingStatusRawMaterial
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdateMaterial,
-			UserID:          order.UserID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"po_raw_materials": order.PoRawMaterials,
-				},
-				After: map[string]interface{}{
-					"po_raw_materials": params.PoRawMaterials,
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdateMaterial,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"po_raw_materials": order.PoRawMaterials,
+					},
+					After: map[string]interface{}{
+						"po_raw_materials": params.PoRawMaterials,
+					},
 				},
 			},
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
-
 	return order, err
 }
 
@@ -1233,39 +1317,28 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderUpdateTrackingStatus(params Bul
 		return nil, err
 	}
 
-	var updates = models.BulkPurchaseOrder{
-		TrackingStatus: params.TrackingStatus,
+	// The action decides the next status, the requested status must agree with it
+	transition, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, params.TrackingAction)
+	if err != nil {
+		return nil, err
+	}
+	if params.TrackingStatus != "" && transition.ToStatus(order.TrackingStatus) != params.TrackingStatus {
+		return nil, errs.ErrBulkPoInvalidTransition
 	}
 
+	var updates models.BulkPurchaseOrder
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      params.TrackingAction,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"tracking_status": order.TrackingStatus,
-					},
-					After: map[string]interface{}{
-						"tracking_status": updates.TrackingStatus,
-					},
-				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        params.TrackingAction,
+			Updates:       &updates,
+		})
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	return order, err
 }
 
@@ -1287,7 +1360,7 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderBuyerApproveRawMaterial(params
 		return nil, err
 	}
 
-	if *order.PoRawMaterials == nil {
+	if order.PoRawMaterials == nil || *order.PoRawMaterials == nil {
 		return nil, errs.ErrBulkPoInvalidToApproveRawMaterial
 	}
 
@@ -1303,13 +1376,17 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderBuyerApproveRawMaterial(params
 		}
 	}
 
+	if !shouldTracking {
+		return order, nil
+	}
+
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if shouldTracking {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionBuyerApproveRawMaterial,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionBuyerApproveRawMaterial,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
 				Metadata: &models.PoTrackingMetadata{
 					Before: map[string]interface{}{
 						"po_raw_materials": order.PoRawMaterials,
@@ -1318,22 +1395,13 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderBuyerApproveRawMaterial(params
 						"po_raw_materials": updates.PoRawMaterials,
 					},
 				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+			},
+		})
 	})
-
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
+
 	order.PoRawMaterials = updates.PoRawMaterials
 	return order, err
 }
@@ -1353,40 +1421,21 @@ func (r *BulkPurchaseOrderRepo) BuyerConfirmDelivered(params BulkOrderConfirmDel
 		return nil, err
 	}
 
-	validStatus := []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusDelivering}
-	if ok := slices.Contains(validStatus, order.TrackingStatus); !ok {
+	if _, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionConfirmDelivered); err != nil {
 		return nil, errs.ErrPoInvalidToConfirmDelivered
 	}
 
-	var updates = models.BulkPurchaseOrder{
-		TrackingStatus:      enums.BulkPoTrackingStatusDeliveryConfirmed,
-		ReceiverConfirmedAt: values.Int64(time.Now().Unix()),
-	}
-
+	var updates models.BulkPurchaseOrder
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: params.BulkPurchaseOrderID,
-			ActionType:      enums.BulkPoTrackingActionConfirmDelivered,
-			UserID:          order.UserID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"tracking_status": order.TrackingStatus,
-				},
-				After: map[string]interface{}{
-					"tracking_status": updates.TrackingStatus,
-				},
-			},
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionConfirmDelivered,
+			Updates:       &updates,
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
 	return order, err
@@ -1405,57 +1454,41 @@ func (r *BulkPurchaseOrderRepo) MarkDelivering(params BulkPurchaseOrderMarkDeliv
 		JwtClaimsInfo:       params.JwtClaimsInfo,
 		IncludeUser:         true,
 	})
-
-	validStatus := []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFinalPaymentConfirm, enums.BulkPoTrackingStatusFinalPaymentConfirmed}
-	if ok := slices.Contains(validStatus, order.TrackingStatus); !ok {
-		return nil, errs.ErrBulkPoInvalidToChangeTrackingStatus
-	}
-
 	if err != nil {
 		return nil, err
 	}
 
+	if _, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionMarkDelivering); err != nil {
+		return nil, errs.ErrBulkPoInvalidToChangeTrackingStatus
+	}
+
 	var updates = models.BulkPurchaseOrder{
-		DeliveryStartedAt: values.Int64(time.Now().Unix()),
-		TrackingStatus:    enums.BulkPoTrackingStatusDelivering,
+		LogisticInfo: params.LogisticInfo,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionMarkDelivering,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionMarkDelivering,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
 				Metadata: &models.PoTrackingMetadata{
 					Before: map[string]interface{}{
 						"tracking_status": order.TrackingStatus,
 					},
 					After: map[string]interface{}{
-						"tracking_status": updates.TrackingStatus,
+						"tracking_status": enums.BulkPoTrackingStatusDelivering,
 						"logistic_info":   params.LogisticInfo,
 					},
 				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+			},
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	order.LogisticInfo = updates.LogisticInfo
 
 	return order, err
@@ -1465,6 +1498,8 @@ type BulkPurchaseOrderMarkFirstPaymentParams struct {
 	JwtClaimsInfo models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" query:"bulk_purchase_order_id" validate:"required"`
+
+	OutboxTasks []models.OutboxTask `json:"-"`
 }
 
 func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkFirstPayment(params BulkPurchaseOrderMarkFirstPaymentParams) (*models.BulkPurchaseOrder, error) {
@@ -1485,37 +1520,23 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkFirstPayment(params BulkPur
 	}
 	updates.FirstPaymentReceivedAt = values.Int64(time.Now().Unix())
 	updates.FirstPaymentMarkAsPaidAt = updates.FirstPaymentReceivedAt
-	updates.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionFirstPaymentConfirmed,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"tracking_status": order.TrackingStatus,
-					},
-					After: map[string]interface{}{
-						"tracking_status": updates.TrackingStatus,
-					},
-				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionFirstPaymentConfirmed,
+			Updates:       &updates,
+		})
+		if err != nil {
+			return err
 		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+
+		return NewOutboxRepo(r.db).AddTasksTx(tx, params.BulkPurchaseOrderID, params.OutboxTasks...)
 	})
 
 	order.FirstPaymentReceivedAt = updates.FirstPaymentReceivedAt
 	order.FirstPaymentMarkAsPaidAt = updates.FirstPaymentMarkAsPaidAt
-	order.TrackingStatus = updates.TrackingStatus
 	return order, err
 }
 
@@ -1523,6 +1544,8 @@ type BulkPurchaseOrderMarkFinalPaymentParams struct {
 	JwtClaimsInfo models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" query:"bulk_purchase_order_id" validate:"required"`
+
+	OutboxTasks []models.OutboxTask `json:"-"`
 }
 
 func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkFinalPayment(params BulkPurchaseOrderMarkFinalPaymentParams) (*models.BulkPurchaseOrder, error) {
@@ -1543,38 +1566,23 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderMarkFinalPayment(params BulkPur
 	}
 	updates.FinalPaymentReceivedAt = values.Int64(time.Now().Unix())
 	updates.FinalPaymentMarkAsPaidAt = updates.FinalPaymentReceivedAt
-	updates.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionFinalPaymentConfirmed,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"tracking_status": order.TrackingStatus,
-					},
-					After: map[string]interface{}{
-						"tracking_status": updates.TrackingStatus,
-					},
-				},
-			})
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionFinalPaymentConfirmed,
+			Updates:       &updates,
+		})
+		if err != nil {
+			return err
 		}
 
-		return r.db.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+		return NewOutboxRepo(r.db).AddTasksTx(tx, params.BulkPurchaseOrderID, params.OutboxTasks...)
 	})
 
 	order.FinalPaymentReceivedAt = updates.FinalPaymentReceivedAt
 	order.FinalPaymentMarkAsPaidAt = updates.FinalPaymentMarkAsPaidAt
-	order.TrackingStatus = updates.TrackingStatus
 	return order, err
 }
 
@@ -1602,6 +1610,10 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderConfirmQCReport(params BulkPurc
 		return nil, err
 	}
 
+	if _, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionMarkFinalPayment); err != nil {
+		return nil, err
+	}
+
 	var updates models.BulkPurchaseOrder
 	err = copier.Copy(&updates, &params)
 	if err != nil {
@@ -1615,7 +1627,6 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderConfirmQCReport(params BulkPurc
 		}
 	}
 
-	updates.TrackingStatus = enums.BulkPoTrackingStatusFinalPayment
 	updates.CommercialInvoice = params.CommercialInvoice
 	updates.AdditionalItems = params.AdditionalItems
 
@@ -1629,42 +1640,21 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderConfirmQCReport(params BulkPurc
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionMarkFinalPayment,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"tracking_status": order.TrackingStatus,
-					},
-					After: map[string]interface{}{
-						"tracking_status": updates.TrackingStatus,
-					},
-				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-		}
-
-		order.TrackingStatus = updates.TrackingStatus
 		order.CommercialInvoice = updates.CommercialInvoice
 		err = order.UpdatePrices()
 		if err != nil {
 			return eris.Wrap(err, err.Error())
 		}
 
-		return r.db.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionMarkFinalPayment,
+			Updates:       &updates,
+		})
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
 	return order, err
@@ -1686,8 +1676,7 @@ func (r *BulkPurchaseOrderRepo) AdminMarkDelivered(params BulkPurchaseOrderMarkD
 		return nil, err
 	}
 
-	validStatus := []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusDelivering}
-	if ok := slices.Contains(validStatus, order.TrackingStatus); !ok {
+	if _, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionDelivered); err != nil {
 		return nil, errs.ErrPoInvalidToConfirmDelivered
 	}
 
@@ -1697,34 +1686,16 @@ func (r *BulkPurchaseOrderRepo) AdminMarkDelivered(params BulkPurchaseOrderMarkD
 		return nil, err
 	}
 
-	updates.TrackingStatus = enums.BulkPoTrackingStatusDelivered
-	updates.DeliveredAt = values.Int64(time.Now().Unix())
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: params.BulkPurchaseOrderID,
-			ActionType:      enums.BulkPoTrackingActionDelivered,
-			UserID:          order.UserID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"tracking_status": order.TrackingStatus,
-				},
-				After: map[string]interface{}{
-					"tracking_status": updates.TrackingStatus,
-				},
-			},
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionDelivered,
+			Updates:       &updates,
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
-
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
 	return order, err
@@ -1764,34 +1735,31 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderUpdatePps(params BulkPurchaseOr
 	}
 
 	var updates = models.BulkPurchaseOrder{
-		PpsInfo:        &ppsInfoArr,
-		TrackingStatus: enums.BulkPoTrackingStatusPps,
+		PpsInfo: &ppsInfoArr,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdatePps,
-			UserID:          order.UserID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"pps_info": order.PpsInfo,
-				},
-				After: map[string]interface{}{
-					"pps_info": updates.PpsInfo,
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdatePps,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"pps_info": order.PpsInfo,
+					},
+					After: map[string]interface{}{
+						"pps_info": updates.PpsInfo,
+					},
 				},
 			},
 		})
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
 	order.PpsInfo = updates.PpsInfo
 	return order, err
 }
@@ -1819,39 +1787,28 @@ func (r *BulkPurchaseOrderRepo) BulkPurchaseOrderUpdateProduction(params BulkPur
 		return nil, err
 	}
 
-	if order.TrackingStatus == enums.BulkPoTrackingStatusRawMaterial {
-		updates.TrackingStatus = enums.BulkPoTrackingStatusProduction
-	}
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdateProduction,
-			UserID:          order.UserID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"production_info": order.ProductionInfo,
-				},
-				After: map[string]interface{}{
-					"production_info": params.ProductionInfo,
+		return r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdateProduction,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"production_info": order.ProductionInfo,
+					},
+					After: map[string]interface{}{
+						"production_info": params.ProductionInfo,
+					},
 				},
 			},
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
-
 	if err != nil {
-		return nil, eris.Wrap(err, err.Error())
+		return nil, err
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
-
 	return order, err
 }
 
@@ -2004,20 +1961,21 @@ func (r *BulkPurchaseOrderRepo) ResetBulkPurchaseOrder(params ResetBulkPurchaseO
 		return nil, err
 	}
 
-	if order.FirstPaymentIntentID != "" || values.Int64Value(order.FirstPaymentMarkAsPaidAt) > 0 || values.Int64Value(order.FirstPaymentReceivedAt) > 0 {
-		return nil, errs.ErrBulkPoFirstPaymentAlreaydPaid
-	}
-
-	var updates = models.BulkPurchaseOrder{
-		SubmittedAt:    nil,
-		TrackingStatus: enums.BulkPoTrackingStatusNew,
-		Status:         enums.BulkPurchaseOrderStatusNew,
-		QuotationAt:    nil,
-		Attachments:    nil,
-	}
+	var updates models.BulkPurchaseOrder
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Select("SubmittedAt", "QuotationAt", "TrackingStatus", "Status", "Attachments").Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(&updates).Error
+		err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionResetOrder,
+			Updates:       &updates,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Updates skips zero values, the cleared columns are selected explicitly
+		err = tx.Select("SubmittedAt", "QuotationAt", "Attachments").Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(&models.BulkPurchaseOrder{}).Error
 		if err != nil {
 			return err
 		}
@@ -2029,10 +1987,10 @@ func (r *BulkPurchaseOrderRepo) ResetBulkPurchaseOrder(params ResetBulkPurchaseO
 		return nil, err
 	}
 
-	order.SubmittedAt = updates.SubmittedAt
-	order.TrackingStatus = updates.TrackingStatus
+	order.SubmittedAt = nil
 	order.Status = updates.Status
-	order.QuotationAt = updates.QuotationAt
+	order.QuotationAt = nil
+	order.Attachments = nil
 	return order, err
 }
 
@@ -2422,10 +2380,9 @@ func (r *BulkPurchaseOrderRepo) UploadExcel(params UploadExcelParams) ([]*models
 			bulkPurchaseOrder.TaxPercentage = &record.TaxPercentage
 			bulkPurchaseOrder.FirstPaymentType = enums.PaymentTypeBankTransfer
 			if record.FirstPaymentPercentage == 0 {
+				// Imported with its quotation, the first payment is skipped after the insert
 				bulkPurchaseOrder.FirstPaymentPercentage = values.Float64(0)
-				bulkPurchaseOrder.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
-				bulkPurchaseOrder.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
-				bulkPurchaseOrder.FirstPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
+				bulkPurchaseOrder.TrackingStatus = enums.BulkPoTrackingStatusFirstPayment
 			}
 			err = bulkPurchaseOrder.UpdatePrices()
 			if err != nil {
@@ -2442,6 +2399,17 @@ func (r *BulkPurchaseOrderRepo) UploadExcel(params UploadExcelParams) ([]*models
 				return err
 			}
 
+			if record.FirstPaymentPercentage == 0 {
+				err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+					JwtClaimsInfo: params.JwtClaimsInfo,
+					Order:         &bulkPurchaseOrder,
+					Action:        enums.BulkPoTrackingActionSkipFirstPayment,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
 			bulkPurchaseOrder.PurchaseOrder = &purchaseOrder
 
 			bulks = append(bulks, &bulkPurchaseOrder)
@@ -3155,9 +3123,6 @@ func (r *BulkPurchaseOrderRepo) CreateMultipleBulkPurchaseOrders(req *models.Cre
 				ShippingFee:   &req.ShippingFee,
 			},
 		}
-		if req.FirstPaymentPercentage == 0 {
-			bulkCreate.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
-		}
 		// update bulk prices
 		var subTotalPrice = price.NewFromFloat(0)
 		bulkQuotation, ok := lo.Find(bulk.Quotations, func(item *models.InquiryQuotationItem) bool {
@@ -3214,6 +3179,17 @@ func (r *BulkPurchaseOrderRepo) CreateMultipleBulkPurchaseOrders(req *models.Cre
 		if err := tx.Create(&bulksToCreate).Error; err != nil {
 			return err
 		}
+		if req.FirstPaymentPercentage == 0 {
+			for _, bulk := range bulksToCreate {
+				if err := r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+					JwtClaimsInfo: req.JwtClaimsInfo,
+					Order:         bulk,
+					Action:        enums.BulkPoTrackingActionSkipFirstPayment,
+				}); err != nil {
+					return err
+				}
+			}
+		}
 		if len(purchaseOrdersToCreate) > 0 {
 			if err := tx.Create(&purchaseOrdersToCreate).Error; err != nil {
 				return err
@@ -3242,29 +3218,26 @@ func (r *BulkPurchaseOrderRepo) AdminSellerBulkPurchaseOrderUploadPo(params Admi
 		return nil, err
 	}
 
-	var checkStatuses = []enums.SellerBulkPoTrackingStatus{
-		enums.SellerBulkPoTrackingStatusPORejected,
-		enums.SellerBulkPoTrackingStatusPO,
+	var updates = models.BulkPurchaseOrder{
+		SellerPoAttachments: &params.SellerPoAttachments,
 	}
 
-	if !lo.Contains(checkStatuses, order.SellerTrackingStatus) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		return NewSellerBulkPurchaseOrderRepo(r.db).TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionAdminUploadSellerPo,
+			Updates:       &updates,
+		})
+	})
+	if eris.Is(err, errs.ErrBulkPoInvalidTransition) {
 		return nil, errs.ErrBulkPoNotAbleToUpdatePO
 	}
-	var updates = models.BulkPurchaseOrder{
-		SellerPoAttachments:  &params.SellerPoAttachments,
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPO,
-	}
-
-	err = r.db.Model(&models.BulkPurchaseOrder{}).
-		Where("id = ?", params.BulkPurchaseOrderID).
-		Updates(&updates).Error
-
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
 	order.SellerPoAttachments = updates.SellerPoAttachments
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
 
 	return order, err
 }
@@ -3288,6 +3261,13 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 		if bulk.TrackingStatus != enums.BulkPoTrackingStatusWaitingForQuotation {
 			return nil, eris.Wrapf(errs.ErrBulkPoInvalidToSendQuotationToBuyer, "bulk_id:%s", bulk.ID)
 		}
+		transition, err := FindBulkPurchaseOrderTransition(bulk.TrackingStatus, enums.BulkPoTrackingActionSubmitQuotation)
+		if err != nil {
+			return nil, eris.Wrapf(err, "bulk_id:%s", bulk.ID)
+		}
+		if err = transition.Validate(bulk, bulk, req.GetRole()); err != nil {
+			return nil, eris.Wrapf(err, "bulk_id:%s", bulk.ID)
+		}
 	}
 
 	var orderItems models.OrderCartItems
@@ -3348,7 +3328,7 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 	var purchaseOrdersToUpdate = make(models.PurchaseOrders, 0, len(req.Quotations))
 	var orderCartItemsToUpdate = make(models.OrderCartItems, 0, len(orderItems))
 
-	var bulkTrackings []*models.BulkPurchaseOrderTracking
+	var fxRateRepo = NewFxRateRepo(r.db)
 
 	for _, quotation := range req.Quotations {
 		bulk, ok := lo.Find(bulks, func(item *models.BulkPurchaseOrder) bool {
@@ -3371,10 +3351,6 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 		bulk.QuotationNote = quotation.QuotationNote
 		bulk.QuotationNoteAttachments = quotation.QuotationNoteAttachments
 		bulk.QuotationAt = values.Int64(time.Now().Unix())
-		bulk.TrackingStatus = enums.BulkPoTrackingStatusFirstPayment
-		if quotation.FirstPaymentPercentage == 0 {
-			bulk.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
-		}
 
 		//update bulk checkout prices
 		var subTotalPrice = price.NewFromFloat(0)
@@ -3402,6 +3378,7 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 		if err := bulk.UpdatePrices(); err != nil {
 			return nil, err
 		}
+		fxRateRepo.GetFxSnapshot(bulk.Currency, nil).ApplyTo(&bulk.Pricing, bulk.Pricing)
 
 		bulksToUpdate = append(bulksToUpdate, bulk)
 
@@ -3440,21 +3417,10 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 			purchaseOrdersToUpdate = append(purchaseOrdersToUpdate, purchaseOrder)
 		}
 
-		bulkTrackings = append(bulkTrackings, &models.BulkPurchaseOrderTracking{
-			PurchaseOrderID: bulk.ID,
-			ActionType:      enums.BulkPoTrackingActionSubmitQuotation,
-			UserID:          bulk.UserID,
-			CreatedByUserID: req.GetUserID(),
-			Description:     "Admin has sent quotation to buyer",
-			Metadata: &models.PoTrackingMetadata{
-				After: map[string]interface{}{
-					"quotations": bulk.AdminQuotations,
-				},
-			},
-		})
 	}
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Clauses(clause.OnConflict{
+		// The tracking status is only moved by the transitions below
+		if err := tx.Omit("TrackingStatus").Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "id"}},
 			UpdateAll: true,
 		}).Create(&bulksToUpdate).Error; err != nil {
@@ -3477,7 +3443,34 @@ func (r *BulkPurchaseOrderRepo) SubmitMultipleBulkQuotations(req *models.SubmitM
 			return err
 		}
 
-		return tx.Create(&bulkTrackings).Error
+		for _, bulk := range bulksToUpdate {
+			if err := r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: req.JwtClaimsInfo,
+				Order:         bulk,
+				Action:        enums.BulkPoTrackingActionSubmitQuotation,
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Description: "Admin has sent quotation to buyer",
+					Metadata: &models.PoTrackingMetadata{
+						After: map[string]interface{}{
+							"quotations": bulk.AdminQuotations,
+						},
+					},
+				},
+			}); err != nil {
+				return eris.Wrapf(err, "bulk_id:%s", bulk.ID)
+			}
+
+			if values.Float64Value(bulk.FirstPaymentPercentage) == 0 {
+				if err := r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+					JwtClaimsInfo: req.JwtClaimsInfo,
+					Order:         bulk,
+					Action:        enums.BulkPoTrackingActionSkipFirstPayment,
+				}); err != nil {
+					return eris.Wrapf(err, "bulk_id:%s", bulk.ID)
+				}
+			}
+		}
+		return nil
 	}); err != nil {
 		return nil, err
 	}
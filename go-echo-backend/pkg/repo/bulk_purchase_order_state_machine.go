@@ -0,0 +1,497 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+	"github.com/thaitanloi365/go-utils/values"
+	"gorm.io/gorm"
+)
+
+// BulkPurchaseOrderTransition one row of the bulk order tracking state machine.
+// Empty To keeps the current tracking status, used by update actions which are
+// allowed in several stages.
+type BulkPurchaseOrderTransition struct {
+	Action enums.BulkPoTrackingAction    `json:"action"`
+	From   []enums.BulkPoTrackingStatus  `json:"from"`
+	To     enums.BulkPoTrackingStatus    `json:"to,omitempty"`
+	Actors []enums.BulkPoTransitionActor `json:"actors"`
+
+	Guard  func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error `json:"-"`
+	Effect func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder)       `json:"-"`
+}
+
+var (
+	bulkPoActorsAdmin         = []enums.BulkPoTransitionActor{enums.BulkPoTransitionActorAdmin, enums.BulkPoTransitionActorSystem}
+	bulkPoActorsAdminAndBuyer = []enums.BulkPoTransitionActor{enums.BulkPoTransitionActorAdmin, enums.BulkPoTransitionActorBuyer, enums.BulkPoTransitionActorSystem}
+)
+
+// BulkPurchaseOrderTransitions is the only source of truth for bulk order tracking status changes
+var BulkPurchaseOrderTransitions = []*BulkPurchaseOrderTransition{
+	{
+		Action: enums.BulkPoTrackingActionUpdateOrder,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusNew},
+		To:     enums.BulkPoTrackingStatusWaitingForSubmitOrder,
+		Actors: bulkPoActorsAdminAndBuyer,
+	},
+	{
+		Action: enums.BulkPoTrackingActionResetOrder,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusNew,
+			enums.BulkPoTrackingStatusWaitingForSubmitOrder,
+			enums.BulkPoTrackingStatusWaitingForQuotation,
+			enums.BulkPoTrackingStatusFirstPayment,
+		},
+		To:     enums.BulkPoTrackingStatusNew,
+		Actors: bulkPoActorsAdmin,
+		Guard: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error {
+			if order.FirstPaymentIntentID != "" || values.Int64Value(order.FirstPaymentMarkAsPaidAt) > 0 || values.Int64Value(order.FirstPaymentReceivedAt) > 0 {
+				return errs.ErrBulkPoFirstPaymentAlreaydPaid
+			}
+			return nil
+		},
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			updates.Status = enums.BulkPurchaseOrderStatusNew
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionSubmitOrder,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusNew, enums.BulkPoTrackingStatusWaitingForSubmitOrder},
+		To:     enums.BulkPoTrackingStatusWaitingForQuotation,
+		Actors: bulkPoActorsAdminAndBuyer,
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			if updates.SubmittedAt == nil {
+				updates.SubmittedAt = values.Int64(time.Now().Unix())
+			}
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionSubmitQuotation,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusNew,
+			enums.BulkPoTrackingStatusWaitingForSubmitOrder,
+			enums.BulkPoTrackingStatusWaitingForQuotation,
+			enums.BulkPoTrackingStatusFirstPayment,
+		},
+		To:     enums.BulkPoTrackingStatusFirstPayment,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		// Quotations without first payment go straight to production
+		Action: enums.BulkPoTrackingActionSkipFirstPayment,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFirstPayment},
+		To:     enums.BulkPoTrackingStatusFirstPaymentConfirmed,
+		Actors: bulkPoActorsAdmin,
+		Guard: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error {
+			if values.Float64Value(order.FirstPaymentPercentage) != 0 {
+				return errs.ErrBulkPoInvalidTransition
+			}
+			return nil
+		},
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			var now = time.Now().Unix()
+			updates.FirstPaymentPercentage = values.Float64(0)
+			if updates.FirstPaymentTransferedAt == nil {
+				updates.FirstPaymentTransferedAt = values.Int64(now)
+			}
+			if updates.FirstPaymentMarkAsPaidAt == nil {
+				updates.FirstPaymentMarkAsPaidAt = values.Int64(now)
+			}
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionMakeFirstPayment,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFirstPayment},
+		To:     enums.BulkPoTrackingStatusFirstPaymentConfirm,
+		Actors: bulkPoActorsAdminAndBuyer,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFirstPaymentConfirmed,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFirstPayment, enums.BulkPoTrackingStatusFirstPaymentConfirm},
+		To:     enums.BulkPoTrackingStatusFirstPaymentConfirmed,
+		// Buyer is allowed because a successful card payment confirms immediately
+		Actors: bulkPoActorsAdminAndBuyer,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFirstPaymentDenied,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFirstPaymentConfirm},
+		To:     enums.BulkPoTrackingStatusFirstPayment,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkRawMaterial,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusFirstPaymentConfirmed,
+			enums.BulkPoTrackingStatusSecondPaymentConfirmed,
+			enums.BulkPoTrackingStatusRawMaterial,
+		},
+		To:     enums.BulkPoTrackingStatusRawMaterial,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateMaterial,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusFirstPaymentConfirmed,
+			enums.BulkPoTrackingStatusSecondPaymentConfirmed,
+			enums.BulkPoTrackingStatusRawMaterial,
+		},
+		To:     enums.BulkPoTrackingStatusRawMaterial,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateMaterial,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusPps, enums.BulkPoTrackingStatusProduction},
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionBuyerApproveRawMaterial,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusRawMaterial,
+			enums.BulkPoTrackingStatusPps,
+			enums.BulkPoTrackingStatusProduction,
+		},
+		Actors: bulkPoActorsAdminAndBuyer,
+		Guard: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error {
+			if order.PoRawMaterials == nil || len(*order.PoRawMaterials) == 0 {
+				return errs.ErrBulkPoInvalidToApproveRawMaterial
+			}
+			return nil
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkPps,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusRawMaterial, enums.BulkPoTrackingStatusPps},
+		To:     enums.BulkPoTrackingStatusPps,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdatePps,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusRawMaterial, enums.BulkPoTrackingStatusPps},
+		To:     enums.BulkPoTrackingStatusPps,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdatePps,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusProduction},
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkProduction,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusRawMaterial,
+			enums.BulkPoTrackingStatusPps,
+			enums.BulkPoTrackingStatusProduction,
+		},
+		To:     enums.BulkPoTrackingStatusProduction,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateProduction,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusRawMaterial},
+		To:     enums.BulkPoTrackingStatusProduction,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateProduction,
+		From: []enums.BulkPoTrackingStatus{
+			enums.BulkPoTrackingStatusPps,
+			enums.BulkPoTrackingStatusProduction,
+			enums.BulkPoTrackingStatusQc,
+		},
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkQc,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusProduction, enums.BulkPoTrackingStatusQc},
+		To:     enums.BulkPoTrackingStatusQc,
+		Actors: bulkPoActorsAdmin,
+		Guard:  bulkPoGuardRawMaterialApproved,
+	},
+	{
+		Action: enums.BulkPoTrackingActionCreateQcReport,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusProduction, enums.BulkPoTrackingStatusQc},
+		To:     enums.BulkPoTrackingStatusQc,
+		Actors: bulkPoActorsAdmin,
+		Guard:  bulkPoGuardRawMaterialApproved,
+	},
+	{
+		Action: enums.BulkPoTrackingActionBuyerApproveQc,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusQc},
+		To:     enums.BulkPoTrackingStatusSubmit,
+		Actors: bulkPoActorsAdminAndBuyer,
+		Guard:  bulkPoGuardRawMaterialApproved,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkFinalPayment,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusQc, enums.BulkPoTrackingStatusSubmit},
+		To:     enums.BulkPoTrackingStatusFinalPayment,
+		Actors: bulkPoActorsAdmin,
+		Guard:  bulkPoGuardRawMaterialApproved,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMakeFinalPayment,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFinalPayment},
+		To:     enums.BulkPoTrackingStatusFinalPaymentConfirm,
+		Actors: bulkPoActorsAdminAndBuyer,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFinalPaymentConfirmed,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFinalPayment, enums.BulkPoTrackingStatusFinalPaymentConfirm},
+		To:     enums.BulkPoTrackingStatusFinalPaymentConfirmed,
+		Actors: bulkPoActorsAdminAndBuyer,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFinalPaymentDenied,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFinalPaymentConfirm},
+		To:     enums.BulkPoTrackingStatusFinalPayment,
+		Actors: bulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkDelivering,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusFinalPaymentConfirm, enums.BulkPoTrackingStatusFinalPaymentConfirmed},
+		To:     enums.BulkPoTrackingStatusDelivering,
+		Actors: bulkPoActorsAdmin,
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			if updates.DeliveryStartedAt == nil {
+				updates.DeliveryStartedAt = values.Int64(time.Now().Unix())
+			}
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionConfirmDelivered,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusDelivering},
+		To:     enums.BulkPoTrackingStatusDeliveryConfirmed,
+		Actors: bulkPoActorsAdminAndBuyer,
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			if updates.ReceiverConfirmedAt == nil {
+				updates.ReceiverConfirmedAt = values.Int64(time.Now().Unix())
+			}
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionDelivered,
+		From:   []enums.BulkPoTrackingStatus{enums.BulkPoTrackingStatusDelivering},
+		To:     enums.BulkPoTrackingStatusDelivered,
+		Actors: bulkPoActorsAdmin,
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			if updates.DeliveredAt == nil {
+				updates.DeliveredAt = values.Int64(time.Now().Unix())
+			}
+		},
+	},
+}
+
+// bulkPoGuardRawMaterialApproved QC can't start before buyer approved every raw material,
+// an order without raw materials has nothing approved yet
+func bulkPoGuardRawMaterialApproved(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error {
+	if order.PoRawMaterials == nil || len(*order.PoRawMaterials) == 0 {
+		return errs.ErrBulkPoRawMaterialNotApproved
+	}
+
+	for _, item := range *order.PoRawMaterials {
+		if !values.BoolValue(item.BuyerApproved) {
+			return errs.ErrBulkPoRawMaterialNotApproved
+		}
+	}
+
+	return nil
+}
+
+// FindBulkPurchaseOrderTransition returns the transition of action which is allowed from status
+func FindBulkPurchaseOrderTransition(status enums.BulkPoTrackingStatus, action enums.BulkPoTrackingAction) (*BulkPurchaseOrderTransition, error) {
+	if status == "" {
+		status = enums.BulkPoTrackingStatusNew
+	}
+
+	for _, transition := range BulkPurchaseOrderTransitions {
+		if transition.Action == action && lo.Contains(transition.From, status) {
+			return transition, nil
+		}
+	}
+
+	return nil, errs.ErrBulkPoInvalidTransition
+}
+
+// FindBulkPurchaseOrderTransitionTo returns the transition which moves status to the target status,
+// used by endpoints which only know the target status
+func FindBulkPurchaseOrderTransitionTo(status enums.BulkPoTrackingStatus, to enums.BulkPoTrackingStatus) (*BulkPurchaseOrderTransition, error) {
+	if status == "" {
+		status = enums.BulkPoTrackingStatusNew
+	}
+
+	for _, transition := range BulkPurchaseOrderTransitions {
+		if transition.To == to && lo.Contains(transition.From, status) {
+			return transition, nil
+		}
+	}
+
+	return nil, errs.ErrBulkPoInvalidTransition
+}
+
+// ToStatus returns the status the order will have after the transition
+func (t *BulkPurchaseOrderTransition) ToStatus(current enums.BulkPoTrackingStatus) enums.BulkPoTrackingStatus {
+	if t.To == "" {
+		return current
+	}
+	return t.To
+}
+
+func (t *BulkPurchaseOrderTransition) IsAllowedFor(role enums.Role) bool {
+	return lo.Contains(t.Actors, enums.BulkPoTransitionActorFromRole(role))
+}
+
+// Validate checks actor and guard, it doesn't touch the database
+func (t *BulkPurchaseOrderTransition) Validate(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder, role enums.Role) error {
+	if !t.IsAllowedFor(role) {
+		return errs.ErrBulkPoTransitionForbidden
+	}
+
+	if t.Guard != nil {
+		return t.Guard(order, updates)
+	}
+
+	return nil
+}
+
+type BulkPurchaseOrderTransitionParams struct {
+	models.JwtClaimsInfo
+
+	Order   *models.BulkPurchaseOrder
+	Action  enums.BulkPoTrackingAction
+	Updates *models.BulkPurchaseOrder
+
+	// Tracking overrides description, attachments and metadata of the tracking row
+	Tracking models.BulkPurchaseOrderTrackingCreateForm
+
+	// SkipTracking when the caller writes its own tracking rows
+	SkipTracking bool
+}
+
+// TransitionTx validates the action against the transition table, records the tracking row and
+// applies the updates in the caller's transaction. The update is conditional on the status the
+// order was read with so two concurrent transitions can't both win.
+// On success order.TrackingStatus holds the new status.
+func (r *BulkPurchaseOrderRepo) TransitionTx(tx *gorm.DB, params BulkPurchaseOrderTransitionParams) error {
+	var order = params.Order
+	var updates = params.Updates
+	if updates == nil {
+		updates = &models.BulkPurchaseOrder{}
+	}
+
+	transition, err := FindBulkPurchaseOrderTransition(order.TrackingStatus, params.Action)
+	if err != nil {
+		return err
+	}
+
+	if err = transition.Validate(order, updates, params.GetRole()); err != nil {
+		return err
+	}
+
+	var fromStatus = order.TrackingStatus
+	updates.TrackingStatus = transition.ToStatus(fromStatus)
+	if transition.Effect != nil {
+		transition.Effect(order, updates)
+	}
+
+	if !params.SkipTracking {
+		var tracking = params.Tracking
+		tracking.PurchaseOrderID = order.ID
+		tracking.ActionType = params.Action
+		tracking.FromStatus = fromStatus
+		tracking.ToStatus = updates.TrackingStatus
+		if tracking.UserID == "" {
+			tracking.UserID = order.UserID
+		}
+		if tracking.CreatedByUserID == "" {
+			tracking.CreatedByUserID = params.GetUserID()
+		}
+		if tracking.Metadata == nil && fromStatus != updates.TrackingStatus {
+			tracking.Metadata = &models.PoTrackingMetadata{
+				Before: map[string]interface{}{
+					"tracking_status": fromStatus,
+				},
+				After: map[string]interface{}{
+					"tracking_status": updates.TrackingStatus,
+				},
+			}
+		}
+
+		if err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, tracking); err != nil {
+			return err
+		}
+	}
+
+	var result = tx.Model(&models.BulkPurchaseOrder{}).
+		Where("id = ? AND tracking_status = ?", order.ID, fromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return eris.Wrap(result.Error, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrBulkPoTrackingStatusChanged
+	}
+
+	order.TrackingStatus = updates.TrackingStatus
+	return nil
+}
+
+// TransitionEachTx applies the action planned for each order of a multi order checkout,
+// orders without an action keep their status
+func (r *BulkPurchaseOrderRepo) TransitionEachTx(tx *gorm.DB, claims models.JwtClaimsInfo, orders []*models.BulkPurchaseOrder, actions map[string]enums.BulkPoTrackingAction) error {
+	for _, order := range orders {
+		action, ok := actions[order.ID]
+		if !ok {
+			continue
+		}
+
+		var err = r.TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: claims,
+			Order:         order,
+			Action:        action,
+		})
+		if err != nil {
+			return eris.Wrapf(err, "bulk_id:%s", order.ID)
+		}
+	}
+
+	return nil
+}
+
+// BulkPurchaseOrderStateDiagram renders the transition table as a mermaid state diagram
+func BulkPurchaseOrderStateDiagram() string {
+	var builder strings.Builder
+	builder.WriteString("stateDiagram-v2\n")
+	builder.WriteString(fmt.Sprintf("    [*] --> %s\n", enums.BulkPoTrackingStatusNew))
+
+	var lines = map[string][]string{}
+	var keys []string
+	for _, transition := range BulkPurchaseOrderTransitions {
+		for _, from := range transition.From {
+			var key = fmt.Sprintf("%s --> %s", from, transition.ToStatus(from))
+			if _, ok := lines[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			var label = string(transition.Action)
+			if transition.Guard != nil {
+				label += " [guarded]"
+			}
+			lines[key] = append(lines[key], label)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		builder.WriteString(fmt.Sprintf("    %s : %s\n", key, strings.Join(lo.Uniq(lines[key]), ", ")))
+	}
+	builder.WriteString(fmt.Sprintf("    %s --> [*]\n", enums.BulkPoTrackingStatusDelivered))
+
+	return builder.String()
+}
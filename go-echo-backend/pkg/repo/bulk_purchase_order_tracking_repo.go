@@ -36,18 +36,22 @@ func (r *BulkPurchaseOrderTrackingRepo) CreateBulkPurchaseOrderTrackingTx(tx *go
 	oneRowLogActions := []enums.BulkPoTrackingAction{enums.BulkPoTrackingActionUpdatePps, enums.BulkPoTrackingActionUpdateProduction}
 	if ok := slices.Contains(oneRowLogActions, params.ActionType); ok {
 		err = tx.Model(&models.BulkPurchaseOrderTracking{}).Where("purchase_order_id = ? AND action_type = ?", params.PurchaseOrderID, params.ActionType).Assign(form).FirstOrCreate(&form).Error
-		return err
+	} else {
+		err = tx.Omit(clause.Associations).Create(&form).Error
 	}
-
-	err = tx.Omit(clause.Associations).Create(&form).Error
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
-	return err
+
+	// Push the change to the clients following the order
+	return NewOutboxRepo(r.db).AddTasksTx(tx, form.PurchaseOrderID, models.NewTopicEvent(
+		models.NewTopic(enums.TopicKindBulkPurchaseOrder, form.PurchaseOrderID), enums.TopicEventTypeTrackingCreated, form,
+	))
 }
 
 type PaginateBulkPurchaseOrderTrackingParams struct {
 	models.PaginationParams
+	models.CursorParams
 	models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string                    `json:"bulk_purchase_order_id" query:"bulk_purchase_order_id" form:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
@@ -58,6 +62,13 @@ type PaginateBulkPurchaseOrderTrackingParams struct {
 }
 
 func (r *BulkPurchaseOrderTrackingRepo) PaginateBulkPurchaseOrderTrackings(params PaginateBulkPurchaseOrderTrackingParams) *query.Pagination {
+	// The admin tracking tables keep the offset pages with the exact total
+	var countMode = query.CountModeEstimate
+	if params.GetRole().IsAdmin() {
+		params.CursorParams = models.CursorParams{}
+		countMode = query.CountModeExact
+	}
+
 	var result = query.New(r.db, queryfunc.NewBulkPurchaseOrderTrackingBuilder(queryfunc.BulkPurchaseOrderTrackingBuilderOptions{
 		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
 			Role: params.GetRole(),
@@ -82,6 +93,9 @@ func (r *BulkPurchaseOrderTrackingRepo) PaginateBulkPurchaseOrderTrackings(param
 				})
 			}
 		}).
+		Cursor(params.Cursor, query.CursorKey{Column: "pot.created_at", Field: "CreatedAt", Desc: true}, query.CursorKey{Column: "pot.id", Field: "ID", Desc: true}).
+		WithCursorPaging(params.IsCursorPaging()).
+		WithCountMode(countMode).
 		Limit(params.Limit).
 		Page(params.Page).
 		PagingFunc()
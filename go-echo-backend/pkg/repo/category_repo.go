@@ -1,10 +1,13 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/engineeringinflow/inflow-backend/pkg/caching"
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
@@ -123,7 +126,17 @@ func (r *CategoryRepo) GetCategories(params PaginateCategoriesParams) []*models.
 	return result
 }
 
+// GetCategoryTree cached until a category is written, product totals may lag by the TTL
 func (r *CategoryRepo) GetCategoryTree(params PaginateCategoriesParams) models.CategoryTreeResponse {
+	var key = fmt.Sprintf("category_tree_%s_%d_%t_%t", params.GetRole(), params.Limit, params.OrderByTotalProduct, params.ParentOnly)
+	response, _ := caching.GetOrLoad(context.Background(), r.db.Cache, key, func() (models.CategoryTreeResponse, error) {
+		return r.getCategoryTree(params), nil
+	}, caching.WithTags(models.CacheTagCategory), caching.WithTTL(time.Minute*5))
+
+	return response
+}
+
+func (r *CategoryRepo) getCategoryTree(params PaginateCategoriesParams) models.CategoryTreeResponse {
 	var result = r.GetCategoriesV0(params)
 
 	var response models.CategoryTreeResponse
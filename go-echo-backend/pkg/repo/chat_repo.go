@@ -2,6 +2,7 @@ package repo
 
 import (
 	"errors"
+	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/errs"
@@ -14,6 +15,7 @@ import (
 	"github.com/jinzhu/copier"
 	"github.com/rotisserie/eris"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChatRepo struct {
@@ -49,10 +51,33 @@ func (r *ChatRepo) CreateChatMessage(req *models.CreateChatMessageRequest) (*mod
 		return nil, eris.Wrap(err, "copy attribute error")
 	}
 
+	if req.ReplyToID != "" {
+		var replyTo models.ChatMessage
+		if err := r.db.Select("ID", "ReceiverID", "ThreadID", "RemovedAt").First(&replyTo, "id = ?", req.ReplyToID).Error; err != nil {
+			if r.db.IsRecordNotFoundError(err) {
+				return nil, errs.ErrChatMessageReplyInvalid
+			}
+			return nil, err
+		}
+		if replyTo.ReceiverID != req.ReceiverID {
+			return nil, errs.ErrChatMessageReplyInvalid
+		}
+		if replyTo.RemovedAt != nil {
+			return nil, errs.ErrChatMessageRemoved
+		}
+		message.ThreadID = replyTo.GetThreadID()
+	}
+
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&message).Error; err != nil {
 			return err
 		}
+		if message.ThreadID != "" {
+			if err := tx.Model(&models.ChatMessage{}).Where("id = ?", message.ThreadID).
+				UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error; err != nil {
+				return err
+			}
+		}
 		err := tx.Select("ID").First(&models.ChatRoomUser{}, "room_id = ? and user_id = ?", req.ReceiverID, req.SenderID).Error
 		if err != nil {
 			if !r.db.IsRecordNotFoundError(err) {
@@ -89,15 +114,247 @@ func (r *ChatRepo) GetMessageList(params *models.GetMessageListRequest) (*query.
 	var result = query.New(r.db, builder).
 		WhereFunc(func(builder *query.Builder) {
 			builder.Where("c.receiver_id = ? ", chatRoom.ID)
+			if params.ThreadID != "" {
+				builder.Where("(c.id = ? OR c.thread_id = ?)", params.ThreadID, params.ThreadID)
+			}
 		}).
+		Cursor(params.Cursor, query.CursorKey{Column: "c.created_at", Field: "CreatedAt", Desc: true}, query.CursorKey{Column: "c.id", Field: "ID", Desc: true}).
+		WithCursorPaging(params.IsCursorPaging()).
+		WithCountMode(query.CountModeNone).
 		Page(params.Page).
 		Limit(params.Limit).
-		WithoutCount(true).
 		PagingFunc()
 
 	return result, nil
 }
 
+// UpdateChatMessage edits the message, the previous content is kept in the edit history
+func (r *ChatRepo) UpdateChatMessage(params *models.UpdateChatMessageRequest) (*models.ChatMessage, error) {
+	if params.Message == "" && params.Attachments == nil {
+		return nil, errs.ErrChatMessageEmpty
+	}
+
+	message, err := r.getChatMessage(params.ChatMessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var now = time.Now()
+	if err = message.CheckChange(params.GetUserID(), params.GetRole(), enums.ChatMessageEditActionEdit, now); err != nil {
+		return nil, err
+	}
+
+	var editedAt = now.Unix()
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var edit = models.ChatMessageEdit{
+			MessageID:   message.ID,
+			UserID:      params.GetUserID(),
+			Action:      enums.ChatMessageEditActionEdit,
+			Message:     message.Message,
+			Attachments: message.Attachments,
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		var updates = models.ChatMessage{
+			Message:     params.Message,
+			Attachments: params.Attachments,
+			EditedAt:    &editedAt,
+		}
+		return tx.Model(&models.ChatMessage{}).
+			Select(params.GetUpdatedFields()).
+			Where("id = ?", message.ID).
+			Updates(&updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The text and the attachments left out of the request are kept
+	if params.Message != "" {
+		message.Message = params.Message
+	}
+	if params.Attachments != nil {
+		message.Attachments = params.Attachments
+	}
+	message.EditedAt = &editedAt
+
+	return message, nil
+}
+
+// DeleteChatMessage removes the message for everyone, the content is kept in the edit history
+func (r *ChatRepo) DeleteChatMessage(params *models.DeleteChatMessageRequest) (*models.ChatMessage, error) {
+	message, err := r.getChatMessage(params.ChatMessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var now = time.Now()
+	if err = message.CheckChange(params.GetUserID(), params.GetRole(), enums.ChatMessageEditActionDelete, now); err != nil {
+		return nil, err
+	}
+
+	var removedAt = now.Unix()
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var edit = models.ChatMessageEdit{
+			MessageID:   message.ID,
+			UserID:      params.GetUserID(),
+			Action:      enums.ChatMessageEditActionDelete,
+			Message:     message.Message,
+			Attachments: message.Attachments,
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&models.ChatMessageReaction{}, "message_id = ?", message.ID).Error; err != nil {
+			return err
+		}
+
+		if message.ThreadID != "" {
+			if err := tx.Model(&models.ChatMessage{}).Where("id = ?", message.ThreadID).
+				UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count - 1, 0)")).Error; err != nil {
+				return err
+			}
+		}
+
+		var updates = models.ChatMessage{
+			RemovedAt:       &removedAt,
+			RemovedByUserID: params.GetUserID(),
+		}
+		return tx.Model(&models.ChatMessage{}).
+			Select("Message", "Attachments", "RemovedAt", "RemovedByUserID").
+			Where("id = ?", message.ID).
+			Updates(&updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message.Message = ""
+	message.Attachments = nil
+	message.Reactions = nil
+	message.RemovedAt = &removedAt
+	message.RemovedByUserID = params.GetUserID()
+
+	return message, nil
+}
+
+// AddChatMessageReaction reacts to the message with the emoji, reacting twice with the same emoji is a no-op
+func (r *ChatRepo) AddChatMessageReaction(params *models.ReactChatMessageRequest) (*models.ChatMessage, error) {
+	message, err := r.getReactableChatMessage(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var reaction = models.ChatMessageReaction{
+		MessageID: message.ID,
+		UserID:    params.GetUserID(),
+		Emoji:     params.Emoji,
+	}
+	if err = r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error; err != nil {
+		return nil, err
+	}
+
+	message.Reactions, err = r.GetChatMessageReactions(message.ID)
+	return message, err
+}
+
+// RemoveChatMessageReaction removes the emoji of the user from the message
+func (r *ChatRepo) RemoveChatMessageReaction(params *models.ReactChatMessageRequest) (*models.ChatMessage, error) {
+	message, err := r.getReactableChatMessage(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Unscoped().
+		Delete(&models.ChatMessageReaction{}, "message_id = ? AND user_id = ? AND emoji = ?", message.ID, params.GetUserID(), params.Emoji).Error
+	if err != nil {
+		return nil, err
+	}
+
+	message.Reactions, err = r.GetChatMessageReactions(message.ID)
+	return message, err
+}
+
+// GetChatMessageReactions reactions of the message grouped by emoji
+func (r *ChatRepo) GetChatMessageReactions(messageID string) ([]*models.ChatMessageReactionSummary, error) {
+	var reactions models.ChatMessageReactions
+	if err := r.db.Select("MessageID", "UserID", "Emoji").Order("created_at ASC").Find(&reactions, "message_id = ?", messageID).Error; err != nil {
+		return nil, err
+	}
+
+	return reactions.Summaries(), nil
+}
+
+// GetChatMessageEdits edit history of the message, oldest first
+func (r *ChatRepo) GetChatMessageEdits(params *models.GetChatMessageEditsRequest) ([]*models.ChatMessageEdit, error) {
+	if _, err := r.getChatMessage(params.ChatMessageID); err != nil {
+		return nil, err
+	}
+
+	var edits []*models.ChatMessageEdit
+	if err := r.db.Order("created_at ASC").Find(&edits, "message_id = ?", params.ChatMessageID).Error; err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+	for _, edit := range edits {
+		userIDs = append(userIDs, edit.UserID)
+	}
+	if len(userIDs) > 0 {
+		var users []*models.User
+		if err := r.db.Select("ID", "Name", "Avatar", "Role").Find(&users, "id IN ?", userIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, edit := range edits {
+			for _, user := range users {
+				if edit.UserID == user.ID {
+					edit.User = user
+				}
+			}
+		}
+	}
+
+	return edits, nil
+}
+
+func (r *ChatRepo) getChatMessage(messageID string) (*models.ChatMessage, error) {
+	var message models.ChatMessage
+	if err := r.db.First(&message, "id = ?", messageID).Error; err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrChatMessageNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// getReactableChatMessage message which is not removed, in a room of the user unless an admin reacts
+func (r *ChatRepo) getReactableChatMessage(params *models.ReactChatMessageRequest) (*models.ChatMessage, error) {
+	message, err := r.getChatMessage(params.ChatMessageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.RemovedAt != nil {
+		return nil, errs.ErrChatMessageRemoved
+	}
+
+	if !params.GetRole().IsAdmin() {
+		isRoomUser, err := NewChatRoomRepo(r.db).IsChatRoomUser(message.ReceiverID, params.GetUserID())
+		if err != nil {
+			return nil, err
+		}
+		if !isRoomUser {
+			return nil, errs.ErrChatRoomUserForbidden
+		}
+	}
+
+	return message, nil
+}
+
 func (r *ChatRepo) GetChatRelevantStage(params *models.GetChatUserRelevantStageRequest) ([]*models.ChatRoomStatus, error) {
 	if err := validateGetChatRelevantStage(params); err != nil {
 		return nil, err
@@ -242,6 +242,26 @@ func (r *ChatRoomRepo) MarkSeenChatRoomMessage(params *models.MarkSeenChatRoomMe
 	return err
 }
 
+// IsChatRoomUser the user is a participant of the room
+func (r *ChatRoomRepo) IsChatRoomUser(roomID, userID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.ChatRoomUser{}).Where("room_id = ? AND user_id = ?", roomID, userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetChatRoomUserIDs participants of the room
+func (r *ChatRoomRepo) GetChatRoomUserIDs(roomID string) ([]string, error) {
+	var userIDs []string
+	if err := r.db.Model(&models.ChatRoomUser{}).Select("UserID").Find(&userIDs, "room_id = ?", roomID).Error; err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 func (r *ChatRoomRepo) CountUnSeenChatMessage(params *models.CountUnSeenChatMessageRequest) (int, error) {
 	countSql := `SELECT COUNT(1) FROM (
 		select 1
@@ -307,7 +307,7 @@ func (r *DataAnalyticRepo) TopProducts(params DataAnalyticTopProductsParam) (pag
 type DataAnalyticTopCategoriesParam struct {
 	models.PaginationParams
 	models.JwtClaimsInfo
-	Select      []string `json:"select,omitempty" query:"select" form:"select" param:"select"`
+	Select      []string `json:"select,omitempty" query:"select" form:"select" param:"select" validate:"dive,oneof=category sub_category"`
 	SubCategory string   `json:"sub_category" query:"sub_category" form:"sub_category" param:"sub_category"`
 	OrderBy     string   `json:"order_by" query:"order_by" form:"order_by" param:"order_by" validate:"required,oneof=sold rev"`
 	DateFrom    int64    `json:"date_from" query:"date_from" form:"date_from" param:"date_from"`
@@ -337,13 +337,13 @@ func (r *DataAnalyticRepo) TopCategories(params DataAnalyticTopCategoriesParam)
 	var result = query.New(r.adb, builder).
 		WhereFunc(func(builder *query.Builder) {
 			builder.Where("p.created_at >= ?", params.DateFrom).Where("p.created_at <= ?", params.DateTo)
-			if len(params.Select) > 0 {
-				builder.Where(fmt.Sprintf("%s != ''", strings.Join(params.Select, ",")))
+			var columns = queryfunc.SafeIdentifiers(params.Select, queryfunc.RankDACategoryColumns...)
+			for _, column := range columns {
+				builder.Where(fmt.Sprintf("%s != ''", column))
 			}
-			if keyword := strings.TrimSpace(params.Keyword); keyword != "" {
-				var q = "%" + keyword + "%"
-				sel := strings.Join(params.Select, ",")
-				builder.Where(fmt.Sprintf("%s ILIKE @keyword", sel), sql.Named("keyword", q))
+			if keyword := strings.TrimSpace(params.Keyword); keyword != "" && len(columns) > 0 {
+				var q = "%" + queryfunc.EscapeLike(keyword) + "%"
+				builder.Where(fmt.Sprintf("concat_ws(' ', %s) ILIKE @keyword", strings.Join(columns, ", ")), sql.Named("keyword", q))
 			}
 		}).
 		Page(params.Page).
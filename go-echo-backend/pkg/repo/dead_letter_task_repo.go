@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"gorm.io/gorm/clause"
+)
+
+type DeadLetterTaskRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewDeadLetterTaskRepo(db *db.DB) *DeadLetterTaskRepo {
+	return &DeadLetterTaskRepo{
+		db:     db,
+		logger: logger.New("repo/DeadLetterTask"),
+	}
+}
+
+type PaginateDeadLetterTasksParams struct {
+	models.PaginationParams
+	models.JwtClaimsInfo
+
+	Statuses      []enums.DeadLetterStatus `json:"statuses" query:"statuses"`
+	TaskName      string                   `json:"task_name" query:"task_name"`
+	Queue         string                   `json:"queue" query:"queue"`
+	ReferenceType string                   `json:"reference_type" query:"reference_type"`
+	ReferenceID   string                   `json:"reference_id" query:"reference_id"`
+	FailedFrom    int64                    `json:"failed_from" query:"failed_from"`
+	FailedTo      int64                    `json:"failed_to" query:"failed_to"`
+}
+
+func (r *DeadLetterTaskRepo) PaginateDeadLetterTasks(params PaginateDeadLetterTasksParams) *query.Pagination {
+	var builder = queryfunc.NewDeadLetterTaskBuilder(queryfunc.DeadLetterTaskBuilderOptions{
+		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+			Role: params.GetRole(),
+		},
+	})
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var result = query.New(r.db, builder).
+		WhereFunc(func(builder *query.Builder) {
+			if len(params.Statuses) > 0 {
+				builder.Where("d.status IN ?", params.Statuses)
+			}
+
+			if params.TaskName != "" {
+				builder.Where("d.task_name = ?", params.TaskName)
+			}
+
+			if params.Queue != "" {
+				builder.Where("d.queue = ?", params.Queue)
+			}
+
+			if params.ReferenceType != "" {
+				builder.Where("d.reference_type = ?", params.ReferenceType)
+			}
+
+			if params.ReferenceID != "" {
+				builder.Where("d.reference_id = ?", params.ReferenceID)
+			}
+
+			if params.FailedFrom > 0 {
+				builder.Where("d.failed_at >= ?", params.FailedFrom)
+			}
+
+			if params.FailedTo > 0 {
+				builder.Where("d.failed_at <= ?", params.FailedTo)
+			}
+		}).
+		Page(params.Page).
+		Limit(params.Limit).
+		PagingFunc()
+
+	return result
+}
+
+type GetDeadLetterTaskParams struct {
+	models.JwtClaimsInfo
+
+	DeadLetterTaskID string `json:"dead_letter_task_id" param:"dead_letter_task_id" validate:"required"`
+}
+
+func (r *DeadLetterTaskRepo) GetDeadLetterTask(params GetDeadLetterTaskParams) (*models.DeadLetterTask, error) {
+	var record models.DeadLetterTask
+	var err = r.db.First(&record, "id = ?", params.DeadLetterTaskID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrDeadLetterTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+type UpdateDeadLetterTaskPayloadParams struct {
+	models.JwtClaimsInfo
+
+	DeadLetterTaskID string          `json:"dead_letter_task_id" param:"dead_letter_task_id" validate:"required"`
+	Payload          json.RawMessage `json:"payload" validate:"required" swaggertype:"object"`
+}
+
+// UpdateDeadLetterTaskPayload fixes the payload before a replay, the payload recorded by asynq is kept on the first edit
+func (r *DeadLetterTaskRepo) UpdateDeadLetterTaskPayload(params UpdateDeadLetterTaskPayloadParams) (*models.DeadLetterTask, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params.Payload, &fields); err != nil {
+		return nil, errs.ErrDeadLetterTaskPayload
+	}
+
+	record, err := r.GetDeadLetterTask(GetDeadLetterTaskParams{DeadLetterTaskID: params.DeadLetterTaskID})
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Status != enums.DeadLetterStatusArchived {
+		return nil, errs.ErrDeadLetterTaskNotReplayable
+	}
+
+	var updates = map[string]interface{}{
+		"payload":           params.Payload,
+		"edited_by_user_id": params.GetUserID(),
+		"edited_at":         time.Now().Unix(),
+	}
+	if len(record.OriginalPayload) == 0 {
+		updates["original_payload"] = record.Payload
+	}
+
+	var result = r.db.Model(record).Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", record.ID, enums.DeadLetterStatusArchived).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errs.ErrDeadLetterTaskNotReplayable
+	}
+
+	return record, nil
+}
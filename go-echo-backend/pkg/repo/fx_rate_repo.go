@@ -0,0 +1,173 @@
+package repo
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm/clause"
+)
+
+type FxRateRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewFxRateRepo(db *db.DB) *FxRateRepo {
+	return &FxRateRepo{
+		db:     db,
+		logger: logger.New("repo/FxRate"),
+	}
+}
+
+type GetFxRatesParams struct {
+	models.JwtClaimsInfo
+
+	BaseCurrency  enums.Currency `json:"base_currency" query:"base_currency"`
+	QuoteCurrency enums.Currency `json:"quote_currency" query:"quote_currency"`
+	DateFrom      int64          `json:"date_from" query:"date_from"`
+	DateTo        int64          `json:"date_to" query:"date_to"`
+}
+
+func (r *FxRateRepo) GetFxRates(params GetFxRatesParams) (models.FxRates, error) {
+	var result models.FxRates
+	var query = r.db.Model(&models.FxRate{})
+	if params.BaseCurrency != "" {
+		query = query.Where("base_currency = ?", params.BaseCurrency)
+	}
+	if params.QuoteCurrency != "" {
+		query = query.Where("quote_currency = ?", params.QuoteCurrency)
+	}
+	if params.DateFrom > 0 {
+		query = query.Where("effective_at >= ?", params.DateFrom)
+	}
+	if params.DateTo > 0 {
+		query = query.Where("effective_at <= ?", params.DateTo)
+	}
+
+	var err = query.Order("effective_at DESC, base_currency ASC, quote_currency ASC").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type CreateFxRateParams struct {
+	models.JwtClaimsInfo
+
+	BaseCurrency  enums.Currency `json:"base_currency" validate:"required,oneof=USD SGD VND"`
+	QuoteCurrency enums.Currency `json:"quote_currency" validate:"required,oneof=USD SGD VND"`
+	Rate          float64        `json:"rate" validate:"required,gt=0"`
+	EffectiveAt   int64          `json:"effective_at"` // Defaults to now
+}
+
+// CreateFxRate adds a manually maintained rate, a rate of the same pair and effective time is overwritten
+func (r *FxRateRepo) CreateFxRate(params CreateFxRateParams) (*models.FxRate, error) {
+	if params.BaseCurrency == params.QuoteCurrency {
+		return nil, errs.ErrFxRateInvalidPair
+	}
+
+	var rate = models.FxRate{
+		BaseCurrency:    params.BaseCurrency,
+		QuoteCurrency:   params.QuoteCurrency,
+		Rate:            params.Rate,
+		EffectiveAt:     params.EffectiveAt,
+		Source:          enums.FxRateSourceManual,
+		CreatedByUserID: params.GetUserID(),
+	}
+	if rate.EffectiveAt == 0 {
+		rate.EffectiveAt = time.Now().Unix()
+	}
+
+	var err = r.upsert(models.FxRates{&rate})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+type ImportFxRatesParams struct {
+	models.JwtClaimsInfo
+
+	File *multipart.FileHeader `json:"-"`
+}
+
+// ImportFxRates imports base_currency,quote_currency,rate,effective_date rows, the whole file is rejected on the first invalid row
+func (r *FxRateRepo) ImportFxRates(params ImportFxRatesParams) (models.FxRates, error) {
+	file, err := params.File.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rates, err := models.ParseFxRatesCSV(file)
+	if err != nil {
+		return nil, eris.Wrap(errs.ErrFxRateInvalidCSV, err.Error())
+	}
+	if len(rates) == 0 {
+		return nil, eris.Wrap(errs.ErrFxRateInvalidCSV, "file has no rows")
+	}
+
+	for _, rate := range rates {
+		rate.CreatedByUserID = params.GetUserID()
+	}
+
+	err = r.upsert(rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+type DeleteFxRateParams struct {
+	models.JwtClaimsInfo
+
+	FxRateID string `json:"fx_rate_id" param:"fx_rate_id" validate:"required"`
+}
+
+// DeleteFxRate removes a mistyped rate as long as no document snapshotted it
+func (r *FxRateRepo) DeleteFxRate(params DeleteFxRateParams) error {
+	for _, model := range []interface{}{&models.Inquiry{}, &models.PurchaseOrder{}, &models.BulkPurchaseOrder{}, &models.Invoice{}} {
+		var count int64
+		var err = r.db.Model(model).Unscoped().Where("fx_rate_id = ?", params.FxRateID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errs.ErrFxRateDeleteLocked
+		}
+	}
+
+	return r.db.Unscoped().Delete(&models.FxRate{}, "id = ?", params.FxRateID).Error
+}
+
+// GetFxSnapshot returns locked when it carries a rate, otherwise the current rate of currency.
+// A missing rate doesn't block quotations or payments, the document is left without converted totals.
+func (r *FxRateRepo) GetFxSnapshot(currency enums.Currency, locked *models.FxSnapshot) *models.FxSnapshot {
+	if !locked.IsEmpty() {
+		return locked
+	}
+
+	snapshot, err := models.NewFxSnapshotTx(r.db.DB, currency, time.Now().Unix())
+	if err != nil {
+		r.logger.Warnf("No FX rate for %s/%s err=%+v", models.FxBaseCurrency, currency, err)
+		return nil
+	}
+
+	return snapshot
+}
+
+func (r *FxRateRepo) upsert(rates models.FxRates) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "created_by_user_id", "updated_at"}),
+	}).Create(&rates).Error
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// IdempotencyKeyDefaultWindow used when IDEMPOTENCY_KEY_WINDOW is not set
+	IdempotencyKeyDefaultWindow = time.Hour * 24
+
+	// idempotencyKeyProcessingTimeout a key still processing after this is left by a crashed request and can be claimed again
+	idempotencyKeyProcessingTimeout = time.Minute * 5
+)
+
+type IdempotencyKeyRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewIdempotencyKeyRepo(db *db.DB) *IdempotencyKeyRepo {
+	return &IdempotencyKeyRepo{
+		db:     db,
+		logger: logger.New("repo/IdempotencyKey"),
+	}
+}
+
+type BeginIdempotentRequestParams struct {
+	UserID      string
+	Key         string
+	Method      string
+	Path        string
+	Fingerprint string
+}
+
+// BeginIdempotentRequest claims the key for the request. When the key was already used it returns the stored response,
+// or ErrIdempotencyKeyReused for a different request and ErrIdempotencyKeyInProgress while the first request is running.
+func (r *IdempotencyKeyRepo) BeginIdempotentRequest(params BeginIdempotentRequestParams) (claimed *models.IdempotencyKey, completed *models.IdempotencyKey, err error) {
+	var now = time.Now()
+	var window = r.db.Configuration.IdempotencyKeyWindow
+	if window <= 0 {
+		window = IdempotencyKeyDefaultWindow
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Unscoped().
+			Where("user_id = ? AND key = ?", params.UserID, params.Key).
+			Where("expires_at < ? OR (status = ? AND updated_at < ?)", now.Unix(), enums.IdempotencyKeyStatusProcessing, now.Add(-idempotencyKeyProcessingTimeout).Unix()).
+			Delete(&models.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		var record = models.IdempotencyKey{
+			Key:         params.Key,
+			UserID:      params.UserID,
+			Method:      params.Method,
+			Path:        params.Path,
+			Fingerprint: params.Fingerprint,
+			Status:      enums.IdempotencyKeyStatusProcessing,
+			ExpiresAt:   now.Add(window).Unix(),
+		}
+		record.ID = helper.GenerateXID()
+
+		var result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			claimed = &record
+			return nil
+		}
+
+		var existing models.IdempotencyKey
+		err = tx.Where("user_id = ? AND key = ?", params.UserID, params.Key).First(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing.Fingerprint != params.Fingerprint {
+			return errs.ErrIdempotencyKeyReused
+		}
+
+		if existing.Status != enums.IdempotencyKeyStatusCompleted {
+			return errs.ErrIdempotencyKeyInProgress
+		}
+
+		completed = &existing
+		return nil
+	})
+	if err != nil {
+		return nil, nil, eris.Wrapf(err, "begin idempotent request key=%s", params.Key)
+	}
+
+	return
+}
+
+// CompleteIdempotentRequest stores the response replayed to the retries
+func (r *IdempotencyKeyRepo) CompleteIdempotentRequest(id string, status int, header json.RawMessage, body []byte) error {
+	var err = r.db.Model(&models.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          enums.IdempotencyKeyStatusCompleted,
+			"response_status": status,
+			"response_header": header,
+			"response_body":   body,
+		}).Error
+	if err != nil {
+		return eris.Wrapf(err, "complete idempotent request id=%s", id)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotentRequest frees the key of a failed request so it can be retried
+func (r *IdempotencyKeyRepo) ReleaseIdempotentRequest(id string) error {
+	var err = r.db.Unscoped().Delete(&models.IdempotencyKey{}, "id = ?", id).Error
+	if err != nil {
+		return eris.Wrapf(err, "release idempotent request id=%s", id)
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes the keys past their window
+func (r *IdempotencyKeyRepo) DeleteExpiredIdempotencyKeys() (int64, error) {
+	var result = r.db.Unscoped().Delete(&models.IdempotencyKey{}, "expires_at < ?", time.Now().Unix())
+	if result.Error != nil {
+		return 0, eris.Wrap(result.Error, "delete expired idempotency keys")
+	}
+
+	return result.RowsAffected, nil
+}
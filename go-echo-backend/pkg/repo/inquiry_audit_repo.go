@@ -10,6 +10,7 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
 	"github.com/jinzhu/copier"
 	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -32,10 +33,21 @@ func (r *InquiryAuditRepo) CreateInquiryAudit(params models.InquiryAuditCreateFo
 		return eris.Wrap(err, "")
 	}
 
-	err = r.db.Omit(clause.Associations).Create(&form).Error
-	if err != nil {
-		return eris.Wrap(err, "")
-	}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err = tx.Omit(clause.Associations).Create(&form).Error
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+
+		if form.InquiryID == "" {
+			return nil
+		}
+
+		// Push the change to the clients following the inquiry
+		return NewOutboxRepo(r.db).AddTasksTx(tx, form.InquiryID, models.NewTopicEvent(
+			models.NewTopic(enums.TopicKindInquiry, form.InquiryID), enums.TopicEventTypeAuditCreated, form,
+		))
+	})
 	return err
 }
 
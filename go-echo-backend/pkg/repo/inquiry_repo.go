@@ -148,13 +148,8 @@ func (r *InquiryRepo) PaginateInquiry(params PaginateInquiryParams) *query.Pagin
 			}
 
 			if keyword := strings.TrimSpace(params.Keyword); keyword != "" {
-				var q = "%" + keyword + "%"
-				if strings.HasPrefix(keyword, "IQ") {
-					builder.Where("iq.reference_id ILIKE ?", q)
-				} else {
-					builder.Where("(iq.id ILIKE @keyword OR iq.title ILIKE @keyword OR iq.sku_note ILIKE @keyword)", sql.Named("keyword", q))
-
-				}
+				var condition, args = inquiriesSearchDocument.keywordCondition("iq", keyword)
+				builder.Where(fmt.Sprintf("(iq.id = @inquiry_id OR %s)", condition), append(args, sql.Named("inquiry_id", keyword))...)
 			}
 		}).
 		OrderBy("iq.updated_at DESC, iq.order_group_id ASC").
@@ -528,6 +523,9 @@ func (r *InquiryRepo) SendInquiryToBuyer(form models.SendInquiryToBuyerForm) (*S
 		ProductWeight:        form.ProductWeight,
 		AssigneeIDs:          lo.Union(append(inquiry.AssigneeIDs, form.GetUserID())),
 	}
+	if fx := NewFxRateRepo(r.db).GetFxSnapshot(inquiry.Currency, nil); fx != nil {
+		inquiryUpdates.FxSnapshot = *fx
+	}
 
 	err = r.db.Clauses(clause.OnConflict{UpdateAll: true}).Where("id = ?", inquiry.ID).Updates(inquiryUpdates).Error
 	if err != nil {
@@ -541,6 +539,7 @@ func (r *InquiryRepo) SendInquiryToBuyer(form models.SendInquiryToBuyerForm) (*S
 	inquiry.BuyerQuotationStatus = inquiryUpdates.BuyerQuotationStatus
 	inquiry.ShippingFee = inquiryUpdates.ShippingFee
 	inquiry.ProductWeight = inquiryUpdates.ProductWeight
+	inquiry.FxSnapshot = inquiryUpdates.FxSnapshot
 
 	_, _ = r.InquiryPreviewCheckout(InquiryPreviewCheckoutParams{
 		InquiryID:     inquiry.ID,
@@ -599,11 +598,15 @@ func (r *InquiryRepo) AdminSubmitQuotation(form models.SendInquiryToBuyerForm) (
 		ProductWeight:               form.ProductWeight,
 		AssigneeIDs:                 lo.Union(append(inquiry.AssigneeIDs, form.GetUserID())),
 	}
+	if fx := NewFxRateRepo(r.db).GetFxSnapshot(inquiry.Currency, nil); fx != nil {
+		inquiryUpdates.FxSnapshot = *fx
+	}
 
 	err = r.db.Clauses(clause.OnConflict{UpdateAll: true}).Where("id = ?", inquiry.ID).Updates(inquiryUpdates).Error
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
+	inquiry.FxSnapshot = inquiryUpdates.FxSnapshot
 
 	var resp = AdminSubmitQuotationResponse{
 		Inquiry: inquiry,
@@ -619,7 +622,7 @@ func (r *InquiryRepo) AdminSubmitMultipleInquiryQuotations(req *models.SubmitMul
 		inquiryIDs = append(inquiryIDs, quotation.InquiryID)
 	}
 	var inquiries models.Inquiries
-	if err := r.db.Select("ID", "BuyerQuotationStatus", "AssigneeIDs", "UserID").Find(&inquiries, "id IN ?", inquiryIDs).Error; err != nil {
+	if err := r.db.Select("ID", "BuyerQuotationStatus", "AssigneeIDs", "UserID", "Currency").Find(&inquiries, "id IN ?", inquiryIDs).Error; err != nil {
 		return nil, err
 	}
 	var dbInquiryIDs = inquiries.IDs()
@@ -645,6 +648,7 @@ func (r *InquiryRepo) AdminSubmitMultipleInquiryQuotations(req *models.SubmitMul
 		}
 	}
 	var inquiriesToUpdate []*models.Inquiry
+	var fxRateRepo = NewFxRateRepo(r.db)
 
 	for _, quotation := range req.Quotations {
 		inquiry, _ := lo.Find(inquiries, func(item *models.Inquiry) bool {
@@ -664,6 +668,9 @@ func (r *InquiryRepo) AdminSubmitMultipleInquiryQuotations(req *models.SubmitMul
 			ProductWeight:               quotation.ProductWeight,
 			AssigneeIDs:                 lo.Union(append(inquiry.AssigneeIDs, req.GetUserID())),
 		}
+		if fx := fxRateRepo.GetFxSnapshot(inquiry.Currency, nil); fx != nil {
+			inquiryUpdate.FxSnapshot = *fx
+		}
 		// inject additional attributes to return
 		user := mapUserIDToUser[inquiry.UserID]
 		inquiryUpdate.User = user
@@ -684,6 +691,10 @@ func (r *InquiryRepo) AdminSubmitMultipleInquiryQuotations(req *models.SubmitMul
 				"tax_percentage",
 				"product_weight",
 				"assignee_ids",
+				"fx_rate_id",
+				"fx_base_currency",
+				"fx_rate",
+				"fx_rated_at",
 			})}).Create(inquiriesToUpdate).Error; err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
@@ -1111,6 +1122,7 @@ func (r *InquiryRepo) InquiryCheckout(params InquiryCheckoutParams) (*models.Pur
 				PaymentTransactionReferenceID: transaction.ReferenceID,
 			}
 			updates.TaxPercentage = purchaseOrder.Inquiry.TaxPercentage
+			NewFxRateRepo(r.db).GetFxSnapshot(purchaseOrder.Inquiry.Currency, &purchaseOrder.Inquiry.FxSnapshot).ApplyTo(&updates.Pricing, purchaseOrder.Pricing)
 			var sqlResult = tx.Model(&models.PurchaseOrder{}).Where("id = ?", purchaseOrder.ID).Updates(&updates)
 			if sqlResult.Error != nil {
 				return eris.Wrap(sqlResult.Error, sqlResult.Error.Error())
@@ -1222,6 +1234,7 @@ PaymentSuccess:
 	}
 
 	updates.TaxPercentage = purchaseOrder.Inquiry.TaxPercentage
+	NewFxRateRepo(r.db).GetFxSnapshot(purchaseOrder.Inquiry.Currency, &purchaseOrder.Inquiry.FxSnapshot).ApplyTo(&updates.Pricing, purchaseOrder.Pricing)
 	err = r.db.Transaction(func(tx *gorm.DB) error {
 
 		// create transaction
@@ -2483,6 +2496,7 @@ func (r *InquiryRepo) InquirySyncSample(form InquirySyncSampleParams) (*models.P
 			MarkAsPaidAt:          values.Int64(time.Now().Unix()),
 		}
 		updates.TaxPercentage = purchaseOrder.Inquiry.TaxPercentage
+		NewFxRateRepo(r.db).GetFxSnapshot(purchaseOrder.Inquiry.Currency, &purchaseOrder.Inquiry.FxSnapshot).ApplyTo(&updates.Pricing, purchaseOrder.Pricing)
 
 		if len(purchaseOrder.Quotations) > 0 {
 			sampleQuotation, _ := lo.Find(purchaseOrder.Quotations, func(item *models.InquiryQuotationItem) bool {
@@ -2856,6 +2870,7 @@ func (r *InquiryRepo) MultiInquiryCheckout(params MultiInquiryCheckoutParams) (*
 					PaymentTransactionReferenceID: transactionRefID,
 				}
 				updates.TaxPercentage = purchaseOrder.Inquiry.TaxPercentage
+				NewFxRateRepo(r.db).GetFxSnapshot(purchaseOrder.Inquiry.Currency, &purchaseOrder.Inquiry.FxSnapshot).ApplyTo(&updates.Pricing, purchaseOrder.Pricing)
 				var sqlResult = tx.Model(&models.PurchaseOrder{}).Where("id = ?", purchaseOrder.ID).Updates(&updates)
 				if sqlResult.Error != nil {
 					return eris.Wrap(sqlResult.Error, sqlResult.Error.Error())
@@ -3006,6 +3021,7 @@ func (r *InquiryRepo) MultiInquiryCheckout(params MultiInquiryCheckoutParams) (*
 				}
 
 				updates.TaxPercentage = purchaseOrder.Inquiry.TaxPercentage
+				NewFxRateRepo(r.db).GetFxSnapshot(purchaseOrder.Inquiry.Currency, &purchaseOrder.Inquiry.FxSnapshot).ApplyTo(&updates.Pricing, purchaseOrder.Pricing)
 				var sqlResult = tx.Model(&models.PurchaseOrder{}).Where("id = ?", purchaseOrder.ID).Updates(&updates)
 				if sqlResult.Error != nil {
 					return sqlResult.Error
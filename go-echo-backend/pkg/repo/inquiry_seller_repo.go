@@ -338,8 +338,8 @@ func (r *InquirySellerRepo) InquirySellerAllocationSearchSeller(params InquirySe
 			builder.Where("u.role = ?", enums.RoleSeller)
 
 			if keyword := strings.TrimSpace(params.Keyword); keyword != "" {
-				var q = "%" + keyword + "%"
-				builder.Where("(u.name ILIKE @keyword OR u.company_name ILIKE @keyword OR u.email ILIKE @keyword)", sql.Named("keyword", q))
+				var condition, args = usersSearchDocument.keywordCondition("u", keyword)
+				builder.Where(condition, args...)
 			}
 		}).
 		Page(params.Page).
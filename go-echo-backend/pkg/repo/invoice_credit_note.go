@@ -0,0 +1,482 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/locker"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/engineeringinflow/inflow-backend/pkg/pdf"
+	"github.com/engineeringinflow/inflow-backend/pkg/s3"
+	"github.com/engineeringinflow/inflow-backend/pkg/stripehelper"
+	"github.com/rotisserie/eris"
+	"github.com/stripe/stripe-go/v74"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GenerateInvoiceDocument renders the invoice pdf, credit notes and void invoices are printed with their marker.
+// The document is written under the fence of the lease, a renderer whose lease expired can't overwrite a newer document.
+func (r *InvoiceRepo) GenerateInvoiceDocument(invoiceNumber int) (*models.Invoice, error) {
+	waitCtx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	lease, err := r.db.Locker.Acquire(waitCtx, fmt.Sprintf("invoice_document_%d", invoiceNumber))
+	if err != nil {
+		return nil, err
+	}
+	defer lease.Release()
+
+	var invoice models.Invoice
+	err = r.db.First(&invoice, "invoice_number = ?", invoiceNumber).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+
+	var printURL = fmt.Sprintf("%s/invoices/print/%d", r.db.Configuration.AdminPortalBaseURL, invoice.InvoiceNumber)
+	var marker = invoice.GetPDFMarker()
+	if marker != "" {
+		printURL = fmt.Sprintf("%s?marker=%s", printURL, url.QueryEscape(marker))
+	}
+
+	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
+		URL:               printURL,
+		Selector:          "#invoice-ready-to-print",
+		Landscape:         true,
+		PrintBackground:   true,
+		PreferCssPageSize: true,
+		DisableLocker:     true,
+		Context:           lease.Context(),
+	})
+	if err != nil {
+		return nil, eris.Wrapf(err, "Generate pdf invoice %d failed", invoice.InvoiceNumber)
+	}
+
+	if len(data) == 0 {
+		return nil, eris.Wrapf(errs.ErrInvoiceGeneratePdfError, "Generate pdf invoice %d empty data", invoice.InvoiceNumber)
+	}
+
+	var kind = "invoice"
+	if marker != "" {
+		kind = strings.ToLower(strings.ReplaceAll(marker, " ", "_"))
+	}
+	var uploadParams = s3.UploadFileParams{
+		Key:         fmt.Sprintf("uploads/invoice_%d_%s.pdf", invoice.InvoiceNumber, kind),
+		Data:        bytes.NewBuffer(data),
+		Bucket:      r.db.Configuration.AWSS3StorageBucket,
+		ContentType: string(models.ContentTypePDF),
+		ACL:         "private",
+	}
+
+	_, err = s3.New(r.db.Configuration).UploadFile(uploadParams)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Document = &models.Attachment{
+		ContentType: uploadParams.ContentType,
+		FileKey:     uploadParams.Key,
+	}
+	invoice.DocumentFence = lease.Token()
+	var result = r.db.Model(&models.Invoice{}).Scopes(lease.Fence("document_fence")).Where("invoice_number = ?", invoice.InvoiceNumber).
+		UpdateColumns(&models.Invoice{Document: invoice.Document, DocumentFence: invoice.DocumentFence})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, eris.Wrapf(locker.ErrLockLost, "Document of invoice %d was written by a later lease", invoice.InvoiceNumber)
+	}
+
+	return &invoice, nil
+}
+
+// findInvoicePaymentTransaction payment the invoice was issued for, milestone invoices are matched through their milestone
+func (r *InvoiceRepo) findInvoicePaymentTransaction(invoice *models.Invoice) (*models.PaymentTransaction, error) {
+	var query = r.db.Where("transaction_type <> ?", enums.TransactionTypeRefund)
+	switch {
+	case invoice.PaymentTransactionID != "":
+		query = query.Where("id = ?", invoice.PaymentTransactionID)
+	case invoice.Metadata.PaymentMilestoneID != "":
+		query = query.Where("payment_milestone_id = ?", invoice.Metadata.PaymentMilestoneID)
+	default:
+		query = query.Where("invoice_number = ?", invoice.InvoiceNumber)
+	}
+
+	var transaction models.PaymentTransaction
+	var err = query.Order("created_at DESC").First(&transaction).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// CreateCreditNote issues a credit note against a paid invoice, and optionally refunds the buyer.
+// Card payments are refunded through Stripe after the pending credit note is committed, see refundCreditNote.
+func (r *InvoiceRepo) CreateCreditNote(params models.CreateCreditNoteParams) (*models.Invoice, error) {
+	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("invoice_credit_note_%d", params.InvoiceNumber), time.Second*30)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var original models.Invoice
+	err = r.db.First(&original, "invoice_number = ?", params.InvoiceNumber).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+
+	if original.IsCreditNote() ||
+		(original.Status != enums.InvoiceStatusPaid && original.Status != enums.InvoiceStatusPartiallyRefunded) {
+		return nil, errs.ErrInvoiceNotRefundable
+	}
+
+	var refundable = original.GetRefundableAmount()
+	var amount = refundable
+	if params.Amount != nil {
+		amount = *params.Amount
+	}
+	if !amount.GreaterThan(0) || amount.GreaterThan(refundable.ToFloat64()) {
+		return nil, errs.ErrInvoiceRefundExceedBalance
+	}
+
+	var payment *models.PaymentTransaction
+	if params.Refund {
+		payment, err = r.findInvoicePaymentTransaction(&original)
+		if err != nil {
+			return nil, err
+		}
+		if payment == nil && params.TransactionRefID == "" {
+			return nil, eris.Wrap(errs.ErrInvoiceNotRefundable, "Payment of the invoice is not found")
+		}
+	}
+
+	var ratio = amount.Div(price.NewFromPtr(original.TotalPrice), true)
+	var creditNote = models.Invoice{
+		UserID:                original.UserID,
+		InvoiceType:           enums.InvoiceTypeCreditNote,
+		CreatedBy:             params.GetUserID(),
+		Metadata:              original.Metadata,
+		IssuedDate:            time.Now().Unix(),
+		Currency:              original.Currency,
+		CountryCode:           original.CountryCode,
+		Status:                enums.InvoiceStatusUnpaid,
+		Note:                  params.Reason,
+		OriginalInvoiceNumber: original.InvoiceNumber,
+		Vendor:                original.Vendor,
+		Consignee:             original.Consignee,
+		Shipper:               original.Shipper,
+		PaymentType:           original.PaymentType,
+		Items: models.InvoiceItems{
+			{
+				Description:   fmt.Sprintf("Credit for %s %s: %s", original.InvoiceType.DisplayName(), original.GetDisplayNumber(), params.Reason),
+				TotalQuantity: 1,
+				UnitPrice:     amount.ToPtr(),
+				TotalAmount:   amount.ToPtr(),
+			},
+		},
+	}
+	creditNote.Metadata.InvoiceType = enums.InvoiceTypeCreditNote
+	creditNote.SubTotal = price.NewFromPtr(original.SubTotal).Multiple(ratio).ToPtr()
+	creditNote.Tax = price.NewFromPtr(original.Tax).Multiple(ratio).ToPtr()
+	creditNote.TotalPrice = amount.ToPtr()
+	creditNote.FxSnapshot = original.FxSnapshot // Credit the amount at the rate it was invoiced at
+
+	var refundedAmount = price.NewFromPtr(original.RefundedAmount).Add(amount)
+	var originalStatus = enums.InvoiceStatusPartiallyRefunded
+	if !original.TotalPrice.GreaterThan(refundedAmount.ToFloat64()) {
+		originalStatus = enums.InvoiceStatusRefunded
+	}
+
+	var now = time.Now().Unix()
+	var refundTransaction *models.PaymentTransaction
+	if params.Refund {
+		refundTransaction = &models.PaymentTransaction{
+			ReferenceID:      helper.GeneratePaymentTransactionReferenceID(),
+			PaymentType:      enums.PaymentTypeBankTransfer,
+			UserID:           original.UserID,
+			Currency:         original.Currency,
+			PaidAmount:       amount.ToPtr(),
+			TotalAmount:      amount.ToPtr(),
+			TransactionType:  enums.TransactionTypeRefund,
+			TransactionRefID: params.TransactionRefID,
+			RefundReason:     params.Reason,
+			Status:           enums.PaymentStatusRefunded,
+			MarkAsPaidAt:     &now,
+		}
+		if payment != nil {
+			refundTransaction.PaymentType = payment.PaymentType
+			refundTransaction.PurchaseOrderID = payment.PurchaseOrderID
+			refundTransaction.BulkPurchaseOrderID = payment.BulkPurchaseOrderID
+			refundTransaction.PaymentMilestoneID = payment.PaymentMilestoneID
+			refundTransaction.PurchaseOrderIDs = payment.PurchaseOrderIDs
+			refundTransaction.BulkPurchaseOrderIDs = payment.BulkPurchaseOrderIDs
+			refundTransaction.Milestone = payment.Milestone
+			refundTransaction.PaymentIntentID = payment.PaymentIntentID
+		}
+	}
+
+	// Card refunds go through Stripe, the credit note stays pending until the refund is created
+	var isCardRefund = refundTransaction != nil && refundTransaction.PaymentType == enums.PaymentTypeCard && refundTransaction.PaymentIntentID != ""
+	switch {
+	case isCardRefund:
+		refundTransaction.Status = enums.PaymentStatusPending
+		refundTransaction.MarkAsPaidAt = nil
+	case refundTransaction != nil:
+		creditNote.Status = enums.InvoiceStatusRefunded
+	default:
+		creditNote.Status = enums.InvoiceStatusCredited
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Create(&creditNote).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", original.InvoiceNumber).
+			Updates(map[string]interface{}{
+				"refunded_amount": refundedAmount,
+				"status":          originalStatus,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		if refundTransaction == nil {
+			return nil
+		}
+
+		refundTransaction.InvoiceNumber = creditNote.InvoiceNumber
+		err = tx.Create(refundTransaction).Error
+		if err != nil {
+			return err
+		}
+
+		creditNote.PaymentTransactionID = refundTransaction.ID
+		return tx.Model(&models.Invoice{}).Where("invoice_number = ?", creditNote.InvoiceNumber).
+			UpdateColumn("payment_transaction_id", creditNote.PaymentTransactionID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if isCardRefund {
+		err = r.refundCreditNote(&original, &creditNote, refundTransaction, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The credit is committed at this point, a missing pdf can be re-created from the attachment endpoint
+	if result, err := r.GenerateInvoiceDocument(creditNote.InvoiceNumber); err != nil {
+		r.logger.ErrorAny(err)
+	} else {
+		creditNote.Document = result.Document
+	}
+
+	return &creditNote, nil
+}
+
+// refundCreditNote refunds a pending credit note through Stripe and marks it refunded.
+// A refund Stripe declines voids the credit note and gives the amount back to the original invoice, so it can be credited again.
+// Other failures leave the credit note pending, it is settled by the charge.refunded webhook, see SettleCreditNoteRefund.
+func (r *InvoiceRepo) refundCreditNote(original *models.Invoice, creditNote *models.Invoice, refundTransaction *models.PaymentTransaction, amount price.Price) error {
+	stripeConfig, err := stripehelper.GetCurrencyConfig(original.Currency.DefaultIfInvalid())
+	if err != nil {
+		return r.rollbackCreditNote(original, creditNote, refundTransaction, err)
+	}
+
+	ref, err := stripehelper.GetInstance().RefundPaymentIntent(stripehelper.RefundPaymentIntentParams{
+		PaymentIntentID: refundTransaction.PaymentIntentID,
+		Amount:          amount.MultipleInt(stripeConfig.SmallestUnitFactor).ToInt64(),
+		IdempotencyKey:  creditNote.DisplayNumber,
+		Metadata: map[string]string{
+			"invoice_number":             fmt.Sprintf("%d", original.InvoiceNumber),
+			"credit_note_number":         creditNote.DisplayNumber,
+			"payment_transaction_ref_id": refundTransaction.ReferenceID,
+		},
+	})
+	if err != nil {
+		if stripehelper.IsDefinitiveError(err) {
+			return r.rollbackCreditNote(original, creditNote, refundTransaction, err)
+		}
+
+		// The refund may have been created, retrying with the same idempotency key or the webhook settles it
+		r.logger.ErrorAny(eris.Wrapf(err, "Refund of credit note %s is pending", creditNote.DisplayNumber))
+		return nil
+	}
+
+	err = r.markCreditNoteRefunded(creditNote.InvoiceNumber, refundTransaction.ID, ref)
+	if err != nil {
+		return err
+	}
+
+	creditNote.Status = enums.InvoiceStatusRefunded
+	return nil
+}
+
+// rollbackCreditNote voids the credit note of a failed refund and gives the amount back to the original invoice
+func (r *InvoiceRepo) rollbackCreditNote(original *models.Invoice, creditNote *models.Invoice, refundTransaction *models.PaymentTransaction, refundErr error) error {
+	var now = time.Now().Unix()
+	var err = r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", creditNote.InvoiceNumber).
+			Updates(map[string]interface{}{
+				"status":      enums.InvoiceStatusVoid,
+				"voided_at":   now,
+				"void_reason": fmt.Sprintf("Refund failed: %s", refundErr.Error()),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Delete(&models.PaymentTransaction{}, "id = ?", refundTransaction.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Invoice{}).Where("invoice_number = ?", original.InvoiceNumber).
+			Updates(map[string]interface{}{
+				"refunded_amount": price.NewFromPtr(original.RefundedAmount),
+				"status":          original.Status,
+			}).Error
+	})
+	if err != nil {
+		r.logger.ErrorAny(err)
+	}
+
+	return eris.Wrap(refundErr, "Refund payment intent failed")
+}
+
+// SettleCreditNoteRefund marks the pending credit note of the Stripe refund refunded, refunds of other records are ignored
+func (r *InvoiceRepo) SettleCreditNoteRefund(ref *stripe.Refund) error {
+	var creditNoteNumber = ref.Metadata["credit_note_number"]
+	if creditNoteNumber == "" || ref.Status != stripe.RefundStatusSucceeded {
+		return nil
+	}
+
+	var creditNote models.Invoice
+	var err = r.db.Select("invoice_number", "status", "payment_transaction_id").
+		First(&creditNote, "display_number = ? AND invoice_type = ?", creditNoteNumber, enums.InvoiceTypeCreditNote).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if creditNote.Status != enums.InvoiceStatusUnpaid || creditNote.PaymentTransactionID == "" {
+		return nil
+	}
+
+	return r.markCreditNoteRefunded(creditNote.InvoiceNumber, creditNote.PaymentTransactionID, ref)
+}
+
+// markCreditNoteRefunded records the Stripe refund on the pending refund transaction and its credit note
+func (r *InvoiceRepo) markCreditNoteRefunded(creditNoteNumber int, refundTransactionID string, ref *stripe.Refund) error {
+	var now = time.Now().Unix()
+	var updates = map[string]interface{}{
+		"txn_id":          ref.ID,
+		"status":          enums.PaymentStatusRefunded,
+		"mark_as_paid_at": now,
+	}
+	if ref.Charge != nil {
+		updates["charge_id"] = ref.Charge.ID
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Model(&models.PaymentTransaction{}).
+			Where("id = ? AND status = ?", refundTransactionID, enums.PaymentStatusPending).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Invoice{}).
+			Where("invoice_number = ? AND status = ?", creditNoteNumber, enums.InvoiceStatusUnpaid).
+			UpdateColumn("status", enums.InvoiceStatusRefunded).Error
+	})
+}
+
+// VoidInvoice cancels an unpaid invoice, it keeps its number in the series but the document is zeroed
+func (r *InvoiceRepo) VoidInvoice(params models.VoidInvoiceParams) (*models.Invoice, error) {
+	var invoice models.Invoice
+	var err = r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "invoice_number = ?", params.InvoiceNumber).Error
+		if err != nil {
+			if r.db.IsRecordNotFoundError(err) {
+				return errs.ErrInvoiceNotFound
+			}
+			return err
+		}
+
+		if invoice.Status != enums.InvoiceStatusUnpaid && invoice.Status != "" {
+			return errs.ErrInvoiceCannotVoid
+		}
+
+		var now = time.Now().Unix()
+		var zero = price.NewFromFloat(0).ToPtr()
+		invoice.Metadata.VoidedTotalPrice = invoice.TotalPrice
+		for _, item := range invoice.Items {
+			item.UnitPrice = zero
+			item.TotalAmount = zero
+		}
+
+		invoice.Status = enums.InvoiceStatusVoid
+		invoice.VoidedAt = &now
+		invoice.VoidedBy = params.GetUserID()
+		invoice.VoidReason = params.Reason
+		invoice.SubTotal = zero
+		invoice.SubTotalAfterDeduction = zero
+		invoice.ShippingFee = zero
+		invoice.TransactionFee = zero
+		invoice.Tax = zero
+		invoice.TotalPrice = zero
+
+		return tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).
+			Select("Status", "VoidedAt", "VoidedBy", "VoidReason", "Metadata", "Items",
+				"SubTotal", "SubTotalAfterDeduction", "ShippingFee", "TransactionFee", "Tax", "TotalPrice").
+			Updates(&invoice).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result, err := r.GenerateInvoiceDocument(invoice.InvoiceNumber); err != nil {
+		r.logger.ErrorAny(err)
+	} else {
+		invoice.Document = result.Document
+	}
+
+	return &invoice, nil
+}
+
+type GetCreditNotesParams struct {
+	models.JwtClaimsInfo
+
+	InvoiceNumber int `json:"invoice_number" param:"invoice_number" validate:"required"`
+}
+
+func (r *InvoiceRepo) GetCreditNotes(params GetCreditNotesParams) ([]*models.Invoice, error) {
+	var result []*models.Invoice
+	var err = r.db.Order("invoice_number ASC").
+		Find(&result, "original_invoice_number = ? AND invoice_type = ?", params.InvoiceNumber, enums.InvoiceTypeCreditNote).Error
+	return result, err
+}
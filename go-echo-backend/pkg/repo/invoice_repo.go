@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -45,7 +46,9 @@ func (r *InvoiceRepo) CreateInvoice(params models.CreateInvoiceParams) (*models.
 	}
 
 	invoice.CreatedBy = params.GetUserID()
-	err = r.db.Create(&invoice).Error
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&invoice).Error
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +65,9 @@ func (r *InvoiceRepo) UpdateInvoice(params models.UpdateInvoiceParams) (*models.
 	if find.Status == enums.InvoiceStatusPaid {
 		return nil, errors.New("cannot update paid invoice")
 	}
+	if find.Status == enums.InvoiceStatusVoid {
+		return nil, errs.ErrInvoiceCannotVoid.WithMessage("cannot update void invoice")
+	}
 
 	var updateInvoice models.Invoice
 	var err = copier.Copy(&updateInvoice, &params)
@@ -113,8 +119,10 @@ func (r *InvoiceRepo) PaginateInvoices(params PaginateInvoicesParams) (result *q
 					builder.Where("iv.metadata->>'bulk_purchase_order_reference_id' = ?", q)
 				} else if strings.HasPrefix(keyword, "IQ-") {
 					builder.Where("iv.metadata->>'inquiry_reference_id' = ?", q)
-				} else {
+				} else if _, err := strconv.Atoi(keyword); err == nil {
 					builder.Where("iv.consignee->>'email' ILIKE ? OR iv.consignee->>'name' ILIKE ? OR iv.invoice_number = ?", q, q, keyword)
+				} else {
+					builder.Where("iv.consignee->>'email' ILIKE ? OR iv.consignee->>'name' ILIKE ? OR iv.display_number = ?", q, q, keyword)
 				}
 			}
 		}).
@@ -165,6 +173,7 @@ type NextInvoiceNumberParams struct {
 	models.JwtClaimsInfo
 }
 
+// NextInvoiceNumber previews the next internal invoice number, the printed number is taken from the invoice series on create
 func (r *InvoiceRepo) NextInvoiceNumber() (next int, err error) {
 	var resultID int
 	if err = r.db.Model(&models.Invoice{}).Select("invoice_number").Order("invoice_number DESC").Limit(1).First(&resultID).Error; err != nil {
@@ -186,184 +195,13 @@ type CreateBulkFinalPaymentInvoiceParams struct {
 	Invoice             *models.Invoice           `json:"-"`
 }
 
+// CreateBulkFinalPaymentInvoice issues the invoice of the final payment milestone
 func (r *InvoiceRepo) CreateBulkFinalPaymentInvoice(params CreateBulkFinalPaymentInvoiceParams) (*models.BulkPurchaseOrder, error) {
-	var bulkPO = params.Bulk
-	var err error
-	if bulkPO == nil {
-		bulkPO, err = NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
-			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-			IncludeUser:         true,
-			IncludeInvoice:      true,
-			IncludeItems:        true,
-		})
-	}
-	if err != nil {
-		return nil, err
+	if params.BulkPurchaseOrderID == "" && params.Bulk != nil {
+		params.BulkPurchaseOrderID = params.Bulk.ID
 	}
 
-	var invoice = params.Invoice
-	if params.Invoice == nil {
-		if bulkPO.FinalPaymentInvoice != nil && !params.ReCreate {
-			r.db.CustomLogger.Debugf("Bulk PO %s final payment invoice is already generated", bulkPO.ReferenceID)
-			return bulkPO, errs.ErrBulkPoInvoiceAlreadyGenerated
-		}
-
-		var invoiceParams = models.CreateInvoiceParams{
-			UserID:      bulkPO.UserID,
-			InvoiceType: enums.InvoiceTypeBulkPOFinalPayment,
-			Vendor:      models.DefaultVendorForOnlinePayment,
-			IssuedDate:  bulkPO.CommercialInvoice.IssuedDate,
-			DueDate:     bulkPO.CommercialInvoice.DueDate,
-			CountryCode: bulkPO.CommercialInvoice.CountryCode,
-			Consignee:   bulkPO.CommercialInvoice.Consignee,
-			Shipper:     bulkPO.CommercialInvoice.Shipper,
-			Status:      enums.InvoiceStatusPaid,
-			Currency:    bulkPO.CommercialInvoice.Currency,
-			// Note:                 fmt.Sprintf("Final payment, %.f", bulkPO.FirstPaymentPercentage) + "% of total payment" + "\n" + bulkPO.CommercialInvoice.Note,
-			PaymentType:          bulkPO.FinalPaymentType,
-			PaymentTransactionID: bulkPO.FinalPaymentIntentID,
-			InvoicePricing: models.InvoicePricing{
-				Pricing: models.Pricing{
-					SubTotal:               bulkPO.FinalPaymentSubTotal,
-					TransactionFee:         bulkPO.FinalPaymentTransactionFee,
-					Tax:                    bulkPO.FinalPaymentTax,
-					TotalPrice:             bulkPO.FinalPaymentTotal,
-					SubTotalAfterDeduction: bulkPO.SubTotalAfterDeduction,
-					TaxPercentage:          bulkPO.CommercialInvoice.TaxPercentage,
-					ShippingFee:            bulkPO.CommercialInvoice.ShippingFee,
-				},
-				FirstPaymentTransactionFee:  bulkPO.FirstPaymentTransactionFee,
-				FirstPaymentTax:             bulkPO.FirstPaymentTax,
-				FirstPaymentSubTotal:        bulkPO.FirstPaymentSubTotal,
-				FirstPaymentTotal:           bulkPO.FirstPaymentTotal,
-				FirstPaymentPercentage:      values.Float64Value(bulkPO.FirstPaymentPercentage),
-				SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
-				SecondPaymentTax:            bulkPO.SecondPaymentTax,
-				SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
-				SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
-				SecondPaymentPercentage:     bulkPO.SecondPaymentPercentage,
-				FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
-				FinalPaymentTax:             bulkPO.FinalPaymentTax,
-				FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
-				FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
-			},
-			Metadata: models.InvoiceMetadata{
-				InvoiceType:                  enums.InvoiceTypeBulkPOFinalPayment,
-				InquiryID:                    bulkPO.Inquiry.ID,
-				InquiryReferenceID:           bulkPO.Inquiry.ReferenceID,
-				BulkPurchaseOrderID:          bulkPO.ID,
-				BulkPurchaseOrderReferenceID: bulkPO.ReferenceID,
-				BulkPurchaseOrderCommercialInvoiceAttachment: bulkPO.CommercialInvoiceAttachment,
-				BulkEstimatedProductionLeadTime:              bulkPO.GetQuotationLeadTime(),
-			},
-		}
-
-		if bulkPO.FinalPaymentType == enums.PaymentTypeBankTransfer && bulkPO.Currency == enums.VND {
-			invoiceParams.Vendor = models.DefaultVendorForLocal
-		}
-
-		if bulkPO.FinalPaymentType == enums.PaymentTypeBankTransfer {
-			invoiceParams.PaymentTransactionID = bulkPO.FinalPaymentTransactionRefID
-		}
-
-		for _, item := range bulkPO.CommercialInvoice.Items {
-			var invoiceItem = &models.InvoiceItem{
-				ItemCode:          item.ID,
-				Color:             item.Color,
-				Size:              item.Size,
-				Description:       fmt.Sprintf("%s-%s", item.Color, item.SizeName),
-				TotalQuantity:     item.TotalQuantity,
-				UnitPrice:         item.UnitPrice.ToPtr(),
-				TotalAmount:       item.TotalAmount.ToPtr(),
-				FabricComposition: bulkPO.Inquiry.Composition,
-			}
-			if item.Size != nil {
-				invoiceItem.Description = fmt.Sprintf("%s-%s", item.Color, item.Size.GetSizeDescription())
-			}
-
-			invoiceParams.Items = append(invoiceParams.Items, invoiceItem)
-		}
-
-		if bulkPO.User != nil {
-			invoiceParams.Consignee = &models.InvoiceParty{
-				ID:          bulkPO.User.ID,
-				Name:        bulkPO.User.Name,
-				Email:       bulkPO.User.Email,
-				PhoneNumber: bulkPO.User.PhoneNumber,
-				CompanyName: bulkPO.User.CompanyName,
-			}
-
-			if bulkPO.User.Coordinate == nil && bulkPO.User.CoordinateID != "" {
-				var coordinate models.Coordinate
-				if err := r.db.First(&coordinate, "id = ?", bulkPO.User.CoordinateID).Error; err == nil {
-					invoiceParams.Consignee.Address = coordinate.Display()
-				}
-			}
-		}
-
-		invoice, err = r.CreateInvoice(invoiceParams)
-		if err != nil {
-			return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
-		}
-
-	}
-
-	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
-		URL:               fmt.Sprintf("%s/invoices/print/%d", r.db.Configuration.AdminPortalBaseURL, invoice.InvoiceNumber),
-		Selector:          "#invoice-ready-to-print",
-		Landscape:         true,
-		PrintBackground:   true,
-		PreferCssPageSize: true,
-	})
-	if err != nil {
-		return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
-	}
-
-	if len(data) == 0 {
-		return nil, eris.Errorf("Generate pdf bulk PO %s empty data", bulkPO.ReferenceID)
-	}
-
-	var uploadParams = s3.UploadFileParams{
-		Key:         fmt.Sprintf("uploads/%s_%s_%d_final_payment_invoice.pdf", bulkPO.Inquiry.ReferenceID, bulkPO.ReferenceID, invoice.InvoiceNumber),
-		Data:        bytes.NewBuffer(data),
-		Bucket:      r.db.Configuration.AWSS3StorageBucket,
-		ContentType: string(models.ContentTypePDF),
-		ACL:         "private",
-	}
-
-	_, err = s3.New(r.db.Configuration).UploadFile(uploadParams)
-	if err != nil {
-		return nil, err
-	}
-
-	var attachment = models.Attachment{
-		ContentType: uploadParams.ContentType,
-		FileKey:     uploadParams.Key,
-	}
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if bulkPO.FinalPaymentInvoice != nil && params.ReCreate {
-			err = tx.Delete(&models.Invoice{}, "invoice_number = ?", bulkPO.FinalPaymentInvoice.InvoiceNumber).Error
-			if err != nil {
-				return err
-			}
-		}
-
-		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).UpdateColumn("Document", &attachment).Error
-		if err != nil {
-			return err
-		}
-
-		bulkPO.FinalPaymentInvoice = invoice
-		bulkPO.FinalPaymentInvoice.Document = &attachment
-
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).UpdateColumn("FinalPaymentInvoiceNumber", invoice.InvoiceNumber).Error
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return bulkPO, err
+	return r.createBulkLegacyMilestoneInvoice(params.JwtClaimsInfo, params.BulkPurchaseOrderID, enums.PaymentMilestoneFinalPayment, params.ReCreate, params.Invoice)
 }
 
 type CreateBulkDepositInvoiceParams struct {
@@ -373,114 +211,72 @@ type CreateBulkDepositInvoiceParams struct {
 	Invoice             *models.Invoice `json:"-"`
 }
 
+// CreateBulkDepositInvoice issues the invoice of the deposit milestone
 func (r *InvoiceRepo) CreateBulkDepositInvoice(params CreateBulkDepositInvoiceParams) (*models.BulkPurchaseOrder, error) {
-	bulkPO, err := NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
-		JwtClaimsInfo:       params.JwtClaimsInfo,
-		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-		IncludeUser:         true,
-		IncludeItems:        true,
-		IncludeInvoice:      true,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var invoice = params.Invoice
+	return r.createBulkLegacyMilestoneInvoice(params.JwtClaimsInfo, params.BulkPurchaseOrderID, enums.PaymentMilestoneDeposit, params.ReCreate, params.Invoice)
+}
 
-	if invoice == nil {
-		if bulkPO.FirstPaymentInvoice != nil && !params.ReCreate {
-			r.db.CustomLogger.Debugf("Bulk PO %s first payment invoice is already generated", bulkPO.ReferenceID)
-			return bulkPO, errs.ErrBulkPoInvoiceAlreadyGenerated
-		}
+type CreateBulkFirstPaymentInvoiceParams struct {
+	models.JwtClaimsInfo
 
-		var invoiceParams = models.CreateInvoiceParams{
-			UserID:               bulkPO.UserID,
-			InvoiceType:          enums.InvoiceTypeBulkPODepositPayment,
-			Status:               enums.InvoiceStatusPaid,
-			Currency:             string(bulkPO.Currency),
-			Vendor:               models.DefaultVendorForOnlinePayment,
-			PaymentType:          bulkPO.FirstPaymentType,
-			PaymentTransactionID: bulkPO.FirstPaymentIntentID,
-			InvoicePricing: models.InvoicePricing{
-				Pricing: models.Pricing{
-					SubTotal:               bulkPO.SubTotal,
-					TransactionFee:         bulkPO.TransactionFee,
-					Tax:                    bulkPO.Tax,
-					TotalPrice:             bulkPO.TotalPrice,
-					TaxPercentage:          bulkPO.TaxPercentage,
-					SubTotalAfterDeduction: bulkPO.FirstPaymentSubTotal,
-				},
-				DepositPaidAmount:           bulkPO.DepositPaidAmount,
-				FirstPaymentTransactionFee:  bulkPO.FirstPaymentTransactionFee,
-				FirstPaymentTax:             bulkPO.FirstPaymentTax,
-				FirstPaymentSubTotal:        bulkPO.FirstPaymentSubTotal,
-				FirstPaymentTotal:           bulkPO.FirstPaymentTotal,
-				SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
-				SecondPaymentTax:            bulkPO.SecondPaymentTax,
-				SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
-				SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
-				FirstPaymentPercentage:      values.Float64Value(bulkPO.FirstPaymentPercentage),
-				FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
-				FinalPaymentTax:             bulkPO.FinalPaymentTax,
-				FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
-				FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
-			},
-			Metadata: models.InvoiceMetadata{
-				InvoiceType:                     enums.InvoiceTypeBulkPODepositPayment,
-				InquiryID:                       bulkPO.Inquiry.ID,
-				InquiryReferenceID:              bulkPO.Inquiry.ReferenceID,
-				BulkPurchaseOrderID:             bulkPO.ID,
-				BulkPurchaseOrderReferenceID:    bulkPO.ReferenceID,
-				BulkEstimatedProductionLeadTime: bulkPO.GetQuotationLeadTime(),
-			},
-		}
+	BulkPurchaseOrderID string          `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	ReCreate            bool            `json:"re_create"`
+	Invoice             *models.Invoice `json:"-"`
+}
 
-		if bulkPO.FirstPaymentType == enums.PaymentTypeBankTransfer && bulkPO.Currency == enums.VND {
-			invoiceParams.Vendor = models.DefaultVendorForLocal
-		}
+// CreateBulkFirstPaymentInvoice issues the invoice of the first payment milestone
+func (r *InvoiceRepo) CreateBulkFirstPaymentInvoice(params CreateBulkFirstPaymentInvoiceParams) (*models.BulkPurchaseOrder, error) {
+	return r.createBulkLegacyMilestoneInvoice(params.JwtClaimsInfo, params.BulkPurchaseOrderID, enums.PaymentMilestoneFirstPayment, params.ReCreate, params.Invoice)
+}
 
-		if bulkPO.FirstPaymentType == enums.PaymentTypeBankTransfer {
-			invoiceParams.PaymentTransactionID = bulkPO.FirstPaymentTransactionRefID
-		}
+type CreateBulkSecondPaymentInvoiceParams struct {
+	models.JwtClaimsInfo
 
-		if bulkPO.FirstPaymentReceivedAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.FirstPaymentReceivedAt
-		}
+	BulkPurchaseOrderID string          `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	ReCreate            bool            `json:"re_create"`
+	Invoice             *models.Invoice `json:"-"`
+}
 
-		if bulkPO.FirstPaymentMarkAsPaidAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.FirstPaymentMarkAsPaidAt
-		}
+// CreateBulkSecondPaymentInvoice issues the invoice of the second payment milestone
+func (r *InvoiceRepo) CreateBulkSecondPaymentInvoice(params CreateBulkSecondPaymentInvoiceParams) (*models.BulkPurchaseOrder, error) {
+	return r.createBulkLegacyMilestoneInvoice(params.JwtClaimsInfo, params.BulkPurchaseOrderID, enums.PaymentMilestoneSecondPayment, params.ReCreate, params.Invoice)
+}
 
-		if bulkPO.User != nil {
-			invoiceParams.Consignee = &models.InvoiceParty{
-				ID:          bulkPO.User.ID,
-				Name:        bulkPO.User.Name,
-				Email:       bulkPO.User.Email,
-				PhoneNumber: bulkPO.User.PhoneNumber,
-				CompanyName: bulkPO.User.CompanyName,
-			}
-			if bulkPO.User.Coordinate == nil && bulkPO.User.CoordinateID != "" {
-				var coordinate models.Coordinate
-				if err := r.db.First(&coordinate, "id = ?", bulkPO.User.CoordinateID).Error; err == nil {
-					invoiceParams.Consignee.Address = coordinate.Display()
-				}
-			}
-		}
+// createBulkLegacyMilestoneInvoice issues the invoice of the milestone behind a legacy payment through CreateBulkMilestoneInvoice,
+// the invoice is also set on the legacy field of the order for the callers reading it
+func (r *InvoiceRepo) createBulkLegacyMilestoneInvoice(claims models.JwtClaimsInfo, bulkPurchaseOrderID string, kind enums.PaymentMilestone, reCreate bool, invoice *models.Invoice) (*models.BulkPurchaseOrder, error) {
+	milestone, err := NewPaymentMilestoneRepo(r.db).FindPaymentMilestoneByKind(bulkPurchaseOrderID, kind)
+	if err != nil {
+		return nil, err
+	}
 
-		invoiceParams.Items = append(invoiceParams.Items, &models.InvoiceItem{
-			ItemCode:      bulkPO.ReferenceID,
-			Description:   bulkPO.DepositNote,
-			TotalQuantity: 1,
-			UnitPrice:     bulkPO.DepositPaidAmount,
-			TotalAmount:   bulkPO.DepositPaidAmount,
-		})
+	bulkPO, milestone, err := r.CreateBulkMilestoneInvoice(CreateBulkMilestoneInvoiceParams{
+		JwtClaimsInfo:       claims,
+		BulkPurchaseOrderID: bulkPurchaseOrderID,
+		PaymentMilestoneID:  milestone.ID,
+		ReCreate:            reCreate,
+		Invoice:             invoice,
+	})
+	if bulkPO == nil || milestone == nil || milestone.Invoice == nil {
+		return bulkPO, err
+	}
 
-		invoice, err = r.CreateInvoice(invoiceParams)
-		if err != nil {
-			return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
-		}
+	switch kind {
+	case enums.PaymentMilestoneDeposit:
+		bulkPO.DepositInvoice = milestone.Invoice
+	case enums.PaymentMilestoneFirstPayment:
+		bulkPO.FirstPaymentInvoice = milestone.Invoice
+	case enums.PaymentMilestoneSecondPayment:
+		bulkPO.SecondPaymentInvoice = milestone.Invoice
+	case enums.PaymentMilestoneFinalPayment:
+		bulkPO.FinalPaymentInvoice = milestone.Invoice
 	}
 
+	return bulkPO, err
+}
+
+// uploadBulkInvoiceDocument renders the invoice print page and stores the PDF next to the other invoices of the order
+func (r *InvoiceRepo) uploadBulkInvoiceDocument(bulkPO *models.BulkPurchaseOrder, invoice *models.Invoice, kind string) (*models.Attachment, error) {
 	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
 		URL:               fmt.Sprintf("%s/invoices/print/%d", r.db.Configuration.AdminPortalBaseURL, invoice.InvoiceNumber),
 		Selector:          "#invoice-ready-to-print",
@@ -497,7 +293,7 @@ func (r *InvoiceRepo) CreateBulkDepositInvoice(params CreateBulkDepositInvoicePa
 	}
 
 	var uploadParams = s3.UploadFileParams{
-		Key:         fmt.Sprintf("uploads/%s_%s_%d_deposit_invoice.pdf", bulkPO.Inquiry.ReferenceID, bulkPO.ReferenceID, invoice.InvoiceNumber),
+		Key:         fmt.Sprintf("uploads/%s_%s_%d_%s_invoice.pdf", bulkPO.Inquiry.ReferenceID, bulkPO.ReferenceID, invoice.InvoiceNumber, kind),
 		Data:        bytes.NewBuffer(data),
 		Bucket:      r.db.Configuration.AWSS3StorageBucket,
 		ContentType: string(models.ContentTypePDF),
@@ -509,116 +305,51 @@ func (r *InvoiceRepo) CreateBulkDepositInvoice(params CreateBulkDepositInvoicePa
 		return nil, err
 	}
 
-	var attachment = models.Attachment{
+	return &models.Attachment{
 		ContentType: uploadParams.ContentType,
 		FileKey:     uploadParams.Key,
-	}
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if bulkPO.DepositInvoice != nil && params.ReCreate {
-			err = tx.Delete(&models.Invoice{}, "invoice_number = ?", bulkPO.DepositInvoice.InvoiceNumber).Error
-			if err != nil {
-				return err
-			}
-		}
-		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).UpdateColumn("Document", &attachment).Error
-		if err != nil {
-			return err
-		}
-
-		bulkPO.DepositInvoice = invoice
-		bulkPO.DepositInvoice.Document = &attachment
-
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).UpdateColumn("DepositInvoiceNumber", invoice.InvoiceNumber).Error
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return bulkPO, err
+	}, nil
 }
 
-type CreateBulkFirstPaymentInvoiceParams struct {
+type CreateBulkMilestoneInvoiceParams struct {
 	models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string          `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	PaymentMilestoneID  string          `json:"payment_milestone_id" param:"payment_milestone_id" validate:"required"`
 	ReCreate            bool            `json:"re_create"`
 	Invoice             *models.Invoice `json:"-"`
 }
 
-func (r *InvoiceRepo) CreateBulkFirstPaymentInvoice(params CreateBulkFirstPaymentInvoiceParams) (*models.BulkPurchaseOrder, error) {
+// CreateBulkMilestoneInvoice issues the debit note of any milestone of the payment schedule
+func (r *InvoiceRepo) CreateBulkMilestoneInvoice(params CreateBulkMilestoneInvoiceParams) (*models.BulkPurchaseOrder, *models.PaymentMilestone, error) {
 	bulkPO, err := NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
 		JwtClaimsInfo:       params.JwtClaimsInfo,
 		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
 		IncludeUser:         true,
 		IncludeItems:        true,
-		IncludeInvoice:      true,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var invoice = params.Invoice
+	milestone, err := NewPaymentMilestoneRepo(r.db).GetPaymentMilestone(bulkPO.ID, params.PaymentMilestoneID)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	var previousInvoiceNumber = milestone.InvoiceNumber
+	var invoice = params.Invoice
 	if invoice == nil {
-		if bulkPO.FirstPaymentInvoice != nil && bulkPO.FirstPaymentInvoice.Document != nil && !params.ReCreate {
-			r.db.CustomLogger.Debugf("Bulk PO %s first payment invoice is already generated", bulkPO.ReferenceID)
-			return bulkPO, errs.ErrBulkPoInvoiceAlreadyGenerated
-		}
-
-		var invoiceParams = models.CreateInvoiceParams{
-			UserID:      bulkPO.UserID,
-			InvoiceType: enums.InvoiceTypeBulkPOFirstPayment,
-			Status:      enums.InvoiceStatusPaid,
-			Currency:    string(bulkPO.Currency),
-			Vendor:      models.DefaultVendorForOnlinePayment,
-			// Note:                 fmt.Sprintf("First payment, %.f", bulkPO.FirstPaymentPercentage) + "% of total payment",
-			PaymentType:          bulkPO.FirstPaymentType,
-			PaymentTransactionID: bulkPO.FirstPaymentIntentID,
-			InvoicePricing: models.InvoicePricing{
-				Pricing: models.Pricing{
-					SubTotal:               bulkPO.SubTotal,
-					TransactionFee:         bulkPO.TransactionFee,
-					Tax:                    bulkPO.Tax,
-					TotalPrice:             bulkPO.TotalPrice,
-					TaxPercentage:          bulkPO.TaxPercentage,
-					SubTotalAfterDeduction: bulkPO.FirstPaymentSubTotal,
-				},
-				FirstPaymentTransactionFee: bulkPO.FirstPaymentTransactionFee,
-				FirstPaymentTax:            bulkPO.FirstPaymentTax,
-				FirstPaymentSubTotal:       bulkPO.FirstPaymentSubTotal,
-				FirstPaymentTotal:          bulkPO.FirstPaymentTotal,
-				FirstPaymentPercentage:     values.Float64Value(bulkPO.FirstPaymentPercentage),
-				FinalPaymentTransactionFee: bulkPO.FinalPaymentTransactionFee,
-				FinalPaymentTax:            bulkPO.FinalPaymentTax,
-				FinalPaymentSubTotal:       bulkPO.FinalPaymentSubTotal,
-				FinalPaymentTotal:          bulkPO.FinalPaymentTotal,
-			},
-			Metadata: models.InvoiceMetadata{
-				InvoiceType:                     enums.InvoiceTypeBulkPOFirstPayment,
-				InquiryID:                       bulkPO.Inquiry.ID,
-				InquiryReferenceID:              bulkPO.Inquiry.ReferenceID,
-				BulkPurchaseOrderID:             bulkPO.ID,
-				BulkPurchaseOrderReferenceID:    bulkPO.ReferenceID,
-				BulkEstimatedProductionLeadTime: bulkPO.GetQuotationLeadTime(),
-			},
-		}
-
-		if bulkPO.FirstPaymentType == enums.PaymentTypeBankTransfer && bulkPO.Currency == enums.VND {
-			invoiceParams.Vendor = models.DefaultVendorForLocal
+		if previousInvoiceNumber > 0 && !params.ReCreate {
+			r.db.CustomLogger.Debugf("Bulk PO %s milestone %d invoice is already generated", bulkPO.ReferenceID, milestone.Sequence)
+			return bulkPO, milestone, errs.ErrBulkPoInvoiceAlreadyGenerated
 		}
 
-		if bulkPO.FirstPaymentType == enums.PaymentTypeBankTransfer {
-			invoiceParams.PaymentTransactionID = bulkPO.FirstPaymentTransactionRefID
-		}
-
-		if bulkPO.FirstPaymentReceivedAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.FirstPaymentReceivedAt
-		}
-
-		if bulkPO.FirstPaymentMarkAsPaidAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.FirstPaymentMarkAsPaidAt
+		var invoiceParams = legacyBulkInvoiceParams(bulkPO, milestone)
+		if invoiceParams == nil {
+			invoiceParams = milestoneInvoiceParams(bulkPO, milestone)
 		}
+		invoiceParams.Metadata.PaymentMilestoneID = milestone.ID
 
 		if bulkPO.User != nil {
 			invoiceParams.Consignee = &models.InvoiceParty{
@@ -636,250 +367,122 @@ func (r *InvoiceRepo) CreateBulkFirstPaymentInvoice(params CreateBulkFirstPaymen
 			}
 		}
 
-		for _, item := range bulkPO.Items {
-			invoiceParams.Items = append(invoiceParams.Items, &models.InvoiceItem{
-				ItemCode:      item.ID,
-				Color:         item.ColorName,
-				SizeName:      item.Size,
-				Description:   fmt.Sprintf("%s-%s", item.ColorName, item.Size),
-				TotalQuantity: item.Qty,
-				UnitPrice:     item.UnitPrice,
-				TotalAmount:   item.TotalPrice,
-			})
-		}
-
-		invoice, err = r.CreateInvoice(invoiceParams)
+		invoice, err = r.CreateInvoice(*invoiceParams)
 		if err != nil {
-			return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
+			return nil, nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
 		}
 	}
 
-	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
-		URL:               fmt.Sprintf("%s/invoices/print/%d", r.db.Configuration.AdminPortalBaseURL, invoice.InvoiceNumber),
-		Selector:          "#invoice-ready-to-print",
-		Landscape:         true,
-		PrintBackground:   true,
-		PreferCssPageSize: true,
-	})
-	if err != nil {
-		return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
-	}
-
-	if len(data) == 0 {
-		return nil, eris.Errorf("Generate pdf bulk PO %s empty data", bulkPO.ReferenceID)
+	var documentKind = fmt.Sprintf("milestone_%d", milestone.Sequence)
+	if milestone.Milestone != "" {
+		documentKind = string(milestone.Milestone)
 	}
 
-	var uploadParams = s3.UploadFileParams{
-		Key:         fmt.Sprintf("uploads/%s_%s_%d_first_payment_invoice.pdf", bulkPO.Inquiry.ReferenceID, bulkPO.ReferenceID, invoice.InvoiceNumber),
-		Data:        bytes.NewBuffer(data),
-		Bucket:      r.db.Configuration.AWSS3StorageBucket,
-		ContentType: string(models.ContentTypePDF),
-		ACL:         "private",
-	}
-
-	_, err = s3.New(r.db.Configuration).UploadFile(uploadParams)
+	attachment, err := r.uploadBulkInvoiceDocument(bulkPO, invoice, documentKind)
 	if err != nil {
-		return nil, err
-	}
-
-	var attachment = models.Attachment{
-		ContentType: uploadParams.ContentType,
-		FileKey:     uploadParams.Key,
+		return nil, nil, err
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if bulkPO.FirstPaymentInvoice != nil && params.ReCreate {
-			err = tx.Delete(&models.Invoice{}, "invoice_number = ?", bulkPO.FirstPaymentInvoice.InvoiceNumber).Error
+		if previousInvoiceNumber > 0 && previousInvoiceNumber != invoice.InvoiceNumber && params.ReCreate {
+			err = tx.Delete(&models.Invoice{}, "invoice_number = ?", previousInvoiceNumber).Error
 			if err != nil {
 				return err
 			}
 		}
-		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).UpdateColumn("Document", &attachment).Error
+
+		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).UpdateColumn("Document", attachment).Error
 		if err != nil {
 			return err
 		}
 
-		bulkPO.FirstPaymentInvoice = invoice
-		bulkPO.FirstPaymentInvoice.Document = &attachment
+		milestone.InvoiceNumber = invoice.InvoiceNumber
+		milestone.Invoice = invoice
+		milestone.Invoice.Document = attachment
 
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).UpdateColumn("FirstPaymentInvoiceNumber", invoice.InvoiceNumber).Error
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	return bulkPO, err
-}
-
-type CreateBulkSecondPaymentInvoiceParams struct {
-	models.JwtClaimsInfo
+		err = tx.Model(&models.PaymentMilestone{}).Where("id = ?", milestone.ID).UpdateColumn("InvoiceNumber", invoice.InvoiceNumber).Error
+		if err != nil {
+			return err
+		}
 
-	BulkPurchaseOrderID string          `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
-	ReCreate            bool            `json:"re_create"`
-	Invoice             *models.Invoice `json:"-"`
-}
+		var updates = milestone.LegacyColumnUpdates()
+		if len(updates) == 0 {
+			return nil
+		}
 
-func (r *InvoiceRepo) CreateBulkSecondPaymentInvoice(params CreateBulkSecondPaymentInvoiceParams) (*models.BulkPurchaseOrder, error) {
-	bulkPO, err := NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
-		JwtClaimsInfo:       params.JwtClaimsInfo,
-		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-		IncludeUser:         true,
-		IncludeItems:        true,
-		IncludeInvoice:      true,
+		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPO.ID).UpdateColumns(updates).Error
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var invoice = params.Invoice
+	bulkPO.PaymentMilestones = models.PaymentMilestones{milestone}
 
-	if invoice == nil {
-		if bulkPO.SecondPaymentInvoice != nil && !params.ReCreate {
-			r.db.CustomLogger.Debugf("Bulk PO %s second payment invoice is already generated", bulkPO.ReferenceID)
-			return bulkPO, errs.ErrBulkPoInvoiceAlreadyGenerated
-		}
+	return bulkPO, milestone, err
+}
 
-		var invoiceParams = models.CreateInvoiceParams{
-			UserID:      bulkPO.UserID,
-			InvoiceType: enums.InvoiceTypeBulkPOSecondPayment,
-			Status:      enums.InvoiceStatusPaid,
-			Currency:    string(bulkPO.Currency),
-			Vendor:      models.DefaultVendorForOnlinePayment,
-			// Note:                 fmt.Sprintf("Second payment, %.f", bulkPO.SecondPaymentPercentage) + "% of total payment",
-			PaymentType:          bulkPO.SecondPaymentType,
-			PaymentTransactionID: bulkPO.SecondPaymentIntentID,
-			InvoicePricing: models.InvoicePricing{
-				Pricing: models.Pricing{
-					SubTotal:               bulkPO.SubTotal,
-					TransactionFee:         bulkPO.TransactionFee,
-					Tax:                    bulkPO.Tax,
-					TotalPrice:             bulkPO.TotalPrice,
-					TaxPercentage:          bulkPO.TaxPercentage,
-					SubTotalAfterDeduction: bulkPO.SecondPaymentSubTotal,
-				},
-				SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
-				SecondPaymentTax:            bulkPO.SecondPaymentTax,
-				SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
-				SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
-				SecondPaymentPercentage:     bulkPO.SecondPaymentPercentage,
-				FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
-				FinalPaymentTax:             bulkPO.FinalPaymentTax,
-				FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
-				FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
-			},
-			Metadata: models.InvoiceMetadata{
-				InvoiceType:                     enums.InvoiceTypeBulkPOSecondPayment,
-				InquiryID:                       bulkPO.Inquiry.ID,
-				InquiryReferenceID:              bulkPO.Inquiry.ReferenceID,
-				BulkPurchaseOrderID:             bulkPO.ID,
-				BulkPurchaseOrderReferenceID:    bulkPO.ReferenceID,
-				BulkEstimatedProductionLeadTime: bulkPO.GetQuotationLeadTime(),
+// milestoneInvoiceParams debit note of a custom milestone, one line with the amount of the milestone
+func milestoneInvoiceParams(bulkPO *models.BulkPurchaseOrder, milestone *models.PaymentMilestone) *models.CreateInvoiceParams {
+	var invoiceParams = &models.CreateInvoiceParams{
+		UserID:               bulkPO.UserID,
+		InvoiceType:          enums.InvoiceTypeBulkPOMilestonePayment,
+		Status:               enums.InvoiceStatusUnpaid,
+		Currency:             string(bulkPO.Currency),
+		Vendor:               models.DefaultVendorForOnlinePayment,
+		PaymentType:          milestone.PaymentType,
+		PaymentTransactionID: milestone.PaymentIntentID,
+		Note:                 milestone.Note,
+		InvoicePricing: models.InvoicePricing{
+			Pricing: models.Pricing{
+				SubTotal:       milestone.SubTotal,
+				TransactionFee: milestone.TransactionFee,
+				Tax:            milestone.Tax,
+				TotalPrice:     milestone.Total,
+				TaxPercentage:  bulkPO.TaxPercentage,
 			},
-		}
-
-		if bulkPO.SecondPaymentType == enums.PaymentTypeBankTransfer && bulkPO.Currency == enums.VND {
-			invoiceParams.Vendor = models.DefaultVendorForLocal
-		}
-
-		if bulkPO.SecondPaymentType == enums.PaymentTypeBankTransfer {
-			invoiceParams.PaymentTransactionID = bulkPO.SecondPaymentTransactionRefID
-		}
-
-		if bulkPO.SecondPaymentReceivedAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.SecondPaymentReceivedAt
-		}
-
-		if bulkPO.SecondPaymentMarkAsPaidAt != nil {
-			invoiceParams.IssuedDate = *bulkPO.SecondPaymentMarkAsPaidAt
-		}
-
-		if bulkPO.User != nil {
-			invoiceParams.Consignee = &models.InvoiceParty{
-				ID:          bulkPO.User.ID,
-				Name:        bulkPO.User.Name,
-				Email:       bulkPO.User.Email,
-				PhoneNumber: bulkPO.User.PhoneNumber,
-				CompanyName: bulkPO.User.CompanyName,
-			}
-			if bulkPO.User.Coordinate == nil && bulkPO.User.CoordinateID != "" {
-				var coordinate models.Coordinate
-				if err := r.db.First(&coordinate, "id = ?", bulkPO.User.CoordinateID).Error; err == nil {
-					invoiceParams.Consignee.Address = coordinate.Display()
-				}
-			}
-		}
-
-		for _, item := range bulkPO.Items {
-			invoiceParams.Items = append(invoiceParams.Items, &models.InvoiceItem{
-				ItemCode:      item.ID,
-				Color:         item.ColorName,
-				SizeName:      item.Size,
-				Description:   fmt.Sprintf("%s-%s", item.ColorName, item.Size),
-				TotalQuantity: item.Qty,
-				UnitPrice:     item.UnitPrice,
-				TotalAmount:   item.TotalPrice,
-			})
-		}
-
-		invoice, err = r.CreateInvoice(invoiceParams)
-		if err != nil {
-			return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
-		}
+		},
+		Metadata: models.InvoiceMetadata{
+			InvoiceType:                     enums.InvoiceTypeBulkPOMilestonePayment,
+			BulkPurchaseOrderID:             bulkPO.ID,
+			BulkPurchaseOrderReferenceID:    bulkPO.ReferenceID,
+			PaymentMilestoneID:              milestone.ID,
+			BulkEstimatedProductionLeadTime: bulkPO.GetQuotationLeadTime(),
+		},
 	}
-
-	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
-		URL:               fmt.Sprintf("%s/invoices/print/%d", r.db.Configuration.AdminPortalBaseURL, invoice.InvoiceNumber),
-		Selector:          "#invoice-ready-to-print",
-		Landscape:         true,
-		PrintBackground:   true,
-		PreferCssPageSize: true,
-	})
-	if err != nil {
-		return nil, eris.Wrapf(err, "Generate pdf bulk PO %s", bulkPO.ReferenceID)
+	if bulkPO.Inquiry != nil {
+		invoiceParams.Metadata.InquiryID = bulkPO.Inquiry.ID
+		invoiceParams.Metadata.InquiryReferenceID = bulkPO.Inquiry.ReferenceID
 	}
 
-	if len(data) == 0 {
-		return nil, eris.Errorf("Generate pdf bulk PO %s empty data", bulkPO.ReferenceID)
+	if milestone.IsPaid() {
+		invoiceParams.Status = enums.InvoiceStatusPaid
+		invoiceParams.IssuedDate = *milestone.MarkAsPaidAt
 	}
 
-	var uploadParams = s3.UploadFileParams{
-		Key:         fmt.Sprintf("uploads/%s_%s_%d_second_payment_invoice.pdf", bulkPO.Inquiry.ReferenceID, bulkPO.ReferenceID, invoice.InvoiceNumber),
-		Data:        bytes.NewBuffer(data),
-		Bucket:      r.db.Configuration.AWSS3StorageBucket,
-		ContentType: string(models.ContentTypePDF),
-		ACL:         "private",
+	if milestone.DueAt != nil {
+		invoiceParams.DueDate = *milestone.DueAt
 	}
 
-	_, err = s3.New(r.db.Configuration).UploadFile(uploadParams)
-	if err != nil {
-		return nil, err
+	if milestone.PaymentType == enums.PaymentTypeBankTransfer {
+		invoiceParams.PaymentTransactionID = milestone.TransactionRefID
+		if bulkPO.Currency == enums.VND {
+			invoiceParams.Vendor = models.DefaultVendorForLocal
+		}
 	}
 
-	var attachment = models.Attachment{
-		ContentType: uploadParams.ContentType,
-		FileKey:     uploadParams.Key,
+	var description = milestone.GetDisplayName()
+	if milestone.Percentage != nil {
+		description = fmt.Sprintf("%s (%.f%% of %s)", description, *milestone.Percentage, bulkPO.ReferenceID)
 	}
-
-	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if bulkPO.SecondPaymentInvoice != nil && params.ReCreate {
-			err = tx.Delete(&models.Invoice{}, "invoice_number = ?", bulkPO.SecondPaymentInvoice.InvoiceNumber).Error
-			if err != nil {
-				return err
-			}
-		}
-		err = tx.Model(&models.Invoice{}).Where("invoice_number = ?", invoice.InvoiceNumber).UpdateColumn("Document", &attachment).Error
-		if err != nil {
-			return err
-		}
-		bulkPO.SecondPaymentInvoice = invoice
-		bulkPO.SecondPaymentInvoice.Document = &attachment
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).UpdateColumn("SecondPaymentInvoiceNumber", invoice.InvoiceNumber).Error
+	invoiceParams.Items = append(invoiceParams.Items, &models.InvoiceItem{
+		ItemCode:      bulkPO.ReferenceID,
+		Description:   description,
+		TotalQuantity: 1,
+		UnitPrice:     milestone.SubTotal,
+		TotalAmount:   milestone.SubTotal,
 	})
-	if err != nil {
-		return nil, err
-	}
 
-	return bulkPO, err
+	return invoiceParams
 }
 
 type CreatePurchaseOrderInvoiceParams struct {
@@ -1277,6 +880,14 @@ func (r *InvoiceRepo) GetInvoiceAttachment(params GetInvoiceAttachmentParams) (*
 		return invoice.Document, nil
 	}
 
+	if invoice.GetPDFMarker() != "" {
+		result, err := r.GenerateInvoiceDocument(invoice.InvoiceNumber)
+		if err != nil {
+			return nil, err
+		}
+		return result.Document, nil
+	}
+
 	switch invoice.InvoiceType {
 	case enums.InvoiceTypeBulkPODepositPayment:
 		_, err = r.CreateBulkDepositInvoice(CreateBulkDepositInvoiceParams{
@@ -1543,7 +1154,7 @@ func (r *InvoiceRepo) CreatePaymentInvoice(req *CreateOrderInvoiceRequest) (*mod
 		return nil, errs.ErrPaymentTransactionNotPaid
 	}
 	var existingInvoice models.Invoice
-	if err := r.db.Select("ID").First(&existingInvoice, "payment_transaction_id = ?", req.PaymentTransactionID).Error; err != nil {
+	if err := r.db.Select("ID", "SeriesID", "SeriesNumber", "DisplayNumber", "FxRateID", "FxBaseCurrency", "FxRate", "FxRatedAt").First(&existingInvoice, "payment_transaction_id = ?", req.PaymentTransactionID).Error; err != nil {
 		if !r.db.IsRecordNotFoundError(err) {
 			return nil, err
 		}
@@ -1562,6 +1173,9 @@ func (r *InvoiceRepo) CreatePaymentInvoice(req *CreateOrderInvoiceRequest) (*mod
 
 	var invoice = models.Invoice{
 		ID:                   existingInvoice.ID,
+		SeriesID:             existingInvoice.SeriesID,
+		SeriesNumber:         existingInvoice.SeriesNumber,
+		DisplayNumber:        existingInvoice.DisplayNumber,
 		PaymentTransactionID: payment.ID,
 		UserID:               payment.UserID,
 		Status:               enums.InvoiceStatusPaid,
@@ -1634,6 +1248,7 @@ func (r *InvoiceRepo) CreatePaymentInvoice(req *CreateOrderInvoiceRequest) (*mod
 			orderCartItems = append(orderCartItems, bulk.OrderCartItems...)
 		}
 	}
+	invoice.FxSnapshot = existingInvoice.FxSnapshot
 	invoice.SubTotal = &subTotal
 	invoice.Tax = &tax
 	invoice.ShippingFee = &shippingFee
@@ -1659,8 +1274,10 @@ func (r *InvoiceRepo) CreatePaymentInvoice(req *CreateOrderInvoiceRequest) (*mod
 		})
 	}
 
-	if err := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, UpdateAll: true}).
-		Create(&invoice).Error; err != nil {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, UpdateAll: true}).
+			Create(&invoice).Error
+	}); err != nil {
 		return nil, err
 	}
 	data, err := pdf.New(r.db.Configuration).GetPDF(pdf.GetPDFParams{
@@ -1712,3 +1329,224 @@ func (r *InvoiceRepo) CreatePaymentInvoice(req *CreateOrderInvoiceRequest) (*mod
 	return &invoice, nil
 
 }
+
+// legacyBulkInvoiceParams invoice of a deposit, first, second or final payment milestone, built with the invoice type,
+// items and pricing the invoices of these payments had before the payment schedule. Nil for the custom milestones
+func legacyBulkInvoiceParams(bulkPO *models.BulkPurchaseOrder, milestone *models.PaymentMilestone) *models.CreateInvoiceParams {
+	var invoiceParams = &models.CreateInvoiceParams{
+		UserID:   bulkPO.UserID,
+		Status:   enums.InvoiceStatusPaid,
+		Currency: string(bulkPO.Currency),
+		Vendor:   models.DefaultVendorForOnlinePayment,
+		Metadata: models.InvoiceMetadata{
+			BulkPurchaseOrderID:             bulkPO.ID,
+			BulkPurchaseOrderReferenceID:    bulkPO.ReferenceID,
+			BulkEstimatedProductionLeadTime: bulkPO.GetQuotationLeadTime(),
+		},
+	}
+	if bulkPO.Inquiry != nil {
+		invoiceParams.Metadata.InquiryID = bulkPO.Inquiry.ID
+		invoiceParams.Metadata.InquiryReferenceID = bulkPO.Inquiry.ReferenceID
+	}
+
+	var paymentType enums.PaymentType
+	var transactionRefID string
+	var receivedAt, markAsPaidAt *int64
+
+	switch milestone.Milestone {
+	case enums.PaymentMilestoneDeposit:
+		invoiceParams.InvoiceType = enums.InvoiceTypeBulkPODepositPayment
+		paymentType = bulkPO.FirstPaymentType
+		transactionRefID = bulkPO.FirstPaymentTransactionRefID
+		receivedAt, markAsPaidAt = bulkPO.FirstPaymentReceivedAt, bulkPO.FirstPaymentMarkAsPaidAt
+		invoiceParams.PaymentTransactionID = bulkPO.FirstPaymentIntentID
+		invoiceParams.InvoicePricing = models.InvoicePricing{
+			Pricing: models.Pricing{
+				SubTotal:               bulkPO.SubTotal,
+				TransactionFee:         bulkPO.TransactionFee,
+				Tax:                    bulkPO.Tax,
+				TotalPrice:             bulkPO.TotalPrice,
+				TaxPercentage:          bulkPO.TaxPercentage,
+				SubTotalAfterDeduction: bulkPO.FirstPaymentSubTotal,
+			},
+			DepositPaidAmount:           bulkPO.DepositPaidAmount,
+			FirstPaymentTransactionFee:  bulkPO.FirstPaymentTransactionFee,
+			FirstPaymentTax:             bulkPO.FirstPaymentTax,
+			FirstPaymentSubTotal:        bulkPO.FirstPaymentSubTotal,
+			FirstPaymentTotal:           bulkPO.FirstPaymentTotal,
+			SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
+			SecondPaymentTax:            bulkPO.SecondPaymentTax,
+			SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
+			SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
+			FirstPaymentPercentage:      values.Float64Value(bulkPO.FirstPaymentPercentage),
+			FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
+			FinalPaymentTax:             bulkPO.FinalPaymentTax,
+			FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
+			FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
+		}
+		invoiceParams.Items = append(invoiceParams.Items, &models.InvoiceItem{
+			ItemCode:      bulkPO.ReferenceID,
+			Description:   bulkPO.DepositNote,
+			TotalQuantity: 1,
+			UnitPrice:     bulkPO.DepositPaidAmount,
+			TotalAmount:   bulkPO.DepositPaidAmount,
+		})
+
+	case enums.PaymentMilestoneFirstPayment:
+		invoiceParams.InvoiceType = enums.InvoiceTypeBulkPOFirstPayment
+		paymentType = bulkPO.FirstPaymentType
+		transactionRefID = bulkPO.FirstPaymentTransactionRefID
+		receivedAt, markAsPaidAt = bulkPO.FirstPaymentReceivedAt, bulkPO.FirstPaymentMarkAsPaidAt
+		invoiceParams.PaymentTransactionID = bulkPO.FirstPaymentIntentID
+		invoiceParams.InvoicePricing = models.InvoicePricing{
+			Pricing: models.Pricing{
+				SubTotal:               bulkPO.SubTotal,
+				TransactionFee:         bulkPO.TransactionFee,
+				Tax:                    bulkPO.Tax,
+				TotalPrice:             bulkPO.TotalPrice,
+				TaxPercentage:          bulkPO.TaxPercentage,
+				SubTotalAfterDeduction: bulkPO.FirstPaymentSubTotal,
+			},
+			FirstPaymentTransactionFee: bulkPO.FirstPaymentTransactionFee,
+			FirstPaymentTax:            bulkPO.FirstPaymentTax,
+			FirstPaymentSubTotal:       bulkPO.FirstPaymentSubTotal,
+			FirstPaymentTotal:          bulkPO.FirstPaymentTotal,
+			FirstPaymentPercentage:     values.Float64Value(bulkPO.FirstPaymentPercentage),
+			FinalPaymentTransactionFee: bulkPO.FinalPaymentTransactionFee,
+			FinalPaymentTax:            bulkPO.FinalPaymentTax,
+			FinalPaymentSubTotal:       bulkPO.FinalPaymentSubTotal,
+			FinalPaymentTotal:          bulkPO.FinalPaymentTotal,
+		}
+		invoiceParams.Items = bulkOrderInvoiceItems(bulkPO)
+
+	case enums.PaymentMilestoneSecondPayment:
+		invoiceParams.InvoiceType = enums.InvoiceTypeBulkPOSecondPayment
+		paymentType = bulkPO.SecondPaymentType
+		transactionRefID = bulkPO.SecondPaymentTransactionRefID
+		receivedAt, markAsPaidAt = bulkPO.SecondPaymentReceivedAt, bulkPO.SecondPaymentMarkAsPaidAt
+		invoiceParams.PaymentTransactionID = bulkPO.SecondPaymentIntentID
+		invoiceParams.InvoicePricing = models.InvoicePricing{
+			Pricing: models.Pricing{
+				SubTotal:               bulkPO.SubTotal,
+				TransactionFee:         bulkPO.TransactionFee,
+				Tax:                    bulkPO.Tax,
+				TotalPrice:             bulkPO.TotalPrice,
+				TaxPercentage:          bulkPO.TaxPercentage,
+				SubTotalAfterDeduction: bulkPO.SecondPaymentSubTotal,
+			},
+			SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
+			SecondPaymentTax:            bulkPO.SecondPaymentTax,
+			SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
+			SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
+			SecondPaymentPercentage:     bulkPO.SecondPaymentPercentage,
+			FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
+			FinalPaymentTax:             bulkPO.FinalPaymentTax,
+			FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
+			FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
+		}
+		invoiceParams.Items = bulkOrderInvoiceItems(bulkPO)
+
+	case enums.PaymentMilestoneFinalPayment:
+		invoiceParams.InvoiceType = enums.InvoiceTypeBulkPOFinalPayment
+		paymentType = bulkPO.FinalPaymentType
+		transactionRefID = bulkPO.FinalPaymentTransactionRefID
+		invoiceParams.PaymentTransactionID = bulkPO.FinalPaymentIntentID
+		invoiceParams.InvoicePricing = models.InvoicePricing{
+			Pricing: models.Pricing{
+				SubTotal:               bulkPO.FinalPaymentSubTotal,
+				TransactionFee:         bulkPO.FinalPaymentTransactionFee,
+				Tax:                    bulkPO.FinalPaymentTax,
+				TotalPrice:             bulkPO.FinalPaymentTotal,
+				SubTotalAfterDeduction: bulkPO.SubTotalAfterDeduction,
+			},
+			FirstPaymentTransactionFee:  bulkPO.FirstPaymentTransactionFee,
+			FirstPaymentTax:             bulkPO.FirstPaymentTax,
+			FirstPaymentSubTotal:        bulkPO.FirstPaymentSubTotal,
+			FirstPaymentTotal:           bulkPO.FirstPaymentTotal,
+			FirstPaymentPercentage:      values.Float64Value(bulkPO.FirstPaymentPercentage),
+			SecondPaymentTransactionFee: bulkPO.SecondPaymentTransactionFee,
+			SecondPaymentTax:            bulkPO.SecondPaymentTax,
+			SecondPaymentSubTotal:       bulkPO.SecondPaymentSubTotal,
+			SecondPaymentTotal:          bulkPO.SecondPaymentTotal,
+			SecondPaymentPercentage:     bulkPO.SecondPaymentPercentage,
+			FinalPaymentTransactionFee:  bulkPO.FinalPaymentTransactionFee,
+			FinalPaymentTax:             bulkPO.FinalPaymentTax,
+			FinalPaymentSubTotal:        bulkPO.FinalPaymentSubTotal,
+			FinalPaymentTotal:           bulkPO.FinalPaymentTotal,
+		}
+		invoiceParams.Metadata.BulkPurchaseOrderCommercialInvoiceAttachment = bulkPO.CommercialInvoiceAttachment
+
+		if commercialInvoice := bulkPO.CommercialInvoice; commercialInvoice != nil {
+			invoiceParams.IssuedDate = commercialInvoice.IssuedDate
+			invoiceParams.DueDate = commercialInvoice.DueDate
+			invoiceParams.CountryCode = commercialInvoice.CountryCode
+			invoiceParams.Consignee = commercialInvoice.Consignee
+			invoiceParams.Shipper = commercialInvoice.Shipper
+			invoiceParams.Currency = commercialInvoice.Currency
+			invoiceParams.TaxPercentage = commercialInvoice.TaxPercentage
+			invoiceParams.ShippingFee = commercialInvoice.ShippingFee
+
+			for _, item := range commercialInvoice.Items {
+				var invoiceItem = &models.InvoiceItem{
+					ItemCode:      item.ID,
+					Color:         item.Color,
+					Size:          item.Size,
+					Description:   fmt.Sprintf("%s-%s", item.Color, item.SizeName),
+					TotalQuantity: item.TotalQuantity,
+					UnitPrice:     item.UnitPrice.ToPtr(),
+					TotalAmount:   item.TotalAmount.ToPtr(),
+				}
+				if bulkPO.Inquiry != nil {
+					invoiceItem.FabricComposition = bulkPO.Inquiry.Composition
+				}
+				if item.Size != nil {
+					invoiceItem.Description = fmt.Sprintf("%s-%s", item.Color, item.Size.GetSizeDescription())
+				}
+
+				invoiceParams.Items = append(invoiceParams.Items, invoiceItem)
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	invoiceParams.PaymentType = paymentType
+	invoiceParams.Metadata.InvoiceType = invoiceParams.InvoiceType
+
+	if paymentType == enums.PaymentTypeBankTransfer && bulkPO.Currency == enums.VND {
+		invoiceParams.Vendor = models.DefaultVendorForLocal
+	}
+
+	if paymentType == enums.PaymentTypeBankTransfer {
+		invoiceParams.PaymentTransactionID = transactionRefID
+	}
+
+	if receivedAt != nil {
+		invoiceParams.IssuedDate = *receivedAt
+	}
+
+	if markAsPaidAt != nil {
+		invoiceParams.IssuedDate = *markAsPaidAt
+	}
+
+	return invoiceParams
+}
+
+// bulkOrderInvoiceItems one invoice line per item of the order
+func bulkOrderInvoiceItems(bulkPO *models.BulkPurchaseOrder) []*models.InvoiceItem {
+	var items []*models.InvoiceItem
+	for _, item := range bulkPO.Items {
+		items = append(items, &models.InvoiceItem{
+			ItemCode:      item.ID,
+			Color:         item.ColorName,
+			SizeName:      item.Size,
+			Description:   fmt.Sprintf("%s-%s", item.ColorName, item.Size),
+			TotalQuantity: item.Qty,
+			UnitPrice:     item.UnitPrice,
+			TotalAmount:   item.TotalPrice,
+		})
+	}
+
+	return items
+}
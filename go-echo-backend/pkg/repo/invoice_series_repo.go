@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceSeriesRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewInvoiceSeriesRepo(db *db.DB) *InvoiceSeriesRepo {
+	return &InvoiceSeriesRepo{
+		db:     db,
+		logger: logger.New("repo/InvoiceSeries"),
+	}
+}
+
+type GetInvoiceSeriesListParams struct {
+	models.JwtClaimsInfo
+
+	InvoiceType enums.InvoiceType `json:"invoice_type" query:"invoice_type"`
+	Currency    enums.Currency    `json:"currency" query:"currency"`
+	Year        int               `json:"year" query:"year"`
+}
+
+func (r *InvoiceSeriesRepo) GetInvoiceSeriesList(params GetInvoiceSeriesListParams) (models.InvoiceSeriesList, error) {
+	var result models.InvoiceSeriesList
+	var query = r.db.Model(&models.InvoiceSeries{})
+	if params.InvoiceType != "" {
+		query = query.Where("invoice_type = ?", params.InvoiceType)
+	}
+	if params.Currency != "" {
+		query = query.Where("currency = ?", params.Currency)
+	}
+	if params.Year > 0 {
+		query = query.Where("year = ?", params.Year)
+	}
+
+	var err = query.Order("year DESC, invoice_type ASC, currency ASC").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, series := range result {
+		series.NextDisplayNumber = series.Format(series.LastNumber + 1)
+	}
+
+	return result, nil
+}
+
+type ReserveInvoiceSeriesParams struct {
+	models.JwtClaimsInfo
+
+	InvoiceType enums.InvoiceType `json:"invoice_type"`
+	Currency    enums.Currency    `json:"currency" validate:"required"`
+	Year        int               `json:"year" validate:"required,min=2000"`
+
+	Prefix      string `json:"prefix" validate:"omitempty,max=20"`
+	Region      string `json:"region" validate:"omitempty,max=10"`
+	Padding     int    `json:"padding" validate:"omitempty,min=1,max=10"`
+	StartNumber int    `json:"start_number" validate:"omitempty,min=1"` // Continue a sequence issued outside of the system
+}
+
+// ReserveInvoiceSeries creates or reconfigures a series ahead of its first invoice.
+// Once a number is issued the format and the counter are locked to keep the sequence gap-free.
+func (r *InvoiceSeriesRepo) ReserveInvoiceSeries(params ReserveInvoiceSeriesParams) (*models.InvoiceSeries, error) {
+	var series = models.NewInvoiceSeries(params.InvoiceType, params.Currency, params.Year)
+
+	var err = r.db.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(series).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(series, "invoice_type = ? AND currency = ? AND year = ?", series.InvoiceType, series.Currency, series.Year).Error
+		if err != nil {
+			return err
+		}
+
+		var issuedCount int64
+		err = tx.Model(&models.Invoice{}).Unscoped().Where("series_id = ?", series.ID).Count(&issuedCount).Error
+		if err != nil {
+			return err
+		}
+		if issuedCount > 0 {
+			return errs.ErrInvoiceSeriesAlreadyIssued
+		}
+
+		if params.Prefix != "" {
+			series.Prefix = params.Prefix
+		}
+		if params.Region != "" {
+			series.Region = params.Region
+		}
+		if params.Padding > 0 {
+			series.Padding = params.Padding
+		}
+		if params.StartNumber > 0 {
+			series.LastNumber = params.StartNumber - 1
+		}
+
+		return tx.Model(&models.InvoiceSeries{}).Where("id = ?", series.ID).
+			Select("Prefix", "Region", "Padding", "LastNumber").
+			Updates(series).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series.NextDisplayNumber = series.Format(series.LastNumber + 1)
+	return series, nil
+}
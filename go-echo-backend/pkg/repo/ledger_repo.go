@@ -0,0 +1,319 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"github.com/engineeringinflow/inflow-backend/pkg/s3"
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewLedgerRepo(db *db.DB) *LedgerRepo {
+	return &LedgerRepo{
+		db:     db,
+		logger: logger.New("repo/Ledger"),
+	}
+}
+
+type LedgerPeriodParams struct {
+	models.JwtClaimsInfo
+
+	DateFrom int64 `json:"date_from" query:"date_from" validate:"required"`
+	DateTo   int64 `json:"date_to" query:"date_to" validate:"required,gtefield=DateFrom"`
+}
+
+type PostJournalEntriesResponse struct {
+	Posted  int `json:"posted"`
+	Skipped int `json:"skipped"` // Already posted
+}
+
+// PostJournalEntries posts the entries of every source dated in the period.
+// Sources are keyed by type and ID, so posting a period again only books what is missing.
+func (r *LedgerRepo) PostJournalEntries(params LedgerPeriodParams) (*PostJournalEntriesResponse, error) {
+	cancel, err := r.db.Locker.AcquireLock("ledger_post", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	entries, err := r.collectJournalEntries(params.DateFrom, params.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp PostJournalEntriesResponse
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		for _, entry := range entries {
+			if !entry.IsBalanced() {
+				return eris.Wrapf(errs.ErrJournalEntryUnbalanced, "%s:%s", entry.SourceType, entry.SourceID)
+			}
+
+			var sqlResult = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "source_type"}, {Name: "source_id"}},
+				DoNothing: true,
+			}).Create(entry)
+			if sqlResult.Error != nil {
+				return sqlResult.Error
+			}
+			if sqlResult.RowsAffected == 0 {
+				resp.Skipped++
+				continue
+			}
+
+			for _, line := range entry.Lines {
+				line.JournalEntryID = entry.ID
+			}
+			if err := tx.Create(&entry.Lines).Error; err != nil {
+				return err
+			}
+			resp.Posted++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (r *LedgerRepo) collectJournalEntries(dateFrom, dateTo int64) (models.JournalEntries, error) {
+	var result models.JournalEntries
+
+	var invoices []*models.Invoice
+	var err = r.db.Find(&invoices, "issued_date BETWEEN ? AND ? AND ((invoice_type <> ? AND status IN ?) OR (invoice_type = ? AND status IN ?))",
+		dateFrom, dateTo,
+		enums.InvoiceTypeCreditNote, []enums.InvoiceStatus{enums.InvoiceStatusPaid, enums.InvoiceStatusPartiallyRefunded, enums.InvoiceStatusRefunded},
+		enums.InvoiceTypeCreditNote, []enums.InvoiceStatus{enums.InvoiceStatusCredited, enums.InvoiceStatusRefunded},
+	).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, invoice := range invoices {
+		if invoice.IsCreditNote() {
+			result = append(result, models.NewCreditNoteJournalEntry(invoice))
+		} else {
+			result = append(result, models.NewInvoiceJournalEntry(invoice))
+		}
+	}
+
+	var transactions models.PaymentTransactions
+	err = r.db.Find(&transactions, "COALESCE(mark_as_paid_at, created_at) BETWEEN ? AND ? AND status IN ?",
+		dateFrom, dateTo, []enums.PaymentStatus{enums.PaymentStatusPaid, enums.PaymentStatusRefunded},
+	).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		result = append(result, models.NewPaymentTransactionJournalEntries(transaction)...)
+	}
+
+	var orders []*models.BulkPurchaseOrder
+	err = r.db.Select("ID", "ReferenceID", "UserID", "Currency", "FinalPaymentMarkAsPaidAt", "SampleDeductionAmount", "FinalPaymentDeductionAmount").
+		Find(&orders, "final_payment_mark_as_paid_at BETWEEN ? AND ? AND (sample_deduction_amount > 0 OR final_payment_deduction_amount > 0)", dateFrom, dateTo).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		result = append(result, models.NewBulkDeductionJournalEntries(order)...)
+	}
+
+	return result, nil
+}
+
+type PaginateJournalEntriesParams struct {
+	models.PaginationParams
+	models.JwtClaimsInfo
+
+	DateFrom    int64                   `json:"date_from" query:"date_from"`
+	DateTo      int64                   `json:"date_to" query:"date_to"`
+	SourceType  enums.JournalSourceType `json:"source_type" query:"source_type"`
+	Currency    enums.Currency          `json:"currency" query:"currency"`
+	AccountCode enums.LedgerAccount     `json:"account_code" query:"account_code"`
+}
+
+func (r *LedgerRepo) PaginateJournalEntries(params PaginateJournalEntriesParams) *query.Pagination {
+	var builder = queryfunc.NewJournalEntryBuilder(queryfunc.JournalEntryBuilderOptions{
+		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+			Role: params.GetRole(),
+		},
+	})
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var result = query.New(r.db, builder).
+		WhereFunc(func(builder *query.Builder) {
+			if params.DateFrom > 0 {
+				builder.Where("je.entry_date >= ?", params.DateFrom)
+			}
+
+			if params.DateTo > 0 {
+				builder.Where("je.entry_date <= ?", params.DateTo)
+			}
+
+			if params.SourceType != "" {
+				builder.Where("je.source_type = ?", params.SourceType)
+			}
+
+			if params.Currency != "" {
+				builder.Where("je.currency = ?", params.Currency)
+			}
+
+			if params.AccountCode != "" {
+				builder.Where("EXISTS (SELECT 1 FROM journal_lines jl WHERE jl.journal_entry_id = je.id AND jl.account_code = ?)", params.AccountCode)
+			}
+		}).
+		Page(params.Page).
+		Limit(params.Limit).
+		PagingFunc()
+
+	if records, ok := result.Records.(*[]*models.JournalEntry); ok {
+		if err := r.loadLines(*records); err != nil {
+			r.logger.ErrorAny(err)
+		}
+	}
+
+	return result
+}
+
+func (r *LedgerRepo) loadLines(entries models.JournalEntries) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var entryIDs = make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+
+	var lines models.JournalLines
+	var err = r.db.Order("debit DESC, account_code ASC").Find(&lines, "journal_entry_id IN ?", entryIDs).Error
+	if err != nil {
+		return err
+	}
+
+	var mapEntryIDToLines = make(map[string]models.JournalLines, len(entries))
+	for _, line := range lines {
+		mapEntryIDToLines[line.JournalEntryID] = append(mapEntryIDToLines[line.JournalEntryID], line)
+	}
+	for _, entry := range entries {
+		entry.Lines = mapEntryIDToLines[entry.ID]
+	}
+
+	return nil
+}
+
+type ExportJournalEntriesParams struct {
+	LedgerPeriodParams
+
+	Format   enums.LedgerExportFormat `json:"format" query:"format" validate:"required"`
+	Currency enums.Currency           `json:"currency" query:"currency"`
+}
+
+// ExportJournalEntries posts the period then exports its entries as a Xero or QuickBooks journal import file
+func (r *LedgerRepo) ExportJournalEntries(params ExportJournalEntriesParams) (*models.Attachment, error) {
+	_, err := r.PostJournalEntries(params.LedgerPeriodParams)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries models.JournalEntries
+	var entriesQuery = r.db.Where("entry_date BETWEEN ? AND ?", params.DateFrom, params.DateTo)
+	if params.Currency != "" {
+		entriesQuery = entriesQuery.Where("currency = ?", params.Currency)
+	}
+	err = entriesQuery.Order("entry_date ASC, created_at ASC").Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.loadLines(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileContent []byte
+	switch params.Format {
+	case enums.LedgerExportFormatXero:
+		fileContent, err = entries.ToXeroCSV()
+	case enums.LedgerExportFormatQuickBooks:
+		fileContent, err = entries.ToQuickBooksCSV()
+	default:
+		return nil, errs.ErrLedgerExportFormatInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var contentType = models.ContentTypeCSV
+	url := fmt.Sprintf("uploads/ledger/export/journal_%s_%d_%d_user_%s.csv", params.Format, params.DateFrom, params.DateTo, params.GetUserID())
+	_, err = s3.New(r.db.Configuration).UploadFile(s3.UploadFileParams{
+		Data:        bytes.NewReader(fileContent),
+		Bucket:      r.db.Configuration.AWSS3StorageBucket,
+		ContentType: string(contentType),
+		ACL:         "private",
+		Key:         url,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp = models.Attachment{
+		FileKey:     url,
+		ContentType: string(contentType),
+	}
+	return &resp, err
+}
+
+type GetTrialBalanceParams struct {
+	LedgerPeriodParams
+
+	Currency enums.Currency `json:"currency" query:"currency"`
+}
+
+// GetTrialBalance sums the posted lines of the period per account, one currency at a time
+func (r *LedgerRepo) GetTrialBalance(params GetTrialBalanceParams) (*models.TrialBalance, error) {
+	var resp = models.TrialBalance{
+		DateFrom: params.DateFrom,
+		DateTo:   params.DateTo,
+		Currency: params.Currency.DefaultIfInvalid(),
+	}
+
+	var err = r.db.Model(&models.JournalLine{}).
+		Select("account_code, SUM(debit) AS debit, SUM(credit) AS credit, SUM(debit) - SUM(credit) AS balance").
+		Where("entry_date BETWEEN ? AND ? AND currency = ?", params.DateFrom, params.DateTo, resp.Currency).
+		Group("account_code").
+		Order("account_code ASC").
+		Scan(&resp.Rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var totalDebit, totalCredit price.Price
+	for _, row := range resp.Rows {
+		row.AccountName = row.AccountCode.DisplayName()
+		totalDebit = totalDebit.AddPtr(row.Debit)
+		totalCredit = totalCredit.AddPtr(row.Credit)
+	}
+	resp.TotalDebit = totalDebit.ToPtr()
+	resp.TotalCredit = totalCredit.ToPtr()
+	resp.IsBalanced = totalDebit.Sub(totalCredit).Equal(0)
+
+	return &resp, nil
+}
@@ -348,6 +348,7 @@ func (repo *OrderCartRepo) CheckoutOrders(req *models.OrderCartCheckoutRequest)
 
 	var purchaseOrdersToUpdate = make([]*models.PurchaseOrder, 0, len(purchaseOrders))
 	var bulksToUpdate = make([]*models.BulkPurchaseOrder, 0, len(bulks))
+	var bulkActions = make(map[string]enums.BulkPoTrackingAction, len(bulks))
 	var inquiryIDsToUpdate = make([]string, 0, len(purchaseOrders))
 	var orderCartItemIDsToUpdate []string
 	var paymentTransaction models.PaymentTransaction
@@ -372,7 +373,7 @@ func (repo *OrderCartRepo) CheckoutOrders(req *models.OrderCartCheckoutRequest)
 			if bpo.TrackingStatus == enums.BulkPoTrackingStatusFirstPayment {
 				totalAmount = totalAmount.AddPtr(bpo.FirstPaymentTotal)
 
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirm
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionMakeFirstPayment
 				bpo.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
 				bpo.FirstPaymentTransactionRefID = req.TransactionRefID
 				bpo.FirstPaymentTransactionAttachment = req.TransactionAttachment
@@ -381,7 +382,7 @@ func (repo *OrderCartRepo) CheckoutOrders(req *models.OrderCartCheckoutRequest)
 			} else {
 				totalAmount = totalAmount.AddPtr(bpo.FinalPaymentTotal)
 
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirm
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionMakeFinalPayment
 				bpo.FinalPaymentTransferedAt = values.Int64(time.Now().Unix())
 				bpo.FinalPaymentTransactionRefID = req.TransactionRefID
 				bpo.FinalPaymentTransactionAttachment = req.TransactionAttachment
@@ -501,14 +502,14 @@ func (repo *OrderCartRepo) CheckoutOrders(req *models.OrderCartCheckoutRequest)
 		}
 		for _, bpo := range bulks {
 			if bpo.TrackingStatus == enums.BulkPoTrackingStatusFirstPayment {
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionFirstPaymentConfirmed
 				bpo.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
 				bpo.FirstPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 				bpo.FirstPaymentTransactionReferenceID = transactionRefID
 				bpo.FirstPaymentIntentID = pi.ID
 				bpo.FirstPaymentCheckoutSessionID = checkoutSessionID
 			} else {
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionFinalPaymentConfirmed
 				bpo.FinalPaymentTransferedAt = values.Int64(time.Now().Unix())
 				bpo.FinalPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 				bpo.FinalPaymentTransactionReferenceID = transactionRefID
@@ -559,12 +560,15 @@ func (repo *OrderCartRepo) CheckoutOrders(req *models.OrderCartCheckoutRequest)
 			}
 		}
 		if len(bulksToUpdate) > 0 {
-			if err := tx.Clauses(clause.OnConflict{
+			if err := tx.Omit("TrackingStatus").Clauses(clause.OnConflict{
 				Columns:   []clause.Column{{Name: "id"}},
 				UpdateAll: true,
 			}).Create(&bulksToUpdate).Error; err != nil {
 				return err
 			}
+			if err := NewBulkPurchaseOrderRepo(repo.db).TransitionEachTx(tx, req.JwtClaimsInfo, bulksToUpdate, bulkActions); err != nil {
+				return err
+			}
 		}
 		if len(inquiryIDsToUpdate) > 0 {
 			if err := tx.Model(&models.Inquiry{}).Where("id IN ?", inquiryIDsToUpdate).
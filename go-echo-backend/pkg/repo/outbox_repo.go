@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxStuckAfter pending rows older than this are reported as stuck
+const OutboxStuckAfter = time.Minute * 10
+
+type OutboxRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewOutboxRepo(db *db.DB) *OutboxRepo {
+	return &OutboxRepo{
+		db:     db,
+		logger: logger.New("repo/Outbox"),
+	}
+}
+
+// AddTasksTx writes the tasks into the outbox using the caller's transaction,
+// they are only relayed to the queue once that transaction commits
+func (r *OutboxRepo) AddTasksTx(tx *gorm.DB, referenceID string, tasks ...models.OutboxTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	var now = time.Now().Unix()
+	var messages = make([]*models.OutboxMessage, 0, len(tasks))
+	for _, task := range tasks {
+		var message = models.OutboxMessage{
+			TaskName:      task.TaskName(),
+			Payload:       task.GetPayload(),
+			ReferenceID:   referenceID,
+			Status:        enums.OutboxStatusPending,
+			NextAttemptAt: now,
+		}
+		message.ID = helper.GenerateXID()
+		message.DedupeKey = message.ID
+
+		if deduper, ok := task.(models.OutboxDeduper); ok && deduper.DedupeKey() != "" {
+			message.DedupeKey = deduper.DedupeKey()
+		}
+
+		messages = append(messages, &message)
+	}
+
+	// A key shared with a pending row is skipped, the pending row will run the task
+	return tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "dedupe_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "status", Value: enums.OutboxStatusPending}}},
+		DoNothing:   true,
+	}).Create(&messages).Error
+}
+
+// AddTasks writes the tasks into the outbox for a change the caller has already committed,
+// the relay retries them while redis is down, unlike a direct dispatch
+func (r *OutboxRepo) AddTasks(referenceID string, tasks ...models.OutboxTask) error {
+	return r.AddTasksTx(r.db.DB, referenceID, tasks...)
+}
+
+type PaginateOutboxMessagesParams struct {
+	models.PaginationParams
+	models.JwtClaimsInfo
+
+	Statuses    []enums.OutboxStatus `json:"statuses" query:"statuses"`
+	TaskName    string               `json:"task_name" query:"task_name"`
+	ReferenceID string               `json:"reference_id" query:"reference_id"`
+	IsStuck     bool                 `json:"is_stuck" query:"is_stuck"`
+}
+
+func (r *OutboxRepo) PaginateOutboxMessages(params PaginateOutboxMessagesParams) *query.Pagination {
+	var builder = queryfunc.NewOutboxMessageBuilder(queryfunc.OutboxMessageBuilderOptions{
+		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+			Role: params.GetRole(),
+		},
+	})
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var result = query.New(r.db, builder).
+		WhereFunc(func(builder *query.Builder) {
+			if len(params.Statuses) > 0 {
+				builder.Where("o.status IN ?", params.Statuses)
+			}
+
+			if params.TaskName != "" {
+				builder.Where("o.task_name = ?", params.TaskName)
+			}
+
+			if params.ReferenceID != "" {
+				builder.Where("o.reference_id = ?", params.ReferenceID)
+			}
+
+			if params.IsStuck {
+				builder.Where("o.status <> ? AND o.created_at <= ?", enums.OutboxStatusDispatched, time.Now().Add(-OutboxStuckAfter).Unix())
+			}
+		}).
+		Page(params.Page).
+		Limit(params.Limit).
+		PagingFunc()
+
+	return result
+}
+
+type RetryOutboxMessageParams struct {
+	models.JwtClaimsInfo
+
+	OutboxMessageID string `json:"outbox_message_id" param:"outbox_message_id" validate:"required"`
+}
+
+// RetryOutboxMessage puts a failed or stuck message back in front of the relay
+func (r *OutboxRepo) RetryOutboxMessage(params RetryOutboxMessageParams) (*models.OutboxMessage, error) {
+	var message models.OutboxMessage
+	var err = r.db.First(&message, "id = ?", params.OutboxMessageID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrOutboxMessageNotFound
+		}
+		return nil, err
+	}
+
+	if message.Status == enums.OutboxStatusDispatched {
+		return nil, errs.ErrOutboxMessageNotRetryable
+	}
+
+	err = r.db.Model(&message).Clauses(clause.Returning{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]interface{}{
+			"status":          enums.OutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().Unix(),
+		}).Error
+	if isDuplicate, _ := r.db.IsDuplicateConstraint(err); isDuplicate {
+		return nil, errs.ErrOutboxMessageAlreadyPending
+	}
+
+	return &message, err
+}
@@ -1,6 +1,11 @@
 package repo
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/caching"
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
@@ -155,6 +160,13 @@ func (r *PageRepo) GetPageDetailByID(params GetPageDetailByIDParams) (*models.Pa
 }
 
 func (r *PageRepo) GetPageByType(PageType string, options queryfunc.PageBuilderOptions) (*models.Page, error) {
+	var key = fmt.Sprintf("page_type_%s_%s", options.Role, PageType)
+	return caching.GetOrLoad(context.Background(), r.db.Cache, key, func() (*models.Page, error) {
+		return r.getPageByType(PageType, options)
+	}, caching.WithTags(models.CacheTagPage))
+}
+
+func (r *PageRepo) getPageByType(PageType string, options queryfunc.PageBuilderOptions) (*models.Page, error) {
 	var builder = queryfunc.NewPageBuilder(options)
 	var Page models.Page
 	var err = query.New(r.db, builder).
@@ -238,6 +250,10 @@ func (r *PageRepo) DeletePageByID(PageID string) error {
 }
 
 func (r *PageRepo) PageCatalog(PageID string) ([]*models.PageSection, error) {
+	return r.getPageSections("pageCatalog", PageID, r.pageCatalog)
+}
+
+func (r *PageRepo) pageCatalog(PageID string) ([]*models.PageSection, error) {
 	var builder = queryfunc.NewPageSectionBuilder(queryfunc.PageSectionBuilderOptions{})
 	var sections []*models.PageSection
 	var err = query.New(r.db, builder).
@@ -257,6 +273,10 @@ func (r *PageRepo) PageCatalog(PageID string) ([]*models.PageSection, error) {
 }
 
 func (r *PageRepo) PageHome(PageID string) ([]*models.PageSection, error) {
+	return r.getPageSections("pageHome", PageID, r.pageHome)
+}
+
+func (r *PageRepo) pageHome(PageID string) ([]*models.PageSection, error) {
 	var builder = queryfunc.NewPageSectionBuilder(queryfunc.PageSectionBuilderOptions{})
 	var sections []*models.PageSection
 	var err = query.New(r.db, builder).
@@ -313,3 +333,12 @@ func (r *PageRepo) PageHome(PageID string) ([]*models.PageSection, error) {
 	// 	}
 
 }
+
+// getPageSections sections with their products, categories and collections. Writes of the page, its sections
+// and categories invalidate them, product and collection changes show up after the TTL.
+func (r *PageRepo) getPageSections(name string, pageID string, loader func(pageID string) ([]*models.PageSection, error)) ([]*models.PageSection, error) {
+	var key = fmt.Sprintf("page_sections_%s_%s", name, pageID)
+	return caching.GetOrLoad(context.Background(), r.db.Cache, key, func() ([]*models.PageSection, error) {
+		return loader(pageID)
+	}, caching.WithTags(models.CacheTagPage, models.GetPageCacheTag(pageID), models.CacheTagCategory), caching.WithTTL(time.Minute*5))
+}
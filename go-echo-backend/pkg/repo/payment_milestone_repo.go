@@ -0,0 +1,676 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/engineeringinflow/inflow-backend/pkg/stripehelper"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+	"github.com/stripe/stripe-go/v74"
+	"github.com/thaitanloi365/go-utils/values"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentMilestoneRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewPaymentMilestoneRepo(db *db.DB) *PaymentMilestoneRepo {
+	return &PaymentMilestoneRepo{
+		db:     db,
+		logger: logger.New("repo/PaymentMilestone"),
+	}
+}
+
+// BuildPaymentMilestoneSchedule resolves percentages and fixed amounts against the order total.
+// Rounding leftovers go to the last milestone so the schedule always adds up to the total.
+func BuildPaymentMilestoneSchedule(order *models.BulkPurchaseOrder, forms []*models.PaymentMilestoneForm) (models.PaymentMilestones, error) {
+	var total = price.NewFromPtr(order.TotalPrice)
+	if !total.GreaterThan(0) || len(forms) == 0 {
+		return nil, errs.ErrPaymentMilestoneInvalidSchedule
+	}
+
+	var hasKind = lo.SomeBy(forms, func(form *models.PaymentMilestoneForm) bool {
+		return form.Milestone != ""
+	})
+	var seenKinds = map[enums.PaymentMilestone]bool{}
+
+	var result models.PaymentMilestones
+	var sum = price.NewFromFloat(0)
+	for index, form := range forms {
+		var m = &models.PaymentMilestone{
+			BulkPurchaseOrderID: order.ID,
+			Sequence:            index + 1,
+			Milestone:           form.Milestone,
+			Name:                form.Name,
+			Note:                form.Note,
+			Currency:            order.Currency,
+			DueTrigger:          form.DueTrigger,
+			DueDays:             form.DueDays,
+			DueAt:               form.DueAt,
+			Status:              enums.PaymentStatusPending,
+			PaymentType:         enums.PaymentTypeBankTransfer,
+		}
+
+		// Without explicit kinds the first and the last milestones drive the tracking status
+		if !hasKind {
+			if index == 0 {
+				m.Milestone = enums.PaymentMilestoneFirstPayment
+			} else if index == len(forms)-1 {
+				m.Milestone = enums.PaymentMilestoneFinalPayment
+			}
+		}
+		if m.Milestone != "" {
+			if seenKinds[m.Milestone] {
+				return nil, eris.Wrapf(errs.ErrPaymentMilestoneInvalidSchedule, "Duplicated milestone %s", m.Milestone)
+			}
+			seenKinds[m.Milestone] = true
+		}
+
+		switch {
+		case form.Amount != nil:
+			if !form.Amount.GreaterThan(0) {
+				return nil, errs.ErrPaymentMilestoneInvalidSchedule
+			}
+			m.Amount = form.Amount
+			m.Total = form.Amount
+			m.Percentage = values.Float64(form.Amount.MultipleInt(100, true).Div(total, true).Round(4).ToFloat64())
+		case form.Percentage != nil:
+			if *form.Percentage <= 0 || *form.Percentage > 100 {
+				return nil, errs.ErrPaymentMilestoneInvalidSchedule
+			}
+			m.Percentage = form.Percentage
+			m.Total = total.MultipleFloat64(*form.Percentage).DivInt(100).ToPtr()
+		default:
+			return nil, errs.ErrPaymentMilestoneInvalidSchedule
+		}
+
+		sum = sum.AddPtr(m.Total)
+		result = append(result, m)
+	}
+
+	// Allow one cent of rounding per milestone, anything else is a wrong schedule
+	var diff = total.Sub(sum)
+	if diff.Abs().GreaterThan(0.01 * float64(len(result))) {
+		return nil, errs.ErrPaymentMilestoneInvalidSchedule
+	}
+	var last = result[len(result)-1]
+	last.Total = last.Total.Add(diff).ToPtr()
+	if last.Amount != nil {
+		last.Amount = last.Total
+	}
+
+	for _, m := range result {
+		var ratio = m.Total.Div(total, true)
+		m.SubTotal = price.NewFromPtr(order.SubTotal).Multiple(ratio).ToPtr()
+		m.Tax = price.NewFromPtr(order.Tax).Multiple(ratio).ToPtr()
+		m.TransactionFee = price.NewFromPtr(order.TransactionFee).Multiple(ratio).ToPtr()
+	}
+
+	return result, nil
+}
+
+type GetPaymentMilestonesParams struct {
+	models.JwtClaimsInfo
+
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+}
+
+// GetPaymentMilestones returns the schedule of the order, orders created before the schedule existed are backfilled on first read
+func (r *PaymentMilestoneRepo) GetPaymentMilestones(params GetPaymentMilestonesParams) (models.PaymentMilestones, error) {
+	order, err := NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
+		JwtClaimsInfo:       params.JwtClaimsInfo,
+		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.PaymentMilestones
+	err = r.db.Transaction(func(tx *gorm.DB) (err error) {
+		result, err = r.ensurePaymentMilestonesTx(tx, order)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.attachInvoices(result)
+
+	return result, nil
+}
+
+func (r *PaymentMilestoneRepo) attachInvoices(items models.PaymentMilestones) {
+	var invoiceNumbers = lo.FilterMap(items, func(item *models.PaymentMilestone, index int) (int, bool) {
+		return item.InvoiceNumber, item.InvoiceNumber > 0
+	})
+	if len(invoiceNumbers) == 0 {
+		return
+	}
+
+	var invoices []*models.Invoice
+	r.db.Find(&invoices, "invoice_number IN ?", invoiceNumbers)
+	for _, inv := range invoices {
+		for _, item := range items {
+			if item.InvoiceNumber == inv.InvoiceNumber {
+				item.Invoice = inv
+			}
+		}
+	}
+}
+
+func (r *PaymentMilestoneRepo) ensurePaymentMilestonesTx(tx *gorm.DB, order *models.BulkPurchaseOrder) (models.PaymentMilestones, error) {
+	var result models.PaymentMilestones
+	var err = tx.Order("sequence ASC").Find(&result, "bulk_purchase_order_id = ?", order.ID).Error
+	if err != nil || len(result) > 0 {
+		return result, err
+	}
+
+	result = order.LegacyPaymentMilestones()
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Order("sequence ASC").Find(&result, "bulk_purchase_order_id = ?", order.ID).Error
+	return result, err
+}
+
+// BackfillPaymentMilestones copies the legacy payment columns of every order without a schedule into payment_milestones
+func (r *PaymentMilestoneRepo) BackfillPaymentMilestones() error {
+	var orders []*models.BulkPurchaseOrder
+	var count int
+	var result = r.db.Model(&models.BulkPurchaseOrder{}).
+		Where("NOT EXISTS (SELECT 1 FROM payment_milestones pm WHERE pm.bulk_purchase_order_id = bulk_purchase_orders.id)").
+		FindInBatches(&orders, 200, func(tx *gorm.DB, batch int) error {
+			var items models.PaymentMilestones
+			for _, order := range orders {
+				items = append(items, order.LegacyPaymentMilestones()...)
+			}
+			if len(items) == 0 {
+				return nil
+			}
+
+			count += len(items)
+			return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&items, 200).Error
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	r.logger.Debugf("Backfilled %d payment milestones", count)
+	return nil
+}
+
+// UpdatePaymentMilestoneSchedule replaces the schedule while nothing has been paid on it
+func (r *PaymentMilestoneRepo) UpdatePaymentMilestoneSchedule(params models.UpdatePaymentMilestoneScheduleParams) (models.PaymentMilestones, error) {
+	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_payment_%s", params.BulkPurchaseOrderID), time.Second*20)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var order models.BulkPurchaseOrder
+	err = r.db.First(&order, "id = ?", params.BulkPurchaseOrderID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrBulkPoNotFound
+		}
+		return nil, err
+	}
+
+	schedule, err := BuildPaymentMilestoneSchedule(&order, params.Milestones)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := r.ensurePaymentMilestonesTx(tx, &order)
+		if err != nil {
+			return err
+		}
+
+		var locked = lo.SomeBy(current, func(item *models.PaymentMilestone) bool {
+			return item.IsPaid() || item.TransferedAt != nil
+		})
+		if locked {
+			return errs.ErrPaymentMilestoneScheduleLocked
+		}
+
+		err = tx.Unscoped().Delete(&models.PaymentMilestone{}, "bulk_purchase_order_id = ?", order.ID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(&schedule).Error
+		if err != nil {
+			return err
+		}
+
+		// Keep the percentages the legacy pricing reads in line with the schedule
+		var updates = map[string]interface{}{}
+		for _, m := range schedule {
+			switch m.Milestone {
+			case enums.PaymentMilestoneFirstPayment:
+				updates["first_payment_percentage"] = m.Percentage
+			case enums.PaymentMilestoneSecondPayment:
+				updates["second_payment_percentage"] = values.Float64Value(m.Percentage)
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *PaymentMilestoneRepo) GetPaymentMilestone(bulkPurchaseOrderID, paymentMilestoneID string) (*models.PaymentMilestone, error) {
+	var milestone models.PaymentMilestone
+	var err = r.db.First(&milestone, "id = ? AND bulk_purchase_order_id = ?", paymentMilestoneID, bulkPurchaseOrderID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrPaymentMilestoneNotFound
+		}
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+// FindPaymentMilestoneByKind resolves a legacy deposit, first, second or final payment to its milestone row
+func (r *PaymentMilestoneRepo) FindPaymentMilestoneByKind(bulkPurchaseOrderID string, kind enums.PaymentMilestone) (*models.PaymentMilestone, error) {
+	var order models.BulkPurchaseOrder
+	var err = r.db.First(&order, "id = ?", bulkPurchaseOrderID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var items models.PaymentMilestones
+	err = r.db.Transaction(func(tx *gorm.DB) (err error) {
+		items, err = r.ensurePaymentMilestonesTx(tx, &order)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	milestone, found := lo.Find(items, func(item *models.PaymentMilestone) bool {
+		return item.Milestone == kind
+	})
+	if !found {
+		return nil, errs.ErrPaymentMilestoneNotFound
+	}
+
+	return milestone, nil
+}
+
+// FindPaymentMilestoneByPaymentLink resolves the milestone a gateway payment link was created for
+func (r *PaymentMilestoneRepo) FindPaymentMilestoneByPaymentLink(paymentLinkID string) (*models.PaymentMilestone, error) {
+	var milestone models.PaymentMilestone
+	var err = r.db.First(&milestone, "payment_link_id = ?", paymentLinkID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrPaymentMilestoneNotFound
+		}
+		return nil, err
+	}
+
+	return &milestone, nil
+}
+
+// SyncLegacyPaymentMilestoneTx copies the legacy columns of one milestone kind into its row,
+// for the code paths that still write Deposit*, FirstPayment*, SecondPayment* and FinalPayment* directly
+func (r *PaymentMilestoneRepo) SyncLegacyPaymentMilestoneTx(tx *gorm.DB, bulkPurchaseOrderID string, kind enums.PaymentMilestone) error {
+	var order models.BulkPurchaseOrder
+	var err = tx.First(&order, "id = ?", bulkPurchaseOrderID).Error
+	if err != nil {
+		return err
+	}
+
+	current, err := r.ensurePaymentMilestonesTx(tx, &order)
+	if err != nil {
+		return err
+	}
+
+	legacy, found := lo.Find(order.LegacyPaymentMilestones(), func(item *models.PaymentMilestone) bool {
+		return item.Milestone == kind
+	})
+	if !found {
+		return nil
+	}
+
+	existing, found := lo.Find(current, func(item *models.PaymentMilestone) bool {
+		return item.Milestone == kind
+	})
+	if !found {
+		return nil
+	}
+
+	return tx.Model(&models.PaymentMilestone{}).
+		Where("id = ?", existing.ID).
+		Select("Status", "PaidAmount", "InvoiceNumber", "PaymentType", "PaymentIntentID", "ChargeID", "TxnID", "ReceiptURL",
+			"CheckoutSessionID", "PaymentLink", "PaymentLinkID", "TransactionRefID", "TransactionAttachment",
+			"PaymentTransactionReferenceID", "TransferedAt", "ReceivedAt", "MarkAsPaidAt", "MarkAsUnpaidAt").
+		Updates(legacy).Error
+}
+
+// PaymentMilestoneCheckout pays a milestone by card or records the buyer's bank transfer
+func (r *PaymentMilestoneRepo) PaymentMilestoneCheckout(params models.PaymentMilestoneCheckoutParams) (*models.PaymentMilestone, error) {
+	order, err := NewBulkPurchaseOrderRepo(r.db).GetBulkPurchaseOrder(GetBulkPurchaseOrderParams{
+		JwtClaimsInfo:       params.JwtClaimsInfo,
+		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	milestone, err := r.GetPaymentMilestone(order.ID, params.PaymentMilestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if milestone.IsPaid() {
+		return nil, errs.ErrPaymentMilestoneAlreadyPaid
+	}
+
+	if params.PaymentType == enums.PaymentTypeBankTransfer {
+		var transaction = r.newPaymentTransaction(order, milestone)
+		transaction.PaymentType = enums.PaymentTypeBankTransfer
+		transaction.TransactionRefID = params.TransactionRefID
+		transaction.Status = enums.PaymentStatusWaitingConfirm
+		transaction.Note = params.Note
+		if params.TransactionAttachment != nil {
+			transaction.Attachments = &models.Attachments{params.TransactionAttachment}
+		}
+
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			var err = tx.Create(&transaction).Error
+			if err != nil {
+				return err
+			}
+
+			milestone.PaymentType = enums.PaymentTypeBankTransfer
+			milestone.TransferedAt = values.Int64(time.Now().Unix())
+			milestone.TransactionRefID = params.TransactionRefID
+			milestone.TransactionAttachment = params.TransactionAttachment
+			milestone.PaymentTransactionReferenceID = transaction.ReferenceID
+			milestone.Status = enums.PaymentStatusWaitingConfirm
+
+			err = tx.Model(&models.PaymentMilestone{}).Where("id = ?", milestone.ID).Updates(milestone).Error
+			if err != nil {
+				return err
+			}
+
+			return r.updateLegacyColumnsTx(tx, milestone)
+		})
+
+		return milestone, err
+	}
+
+	var user models.User
+	err = r.db.Select("ID", "StripeCustomerID").First(&user, "id = ?", params.GetUserID()).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stripeConfig, err := stripehelper.GetCurrencyConfig(order.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	pi, err := stripehelper.GetInstance().CreatePaymentIntent(stripehelper.CreatePaymentIntentParams{
+		Amount:             milestone.Total.MultipleInt(stripeConfig.SmallestUnitFactor).ToInt64(),
+		Currency:           order.Currency,
+		PaymentMethodID:    params.PaymentMethodID,
+		CustomerID:         user.StripeCustomerID,
+		Description:        fmt.Sprintf("Charges for %s %s", milestone.GetDisplayName(), order.ReferenceID),
+		PaymentMethodTypes: []string{"card"},
+		Metadata: map[string]string{
+			"milestone":                        string(milestone.Milestone),
+			"payment_milestone_id":             milestone.ID,
+			"inquiry_id":                       order.InquiryID,
+			"bulk_purchase_order_id":           order.ID,
+			"bulk_purchase_order_reference_id": order.ReferenceID,
+			"action_source":                    string(stripehelper.ActionSourceBulkPOMilestonePayment),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&models.PaymentMilestone{}).Where("id = ?", milestone.ID).Updates(&models.PaymentMilestone{
+		PaymentType:     enums.PaymentTypeCard,
+		PaymentIntentID: pi.ID,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	milestone.PaymentType = enums.PaymentTypeCard
+	milestone.PaymentIntentID = pi.ID
+
+	// The payment_intent.succeeded webhook marks the milestone as paid, even when the card is charged right away
+	if pi.Status != stripe.PaymentIntentStatusSucceeded && pi.NextAction == nil {
+		return nil, eris.Errorf("Payment error with status %s", pi.Status)
+	}
+
+	return milestone, nil
+}
+
+func (r *PaymentMilestoneRepo) newPaymentTransaction(order *models.BulkPurchaseOrder, milestone *models.PaymentMilestone) models.PaymentTransaction {
+	var milestoneKind = milestone.Milestone
+	if milestoneKind == "" {
+		milestoneKind = enums.PaymentMilestone(fmt.Sprintf("milestone_%d", milestone.Sequence))
+	}
+
+	var transaction = models.PaymentTransaction{
+		BulkPurchaseOrderID: order.ID,
+		PaymentMilestoneID:  milestone.ID,
+		Currency:            order.Currency,
+		PaidAmount:          milestone.Total,
+		Milestone:           milestoneKind,
+		UserID:              order.UserID,
+		PaymentPercentage:   milestone.Percentage,
+		TotalAmount:         order.TotalPrice,
+		TransactionType:     enums.TransactionTypeCredit,
+		Metadata: &models.PaymentTransactionMetadata{
+			BulkPurchaseOrderReferenceID: order.ReferenceID,
+			BulkPurchaseOrderID:          order.ID,
+		},
+	}
+	if order.Inquiry != nil {
+		transaction.Metadata.InquiryID = order.Inquiry.ID
+		transaction.Metadata.InquiryReferenceID = order.Inquiry.ReferenceID
+	}
+
+	return transaction
+}
+
+func (r *PaymentMilestoneRepo) updateLegacyColumnsTx(tx *gorm.DB, milestone *models.PaymentMilestone) error {
+	var updates = milestone.LegacyColumnUpdates()
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", milestone.BulkPurchaseOrderID).Updates(updates).Error
+}
+
+// MarkPaymentMilestoneAsPaid is the single entry point for admin confirmations and gateway webhooks.
+// First and final payment milestones also move the order tracking status like the legacy flow did.
+func (r *PaymentMilestoneRepo) MarkPaymentMilestoneAsPaid(params models.PaymentMilestoneMarkAsPaidParams) (*models.PaymentMilestone, error) {
+	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_payment_%s", params.BulkPurchaseOrderID), time.Second*20)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	milestone, err := r.GetPaymentMilestone(params.BulkPurchaseOrderID, params.PaymentMilestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if milestone.IsPaid() {
+		return milestone, errs.ErrPaymentMilestoneAlreadyPaid
+	}
+
+	var order models.BulkPurchaseOrder
+	err = r.db.First(&order, "id = ?", params.BulkPurchaseOrderID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var now = time.Now().Unix()
+	milestone.Status = enums.PaymentStatusPaid
+	milestone.MarkAsPaidAt = values.Int64(now)
+	milestone.PaidAmount = milestone.Total
+	if milestone.TransferedAt == nil {
+		milestone.TransferedAt = values.Int64(now)
+	}
+	if params.PaymentType != "" {
+		milestone.PaymentType = params.PaymentType
+	}
+	if params.PaymentIntentID != "" {
+		milestone.PaymentIntentID = params.PaymentIntentID
+	}
+	if params.ChargeID != "" {
+		milestone.ChargeID = params.ChargeID
+	}
+	if params.TxnID != "" {
+		milestone.TxnID = params.TxnID
+	}
+	if params.ReceiptURL != "" {
+		milestone.ReceiptURL = params.ReceiptURL
+	}
+	if params.PaymentLinkID != "" {
+		milestone.PaymentLinkID = params.PaymentLinkID
+	}
+	if params.CheckoutSessionID != "" {
+		milestone.CheckoutSessionID = params.CheckoutSessionID
+	}
+
+	var transaction = r.newPaymentTransaction(&order, milestone)
+	transaction.PaymentType = milestone.PaymentType
+	transaction.Status = enums.PaymentStatusPaid
+	transaction.MarkAsPaidAt = milestone.MarkAsPaidAt
+	transaction.PaymentIntentID = milestone.PaymentIntentID
+	transaction.ChargeID = milestone.ChargeID
+	transaction.TxnID = milestone.TxnID
+	transaction.ReceiptURL = milestone.ReceiptURL
+	transaction.PaymentLinkID = milestone.PaymentLinkID
+	transaction.TransactionRefID = milestone.TransactionRefID
+	transaction.Net = params.Net
+	transaction.Fee = params.Fee
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// Transactions recorded by the legacy checkout only know the milestone kind
+		var existing models.PaymentTransaction
+		var err = tx.Select("ID", "ReferenceID").
+			Where("transaction_type = ? AND (payment_milestone_id = ? OR (bulk_purchase_order_id = ? AND milestone = ? AND COALESCE(payment_milestone_id, '') = ''))",
+				transaction.TransactionType, milestone.ID, order.ID, transaction.Milestone).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		if existing.ID != "" {
+			err = tx.Model(&models.PaymentTransaction{}).Where("id = ?", existing.ID).Updates(&transaction).Error
+			transaction.ID = existing.ID
+			transaction.ReferenceID = existing.ReferenceID
+		} else {
+			err = tx.Create(&transaction).Error
+		}
+		if err != nil {
+			return err
+		}
+		milestone.PaymentTransactionReferenceID = transaction.ReferenceID
+
+		err = tx.Model(&models.PaymentMilestone{}).Where("id = ?", milestone.ID).Updates(milestone).Error
+		if err != nil {
+			return err
+		}
+
+		err = r.updateLegacyColumnsTx(tx, milestone)
+		if err != nil {
+			return err
+		}
+
+		var updates models.BulkPurchaseOrder
+		if order.StartDate == nil {
+			if bulkQuotation := order.GetBulkQuotation(); bulkQuotation != nil {
+				updates.LeadTime = int(values.Int64Value(bulkQuotation.LeadTime))
+				updates.StartDate = milestone.MarkAsPaidAt
+				updates.CompletionDate = values.Int64(time.Unix(*updates.StartDate, 0).AddDate(0, 0, updates.LeadTime).Unix())
+			}
+		}
+
+		var action enums.BulkPoTrackingAction
+		switch milestone.Milestone {
+		case enums.PaymentMilestoneFirstPayment:
+			action = enums.BulkPoTrackingActionFirstPaymentConfirmed
+		case enums.PaymentMilestoneFinalPayment:
+			action = enums.BulkPoTrackingActionFinalPaymentConfirmed
+		}
+
+		var transitionErr error
+		if action != "" {
+			_, transitionErr = FindBulkPurchaseOrderTransition(order.TrackingStatus, action)
+			if transitionErr != nil {
+				// The payment is recorded anyway, the order status has to be fixed by hand
+				r.db.CustomLogger.Errorf("Bulk order %s milestone %s is paid in status %s, %s is skipped: %v", order.ReferenceID, milestone.ID, order.TrackingStatus, action, transitionErr)
+			}
+		}
+
+		if action != "" && transitionErr == nil {
+			err = NewBulkPurchaseOrderRepo(r.db).TransitionTx(tx, BulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         &order,
+				Action:        action,
+				Updates:       &updates,
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Description: fmt.Sprintf("%s is marked as paid", milestone.GetDisplayName()),
+					Metadata: &models.PoTrackingMetadata{
+						After: map[string]interface{}{
+							"payment_milestone_id":   milestone.ID,
+							"payment_transaction_id": transaction.ID,
+						},
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+		} else if updates.StartDate != nil {
+			err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", order.ID).Updates(&updates).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return NewOutboxRepo(r.db).AddTasksTx(tx, order.ID, params.OutboxTasks...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
@@ -2,7 +2,6 @@ package repo
 
 import (
 	"bytes"
-	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
@@ -103,7 +102,8 @@ func (r *PaymentTransactionRepo) PaginatePaymentTransactions(params PaginatePaym
 					} else if strings.HasPrefix(keyword, "IQ-") {
 						builder.Where("p.metadata->>'inquiry_reference_id' = ?", keyword)
 					} else {
-						builder.Where("(u.name ILIKE @keyword OR u.email ILIKE @keyword)", sql.Named("keyword", "%"+keyword+"%"))
+						var condition, args = usersSearchDocument.keywordCondition("u", keyword)
+						builder.Where(condition, args...)
 					}
 				}
 			}
@@ -121,8 +121,9 @@ type GetPaymentTransactionsParams struct {
 	PaymentTransactionID string `json:"payment_transaction_id" param:"payment_transaction_id" validate:"required"`
 	Note                 string `json:"note"`
 
-	IncludeInvoice bool `json:"-"`
-	IncludeDetails bool `json:"-"`
+	IncludeInvoice bool                `json:"-"`
+	IncludeDetails bool                `json:"-"`
+	OutboxTasks    []models.OutboxTask `json:"-"`
 }
 
 func (r *PaymentTransactionRepo) GetPaymentTransaction(params GetPaymentTransactionsParams) (result models.PaymentTransaction, err error) {
@@ -201,6 +202,7 @@ func (r *PaymentTransactionRepo) ApprovePaymentTransactions(params GetPaymentTra
 
 	}
 	var bulks = make(models.BulkPurchaseOrders, 0, len(transaction.BulkPurchaseOrderIDs))
+	var bulkActions = make(map[string]enums.BulkPoTrackingAction, len(transaction.BulkPurchaseOrderIDs))
 	if len(transaction.BulkPurchaseOrderIDs) > 0 {
 		if err := r.db.Find(&bulks, "id IN ?", []string(transaction.BulkPurchaseOrderIDs)).Error; err != nil {
 			return nil, err
@@ -216,12 +218,12 @@ func (r *PaymentTransactionRepo) ApprovePaymentTransactions(params GetPaymentTra
 					bpo.StartDate = values.Int64(time.Now().Unix())
 					bpo.CompletionDate = values.Int64(time.Unix(*bpo.StartDate, 0).AddDate(0, 0, bpo.LeadTime).Unix())
 				}
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
 				bpo.FirstPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionFirstPaymentConfirmed
 			}
 			if bpo.TrackingStatus == enums.BulkPoTrackingStatusFinalPaymentConfirm {
-				bpo.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
 				bpo.FinalPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
+				bulkActions[bpo.ID] = enums.BulkPoTrackingActionFinalPaymentConfirmed
 			}
 
 		}
@@ -247,7 +249,10 @@ func (r *PaymentTransactionRepo) ApprovePaymentTransactions(params GetPaymentTra
 			}
 		}
 		if len(bulks) > 0 {
-			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&bulks).Error; err != nil {
+			if err := tx.Omit("TrackingStatus").Clauses(clause.OnConflict{UpdateAll: true}).Create(&bulks).Error; err != nil {
+				return err
+			}
+			if err := NewBulkPurchaseOrderRepo(r.db).TransitionEachTx(tx, params.JwtClaimsInfo, bulks, bulkActions); err != nil {
 				return err
 			}
 			var finalPaymentBulkIDs []string
@@ -262,7 +267,7 @@ func (r *PaymentTransactionRepo) ApprovePaymentTransactions(params GetPaymentTra
 			}
 
 		}
-		return nil
+		return NewOutboxRepo(r.db).AddTasksTx(tx, transaction.ID, params.OutboxTasks...)
 	}); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,294 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+)
+
+var productFacets = []enums.ProductFacet{
+	enums.ProductFacetCategory,
+	enums.ProductFacetFabric,
+	enums.ProductFacetMinOrder,
+	enums.ProductFacetRating,
+	enums.ProductFacetPriceTier,
+}
+
+var productFacetMinOrders = []enums.FilterMinOrder{
+	enums.FilterRating10,
+	enums.FilterRating100,
+	enums.FilterRating500,
+}
+
+var productFacetRatings = []enums.FilterRating{
+	enums.FilterRating1,
+	enums.FilterRating2,
+	enums.FilterRating3,
+	enums.FilterRating4,
+	enums.FilterRating5,
+}
+
+var productFacetPriceTiers = []enums.FilterPriceTier{
+	enums.FilterPriceTierUnder5,
+	enums.FilterPriceTier5To10,
+	enums.FilterPriceTier10To20,
+	enums.FilterPriceTier20To50,
+	enums.FilterPriceTierOver50,
+}
+
+// ProductFacetMatch condition of the selected options of a facet, the options of a facet are OR-ed and the facets AND-ed
+type ProductFacetMatch struct {
+	Condition string
+	Args      []sql.NamedArg
+}
+
+// GetProductFacetMatches conditions of the facets with a selected option, the thresholds of min order and rating match from the lowest selected one
+func GetProductFacetMatches(params PaginateProductParams) map[enums.ProductFacet]ProductFacetMatch {
+	var matches = map[enums.ProductFacet]ProductFacetMatch{}
+
+	if len(params.CategoryIDs) > 0 {
+		matches[enums.ProductFacetCategory] = ProductFacetMatch{
+			Condition: "p.category_id IN @facet_category_ids",
+			Args:      []sql.NamedArg{sql.Named("facet_category_ids", params.CategoryIDs)},
+		}
+	}
+
+	if len(params.FabricIDs) > 0 {
+		matches[enums.ProductFacetFabric] = ProductFacetMatch{
+			Condition: "p.fabric_ids && CAST(@facet_fabric_ids AS varchar[])",
+			Args:      []sql.NamedArg{sql.Named("facet_fabric_ids", pq.StringArray(params.FabricIDs))},
+		}
+	}
+
+	if minOrders := lo.Intersect(productFacetMinOrders, params.MinOrders); len(minOrders) > 0 {
+		matches[enums.ProductFacetMinOrder] = ProductFacetMatch{
+			Condition: "p.min_order >= @facet_min_order",
+			Args:      []sql.NamedArg{sql.Named("facet_min_order", int(lo.Min(minOrders)))},
+		}
+	}
+
+	if ratings := lo.Intersect(productFacetRatings, params.Ratings); len(ratings) > 0 {
+		matches[enums.ProductFacetRating] = ProductFacetMatch{
+			Condition: "p.rating_star >= @facet_rating",
+			Args:      []sql.NamedArg{sql.Named("facet_rating", int(lo.Min(ratings)))},
+		}
+	}
+
+	if priceTiers := lo.Intersect(productFacetPriceTiers, params.PriceTiers); len(priceTiers) > 0 {
+		var conditions = lo.Map(priceTiers, func(tier enums.FilterPriceTier, index int) string {
+			return getPriceTierCondition(tier)
+		})
+		matches[enums.ProductFacetPriceTier] = ProductFacetMatch{
+			Condition: strings.Join(conditions, " OR "),
+		}
+	}
+
+	return matches
+}
+
+func getPriceTierCondition(tier enums.FilterPriceTier) string {
+	var min, max = tier.Range()
+	if max == 0 {
+		return fmt.Sprintf("p.price >= %g", min)
+	}
+
+	return fmt.Sprintf("(p.price >= %g AND p.price < %g)", min, max)
+}
+
+// whereProductFacets filters the products by the selected facet options
+func whereProductFacets(params PaginateProductParams) query.WhereFunc {
+	return func(builder *query.Builder) {
+		for _, match := range GetProductFacetMatches(params) {
+			builder.Where(fmt.Sprintf("(%s)", match.Condition))
+			for _, arg := range match.Args {
+				builder.Where(arg)
+			}
+		}
+	}
+}
+
+// GetProductFacets options of every facet with the count of products matching the keyword, the base filters and the other facets
+func (r *ProductRepo) GetProductFacets(params PaginateProductParams) ([]*models.ProductFilter, error) {
+	var matches = GetProductFacetMatches(params)
+	var columns []queryfunc.SQL
+	for _, facet := range productFacets {
+		var condition = "TRUE"
+		if match, ok := matches[facet]; ok {
+			condition = match.Condition
+		}
+		columns = append(columns, queryfunc.SQL(fmt.Sprintf("(%s) AS match_%s", condition, facet)))
+	}
+
+	var builder = queryfunc.NewProductFacetBuilder(queryfunc.ProductFacetBuilderOptions{
+		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+			Role: params.GetRole(),
+		},
+		Matches: columns,
+	})
+
+	var counts []*models.ProductFacetCount
+	var err = query.New(r.db, builder).
+		WhereFunc(r.whereProducts(params)).
+		WhereFunc(func(builder *query.Builder) {
+			for _, match := range matches {
+				for _, arg := range match.Args {
+					builder.Where(arg)
+				}
+			}
+		}).
+		WithWrapSQL(getProductFacetCountSQL()).
+		Find(&counts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getProductFacetOptions(params, counts)
+}
+
+// getProductFacetCountSQL counts the options of each facet over the alias CTE of the filtered products
+func getProductFacetCountSQL() string {
+	var otherMatches = func(facet enums.ProductFacet) string {
+		var others = lo.FilterMap(productFacets, func(other enums.ProductFacet, index int) (string, bool) {
+			return fmt.Sprintf("a.match_%s", other), other != facet
+		})
+		return strings.Join(others, " AND ")
+	}
+
+	var minOrders = lo.Map(productFacetMinOrders, func(value enums.FilterMinOrder, index int) string {
+		return strconv.Itoa(int(value))
+	})
+	var ratings = lo.Map(productFacetRatings, func(value enums.FilterRating, index int) string {
+		return strconv.Itoa(int(value))
+	})
+	var priceTiers = lo.Map(productFacetPriceTiers, func(tier enums.FilterPriceTier, index int) string {
+		return fmt.Sprintf("WHEN %s THEN '%s'", strings.ReplaceAll(getPriceTierCondition(tier), "p.", "a."), tier)
+	})
+
+	return fmt.Sprintf(`
+	SELECT '%s' AS facet, a.category_id AS value, COUNT(1) AS count
+	FROM alias a WHERE %s AND a.category_id <> '' GROUP BY a.category_id
+	UNION ALL
+	SELECT '%s' AS facet, f.fabric_id AS value, COUNT(1) AS count
+	FROM alias a CROSS JOIN LATERAL unnest(a.fabric_ids) f(fabric_id) WHERE %s GROUP BY f.fabric_id
+	UNION ALL
+	SELECT '%s' AS facet, v.value::text AS value, COUNT(1) AS count
+	FROM alias a JOIN unnest(ARRAY[%s]) v(value) ON a.min_order >= v.value WHERE %s GROUP BY v.value
+	UNION ALL
+	SELECT '%s' AS facet, v.value::text AS value, COUNT(1) AS count
+	FROM alias a JOIN unnest(ARRAY[%s]) v(value) ON a.rating_star >= v.value WHERE %s GROUP BY v.value
+	UNION ALL
+	SELECT '%s' AS facet, t.value, COUNT(1) AS count
+	FROM alias a CROSS JOIN LATERAL (SELECT CASE %s END AS value) t WHERE %s AND t.value IS NOT NULL GROUP BY t.value
+	`,
+		enums.ProductFacetCategory, otherMatches(enums.ProductFacetCategory),
+		enums.ProductFacetFabric, otherMatches(enums.ProductFacetFabric),
+		enums.ProductFacetMinOrder, strings.Join(minOrders, ","), otherMatches(enums.ProductFacetMinOrder),
+		enums.ProductFacetRating, strings.Join(ratings, ","), otherMatches(enums.ProductFacetRating),
+		enums.ProductFacetPriceTier, strings.Join(priceTiers, " "), otherMatches(enums.ProductFacetPriceTier),
+	)
+}
+
+func (r *ProductRepo) getProductFacetOptions(params PaginateProductParams, counts []*models.ProductFacetCount) ([]*models.ProductFilter, error) {
+	var countsByFacet = lo.GroupBy(counts, func(count *models.ProductFacetCount) string {
+		return count.Facet
+	})
+	var getCount = func(facet enums.ProductFacet, value string) int {
+		for _, count := range countsByFacet[string(facet)] {
+			if count.Value == value {
+				return count.Count
+			}
+		}
+		return 0
+	}
+	var getValues = func(facet enums.ProductFacet, selected []string) []string {
+		var values = lo.Map(countsByFacet[string(facet)], func(count *models.ProductFacetCount, index int) string {
+			return count.Value
+		})
+		return lo.Uniq(append(values, selected...))
+	}
+
+	var categoryIDs = getValues(enums.ProductFacetCategory, params.CategoryIDs)
+	var categories []*models.Category
+	if len(categoryIDs) > 0 {
+		if err := r.db.Select("ID", "Name", "Slug").Find(&categories, "id IN ?", categoryIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var fabricIDs = getValues(enums.ProductFacetFabric, params.FabricIDs)
+	var fabrics []*models.Fabric
+	if len(fabricIDs) > 0 {
+		if err := r.db.Select("ID", "ReferenceID", "FabricType").Find(&fabrics, "id IN ?", fabricIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var categoryOptions = lo.Map(categories, func(category *models.Category, index int) *models.ProductFacetOption {
+		return &models.ProductFacetOption{
+			Name:     category.Name,
+			Value:    category.ID,
+			Count:    getCount(enums.ProductFacetCategory, category.ID),
+			Selected: lo.Contains(params.CategoryIDs, category.ID),
+		}
+	})
+
+	var fabricOptions = lo.Map(fabrics, func(fabric *models.Fabric, index int) *models.ProductFacetOption {
+		return &models.ProductFacetOption{
+			Name:     lo.Ternary(fabric.FabricType != "", fabric.FabricType, fabric.ReferenceID),
+			Value:    fabric.ID,
+			Count:    getCount(enums.ProductFacetFabric, fabric.ID),
+			Selected: lo.Contains(params.FabricIDs, fabric.ID),
+		}
+	})
+
+	var minOrderOptions = lo.Map(productFacetMinOrders, func(value enums.FilterMinOrder, index int) *models.ProductFacetOption {
+		return &models.ProductFacetOption{
+			Name:     value.DisplayName(),
+			Value:    value,
+			Count:    getCount(enums.ProductFacetMinOrder, strconv.Itoa(int(value))),
+			Selected: lo.Contains(params.MinOrders, value),
+		}
+	})
+
+	var ratingOptions = lo.Map(productFacetRatings, func(value enums.FilterRating, index int) *models.ProductFacetOption {
+		return &models.ProductFacetOption{
+			Name:     value.DisplayName(),
+			Value:    value,
+			Count:    getCount(enums.ProductFacetRating, strconv.Itoa(int(value))),
+			Selected: lo.Contains(params.Ratings, value),
+		}
+	})
+
+	var priceTierOptions = lo.Map(productFacetPriceTiers, func(value enums.FilterPriceTier, index int) *models.ProductFacetOption {
+		return &models.ProductFacetOption{
+			Name:     value.DisplayName(),
+			Value:    value,
+			Count:    getCount(enums.ProductFacetPriceTier, string(value)),
+			Selected: lo.Contains(params.PriceTiers, value),
+		}
+	})
+
+	var sortByCount = func(options []*models.ProductFacetOption) []*models.ProductFacetOption {
+		sort.SliceStable(options, func(i, j int) bool {
+			return options[i].Count > options[j].Count
+		})
+		return options
+	}
+
+	return []*models.ProductFilter{
+		{Name: enums.ProductFacetCategory.DisplayName(), Key: "category_ids", Type: "multi_select", Options: sortByCount(categoryOptions)},
+		{Name: enums.ProductFacetFabric.DisplayName(), Key: "fabric_ids", Type: "multi_select", Options: sortByCount(fabricOptions)},
+		{Name: enums.ProductFacetMinOrder.DisplayName(), Key: "min_orders", Type: "multi_select", Options: minOrderOptions},
+		{Name: enums.ProductFacetRating.DisplayName(), Key: "ratings", Type: "multi_select", Options: ratingOptions},
+		{Name: enums.ProductFacetPriceTier.DisplayName(), Key: "price_tiers", Type: "multi_select", Options: priceTierOptions},
+	}, nil
+}
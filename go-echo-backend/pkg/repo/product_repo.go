@@ -2,7 +2,6 @@ package repo
 
 import (
 	"bytes"
-	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
@@ -72,6 +71,14 @@ type PaginateProductParams struct {
 	Tags               []string `json:"tags" query:"tags" param:"tags"`
 	RecommendProductID string   `json:"recommend_product_id" query:"recommend_product_id" param:"recommend_product_id"`
 	ProductClass       string   `json:"product_class" param:"product_class" query:"product_class" form:"product_class"`
+
+	// Facets, options of a facet are OR-ed and the facets AND-ed
+	CategoryIDs   []string                `json:"category_ids" query:"category_ids" form:"category_ids"`
+	FabricIDs     []string                `json:"fabric_ids" query:"fabric_ids" form:"fabric_ids"`
+	MinOrders     []enums.FilterMinOrder  `json:"min_orders" query:"min_orders" form:"min_orders"`
+	Ratings       []enums.FilterRating    `json:"ratings" query:"ratings" form:"ratings"`
+	PriceTiers    []enums.FilterPriceTier `json:"price_tiers" query:"price_tiers" form:"price_tiers"`
+	IncludeFacets bool                    `json:"include_facets" query:"include_facets" form:"include_facets"`
 }
 
 func (r *ProductRepo) PaginateProducts(params PaginateProductParams) *query.Pagination {
@@ -82,73 +89,87 @@ func (r *ProductRepo) PaginateProducts(params PaginateProductParams) *query.Pagi
 	})
 
 	var result = query.New(r.db, builder).
-		WhereFunc(func(builder *query.Builder) {
-			if params.ParentCategoryID != "" {
-				builder.Where("p.category_id = ?", params.ParentCategoryID)
-			}
+		WhereFunc(r.whereProducts(params)).
+		WhereFunc(whereProductFacets(params)).
+		Page(params.Page).
+		Limit(params.Limit).
+		OrderBy("p.created_at DESC").
+		PagingFunc()
 
-			if params.SubCategorySlug != "" {
-				params.SubCategorySlug = strings.ToLower(strings.TrimSpace(params.SubCategorySlug))
-				builder.Where("ct.slug = ? OR ct.vi ->> 'slug' = ?", params.SubCategorySlug, params.SubCategorySlug)
-			} else if params.CategorySlug != "" {
-				params.CategorySlug = strings.ToLower(strings.TrimSpace(params.CategorySlug))
-				builder.Where("pr.slug = ? OR pr.vi ->> 'slug' = ?", params.CategorySlug, params.CategorySlug)
-			}
+	if params.IncludeFacets {
+		facets, err := r.GetProductFacets(params)
+		if err != nil {
+			r.logger.ErrorAny(err)
+		}
+		result.Metadata = facets
+	}
 
-			if params.CategoryID != "" {
-				var cateIds = NewCategoryRepo(r.db).GetChildCategoryIDs(params.CategoryID)
-				cateIds = append(cateIds, params.CategoryID)
-				builder.Where("p.category_id IN (?)", cateIds)
-			}
+	return result
+}
 
-			if params.ExceptedProductIDs != nil && len(params.ExceptedProductIDs) > 0 {
-				builder.Where("p.id NOT IN (?)", params.ExceptedProductIDs)
-			}
+// whereProducts filters of the product search shared by the results and the facet counts
+func (r *ProductRepo) whereProducts(params PaginateProductParams) query.WhereFunc {
+	return func(builder *query.Builder) {
+		if params.ParentCategoryID != "" {
+			builder.Where("p.category_id = ?", params.ParentCategoryID)
+		}
 
-			if len(params.ShopIDs) > 0 {
-				builder.Where("p.shop_id IN ?", params.ShopIDs)
-			}
+		if params.SubCategorySlug != "" {
+			params.SubCategorySlug = strings.ToLower(strings.TrimSpace(params.SubCategorySlug))
+			builder.Where("ct.slug = ? OR ct.vi ->> 'slug' = ?", params.SubCategorySlug, params.SubCategorySlug)
+		} else if params.CategorySlug != "" {
+			params.CategorySlug = strings.ToLower(strings.TrimSpace(params.CategorySlug))
+			builder.Where("pr.slug = ? OR pr.vi ->> 'slug' = ?", params.CategorySlug, params.CategorySlug)
+		}
 
-			if params.ReadyToShip {
-				builder.Where("p.ready_to_ship = ?", true)
-			}
+		if params.CategoryID != "" {
+			var cateIds = NewCategoryRepo(r.db).GetChildCategoryIDs(params.CategoryID)
+			cateIds = append(cateIds, params.CategoryID)
+			builder.Where("p.category_id IN (?)", cateIds)
+		}
 
-			if params.DailyDeal {
-				builder.Where("p.daily_deal = ?", true)
-			}
+		if params.ExceptedProductIDs != nil && len(params.ExceptedProductIDs) > 0 {
+			builder.Where("p.id NOT IN (?)", params.ExceptedProductIDs)
+		}
 
-			if params.RatingStar > 0 {
-				builder.Where("p.rating_star >= ?", params.RatingStar)
-			}
+		if len(params.ShopIDs) > 0 {
+			builder.Where("p.shop_id IN ?", params.ShopIDs)
+		}
 
-			if params.MinOrder > 0 {
-				builder.Where("p.min_order >= ?", params.MinOrder)
-			}
+		if params.ReadyToShip {
+			builder.Where("p.ready_to_ship = ?", true)
+		}
 
-			if params.ProductType != "" {
-				builder.Where("p.product_type = ?", params.ProductType)
-			}
+		if params.DailyDeal {
+			builder.Where("p.daily_deal = ?", true)
+		}
 
-			if len(params.Tags) > 0 {
-				for _, tag := range params.Tags {
-					tag = strings.TrimSpace(strings.ToLower(tag))
-					if enums.ProductTag(tag) == enums.ProductTagTrending {
-						builder.Where("p.is_trending = ?", true)
-					}
-				}
-			}
+		if params.RatingStar > 0 {
+			builder.Where("p.rating_star >= ?", params.RatingStar)
+		}
 
-			if strings.TrimSpace(params.Keyword) != "" {
-				var q = "%" + params.Keyword + "%"
-				builder.Where("p.name ILIKE @query", sql.Named("query", q))
+		if params.MinOrder > 0 {
+			builder.Where("p.min_order >= ?", params.MinOrder)
+		}
+
+		if params.ProductType != "" {
+			builder.Where("p.product_type = ?", params.ProductType)
+		}
+
+		if len(params.Tags) > 0 {
+			for _, tag := range params.Tags {
+				tag = strings.TrimSpace(strings.ToLower(tag))
+				if enums.ProductTag(tag) == enums.ProductTagTrending {
+					builder.Where("p.is_trending = ?", true)
+				}
 			}
-		}).
-		Page(params.Page).
-		Limit(params.Limit).
-		OrderBy("p.created_at DESC").
-		PagingFunc()
+		}
 
-	return result
+		if strings.TrimSpace(params.Keyword) != "" {
+			var condition, args = productsSearchDocument.keywordCondition("p", params.Keyword)
+			builder.Where(condition, args...)
+		}
+	}
 }
 
 type PaginateRecommendProductParams struct {
@@ -221,8 +242,8 @@ func (r *ProductRepo) PaginateJustForYouProducts(params PaginateProductParams) *
 			}
 
 			if strings.TrimSpace(params.Keyword) != "" {
-				var q = "%" + params.Keyword + "%"
-				builder.Where("name ILIKE @query", sql.Named("query", q))
+				var condition, args = productsSearchDocument.keywordCondition("p", params.Keyword)
+				builder.Where(condition, args...)
 			}
 		}).
 		Page(params.Page).
@@ -255,8 +276,8 @@ func (r *ProductRepo) PaginateBestSellingProducts(params PaginateProductParams)
 			}
 
 			if strings.TrimSpace(params.Keyword) != "" {
-				var q = "%" + params.Keyword + "%"
-				builder.Where("name ILIKE @query", sql.Named("query", q))
+				var condition, args = productsSearchDocument.keywordCondition("p", params.Keyword)
+				builder.Where(condition, args...)
 			}
 		}).
 		Page(params.Page).
@@ -36,11 +36,16 @@ func (r *PurchaseOrderTrackingRepo) CreatePurchaseOrderTrackingTx(tx *gorm.DB, p
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
-	return err
+
+	// Push the change to the clients following the order
+	return NewOutboxRepo(r.db).AddTasksTx(tx, form.PurchaseOrderID, models.NewTopicEvent(
+		models.NewTopic(enums.TopicKindPurchaseOrder, form.PurchaseOrderID), enums.TopicEventTypeTrackingCreated, form,
+	))
 }
 
 type PaginatePurchaseOrderTrackingParams struct {
 	models.PaginationParams
+	models.CursorParams
 	models.JwtClaimsInfo
 
 	PurchaseOrderID string                    `json:"purchase_order_id" query:"purchase_order_id" form:"purchase_order_id" param:"purchase_order_id" validate:"required"`
@@ -50,6 +55,13 @@ type PaginatePurchaseOrderTrackingParams struct {
 }
 
 func (r *PurchaseOrderTrackingRepo) PaginatePurchaseOrderTrackings(params PaginatePurchaseOrderTrackingParams) *query.Pagination {
+	// The admin tracking tables keep the offset pages with the exact total
+	var countMode = query.CountModeEstimate
+	if params.GetRole().IsAdmin() {
+		params.CursorParams = models.CursorParams{}
+		countMode = query.CountModeExact
+	}
+
 	var result = query.New(r.db, queryfunc.NewPurchaseOrderTrackingBuilder(queryfunc.PurchaseOrderTrackingBuilderOptions{
 		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
 			Role: params.GetRole(),
@@ -66,6 +78,9 @@ func (r *PurchaseOrderTrackingRepo) PaginatePurchaseOrderTrackings(params Pagina
 				builder.Where("pot.user_group = ?", params.UserGroup)
 			}
 		}).
+		Cursor(params.Cursor, query.CursorKey{Column: "pot.created_at", Field: "CreatedAt", Desc: true}, query.CursorKey{Column: "pot.id", Field: "ID", Desc: true}).
+		WithCursorPaging(params.IsCursorPaging()).
+		WithCountMode(countMode).
 		Limit(params.Limit).
 		Page(params.Page).
 		PagingFunc()
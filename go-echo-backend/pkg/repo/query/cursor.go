@@ -0,0 +1,307 @@
+package query
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+)
+
+const defaultCursorLimit = 20
+
+// CountMode how the total of the records is counted
+type CountMode string
+
+var (
+	// CountModeExact runs the count query, the default
+	CountModeExact CountMode = ""
+
+	// CountModeEstimate reads the row estimate of the query plan
+	CountModeEstimate CountMode = "estimate"
+
+	// CountModeNone skips the count
+	CountModeNone CountMode = "none"
+)
+
+// CursorKey column of the keyset sort, the last key must be unique to break the ties of the others
+type CursorKey struct {
+	// Column SQL column, e.g. c.created_at
+	Column string
+
+	// Field field of the records holding the column value, e.g. CreatedAt
+	Field string
+
+	Desc bool
+}
+
+// cursorPayload values of the keys of the last record of the page
+type cursorPayload struct {
+	Values []interface{} `json:"v"`
+}
+
+// EncodeCursor opaque cursor of the key values
+func EncodeCursor(values ...interface{}) string {
+	data, _ := json.Marshal(cursorPayload{Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor values of the keys encoded in the cursor
+func DecodeCursor(cursor string, keys int) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, eris.Wrap(err, "invalid cursor")
+	}
+
+	var decoder = json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	var payload cursorPayload
+	if err = decoder.Decode(&payload); err != nil {
+		return nil, eris.Wrap(err, "invalid cursor")
+	}
+	if len(payload.Values) != keys {
+		return nil, eris.Errorf("invalid cursor: %d values for %d keys", len(payload.Values), keys)
+	}
+
+	for index, value := range payload.Values {
+		switch v := value.(type) {
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				payload.Values[index] = i
+			} else if f, err := v.Float64(); err == nil {
+				payload.Values[index] = f
+			}
+		case string, bool:
+		default:
+			return nil, eris.Errorf("invalid cursor value %v", value)
+		}
+	}
+
+	return payload.Values, nil
+}
+
+// Cursor keys of the keyset sort, new records don't shift the next pages.
+// The list is paged by the keys only when a cursor is sent or WithCursorPaging is enabled, the offset pages are ordered by the unique last key on ties.
+func (b *Builder) Cursor(cursor string, keys ...CursorKey) *Builder {
+	b.cursor = cursor
+	b.cursorKeys = keys
+	return b
+}
+
+// WithCursorPaging pages the first page by the cursor keys, the client has to opt in because the response has no page and total_page
+func (b *Builder) WithCursorPaging(enabled bool) *Builder {
+	b.cursorPaging = enabled
+	return b
+}
+
+// WithCountMode counts the total exactly, from the query plan estimate or not at all
+func (b *Builder) WithCountMode(mode CountMode) *Builder {
+	b.countMode = mode
+	return b
+}
+
+func (b *Builder) isCursorMode() bool {
+	return len(b.cursorKeys) > 0 && (b.cursor != "" || b.cursorPaging)
+}
+
+// tiebreakerOrderBy order by of the builder ending with the unique cursor key, so the offset pages don't repeat or skip the ties
+func (b *Builder) tiebreakerOrderBy() string {
+	if len(b.cursorKeys) == 0 {
+		return b.orderBy
+	}
+
+	var key = b.cursorKeys[len(b.cursorKeys)-1]
+	if strings.Contains(b.orderBy, key.Column) {
+		return b.orderBy
+	}
+
+	var tiebreaker = fmt.Sprintf("%s %s", key.Column, lo.Ternary(key.Desc, "DESC", "ASC"))
+	if b.orderBy == "" {
+		return tiebreaker
+	}
+
+	return fmt.Sprintf("%s, %s", b.orderBy, tiebreaker)
+}
+
+// cursorWhere records after the cursor: (k1 > v1) OR (k1 = v1 AND k2 > v2) ..., the comparison follows the direction of each key
+func (b *Builder) cursorWhere(values []interface{}) string {
+	var conditions []string
+	for index, key := range b.cursorKeys {
+		var terms []string
+		for previous := 0; previous < index; previous++ {
+			terms = append(terms, fmt.Sprintf("%s = @cursor_%d", b.cursorKeys[previous].Column, previous))
+		}
+
+		var operator = lo.Ternary(key.Desc, "<", ">")
+		terms = append(terms, fmt.Sprintf("%s %s @cursor_%d", key.Column, operator, index))
+		conditions = append(conditions, strings.Join(terms, " AND "))
+	}
+
+	for index, value := range values {
+		b.Where(sql.Named(fmt.Sprintf("cursor_%d", index), value))
+	}
+
+	return fmt.Sprintf("((%s))", strings.Join(conditions, ") OR ("))
+}
+
+func (b *Builder) cursorOrderBy() string {
+	var orderBy []string
+	for _, key := range b.cursorKeys {
+		orderBy = append(orderBy, fmt.Sprintf("%s %s", key.Column, lo.Ternary(key.Desc, "DESC", "ASC")))
+	}
+
+	return strings.Join(orderBy, ", ")
+}
+
+// PagingCursorFunc fetches one record more than the limit to know if there is a next page, the total is only counted for the first page
+func (b *Builder) PagingCursorFunc(f ...ExecFunc) *Pagination {
+	var fn = b.GetPagingFunc(f...)
+	if fn == nil {
+		panic(fmt.Errorf("fn is not implement"))
+	}
+
+	if b.limit <= 0 {
+		b.limit = defaultCursorLimit
+	}
+
+	if b.cursor != "" {
+		values, err := DecodeCursor(b.cursor, len(b.cursorKeys))
+		if err != nil {
+			b.db.CustomLogger.Debugf("Paginate from the first page, %v", err)
+			b.cursor = ""
+		} else {
+			b.Where(b.cursorWhere(values))
+		}
+	}
+
+	var limit = b.limit
+	b.orderBy = b.cursorOrderBy()
+	b.limit = limit + 1
+	b.page = 0
+	sqlString, countSQLString := b.build()
+	b.limit = limit
+
+	var pagination = Pagination{
+		PerPage: limit,
+		Cursor:  b.cursor,
+	}
+
+	var values = b.mergeValues()
+	if b.cursor == "" {
+		pagination.TotalRecord, pagination.IsEstimatedTotal = b.countTotal(countSQLString, values)
+	}
+
+	result, err := fn(b.db, b.db.WithGorm(b.db.Clauses(b.clauses...).Raw(sqlString, values...)))
+	if err != nil {
+		b.db.CustomLogger.ErrorAny(err)
+	}
+
+	var records = b.sliceResult(result)
+	if records.IsValid() && records.Len() > limit {
+		pagination.HasNext = true
+		if records.CanSet() {
+			records.SetLen(limit)
+		} else {
+			records = records.Slice(0, limit)
+			result = records.Interface()
+		}
+	}
+
+	pagination.Records = result
+	pagination.TotalCurrentRecord = b.countResult(result)
+	pagination.HasPrev = b.cursor != ""
+
+	if pagination.HasNext {
+		pagination.NextCursor = b.recordCursor(records.Index(limit - 1))
+	}
+
+	return &pagination
+}
+
+// countTotal total of the count query with the count mode of the builder
+func (b *Builder) countTotal(countSQLString string, values []interface{}) (total int, estimated bool) {
+	switch b.countMode {
+	case CountModeNone:
+		return 0, false
+
+	case CountModeEstimate:
+		var plan string
+		var err = b.db.Clauses(b.clauses...).Raw(fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", countSQLString), values...).Row().Scan(&plan)
+		if err != nil {
+			b.db.CustomLogger.DebugAny(err)
+			return 0, true
+		}
+
+		return GetPlanRows(plan), true
+	}
+
+	var err = b.db.Clauses(b.clauses...).Raw(fmt.Sprintf(`
+SELECT COUNT(1)
+FROM (
+%s
+) t
+	`, countSQLString), values...).Row().Scan(&total)
+	if err != nil {
+		b.db.CustomLogger.DebugAny(err)
+	}
+
+	return total, false
+}
+
+// GetPlanRows estimated rows of the top node of an EXPLAIN (FORMAT JSON) plan
+func GetPlanRows(plan string) int {
+	var explain []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil || len(explain) == 0 {
+		return 0
+	}
+
+	return int(explain[0].Plan.PlanRows)
+}
+
+// sliceResult slice of the records returned by the paging func
+func (b *Builder) sliceResult(result interface{}) reflect.Value {
+	if result == nil {
+		return reflect.Value{}
+	}
+
+	var rResult = reflect.ValueOf(result)
+	for rResult.Kind() == reflect.Ptr {
+		rResult = rResult.Elem()
+	}
+	if rResult.Kind() != reflect.Slice {
+		return reflect.Value{}
+	}
+
+	return rResult
+}
+
+// recordCursor cursor of the key values of the record
+func (b *Builder) recordCursor(record reflect.Value) string {
+	for record.Kind() == reflect.Ptr || record.Kind() == reflect.Interface {
+		if record.IsNil() {
+			return ""
+		}
+		record = record.Elem()
+	}
+
+	var values []interface{}
+	for _, key := range b.cursorKeys {
+		var field = record.FieldByName(key.Field)
+		if !field.IsValid() {
+			b.db.CustomLogger.Errorf("Cursor field %s not found in %s", key.Field, record.Type())
+			return ""
+		}
+		values = append(values, field.Interface())
+	}
+
+	return EncodeCursor(values...)
+}
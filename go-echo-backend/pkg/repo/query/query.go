@@ -24,6 +24,7 @@ type QueryBuilder interface {
 	IsWrapJSON() bool
 	IsWrapSelect() bool
 	GetClauses() []clause.Expression
+	GetNamedArgs() map[string]interface{}
 }
 
 type ExecFunc = func(db *db.DB, rawSQL *db.DB) (interface{}, error)
@@ -43,6 +44,9 @@ type Pagination struct {
 	TotalPage          int         `json:"total_page,omitempty"`
 	Metadata           interface{} `json:"metadata,omitempty"`
 	TotalCurrentRecord int         `json:"total_current_record,omitempty"`
+	IsEstimatedTotal   bool        `json:"is_estimated_total,omitempty"`
+	Cursor             string      `json:"cursor,omitempty"`
+	NextCursor         string      `json:"next_cursor,omitempty"`
 }
 
 type Builder struct {
@@ -59,7 +63,12 @@ type Builder struct {
 	having           string
 	wrapJSON         bool
 	wrapSelect       bool
+	wrapSQL          string
 	withoutCount     bool
+	countMode        CountMode
+	cursor           string
+	cursorKeys       []CursorKey
+	cursorPaging     bool
 	qf               QueryBuilder
 	clauses          []clause.Expression
 }
@@ -80,6 +89,9 @@ func New(db *db.DB, qf QueryBuilder) *Builder {
 		qf:               qf,
 		clauses:          qf.GetClauses(),
 	}
+	for name, value := range qf.GetNamedArgs() {
+		builder.namedWhereValues[name] = value
+	}
 
 	return builder
 }
@@ -94,6 +106,13 @@ func (b *Builder) WithWrapSelect(isWrapSelect bool) *Builder {
 	return b
 }
 
+// WithWrapSQL the query becomes the alias CTE of wrapSQL, to aggregate the filtered rows
+func (b *Builder) WithWrapSQL(wrapSQL string) *Builder {
+	b.wrapSQL = wrapSQL
+	return b
+}
+
+// Where appends the condition to the query, the condition is SQL written in code and the request values are bound by args or sql.Named
 func (b *Builder) Where(query interface{}, args ...interface{}) *Builder {
 	switch value := query.(type) {
 	case map[string]interface{}:
@@ -187,8 +206,8 @@ func (b *Builder) build() (queryString string, countQuery string) {
 		countQuery = fmt.Sprintf("%s HAVING %s", countQuery, b.having)
 	}
 
-	if b.orderBy != "" {
-		queryString = fmt.Sprintf("%s ORDER BY %s", queryString, b.orderBy)
+	if orderBy := b.tiebreakerOrderBy(); orderBy != "" {
+		queryString = fmt.Sprintf("%s ORDER BY %s", queryString, orderBy)
 	}
 
 	if b.limit > 0 {
@@ -222,6 +241,15 @@ SELECT * FROM (
 		`, queryString)
 	}
 
+	if b.wrapSQL != "" {
+		queryString = fmt.Sprintf(`
+WITH alias AS (
+%s
+)
+%s
+		`, queryString, b.wrapSQL)
+	}
+
 	return
 }
 
@@ -238,7 +266,11 @@ func (b *Builder) GetPagingFunc(f ...ExecFunc) ExecFunc {
 }
 
 func (b *Builder) PagingFunc(f ...ExecFunc) *Pagination {
-	if b.withoutCount {
+	if b.isCursorMode() {
+		return b.PagingCursorFunc(f...)
+	}
+
+	if b.withoutCount || b.countMode == CountModeNone {
 		return b.PagingInfiniteFunc(f...)
 	}
 
@@ -258,14 +290,19 @@ func (b *Builder) PagingFunc(f ...ExecFunc) *Pagination {
 	sqlString, countSQLString := b.build()
 
 	var values = b.mergeValues()
-	countSQLString = fmt.Sprintf(`
+	if b.countMode == CountModeEstimate {
+		count, pagination.IsEstimatedTotal = b.countTotal(countSQLString, values)
+		done <- true
+	} else {
+		countSQLString = fmt.Sprintf(`
 SELECT COUNT(1) 
 FROM (
 %s
 ) t
 	`, countSQLString)
-	var countSQL = b.db.WithGorm(b.db.Clauses(b.clauses...).Raw(countSQLString, values...))
-	go b.count(countSQL, done, &count)
+		var countSQL = b.db.WithGorm(b.db.Clauses(b.clauses...).Raw(countSQLString, values...))
+		go b.count(countSQL, done, &count)
+	}
 
 	result, err := fn(b.db, b.db.WithGorm(b.db.Clauses(b.clauses...).Raw(sqlString, values...)))
 	if err != nil {
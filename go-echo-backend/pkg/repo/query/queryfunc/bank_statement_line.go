@@ -0,0 +1,65 @@
+package queryfunc
+
+import (
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+)
+
+type BankStatementLineAlias struct {
+	*models.BankStatementLine
+}
+
+type BankStatementLineBuilderOptions struct {
+	QueryBuilderOptions
+}
+
+func NewBankStatementLineBuilder(options BankStatementLineBuilderOptions) *Builder {
+	var rawSQL = `
+	SELECT /* {{Description}} */ bsl.*
+
+	FROM bank_statement_lines bsl
+	`
+	var countSQL = `
+	SELECT /* {{Description}} */ 1
+
+	FROM bank_statement_lines bsl
+	`
+
+	return NewBuilder(rawSQL, countSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		}).
+		WithOrderBy("bsl.booking_date DESC, bsl.created_at DESC").
+		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
+			var records = make([]*models.BankStatementLine, 0, rawSQL.RowsAffected)
+
+			rows, err := rawSQL.Rows()
+			if err != nil {
+				return nil, err
+
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var alias BankStatementLineAlias
+				err = db.ScanRows(rows, &alias)
+				if err != nil {
+					db.CustomLogger.Errorf("Scan rows error", err)
+					continue
+				}
+
+				records = append(records, alias.BankStatementLine)
+			}
+
+			return &records, nil
+		})
+}
@@ -24,7 +24,13 @@ func NewChatMessageBuilder(options ChatMessageBuilderOptions) *Builder {
 	c.message,
 	c.message_type,
 	c.attachments,
-	c.seen_at
+	c.seen_at,
+	c.reply_to_id,
+	c.thread_id,
+	c.reply_count,
+	c.edited_at,
+	c.removed_at,
+	c.removed_by_user_id
 	
 	FROM chat_messages c
 	`
@@ -57,6 +63,8 @@ func NewChatMessageBuilder(options ChatMessageBuilderOptions) *Builder {
 			defer rows.Close()
 
 			var userIds []string
+			var messageIDs []string
+			var replyToIDs []string
 
 			for rows.Next() {
 				var copy models.ChatMessage
@@ -66,10 +74,14 @@ func NewChatMessageBuilder(options ChatMessageBuilderOptions) *Builder {
 				}
 
 				userIds = append(userIds, copy.SenderID)
+				messageIDs = append(messageIDs, copy.ID)
+				if copy.ReplyToID != "" {
+					replyToIDs = append(replyToIDs, copy.ReplyToID)
+				}
 				records = append(records, &copy)
 			}
 			var wg sync.WaitGroup
-			wg.Add(1)
+			wg.Add(3)
 			go func() {
 				defer wg.Done()
 				if len(userIds) > 0 {
@@ -88,6 +100,41 @@ func NewChatMessageBuilder(options ChatMessageBuilderOptions) *Builder {
 					}
 				}
 			}()
+
+			go func() {
+				defer wg.Done()
+				if len(messageIDs) > 0 {
+					var reactions models.ChatMessageReactions
+					err := db.Select("MessageID", "UserID", "Emoji").Order("created_at ASC").Find(&reactions, "message_id IN ?", messageIDs).Error
+					if err != nil {
+						return
+					}
+
+					var summaries = reactions.GroupByMessageID()
+					for _, record := range records {
+						record.Reactions = summaries[record.ID]
+					}
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+				if len(replyToIDs) > 0 {
+					var replyTos []*models.ChatMessage
+					err := db.Select("ID", "CreatedAt", "SenderID", "Message", "MessageType", "Attachments", "RemovedAt").Find(&replyTos, "id IN ?", replyToIDs).Error
+					if err != nil {
+						return
+					}
+
+					for _, replyTo := range replyTos {
+						for _, record := range records {
+							if record.ReplyToID == replyTo.ID {
+								record.ReplyTo = replyTo
+							}
+						}
+					}
+				}
+			}()
 			wg.Wait()
 
 			return &records, nil
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"text/template"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 )
@@ -17,7 +19,7 @@ type ChatRoomBuilderOptions struct {
 
 func NewChatRoomBuilder(options ChatRoomBuilderOptions) *Builder {
 	var rawSQL = `
-	SELECT /* ChatRoomBuilder {{.ChatRoom}} */ cc.* 
+	SELECT /* {{Description}} */ cc.* 
 	FROM (
 		SELECT cr.*,
 
@@ -55,7 +57,7 @@ func NewChatRoomBuilder(options ChatRoomBuilderOptions) *Builder {
 
 	if options.Role == enums.RoleClient {
 		rawSQL = `
-	SELECT /* ChatRoomBuilder {{.ChatRoom}} */ cc.* 
+	SELECT /* {{Description}} */ cc.* 
 	FROM (
 		SELECT cr.*, 
 
@@ -92,29 +94,37 @@ func NewChatRoomBuilder(options ChatRoomBuilderOptions) *Builder {
 	`
 	}
 	if options.ReferenceIDKeyWord != "" {
-		unionStmt := fmt.Sprintf(`WITH union_data AS (
+		var unionStmt = `WITH union_data AS (
 		SELECT u.id FROM (
 			SELECT id,reference_id FROM inquiries UNION
 			SELECT id,reference_id FROM purchase_orders po WHERE po.status = 'paid' OR from_catalog = true UNION
 			SELECT id,reference_id FROM bulk_purchase_orders
 		) AS u
-		WHERE u.reference_id ILIKE '%%%s%%'
-	)`, options.ReferenceIDKeyWord)
+		WHERE u.reference_id ILIKE {{ Param "reference_id_keyword" (Contains .ReferenceIDKeyWord) }}
+	)`
 		if options.Role == enums.RoleClient {
-			unionStmt = fmt.Sprintf(`WITH union_data AS (
+			unionStmt = `WITH union_data AS (
 			SELECT u.id FROM (
 				SELECT id,reference_id FROM inquiries WHERE user_id = @userID UNION
 				SELECT id,reference_id FROM purchase_orders po WHERE (po.status = 'paid' OR from_catalog = true) AND user_id = @userID UNION
 				SELECT id,reference_id FROM bulk_purchase_orders WHERE user_id = @userID
 			) AS u
-			WHERE u.reference_id ILIKE '%%%s%%'
-		)`, options.ReferenceIDKeyWord)
+			WHERE u.reference_id ILIKE {{ Param "reference_id_keyword" (Contains .ReferenceIDKeyWord) }}
+		)`
 		}
 
 		rawSQL = fmt.Sprintf("%s %s", unionStmt, rawSQL)
 	}
 	return NewBuilder(rawSQL).
-		WithOptions(options).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		}).
 		WithOrderBy("cc.latest_message_json ->> 'created_at' DESC NULLS LAST").
 		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
 			var records = make([]*models.ChatRoom, rawSQL.RowsAffected)
@@ -210,7 +210,7 @@ func NewRankDAProductBuilder(options RankDAProductBuilderOptions) *Builder {
 				return "p.id, p.created_at, p.updated_at, p.url , p.country_code,p.domain ,p.name , p.description , p.category , p.sub_category , p.images , p.private_images , p.price , p.sold , p.stock , p.trending"
 			},
 			"order_by": func() string {
-				return options.OrderBy
+				return strings.Join(SafeIdentifiers([]string{options.OrderBy}, "sold"), "")
 			},
 		}).
 		WithOrderBy("pc.score desc, p.id desc").
@@ -247,6 +247,9 @@ type RankDACategoryAlias struct {
 	models.DataAnalyticCategory
 }
 
+// RankDACategoryColumns columns the categories can be grouped by
+var RankDACategoryColumns = []string{"category", "sub_category"}
+
 type RankDACategoryBuilderOptions struct {
 	models.PaginationParams
 	QueryBuilderOptions
@@ -257,6 +260,12 @@ type RankDACategoryBuilderOptions struct {
 }
 
 func NewRankDACategoryBuilder(options RankDACategoryBuilderOptions) *Builder {
+	var groupBy = strings.Join(SafeIdentifiers(options.Select, RankDACategoryColumns...), ",")
+	var orderBy = strings.Join(SafeIdentifiers([]string{options.OrderBy}, "sold", "rev"), "")
+	if orderBy == "" {
+		orderBy = "sold"
+	}
+
 	var rawSQL = `
 	SELECT /* {{Description}} */  {{group_by}}, sum(sold) as sold, sum(sold * price) as rev, json_agg(distinct domain) as domains
 	FROM products p
@@ -277,11 +286,11 @@ func NewRankDACategoryBuilder(options RankDACategoryBuilderOptions) *Builder {
 				)
 			},
 			"group_by": func() string {
-				return strings.Join(options.Select, ",")
+				return groupBy
 			},
 		}).
-		WithGroupBy(strings.Join(options.Select, ",")).
-		WithOrderBy(fmt.Sprintf("%s DESC", options.OrderBy)).
+		WithGroupBy(groupBy).
+		WithOrderBy(fmt.Sprintf("%s DESC", orderBy)).
 		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
 			var records = make([]*models.DataAnalyticCategory, rawSQL.RowsAffected)
 
@@ -0,0 +1,65 @@
+package queryfunc
+
+import (
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+)
+
+type DeadLetterTaskAlias struct {
+	*models.DeadLetterTask
+}
+
+type DeadLetterTaskBuilderOptions struct {
+	QueryBuilderOptions
+}
+
+func NewDeadLetterTaskBuilder(options DeadLetterTaskBuilderOptions) *Builder {
+	var rawSQL = `
+	SELECT /* {{Description}} */ d.*
+
+	FROM dead_letter_tasks d
+	`
+	var countSQL = `
+	SELECT /* {{Description}} */ 1
+
+	FROM dead_letter_tasks d
+	`
+
+	return NewBuilder(rawSQL, countSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		}).
+		WithOrderBy("d.failed_at DESC").
+		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
+			var records = make([]*models.DeadLetterTask, 0, rawSQL.RowsAffected)
+
+			rows, err := rawSQL.Rows()
+			if err != nil {
+				return nil, err
+
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var alias DeadLetterTaskAlias
+				err = db.ScanRows(rows, &alias)
+				if err != nil {
+					db.CustomLogger.Errorf("Scan rows error", err)
+					continue
+				}
+
+				records = append(records, alias.DeadLetterTask)
+			}
+
+			return &records, nil
+		})
+}
@@ -1,9 +1,10 @@
 package queryfunc
 
 import (
-	"fmt"
+	"text/template"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 )
@@ -24,7 +25,7 @@ type InventoryAlias struct {
 
 func NewInventoryBuilder(options InventoryBuilderOptions) *Builder {
 	var rawSQL = `
-	SELECT /* InventoryBuilder - %s */ v.*,
+	SELECT /* {{Description}} */ v.*,
 	p.id AS p__id,
 	p.name AS p__name,
 	p.short_description AS p__short_description,
@@ -47,7 +48,16 @@ func NewInventoryBuilder(options InventoryBuilderOptions) *Builder {
 	JOIN products p ON p.id = v.product_id
 	`
 
-	return NewBuilder(fmt.Sprintf(rawSQL, options.Comment)).
+	return NewBuilder(rawSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					SQLComment(options.Comment),
+				)
+			},
+		}).
 		WithOrderBy("v.stock ASC").
 		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
 			var records = make([]*models.Variant, rawSQL.RowsAffected)
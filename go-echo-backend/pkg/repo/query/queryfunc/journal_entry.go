@@ -0,0 +1,65 @@
+package queryfunc
+
+import (
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+)
+
+type JournalEntryAlias struct {
+	*models.JournalEntry
+}
+
+type JournalEntryBuilderOptions struct {
+	QueryBuilderOptions
+}
+
+func NewJournalEntryBuilder(options JournalEntryBuilderOptions) *Builder {
+	var rawSQL = `
+	SELECT /* {{Description}} */ je.*
+
+	FROM journal_entries je
+	`
+	var countSQL = `
+	SELECT /* {{Description}} */ 1
+
+	FROM journal_entries je
+	`
+
+	return NewBuilder(rawSQL, countSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		}).
+		WithOrderBy("je.entry_date ASC, je.created_at ASC").
+		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
+			var records = make([]*models.JournalEntry, 0, rawSQL.RowsAffected)
+
+			rows, err := rawSQL.Rows()
+			if err != nil {
+				return nil, err
+
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var alias JournalEntryAlias
+				err = db.ScanRows(rows, &alias)
+				if err != nil {
+					db.CustomLogger.Errorf("Scan rows error", err)
+					continue
+				}
+
+				records = append(records, alias.JournalEntry)
+			}
+
+			return &records, nil
+		})
+}
@@ -0,0 +1,65 @@
+package queryfunc
+
+import (
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+)
+
+type OutboxMessageAlias struct {
+	*models.OutboxMessage
+}
+
+type OutboxMessageBuilderOptions struct {
+	QueryBuilderOptions
+}
+
+func NewOutboxMessageBuilder(options OutboxMessageBuilderOptions) *Builder {
+	var rawSQL = `
+	SELECT /* {{Description}} */ o.*
+
+	FROM outbox_messages o
+	`
+	var countSQL = `
+	SELECT /* {{Description}} */ 1
+
+	FROM outbox_messages o
+	`
+
+	return NewBuilder(rawSQL, countSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		}).
+		WithOrderBy("o.created_at ASC").
+		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
+			var records = make([]*models.OutboxMessage, 0, rawSQL.RowsAffected)
+
+			rows, err := rawSQL.Rows()
+			if err != nil {
+				return nil, err
+
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var alias OutboxMessageAlias
+				err = db.ScanRows(rows, &alias)
+				if err != nil {
+					db.CustomLogger.Errorf("Scan rows error", err)
+					continue
+				}
+
+				records = append(records, alias.OutboxMessage)
+			}
+
+			return &records, nil
+		})
+}
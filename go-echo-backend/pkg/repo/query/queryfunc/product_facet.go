@@ -0,0 +1,40 @@
+package queryfunc
+
+import (
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+)
+
+type ProductFacetBuilderOptions struct {
+	QueryBuilderOptions
+
+	// Matches match column of each facet, the counts of a facet apply the conditions of the other ones
+	Matches []SQL
+}
+
+// NewProductFacetBuilder selects the filtered products with a match column per facet, the facet counts aggregate it with WithWrapSQL
+func NewProductFacetBuilder(options ProductFacetBuilderOptions) *Builder {
+	var rawSQL = `
+	SELECT /* {{Description}} */ p.id, p.category_id, p.fabric_ids, p.min_order, p.rating_star, p.price,
+	{{- range .Matches }}
+	{{ Fragment . }},
+	{{- end }}
+	p.created_at
+
+	FROM products p
+	LEFT JOIN categories ct ON p.category_id = ct.id
+    LEFT JOIN categories pr ON ct.parent_category_id = pr.id
+	`
+
+	return NewBuilder(rawSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					options.Role.DisplayName(),
+				)
+			},
+		})
+}
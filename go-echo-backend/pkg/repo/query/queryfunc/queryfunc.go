@@ -31,6 +31,7 @@ type Builder struct {
 	paginationFn Handler
 	clauses      []clause.Expression
 	options      interface{}
+	namedArgs    map[string]interface{}
 }
 
 func NewBuilder(rawSQL string, countRawSQL ...string) *Builder {
@@ -144,12 +145,17 @@ func (b *Builder) GetClauses() []clause.Expression {
 	return b.clauses
 }
 
+// GetNamedArgs values bound by {{ Param }} in the templates
+func (b *Builder) GetNamedArgs() map[string]interface{} {
+	return b.namedArgs
+}
+
 func (b *Builder) GetRawSQLTemplateStr(funcs ...template.FuncMap) string {
-	return helper.TemplateStr(b.rawSQL, b.options, funcs...)
+	return b.renderSQL(b.rawSQL, funcs...)
 }
 
 func (b *Builder) GetCountRawSQLTemplateStr(funcs ...template.FuncMap) string {
-	return helper.TemplateStr(b.countRawSQL, b.options, funcs...)
+	return b.renderSQL(b.countRawSQL, funcs...)
 }
 
 func GetCaller() string {
@@ -1,8 +1,9 @@
 package queryfunc
 
 import (
-	"fmt"
+	"text/template"
 
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 )
 
@@ -14,10 +15,19 @@ type StatsBuyersBuilderOptions struct {
 
 func NewStatsBuyersBuilder(options StatsBuyersBuilderOptions) *Builder {
 	var rawSQL = `
-	SELECT /* StatsBuyersBuilder - %s */ COUNT(1) AS total_records
+	SELECT /* {{Description}} */ COUNT(1) AS total_records
 
 	FROM users u
 	`
 
-	return NewBuilder(fmt.Sprintf(rawSQL, options.Comment))
+	return NewBuilder(rawSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					SQLComment(options.Comment),
+				)
+			},
+		})
 }
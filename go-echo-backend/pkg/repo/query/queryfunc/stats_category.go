@@ -1,8 +1,9 @@
 package queryfunc
 
 import (
-	"fmt"
+	"text/template"
 
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 )
 
@@ -14,10 +15,19 @@ type StatsCategoriesBuilderOptions struct {
 
 func NewStatsCategoriesBuilder(options StatsCategoriesBuilderOptions) *Builder {
 	var rawSQL = `
-	SELECT /* StatsCategoriesBuilder - %s */ COUNT(1) AS total_records
+	SELECT /* {{Description}} */ COUNT(1) AS total_records
 
 	FROM categories c
 	`
 
-	return NewBuilder(fmt.Sprintf(rawSQL, options.Comment))
+	return NewBuilder(rawSQL).
+		WithOptions(options, template.FuncMap{
+			"Description": func() string {
+				return helper.JoinNonEmptyStrings(
+					"-",
+					GetCaller(),
+					SQLComment(options.Comment),
+				)
+			},
+		})
 }
@@ -0,0 +1,156 @@
+package queryfunc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/rotisserie/eris"
+)
+
+// SQL fragment written in code, the only value type a template prints besides the bound parameters
+type SQL string
+
+var paramNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// valueFuncs prepare a value for Param, printing their result would interpolate the value
+var valueFuncs = map[string]bool{
+	"Contains": true,
+	"Prefix":   true,
+}
+
+// EscapeLike escapes the LIKE wildcards of the value
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// SQLComment text safe to print inside a /* */ comment
+func SQLComment(comment string) string {
+	return strings.NewReplacer("/*", "", "*/", "").Replace(comment)
+}
+
+// SafeIdentifiers keeps the identifiers of the allow list, for the columns picked by the request
+func SafeIdentifiers(identifiers []string, allowed ...string) []string {
+	var result []string
+	for _, identifier := range identifiers {
+		for _, value := range allowed {
+			if identifier == value {
+				result = append(result, identifier)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// templateFuncs binds the values of the options as named parameters:
+//
+//	WHERE u.reference_id ILIKE {{ Param "reference_id" (Contains .Keyword) }}
+//
+// renders `@reference_id` and the query binds the value.
+func (b *Builder) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"Caller": func() string {
+			return fmt.Sprintf("%s:%s:%s", helper.GetFuncName(16), helper.GetFuncName(17), helper.GetFuncName(18))
+		},
+		"Param": func(name string, value interface{}) (SQL, error) {
+			if !paramNameRegexp.MatchString(name) {
+				return "", fmt.Errorf("invalid parameter name %q", name)
+			}
+			if b.namedArgs == nil {
+				b.namedArgs = map[string]interface{}{}
+			}
+			b.namedArgs[name] = value
+			return SQL("@" + name), nil
+		},
+		"Fragment": func(fragment SQL) SQL {
+			return fragment
+		},
+		"Contains": func(value string) string {
+			return "%" + EscapeLike(value) + "%"
+		},
+		"Prefix": func(value string) string {
+			return EscapeLike(value) + "%"
+		},
+	}
+}
+
+// renderSQL executes the SQL template with the options, a template printing a value panics as it would be interpolated in the query
+func (b *Builder) renderSQL(sql string, funcs ...template.FuncMap) string {
+	var mergeFuncs = b.templateFuncs()
+	for _, funcMap := range funcs {
+		for name, f := range funcMap {
+			mergeFuncs[name] = f
+		}
+	}
+
+	t, err := template.New("sql").Funcs(mergeFuncs).Parse(sql)
+	if err != nil {
+		return sql
+	}
+
+	if err = CheckSQLTemplate(t.Tree.Root, mergeFuncs); err != nil {
+		panic(eris.Wrapf(err, "unsafe SQL template %s", GetCaller()))
+	}
+
+	var buf = &bytes.Buffer{}
+	if err = t.Execute(buf, b.options); err != nil {
+		return sql
+	}
+
+	return buf.String()
+}
+
+// CheckSQLTemplate allows the actions printing the result of a SQL func, like {{ Param }} or {{ Description }}, values of the options and builtins like printf can't be printed
+func CheckSQLTemplate(node parse.Node, funcs template.FuncMap) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := CheckSQLTemplate(child, funcs); err != nil {
+				return err
+			}
+		}
+
+	case *parse.ActionNode:
+		if len(node.Pipe.Decl) > 0 {
+			return nil
+		}
+
+		var last = node.Pipe.Cmds[len(node.Pipe.Cmds)-1]
+		if ident, ok := last.Args[0].(*parse.IdentifierNode); !ok || funcs[ident.Ident] == nil || valueFuncs[ident.Ident] {
+			return fmt.Errorf("action %s prints a value, bind it with Param", node)
+		}
+
+	case *parse.IfNode:
+		return checkSQLTemplateBranch(&node.BranchNode, funcs)
+
+	case *parse.RangeNode:
+		return checkSQLTemplateBranch(&node.BranchNode, funcs)
+
+	case *parse.WithNode:
+		return checkSQLTemplateBranch(&node.BranchNode, funcs)
+
+	case *parse.TemplateNode:
+		return fmt.Errorf("action %s includes a template", node)
+	}
+
+	return nil
+}
+
+func checkSQLTemplateBranch(node *parse.BranchNode, funcs template.FuncMap) error {
+	if err := CheckSQLTemplate(node.List, funcs); err != nil {
+		return err
+	}
+
+	return CheckSQLTemplate(node.ElseList, funcs)
+}
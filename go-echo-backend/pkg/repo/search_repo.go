@@ -0,0 +1,310 @@
+package repo
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/samber/lo"
+)
+
+const (
+	searchTextConfig = "inflow_search"
+
+	searchMaxTerms = 8
+)
+
+// searchDocument columns of a table concatenated by search_text() the same way in the indexes and the queries
+type searchDocument struct {
+	table   string
+	columns []string
+}
+
+var (
+	usersSearchDocument = searchDocument{
+		table:   "users",
+		columns: []string{"name", "first_name", "last_name", "email::text", "brand_name", "company_name", "phone_number"},
+	}
+	inquiriesSearchDocument = searchDocument{
+		table:   "inquiries",
+		columns: []string{"reference_id", "title", "sku_note"},
+	}
+	productsSearchDocument = searchDocument{
+		table:   "products",
+		columns: []string{"name", "sku", "short_description"},
+	}
+)
+
+// searchSource table searched for a group
+type searchSource struct {
+	searchDocument
+
+	group enums.SearchGroup
+
+	id          string
+	referenceID string
+	title       string
+	subtitle    string
+	status      string
+}
+
+var searchSources = []searchSource{
+	{
+		searchDocument: usersSearchDocument,
+		group:          enums.SearchGroupUsers,
+		id:             "id",
+		referenceID:    "''",
+		title:          "COALESCE(NULLIF(name, ''), concat_ws(' ', first_name, last_name))",
+		subtitle:       "COALESCE(email::text, '')",
+		status:         "COALESCE(account_status, '')",
+	},
+	{
+		searchDocument: inquiriesSearchDocument,
+		group:          enums.SearchGroupInquiries,
+		id:             "id",
+		referenceID:    "reference_id",
+		title:          "title",
+		subtitle:       "COALESCE(sku_note, '')",
+		status:         "COALESCE(status, '')",
+	},
+	{
+		searchDocument: searchDocument{
+			table:   "purchase_orders",
+			columns: []string{"reference_id", "client_reference_id", "product_name"},
+		},
+		group:       enums.SearchGroupPurchaseOrders,
+		id:          "id",
+		referenceID: "reference_id",
+		title:       "COALESCE(product_name, '')",
+		subtitle:    "COALESCE(client_reference_id, '')",
+		status:      "COALESCE(status, '')",
+	},
+	{
+		searchDocument: searchDocument{
+			table:   "bulk_purchase_orders",
+			columns: []string{"reference_id", "product_name"},
+		},
+		group:       enums.SearchGroupBulkPurchaseOrders,
+		id:          "id",
+		referenceID: "reference_id",
+		title:       "COALESCE(product_name, '')",
+		subtitle:    "''",
+		status:      "COALESCE(status, '')",
+	},
+	{
+		searchDocument: productsSearchDocument,
+		group:          enums.SearchGroupProducts,
+		id:             "id",
+		referenceID:    "COALESCE(sku, '')",
+		title:          "COALESCE(name, '')",
+		subtitle:       "COALESCE(slug, '')",
+		status:         "''",
+	},
+	{
+		searchDocument: searchDocument{
+			table:   "invoices",
+			columns: []string{"display_number", "invoice_number::text", "consignee->>'name'", "consignee->>'email'"},
+		},
+		group:       enums.SearchGroupInvoices,
+		id:          "invoice_number::text",
+		referenceID: "COALESCE(NULLIF(display_number, ''), invoice_number::text)",
+		title:       "COALESCE(consignee->>'name', '')",
+		subtitle:    "COALESCE(consignee->>'email', '')",
+		status:      "COALESCE(status, '')",
+	},
+}
+
+// searchTeamGroups groups searched by the staff of a team, leaders and super admins search every group
+var searchTeamGroups = map[enums.Team][]enums.SearchGroup{
+	enums.TeamSales:           {enums.SearchGroupUsers, enums.SearchGroupInquiries, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupProducts},
+	enums.TeamCustomerService: {enums.SearchGroupUsers, enums.SearchGroupInquiries, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupProducts},
+	enums.TeamMarketing:       {enums.SearchGroupUsers, enums.SearchGroupInquiries, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupProducts},
+	enums.TeamOperator:        {enums.SearchGroupInquiries, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupProducts},
+	enums.TeamQA:              {enums.SearchGroupInquiries, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupProducts},
+	enums.TeamDesigner:        {enums.SearchGroupInquiries, enums.SearchGroupProducts},
+	enums.Finance:             {enums.SearchGroupUsers, enums.SearchGroupPurchaseOrders, enums.SearchGroupBulkPurchaseOrders, enums.SearchGroupInvoices},
+}
+
+type SearchRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewSearchRepo(db *db.DB) *SearchRepo {
+	return &SearchRepo{
+		db:     db,
+		logger: logger.New("repo/Search"),
+	}
+}
+
+type SearchParams struct {
+	models.JwtClaimsInfo
+
+	Keyword string              `json:"keyword" query:"keyword" form:"keyword" validate:"required,min=2,max=200"`
+	Groups  []enums.SearchGroup `json:"groups" query:"groups" form:"groups"`
+	Limit   int                 `json:"limit" query:"limit" form:"limit" validate:"max=20"`
+
+	Team enums.Team `json:"-"`
+}
+
+// GetSearchGroups groups the role can search, staff only search the groups of their team
+func GetSearchGroups(role enums.Role, team enums.Team) []enums.SearchGroup {
+	switch {
+	case role == enums.RoleSuperAdmin, role == enums.RoleLeader, role == enums.RoleStaff && team == enums.TeamDev:
+		return lo.Map(searchSources, func(source searchSource, index int) enums.SearchGroup {
+			return source.group
+		})
+
+	case role == enums.RoleStaff:
+		return searchTeamGroups[team]
+	}
+
+	return nil
+}
+
+// GetSearchTsQuery tsquery of the keyword terms, the last term matches as a prefix while the user is typing
+func GetSearchTsQuery(keyword string) string {
+	var terms = strings.FieldsFunc(strings.ToLower(keyword), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return ""
+	}
+	if len(terms) > searchMaxTerms {
+		terms = terms[:searchMaxTerms]
+	}
+
+	terms[len(terms)-1] = terms[len(terms)-1] + ":*"
+	return strings.Join(terms, " & ")
+}
+
+// Search ranks the records of every group visible to the user, typos are matched by trigram word similarity
+func (r *SearchRepo) Search(params SearchParams) (*models.SearchResponse, error) {
+	if params.Limit <= 0 {
+		params.Limit = 5
+	}
+
+	var groups = GetSearchGroups(params.GetRole(), params.Team)
+	if len(params.Groups) > 0 {
+		groups = lo.Intersect(groups, params.Groups)
+	}
+
+	var result = models.SearchResponse{
+		Keyword: params.Keyword,
+		Groups:  []*models.SearchGroupResult{},
+	}
+
+	var tsQuery = GetSearchTsQuery(params.Keyword)
+	if tsQuery == "" {
+		return &result, nil
+	}
+
+	for _, source := range searchSources {
+		if !lo.Contains(groups, source.group) {
+			continue
+		}
+
+		var hits = []*models.SearchHit{}
+		var err = r.db.Raw(source.searchSQL(params.GetRole()), map[string]interface{}{
+			"keyword":  strings.TrimSpace(params.Keyword),
+			"ts_query": tsQuery,
+			"limit":    params.Limit + 1,
+		}).Scan(&hits).Error
+		if err != nil {
+			return nil, err
+		}
+
+		var group = &models.SearchGroupResult{
+			Group:       source.group,
+			DisplayName: source.group.DisplayName(),
+			Hits:        hits,
+		}
+		if len(hits) > params.Limit {
+			group.Hits = hits[:params.Limit]
+			group.HasMore = true
+		}
+
+		result.Groups = append(result.Groups, group)
+	}
+
+	return &result, nil
+}
+
+// SetupSearchIndexes creates the full-text and trigram indexes of the searched tables
+func (r *SearchRepo) SetupSearchIndexes() error {
+	for _, source := range searchSources {
+		var statements = []string{
+			fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_%s_search_tsv ON %s USING GIN (%s)", source.table, source.table, source.tsVector("")),
+			fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_%s_search_trgm ON %s USING GIN (%s gin_trgm_ops)", source.table, source.table, source.document("")),
+		}
+
+		for _, statement := range statements {
+			if err := r.db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// document the searched text, the columns are qualified with the alias of the table in the query
+func (doc searchDocument) document(alias string) string {
+	var columns = doc.columns
+	if alias != "" {
+		columns = lo.Map(doc.columns, func(column string, index int) string {
+			return alias + "." + column
+		})
+	}
+
+	return fmt.Sprintf("search_text(%s)", strings.Join(columns, ", "))
+}
+
+func (doc searchDocument) tsVector(alias string) string {
+	return fmt.Sprintf("to_tsvector('%s', %s)", searchTextConfig, doc.document(alias))
+}
+
+// keywordCondition matches the keyword with the full-text or the trigram index of the table, the list filters use it instead of ILIKE
+func (doc searchDocument) keywordCondition(alias string, keyword string) (string, []interface{}) {
+	var condition = fmt.Sprintf("(%s @@ to_tsquery('%s', @%s_ts_query) OR search_text(@%s_keyword) <%% %s)",
+		doc.tsVector(alias), searchTextConfig, doc.table, doc.table, doc.document(alias),
+	)
+
+	return condition, []interface{}{
+		sql.Named(doc.table+"_ts_query", GetSearchTsQuery(keyword)),
+		sql.Named(doc.table+"_keyword", strings.TrimSpace(keyword)),
+	}
+}
+
+func (source searchSource) searchSQL(role enums.Role) string {
+	var where = []string{"deleted_at IS NULL"}
+	if source.group == enums.SearchGroupUsers && role != enums.RoleSuperAdmin {
+		where = append(where, fmt.Sprintf("role <> '%s'", enums.RoleSuperAdmin))
+	}
+
+	var rank = fmt.Sprintf("ts_rank_cd(%s, q.query) + word_similarity(search_text(@keyword), %s)", source.tsVector(""), source.document(""))
+	if source.referenceID != "''" {
+		rank = fmt.Sprintf("%s + CASE WHEN lower(%s) = lower(@keyword) THEN 1 ELSE 0 END", rank, source.referenceID)
+	}
+
+	return fmt.Sprintf(`
+	SELECT %s AS id, %s AS reference_id, %s AS title, %s AS subtitle, %s AS status, created_at,
+	ts_headline('%s', concat_ws(' ', %s), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet,
+	%s AS rank
+	FROM %s, to_tsquery('%s', @ts_query) q(query)
+	WHERE %s AND (%s @@ q.query OR search_text(@keyword) <%% %s)
+	ORDER BY rank DESC, created_at DESC
+	LIMIT @limit
+	`,
+		source.id, source.referenceID, source.title, source.subtitle, source.status,
+		searchTextConfig, strings.Join(source.columns, ", "),
+		rank,
+		source.table, searchTextConfig,
+		strings.Join(where, " AND "), source.tsVector(""), source.document(""),
+	)
+}
@@ -18,7 +18,6 @@ import (
 	"github.com/rotisserie/eris"
 	"github.com/samber/lo"
 	"github.com/thaitanloi365/go-utils/values"
-	"golang.org/x/exp/slices"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -339,14 +338,15 @@ func (r *SellerBulkPurchaseOrderRepo) AdminBulkPurchaseOrderFirstPayout(params A
 		SellerFirstPayoutTransferedAt:          values.Int64(time.Now().Unix()),
 		SellerFirstPayoutMarkAsPaidAt:          values.Int64(time.Now().Unix()),
 		SellerFirstPayoutPercentage:            &params.PayoutPercentage,
-		SellerTrackingStatus:                   enums.SellerBulkPoTrackingStatusFirstPaymentConfirm,
 		SellerPayoutTotalAmount:                bulkPO.SellerBulkQuotation.BulkQuotations[0].TotalPrice.ToPtr(),
 	}
 	updates.SellerFirstPayoutTotalAmount = updates.SellerPayoutTotalAmount.MultipleFloat64(params.PayoutPercentage).DivInt(100).ToPtr()
 	updates.SellerFinalPayoutTotalAmount = updates.SellerPayoutTotalAmount.SubPtr(updates.SellerFirstPayoutTotalAmount).ToPtr()
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var action = enums.BulkPoTrackingActionAdminSkipFirstPayout
 		if params.PayoutPercentage > 0 {
+			action = enums.BulkPoTrackingActionAdminFirstPayout
 			var transaction = models.PaymentTransaction{
 				BulkPurchaseOrderID: bulkPO.ID,
 				PaymentType:         enums.PaymentTypeBankTransfer,
@@ -372,21 +372,14 @@ func (r *SellerBulkPurchaseOrderRepo) AdminBulkPurchaseOrderFirstPayout(params A
 			}
 
 			updates.SellerFirstPayoutTransactionReferenceID = transaction.ReferenceID
-
-		} else {
-			updates.SellerTrackingStatus = enums.SellerBulkPoTrackingStatusFirstPaymentSkipped
-		}
-
-		var sqlResult = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPO.ID).Updates(&updates)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
 		}
 
-		if sqlResult.RowsAffected == 0 {
-			return errs.ErrBulkPoNotFound
-		}
-		return sqlResult.Error
-
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         bulkPO,
+			Action:        action,
+			Updates:       &updates,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -427,7 +420,6 @@ func (r *SellerBulkPurchaseOrderRepo) AdminBulkPurchaseOrderFinalPayout(params A
 		SellerFinalPayoutTransactionAttachment: params.TransactionAttachment,
 		SellerFinalPayoutTransferedAt:          values.Int64(time.Now().Unix()),
 		SellerFinalPayoutMarkAsPaidAt:          values.Int64(time.Now().Unix()),
-		SellerTrackingStatus:                   enums.SellerBulkPoTrackingStatusFinalPaymentConfirm,
 	}
 
 	if bulkPO.SellerPayoutTotalAmount != nil {
@@ -469,16 +461,12 @@ func (r *SellerBulkPurchaseOrderRepo) AdminBulkPurchaseOrderFinalPayout(params A
 
 		}
 
-		var sqlResult = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPO.ID).Updates(&updates)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			return errs.ErrBulkPoNotFound
-		}
-		return sqlResult.Error
-
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         bulkPO,
+			Action:        enums.BulkPoTrackingActionAdminFinalPayout,
+			Updates:       &updates,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -511,36 +499,29 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderUpdateRawMaterial(p
 
 	_ = updates.GenerateRawMaterialRefID(updates.SellerPoRawMaterials)
 
-	updates.SellerTrackingStatus = enums.SellerBulkPoTrackingStatusRawMaterial
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdateMaterial,
-			UserID:          params.GetUserID(),
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"po_raw_materials": order.PoRawMaterials,
-				},
-				After: map[string]interface{}{
-					"po_raw_materials": params.PoRawMaterials,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdateMaterial,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: params.GetUserID(),
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"po_raw_materials": order.PoRawMaterials,
+					},
+					After: map[string]interface{}{
+						"po_raw_materials": params.PoRawMaterials,
+					},
 				},
 			},
 		})
-		if err != nil {
-			return err
-		}
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
-
 	return order, err
 }
 
@@ -577,37 +558,33 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderUpdatePps(params Se
 	}
 
 	var updates = models.BulkPurchaseOrder{
-		SellerPpsInfo:        &ppsInfoArr,
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPps,
+		SellerPpsInfo: &ppsInfoArr,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionSellerUpdatePps,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			UserID:          params.GetUserID(),
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_pps_info": order.PpsInfo,
-				},
-				After: map[string]interface{}{
-					"seller_pps_info": updates.PpsInfo,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSellerUpdatePps,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: params.GetUserID(),
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"seller_pps_info": order.SellerPpsInfo,
+					},
+					After: map[string]interface{}{
+						"seller_pps_info": updates.SellerPpsInfo,
+					},
 				},
 			},
 		})
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
-	order.PpsInfo = updates.PpsInfo
+	order.SellerPpsInfo = updates.SellerPpsInfo
 
 	return order, err
 }
@@ -633,36 +610,29 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderUpdateProduction(pa
 		SellerProductionInfo: params.ProductionInfo,
 	}
 
-	if order.SellerTrackingStatus == enums.SellerBulkPoTrackingStatusPps {
-		updates.SellerTrackingStatus = enums.SellerBulkPoTrackingStatusProduction
-	}
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdateProduction,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			UserID:          params.GetUserID(),
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"production_info": order.ProductionInfo,
-				},
-				After: map[string]interface{}{
-					"production_info": params.ProductionInfo,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdateProduction,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: params.GetUserID(),
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"production_info": order.SellerProductionInfo,
+					},
+					After: map[string]interface{}{
+						"production_info": params.ProductionInfo,
+					},
 				},
 			},
 		})
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
-
 	return order, err
 }
 
@@ -686,14 +656,14 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderCreateQcReport(para
 	}
 
 	var updates = models.BulkPurchaseOrder{
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusQc,
-		SellerPoQcReports:    &params.PoQcReports,
+		SellerPoQcReports: &params.PoQcReports,
 	}
 	var trackings = lo.Map(params.PoQcReports, func(item *models.PoReportMeta, index int) *models.BulkPurchaseOrderTracking {
 		var tracking = models.BulkPurchaseOrderTracking{
 			PurchaseOrderID: order.ID,
 			ActionType:      enums.BulkPoTrackingActionCreateQcReport,
 			UserID:          params.GetUserID(),
+			UserGroup:       enums.PoTrackingUserGroupSeller,
 			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
 			ReportStatus:    item.Status,
 			Attachments:     &item.Attachments,
@@ -713,13 +683,18 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderCreateQcReport(para
 		if err != nil {
 			return err
 		}
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionCreateQcReport,
+			Updates:       &updates,
+			SkipTracking:  true,
+		})
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
 	order.SellerPoQcReports = updates.SellerPoQcReports
 
 	return order, err
@@ -1070,6 +1045,12 @@ func (r *SellerBulkPurchaseOrderRepo) AdminApproveSellerBulkPurchaseOrderQuotati
 		return errs.ErrInquiryInvalidToSendQuotationToBuyer
 	}
 
+	var order models.BulkPurchaseOrder
+	err = r.db.Select("ID", "SellerID", "SellerTrackingStatus").First(&order, "id = ?", sellerQuotation.BulkPurchaseOrderID).Error
+	if err != nil {
+		return err
+	}
+
 	err = r.db.Transaction(func(tx *gorm.DB) error {
 		var quotationUpdates = models.BulkPurchaseOrderSellerQuotation{
 			Status: enums.BulkPurchaseOrderSellerStatusApproved,
@@ -1089,32 +1070,25 @@ func (r *SellerBulkPurchaseOrderRepo) AdminApproveSellerBulkPurchaseOrderQuotati
 			return err
 		}
 
-		var bulkUpdates = models.BulkPurchaseOrder{
-			SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPO,
-			SellerID:             sellerQuotation.UserID,
-		}
-
-		var log = models.BulkPurchaseOrderTracking{
-			PurchaseOrderID: sellerQuotation.BulkPurchaseOrderID,
-			ActionType:      enums.BulkPoTrackingActionAdminApproveSellerQuotation,
-			UserID:          sellerQuotation.UserID,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_quotation_status": sellerQuotation.Status,
-				},
-				After: map[string]interface{}{
-					"seller_quotation_status": quotationUpdates.Status,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         &order,
+			Action:        enums.BulkPoTrackingActionAdminApproveSellerQuotation,
+			Updates: &models.BulkPurchaseOrder{
+				SellerID: sellerQuotation.UserID,
+			},
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: sellerQuotation.UserID,
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"seller_quotation_status": sellerQuotation.Status,
+					},
+					After: map[string]interface{}{
+						"seller_quotation_status": quotationUpdates.Status,
+					},
 				},
 			},
-		}
-		err = tx.Create(&log).Error
-		if err != nil {
-			return err
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", sellerQuotation.BulkPurchaseOrderID).Updates(&bulkUpdates).Error
+		})
 	})
 
 	return err
@@ -1243,33 +1217,21 @@ func (r *SellerBulkPurchaseOrderRepo) BulkPurchaseOrderApprovePO(params BulkPurc
 		})
 
 		var err = r.db.Transaction(func(tx *gorm.DB) error {
-			var updates = models.BulkPurchaseOrder{
-				SellerPoAttachments:  &items,
-				SellerTrackingStatus: enums.SellerBulkPoTrackingStatusWaitingFirstPayment,
-			}
-			err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
-			if err != nil {
-				return err
-			}
-
-			var log = models.BulkPurchaseOrderTracking{
-				PurchaseOrderID: bulkPO.ID,
-				ActionType:      enums.BulkPoTrackingActionSellerApprovePO,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				UserID:          bulkPO.SellerID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_po_attachments":  bulkPO.SellerPoAttachments,
-						"seller_tracking_status": bulkPO.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
+			return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         bulkPO,
+				Action:        enums.BulkPoTrackingActionSellerApprovePO,
+				Updates: &models.BulkPurchaseOrder{
+					SellerPoAttachments: &items,
+				},
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Metadata: &models.PoTrackingMetadata{
+						Before: map[string]interface{}{
+							"seller_po_attachments": bulkPO.SellerPoAttachments,
+						},
 					},
 				},
-			}
-
-			return tx.Create(&log).Error
+			})
 		})
 
 		if err != nil {
@@ -1308,33 +1270,21 @@ func (r *SellerBulkPurchaseOrderRepo) BulkPurchaseOrderRejectPO(params BulkPurch
 		})
 
 		err = r.db.Transaction(func(tx *gorm.DB) error {
-			var updates = models.BulkPurchaseOrder{
-				SellerPoAttachments:  &items,
-				SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPORejected,
-			}
-			err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
-			if err != nil {
-				return err
-			}
-
-			var log = models.BulkPurchaseOrderTracking{
-				PurchaseOrderID: bulkPO.ID,
-				ActionType:      enums.BulkPoTrackingActionSellerRejectPO,
-				UserID:          bulkPO.SellerID,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_po_attachments":  bulkPO.SellerPoAttachments,
-						"seller_tracking_status": bulkPO.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
+			return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+				JwtClaimsInfo: params.JwtClaimsInfo,
+				Order:         bulkPO,
+				Action:        enums.BulkPoTrackingActionSellerRejectPO,
+				Updates: &models.BulkPurchaseOrder{
+					SellerPoAttachments: &items,
+				},
+				Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+					Metadata: &models.PoTrackingMetadata{
+						Before: map[string]interface{}{
+							"seller_po_attachments": bulkPO.SellerPoAttachments,
+						},
 					},
 				},
-			}
-
-			return tx.Create(&log).Error
+			})
 		})
 		if err != nil {
 			return nil, err
@@ -1359,43 +1309,20 @@ func (r *SellerBulkPurchaseOrderRepo) BulkPurchaseOrderStartWithoutFirstPayment(
 		return nil, err
 	}
 
-	if bulkPO.SellerTrackingStatus != enums.SellerBulkPoTrackingStatusFirstPaymentSkipped {
-		return nil, errs.ErrBulkPoNotAbleToStart
-	}
-
-	var updates = models.BulkPurchaseOrder{
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
-	}
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
-		if err != nil {
-			return err
-		}
-
-		var log = models.BulkPurchaseOrderTracking{
-			PurchaseOrderID: bulkPO.ID,
-			ActionType:      enums.BulkPoTrackingActionAdminRejectSellerQuotation,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			UserID:          bulkPO.SellerID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_tracking_status": bulkPO.SellerTrackingStatus,
-				},
-				After: map[string]interface{}{
-					"seller_tracking_status": updates.SellerTrackingStatus,
-				},
-			},
-		}
-
-		return tx.Create(&log).Error
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         bulkPO,
+			Action:        enums.BulkPoTrackingActionStartWithoutFirstPayment,
+		})
 	})
+	if eris.Is(err, errs.ErrBulkPoInvalidTransition) {
+		return nil, errs.ErrBulkPoNotAbleToStart
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	bulkPO.SellerTrackingStatus = updates.SellerTrackingStatus
 	return bulkPO, err
 }
 
@@ -1414,43 +1341,20 @@ func (r *SellerBulkPurchaseOrderRepo) BulkPurchaseOrderConfirmReceiveFirstPaymen
 		return nil, err
 	}
 
-	if bulkPO.SellerTrackingStatus != enums.SellerBulkPoTrackingStatusFirstPaymentConfirm {
-		return nil, errs.ErrBulkPoNotAbleToConfirm
-	}
-
-	var updates = models.BulkPurchaseOrder{
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
-	}
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
-		if err != nil {
-			return err
-		}
-
-		var log = models.BulkPurchaseOrderTracking{
-			PurchaseOrderID: bulkPO.ID,
-			ActionType:      enums.BulkPoTrackingActionFirstPaymentConfirmed,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			UserID:          bulkPO.SellerID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_tracking_status": bulkPO.SellerTrackingStatus,
-				},
-				After: map[string]interface{}{
-					"seller_tracking_status": updates.SellerTrackingStatus,
-				},
-			},
-		}
-
-		return tx.Create(&log).Error
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         bulkPO,
+			Action:        enums.BulkPoTrackingActionFirstPaymentConfirmed,
+		})
 	})
+	if eris.Is(err, errs.ErrBulkPoInvalidTransition) {
+		return nil, errs.ErrBulkPoNotAbleToConfirm
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	bulkPO.SellerTrackingStatus = updates.SellerTrackingStatus
 	return bulkPO, err
 }
 
@@ -1469,43 +1373,20 @@ func (r *SellerBulkPurchaseOrderRepo) BulkPurchaseOrderConfirmReceiveFinalPaymen
 		return nil, err
 	}
 
-	if bulkPO.SellerTrackingStatus != enums.SellerBulkPoTrackingStatusFinalPaymentConfirm {
-		return nil, errs.ErrBulkPoNotAbleToConfirm
-	}
-
-	var updates = models.BulkPurchaseOrder{
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusFinalPaymentConfirmed,
-	}
-
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", params.BulkPurchaseOrderID).Updates(&updates).Error
-		if err != nil {
-			return err
-		}
-
-		var log = models.BulkPurchaseOrderTracking{
-			PurchaseOrderID: bulkPO.ID,
-			ActionType:      enums.BulkPoTrackingActionFinalPaymentConfirmed,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			UserID:          bulkPO.SellerID,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_tracking_status": bulkPO.SellerTrackingStatus,
-				},
-				After: map[string]interface{}{
-					"seller_tracking_status": updates.SellerTrackingStatus,
-				},
-			},
-		}
-
-		return tx.Create(&log).Error
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         bulkPO,
+			Action:        enums.BulkPoTrackingActionFinalPaymentConfirmed,
+		})
 	})
+	if eris.Is(err, errs.ErrBulkPoInvalidTransition) {
+		return nil, errs.ErrBulkPoNotAbleToConfirm
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	bulkPO.SellerTrackingStatus = updates.SellerTrackingStatus
 	return bulkPO, err
 }
 
@@ -1523,45 +1404,23 @@ func (r *SellerBulkPurchaseOrderRepo) AdminSellerBulkPoConfirmDelivered(params A
 	if err != nil {
 		return nil, err
 	}
-	validStatus := []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusDelivering}
-	if ok := slices.Contains(validStatus, order.SellerTrackingStatus); !ok {
-		return nil, errs.ErrPoInvalidToConfirmDelivered
-	}
-
-	var updates = models.BulkPurchaseOrder{
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusDeliveryConfirmed,
-	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.SellerTrackingStatus != updates.SellerTrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionConfirmDelivered,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				UserID:          params.GetUserID(),
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_tracking_status": order.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
-					},
-				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionConfirmDelivered,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: params.GetUserID(),
+			},
+		})
 	})
-
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
+	if eris.Is(err, errs.ErrBulkPoInvalidTransition) {
+		return nil, errs.ErrPoInvalidToConfirmDelivered
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	return order, err
 }
@@ -1585,43 +1444,29 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderMarkDelivering(para
 
 	var updates = models.BulkPurchaseOrder{
 		SellerDeliveryStartedAt: values.Int64(time.Now().Unix()),
-		SellerTrackingStatus:    enums.SellerBulkPoTrackingStatusDelivering,
 		SellerLogisticInfo:      params.LogisticInfo,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.TrackingStatus != updates.TrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionSellerDelivering,
-				UserID:          order.UserID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				UserGroup:       enums.PoTrackingUserGroupSeller,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSellerDelivering,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: order.UserID,
 				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_tracking_status": order.SellerTrackingStatus,
-					},
 					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
-						"logistic_info":          params.LogisticInfo,
+						"logistic_info": params.LogisticInfo,
 					},
 				},
-			})
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-		}
-
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
+			},
+		})
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
-	order.DeliveryStartedAt = updates.DeliveryStartedAt
-	order.TrackingStatus = updates.TrackingStatus
+	order.SellerDeliveryStartedAt = updates.SellerDeliveryStartedAt
 	order.SellerLogisticInfo = updates.SellerLogisticInfo
 
 	return order, err
@@ -1695,45 +1540,26 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderUpdateTrackingStatu
 		return nil, err
 	}
 
-	var updates models.BulkPurchaseOrder
-	err = copier.Copy(&updates, &params)
+	// The action decides the next status, the status of the params must agree with it
+	transition, err := FindSellerBulkPurchaseOrderTransition(order.SellerTrackingStatus, params.TrackingAction)
 	if err != nil {
 		return nil, err
 	}
+	if params.SellerTrackingStatus != "" && transition.ToStatus(order.SellerTrackingStatus) != params.SellerTrackingStatus {
+		return nil, errs.ErrBulkPoInvalidTransition
+	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.SellerTrackingStatus != updates.SellerTrackingStatus {
-			err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      params.TrackingAction,
-				UserID:          order.SellerID,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_tracking_status": order.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
-					},
-				},
-			})
-
-			if err != nil {
-				return eris.Wrap(err, err.Error())
-			}
-
-		}
-		return r.db.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        params.TrackingAction,
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
 	return order, err
 }
 
@@ -1756,45 +1582,21 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderMarkProduction(para
 
 	var updates = models.BulkPurchaseOrder{
 		SellerProductionInfo: params.ProductionInfo,
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusProduction,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.SellerTrackingStatus != updates.SellerTrackingStatus {
-			var record = models.BulkPurchaseOrderTracking{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionSellerMarkProduction,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				UserID:          order.SellerID,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_tracking_status": order.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
-					},
-				},
-			}
-			err = tx.Create(&record).Error
-			if err != nil {
-				return err
-			}
-		}
-
-		err = tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
-		return err
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSellerMarkProduction,
+			Updates:       &updates,
+		})
 	})
 
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
-
 	return order, err
 }
 
@@ -1819,45 +1621,21 @@ func (r *SellerBulkPurchaseOrderRepo) SellerBulkPurchaseOrderMarkInspection(para
 	var updates = models.BulkPurchaseOrder{
 		SellerInspectionProcedureAttachments: &params.InspectionProcedureAttachments,
 		SellerInspectionProcedureNote:        params.InspectionProcedureNote,
-		SellerTrackingStatus:                 enums.SellerBulkPoTrackingStatusInspection,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		if order.SellerTrackingStatus != updates.SellerTrackingStatus {
-			var record = models.BulkPurchaseOrderTracking{
-				PurchaseOrderID: params.BulkPurchaseOrderID,
-				ActionType:      enums.BulkPoTrackingActionSellerMarkInspection,
-				UserID:          order.SellerID,
-				UserGroup:       enums.PoTrackingUserGroupSeller,
-				CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-				Metadata: &models.PoTrackingMetadata{
-					Before: map[string]interface{}{
-						"seller_tracking_status": order.SellerTrackingStatus,
-					},
-					After: map[string]interface{}{
-						"seller_tracking_status": updates.SellerTrackingStatus,
-					},
-				},
-			}
-			err = tx.Create(&record).Error
-			if err != nil {
-				return err
-			}
-		}
-
-		err = tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
-
-		return err
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionSellerMarkInspection,
+			Updates:       &updates,
+		})
 	})
 
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.SellerTrackingStatus = updates.SellerTrackingStatus
-
 	return order, err
 }
 
@@ -1895,35 +1673,32 @@ func (r *SellerBulkPurchaseOrderRepo) AdminSellerBulkPurchaseOrderUpdatePps(para
 	}
 
 	var updates = models.BulkPurchaseOrder{
-		SellerPpsInfo:        &ppsInfoArr,
-		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPps,
+		SellerPpsInfo: &ppsInfoArr,
 	}
 
 	err = r.db.Transaction(func(tx *gorm.DB) error {
-		err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, models.BulkPurchaseOrderTrackingCreateForm{
-			PurchaseOrderID: order.ID,
-			ActionType:      enums.BulkPoTrackingActionUpdatePps,
-			UserID:          order.UserID,
-			UserGroup:       enums.PoTrackingUserGroupSeller,
-			CreatedByUserID: params.JwtClaimsInfo.GetUserID(),
-			Metadata: &models.PoTrackingMetadata{
-				Before: map[string]interface{}{
-					"seller_pps_info": order.PpsInfo,
-				},
-				After: map[string]interface{}{
-					"seller_pps_info": updates.PpsInfo,
+		return r.TransitionTx(tx, SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: params.JwtClaimsInfo,
+			Order:         order,
+			Action:        enums.BulkPoTrackingActionUpdatePps,
+			Updates:       &updates,
+			Tracking: models.BulkPurchaseOrderTrackingCreateForm{
+				UserID: order.UserID,
+				Metadata: &models.PoTrackingMetadata{
+					Before: map[string]interface{}{
+						"seller_pps_info": order.SellerPpsInfo,
+					},
+					After: map[string]interface{}{
+						"seller_pps_info": updates.SellerPpsInfo,
+					},
 				},
 			},
 		})
-		return tx.Model(&models.BulkPurchaseOrder{}).
-			Where("id = ?", params.BulkPurchaseOrderID).
-			Updates(&updates).Error
 	})
 	if err != nil {
 		return nil, eris.Wrap(err, err.Error())
 	}
 
-	order.TrackingStatus = updates.TrackingStatus
-	order.PpsInfo = updates.PpsInfo
+	order.SellerPpsInfo = updates.SellerPpsInfo
 	return order, err
 }
@@ -0,0 +1,395 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+	"github.com/thaitanloi365/go-utils/values"
+	"gorm.io/gorm"
+)
+
+// SellerBulkPurchaseOrderTransition one row of the seller side tracking state machine of a bulk order.
+// Empty From status is an order which isn't assigned to a seller yet, empty To keeps the current status.
+type SellerBulkPurchaseOrderTransition struct {
+	Action enums.BulkPoTrackingAction         `json:"action"`
+	From   []enums.SellerBulkPoTrackingStatus `json:"from"`
+	To     enums.SellerBulkPoTrackingStatus   `json:"to,omitempty"`
+	Actors []enums.BulkPoTransitionActor      `json:"actors"`
+
+	Guard  func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) error `json:"-"`
+	Effect func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder)       `json:"-"`
+}
+
+var (
+	sellerBulkPoActorsAdmin          = []enums.BulkPoTransitionActor{enums.BulkPoTransitionActorAdmin, enums.BulkPoTransitionActorSystem}
+	sellerBulkPoActorsAdminAndSeller = []enums.BulkPoTransitionActor{enums.BulkPoTransitionActorAdmin, enums.BulkPoTransitionActorSeller, enums.BulkPoTransitionActorSystem}
+
+	sellerBulkPoProductionStatuses = []enums.SellerBulkPoTrackingStatus{
+		enums.SellerBulkPoTrackingStatusProduction,
+		enums.SellerBulkPoTrackingStatusInspection,
+		enums.SellerBulkPoTrackingStatusQc,
+	}
+)
+
+// SellerBulkPurchaseOrderTransitions is the only source of truth for seller tracking status changes
+var SellerBulkPurchaseOrderTransitions = []*SellerBulkPurchaseOrderTransition{
+	{
+		Action: enums.BulkPoTrackingActionAdminApproveSellerQuotation,
+		From: []enums.SellerBulkPoTrackingStatus{
+			"",
+			enums.SellerBulkPoTrackingStatusWaitingForSubmitOrder,
+			enums.SellerBulkPoTrackingStatusWaitingForQuotation,
+			enums.SellerBulkPoTrackingStatusPO,
+			enums.SellerBulkPoTrackingStatusPORejected,
+		},
+		To:     enums.SellerBulkPoTrackingStatusPO,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionAdminUploadSellerPo,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusPO, enums.SellerBulkPoTrackingStatusPORejected},
+		To:     enums.SellerBulkPoTrackingStatusPO,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerApprovePO,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusPO},
+		To:     enums.SellerBulkPoTrackingStatusWaitingFirstPayment,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerRejectPO,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusPO},
+		To:     enums.SellerBulkPoTrackingStatusPORejected,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionAdminFirstPayout,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusPO,
+			enums.SellerBulkPoTrackingStatusWaitingFirstPayment,
+			enums.SellerBulkPoTrackingStatusFirstPaymentSkipped,
+			enums.SellerBulkPoTrackingStatusFirstPaymentConfirm,
+		},
+		To:     enums.SellerBulkPoTrackingStatusFirstPaymentConfirm,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionAdminSkipFirstPayout,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusPO,
+			enums.SellerBulkPoTrackingStatusWaitingFirstPayment,
+			enums.SellerBulkPoTrackingStatusFirstPaymentSkipped,
+			enums.SellerBulkPoTrackingStatusFirstPaymentConfirm,
+		},
+		To:     enums.SellerBulkPoTrackingStatusFirstPaymentSkipped,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionStartWithoutFirstPayment,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFirstPaymentSkipped},
+		To:     enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFirstPaymentConfirmed,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFirstPaymentConfirm},
+		To:     enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionMarkRawMaterial,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed, enums.SellerBulkPoTrackingStatusRawMaterial},
+		To:     enums.SellerBulkPoTrackingStatusRawMaterial,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerMarkRawMaterial,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed, enums.SellerBulkPoTrackingStatusRawMaterial},
+		To:     enums.SellerBulkPoTrackingStatusRawMaterial,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateMaterial,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed, enums.SellerBulkPoTrackingStatusRawMaterial},
+		To:     enums.SellerBulkPoTrackingStatusRawMaterial,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateMaterial,
+		From:   append([]enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusPps}, sellerBulkPoProductionStatuses...),
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerUpdatePps,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
+			enums.SellerBulkPoTrackingStatusRawMaterial,
+			enums.SellerBulkPoTrackingStatusPps,
+		},
+		To:     enums.SellerBulkPoTrackingStatusPps,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerUpdatePps,
+		From:   sellerBulkPoProductionStatuses,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdatePps,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusFirstPaymentConfirmed,
+			enums.SellerBulkPoTrackingStatusRawMaterial,
+			enums.SellerBulkPoTrackingStatusPps,
+		},
+		To:     enums.SellerBulkPoTrackingStatusPps,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdatePps,
+		From:   sellerBulkPoProductionStatuses,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateProduction,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusPps},
+		To:     enums.SellerBulkPoTrackingStatusProduction,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionUpdateProduction,
+		From:   append([]enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusRawMaterial}, sellerBulkPoProductionStatuses...),
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerMarkProduction,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusRawMaterial,
+			enums.SellerBulkPoTrackingStatusPps,
+			enums.SellerBulkPoTrackingStatusProduction,
+		},
+		To:     enums.SellerBulkPoTrackingStatusProduction,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerMarkInspection,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusProduction, enums.SellerBulkPoTrackingStatusInspection},
+		To:     enums.SellerBulkPoTrackingStatusInspection,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionCreateQcReport,
+		From:   sellerBulkPoProductionStatuses,
+		To:     enums.SellerBulkPoTrackingStatusQc,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+	{
+		Action: enums.BulkPoTrackingActionSellerDelivering,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusInspection,
+			enums.SellerBulkPoTrackingStatusQc,
+			enums.SellerBulkPoTrackingStatusFinalPaymentConfirm,
+			enums.SellerBulkPoTrackingStatusFinalPaymentConfirmed,
+			enums.SellerBulkPoTrackingStatusDelivering,
+		},
+		To:     enums.SellerBulkPoTrackingStatusDelivering,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+		Effect: func(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder) {
+			if updates.SellerDeliveryStartedAt == nil {
+				updates.SellerDeliveryStartedAt = values.Int64(time.Now().Unix())
+			}
+		},
+	},
+	{
+		Action: enums.BulkPoTrackingActionConfirmDelivered,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusDelivering},
+		To:     enums.SellerBulkPoTrackingStatusDeliveryConfirmed,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionAdminFinalPayout,
+		From: []enums.SellerBulkPoTrackingStatus{
+			enums.SellerBulkPoTrackingStatusQc,
+			enums.SellerBulkPoTrackingStatusDelivering,
+			enums.SellerBulkPoTrackingStatusDeliveryConfirmed,
+			enums.SellerBulkPoTrackingStatusDelivered,
+			enums.SellerBulkPoTrackingStatusFinalPaymentConfirm,
+		},
+		To:     enums.SellerBulkPoTrackingStatusFinalPaymentConfirm,
+		Actors: sellerBulkPoActorsAdmin,
+	},
+	{
+		Action: enums.BulkPoTrackingActionFinalPaymentConfirmed,
+		From:   []enums.SellerBulkPoTrackingStatus{enums.SellerBulkPoTrackingStatusFinalPaymentConfirm},
+		To:     enums.SellerBulkPoTrackingStatusFinalPaymentConfirmed,
+		Actors: sellerBulkPoActorsAdminAndSeller,
+	},
+}
+
+// FindSellerBulkPurchaseOrderTransition returns the seller transition of action which is allowed from status
+func FindSellerBulkPurchaseOrderTransition(status enums.SellerBulkPoTrackingStatus, action enums.BulkPoTrackingAction) (*SellerBulkPurchaseOrderTransition, error) {
+	for _, transition := range SellerBulkPurchaseOrderTransitions {
+		if transition.Action == action && lo.Contains(transition.From, status) {
+			return transition, nil
+		}
+	}
+
+	return nil, errs.ErrBulkPoInvalidTransition
+}
+
+// ToStatus returns the seller status the order will have after the transition
+func (t *SellerBulkPurchaseOrderTransition) ToStatus(current enums.SellerBulkPoTrackingStatus) enums.SellerBulkPoTrackingStatus {
+	if t.To == "" {
+		return current
+	}
+	return t.To
+}
+
+func (t *SellerBulkPurchaseOrderTransition) IsAllowedFor(role enums.Role) bool {
+	return lo.Contains(t.Actors, enums.BulkPoTransitionActorFromRole(role))
+}
+
+// Validate checks actor and guard, it doesn't touch the database
+func (t *SellerBulkPurchaseOrderTransition) Validate(order *models.BulkPurchaseOrder, updates *models.BulkPurchaseOrder, role enums.Role) error {
+	if !t.IsAllowedFor(role) {
+		return errs.ErrBulkPoTransitionForbidden
+	}
+
+	if t.Guard != nil {
+		return t.Guard(order, updates)
+	}
+
+	return nil
+}
+
+type SellerBulkPurchaseOrderTransitionParams struct {
+	models.JwtClaimsInfo
+
+	Order   *models.BulkPurchaseOrder
+	Action  enums.BulkPoTrackingAction
+	Updates *models.BulkPurchaseOrder
+
+	// Tracking overrides description, attachments and metadata of the tracking row
+	Tracking models.BulkPurchaseOrderTrackingCreateForm
+
+	// SkipTracking when the caller writes its own tracking rows
+	SkipTracking bool
+}
+
+// TransitionTx is the seller side counterpart of BulkPurchaseOrderRepo.TransitionTx, the update is
+// conditional on the seller status the order was read with.
+// On success order.SellerTrackingStatus holds the new status.
+func (r *SellerBulkPurchaseOrderRepo) TransitionTx(tx *gorm.DB, params SellerBulkPurchaseOrderTransitionParams) error {
+	var order = params.Order
+	var updates = params.Updates
+	if updates == nil {
+		updates = &models.BulkPurchaseOrder{}
+	}
+
+	transition, err := FindSellerBulkPurchaseOrderTransition(order.SellerTrackingStatus, params.Action)
+	if err != nil {
+		return err
+	}
+
+	if err = transition.Validate(order, updates, params.GetRole()); err != nil {
+		return err
+	}
+
+	var fromStatus = order.SellerTrackingStatus
+	updates.SellerTrackingStatus = transition.ToStatus(fromStatus)
+	if transition.Effect != nil {
+		transition.Effect(order, updates)
+	}
+
+	if !params.SkipTracking {
+		var tracking = params.Tracking
+		tracking.PurchaseOrderID = order.ID
+		tracking.ActionType = params.Action
+		tracking.UserGroup = enums.PoTrackingUserGroupSeller
+		if tracking.UserID == "" {
+			tracking.UserID = order.SellerID
+		}
+		if tracking.CreatedByUserID == "" {
+			tracking.CreatedByUserID = params.GetUserID()
+		}
+		if fromStatus != updates.SellerTrackingStatus {
+			if tracking.Metadata == nil {
+				tracking.Metadata = &models.PoTrackingMetadata{}
+			}
+			tracking.Metadata.Before = withSellerTrackingStatus(tracking.Metadata.Before, fromStatus)
+			tracking.Metadata.After = withSellerTrackingStatus(tracking.Metadata.After, updates.SellerTrackingStatus)
+		}
+
+		if err = NewBulkPurchaseOrderTrackingRepo(r.db).CreateBulkPurchaseOrderTrackingTx(tx, tracking); err != nil {
+			return err
+		}
+	}
+
+	// Orders which were never assigned to a seller have a null seller status
+	var result = tx.Model(&models.BulkPurchaseOrder{}).
+		Where("id = ? AND COALESCE(seller_tracking_status, '') = ?", order.ID, fromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return eris.Wrap(result.Error, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errs.ErrBulkPoTrackingStatusChanged
+	}
+
+	order.SellerTrackingStatus = updates.SellerTrackingStatus
+	return nil
+}
+
+// withSellerTrackingStatus adds the seller status to the metadata map the caller filled
+func withSellerTrackingStatus(metadata interface{}, status enums.SellerBulkPoTrackingStatus) interface{} {
+	var result = map[string]interface{}{}
+	if fields, ok := metadata.(map[string]interface{}); ok {
+		for key, value := range fields {
+			result[key] = value
+		}
+	}
+	result["seller_tracking_status"] = status
+	return result
+}
+
+// SellerBulkPurchaseOrderStateDiagram renders the seller transition table as a mermaid state diagram
+func SellerBulkPurchaseOrderStateDiagram() string {
+	var builder strings.Builder
+	builder.WriteString("stateDiagram-v2\n")
+
+	var lines = map[string][]string{}
+	var keys []string
+	for _, transition := range SellerBulkPurchaseOrderTransitions {
+		for _, from := range transition.From {
+			var fromState = string(from)
+			if from == "" {
+				fromState = "[*]"
+			}
+			var key = fmt.Sprintf("%s --> %s", fromState, transition.ToStatus(from))
+			if _, ok := lines[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			var label = string(transition.Action)
+			if transition.Guard != nil {
+				label += " [guarded]"
+			}
+			lines[key] = append(lines[key], label)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		builder.WriteString(fmt.Sprintf("    %s : %s\n", key, strings.Join(lo.Uniq(lines[key]), ", ")))
+	}
+	builder.WriteString(fmt.Sprintf("    %s --> [*]\n", enums.SellerBulkPoTrackingStatusFinalPaymentConfirmed))
+
+	return builder.String()
+}
@@ -1,8 +1,11 @@
 package repo
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
+	"github.com/engineeringinflow/inflow-backend/pkg/caching"
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
@@ -79,18 +82,21 @@ func (r *SettingSEORepo) GetSettingSEOByRouteName(params GetSettingSEOByRouteNam
 		}, nil
 	}
 
-	var result models.SettingSEOLanguageGroup
+	var key = fmt.Sprintf("setting_seo_%s_%s", params.GetRole(), params.RouteName)
+	return caching.GetOrLoad(context.Background(), r.db.Cache, key, func() (*models.SettingSEOLanguageGroup, error) {
+		var result models.SettingSEOLanguageGroup
 
-	var err = query.New(r.db, queryfunc.NewSettingSEOLanguageGroupBuilder(queryfunc.SettingSEOBuilderOptions{
-		QueryBuilderOptions: queryfunc.QueryBuilderOptions{
-			Role: params.GetRole(),
-		},
-	})).WhereFunc(func(builder *query.Builder) {
-		builder.Where("ss.route = ?", params.RouteName)
-	}).
-		FirstFunc(&result)
+		var err = query.New(r.db, queryfunc.NewSettingSEOLanguageGroupBuilder(queryfunc.SettingSEOBuilderOptions{
+			QueryBuilderOptions: queryfunc.QueryBuilderOptions{
+				Role: params.GetRole(),
+			},
+		})).WhereFunc(func(builder *query.Builder) {
+			builder.Where("ss.route = ?", params.RouteName)
+		}).
+			FirstFunc(&result)
 
-	return &result, err
+		return &result, err
+	}, caching.WithTags(models.CacheTagSettingSEO))
 }
 
 func (r *SettingSEORepo) CreateSettingSEO(form models.CreateSettingSEOForm) (settingSEO models.SettingSEO, err error) {
@@ -2,6 +2,10 @@ package repo
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
@@ -11,6 +15,7 @@ import (
 	"github.com/jinzhu/copier"
 	"github.com/lib/pq"
 	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -52,6 +57,15 @@ func (r *TNARepo) Create(params CreateTNAsParams) (result *models.TNA, err error
 		return
 	}
 
+	items, err := r.getPlanItems(tna.ReferenceID)
+	if err != nil {
+		return
+	}
+	if _, err = append(items, &tna).SortTopologically(); err != nil {
+		return
+	}
+	var previous = r.getPlanSilently(tna.ReferenceID)
+
 	if err = r.db.Model(&models.User{}).Where("id IN ?", []string(params.AssigneeIDs)).Find(&tna.Assignees).Error; err != nil {
 		return
 	}
@@ -62,10 +76,20 @@ func (r *TNARepo) Create(params CreateTNAsParams) (result *models.TNA, err error
 		return
 	}
 
+	plan, err := r.Reschedule(tna.ReferenceID, previous)
+	if err != nil {
+		return
+	}
+
 	result, err = r.Get(GetTNAParams{
 		JwtClaimsInfo: params.JwtClaimsInfo,
 		ID:            tna.ID,
 	})
+	if err != nil {
+		return
+	}
+
+	result.Plan = plan
 	return
 }
 
@@ -91,6 +115,35 @@ func (r *TNARepo) Update(params UpdateTNAsParams) (result *models.TNA, err error
 		return nil, eris.Wrap(err, "")
 	}
 
+	var existing models.TNA
+	if err = r.db.First(&existing, "id = ?", params.ID).Error; err != nil {
+		return
+	}
+
+	// Validate the dependency graph with the updated task in place
+	var candidate = existing
+	if tna.ReferenceID != "" {
+		candidate.ReferenceID = tna.ReferenceID
+	}
+	if tna.DateFrom > 0 {
+		candidate.DateFrom = tna.DateFrom
+	}
+	if tna.DateTo > 0 {
+		candidate.DateTo = tna.DateTo
+	}
+	if tna.Dependencies != nil {
+		candidate.Dependencies = tna.Dependencies
+	}
+	items, err := r.getPlanItems(candidate.ReferenceID)
+	if err != nil {
+		return
+	}
+	items = lo.Reject(items, func(item *models.TNA, _ int) bool { return item.ID == candidate.ID })
+	if _, err = append(items, &candidate).SortTopologically(); err != nil {
+		return
+	}
+	var previous = r.getPlanSilently(candidate.ReferenceID)
+
 	if err = r.db.Model(&models.User{}).Where("id IN ?", []string(params.AssigneeIDs)).
 		Find(&tna.Assignees).Error; err != nil {
 		return
@@ -103,10 +156,20 @@ func (r *TNARepo) Update(params UpdateTNAsParams) (result *models.TNA, err error
 		return
 	}
 
+	plan, err := r.Reschedule(candidate.ReferenceID, previous)
+	if err != nil {
+		return
+	}
+
 	result, err = r.Get(GetTNAParams{
 		JwtClaimsInfo: params.JwtClaimsInfo,
 		ID:            tna.ID,
 	})
+	if err != nil {
+		return
+	}
+
+	result.Plan = plan
 	return
 }
 
@@ -187,3 +250,115 @@ func (r *TNARepo) Get(params GetTNAParams) (result *models.TNA, err error) {
 	result = &tna
 	return
 }
+
+type GetTNAPlanParams struct {
+	models.JwtClaimsInfo
+	ReferenceID string `json:"reference_id" param:"reference_id" query:"reference_id" form:"reference_id" validate:"required"`
+}
+
+// GetPlan the TNA of an inquiry, PO or bulk PO with critical path, float and projected ex-factory date
+func (r *TNARepo) GetPlan(params GetTNAPlanParams) (*models.TNAPlan, error) {
+	items, err := r.getPlanItems(params.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedDeliveryAt, picIDs := r.getPlanOrder(params.ReferenceID)
+	plan, err := models.NewTNAPlan(params.ReferenceID, items, requestedDeliveryAt)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.PicIDs = picIDs
+	return plan, nil
+}
+
+// Reschedule pushes tasks whose dependencies finish later than their start and saves the shifted dates.
+// NotifySlippage is set when the projected ex-factory date is past the requested date and moved later than previous.
+func (r *TNARepo) Reschedule(referenceID string, previous *models.TNAPlan) (*models.TNAPlan, error) {
+	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("tna_plan_%s", referenceID), time.Second*20)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	plan, err := r.GetPlan(GetTNAPlanParams{ReferenceID: referenceID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(plan.ShiftedTNAIDs) > 0 {
+		var shifted = lo.Filter(plan.Items, func(item *models.TNA, _ int) bool {
+			return lo.Contains(plan.ShiftedTNAIDs, item.ID)
+		})
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			for _, item := range shifted {
+				if err := tx.Model(&models.TNA{}).Where("id = ?", item.ID).
+					UpdateColumns(map[string]interface{}{"date_from": item.DateFrom, "date_to": item.DateTo}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan.NotifySlippage = plan.IsSlipped() && (previous == nil || plan.ProjectedFinishAt > previous.ProjectedFinishAt)
+	return plan, nil
+}
+
+func (r *TNARepo) getPlanSilently(referenceID string) *models.TNAPlan {
+	plan, err := r.GetPlan(GetTNAPlanParams{ReferenceID: referenceID})
+	if err != nil {
+		r.logger.Warnf("TNA plan reference_id=%s err=%+v", referenceID, err)
+		return nil
+	}
+	return plan
+}
+
+func (r *TNARepo) getPlanItems(referenceID string) (models.TNAs, error) {
+	var items models.TNAs
+	var err = r.db.Find(&items, "reference_id = ?", referenceID).Error
+	return items, err
+}
+
+// getPlanOrder the buyer's requested delivery date and the PICs of the order the plan belongs to
+func (r *TNARepo) getPlanOrder(referenceID string) (requestedDeliveryAt *int64, picIDs []string) {
+	var inquiryID string
+	switch {
+	case strings.HasPrefix(referenceID, "IQ-"):
+		var inquiry models.Inquiry
+		if err := r.db.Select("ID", "DeliveryDate", "AssigneeIDs").First(&inquiry, "reference_id = ?", referenceID).Error; err != nil {
+			return nil, nil
+		}
+		return inquiry.DeliveryDate, inquiry.AssigneeIDs
+
+	case strings.HasPrefix(referenceID, "PO-"):
+		var purchaseOrder models.PurchaseOrder
+		if err := r.db.Select("ID", "InquiryID", "AssigneeIDs").First(&purchaseOrder, "reference_id = ?", referenceID).Error; err != nil {
+			return nil, nil
+		}
+		inquiryID, picIDs = purchaseOrder.InquiryID, purchaseOrder.AssigneeIDs
+
+	case strings.HasPrefix(referenceID, "BPO-"):
+		var bulkPurchaseOrder models.BulkPurchaseOrder
+		if err := r.db.Select("ID", "InquiryID", "AssigneeIDs").First(&bulkPurchaseOrder, "reference_id = ?", referenceID).Error; err != nil {
+			return nil, nil
+		}
+		inquiryID, picIDs = bulkPurchaseOrder.InquiryID, bulkPurchaseOrder.AssigneeIDs
+
+	default:
+		return nil, nil
+	}
+
+	if inquiryID != "" {
+		var inquiry models.Inquiry
+		if err := r.db.Select("ID", "DeliveryDate").First(&inquiry, "id = ?", inquiryID).Error; err == nil {
+			requestedDeliveryAt = inquiry.DeliveryDate
+		}
+	}
+
+	return requestedDeliveryAt, picIDs
+}
@@ -0,0 +1,248 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+)
+
+type TNATemplateRepo struct {
+	db     *db.DB
+	logger *logger.Logger
+}
+
+func NewTNATemplateRepo(db *db.DB) *TNATemplateRepo {
+	return &TNATemplateRepo{
+		db:     db,
+		logger: logger.New("repo/TNATemplate"),
+	}
+}
+
+type GetTNATemplatesParams struct {
+	models.JwtClaimsInfo
+
+	OrderType    enums.InquiryType `json:"order_type" query:"order_type"`
+	ProductClass string            `json:"product_class" query:"product_class"`
+}
+
+func (r *TNATemplateRepo) GetTNATemplates(params GetTNATemplatesParams) (models.TNATemplates, error) {
+	var result models.TNATemplates
+	var query = r.db.Model(&models.TNATemplate{})
+	if params.OrderType != "" {
+		query = query.Where("order_type = ?", params.OrderType)
+	}
+	if params.ProductClass != "" {
+		query = query.Where("product_class = ?", params.ProductClass)
+	}
+
+	var err = query.Order("order_type ASC, product_class ASC, name ASC").Find(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type CreateTNATemplateParams struct {
+	models.JwtClaimsInfo
+
+	Name         string                  `json:"name" validate:"required"`
+	OrderType    enums.InquiryType       `json:"order_type" validate:"required,oneof=bulk sample"`
+	ProductClass string                  `json:"product_class"`
+	Items        models.TNATemplateItems `json:"items" validate:"required,min=1,dive"`
+}
+
+func (r *TNATemplateRepo) CreateTNATemplate(params CreateTNATemplateParams) (*models.TNATemplate, error) {
+	var template = models.TNATemplate{
+		Name:            params.Name,
+		OrderType:       params.OrderType,
+		ProductClass:    params.ProductClass,
+		Items:           params.Items,
+		CreatedByUserID: params.GetUserID(),
+	}
+	if _, err := template.NewTNAs("", 0); err != nil {
+		return nil, err
+	}
+
+	var err = r.db.Create(&template).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+type UpdateTNATemplateParams struct {
+	models.JwtClaimsInfo
+
+	TNATemplateID string                  `json:"tna_template_id" param:"tna_template_id" validate:"required"`
+	Name          string                  `json:"name" validate:"required"`
+	OrderType     enums.InquiryType       `json:"order_type" validate:"required,oneof=bulk sample"`
+	ProductClass  string                  `json:"product_class"`
+	Items         models.TNATemplateItems `json:"items" validate:"required,min=1,dive"`
+}
+
+// UpdateTNATemplate replaces the template, plans already instantiated from it are not changed
+func (r *TNATemplateRepo) UpdateTNATemplate(params UpdateTNATemplateParams) (*models.TNATemplate, error) {
+	template, err := r.GetTNATemplate(params.TNATemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = params.Name
+	template.OrderType = params.OrderType
+	template.ProductClass = params.ProductClass
+	template.Items = params.Items
+	if _, err := template.NewTNAs("", 0); err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&models.TNATemplate{}).Where("id = ?", template.ID).
+		Select("Name", "OrderType", "ProductClass", "Items").
+		Updates(template).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+type DeleteTNATemplateParams struct {
+	models.JwtClaimsInfo
+
+	TNATemplateID string `json:"tna_template_id" param:"tna_template_id" validate:"required"`
+}
+
+func (r *TNATemplateRepo) DeleteTNATemplate(params DeleteTNATemplateParams) error {
+	return r.db.Unscoped().Delete(&models.TNATemplate{}, "id = ?", params.TNATemplateID).Error
+}
+
+func (r *TNATemplateRepo) GetTNATemplate(id string) (*models.TNATemplate, error) {
+	var template models.TNATemplate
+	var err = r.db.First(&template, "id = ?", id).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrTNATemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// FindTNATemplate the template of the product class, falling back to the one for any product class
+func (r *TNATemplateRepo) FindTNATemplate(orderType enums.InquiryType, productClass string) (*models.TNATemplate, error) {
+	var template models.TNATemplate
+	var err = r.db.
+		Where("order_type = ? AND (product_class = ? OR product_class = '' OR product_class IS NULL)", orderType, productClass).
+		Order("product_class DESC NULLS LAST, created_at DESC").
+		First(&template).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrTNATemplateNotFound
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+type InstantiateBulkPurchaseOrderTNAParams struct {
+	models.JwtClaimsInfo
+
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" validate:"required"`
+	TNATemplateID       string `json:"tna_template_id"` // Matched by order type and product class when empty
+	AnchorAt            int64  `json:"anchor_at"`       // Agreed delivery date, defaults to the order completion date or the requested delivery date
+}
+
+// InstantiateBulkPurchaseOrderTNA creates the TNA plan of a bulk order from a template, backward-scheduled from the delivery date.
+// Orders which already have a plan are rejected so merchandiser edits are never overwritten.
+func (r *TNATemplateRepo) InstantiateBulkPurchaseOrderTNA(params InstantiateBulkPurchaseOrderTNAParams) (models.TNAs, error) {
+	var order models.BulkPurchaseOrder
+	var err = r.db.Select("ID", "ReferenceID", "InquiryID", "CompletionDate").First(&order, "id = ?", params.BulkPurchaseOrderID).Error
+	if err != nil {
+		if r.db.IsRecordNotFoundError(err) {
+			return nil, errs.ErrBulkPoNotFound
+		}
+		return nil, err
+	}
+
+	cancel, err := r.db.Locker.AcquireLock(fmt.Sprintf("tna_plan_%s", order.ReferenceID), time.Second*20)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var count int64
+	err = r.db.Model(&models.TNA{}).Where("reference_id = ?", order.ReferenceID).Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errs.ErrTNAPlanAlreadyExists
+	}
+
+	var inquiry models.Inquiry
+	if order.InquiryID != "" {
+		if err := r.db.Select("ID", "ProductID", "DeliveryDate").First(&inquiry, "id = ?", order.InquiryID).Error; err != nil && !r.db.IsRecordNotFoundError(err) {
+			return nil, err
+		}
+	}
+
+	var template *models.TNATemplate
+	if params.TNATemplateID != "" {
+		template, err = r.GetTNATemplate(params.TNATemplateID)
+	} else {
+		template, err = r.FindTNATemplate(enums.InquiryTypeBulk, r.getProductClass(inquiry.ProductID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var anchorAt = params.AnchorAt
+	if anchorAt == 0 && order.CompletionDate != nil {
+		anchorAt = *order.CompletionDate
+	}
+	if anchorAt == 0 && inquiry.DeliveryDate != nil {
+		anchorAt = *inquiry.DeliveryDate
+	}
+	if anchorAt == 0 {
+		spanDays, err := template.GetSpanDays()
+		if err != nil {
+			return nil, err
+		}
+		anchorAt = time.Now().AddDate(0, 0, spanDays).Unix()
+	}
+
+	tnas, err := template.NewTNAs(order.ReferenceID, anchorAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Create(&tnas).Error
+	if err != nil {
+		return nil, eris.Wrap(err, "create tnas")
+	}
+
+	return tnas, nil
+}
+
+func (r *TNATemplateRepo) getProductClass(productID string) string {
+	if productID == "" {
+		return ""
+	}
+
+	var productClass models.ProductClass
+	var err = r.db.Where("product_id = ?", productID).Order("conf DESC").First(&productClass).Error
+	if err != nil {
+		return ""
+	}
+
+	return productClass.Class
+}
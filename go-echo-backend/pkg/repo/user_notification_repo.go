@@ -6,10 +6,12 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/db"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
 	"github.com/jinzhu/copier"
 	"github.com/rotisserie/eris"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -27,6 +29,7 @@ func NewUserNotificationRepo(db *db.DB) *UserNotificationRepo {
 
 type PaginateUserNotificationsParams struct {
 	models.PaginationParams
+	models.CursorParams
 	models.JwtClaimsInfo
 
 	UserID string `json:"user_id" query:"user_id" form:"user_id"`
@@ -50,6 +53,8 @@ func (r *UserNotificationRepo) PaginateUserNotifications(params PaginateUserNoti
 				builder.Where("un.user_id = ?", params.GetUserID())
 			}
 		}).
+		Cursor(params.Cursor, query.CursorKey{Column: "un.created_at", Field: "CreatedAt", Desc: true}, query.CursorKey{Column: "un.id", Field: "ID", Desc: true}).
+		WithCursorPaging(params.IsCursorPaging()).
 		Page(params.Page).
 		Limit(params.Limit).
 		PagingFunc()
@@ -64,9 +69,18 @@ func (r *UserNotificationRepo) CreateUserNotification(params models.UserNotifica
 		return nil, eris.Wrap(err, "")
 	}
 
-	err = r.db.Omit(clause.Associations).Create(&form).Error
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		err = tx.Omit(clause.Associations).Create(&form).Error
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+
+		return NewOutboxRepo(r.db).AddTasksTx(tx, form.UserID, models.NewTopicEvent(
+			models.NewTopic(enums.TopicKindNotifications, form.UserID), enums.TopicEventTypeNotificationCreated, form,
+		))
+	})
 	if err != nil {
-		return nil, eris.Wrap(err, "")
+		return nil, err
 	}
 
 	return &form, nil
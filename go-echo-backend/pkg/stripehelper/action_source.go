@@ -5,14 +5,15 @@ type ActionSource string
 var (
 	ActionSourceCreatePaymentLink ActionSource = "create_payment_link"
 
-	ActionSourceInquiryPayment       ActionSource = "inquiry_payment"
-	ActionSourceBulkPODepositPayment ActionSource = "bulk_po_deposit_payment"
-	ActionSourceBulkPOFirstPayment   ActionSource = "bulk_po_first_payment"
-	ActionSourceBulkPOSecondPayment  ActionSource = "bulk_po_second_payment"
-	ActionSourceBulkPOFinalPayment   ActionSource = "bulk_po_final_payment"
-	ActionSourceMultiInquiryPayment  ActionSource = "multi_inquiry_payment"
-	ActionSourceMultiPOPayment       ActionSource = "multi_po_payment"
-	ActionSourceOrderCartPayment     ActionSource = "order_cart_payment"
+	ActionSourceInquiryPayment         ActionSource = "inquiry_payment"
+	ActionSourceBulkPODepositPayment   ActionSource = "bulk_po_deposit_payment"
+	ActionSourceBulkPOFirstPayment     ActionSource = "bulk_po_first_payment"
+	ActionSourceBulkPOSecondPayment    ActionSource = "bulk_po_second_payment"
+	ActionSourceBulkPOFinalPayment     ActionSource = "bulk_po_final_payment"
+	ActionSourceBulkPOMilestonePayment ActionSource = "bulk_po_milestone_payment"
+	ActionSourceMultiInquiryPayment    ActionSource = "multi_inquiry_payment"
+	ActionSourceMultiPOPayment         ActionSource = "multi_po_payment"
+	ActionSourceOrderCartPayment       ActionSource = "order_cart_payment"
 )
 
 func (ac ActionSource) IsValid() bool {
@@ -23,6 +24,7 @@ func (ac ActionSource) IsValid() bool {
 		ActionSourceBulkPOFirstPayment,
 		ActionSourceBulkPOSecondPayment,
 		ActionSourceBulkPOFinalPayment,
+		ActionSourceBulkPOMilestonePayment,
 		ActionSourceMultiInquiryPayment,
 		ActionSourceMultiPOPayment,
 		ActionSourceOrderCartPayment:
@@ -34,4 +34,5 @@ var (
 	PaymentIntentFailed         PayoutEvent = "payment_intent.payment_failed"
 	PaymentIntentSucceeded      PayoutEvent = "payment_intent.succeeded"
 	CheckoutSessionCompleted    PayoutEvent = "checkout.session.completed"
+	ChargeRefunded              PayoutEvent = "charge.refunded"
 )
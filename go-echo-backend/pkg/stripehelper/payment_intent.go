@@ -1,6 +1,8 @@
 package stripehelper
 
 import (
+	"errors"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/rotisserie/eris"
@@ -155,6 +157,7 @@ type RefundPaymentIntentParams struct {
 	PaymentIntentID string
 	Amount          int64
 	Metadata        map[string]string
+	IdempotencyKey  string
 }
 
 func (client *StripeClient) RefundPaymentIntent(params RefundPaymentIntentParams) (*stripe.Refund, error) {
@@ -164,6 +167,9 @@ func (client *StripeClient) RefundPaymentIntent(params RefundPaymentIntentParams
 	if params.Amount > 0 {
 		refundParams.Amount = stripe.Int64(params.Amount)
 	}
+	if params.IdempotencyKey != "" {
+		refundParams.SetIdempotencyKey(params.IdempotencyKey)
+	}
 
 	for k, v := range params.Metadata {
 		refundParams.AddMetadata(k, v)
@@ -175,6 +181,31 @@ func (client *StripeClient) RefundPaymentIntent(params RefundPaymentIntentParams
 
 }
 
+// ListRefundsOfCharge refunds created on the charge
+func (client *StripeClient) ListRefundsOfCharge(chargeID string) ([]*stripe.Refund, error) {
+	var params = &stripe.RefundListParams{
+		Charge: stripe.String(chargeID),
+	}
+
+	var result []*stripe.Refund
+	i := refund.List(params)
+	for i.Next() {
+		result = append(result, i.Refund())
+	}
+
+	return result, i.Err()
+}
+
+// IsDefinitiveError Stripe declined or rejected the request, sending it again fails the same way
+func IsDefinitiveError(err error) bool {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return false
+	}
+
+	return stripeErr.Type == stripe.ErrorTypeCard || stripeErr.Type == stripe.ErrorTypeInvalidRequest
+}
+
 type CancelPaymentIntentParams struct {
 	PaymentIntentID string
 }
@@ -0,0 +1,335 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/getsentry/sentry-go"
+	"github.com/hibiken/asynq"
+	"github.com/rotisserie/eris"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// DeadLetterAlertWindow archived tasks are counted over this window when checking for a spike
+	DeadLetterAlertWindow = time.Minute * 10
+
+	deadLetterAlertBaseline         = time.Hour * 24
+	deadLetterAlertFactor           = 3.0
+	defaultDeadLetterAlertThreshold = 20
+	deadLetterReplayBatchSize       = 500
+)
+
+// deadLetterReferenceKeys payload fields naming the entity a task works on, the most specific first
+var deadLetterReferenceKeys = []string{
+	"bulk_purchase_order_id",
+	"purchase_order_id",
+	"inquiry_seller_id",
+	"inquiry_id",
+	"payment_transaction_id",
+	"seller_quotation_id",
+	"room_id",
+	"reference_id",
+	"user_id",
+}
+
+// GetDeadLetterReference entity of the task payload, the type is the payload field without its _id suffix
+func GetDeadLetterReference(payload []byte) (referenceType string, referenceID string) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(DecodeEnvelope(payload).Payload, &fields); err != nil {
+		return
+	}
+
+	for _, key := range deadLetterReferenceKeys {
+		if id, ok := fields[key].(string); ok && id != "" {
+			return strings.TrimSuffix(key, "_id"), id
+		}
+	}
+
+	return
+}
+
+// handleTaskError records the tasks archived by asynq, the earlier failures are retried
+func (worker *Worker) handleTaskError(ctx context.Context, task *asynq.Task, err error) {
+	if IsThrottled(err) {
+		return
+	}
+	worker.Logger.Debugf("Task error task=%s payload=%s err=%+v", task.Type(), string(task.Payload()), err)
+
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	if retried < maxRetry && !errors.Is(err, asynq.SkipRetry) {
+		return
+	}
+
+	if e := worker.recordDeadLetter(ctx, task, err, retried, maxRetry); e != nil {
+		worker.Logger.Errorf("Record dead letter task=%s err=%+v", task.Type(), e)
+	}
+}
+
+func (worker *Worker) recordDeadLetter(ctx context.Context, task *asynq.Task, taskErr error, retried int, maxRetry int) error {
+	taskID, _ := asynq.GetTaskID(ctx)
+	queue, _ := asynq.GetQueueName(ctx)
+	referenceType, referenceID := GetDeadLetterReference(task.Payload())
+
+	var payload = json.RawMessage(task.Payload())
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(task.Payload()))
+	}
+	stack, _ := json.Marshal(errs.FormatErisJSON(taskErr))
+
+	var record = models.DeadLetterTask{
+		TaskID:        taskID,
+		Queue:         queue,
+		TaskName:      task.Type(),
+		Payload:       payload,
+		Status:        enums.DeadLetterStatusArchived,
+		Retried:       retried,
+		MaxRetry:      maxRetry,
+		ErrorMessage:  taskErr.Error(),
+		ErrorStack:    stack,
+		FailedAt:      time.Now().Unix(),
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+	}
+	record.ID = helper.GenerateXID()
+
+	worker.Logger.Errorf("Task archived task=%s id=%s queue=%s reference=%s:%s retried=%d err=%v", task.Type(), taskID, queue, referenceType, referenceID, retried, taskErr)
+
+	return worker.App.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "queue"}, {Name: "task_id"}},
+		DoNothing: true,
+	}).Create(&record).Error
+}
+
+type ReplayDeadLetterTasksParams struct {
+	IDs        []string
+	TaskName   string
+	FailedFrom int64
+	FailedTo   int64
+	UserID     string
+}
+
+// ReplayDeadLetterTask enqueues the archived task again with its current payload
+func (worker *Worker) ReplayDeadLetterTask(ctx context.Context, id string, userID string) (*models.DeadLetterTask, error) {
+	var record models.DeadLetterTask
+	var err = worker.App.DB.First(&record, "id = ?", id).Error
+	if err != nil {
+		if worker.App.DB.IsRecordNotFoundError(err) {
+			return nil, errs.ErrDeadLetterTaskNotFound
+		}
+		return nil, err
+	}
+
+	replayed, err := worker.replayDeadLetterTask(ctx, &record, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !replayed {
+		return nil, errs.ErrDeadLetterTaskNotReplayable
+	}
+
+	return &record, nil
+}
+
+// ReplayDeadLetterTasks replays up to 500 archived tasks matching the filters, oldest first
+func (worker *Worker) ReplayDeadLetterTasks(ctx context.Context, params ReplayDeadLetterTasksParams) (count int, err error) {
+	var query = worker.App.DB.Model(&models.DeadLetterTask{}).Where("status = ?", enums.DeadLetterStatusArchived)
+	if len(params.IDs) > 0 {
+		query = query.Where("id IN ?", params.IDs)
+	}
+	if params.TaskName != "" {
+		query = query.Where("task_name = ?", params.TaskName)
+	}
+	if params.FailedFrom > 0 {
+		query = query.Where("failed_at >= ?", params.FailedFrom)
+	}
+	if params.FailedTo > 0 {
+		query = query.Where("failed_at <= ?", params.FailedTo)
+	}
+
+	var records models.DeadLetterTasks
+	err = query.Order("failed_at ASC").Limit(deadLetterReplayBatchSize).Find(&records).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		replayed, err := worker.replayDeadLetterTask(ctx, record, params.UserID)
+		if err != nil {
+			return count, err
+		}
+		if replayed {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// replayDeadLetterTask claims the record before enqueuing so concurrent replays run the task once
+func (worker *Worker) replayDeadLetterTask(ctx context.Context, record *models.DeadLetterTask, userID string) (bool, error) {
+	var now = time.Now().Unix()
+	var replayCount = record.ReplayCount
+	var replayTaskID = fmt.Sprintf("%s_replay_%d", record.ID, replayCount+1)
+
+	var result = worker.App.DB.Model(record).Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", record.ID, enums.DeadLetterStatusArchived).
+		Updates(map[string]interface{}{
+			"status":              enums.DeadLetterStatusReplayed,
+			"replay_count":        replayCount + 1,
+			"replayed_by_user_id": userID,
+			"replayed_at":         now,
+			"replay_task_id":      replayTaskID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	var opts = worker.TaskEnqueueOptions(record.TaskName, asynq.TaskID(replayTaskID))
+	if record.Queue != "" {
+		opts = append(opts, asynq.Queue(record.Queue))
+	}
+
+	_, err := worker.Client.EnqueueContext(ctx, asynq.NewTask(record.TaskName, record.Payload), opts...)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) && !errors.Is(err, asynq.ErrDuplicateTask) {
+		worker.App.DB.Model(&models.DeadLetterTask{}).
+			Where("id = ?", record.ID).
+			Updates(map[string]interface{}{
+				"status":       enums.DeadLetterStatusArchived,
+				"replay_count": replayCount,
+			})
+		return false, eris.Wrapf(err, "replay dead letter id=%s task=%s", record.ID, record.TaskName)
+	}
+
+	worker.deleteArchivedTask(record)
+	return true, nil
+}
+
+// DiscardDeadLetterTask drops the archived task, it is kept in the table for the record
+func (worker *Worker) DiscardDeadLetterTask(id string) (*models.DeadLetterTask, error) {
+	var record models.DeadLetterTask
+	var err = worker.App.DB.First(&record, "id = ?", id).Error
+	if err != nil {
+		if worker.App.DB.IsRecordNotFoundError(err) {
+			return nil, errs.ErrDeadLetterTaskNotFound
+		}
+		return nil, err
+	}
+
+	var result = worker.App.DB.Model(&record).Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", record.ID, enums.DeadLetterStatusArchived).
+		Update("status", enums.DeadLetterStatusDiscarded)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errs.ErrDeadLetterTaskNotReplayable
+	}
+
+	worker.deleteArchivedTask(&record)
+	return &record, nil
+}
+
+// deleteArchivedTask removes the task from the asynq archive, it may already be gone after the archive retention
+func (worker *Worker) deleteArchivedTask(record *models.DeadLetterTask) {
+	if record.TaskID == "" || record.Queue == "" {
+		return
+	}
+
+	var err = worker.Inspector.DeleteTask(record.Queue, record.TaskID)
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+		worker.Logger.Errorf("Delete archived task id=%s queue=%s err=%+v", record.TaskID, record.Queue, err)
+	}
+}
+
+// DeadLetterRate archived tasks of the last window against the average window of the last day
+type DeadLetterRate struct {
+	Count     int64            `json:"count"`
+	Baseline  float64          `json:"baseline"`
+	Threshold int              `json:"threshold"`
+	TaskNames map[string]int64 `json:"task_names"`
+	IsSpike   bool             `json:"is_spike"`
+}
+
+// GetDeadLetterRate a spike is at least the threshold and three times the baseline
+func (worker *Worker) GetDeadLetterRate() (*DeadLetterRate, error) {
+	var now = time.Now()
+	var windowStart = now.Add(-DeadLetterAlertWindow).Unix()
+	var rate = DeadLetterRate{
+		Threshold: worker.App.Config.DeadLetterAlertThreshold,
+		TaskNames: map[string]int64{},
+	}
+	if rate.Threshold <= 0 {
+		rate.Threshold = defaultDeadLetterAlertThreshold
+	}
+
+	var counts []struct {
+		TaskName string
+		Count    int64
+	}
+	var err = worker.App.DB.Model(&models.DeadLetterTask{}).
+		Select("task_name, COUNT(1) AS count").
+		Where("failed_at >= ?", windowStart).
+		Group("task_name").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		rate.TaskNames[count.TaskName] = count.Count
+		rate.Count += count.Count
+	}
+
+	var previous int64
+	err = worker.App.DB.Model(&models.DeadLetterTask{}).
+		Where("failed_at >= ? AND failed_at < ?", now.Add(-deadLetterAlertBaseline).Unix(), windowStart).
+		Count(&previous).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rate.Baseline = float64(previous) / float64((deadLetterAlertBaseline-DeadLetterAlertWindow)/DeadLetterAlertWindow)
+	rate.IsSpike = rate.Count >= int64(rate.Threshold) && float64(rate.Count) >= rate.Baseline*deadLetterAlertFactor
+
+	return &rate, nil
+}
+
+// CheckDeadLetterRate reports a spike of archived tasks to Sentry
+func (worker *Worker) CheckDeadLetterRate() (*DeadLetterRate, error) {
+	rate, err := worker.GetDeadLetterRate()
+	if err != nil {
+		return nil, err
+	}
+	if !rate.IsSpike {
+		return rate, nil
+	}
+
+	var message = fmt.Sprintf("Dead letter spike: %d tasks archived in the last %s (baseline %.1f)", rate.Count, DeadLetterAlertWindow, rate.Baseline)
+	worker.Logger.Errorf("%s task_names=%v", message, rate.TaskNames)
+
+	sentry.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelError)
+		scope.SetTag("alert", "dead_letter_spike")
+		scope.SetContext("dead_letter", map[string]interface{}{
+			"count":      rate.Count,
+			"baseline":   rate.Baseline,
+			"threshold":  rate.Threshold,
+			"task_names": rate.TaskNames,
+		})
+		sentry.CaptureMessage(message)
+	})
+
+	return rate, nil
+}
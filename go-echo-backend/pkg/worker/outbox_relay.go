@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/hibiken/asynq"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	outboxRelayInterval    = time.Second * 2
+	outboxRelayBatchSize   = 100
+	outboxRelayMaxAttempts = 20
+	outboxRelayMaxBackoff  = time.Minute * 10
+
+	outboxRelayClaimTimeout = time.Minute * 5
+)
+
+// RunOutboxRelay drains pending outbox rows into asynq until ctx is done.
+// Rows are claimed with SKIP LOCKED so several consumers can relay in parallel,
+// and the row ID is used as asynq task ID so a re-relayed row never runs twice.
+func (worker *Worker) RunOutboxRelay(ctx context.Context) {
+	var ticker = time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	worker.Logger.Debugf("Run outbox relay successfully")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				count, err := worker.relayOutboxMessages(ctx)
+				if err != nil {
+					worker.Logger.Errorf("Relay outbox messages error: %+v", err)
+					break
+				}
+				if count < outboxRelayBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (worker *Worker) relayOutboxMessages(ctx context.Context) (int, error) {
+	messages, err := worker.claimOutboxMessages()
+	if err != nil {
+		return 0, err
+	}
+
+	// Enqueued after the claim committed, no row lock is held while waiting on redis
+	for _, message := range messages {
+		var updates = worker.RelayOutboxMessage(ctx, message)
+		var err = worker.App.DB.Model(&models.OutboxMessage{}).
+			Where("id = ? AND status = ?", message.ID, enums.OutboxStatusPending).
+			Updates(updates).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// claimOutboxMessages pushes the next attempt of the due rows past the claim timeout,
+// rows of a relay which stopped before recording the result are claimed again once it expires
+func (worker *Worker) claimOutboxMessages() (messages []*models.OutboxMessage, err error) {
+	var now = time.Now()
+	err = worker.App.DB.Transaction(func(tx *gorm.DB) error {
+		var err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", enums.OutboxStatusPending, now.Unix()).
+			Order("created_at ASC").
+			Limit(outboxRelayBatchSize).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		var ids = lo.Map(messages, func(message *models.OutboxMessage, _ int) string {
+			return message.ID
+		})
+		return tx.Model(&models.OutboxMessage{}).
+			Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(outboxRelayClaimTimeout).Unix()).Error
+	})
+
+	return
+}
+
+// RelayOutboxMessage enqueues a claimed row and returns the columns recording the attempt
+func (worker *Worker) RelayOutboxMessage(ctx context.Context, message *models.OutboxMessage) map[string]interface{} {
+	var now = time.Now()
+	var opts = worker.TaskEnqueueOptions(message.TaskName, asynq.TaskID(message.ID))
+	if message.Queue != "" {
+		opts = append(opts, asynq.Queue(message.Queue))
+	}
+	if message.ProcessAt > now.Unix() {
+		opts = append(opts, asynq.ProcessAt(time.Unix(message.ProcessAt, 0)))
+	}
+
+	info, err := worker.Client.EnqueueContext(ctx, asynq.NewTask(message.TaskName, message.Payload), opts...)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) && !errors.Is(err, asynq.ErrDuplicateTask) {
+		var attempts = message.Attempts + 1
+		var status = enums.OutboxStatusPending
+		if attempts >= outboxRelayMaxAttempts {
+			status = enums.OutboxStatusFailed
+		}
+		var backoff = time.Duration(math.Min(math.Pow(2, float64(attempts)), outboxRelayMaxBackoff.Seconds())) * time.Second
+
+		worker.Logger.Errorf("Relay outbox message id=%s task=%s attempts=%d err=%+v", message.ID, message.TaskName, attempts, err)
+		return map[string]interface{}{
+			"status":          status,
+			"attempts":        attempts,
+			"last_error":      err.Error(),
+			"next_attempt_at": now.Add(backoff).Unix(),
+		}
+	}
+
+	// Task ID conflict means the task was already enqueued by a previous relay run
+	var taskID = message.ID
+	if info != nil {
+		taskID = info.ID
+	}
+
+	return map[string]interface{}{
+		"status":        enums.OutboxStatusDispatched,
+		"attempts":      message.Attempts + 1,
+		"last_error":    "",
+		"dispatched_at": now.Unix(),
+		"task_id":       taskID,
+	}
+}
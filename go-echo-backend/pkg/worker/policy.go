@@ -0,0 +1,243 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	defaultMaxRetry = 3
+
+	// concurrencyWait how long a task waits for a free slot before it is put back in the queue
+	concurrencyWait  = time.Second
+	concurrencyDelay = time.Second * 5
+
+	// rateLimitWait rate limited tasks sleep up to this, longer waits put them back in the queue
+	rateLimitWait = time.Second * 2
+)
+
+// Queue names of the task policies
+var (
+	QueueNameLow      = queueLow
+	QueueNameDefault  = queueDefault
+	QueueNameMedium   = queueMedium
+	QueueNameHigh     = queueHigh
+	QueueNameCritical = queueCritical
+)
+
+// ErrNonRetryable tasks failing with an error wrapping it are archived without retry
+var ErrNonRetryable = errors.New("non retryable")
+
+// TaskPolicy how the worker runs a task, the zero value keeps the defaults
+type TaskPolicy struct {
+	// Queue queue the task is enqueued to
+	Queue string
+
+	// MaxConcurrency tasks of this type running at once in a consumer process
+	MaxConcurrency int
+
+	// RateLimit token bucket shared by the tasks calling the same integration
+	RateLimit *RateLimit
+
+	// Retry attempts and backoff, 3 retries with the asynq backoff by default
+	Retry *RetryPolicy
+
+	// Timeout deadline of one attempt
+	Timeout time.Duration
+
+	// Unique rejects the same task enqueued again with the same payload within the window
+	Unique time.Duration
+}
+
+// PolicyTask a task which declares its policy
+type PolicyTask interface {
+	Policy() TaskPolicy
+}
+
+// RateLimit allows Limit calls per Interval with bursts of up to Burst, Limit when unset
+type RateLimit struct {
+	Key      string
+	Limit    int
+	Interval time.Duration
+	Burst    int
+}
+
+// RetryPolicy attempts and backoff of a failed task
+type RetryPolicy struct {
+	MaxRetry int
+
+	// Backoff delay before the nth retry
+	Backoff func(n int) time.Duration
+
+	// NonRetryable errors archived at once
+	NonRetryable []error
+
+	// IsNonRetryable classifies the other permanent errors, like a validation error of the integration
+	IsNonRetryable func(err error) bool
+}
+
+// ExponentialBackoff doubles the delay from base up to max, with jitter
+func ExponentialBackoff(base time.Duration, max time.Duration) func(n int) time.Duration {
+	return func(n int) time.Duration {
+		var delay = time.Duration(float64(base) * math.Pow(2, float64(n)))
+		if delay <= 0 || delay > max {
+			delay = max
+		}
+
+		return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+}
+
+// FixedBackoff the same delay before every retry
+func FixedBackoff(delay time.Duration) func(n int) time.Duration {
+	return func(n int) time.Duration {
+		return delay
+	}
+}
+
+// throttledError the task is put back in the queue without counting as a failed attempt
+type throttledError struct {
+	reason string
+	delay  time.Duration
+}
+
+func (e *throttledError) Error() string {
+	return fmt.Sprintf("task throttled by %s, retry in %s", e.reason, e.delay)
+}
+
+// IsThrottled the error only delayed the task
+func IsThrottled(err error) bool {
+	var throttled *throttledError
+	return errors.As(err, &throttled)
+}
+
+// GetTaskPolicy policy declared by the task
+func GetTaskPolicy(task interface{}) TaskPolicy {
+	if policyTask, ok := task.(PolicyTask); ok {
+		return policyTask.Policy()
+	}
+
+	return TaskPolicy{}
+}
+
+// EnqueueOptions default enqueue options of the policy, the caller options are appended after them and win
+func (p TaskPolicy) EnqueueOptions() []asynq.Option {
+	var maxRetry = defaultMaxRetry
+	if p.Retry != nil {
+		maxRetry = p.Retry.MaxRetry
+	}
+
+	var options = []asynq.Option{
+		asynq.MaxRetry(maxRetry),
+		asynq.Retention(time.Hour * 24),
+	}
+	if p.Queue != "" {
+		options = append(options, asynq.Queue(p.Queue))
+	}
+	if p.Timeout > 0 {
+		options = append(options, asynq.Timeout(p.Timeout))
+	}
+
+	return options
+}
+
+// UniqueTaskID task ID deduplicating the enveloped payload within the Unique window, empty when the policy has none.
+// asynq.Unique hashes the whole payload, which never matches since the envelope carries a new trace ID and enqueue time,
+// so the key is the task name with the hash of the inner payload and the window the enqueue falls in
+func (p TaskPolicy) UniqueTaskID(taskName string, payload []byte, now time.Time) string {
+	if p.Unique <= 0 {
+		return ""
+	}
+
+	var sum = sha256.Sum256(DecodeEnvelope(payload).Payload)
+	var window = now.UnixNano() / int64(p.Unique)
+
+	return fmt.Sprintf("%s:%s:%d", taskName, hex.EncodeToString(sum[:]), window)
+}
+
+// IsNonRetryable the error is classified as permanent by the policy
+func (p TaskPolicy) IsNonRetryable(err error) bool {
+	if errors.Is(err, ErrNonRetryable) {
+		return true
+	}
+
+	if p.Retry != nil {
+		for _, target := range p.Retry.NonRetryable {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		if p.Retry.IsNonRetryable != nil && p.Retry.IsNonRetryable(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wrapHandler enforces the policy around the task handler
+func (worker *Worker) wrapHandler(taskName string, policy TaskPolicy, handler Handler) Handler {
+	var slots chan struct{}
+	if policy.MaxConcurrency > 0 {
+		slots = make(chan struct{}, policy.MaxConcurrency)
+	}
+
+	return func(ctx context.Context, task *asynq.Task) error {
+		if slots != nil {
+			var timer = time.NewTimer(concurrencyWait)
+			select {
+			case slots <- struct{}{}:
+				timer.Stop()
+				defer func() { <-slots }()
+			case <-timer.C:
+				return &throttledError{reason: fmt.Sprintf("max concurrency %d", policy.MaxConcurrency), delay: concurrencyDelay}
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		if policy.RateLimit != nil && worker.limiter != nil {
+			if err := worker.limiter.Wait(ctx, *policy.RateLimit, rateLimitWait); err != nil {
+				return err
+			}
+		}
+
+		if policy.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+			defer cancel()
+		}
+
+		var err = handler(ctx, task)
+		if err != nil && !IsThrottled(err) && policy.IsNonRetryable(err) && !errors.Is(err, asynq.SkipRetry) {
+			worker.Logger.Debugf("Task %s failed with a non retryable error: %v", taskName, err)
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+
+		return err
+	}
+}
+
+// retryDelay backoff of the task policy, throttled tasks wait for the limiter
+func (worker *Worker) retryDelay(n int, err error, task *asynq.Task) time.Duration {
+	var throttled *throttledError
+	if errors.As(err, &throttled) {
+		return throttled.delay
+	}
+
+	if policy, ok := worker.policies[task.Type()]; ok && policy.Retry != nil && policy.Retry.Backoff != nil {
+		return policy.Retry.Backoff(n)
+	}
+
+	return asynq.DefaultRetryDelayFunc(n, err, task)
+}
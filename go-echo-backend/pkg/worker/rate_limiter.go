@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// tokenBucketScript takes a token from the bucket, it returns 0 when one was taken
+// or the milliseconds until the next token otherwise.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate) + 1000)
+return wait
+`)
+
+// rateLimiter token buckets in redis, shared by every consumer process
+type rateLimiter struct {
+	namespace string
+	redis     redis.UniversalClient
+}
+
+func newRateLimiter(namespace string, redisClient redis.UniversalClient) *rateLimiter {
+	return &rateLimiter{
+		namespace: namespace,
+		redis:     redisClient,
+	}
+}
+
+// Take takes a token, or returns how long until the next one
+func (l *rateLimiter) Take(ctx context.Context, limit RateLimit) (time.Duration, error) {
+	if limit.Limit <= 0 || limit.Interval <= 0 {
+		return 0, nil
+	}
+
+	var burst = limit.Burst
+	if burst <= 0 {
+		burst = limit.Limit
+	}
+	var rate = float64(limit.Limit) / float64(limit.Interval.Milliseconds())
+
+	wait, err := tokenBucketScript.Run(ctx, l.redis, []string{l.getKey(limit.Key)}, rate, burst, time.Now().UnixMilli()).Int64()
+	if err != nil {
+		return 0, eris.Wrapf(err, "rate limit key=%s", limit.Key)
+	}
+
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+// Wait sleeps for a token up to maxWait, longer waits return a throttled error so the task goes back to the queue
+func (l *rateLimiter) Wait(ctx context.Context, limit RateLimit, maxWait time.Duration) error {
+	var deadline = time.Now().Add(maxWait)
+	for {
+		wait, err := l.Take(ctx, limit)
+		if err != nil {
+			// The integration limit is not worth failing the task for
+			return nil
+		}
+		if wait == 0 {
+			return nil
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return &throttledError{reason: fmt.Sprintf("rate limit %s", limit.Key), delay: wait}
+		}
+
+		var timer = time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *rateLimiter) getKey(key string) string {
+	return fmt.Sprintf("%s_rate_limit_%s", l.namespace, key)
+}
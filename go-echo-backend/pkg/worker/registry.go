@@ -0,0 +1,415 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/hibiken/asynq"
+	"github.com/rotisserie/eris"
+	"github.com/rs/xid"
+)
+
+const (
+	envelopeSchema = "inflow.task.v1"
+
+	// legacyPayloadVersion version of the payloads enqueued before the task moved to the registry
+	legacyPayloadVersion = 1
+
+	checkQueuedTasksPageSize = 500
+)
+
+// Envelope wraps the payload of a registered task with its schema version and trace metadata
+type Envelope struct {
+	Schema     string          `json:"schema"`
+	Version    int             `json:"version"`
+	TraceID    string          `json:"trace_id,omitempty"`
+	EnqueuedAt int64           `json:"enqueued_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// DecodeEnvelope reads the envelope of the raw payload, payloads enqueued without one are the legacy version
+func DecodeEnvelope(raw []byte) Envelope {
+	var envelope Envelope
+	if err := json.Unmarshal(raw, &envelope); err == nil && envelope.Schema == envelopeSchema {
+		return envelope
+	}
+
+	return Envelope{
+		Version: legacyPayloadVersion,
+		Payload: raw,
+	}
+}
+
+// Upcaster converts a payload of one version to the next one
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+// RenameField upcaster for a renamed payload field
+func RenameField(from string, to string) Upcaster {
+	return func(payload json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+
+		if value, ok := fields[from]; ok {
+			if _, exists := fields[to]; !exists {
+				fields[to] = value
+			}
+			delete(fields, from)
+		}
+
+		return json.Marshal(fields)
+	}
+}
+
+// RegisteredTask a task served by the consumer
+type RegisteredTask interface {
+	TaskName() string
+	Handler(context.Context, *asynq.Task) error
+}
+
+// VersionedTask a registered task which reads enveloped payloads up to its version
+type VersionedTask interface {
+	RegisteredTask
+	Version() int
+}
+
+type traceIDKey struct{}
+
+type resultWriterKey struct{}
+
+// WithTraceID the tasks dispatched with the context carry the trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// GetTraceID trace ID of the context, the request ID when it comes from an API request
+func GetTraceID(ctx context.Context) string {
+	if traceID, ok := ctx.Value(traceIDKey{}).(string); ok && traceID != "" {
+		return traceID
+	}
+
+	if reqID, ok := ctx.Value(logger.RequestIDKey).(string); ok {
+		return reqID
+	}
+
+	return ""
+}
+
+// TaskType a typed task: the payload struct P is wrapped in an envelope with the version,
+// and payloads of older versions are upcast before the handler reads them.
+type TaskType[P any] struct {
+	name      string
+	version   int
+	handle    func(ctx context.Context, payload P) error
+	upcasters map[int]Upcaster
+	options   []asynq.Option
+	policy    TaskPolicy
+}
+
+type TaskTypeOption func(*taskTypeOptions)
+
+type taskTypeOptions struct {
+	upcasters map[int]Upcaster
+	options   []asynq.Option
+	policy    TaskPolicy
+}
+
+// WithUpcaster converts the payloads of version from to version from+1
+func WithUpcaster(from int, upcaster Upcaster) TaskTypeOption {
+	return func(o *taskTypeOptions) {
+		o.upcasters[from] = upcaster
+	}
+}
+
+// WithTaskOptions default enqueue options of the task
+func WithTaskOptions(opts ...asynq.Option) TaskTypeOption {
+	return func(o *taskTypeOptions) {
+		o.options = append(o.options, opts...)
+	}
+}
+
+// WithPolicy concurrency, rate limit, retries, timeout and uniqueness of the task
+func WithPolicy(policy TaskPolicy) TaskTypeOption {
+	return func(o *taskTypeOptions) {
+		o.policy = policy
+	}
+}
+
+// NewTaskType declares a task, the version must be bumped with an upcaster whenever the payload changes incompatibly
+func NewTaskType[P any](name string, version int, handle func(ctx context.Context, payload P) error, opts ...TaskTypeOption) *TaskType[P] {
+	var options = taskTypeOptions{upcasters: map[int]Upcaster{}}
+	for _, apply := range opts {
+		apply(&options)
+	}
+
+	for v := legacyPayloadVersion; v < version; v++ {
+		if _, ok := options.upcasters[v]; !ok {
+			panic(fmt.Sprintf("task %s: missing upcaster from version %d to %d", name, v, v+1))
+		}
+	}
+
+	return &TaskType[P]{
+		name:      name,
+		version:   version,
+		handle:    handle,
+		upcasters: options.upcasters,
+		options:   options.options,
+		policy:    options.policy,
+	}
+}
+
+// TaskName task name
+func (t *TaskType[P]) TaskName() string {
+	return t.name
+}
+
+// Version current payload version
+func (t *TaskType[P]) Version() int {
+	return t.version
+}
+
+// Policy policy enforced by the worker
+func (t *TaskType[P]) Policy() TaskPolicy {
+	return t.policy
+}
+
+// Decode upcasts the raw payload to the current version
+func (t *TaskType[P]) Decode(raw []byte) (envelope Envelope, payload P, err error) {
+	envelope = DecodeEnvelope(raw)
+	if envelope.Version > t.version {
+		err = eris.Errorf("task %s: payload version %d is newer than %d", t.name, envelope.Version, t.version)
+		return
+	}
+
+	var data = envelope.Payload
+	for v := envelope.Version; v < t.version; v++ {
+		data, err = t.upcasters[v](data)
+		if err != nil {
+			err = eris.Wrapf(err, "task %s: upcast payload from version %d", t.name, v)
+			return
+		}
+	}
+
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &payload); err != nil {
+			err = eris.Wrapf(err, "task %s: bind payload", t.name)
+			return
+		}
+	}
+
+	return
+}
+
+// Handler handler
+func (t *TaskType[P]) Handler(ctx context.Context, task *asynq.Task) error {
+	envelope, payload, err := t.Decode(task.Payload())
+	if err != nil {
+		return err
+	}
+
+	if err = GetInstance().Validator.Validate(&payload); err != nil {
+		return eris.Wrapf(asynq.SkipRetry, "task %s: validate payload err=%v", t.name, err)
+	}
+
+	if envelope.TraceID != "" {
+		ctx = WithTraceID(ctx, envelope.TraceID)
+	}
+	ctx = context.WithValue(ctx, resultWriterKey{}, task.ResultWriter())
+
+	return t.handle(ctx, payload)
+}
+
+// WriteResult stores the result of the task handled with ctx, it is shown in the monitoring UI
+func WriteResult(ctx context.Context, data []byte) {
+	if writer, ok := ctx.Value(resultWriterKey{}).(*asynq.ResultWriter); ok && writer != nil {
+		_, _ = writer.Write(data)
+	}
+}
+
+// NewPayload enveloped payload of the current version
+func (t *TaskType[P]) NewPayload(ctx context.Context, payload P) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, eris.Wrapf(err, "task %s: marshal payload", t.name)
+	}
+
+	var traceID = GetTraceID(ctx)
+	if traceID == "" {
+		traceID = xid.New().String()
+	}
+
+	return json.Marshal(Envelope{
+		Schema:     envelopeSchema,
+		Version:    t.version,
+		TraceID:    traceID,
+		EnqueuedAt: time.Now().Unix(),
+		Payload:    data,
+	})
+}
+
+// Dispatch enqueues the task with the payload
+func (t *TaskType[P]) Dispatch(ctx context.Context, payload P, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	data, err := t.NewPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetInstance().Client.EnqueueContext(ctx, asynq.NewTask(t.name, data), append(t.EnqueueOptions(t.name, data), opts...)...)
+}
+
+// DispatchIn enqueues the task to be processed after d
+func (t *TaskType[P]) DispatchIn(ctx context.Context, payload P, d time.Duration, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	return t.Dispatch(ctx, payload, append([]asynq.Option{asynq.ProcessIn(d)}, opts...)...)
+}
+
+// DispatchAt enqueues the task to be processed at the time
+func (t *TaskType[P]) DispatchAt(ctx context.Context, payload P, at time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	return t.Dispatch(ctx, payload, append([]asynq.Option{asynq.ProcessAt(at)}, opts...)...)
+}
+
+// DispatchNamed enqueues the payload as a task named name, served by a handler registered with HandleTaskPrefix
+func (t *TaskType[P]) DispatchNamed(ctx context.Context, name string, payload P, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	data, err := t.NewPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetInstance().Client.EnqueueContext(ctx, asynq.NewTask(name, data), append(t.EnqueueOptions(name, data), opts...)...)
+}
+
+// EnqueueOptions options of the policy and the task for the enveloped payload enqueued as name, the caller options are appended after them and win
+func (t *TaskType[P]) EnqueueOptions(name string, data []byte) []asynq.Option {
+	var options = append(t.policy.EnqueueOptions(), t.options...)
+	if taskID := t.policy.UniqueTaskID(name, data, time.Now()); taskID != "" {
+		options = append(options, asynq.TaskID(taskID))
+	}
+
+	return options
+}
+
+// Schedule registers the task with the payload on the cron spec
+func (t *TaskType[P]) Schedule(worker *Worker, cronspec string, payload P, opts ...asynq.Option) (string, error) {
+	data, err := t.NewPayload(context.Background(), payload)
+	if err != nil {
+		return "", err
+	}
+
+	// The envelope of a cron task is built once, so the hash of the whole payload is stable across runs
+	var options = append(t.policy.EnqueueOptions(), t.options...)
+	if t.policy.Unique > 0 {
+		options = append(options, asynq.Unique(t.policy.Unique))
+	}
+
+	return worker.Scheduler.Register(cronspec, asynq.NewTask(t.name, data, append(options, opts...)...))
+}
+
+// Outbox the task with the payload written in the caller transaction by the outbox repo
+func (t *TaskType[P]) Outbox(ctx context.Context, payload P) (*OutboxTask, error) {
+	data, err := t.NewPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var task = OutboxTask{name: t.name, payload: data}
+	if deduper, ok := any(payload).(models.OutboxDeduper); ok {
+		task.dedupeKey = deduper.DedupeKey()
+	}
+
+	return &task, nil
+}
+
+// OutboxTask an enveloped payload waiting for the outbox relay
+type OutboxTask struct {
+	name      string
+	payload   []byte
+	dedupeKey string
+}
+
+func (t *OutboxTask) TaskName() string {
+	return t.name
+}
+
+func (t *OutboxTask) GetPayload() []byte {
+	return t.payload
+}
+
+// DedupeKey dedupe key declared by the payload, empty when each write is its own key
+func (t *OutboxTask) DedupeKey() string {
+	return t.dedupeKey
+}
+
+// HandleTaskPrefix serves every task type starting with the prefix, like the dynamic tasks named after a record
+func (worker *Worker) HandleTaskPrefix(prefix string, task RegisteredTask) {
+	worker.mux.HandleFunc(prefix, task.Handler)
+}
+
+// CheckQueuedTasks fails when a task waiting in any queue has no handler,
+// or carries a payload version newer than the handler reads.
+func (worker *Worker) CheckQueuedTasks() error {
+	queues, err := worker.Inspector.Queues()
+	if err != nil {
+		return eris.Wrap(err, "list queues")
+	}
+
+	var problems = map[string]string{}
+	for _, queue := range queues {
+		var listers = []func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error){
+			worker.Inspector.ListPendingTasks,
+			worker.Inspector.ListScheduledTasks,
+			worker.Inspector.ListRetryTasks,
+		}
+
+		for _, list := range listers {
+			for page := 1; ; page++ {
+				infos, err := list(queue, asynq.PageSize(checkQueuedTasksPageSize), asynq.Page(page))
+				if err != nil {
+					return eris.Wrapf(err, "list tasks queue=%s", queue)
+				}
+
+				for _, info := range infos {
+					if problem := worker.checkQueuedTask(info); problem != "" {
+						problems[info.Type] = problem
+					}
+				}
+
+				if len(infos) < checkQueuedTasksPageSize {
+					break
+				}
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var messages []string
+	for taskType, problem := range problems {
+		messages = append(messages, fmt.Sprintf("%s: %s", taskType, problem))
+	}
+	sort.Strings(messages)
+
+	return eris.Errorf("queued tasks can not be handled: %s", strings.Join(messages, "; "))
+}
+
+func (worker *Worker) checkQueuedTask(info *asynq.TaskInfo) string {
+	_, pattern := worker.mux.Handler(asynq.NewTask(info.Type, nil))
+	if pattern == "" {
+		return "missing handler"
+	}
+
+	if task, ok := worker.tasks[pattern].(VersionedTask); ok {
+		if version := DecodeEnvelope(info.Payload).Version; version > task.Version() {
+			return fmt.Sprintf("payload version %d is newer than %d", version, task.Version())
+		}
+	}
+
+	return ""
+}
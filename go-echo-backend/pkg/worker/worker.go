@@ -2,9 +2,6 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/app"
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
@@ -13,10 +10,9 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/validation"
 	"github.com/hibiken/asynq"
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
-	"github.com/rotisserie/eris"
-
 	"github.com/hibiken/asynqmon"
 )
 
@@ -38,14 +34,6 @@ var (
 	QueueCritical = asynq.Queue(queueCritical)
 )
 
-// TaskHandler handler
-type TaskHandler interface {
-	TaskName() string
-	Handler(context.Context, *asynq.Task) error
-	GetPayload() []byte
-	Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error)
-}
-
 type Handler func(context.Context, *asynq.Task) error
 
 // Worker dispatcher
@@ -60,7 +48,9 @@ type Worker struct {
 	Scheduler *asynq.Scheduler
 	mux       *asynq.ServeMux
 
-	tasks map[string]TaskHandler
+	tasks    map[string]RegisteredTask
+	policies map[string]TaskPolicy
+	limiter  *rateLimiter
 }
 
 // Config config
@@ -82,6 +72,11 @@ func New(config *Config) *Worker {
 		Client:    client,
 		Config:    config,
 		Inspector: asynq.NewInspector(getRedisConnOpt(config.App.Config)),
+		limiter: newRateLimiter(config.Namespace, redis.NewUniversalClient(&redis.UniversalOptions{
+			Addrs:    config.App.Config.RedisAddress,
+			Password: config.App.Config.RedisPassword,
+			DB:       config.App.Config.RedisDBMode,
+		})),
 	}
 
 	if config.IsConsumer {
@@ -116,43 +111,6 @@ func (worker *Worker) Run() {
 
 }
 
-// SendTaskWithContext send task with context
-func (worker *Worker) SendDynamicTask(task *asynq.Task, handler Handler) (*asynq.TaskInfo, error) {
-	worker.mux.HandleFunc(task.Type(), handler)
-
-	return worker.Client.EnqueueContext(context.Background(), task)
-
-}
-
-// SendTaskWithContext send task with context
-func (worker *Worker) SendTaskWithContext(ctx context.Context, task TaskHandler, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	var options = []asynq.Option{
-		asynq.MaxRetry(3),
-		asynq.Retention(time.Hour * 24),
-	}
-
-	return worker.Client.EnqueueContext(ctx, asynq.NewTask(task.TaskName(), task.GetPayload(), append(options, opts...)...))
-
-}
-
-func (worker *Worker) SendTask(task TaskHandler, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return worker.SendTaskWithContext(context.Background(), task, opts...)
-}
-
-func (worker *Worker) SendTaskAt(task TaskHandler, time time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	var options = []asynq.Option{
-		asynq.ProcessAt(time),
-	}
-	return worker.SendTaskWithContext(context.Background(), task, append(options, opts...)...)
-}
-
-func (worker *Worker) SendTaskIn(task TaskHandler, duration time.Duration, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	var options = []asynq.Option{
-		asynq.ProcessIn(duration),
-	}
-	return worker.SendTaskWithContext(context.Background(), task, append(options, opts...)...)
-}
-
 func GetInstance() *Worker {
 	if instance == nil {
 		panic("Worker instance is nil, must be call New() first")
@@ -170,14 +128,19 @@ func (worker *Worker) createServer() *asynq.Server {
 			// Optionally specify multiple queues with different priority.
 			Queues: map[string]int{
 				queueCritical: 6,
+				queueHigh:     5,
+				queueMedium:   4,
 				queueDefault:  3,
 				queueLow:      1,
 			},
-			Logger:   worker.Logger.Sugar(),
-			LogLevel: asynq.DebugLevel,
-			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
-				worker.Logger.Debugf("Task error task=%s payload=%s err=%+v", task.Type(), string(task.Payload()), err)
-			}),
+			Logger:         worker.Logger.Sugar(),
+			LogLevel:       asynq.DebugLevel,
+			ErrorHandler:   asynq.ErrorHandlerFunc(worker.handleTaskError),
+			RetryDelayFunc: worker.retryDelay,
+			// Throttled tasks are put back in the queue without using an attempt
+			IsFailure: func(err error) bool {
+				return !IsThrottled(err)
+			},
 			// See the godoc for other configuration options
 		},
 	)
@@ -210,38 +173,22 @@ func (worker *Worker) ServeMonitoringRoutes(e *echo.Echo) {
 	e.Any("/tasks/*", echo.WrapHandler(mon), middlewares.IsBasicAuth())
 }
 
-func (worker *Worker) BindAndValidate(rawValue []byte, task TaskHandler) error {
-	var err = worker.Bind(rawValue, task)
-	if err != nil {
-		return eris.Wrap(err, err.Error())
-	}
-
-	err = worker.Validator.Validate(task)
-	if err != nil {
-		worker.Logger.DebugAny(task, fmt.Sprintf("Validate task error=%s+v", err))
-		return eris.Wrap(err, err.Error())
-	}
+// RegisterTasks records the tasks and their policies, the processes enqueueing by name need them as well as the consumer
+func (worker *Worker) RegisterTasks(tasks ...RegisteredTask) {
+	worker.tasks = map[string]RegisteredTask{}
+	worker.policies = map[string]TaskPolicy{}
 
-	return nil
-}
-
-func (worker *Worker) Bind(rawValue []byte, task TaskHandler) error {
-	var err = json.Unmarshal(rawValue, task)
-	if err != nil {
-		worker.Logger.ErrorAny(err, "Bind error")
-		return eris.Wrap(err, err.Error())
+	for _, task := range tasks {
+		worker.tasks[task.TaskName()] = task
+		worker.policies[task.TaskName()] = GetTaskPolicy(task)
 	}
-	return nil
 }
 
-func (worker *Worker) CreateTaskHandler(tasks ...TaskHandler) *asynq.ServeMux {
+// CreateTaskHandler serves the registered tasks, consumer only
+func (worker *Worker) CreateTaskHandler() *asynq.ServeMux {
 	var mux = asynq.NewServeMux()
-	worker.tasks = map[string]TaskHandler{}
-
-	for _, task := range tasks {
-		mux.HandleFunc(task.TaskName(), task.Handler)
-
-		worker.tasks[task.TaskName()] = task
+	for name, task := range worker.tasks {
+		mux.HandleFunc(name, worker.wrapHandler(name, worker.policies[name], task.Handler))
 	}
 
 	worker.mux = mux
@@ -249,8 +196,9 @@ func (worker *Worker) CreateTaskHandler(tasks ...TaskHandler) *asynq.ServeMux {
 	return worker.mux
 }
 
-func (worker *Worker) ScheduleTask(conspec string, task TaskHandler, opts ...asynq.Option) (string, error) {
-	return worker.Scheduler.Register(conspec, asynq.NewTask(task.TaskName(), task.GetPayload(), opts...))
+// TaskEnqueueOptions enqueue options of the policy of the registered task followed by opts, for the tasks enqueued by name
+func (worker *Worker) TaskEnqueueOptions(taskName string, opts ...asynq.Option) []asynq.Option {
+	return append(worker.policies[taskName].EnqueueOptions(), opts...)
 }
 
 func getRedisConnOpt(config *config.Configuration) asynq.RedisConnOpt {
@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"github.com/thaitanloi365/melody"
+)
+
+// client a connection of a user, over websocket or server-sent events.
+// Both transports go through the same delivery so they receive the same payloads.
+type client interface {
+	getUserID() string
+	getSubscriptions() *topicSubscriptions
+	getPresenceConnection() (PresenceConnection, bool)
+	write(f *frame) error
+}
+
+// frame a payload delivered to clients, Seq is set for the events of a topic
+type frame struct {
+	Topic   string
+	Seq     int64
+	Message json.RawMessage
+}
+
+func newTopicFrame(data json.RawMessage) (*frame, error) {
+	var message TopicMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, eris.Wrapf(err, "decode topic event data=%s", data)
+	}
+
+	var f = frame{Topic: message.Topic, Seq: message.Seq, Message: data}
+	if strings.HasPrefix(message.Topic, string(enums.TopicKindInbox)+":") {
+		// Direct messages keep the payload clients already handle
+		f.Message = message.Data
+	}
+
+	return &f, nil
+}
+
+type wsClient struct {
+	session *melody.Session
+}
+
+func (c *wsClient) getUserID() string {
+	if conn, ok := getPresenceConnection(c.session); ok {
+		return conn.UserID
+	}
+	return ""
+}
+
+func (c *wsClient) getSubscriptions() *topicSubscriptions {
+	if v, found := c.session.Get(UserTopicsContextKey); found {
+		if subscriptions, ok := v.(*topicSubscriptions); ok {
+			return subscriptions
+		}
+	}
+
+	return nil
+}
+
+func (c *wsClient) getPresenceConnection() (PresenceConnection, bool) {
+	return getPresenceConnection(c.session)
+}
+
+func (c *wsClient) write(f *frame) error {
+	return c.session.Write(f.Message)
+}
+
+// getClients clients connected to this node
+func (ws *WS) getClients() (result []client) {
+	if sessions, err := ws.melody.Sessions(); err == nil {
+		for _, session := range sessions {
+			if _, ok := getPresenceConnection(session); ok {
+				result = append(result, &wsClient{session: session})
+			}
+		}
+	}
+
+	ws.sseMu.RLock()
+	for _, c := range ws.sseClients {
+		result = append(result, c)
+	}
+	ws.sseMu.RUnlock()
+
+	return
+}
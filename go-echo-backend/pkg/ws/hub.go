@@ -0,0 +1,133 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+)
+
+// Envelope a broadcast published to every node, each node delivers it to the sessions it holds
+// for the users, or to the sessions subscribed to the topic
+type Envelope struct {
+	NodeID  string          `json:"node_id"`
+	UserIDs []string        `json:"user_ids,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewEnvelope envelope for the receivers, empty and duplicated user IDs are dropped
+func NewEnvelope(nodeID string, userIDs []string, data json.RawMessage) *Envelope {
+	return &Envelope{
+		NodeID:  nodeID,
+		UserIDs: lo.Uniq(lo.Compact(userIDs)),
+		Data:    data,
+	}
+}
+
+func (e *Envelope) ToJSONRaw() json.RawMessage {
+	data, _ := json.Marshal(e)
+
+	return data
+}
+
+func getBroadcastChannel(namespace string) string {
+	if namespace == "" {
+		namespace = "inflow"
+	}
+	return fmt.Sprintf("%s_ws_broadcast", namespace)
+}
+
+// publish sends the data to the users wherever they are connected.
+// It goes through the inbox topic of each user so clients can resume, transient data like typing is sent once without sequence.
+func (ws *WS) publish(userIDs []string, data json.RawMessage, transient bool) error {
+	var envelope = NewEnvelope(ws.nodeID, userIDs, data)
+	if len(envelope.UserIDs) == 0 {
+		return nil
+	}
+
+	if transient {
+		var err = ws.redis.Publish(context.Background(), ws.channel, envelope.ToJSONRaw()).Err()
+		if err != nil {
+			// Clients on this node can still be reached
+			ws.deliver(envelope)
+			return eris.Wrapf(err, "publish ws broadcast users=%v", envelope.UserIDs)
+		}
+		return nil
+	}
+
+	var errors []error
+	for _, userID := range envelope.UserIDs {
+		_, err := ws.Stream.Append(context.Background(), ws.nodeID, models.TopicEvent{
+			Topic: models.NewTopic(enums.TopicKindInbox, userID).String(),
+			Event: enums.TopicEventTypeMessage,
+			Data:  data,
+		})
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return eris.Wrapf(errors[0], "publish ws broadcast failed=%d users=%v", len(errors), envelope.UserIDs)
+	}
+
+	return nil
+}
+
+// subscribe delivers the broadcasts published by any node until the context is cancelled
+func (ws *WS) subscribe(ctx context.Context) {
+	var pubsub = ws.redis.Subscribe(ctx, ws.channel)
+	defer pubsub.Close()
+
+	var messages = pubsub.Channel(redis.WithChannelSize(1024))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var envelope Envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+				ws.Logger.Errorf("Decode ws broadcast payload=%s err=%+v", msg.Payload, err)
+				continue
+			}
+
+			ws.deliver(&envelope)
+		}
+	}
+}
+
+func (ws *WS) deliver(envelope *Envelope) {
+	var f = &frame{Message: envelope.Data}
+	if envelope.Topic != "" {
+		var err error
+		if f, err = newTopicFrame(envelope.Data); err != nil {
+			ws.Logger.ErrorAny(err)
+			return
+		}
+	}
+
+	var clients = lo.Filter(ws.getClients(), func(c client, _ int) bool {
+		if envelope.Topic != "" {
+			var subscriptions = c.getSubscriptions()
+			return subscriptions != nil && subscriptions.has(envelope.Topic)
+		}
+		return lo.Contains(envelope.UserIDs, c.getUserID())
+	})
+
+	ws.Logger.Debugf("Deliver broadcast origin=%s receivers=%v topic=%s total_clients=%d", envelope.NodeID, envelope.UserIDs, envelope.Topic, len(clients))
+
+	for _, c := range clients {
+		if err := c.write(f); err != nil {
+			ws.Logger.Debugf("Deliver broadcast receivers=%v topic=%s error=%+v", envelope.UserIDs, envelope.Topic, err)
+		}
+	}
+}
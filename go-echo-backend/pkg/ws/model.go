@@ -23,6 +23,8 @@ var (
 	MessageTypeCancelTyping MessageType = "cancel_typing"
 	MessageTypeSeenMessage  MessageType = "seen_message"
 	MessageTypeChat         MessageType = "chat"
+	MessageTypeSubscribe    MessageType = "subscribe"
+	MessageTypeUnsubscribe  MessageType = "unsubscribe"
 )
 
 type Message struct {
@@ -34,6 +36,10 @@ type Message struct {
 
 type MessageData struct {
 	ChatRoomID string `json:"chat_room_id,omitempty"`
+
+	// Topic e.g. bulk_purchase_order:<id>, inquiry:<id> or notifications, LastSeq resumes after the last seen event
+	Topic   string `json:"topic,omitempty"`
+	LastSeq int64  `json:"last_seq,omitempty"`
 }
 
 type BroadcastChatMessage struct {
@@ -0,0 +1,195 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// Connections are refreshed by their node every presenceHeartbeat, a node which stopped refreshing is considered gone after presenceTTL
+const (
+	presenceHeartbeat = time.Second * 30
+	presenceTTL       = time.Second * 90
+)
+
+// PresenceConnection a websocket connection of a user on any node
+type PresenceConnection struct {
+	UserID       string
+	ConnectionID string
+}
+
+func (c PresenceConnection) String() string {
+	return fmt.Sprintf("%s%s%s", c.UserID, separator, c.ConnectionID)
+}
+
+func ParsePresenceConnection(member string) (PresenceConnection, bool) {
+	var parts = strings.SplitN(member, separator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return PresenceConnection{}, false
+	}
+
+	return PresenceConnection{UserID: parts[0], ConnectionID: parts[1]}, true
+}
+
+// Presence registry of the connections of every node, shared through redis.
+// A user is online while at least one of their connections has not expired.
+type Presence struct {
+	redis     redis.UniversalClient
+	namespace string
+}
+
+func NewPresence(client redis.UniversalClient, namespace string) *Presence {
+	if namespace == "" {
+		namespace = "inflow"
+	}
+	return &Presence{
+		redis:     client,
+		namespace: namespace,
+	}
+}
+
+// connectionsKey all connections, scored by expiry, used to sweep connections of dead nodes
+func (p *Presence) connectionsKey() string {
+	return fmt.Sprintf("%s_ws_presence", p.namespace)
+}
+
+// userKey connections of the user, scored by expiry
+func (p *Presence) userKey(userID string) string {
+	return fmt.Sprintf("%s_ws_presence_%s", p.namespace, userID)
+}
+
+// Join registers the connection, cameOnline is true for the first live connection of the user
+func (p *Presence) Join(ctx context.Context, conn PresenceConnection) (cameOnline bool, err error) {
+	var now = time.Now()
+	var expiry = float64(now.Add(presenceTTL).Unix())
+
+	var pipe = p.redis.Pipeline()
+	pipe.ZAdd(ctx, p.connectionsKey(), redis.Z{Score: expiry, Member: conn.String()})
+	pipe.ZRemRangeByScore(ctx, p.userKey(conn.UserID), "-inf", strconv.FormatInt(now.Unix(), 10))
+	pipe.ZAdd(ctx, p.userKey(conn.UserID), redis.Z{Score: expiry, Member: conn.ConnectionID})
+	pipe.Expire(ctx, p.userKey(conn.UserID), presenceTTL)
+	var count = pipe.ZCard(ctx, p.userKey(conn.UserID))
+	if _, err = pipe.Exec(ctx); err != nil {
+		return false, eris.Wrapf(err, "join presence user=%s", conn.UserID)
+	}
+
+	return count.Val() == 1, nil
+}
+
+// Leave unregisters the connection, wentOffline is true when it was the last live connection of the user
+func (p *Presence) Leave(ctx context.Context, conn PresenceConnection) (wentOffline bool, err error) {
+	removed, remaining, err := p.leave(ctx, conn)
+	if err != nil {
+		return false, err
+	}
+
+	return removed && remaining == 0, nil
+}
+
+func (p *Presence) leave(ctx context.Context, conn PresenceConnection) (removed bool, remaining int64, err error) {
+	var pipe = p.redis.Pipeline()
+	pipe.ZRem(ctx, p.connectionsKey(), conn.String())
+	var removedCmd = pipe.ZRem(ctx, p.userKey(conn.UserID), conn.ConnectionID)
+	pipe.ZRemRangeByScore(ctx, p.userKey(conn.UserID), "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+	var count = pipe.ZCard(ctx, p.userKey(conn.UserID))
+	if _, err = pipe.Exec(ctx); err != nil {
+		return false, 0, eris.Wrapf(err, "leave presence user=%s", conn.UserID)
+	}
+
+	return removedCmd.Val() > 0, count.Val(), nil
+}
+
+// Refresh extends the expiry of connections which are still open
+func (p *Presence) Refresh(ctx context.Context, conns ...PresenceConnection) error {
+	if len(conns) == 0 {
+		return nil
+	}
+
+	var expiry = float64(time.Now().Add(presenceTTL).Unix())
+	var pipe = p.redis.Pipeline()
+	for _, conn := range conns {
+		pipe.ZAdd(ctx, p.connectionsKey(), redis.Z{Score: expiry, Member: conn.String()})
+		pipe.ZAdd(ctx, p.userKey(conn.UserID), redis.Z{Score: expiry, Member: conn.ConnectionID})
+		pipe.Expire(ctx, p.userKey(conn.UserID), presenceTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return eris.Wrap(err, "refresh presence")
+	}
+
+	return nil
+}
+
+// Sweep removes expired connections left by nodes which stopped without leaving,
+// returning the users who have no live connection anymore
+func (p *Presence) Sweep(ctx context.Context) (offlineUserIDs []string, err error) {
+	members, err := p.redis.ZRangeByScore(ctx, p.connectionsKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "sweep presence")
+	}
+
+	for _, member := range members {
+		// Only the node that removes the member reports the user, others see 0
+		removed, err := p.redis.ZRem(ctx, p.connectionsKey(), member).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+
+		conn, ok := ParsePresenceConnection(member)
+		if !ok {
+			continue
+		}
+
+		// The user key may have expired already, so the remaining count decides
+		_, remaining, err := p.leave(ctx, conn)
+		if err != nil {
+			return offlineUserIDs, err
+		}
+		if remaining == 0 {
+			offlineUserIDs = append(offlineUserIDs, conn.UserID)
+		}
+	}
+
+	return offlineUserIDs, nil
+}
+
+// GetOnlineUsers online status of the users on any node
+func (p *Presence) GetOnlineUsers(ctx context.Context, userIDs ...string) (map[string]bool, error) {
+	var result = make(map[string]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var now = strconv.FormatInt(time.Now().Unix(), 10)
+	var pipe = p.redis.Pipeline()
+	var counts = make([]*redis.IntCmd, len(userIDs))
+	for i, userID := range userIDs {
+		counts[i] = pipe.ZCount(ctx, p.userKey(userID), "("+now, "+inf")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, eris.Wrap(err, "get online users")
+	}
+
+	for i, userID := range userIDs {
+		result[userID] = counts[i].Val() > 0
+	}
+
+	return result, nil
+}
+
+// IsOnline whether the user has a live connection on any node
+func (p *Presence) IsOnline(ctx context.Context, userID string) (bool, error) {
+	result, err := p.GetOnlineUsers(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return result[userID], nil
+}
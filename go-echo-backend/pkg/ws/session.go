@@ -10,6 +10,9 @@ const UserSessionIDContextKey = "user_session_id"
 const separator = "__"
 const UserConnectedAtKey = "user_connected_at"
 
+// UserConnectionIDContextKey unique id of the connection across nodes, a user may have several connections from the same address
+const UserConnectionIDContextKey = "user_connection_id"
+
 // Session session
 type Session struct {
 	UserID        string      `json:"user_id"`
@@ -0,0 +1,211 @@
+package ws
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+	"github.com/samber/lo"
+)
+
+const (
+	sseHeartbeat  = time.Second * 15
+	sseBufferSize = 1024
+)
+
+var errSSEClientClosed = eris.New("sse client closed")
+
+// sseClient a server-sent events stream, for clients behind proxies which drop websockets
+type sseClient struct {
+	session       *Session
+	conn          PresenceConnection
+	subscriptions *topicSubscriptions
+	frames        chan *frame
+	done          chan struct{}
+	closeOnce     sync.Once
+
+	// cursor last seq sent for each topic, it is the id of every event so Last-Event-ID resumes all topics at once
+	cursor map[string]int64
+}
+
+func (c *sseClient) getUserID() string {
+	return c.session.UserID
+}
+
+func (c *sseClient) getSubscriptions() *topicSubscriptions {
+	return c.subscriptions
+}
+
+func (c *sseClient) getPresenceConnection() (PresenceConnection, bool) {
+	return c.conn, true
+}
+
+// write queues the frame, a client too slow to drain its buffer is closed and resumes when it reconnects
+func (c *sseClient) write(f *frame) error {
+	select {
+	case <-c.done:
+		return errSSEClientClosed
+	case c.frames <- f:
+		return nil
+	default:
+		c.close()
+		return eris.Wrapf(errSSEClientClosed, "buffer full user=%s", c.session.UserID)
+	}
+}
+
+func (c *sseClient) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// handleSSE streams the same payloads as /ws: chat messages sent to the user, their notifications and the topics
+// passed in the topics query param. Each event id is a cursor of the topics, browsers send it back as Last-Event-ID
+// when they reconnect and the missed events are replayed.
+func (ws *WS) handleSSE(c echo.Context) error {
+	var token = c.QueryParam("token")
+	var claims models.JwtClaims
+	var err = claims.ValidateToken(ws.app.Config.JWTSecret, token)
+	if err != nil {
+		ws.Logger.ErrorAny(err)
+		return err
+	}
+
+	var sess = &Session{
+		UserID:        claims.ID,
+		Role:          claims.Audience,
+		RemoteAddress: c.RealIP(),
+	}
+
+	var lastEventID = c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+	var cursor = ParseSSECursor(lastEventID)
+
+	var client = &sseClient{
+		session:       sess,
+		conn:          ws.newPresenceConnection(sess.UserID),
+		subscriptions: &topicSubscriptions{topics: map[string]bool{}},
+		frames:        make(chan *frame, sseBufferSize),
+		done:          make(chan struct{}),
+		cursor:        map[string]int64{},
+	}
+
+	var res = c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ws.sseMu.Lock()
+	ws.sseClients[client.conn.ConnectionID] = client
+	ws.sseMu.Unlock()
+	ws.join(client.conn)
+
+	defer func() {
+		client.close()
+		ws.sseMu.Lock()
+		delete(ws.sseClients, client.conn.ConnectionID)
+		ws.sseMu.Unlock()
+		ws.leave(client.conn)
+	}()
+
+	var topics = []string{string(enums.TopicKindInbox), string(enums.TopicKindNotifications)}
+	for _, value := range c.QueryParams()["topics"] {
+		topics = append(topics, strings.Split(value, ",")...)
+	}
+	for _, value := range lo.Uniq(lo.Compact(topics)) {
+		var lastSeq int64
+		var resume bool
+		if topic, err := models.ParseTopic(value, sess.UserID); err == nil {
+			lastSeq, resume = cursor[topic.String()]
+		}
+		ws.subscribeTopic(client, sess, value, lastSeq, resume)
+	}
+
+	var ticker = time.NewTicker(sseHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+
+		case <-client.done:
+			return nil
+
+		case f := <-client.frames:
+			if f.Topic != "" && f.Seq > client.cursor[f.Topic] {
+				client.cursor[f.Topic] = f.Seq
+			}
+			if _, err := res.Write(FormatSSEEvent(EncodeSSECursor(client.cursor), f.Message)); err != nil {
+				return nil
+			}
+			res.Flush()
+
+		case <-ticker.C:
+			// Comment lines keep proxies from closing an idle stream
+			if _, err := res.Write([]byte(": heartbeat\n\n")); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// FormatSSEEvent event in the text/event-stream format
+func FormatSSEEvent(id string, data []byte) []byte {
+	var buf bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", id)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
+// EncodeSSECursor the last seq of each topic, as topic=seq pairs sorted by topic
+func EncodeSSECursor(cursor map[string]int64) string {
+	var topics = lo.Keys(cursor)
+	sort.Strings(topics)
+
+	var parts = make([]string, 0, len(topics))
+	for _, topic := range topics {
+		parts = append(parts, fmt.Sprintf("%s=%d", topic, cursor[topic]))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// ParseSSECursor reads the cursor of a Last-Event-ID, malformed pairs are skipped
+func ParseSSECursor(value string) map[string]int64 {
+	var cursor = map[string]int64{}
+	for _, part := range strings.Split(value, ";") {
+		topic, seq, found := strings.Cut(part, "=")
+		if !found || topic == "" {
+			continue
+		}
+		if n, err := strconv.ParseInt(seq, 10, 64); err == nil && n >= 0 {
+			cursor[topic] = n
+		}
+	}
+
+	return cursor
+}
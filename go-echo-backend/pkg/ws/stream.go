@@ -0,0 +1,160 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// The last streamMaxEvents events of a topic are kept for streamRetention so reconnecting clients can catch up,
+// the sequence expires with them and a client resuming from a lost sequence is sent a reset
+const (
+	streamMaxEvents = 200
+	streamRetention = time.Hour * 24
+)
+
+// appendScript numbers the event, stores it and publishes it in one step so the events of a topic are published in sequence order
+var appendScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+local event = ARGV[1] .. seq .. ARGV[2]
+redis.call('ZADD', KEYS[2], seq, event)
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -tonumber(ARGV[6]) - 1)
+redis.call('EXPIRE', KEYS[2], ARGV[7])
+redis.call('EXPIRE', KEYS[1], ARGV[7])
+redis.call('PUBLISH', ARGV[5], ARGV[3] .. event .. ARGV[4])
+return seq
+`)
+
+// Stream per topic sequence of events shared by every node
+type Stream struct {
+	redis     redis.UniversalClient
+	namespace string
+	channel   string
+}
+
+func NewStream(client redis.UniversalClient, namespace string) *Stream {
+	if namespace == "" {
+		namespace = "inflow"
+	}
+	return &Stream{
+		redis:     client,
+		namespace: namespace,
+		channel:   getBroadcastChannel(namespace),
+	}
+}
+
+// The hash tag keeps both keys of a topic on the same cluster slot
+func (s *Stream) seqKey(topic string) string {
+	return fmt.Sprintf("%s_ws_topic_{%s}_seq", s.namespace, topic)
+}
+
+func (s *Stream) eventsKey(topic string) string {
+	return fmt.Sprintf("%s_ws_topic_{%s}_events", s.namespace, topic)
+}
+
+// Append numbers the event and publishes it to the subscribers on every node
+func (s *Stream) Append(ctx context.Context, nodeID string, event models.TopicEvent) (int64, error) {
+	var message = TopicMessage{
+		Type:      TopicMessageTypeEvent,
+		Topic:     event.Topic,
+		Event:     event.Event,
+		Data:      event.Data,
+		CreatedAt: time.Now().Unix(),
+	}
+	data, err := json.Marshal(&message)
+	if err != nil {
+		return 0, eris.Wrap(err, "marshal topic event")
+	}
+	nodeIDJSON, _ := json.Marshal(nodeID)
+	topicJSON, _ := json.Marshal(event.Topic)
+
+	// seq is omitted while empty, the script appends it as the last field
+	var eventPrefix = string(data[:len(data)-1]) + `,"seq":`
+	var envelopePrefix = fmt.Sprintf(`{"node_id":%s,"topic":%s,"data":`, nodeIDJSON, topicJSON)
+
+	seq, err := appendScript.Run(ctx, s.redis,
+		[]string{s.seqKey(event.Topic), s.eventsKey(event.Topic)},
+		eventPrefix, "}", envelopePrefix, "}", s.channel, streamMaxEvents, int64(streamRetention.Seconds()),
+	).Int64()
+	if err != nil {
+		return 0, eris.Wrapf(err, "append topic event topic=%s", event.Topic)
+	}
+
+	return seq, nil
+}
+
+// GetEventsSince events of the topic after lastSeq, complete is false when some of them are no longer retained
+func (s *Stream) GetEventsSince(ctx context.Context, topic string, lastSeq int64) (events []json.RawMessage, currentSeq int64, complete bool, err error) {
+	var pipe = s.redis.Pipeline()
+	var seqCmd = pipe.Get(ctx, s.seqKey(topic))
+	var eventsCmd = pipe.ZRangeByScoreWithScores(ctx, s.eventsKey(topic), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(lastSeq, 10),
+		Max: "+inf",
+	})
+	if _, err = pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, false, eris.Wrapf(err, "get topic events topic=%s", topic)
+	}
+
+	currentSeq, _ = seqCmd.Int64()
+
+	var oldestSeq int64
+	for i, item := range eventsCmd.Val() {
+		if i == 0 {
+			oldestSeq = int64(item.Score)
+		}
+		if member, ok := item.Member.(string); ok {
+			events = append(events, json.RawMessage(member))
+		}
+	}
+
+	return events, currentSeq, CheckResume(lastSeq, currentSeq, oldestSeq), nil
+}
+
+// CheckResume whether the retained events, starting at oldestSeq (0 when none), cover everything after lastSeq
+func CheckResume(lastSeq, currentSeq, oldestSeq int64) bool {
+	if lastSeq > currentSeq {
+		// The sequence was lost, the client is ahead of the server
+		return false
+	}
+	if lastSeq == currentSeq {
+		return true
+	}
+
+	return oldestSeq != 0 && oldestSeq <= lastSeq+1
+}
+
+type TopicMessageType string
+
+var (
+	TopicMessageTypeEvent          TopicMessageType = "topic_event"
+	TopicMessageTypeSubscribed     TopicMessageType = "subscribed"
+	TopicMessageTypeUnsubscribed   TopicMessageType = "unsubscribed"
+	TopicMessageTypeResyncRequired TopicMessageType = "resync_required"
+	TopicMessageTypeError          TopicMessageType = "subscribe_error"
+)
+
+// TopicMessage frame sent to the subscribers of a topic.
+// Events of a topic are numbered by seq, clients drop events with a seq they have already seen
+// and send the last one when subscribing again to receive what they missed.
+type TopicMessage struct {
+	Type      TopicMessageType     `json:"type"`
+	Topic     string               `json:"topic"`
+	Event     enums.TopicEventType `json:"event,omitempty"`
+	Data      json.RawMessage      `json:"data,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	CreatedAt int64                `json:"created_at,omitempty"`
+	Seq       int64                `json:"seq,omitempty"`
+}
+
+func (m *TopicMessage) ToJSONRaw() json.RawMessage {
+	data, _ := json.Marshal(m)
+
+	return data
+}
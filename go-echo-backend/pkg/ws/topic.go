@@ -0,0 +1,177 @@
+package ws
+
+import (
+	"context"
+	"sync"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+)
+
+// UserTopicsContextKey topics the session is subscribed to
+const UserTopicsContextKey = "user_topics"
+
+const maxTopicSubscriptions = 200
+
+type topicSubscriptions struct {
+	mu     sync.RWMutex
+	topics map[string]bool
+}
+
+// newTopicSubscriptions every connection receives the payloads sent to its user
+func newTopicSubscriptions(userID string) *topicSubscriptions {
+	return &topicSubscriptions{topics: map[string]bool{
+		models.NewTopic(enums.TopicKindInbox, userID).String(): true,
+	}}
+}
+
+func (t *topicSubscriptions) has(topic string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.topics[topic]
+}
+
+func (t *topicSubscriptions) add(topic string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.topics[topic] && len(t.topics) >= maxTopicSubscriptions {
+		return false
+	}
+	t.topics[topic] = true
+
+	return true
+}
+
+func (t *topicSubscriptions) remove(topic string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.topics, topic)
+}
+
+// PublishTopicEvent pushes the event to the clients subscribed to its topic on any node
+func (ws *WS) PublishTopicEvent(ctx context.Context, event models.TopicEvent) (int64, error) {
+	return ws.Stream.Append(ctx, ws.nodeID, event)
+}
+
+// subscribeTopic registers the client before replaying the events after lastSeq, so an event published in between may be sent twice but never lost
+func (ws *WS) subscribeTopic(c client, sess *Session, value string, lastSeq int64, resume bool) {
+	topic, err := models.ParseTopic(value, sess.UserID)
+	if err != nil {
+		ws.sendTopicError(c, value, err)
+		return
+	}
+
+	err = ws.authorizeTopic(sess, topic)
+	if err != nil {
+		ws.sendTopicError(c, topic.String(), err)
+		return
+	}
+
+	var subscriptions = c.getSubscriptions()
+	if subscriptions == nil || !subscriptions.add(topic.String()) {
+		ws.sendTopicError(c, topic.String(), errs.ErrTopicLimit)
+		return
+	}
+
+	events, currentSeq, complete, err := ws.Stream.GetEventsSince(context.Background(), topic.String(), lastSeq)
+	if err != nil {
+		ws.Logger.ErrorAny(err)
+		complete = false
+	}
+
+	var ack = TopicMessage{Type: TopicMessageTypeSubscribed, Topic: topic.String(), Seq: currentSeq}
+	if resume && !complete {
+		// Too far behind, the client reloads the resource and continues from the current seq
+		ack.Type = TopicMessageTypeResyncRequired
+		events = nil
+	}
+
+	// When resuming, the cursor of the client advances with the replayed events rather than jumping to the current seq
+	var ackFrame = frame{Topic: ack.Topic, Seq: ack.Seq, Message: ack.ToJSONRaw()}
+	if resume && complete {
+		ackFrame.Seq = lastSeq
+	}
+	if err := c.write(&ackFrame); err != nil {
+		ws.Logger.Debugf("Send topic ack topic=%s err=%+v", topic, err)
+		return
+	}
+
+	if !resume {
+		return
+	}
+
+	for _, event := range events {
+		f, err := newTopicFrame(event)
+		if err != nil {
+			ws.Logger.ErrorAny(err)
+			continue
+		}
+		if err := c.write(f); err != nil {
+			ws.Logger.Debugf("Replay topic event topic=%s err=%+v", topic, err)
+			return
+		}
+	}
+}
+
+func (ws *WS) unsubscribeTopic(c client, sess *Session, value string) {
+	topic, err := models.ParseTopic(value, sess.UserID)
+	if err != nil {
+		ws.sendTopicError(c, value, err)
+		return
+	}
+
+	if subscriptions := c.getSubscriptions(); subscriptions != nil {
+		subscriptions.remove(topic.String())
+	}
+
+	var ack = TopicMessage{Type: TopicMessageTypeUnsubscribed, Topic: topic.String()}
+	_ = c.write(&frame{Message: ack.ToJSONRaw()})
+}
+
+func (ws *WS) sendTopicError(c client, topic string, err error) {
+	var message = TopicMessage{Type: TopicMessageTypeError, Topic: topic, Error: err.Error()}
+	if appErr, ok := eris.Cause(err).(*errs.Error); ok {
+		message.Error = appErr.Message
+	}
+
+	_ = c.write(&frame{Message: message.ToJSONRaw()})
+}
+
+// authorizeTopic admins can follow everything, buyers and sellers only the orders they are party to and their own notifications
+func (ws *WS) authorizeTopic(sess *Session, topic models.Topic) error {
+	var role = enums.Role(sess.Role)
+	if topic.Kind == enums.TopicKindNotifications || topic.Kind == enums.TopicKindInbox {
+		if topic.ID != sess.UserID {
+			return errs.ErrTopicForbidden
+		}
+		return nil
+	}
+
+	if role.IsAdmin() {
+		return nil
+	}
+
+	var count int64
+	var err error
+	switch topic.Kind {
+	case enums.TopicKindInquiry:
+		err = ws.app.DB.Model(&models.Inquiry{}).Where("id = ? AND user_id = ?", topic.ID, sess.UserID).Count(&count).Error
+	case enums.TopicKindPurchaseOrder:
+		err = ws.app.DB.Model(&models.PurchaseOrder{}).Where("id = ? AND (user_id = ? OR sample_maker_id = ?)", topic.ID, sess.UserID, sess.UserID).Count(&count).Error
+	case enums.TopicKindBulkPurchaseOrder:
+		err = ws.app.DB.Model(&models.BulkPurchaseOrder{}).Where("id = ? AND (user_id = ? OR seller_id = ?)", topic.ID, sess.UserID, sess.UserID).Count(&count).Error
+	}
+	if err != nil {
+		return eris.Wrapf(err, "authorize topic=%s", topic)
+	}
+	if count == 0 {
+		return errs.ErrTopicForbidden
+	}
+
+	return nil
+}
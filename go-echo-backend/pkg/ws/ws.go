@@ -1,11 +1,14 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/app"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
 	"github.com/engineeringinflow/inflow-backend/pkg/middlewares"
@@ -14,39 +17,79 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/hibiken/asynq"
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
 	"github.com/thaitanloi365/melody"
 )
 
 var instance *WS
 
-// WS ws
+// WS ws, broadcasts go through redis so they reach sessions held by any node
 type WS struct {
 	melody             *melody.Melody
 	app                *app.App
 	messageHandlerFunc func(msg Message) error
 	Logger             *logger.Logger
+	Presence           *Presence
+	Stream             *Stream
+
+	nodeID     string
+	channel    string
+	redis      redis.UniversalClient
+	cancel     context.CancelFunc
+	sseMu      sync.RWMutex
+	sseClients map[string]*sseClient
 }
 
 // New init
 func New(e *echo.Echo, app *app.App, messageHandlerFunc func(msg Message) error) *WS {
+	var redisClient = redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:    app.Config.RedisAddress,
+		Password: app.Config.RedisPassword,
+		DB:       app.Config.RedisDBMode,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+
 	instance = &WS{
 		melody:             melody.New(),
 		Logger:             logger.New("chat/queue"),
 		app:                app,
 		messageHandlerFunc: messageHandlerFunc,
+		Presence:           NewPresence(redisClient, app.Config.RedisNamespace),
+		Stream:             NewStream(redisClient, app.Config.RedisNamespace),
+		nodeID:             xid.New().String(),
+		channel:            getBroadcastChannel(app.Config.RedisNamespace),
+		redis:              redisClient,
+		cancel:             cancel,
+		sseClients:         map[string]*sseClient{},
 	}
 
 	e.GET("/ws", instance.handleRequest, middlewares.IsAuthorizedWithQueryToken(app.Config.JWTSecret))
 
+	e.GET("/sse", instance.handleSSE, middlewares.IsAuthorizedWithQueryToken(app.Config.JWTSecret))
+
 	instance.melody.HandleMessage(instance.handleMessage)
 
 	instance.melody.HandleConnect(instance.handleConnect)
 
 	instance.melody.HandleDisconnect(instance.handleDisconnect)
 
+	go instance.subscribe(ctx)
+
+	go instance.heartbeat(ctx)
+
 	return instance
 }
 
+// Close stops receiving broadcasts and closes the sessions of this node, which leave the presence registry
+func (ws *WS) Close() error {
+	var err = ws.melody.Close()
+
+	ws.cancel()
+
+	return err
+}
+
 // GetInstance get instance
 func GetInstance() *WS {
 	if instance == nil {
@@ -109,45 +152,18 @@ func (ws *WS) GetUserSessions(userIDs ...string) (result []*melody.Session) {
 	return
 }
 
+// BroadcastToUser sends the message to the user on whichever node holds their sessions
 func (ws *WS) BroadcastToUser(msg *Message) error {
-	var sessions = ws.GetUserSessions(msg.UserID)
-
-	ws.Logger.Debugf("Broadcast to team receiver=%v type=%s total_sessions=%d", msg.UserID, msg.Type, len(sessions))
-
-	if len(sessions) == 0 {
-		return nil
-	}
-
-	for _, sess := range sessions {
-		if v, found := sess.Get("user_id"); found {
-			if str, ok := v.(string); ok && str != "" {
-				var err = ws.melody.BroadcastMultiple(msg.ToJSONRaw(), []*melody.Session{sess})
-				if err != nil {
-					ws.Logger.Debugf("Broadcast to team receiver=%s type=%s total_sessions=%d error=%+v", msg.UserID, msg.Type, len(sessions), err)
-				}
-			}
-		}
-
-	}
+	ws.Logger.Debugf("Broadcast to user receiver=%v type=%s", msg.UserID, msg.Type)
 
-	return nil
+	return ws.publish([]string{msg.UserID}, msg.ToJSONRaw(), false)
 }
 
+// BroadcastToUsers sends the chat message to the participants on whichever node holds their sessions
 func (ws *WS) BroadcastToUsers(msg *BroadcastChatMessage) error {
-	var sessions = ws.GetUserSessions(msg.ParticipantIDs...)
-
-	if len(sessions) == 0 {
-		return nil
-	}
-
-	for _, sess := range sessions {
-		var err = ws.melody.BroadcastMultiple(models.WSMessagePayload{Type: msg.Type, Message: msg.Message, ChatRoom: msg.ChatRoom}.ToJSONRaw(), []*melody.Session{sess})
-		if err != nil {
-			ws.Logger.Debugf("Broadcast to team receiver=%s type=%s total_sessions=%d error=%+v", msg.Message.ReceiverID, msg.Message.MessageType, len(sessions), err)
-		}
-	}
+	var transient = msg.Type == enums.ChatMessageWsTypeTyping.String() || msg.Type == enums.ChatMessageWsTypeCancelTyping.String()
 
-	return nil
+	return ws.publish(msg.ParticipantIDs, models.WSMessagePayload{Type: msg.Type, Message: msg.Message, ChatRoom: msg.ChatRoom}.ToJSONRaw(), transient)
 }
 
 func (ws *WS) handleRequest(c echo.Context) error {
@@ -185,21 +201,13 @@ func (ws *WS) handleConnect(s *melody.Session) {
 	ws.Logger.Debugf("User connected: %s", sessionID)
 	if sessionID != "" {
 		if sess := sessionID.GetSession(); sess != nil {
-			s.Set(UserConnectedAtKey, sess.ConnectedAt)
+			var conn = ws.newPresenceConnection(sess.UserID)
+			s.Set(UserConnectedAtKey, time.Now().Unix())
 			s.Set(UserSessionIDContextKey, sessionID.String())
+			s.Set(UserConnectionIDContextKey, conn.ConnectionID)
+			s.Set(UserTopicsContextKey, newTopicSubscriptions(sess.UserID))
 
-			worker.GetInstance().Client.EnqueueContext(
-				s.Request.Context(),
-				asynq.NewTask("user_ping", helper.ToJson(map[string]interface{}{
-					"user_id":        sess.UserID,
-					"is_offline":     false,
-					"last_online_at": time.Now().Unix(),
-				})),
-				worker.QueueLow,
-				asynq.MaxRetry(1),
-				asynq.Retention(time.Hour*4),
-			)
-
+			ws.join(conn)
 		}
 
 	}
@@ -214,27 +222,107 @@ func (ws *WS) handleDisconnect(s *melody.Session) {
 		return
 	}
 
-	if str, ok := sessionID.(string); ok {
-		if sess := SessionID(str).GetSession(); sess != nil {
-			s.Set(UserConnectedAtKey, sess.ConnectedAt)
-			s.Set(UserSessionIDContextKey, sessionID)
-
-			_, _ = worker.GetInstance().Client.EnqueueContext(
-				s.Request.Context(),
-				asynq.NewTask("user_ping", helper.ToJson(map[string]interface{}{
-					"user_id":    sess.UserID,
-					"is_offline": true,
-				})),
-				worker.QueueLow,
-				asynq.MaxRetry(1),
-				asynq.Retention(time.Hour*4),
-			)
+	if conn, ok := getPresenceConnection(s); ok {
+		ws.leave(conn)
+	}
+
+	ws.Logger.Debugf("%s is disconnected", sessionID)
+
+}
+
+// heartbeat keeps the connections of this node alive in the presence registry and marks offline the users left behind by nodes which stopped
+func (ws *WS) heartbeat(ctx context.Context) {
+	var ticker = time.NewTicker(presenceHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var conns []PresenceConnection
+			for _, c := range ws.getClients() {
+				if conn, ok := c.getPresenceConnection(); ok {
+					conns = append(conns, conn)
+				}
+			}
+
+			if err := ws.Presence.Refresh(ctx, conns...); err != nil {
+				ws.Logger.ErrorAny(err)
+			}
 
+			offlineUserIDs, err := ws.Presence.Sweep(ctx)
+			if err != nil {
+				ws.Logger.ErrorAny(err)
+			}
+			for _, userID := range offlineUserIDs {
+				ws.dispatchUserPing(userID, true)
+			}
 		}
 	}
+}
 
-	ws.Logger.Debugf("%s is disconnected", sessionID)
+func (ws *WS) newPresenceConnection(userID string) PresenceConnection {
+	return PresenceConnection{UserID: userID, ConnectionID: fmt.Sprintf("%s%s%s", ws.nodeID, separator, xid.New().String())}
+}
+
+func (ws *WS) join(conn PresenceConnection) {
+	cameOnline, err := ws.Presence.Join(context.Background(), conn)
+	if err != nil {
+		ws.Logger.ErrorAny(err)
+	}
+	if cameOnline {
+		ws.dispatchUserPing(conn.UserID, false)
+	}
+}
+
+func (ws *WS) leave(conn PresenceConnection) {
+	wentOffline, err := ws.Presence.Leave(context.Background(), conn)
+	if err != nil {
+		ws.Logger.ErrorAny(err)
+	}
+	if wentOffline {
+		ws.dispatchUserPing(conn.UserID, true)
+	}
+}
 
+// dispatchUserPing persists the online status of the user, only on presence transitions
+func (ws *WS) dispatchUserPing(userID string, isOffline bool) {
+	_, err := worker.GetInstance().Client.Enqueue(
+		asynq.NewTask("user_ping", helper.ToJson(map[string]interface{}{
+			"user_id":        userID,
+			"is_offline":     isOffline,
+			"last_online_at": time.Now().Unix(),
+		})),
+		worker.QueueLow,
+		asynq.MaxRetry(1),
+		asynq.Retention(time.Hour*4),
+	)
+	if err != nil {
+		ws.Logger.ErrorAny(err, fmt.Sprintf("User %s", userID))
+	}
+}
+
+func getPresenceConnection(s *melody.Session) (PresenceConnection, bool) {
+	sessionID, found := s.Get(UserSessionIDContextKey)
+	if !found {
+		return PresenceConnection{}, false
+	}
+	connectionID, found := s.Get(UserConnectionIDContextKey)
+	if !found {
+		return PresenceConnection{}, false
+	}
+
+	str, ok := sessionID.(string)
+	if !ok {
+		return PresenceConnection{}, false
+	}
+	connectionIDStr, ok := connectionID.(string)
+	if !ok {
+		return PresenceConnection{}, false
+	}
+
+	return PresenceConnection{UserID: SessionID(str).GetSession().UserID, ConnectionID: connectionIDStr}, true
 }
 
 func (ws *WS) handleMessage(s *melody.Session, msg []byte) {
@@ -258,6 +346,26 @@ func (ws *WS) handleMessage(s *melody.Session, msg []byte) {
 		}
 		message.UserID = sess.UserID
 		message.Role = enums.Role(sess.Role)
+		switch message.Type {
+		case MessageTypePing:
+			if conn, ok := getPresenceConnection(s); ok {
+				if err := ws.Presence.Refresh(context.Background(), conn); err != nil {
+					ws.Logger.ErrorAny(err)
+				}
+			}
+		case MessageTypeSubscribe, MessageTypeUnsubscribe:
+			var c = &wsClient{session: s}
+			if message.Data == nil {
+				ws.sendTopicError(c, "", errs.ErrTopicInvalid)
+				return
+			}
+			if message.Type == MessageTypeSubscribe {
+				ws.subscribeTopic(c, sess, message.Data.Topic, message.Data.LastSeq, message.Data.LastSeq > 0)
+			} else {
+				ws.unsubscribeTopic(c, sess, message.Data.Topic)
+			}
+			return
+		}
 		err = ws.messageHandlerFunc(message)
 		if err != nil {
 			ws.Logger.Debugf("handle message msg=%s err=%+v", msg, err)
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// GetBankStatements
+// @Tags Admin-BankStatement
+// @Summary Bank statements
+// @Description Uploaded bank statements
+// @Accept  json
+// @Produce  json
+// @Param date_from query int false "Uploaded from"
+// @Param date_to query int false "Uploaded to"
+// @Success 200 {object} []models.BankStatement
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements [get]
+func GetBankStatements(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetBankStatementsParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewBankReconciliationRepo(cc.App.DB).GetBankStatements(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// ImportBankStatement
+// @Tags Admin-BankStatement
+// @Summary Import bank statement
+// @Description Upload a CSV, MT940 or CAMT.053 statement, incoming lines are auto-matched against pending bank transfers
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "Statement file"
+// @Param format formData string false "csv, mt940 or camt053, detected from the content when empty"
+// @Success 200 {object} models.BankStatement
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements/import [post]
+func ImportBankStatement(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.ImportBankStatementParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.File, err = cc.FormFile("file")
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewBankReconciliationRepo(cc.App.DB).ImportBankStatement(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// MatchBankStatement
+// @Tags Admin-BankStatement
+// @Summary Re-run matching
+// @Description Match unmatched lines again, e.g. after buyers submitted new bank transfers
+// @Accept  json
+// @Produce  json
+// @Param bank_statement_id path string true "Bank statement ID"
+// @Success 200 {object} []models.BankStatementLine
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements/{bank_statement_id}/match [post]
+func MatchBankStatement(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.MatchBankStatementParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewBankReconciliationRepo(cc.App.DB).MatchBankStatement(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// PaginateBankStatementLines
+// @Tags Admin-BankStatement
+// @Summary Bank statement lines
+// @Description Review queue, filter statuses by matched and suggested for lines waiting for approval
+// @Accept  json
+// @Produce  json
+// @Param bank_statement_id query string false "Bank statement ID"
+// @Param statuses query []string false "unmatched, matched, suggested, reconciled or ignored"
+// @Param currency query string false "Currency"
+// @Param page query int false "Page index"
+// @Param limit query int false "Size of page"
+// @Success 200 {object} query.Pagination{Records=[]models.BankStatementLine}
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements/lines [get]
+func PaginateBankStatementLines(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.PaginateBankStatementLinesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	var result = repo.NewBankReconciliationRepo(cc.App.DB).PaginateBankStatementLines(params)
+	return cc.Success(result)
+}
+
+// ApproveBankStatementLine
+// @Tags Admin-BankStatement
+// @Summary Approve bank statement match
+// @Description Confirm the matched bank transfer the same way as marking the order paid, payment_transaction_id overrides the suggestion
+// @Accept  json
+// @Produce  json
+// @Param bank_statement_line_id path string true "Bank statement line ID"
+// @Param data body repo.BankStatementLineParams false "Form"
+// @Success 200 {object} models.BankStatementLine
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements/lines/{bank_statement_line_id}/approve [put]
+func ApproveBankStatementLine(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.BankStatementLineParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	var reconciliationRepo = repo.NewBankReconciliationRepo(cc.App.DB)
+	line, err := reconciliationRepo.GetApprovableBankStatementLine(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	// The transfer is already paid when a previous approval failed to reconcile the line
+	if line.PaymentTransaction.Status == enums.PaymentStatusWaitingConfirm {
+		err = confirmBankTransfer(cc, claims, line.PaymentTransaction, params.Note)
+		if err != nil {
+			return eris.Wrap(err, err.Error())
+		}
+	}
+
+	result, err := reconciliationRepo.MarkBankStatementLineReconciled(params, line)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// IgnoreBankStatementLine
+// @Tags Admin-BankStatement
+// @Summary Ignore bank statement line
+// @Description Take a line unrelated to orders out of the review queue
+// @Accept  json
+// @Produce  json
+// @Param bank_statement_line_id path string true "Bank statement line ID"
+// @Param data body repo.BankStatementLineParams false "Form"
+// @Success 200 {object} models.BankStatementLine
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bank_statements/lines/{bank_statement_line_id}/ignore [put]
+func IgnoreBankStatementLine(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.BankStatementLineParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewBankReconciliationRepo(cc.App.DB).IgnoreBankStatementLine(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// confirmBankTransfer runs the same confirmation as the manual approve/mark as paid actions for the kind of order the transfer pays
+func confirmBankTransfer(cc *models.CustomContext, claims models.JwtClaimsInfo, transaction *models.PaymentTransaction, note string) error {
+	// Multi orders checkout
+	if len(transaction.PurchaseOrderIDs) > 0 || len(transaction.BulkPurchaseOrderIDs) > 0 {
+		invoiceTask, err := tasks.CreatePaymentInvoiceTask.Outbox(cc.Request().Context(), tasks.CreatePaymentInvoicePayload{
+			PaymentTransactionID: transaction.ID,
+			ApprovedByUserID:     claims.GetUserID(),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.NewPaymentTransactionRepo(cc.App.DB).ApprovePaymentTransactions(repo.GetPaymentTransactionsParams{
+			JwtClaimsInfo:        claims,
+			PaymentTransactionID: transaction.ID,
+			Note:                 note,
+			OutboxTasks:          []models.OutboxTask{invoiceTask},
+		})
+		return err
+	}
+
+	if transaction.BulkPurchaseOrderID != "" {
+		var params = models.BulkPurchaseOrderMarkAsPaidParams{
+			JwtClaimsInfo:       claims,
+			BulkPurchaseOrderID: transaction.BulkPurchaseOrderID,
+			Milestone:           transaction.Milestone,
+			PaymentMilestoneID:  transaction.PaymentMilestoneID,
+		}
+		var milestone = params.Milestone
+		if milestone != enums.PaymentMilestoneFinalPayment {
+			milestone = enums.PaymentMilestoneFirstPayment
+		}
+		var outboxTask models.OutboxTask
+		var err error
+		if params.PaymentMilestoneID != "" {
+			outboxTask, err = tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(cc.Request().Context(), tasks.CreateBulkPoMilestoneInvoicePayload{
+				ApprovedByUserID:    claims.GetUserID(),
+				BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+				PaymentMilestoneID:  params.PaymentMilestoneID,
+			})
+		} else {
+			outboxTask, err = tasks.BulkPurchaseOrderBankTransferConfirmedTask.Outbox(cc.Request().Context(), tasks.BulkPurchaseOrderBankTransferConfirmedPayload{
+				ApprovedByUserID:    claims.GetUserID(),
+				BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+				Milestone:           milestone,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		params.OutboxTasks = []models.OutboxTask{outboxTask}
+		_, err = repo.NewBulkPurchaseOrderRepo(cc.App.DB).BulkPurchaseOrderMarkAsPaid(params)
+		return err
+	}
+
+	order, err := repo.NewPurchaseOrderRepo(cc.App.DB).GetPurchaseOrderShortInfo(transaction.PurchaseOrderID)
+	if err != nil {
+		return err
+	}
+
+	var purchaseOrders models.PurchaseOrders
+	if order.CheckoutSessionID != "" {
+		purchaseOrders, err = repo.NewPurchaseOrderRepo(cc.App.DB).MultiPurchaseOrderMarkAsPaid(repo.MultiPurchaseOrderParams{
+			CheckoutSessionID: order.CheckoutSessionID,
+			Note:              note,
+		})
+	} else {
+		var purchaseOrder *models.PurchaseOrder
+		purchaseOrder, err = repo.NewPurchaseOrderRepo(cc.App.DB).PurchaseOrderMarkAsPaid(models.PurchaseOrderIDParam{
+			JwtClaimsInfo:   claims,
+			PurchaseOrderID: order.ID,
+			Note:            note,
+			PurchaseOrder:   order,
+		})
+		purchaseOrders = models.PurchaseOrders{purchaseOrder}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, purchaseOrder := range purchaseOrders {
+		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(cc.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
+			ApprovedByUserID: claims.GetUserID(),
+			PurchaseOrderID:  purchaseOrder.ID,
+		})
+	}
+
+	return nil
+}
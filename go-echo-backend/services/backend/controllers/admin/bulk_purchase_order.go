@@ -116,11 +116,18 @@ func AdminUpdateBulkPurchaseOrder(c echo.Context) error {
 	}
 
 	if result.TrackingStatus == enums.BulkPoTrackingStatusWaitingForSubmitOrder {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: result.UserID,
 			Event:  customerio.EventBulkPoSubmitOrder,
 			Data:   result.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
+	}
+
+	// Waiting for the buyer's PO, plan the order from its TNA template
+	if result.TrackingStatus == enums.BulkPoTrackingStatusWaitingForSubmitOrder || result.Status == enums.BulkPurchaseOrderStatusWaitingForPo {
+		_, _ = tasks.InstantiateTNATemplateTask.Dispatch(c.Request().Context(), tasks.InstantiateTNATemplatePayload{
+			BulkPurchaseOrderID: result.ID,
+		})
 	}
 
 	return cc.Success(result)
@@ -236,13 +243,13 @@ func AdminSendQuotationToBuyer(c echo.Context) error {
 	}
 
 	if params.FirstPaymentPercentage > 0 {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: order.UserID,
 			Event:  customerio.EventBulkPoSubmitQuotation,
 			Data:   order.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 
-		tasks.CreateUserNotificationTask{
+		tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 			UserID:           order.UserID,
 			Message:          fmt.Sprintf("New quotation was created for %s", order.ReferenceID),
 			NotificationType: enums.UserNotificationTypeBulkPoSubmitQuotation,
@@ -253,7 +260,7 @@ func AdminSendQuotationToBuyer(c echo.Context) error {
 				InquiryID:                    order.Inquiry.ID,
 				InquiryReferenceID:           order.Inquiry.ReferenceID,
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Sent")
@@ -292,19 +299,19 @@ func AdminBulkPurchaseOrderCreateQcReport(c echo.Context) error {
 	}
 
 	if params.ApproveQCAt != nil {
-		_, _ = tasks.BulkPurchaseQCApproveTask{
+		_, _ = tasks.BulkPurchaseQCApproveTask.DispatchAt(cc.Request().Context(), tasks.BulkPurchaseQCApprovePayload{
 			JwtClaimsInfo:       claims,
 			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-		}.DispatchAt(time.Unix(*params.ApproveQCAt, 0), asynq.MaxRetry(0))
+		}, time.Unix(*params.ApproveQCAt, 0), asynq.MaxRetry(0))
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: order.UserID,
 		Event:  customerio.EventBulkPoCreateQcReport,
 		Data:   order.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           order.UserID,
 		Message:          fmt.Sprintf("New QC report was created for %s", order.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoCreateQcReport,
@@ -315,7 +322,7 @@ func AdminBulkPurchaseOrderCreateQcReport(c echo.Context) error {
 			InquiryID:                    order.Inquiry.ID,
 			InquiryReferenceID:           order.Inquiry.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(order)
 }
@@ -384,13 +391,13 @@ func AdminBulkPurchaseOrderUpdateRawMaterial(c echo.Context) error {
 	}
 
 	if params.ApproveRawMaterialAt != nil {
-		_, _ = tasks.BulkPurchaseOrderRawMaterialApproveTask{
+		_, _ = tasks.BulkPurchaseOrderRawMaterialApproveTask.DispatchAt(cc.Request().Context(), tasks.BulkPurchaseOrderRawMaterialApprovePayload{
 			JwtClaimsInfo:       claims,
 			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-		}.DispatchAt(time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
+		}, time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
 	}
 
-	_, _ = tasks.CreateUserNotificationTask{
+	_, _ = tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Raw material was updated for %s", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoUpdateRawMaterial,
@@ -411,7 +418,7 @@ func AdminBulkPurchaseOrderUpdateRawMaterial(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -483,13 +490,13 @@ func AdminBulkPurchaseOrderMarkProduction(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkProduction,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Bulk purchase order %s is on Production", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoMarkProduction,
@@ -510,7 +517,7 @@ func AdminBulkPurchaseOrderMarkProduction(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -548,13 +555,13 @@ func AdminBulkPurchaseOrderMarkRawMaterial(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkRawMaterial,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Bulk purchase order %s is on Raw Materials", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoMarkRawMaterial,
@@ -575,7 +582,7 @@ func AdminBulkPurchaseOrderMarkRawMaterial(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -613,13 +620,13 @@ func AdminBulkPurchaseOrderMarkPps(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkPps,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Bulk purchase order %s is on PPS", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoMarkPps,
@@ -640,7 +647,7 @@ func AdminBulkPurchaseOrderMarkPps(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -676,7 +683,7 @@ func AdminBulkPurchaseOrderUpdatePps(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Pre-Production info was updated for %s", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoUpdatePps,
@@ -697,13 +704,13 @@ func AdminBulkPurchaseOrderUpdatePps(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoUpdatePps,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 	return cc.Success(bulkPO)
 }
 
@@ -741,13 +748,13 @@ func AdminBulkPurchaseOrderMarkQc(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkQc,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Bulk purchase order %s is on QC", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoMarkQc,
@@ -768,13 +775,13 @@ func AdminBulkPurchaseOrderMarkQc(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkQc,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -810,15 +817,15 @@ func AdminBulkPurchaseOrderConfirmQcReport(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoConfirmQCReport,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateBulkPoAttachmentPDFsTask{
+	tasks.CreateBulkPoAttachmentPDFsTask.Dispatch(c.Request().Context(), tasks.CreateBulkPoAttachmentPDFsPayload{
 		BulkPurchaseOrderID: bulkPO.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -855,7 +862,7 @@ func AdminBulkPurchaseOrderMarkDelivering(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.UserID,
 		Message:          fmt.Sprintf("Bulk purchase order %s is on delivering", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoMarkDelivering,
@@ -876,13 +883,13 @@ func AdminBulkPurchaseOrderMarkDelivering(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.UserID,
 		Event:  customerio.EventBulkPoMarkDelivering,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -948,17 +955,20 @@ func AdminBulkPurchaseOrderMarkFirstPayment(c echo.Context) error {
 	}
 
 	params.JwtClaimsInfo = claims
+	confirmedTask, err := tasks.BulkPurchaseOrderBankTransferConfirmedTask.Outbox(cc.Request().Context(), tasks.BulkPurchaseOrderBankTransferConfirmedPayload{
+		ApprovedByUserID:    claims.GetUserID(),
+		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+		Milestone:           enums.PaymentMilestoneFirstPayment,
+	})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	params.OutboxTasks = []models.OutboxTask{confirmedTask}
 	bulkPO, err := repo.NewBulkPurchaseOrderRepo(cc.App.DB).BulkPurchaseOrderMarkFirstPayment(params)
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.BulkPurchaseOrderBankTransferConfirmedTask{
-		ApprovedByUserID:    claims.GetUserID(),
-		BulkPurchaseOrderID: bulkPO.ID,
-		Milestone:           enums.PaymentMilestoneFirstPayment,
-	}.Dispatch(cc.Request().Context())
-
 	return cc.Success(bulkPO)
 }
 
@@ -989,17 +999,20 @@ func AdminBulkPurchaseOrderMarkFinalPayment(c echo.Context) error {
 	}
 
 	params.JwtClaimsInfo = claims
+	confirmedTask, err := tasks.BulkPurchaseOrderBankTransferConfirmedTask.Outbox(cc.Request().Context(), tasks.BulkPurchaseOrderBankTransferConfirmedPayload{
+		ApprovedByUserID:    claims.GetUserID(),
+		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+		Milestone:           enums.PaymentMilestoneFinalPayment,
+	})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	params.OutboxTasks = []models.OutboxTask{confirmedTask}
 	bulkPO, err := repo.NewBulkPurchaseOrderRepo(cc.App.DB).BulkPurchaseOrderMarkFinalPayment(params)
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.BulkPurchaseOrderBankTransferConfirmedTask{
-		ApprovedByUserID:    claims.GetUserID(),
-		BulkPurchaseOrderID: bulkPO.ID,
-		Milestone:           enums.PaymentMilestoneFinalPayment,
-	}.Dispatch(cc.Request().Context())
-
 	return cc.Success(bulkPO)
 }
 
@@ -1030,6 +1043,28 @@ func AdminBulkPurchaseOrderMarkAsPaid(c echo.Context) error {
 	}
 
 	params.JwtClaimsInfo = claims
+	var milestone = params.Milestone
+	if milestone != enums.PaymentMilestoneFinalPayment {
+		milestone = enums.PaymentMilestoneFirstPayment
+	}
+	var outboxTask models.OutboxTask
+	if params.PaymentMilestoneID != "" {
+		outboxTask, err = tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(cc.Request().Context(), tasks.CreateBulkPoMilestoneInvoicePayload{
+			ApprovedByUserID:    claims.GetUserID(),
+			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+			PaymentMilestoneID:  params.PaymentMilestoneID,
+		})
+	} else {
+		outboxTask, err = tasks.BulkPurchaseOrderBankTransferConfirmedTask.Outbox(cc.Request().Context(), tasks.BulkPurchaseOrderBankTransferConfirmedPayload{
+			ApprovedByUserID:    claims.GetUserID(),
+			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+			Milestone:           milestone,
+		})
+	}
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	params.OutboxTasks = []models.OutboxTask{outboxTask}
 	_, err = repo.NewBulkPurchaseOrderRepo(cc.App.DB).BulkPurchaseOrderMarkAsPaid(params)
 	if err != nil {
 		return eris.Wrap(err, "")
@@ -1107,11 +1142,11 @@ func AdminBulkPurchaseOrderAssignPIC(c echo.Context) error {
 	}
 
 	for _, userID := range result.AssigneeIDs {
-		tasks.AssignBulkPurchaseOrderPICTask{
+		tasks.AssignBulkPurchaseOrderPICTask.Dispatch(c.Request().Context(), tasks.AssignBulkPurchaseOrderPICPayload{
 			AssignerID:          claims.GetUserID(),
 			AssigneeID:          userID,
 			BulkPurchaseOrderID: params.BulkPurchaseOrderID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 	return cc.Success(result)
 }
@@ -1325,13 +1360,13 @@ func AdminBulkPurchaseOrderAddNote(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.NewBulkPONotesTask{
+	tasks.NewBulkPONotesTask.Dispatch(c.Request().Context(), tasks.NewBulkPONotesPayload{
 		UserID:              claims.GetUserID(),
 		BulkPurchaseOrderID: params.TargetID,
 		MentionUserIDs:      params.MentionUserIDs,
 		Message:             params.Message,
 		Attachments:         params.Attachments,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -1531,7 +1566,7 @@ func AdminBulkPurchaseOrderStageCommentsCreate(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventBulkPoNewComment,
 		Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
@@ -1540,7 +1575,7 @@ func AdminBulkPurchaseOrderStageCommentsCreate(c echo.Context) error {
 				return params.Attachments.GenerateFileURL()
 			}(),
 		}),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -1635,30 +1670,30 @@ func AdminCreateMultipleBulks(c echo.Context) error {
 	}
 
 	for _, bulk := range bulks {
-		_, _ = tasks.CreateChatRoomTask{
+		_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 			UserID:              claims.GetUserID(),
 			Role:                claims.GetRole(),
 			BulkPurchaseOrderID: bulk.ID,
 			BuyerID:             bulk.UserID,
-		}.Dispatch(c.Request().Context())
+		})
 		if bulk.PurchaseOrder.ID != "" {
-			_, _ = tasks.CreateChatRoomTask{
+			_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 				UserID:          claims.GetUserID(),
 				Role:            claims.GetRole(),
 				PurchaseOrderID: bulk.PurchaseOrder.ID,
 				BuyerID:         bulk.UserID,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
-		_, _ = tasks.UpdateUserProductClassesTask{
+		_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 			UserID:          claims.GetUserID(),
 			PurchaseOrderID: bulk.ID,
-		}.Dispatch(c.Request().Context())
+		})
 
-		_, _ = tasks.UpdateUserProductClassesTask{
+		_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 			UserID:          claims.GetUserID(),
 			PurchaseOrderID: bulk.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulks)
@@ -1698,13 +1733,13 @@ func AdminSubmitMultipleBulkQuotations(c echo.Context) error {
 
 	for _, bulk := range bulks {
 		if *bulk.FirstPaymentPercentage > 0 {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: bulk.UserID,
 				Event:  customerio.EventBulkPoSubmitQuotation,
 				Data:   bulk.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 
-			tasks.CreateUserNotificationTask{
+			tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 				UserID:           bulk.UserID,
 				Message:          fmt.Sprintf("New quotation was created for %s", bulk.ReferenceID),
 				NotificationType: enums.UserNotificationTypeBulkPoSubmitQuotation,
@@ -1713,7 +1748,7 @@ func AdminSubmitMultipleBulkQuotations(c echo.Context) error {
 					BulkPurchaseOrderID:          bulk.ID,
 					BulkPurchaseOrderReferenceID: bulk.ReferenceID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
@@ -1754,3 +1789,25 @@ func AdminUploadBulkPurchaseOrders(c echo.Context) error {
 
 	return cc.Success(results)
 }
+
+// AdminGetBulkPurchaseOrderStateMachine
+// @Tags Admin-PO
+// @Summary Bulk purchase order tracking state machine
+// @Description Buyer and seller transition tables and their mermaid diagrams
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} repo.BulkPurchaseOrderTransition
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/state_machine [get]
+func AdminGetBulkPurchaseOrderStateMachine(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	return cc.Success(map[string]interface{}{
+		"transitions":        repo.BulkPurchaseOrderTransitions,
+		"diagram":            repo.BulkPurchaseOrderStateDiagram(),
+		"seller_transitions": repo.SellerBulkPurchaseOrderTransitions,
+		"seller_diagram":     repo.SellerBulkPurchaseOrderStateDiagram(),
+	})
+}
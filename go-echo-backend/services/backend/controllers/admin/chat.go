@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
 	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
 	"github.com/labstack/echo/v4"
@@ -40,9 +41,9 @@ func AdminCreateChatMessage(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, err = tasks.SendChatMessageTask{
+	_, err = tasks.SendChatMessageTask.Dispatch(cc.Request().Context(), tasks.SendChatMessagePayload{
 		ChatMessage: msg,
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -57,6 +58,9 @@ func AdminCreateChatMessage(c echo.Context) error {
 // @Param page query int false
 // @Param limit query int false
 // @Param room_id query string false
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param thread_id query string false "root message of the thread"
 // @Success 200 {object} query.Pagination{Records=[]models.ChatMessage}
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -84,6 +88,202 @@ func AdminGetChatMessageList(c echo.Context) error {
 	return cc.Success(messageList)
 }
 
+// @Tags Admin-Chat
+// @Summary Update chat message
+// @Description This API allows admin to edit their message within the edit window, the previous version is kept
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param data body models.UpdateChatMessageRequest true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/chat_messages/{chat_message_id} [put]
+func AdminUpdateChatMessage(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.UpdateChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).UpdateChatMessage(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeMessageUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Admin-Chat
+// @Summary Delete chat message
+// @Description This API allows admin to delete any message for everyone, the content is kept for disputes
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/chat_messages/{chat_message_id} [delete]
+func AdminDeleteChatMessage(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.DeleteChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).DeleteChatMessage(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeMessageDeleted,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Admin-Chat
+// @Summary Add chat message reaction
+// @Description This API allows admin to react to a message with an emoji
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param data body models.ReactChatMessageRequest true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/chat_messages/{chat_message_id}/reactions [put]
+func AdminAddChatMessageReaction(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.ReactChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).AddChatMessageReaction(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeReactionUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Admin-Chat
+// @Summary Remove chat message reaction
+// @Description This API allows admin to remove their emoji from a message
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param emoji query string true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/chat_messages/{chat_message_id}/reactions [delete]
+func AdminRemoveChatMessageReaction(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.ReactChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).RemoveChatMessageReaction(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeReactionUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Admin-Chat
+// @Summary Get chat message edits
+// @Description This API allows admin to review the previous versions of an edited or deleted message, for disputes
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Success 200 {object} []models.ChatMessageEdit
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/chat_messages/{chat_message_id}/edits [get]
+func AdminGetChatMessageEdits(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.GetChatMessageEditsRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	edits, err := repo.NewChatRepo(cc.App.DB).GetChatMessageEdits(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(edits)
+}
+
 // @Tags Admin-Chat
 // @Summary Get chat user relevant stage
 // @Description This API allows admin to retrieve chat user relevant stage
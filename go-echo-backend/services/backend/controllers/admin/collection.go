@@ -77,13 +77,13 @@ func AdminCreateCollection(c echo.Context) error {
 	}
 
 	if collection != nil {
-		_, _ = tasks.CreateSysNotificationTask{
+		_, _ = tasks.CreateSysNotificationTask.Dispatch(c.Request().Context(), tasks.CreateSysNotificationPayload{
 			SysNotification: models.SysNotification{
 				Name:    fmt.Sprintf("New Collection - %s", collection.Name),
 				Type:    enums.SysNotificationCreateCollectionType,
 				Message: fmt.Sprintf("New Collection - %s", collection.Name),
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(collection)
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// PaginateDeadLetterTasks
+// @Tags Admin-DeadLetter
+// @Summary Dead letter tasks
+// @Description Tasks archived after they exhausted their retries, newest first
+// @Accept  json
+// @Produce  json
+// @Param statuses query []string false "Statuses"
+// @Param task_name query string false "Task name"
+// @Param queue query string false "Queue"
+// @Param reference_type query string false "Reference type"
+// @Param reference_id query string false "Reference ID"
+// @Param failed_from query int false "Failed from"
+// @Param failed_to query int false "Failed to"
+// @Success 200 {object} query.Pagination
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks [get]
+func PaginateDeadLetterTasks(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.PaginateDeadLetterTasksParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	var result = repo.NewDeadLetterTaskRepo(cc.App.DB).PaginateDeadLetterTasks(params)
+
+	return cc.Success(result)
+}
+
+// GetDeadLetterTaskRate
+// @Tags Admin-DeadLetter
+// @Summary Dead letter rate
+// @Description Tasks archived in the alert window against the average window of the last day
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} worker.DeadLetterRate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/rate [get]
+func GetDeadLetterTaskRate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	result, err := worker.GetInstance().GetDeadLetterRate()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// GetDeadLetterTask
+// @Tags Admin-DeadLetter
+// @Summary Dead letter task
+// @Description Dead letter task with its error stack
+// @Accept  json
+// @Produce  json
+// @Param dead_letter_task_id path string true "ID"
+// @Success 200 {object} models.DeadLetterTask
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/{dead_letter_task_id} [get]
+func GetDeadLetterTask(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetDeadLetterTaskParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewDeadLetterTaskRepo(cc.App.DB).GetDeadLetterTask(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// UpdateDeadLetterTaskPayload
+// @Tags Admin-DeadLetter
+// @Summary Edit dead letter payload
+// @Description Fix the payload of an archived task before replaying it, the original payload is kept
+// @Accept  json
+// @Produce  json
+// @Param dead_letter_task_id path string true "ID"
+// @Param data body repo.UpdateDeadLetterTaskPayloadParams true "Form"
+// @Success 200 {object} models.DeadLetterTask
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/{dead_letter_task_id}/payload [put]
+func UpdateDeadLetterTaskPayload(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.UpdateDeadLetterTaskPayloadParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewDeadLetterTaskRepo(cc.App.DB).UpdateDeadLetterTaskPayload(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// ReplayDeadLetterTask
+// @Tags Admin-DeadLetter
+// @Summary Replay dead letter task
+// @Description Enqueue an archived task again with its current payload
+// @Accept  json
+// @Produce  json
+// @Param dead_letter_task_id path string true "ID"
+// @Success 200 {object} models.DeadLetterTask
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/{dead_letter_task_id}/replay [put]
+func ReplayDeadLetterTask(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetDeadLetterTaskParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	result, err := worker.GetInstance().ReplayDeadLetterTask(c.Request().Context(), params.DeadLetterTaskID, claims.GetUserID())
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// ReplayDeadLetterTasks
+// @Tags Admin-DeadLetter
+// @Summary Bulk replay dead letter tasks
+// @Description Replay up to 500 archived tasks by IDs, or by task name within the failed time range, oldest first
+// @Accept  json
+// @Produce  json
+// @Param data body models.ReplayDeadLetterTasksForm true "Form"
+// @Success 200 {object} map[string]int
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/replay [post]
+func ReplayDeadLetterTasks(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var form models.ReplayDeadLetterTasksForm
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&form)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	count, err := worker.GetInstance().ReplayDeadLetterTasks(c.Request().Context(), worker.ReplayDeadLetterTasksParams{
+		IDs:        form.IDs,
+		TaskName:   form.TaskName,
+		FailedFrom: form.FailedFrom,
+		FailedTo:   form.FailedTo,
+		UserID:     claims.GetUserID(),
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(map[string]int{"replayed": count})
+}
+
+// DiscardDeadLetterTask
+// @Tags Admin-DeadLetter
+// @Summary Discard dead letter task
+// @Description Drop an archived task which must not run again
+// @Accept  json
+// @Produce  json
+// @Param dead_letter_task_id path string true "ID"
+// @Success 200 {object} models.DeadLetterTask
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/dead_letter_tasks/{dead_letter_task_id}/discard [put]
+func DiscardDeadLetterTask(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetDeadLetterTaskParams
+
+	err := cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	result, err := worker.GetInstance().DiscardDeadLetterTask(params.DeadLetterTaskID)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
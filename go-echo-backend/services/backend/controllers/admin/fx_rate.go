@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// GetFxRates
+// @Tags Admin-FxRate
+// @Summary FX rates
+// @Description FX rates snapshotted on quotations, orders and invoices
+// @Accept  json
+// @Produce  json
+// @Param base_currency query string false "Base currency"
+// @Param quote_currency query string false "Quote currency"
+// @Param date_from query int false "Effective from"
+// @Param date_to query int false "Effective to"
+// @Success 200 {object} []models.FxRate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/fx_rates [get]
+func GetFxRates(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetFxRatesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewFxRateRepo(cc.App.DB).GetFxRates(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// CreateFxRate
+// @Tags Admin-FxRate
+// @Summary Create FX rate
+// @Description One unit of base currency is worth rate units of quote currency, e.g. USD/VND 25350
+// @Accept  json
+// @Produce  json
+// @Param data body repo.CreateFxRateParams true "Form"
+// @Success 200 {object} models.FxRate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/fx_rates [post]
+func CreateFxRate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.CreateFxRateParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewFxRateRepo(cc.App.DB).CreateFxRate(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// ImportFxRates
+// @Tags Admin-FxRate
+// @Summary Import FX rates
+// @Description CSV with base_currency,quote_currency,rate,effective_date rows, effective_date is YYYY-MM-DD or a unix timestamp
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV file"
+// @Success 200 {object} []models.FxRate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/fx_rates/import [post]
+func ImportFxRates(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	formFile, err := cc.FormFile("file")
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	result, err := repo.NewFxRateRepo(cc.App.DB).ImportFxRates(repo.ImportFxRatesParams{
+		JwtClaimsInfo: claims,
+		File:          formFile,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// DeleteFxRate
+// @Tags Admin-FxRate
+// @Summary Delete FX rate
+// @Description Only rates no document has snapshotted can be deleted
+// @Accept  json
+// @Produce  json
+// @Param fx_rate_id path string true "FX rate ID"
+// @Success 200 {string} string
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/fx_rates/{fx_rate_id} [delete]
+func DeleteFxRate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.DeleteFxRateParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	err = repo.NewFxRateRepo(cc.App.DB).DeleteFxRate(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success("Deleted")
+}
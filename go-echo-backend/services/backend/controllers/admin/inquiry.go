@@ -226,13 +226,13 @@ func AdminSendInquiryToBuyer(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.SendInquiryToBuyerTask{
+	_, _ = tasks.SendInquiryToBuyerTask.Dispatch(c.Request().Context(), tasks.SendInquiryToBuyerPayload{
 		UserID: resp.Inquiry.User.ID,
 		Event:  customerio.EventAdminSentQuotationToBuyer,
 		Data:   resp.Inquiry.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           resp.Inquiry.UserID,
 		Message:          fmt.Sprintf("New quotation was created for %s", resp.Inquiry.ReferenceID),
 		NotificationType: enums.UserNotificationTypeInquirySubmitQuotation,
@@ -241,9 +241,9 @@ func AdminSendInquiryToBuyer(c echo.Context) error {
 			InquiryID:          resp.Inquiry.ID,
 			InquiryReferenceID: resp.Inquiry.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateInquiryAuditTask{
+	tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:   resp.Inquiry.ID,
 			ActionType:  enums.AuditActionTypeInquiryAdminSendBuyerQuotation,
@@ -255,7 +255,7 @@ func AdminSendInquiryToBuyer(c echo.Context) error {
 				},
 			},
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Sent")
 }
@@ -293,10 +293,10 @@ func AdminSubmitInquiryQuotation(c echo.Context) error {
 	}
 
 	// CMS notification
-	tasks.CreateCmsNotificationTask{
+	tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 		Message:          fmt.Sprintf("New quotation was created for %s", resp.Inquiry.ReferenceID),
 		NotificationType: enums.CmsNotificationTypeNewInquiryQuotation,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Sent")
 }
@@ -338,13 +338,13 @@ func AdminSubmitMultipleInquiryQuotations(c echo.Context) error {
 	}
 
 	for _, inquiry := range resp {
-		_, _ = tasks.SendInquiryToBuyerTask{
+		_, _ = tasks.SendInquiryToBuyerTask.Dispatch(c.Request().Context(), tasks.SendInquiryToBuyerPayload{
 			UserID: inquiry.User.ID,
 			Event:  customerio.EventAdminSentQuotationToBuyer,
 			Data:   inquiry.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 
-		tasks.CreateUserNotificationTask{
+		tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 			UserID:           inquiry.UserID,
 			Message:          fmt.Sprintf("New quotation was created for %s", inquiry.ReferenceID),
 			NotificationType: enums.UserNotificationTypeInquirySubmitQuotation,
@@ -353,9 +353,9 @@ func AdminSubmitMultipleInquiryQuotations(c echo.Context) error {
 				InquiryID:          inquiry.ID,
 				InquiryReferenceID: inquiry.ReferenceID,
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
-		tasks.CreateInquiryAuditTask{
+		tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   inquiry.ID,
 				ActionType:  enums.AuditActionTypeInquiryAdminSendBuyerQuotation,
@@ -367,7 +367,7 @@ func AdminSubmitMultipleInquiryQuotations(c echo.Context) error {
 					},
 				},
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Sent")
@@ -411,13 +411,13 @@ func AdminInquiryInternalApproveQuotation(c echo.Context) error {
 		"link_quotation": fmt.Sprintf("%s/login", cc.App.Config.WebAppBaseURL),
 	}
 
-	_, _ = tasks.SendInquiryToBuyerTask{
+	_, _ = tasks.SendInquiryToBuyerTask.Dispatch(c.Request().Context(), tasks.SendInquiryToBuyerPayload{
 		UserID: resp.Inquiry.User.ID,
 		Event:  customerio.EventAdminSentQuotationToBuyer,
 		Data:   taskData,
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           resp.Inquiry.UserID,
 		Message:          fmt.Sprintf("New quotation was created for %s", resp.Inquiry.ReferenceID),
 		NotificationType: enums.UserNotificationTypeInquirySubmitQuotation,
@@ -426,7 +426,7 @@ func AdminInquiryInternalApproveQuotation(c echo.Context) error {
 			InquiryID:          resp.Inquiry.ID,
 			InquiryReferenceID: resp.Inquiry.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Sent")
 }
@@ -637,10 +637,10 @@ func AdminInquiryMarkAsPaid(c echo.Context) error {
 			return eris.Wrap(err, err.Error())
 		}
 		for _, purchaseOrder := range purchaseOrders {
-			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 				ApprovedByUserID: claims.GetUserID(),
 				PurchaseOrderID:  purchaseOrder.ID,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	} else {
@@ -648,10 +648,10 @@ func AdminInquiryMarkAsPaid(c echo.Context) error {
 		if err != nil {
 			return eris.Wrap(err, err.Error())
 		}
-		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 			ApprovedByUserID: claims.GetUserID(),
 			PurchaseOrderID:  purchaseOrder.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Confirmed")
@@ -701,10 +701,10 @@ func AdminInquiryMarkAsUnpaid(c echo.Context) error {
 			return eris.Wrap(err, err.Error())
 		}
 		for _, purchaseOrder := range purchaseOrders {
-			tasks.PurchaseOrderBankTransferRejectedTask{
+			tasks.PurchaseOrderBankTransferRejectedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferRejectedPayload{
 				ApprovedByUserID: claims.GetUserID(),
 				PurchaseOrderID:  purchaseOrder.ID,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	} else {
@@ -713,10 +713,10 @@ func AdminInquiryMarkAsUnpaid(c echo.Context) error {
 			return eris.Wrap(err, err.Error())
 		}
 
-		tasks.PurchaseOrderBankTransferRejectedTask{
+		tasks.PurchaseOrderBankTransferRejectedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferRejectedPayload{
 			ApprovedByUserID: claims.GetUserID(),
 			PurchaseOrderID:  purchaseOrder.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("UnPaid")
@@ -755,11 +755,11 @@ func AdminInquiryAssignPIC(c echo.Context) error {
 	}
 
 	for _, userID := range result.AssigneeIDs {
-		tasks.AssignInquiryPICTask{
+		tasks.AssignInquiryPICTask.Dispatch(c.Request().Context(), tasks.AssignInquiryPICPayload{
 			AssignerID: claims.GetUserID(),
 			AssigneeID: userID,
 			InquiryID:  params.InquiryID,
-		}.Dispatch(c.Request().Context())
+		})
 
 	}
 	return cc.Success(result)
@@ -796,25 +796,27 @@ func AdminCreateInquiry(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.CreateCmsNotificationTask{
+	_, _ = tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 		Message:          fmt.Sprintf("New inquiry %s was created", result.ReferenceID),
 		NotificationType: enums.CmsNotificationTypeNewInquiry,
 		Metadata: &models.NotificationMetadata{
 			InquiryID: result.ID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.HubspotSyncInquiryTask{
+	if hubspotTask, err := tasks.HubspotSyncInquiryTask.Outbox(c.Request().Context(), tasks.HubspotSyncInquiryPayload{
 		InquiryID: result.ID,
 		UserID:    claims.GetUserID(),
 		IsAdmin:   false,
-	}.Dispatch(c.Request().Context())
+	}); err == nil {
+		_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(result.ID, hubspotTask)
+	}
 
 	if form.BuyerId != "" {
-		_, _ = tasks.UpdateUserProductClassesTask{
+		_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 			UserID:          claims.GetUserID(),
 			PurchaseOrderID: form.BuyerId,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(result)
@@ -1063,21 +1065,21 @@ func AdminSendInquiryToSeller(c echo.Context) error {
 		})
 		if err == nil {
 			for _, record := range result {
-				tasks.TrackCustomerIOTask{
+				tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 					UserID: record.UserID,
 					Event:  customerio.EventSellerNewRFQRequest,
 					Data: inquiry.GetCustomerIOMetadata(map[string]interface{}{
 						"offer_price":  record.OfferPrice,
 						"offer_remark": record.OfferRemark,
 					}),
-				}.Dispatch(cc.Request().Context())
+				})
 
-				tasks.CreateChatRoomTask{
+				tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 					UserID:    claims.GetUserID(),
 					Role:      claims.GetRole(),
 					InquiryID: inquiry.ID,
 					SellerID:  record.UserID,
-				}.Dispatch(c.Request().Context())
+				})
 			}
 		}
 
@@ -1188,13 +1190,13 @@ func InquiryAddNote(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.NewInquiryNotesTask{
+	tasks.NewInquiryNotesTask.Dispatch(c.Request().Context(), tasks.NewInquiryNotesPayload{
 		UserID:         claims.GetUserID(),
 		InquiryID:      params.TargetID,
 		MentionUserIDs: params.MentionUserIDs,
 		Message:        params.Message,
 		Attachments:    params.Attachments,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -1405,14 +1407,14 @@ func AdminInquirySellerRequestCreateComment(c echo.Context) error {
 
 	if request, err := repo.NewInquirySellerRepo(cc.App.DB).GetInquirySellerRequestByID(requestID, queryfunc.InquirySellerRequestBuilderOptions{}); err == nil {
 		if sender, err := repo.NewUserRepo(cc.App.DB).GetShortUserInfo(params.GetUserID()); err == nil {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: request.UserID,
 				Event:  customerio.EventAdminDesignCommentOnSellerRequest,
 				Data: comment.GetCustomerIOMetadata(map[string]interface{}{
 					"sender":  sender,
 					"request": request.GetCustomerIOMetadata(nil),
 				}),
-			}.Dispatch(cc.Request().Context())
+			})
 		}
 	}
 
@@ -1663,7 +1665,7 @@ func AdminApproveMultipleInquiryQuotations(c echo.Context) error {
 	}
 
 	for _, iq := range inquiries {
-		_, _ = tasks.CreateInquiryAuditTask{
+		_, _ = tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   iq.ID,
 				ActionType:  enums.AuditActionTypeInquiryBuyerApproveQuotation,
@@ -1677,7 +1679,7 @@ func AdminApproveMultipleInquiryQuotations(c echo.Context) error {
 					},
 				},
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Sent")
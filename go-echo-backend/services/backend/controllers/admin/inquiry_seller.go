@@ -49,14 +49,14 @@ func AdminInquirySellerCreateDesignComment(c echo.Context) error {
 
 	if request, err := repo.NewInquirySellerRepo(cc.App.DB).GetInquirySellerRequestByID(requestID, queryfunc.InquirySellerRequestBuilderOptions{}); err == nil {
 		if sender, err := repo.NewUserRepo(cc.App.DB).GetShortUserInfo(params.GetUserID()); err == nil {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: request.UserID,
 				Event:  customerio.EventAdminDesignCommentOnSellerRequest,
 				Data: comment.GetCustomerIOMetadata(map[string]interface{}{
 					"sender":  sender,
 					"request": request.GetCustomerIOMetadata(nil),
 				}),
-			}.Dispatch(cc.Request().Context())
+			})
 		}
 	}
 
@@ -166,17 +166,17 @@ func AdminInquirySellerApproveQuotation(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.AdminApproveSellerQuotationTask{
+	tasks.AdminApproveSellerQuotationTask.Dispatch(c.Request().Context(), tasks.AdminApproveSellerQuotationPayload{
 		AdminID:         claims.GetUserID(),
 		InquirySellerID: params.InquirySellerID,
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateChatRoomTask{
+	tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:          claims.GetUserID(),
 		Role:            claims.GetRole(),
 		PurchaseOrderID: iqSeller.PurchaseOrderID,
 		SellerID:        iqSeller.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Approved")
 }
@@ -213,10 +213,10 @@ func AdminInquirySellerRejectQuotation(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.AdminRejectSellerQuotationTask{
+	tasks.AdminRejectSellerQuotationTask.Dispatch(c.Request().Context(), tasks.AdminRejectSellerQuotationPayload{
 		AdminID:         claims.GetUserID(),
 		InquirySellerID: params.InquirySellerID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Rejected")
 }
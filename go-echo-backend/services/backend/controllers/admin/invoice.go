@@ -245,3 +245,182 @@ func GetInvoiceAttachment(c echo.Context) error {
 	}
 	return cc.Success(result)
 }
+
+// GetInvoiceSeriesList
+// @Tags Admin-Invoice
+// @Summary Invoice series
+// @Description Invoice numbering series with their next number
+// @Accept  json
+// @Produce  json
+// @Param invoice_type query string false "Invoice type"
+// @Param currency query string false "Currency"
+// @Param year query int false "Year"
+// @Success 200 {object} []models.InvoiceSeries
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/invoice_series [get]
+func GetInvoiceSeriesList(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetInvoiceSeriesListParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewInvoiceSeriesRepo(cc.App.DB).GetInvoiceSeriesList(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// ReserveInvoiceSeries
+// @Tags Admin-Invoice
+// @Summary Reserve invoice series
+// @Description Create or configure a series before its first invoice, e.g. to continue a sequence issued outside of the system
+// @Accept  json
+// @Produce  json
+// @Param data body repo.ReserveInvoiceSeriesParams true "Form"
+// @Success 200 {object} models.InvoiceSeries
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/invoice_series [post]
+func ReserveInvoiceSeries(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.ReserveInvoiceSeriesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewInvoiceSeriesRepo(cc.App.DB).ReserveInvoiceSeries(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// CreateCreditNote
+// @Tags Admin-Invoice
+// @Summary Create credit note
+// @Description Credit all or part of a paid invoice, with refund=true the payment is refunded as well
+// @Accept  json
+// @Produce  json
+// @Param invoice_number path int true "Invoice number"
+// @Param data body models.CreateCreditNoteParams true "Form"
+// @Success 200 {object} models.Invoice
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/invoices/{invoice_number}/credit_notes [post]
+func CreateCreditNote(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params models.CreateCreditNoteParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewInvoiceRepo(cc.App.DB).CreateCreditNote(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// GetCreditNotes
+// @Tags Admin-Invoice
+// @Summary Credit notes of invoice
+// @Description Credit notes of invoice
+// @Accept  json
+// @Produce  json
+// @Param invoice_number path int true "Invoice number"
+// @Success 200 {object} []models.Invoice
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/invoices/{invoice_number}/credit_notes [get]
+func GetCreditNotes(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetCreditNotesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewInvoiceRepo(cc.App.DB).GetCreditNotes(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// VoidInvoice
+// @Tags Admin-Invoice
+// @Summary Void invoice
+// @Description Void an unpaid invoice, the number is kept and the document is re-generated with a VOID marker
+// @Accept  json
+// @Produce  json
+// @Param invoice_number path int true "Invoice number"
+// @Param data body models.VoidInvoiceParams true "Form"
+// @Success 200 {object} models.Invoice
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/invoices/{invoice_number}/void [put]
+func VoidInvoice(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params models.VoidInvoiceParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewInvoiceRepo(cc.App.DB).VoidInvoice(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// PostJournalEntries
+// @Tags Admin-Ledger
+// @Summary Post journal entries
+// @Description Post the journal entries of paid invoices, credit notes, payments, fees, refunds, deductions and seller payouts dated in the period. Sources already posted are skipped.
+// @Accept  json
+// @Produce  json
+// @Param data body repo.LedgerPeriodParams true "Form"
+// @Success 200 {object} repo.PostJournalEntriesResponse
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/ledger/journal_entries/post [post]
+func PostJournalEntries(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.LedgerPeriodParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewLedgerRepo(cc.App.DB).PostJournalEntries(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// PaginateJournalEntries
+// @Tags Admin-Ledger
+// @Summary Journal entries
+// @Description Posted journal entries with their lines
+// @Accept  json
+// @Produce  json
+// @Param date_from query int false "Date from"
+// @Param date_to query int false "Date to"
+// @Param source_type query string false "Source type"
+// @Param currency query string false "Currency"
+// @Param account_code query string false "Account code"
+// @Success 200 {object} query.Pagination
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/ledger/journal_entries [get]
+func PaginateJournalEntries(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.PaginateJournalEntriesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	var result = repo.NewLedgerRepo(cc.App.DB).PaginateJournalEntries(params)
+
+	return cc.Success(result)
+}
+
+// ExportJournalEntries
+// @Tags Admin-Ledger
+// @Summary Export journal entries
+// @Description Journal import file for Xero (format=xero) or QuickBooks Online (format=quickbooks). The period is posted before exporting.
+// @Accept  json
+// @Produce  json
+// @Param date_from query int true "Date from"
+// @Param date_to query int true "Date to"
+// @Param format query string true "xero or quickbooks"
+// @Param currency query string false "Currency"
+// @Success 200 {object} models.Attachment
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/ledger/journal_entries/export [get]
+func ExportJournalEntries(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.ExportJournalEntriesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewLedgerRepo(cc.App.DB).ExportJournalEntries(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// GetTrialBalance
+// @Tags Admin-Ledger
+// @Summary Trial balance
+// @Description Debits, credits and balance per account of the posted entries in the period
+// @Accept  json
+// @Produce  json
+// @Param date_from query int true "Date from"
+// @Param date_to query int true "Date to"
+// @Param currency query string false "Currency, USD by default"
+// @Success 200 {object} models.TrialBalance
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/ledger/trial_balance [get]
+func GetTrialBalance(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetTrialBalanceParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewLedgerRepo(cc.App.DB).GetTrialBalance(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
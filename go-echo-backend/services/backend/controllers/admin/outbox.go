@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// PaginateOutboxMessages
+// @Tags Admin-Outbox
+// @Summary Outbox messages
+// @Description Outbox messages, use is_stuck=true to list rows that were not relayed in time
+// @Accept  json
+// @Produce  json
+// @Param statuses query []string false "Statuses"
+// @Param task_name query string false "Task name"
+// @Param reference_id query string false "Reference ID"
+// @Param is_stuck query bool false "Is stuck"
+// @Success 200 {object} query.Pagination
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/outbox_messages [get]
+func PaginateOutboxMessages(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.PaginateOutboxMessagesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	var result = repo.NewOutboxRepo(cc.App.DB).PaginateOutboxMessages(params)
+
+	return cc.Success(result)
+}
+
+// RetryOutboxMessage
+// @Tags Admin-Outbox
+// @Summary Retry outbox message
+// @Description Put a failed or stuck outbox message back to pending
+// @Accept  json
+// @Produce  json
+// @Param outbox_message_id path string true "ID"
+// @Success 200 {object} models.OutboxMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/outbox_messages/{outbox_message_id}/retry [put]
+func RetryOutboxMessage(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.RetryOutboxMessageParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewOutboxRepo(cc.App.DB).RetryOutboxMessage(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// AdminGetBulkPurchaseOrderPaymentMilestones
+// @Tags Admin-PO
+// @Summary Get payment schedule of bulk purchase order
+// @Description Get payment schedule of bulk purchase order
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Success 200 {object} []models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones [get]
+func AdminGetBulkPurchaseOrderPaymentMilestones(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetPaymentMilestonesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewPaymentMilestoneRepo(cc.App.DB).GetPaymentMilestones(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(result)
+}
+
+// AdminUpdateBulkPurchaseOrderPaymentMilestones
+// @Tags Admin-PO
+// @Summary Replace payment schedule of bulk purchase order
+// @Description Replace payment schedule of bulk purchase order, only allowed while no milestone has been paid
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Param data body models.UpdatePaymentMilestoneScheduleParams true "Form"
+// @Success 200 {object} []models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones [put]
+func AdminUpdateBulkPurchaseOrderPaymentMilestones(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params models.UpdatePaymentMilestoneScheduleParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewPaymentMilestoneRepo(cc.App.DB).UpdatePaymentMilestoneSchedule(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(result)
+}
+
+// AdminPaymentMilestoneMarkAsPaid
+// @Tags Admin-PO
+// @Summary Confirm payment of a milestone
+// @Description Confirm payment of a milestone
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Param payment_milestone_id path string true "Milestone ID"
+// @Success 200 {object} models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones/{payment_milestone_id}/mark_as_paid [put]
+func AdminPaymentMilestoneMarkAsPaid(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params models.PaymentMilestoneMarkAsPaidParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	invoiceTask, err := tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(cc.Request().Context(), tasks.CreateBulkPoMilestoneInvoicePayload{
+		ApprovedByUserID:    claims.GetUserID(),
+		BulkPurchaseOrderID: params.BulkPurchaseOrderID,
+		PaymentMilestoneID:  params.PaymentMilestoneID,
+	})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	params.OutboxTasks = []models.OutboxTask{invoiceTask}
+	result, err := repo.NewPaymentMilestoneRepo(cc.App.DB).MarkPaymentMilestoneAsPaid(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(result)
+}
+
+// AdminCreatePaymentMilestoneInvoice
+// @Tags Admin-PO
+// @Summary Create invoice of a milestone
+// @Description Create invoice of a milestone
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Param payment_milestone_id path string true "Milestone ID"
+// @Success 200 {object} models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones/{payment_milestone_id}/invoice [post]
+func AdminCreatePaymentMilestoneInvoice(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.CreateBulkMilestoneInvoiceParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	_, milestone, err := repo.NewInvoiceRepo(cc.App.DB).CreateBulkMilestoneInvoice(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(milestone)
+}
@@ -100,15 +100,18 @@ func ApprovePaymentTransaction(c echo.Context) error {
 	}
 
 	params.JwtClaimsInfo = claims
-	transaction, err := repo.NewPaymentTransactionRepo(cc.App.DB).ApprovePaymentTransactions(params)
+	invoiceTask, err := tasks.CreatePaymentInvoiceTask.Outbox(cc.Request().Context(), tasks.CreatePaymentInvoicePayload{
+		PaymentTransactionID: params.PaymentTransactionID,
+		ApprovedByUserID:     claims.GetUserID(),
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.OutboxTasks = []models.OutboxTask{invoiceTask}
+	_, err = repo.NewPaymentTransactionRepo(cc.App.DB).ApprovePaymentTransactions(params)
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-
-	tasks.CreatePaymentInvoiceTask{
-		PaymentTransactionID: transaction.ID,
-		ApprovedByUserID:     claims.GetUserID(),
-	}.Dispatch(cc.Request().Context())
 
 	// if len(transaction.PurchaseOrders) > 0 {
 	// 	for _, po := range transaction.PurchaseOrders {
@@ -365,13 +365,13 @@ func CreateProduct(c echo.Context) error {
 	}
 
 	if product != nil {
-		_, _ = tasks.CreateSysNotificationTask{
+		_, _ = tasks.CreateSysNotificationTask.Dispatch(c.Request().Context(), tasks.CreateSysNotificationPayload{
 			SysNotification: models.SysNotification{
 				Name:    fmt.Sprintf("New Product - %s", product.Name),
 				Type:    enums.SysNotificationCreateProductType,
 				Message: fmt.Sprintf("New Product - %s", product.Name),
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(product)
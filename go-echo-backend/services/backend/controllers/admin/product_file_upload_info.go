@@ -38,12 +38,12 @@ func UploadProductFile(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.UploadProductFileTask{
+	tasks.UploadProductFileTask.Dispatch(cc.Request().Context(), tasks.UploadProductFilePayload{
 		UploadID:   result.ID,
 		SiteName:   result.SiteName,
 		FileKey:    result.Attachment.FileKey,
 		ScrapeDate: result.ScrapeDate,
-	}.Dispatch(cc.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -232,7 +232,7 @@ func AdminUpdatePurchaseOrderDesign(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("New design was updated for %s", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoUpdateDesign,
@@ -253,9 +253,9 @@ func AdminUpdatePurchaseOrderDesign(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoUpdateDesign,
 		Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
@@ -263,12 +263,12 @@ func AdminUpdatePurchaseOrderDesign(c echo.Context) error {
 			"admin_po_url":                 fmt.Sprintf("%s/samples/%s/customer?open_design_comments=true", cc.App.Config.AdminPortalBaseURL, purchaseOrder.ID),
 			"brand_po_url":                 fmt.Sprintf("%s/samples/%s?open_design_comments=true", cc.App.Config.BrandPortalBaseURL, purchaseOrder.ID),
 		}),
-	}.Dispatch(c.Request().Context())
+	})
 
-	_, _ = tasks.UpdateUserProductClassesTask{
+	_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 		UserID:          purchaseOrder.UserID,
 		PurchaseOrderID: purchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -306,13 +306,13 @@ func AdminUpdatePurchaseOrderRawMaterial(c echo.Context) error {
 	}
 
 	if params.ApproveRawMaterialAt != nil {
-		_, _ = tasks.PurchaseOrderRawMaterialApproveTask{
+		_, _ = tasks.PurchaseOrderRawMaterialApproveTask.DispatchAt(cc.Request().Context(), tasks.PurchaseOrderRawMaterialApprovePayload{
 			JwtClaimsInfo:   claims,
 			PurchaseOrderID: params.PurchaseOrderID,
-		}.DispatchAt(time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
+		}, time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("New raw material was updated for %s", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoUpdateRawMaterial,
@@ -321,16 +321,16 @@ func AdminUpdatePurchaseOrderRawMaterial(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	if len(params.PoRawMaterials) > 0 {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: purchaseOrder.UserID,
 			Event:  customerio.EventPoUpdateMaterial,
 			Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
 				"updated_po_raw_materials": params.PoRawMaterials.GenerateFileURL(),
 			}),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(purchaseOrder)
@@ -368,7 +368,7 @@ func AdminPurchaseOrderMarkMaking(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("Order %s is on making", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoMarkMaking,
@@ -377,13 +377,13 @@ func AdminPurchaseOrderMarkMaking(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoMarkMaking,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -420,7 +420,7 @@ func AdminPurchaseOrderMarkMakingWithoutRawMaterial(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("Order %s is on making", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoMarkMaking,
@@ -429,13 +429,13 @@ func AdminPurchaseOrderMarkMakingWithoutRawMaterial(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoMarkMaking,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -472,7 +472,7 @@ func AdminPurchaseOrderMarkSubmit(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("Order %s is on submit", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoMarkSubmit,
@@ -481,13 +481,13 @@ func AdminPurchaseOrderMarkSubmit(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoMarkSubmit,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -524,7 +524,7 @@ func AdmiPurchaseOrderMarkDelivering(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("Order %s is on delivering", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoMarkDelivering,
@@ -533,13 +533,13 @@ func AdmiPurchaseOrderMarkDelivering(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoMarkDelivering,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -577,11 +577,11 @@ func AdmiPurchaseOrderMarkDelivered(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoConfirmDelivered,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -649,11 +649,11 @@ func AdminApprovePurchaseOrderDesign(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoApproveDesign,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Approved")
 }
@@ -695,7 +695,7 @@ func AdminPurchaseOrderAddDesignComments(c echo.Context) error {
 
 	if purchaseOrder, err := repo.NewPurchaseOrderRepo(cc.App.DB).GetPurchaseOrderShortInfo(orderID); err == nil {
 		comment.PurchaseOrder = purchaseOrder
-		tasks.CreateUserNotificationTask{
+		tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 			UserID:           purchaseOrder.UserID,
 			Message:          fmt.Sprintf("New comment on sample purchase order %s", purchaseOrder.ReferenceID),
 			NotificationType: enums.UserNotificationTypePoDesignNewComment,
@@ -704,10 +704,10 @@ func AdminPurchaseOrderAddDesignComments(c echo.Context) error {
 				PurchaseOrderID:          purchaseOrder.ID,
 				PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
 		if userInfo, err := repo.NewUserRepo(cc.App.DB).GetShortUserInfo(claims.GetUserID()); err == nil {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: purchaseOrder.UserID,
 				Event:  customerio.EventAdminNewDesignComment,
 				Data: comment.GetCustomerIOMetadata(map[string]interface{}{
@@ -715,7 +715,7 @@ func AdminPurchaseOrderAddDesignComments(c echo.Context) error {
 					"admin_po_url": fmt.Sprintf("%s/samples/%s/customer?open_design_comments=true", cc.App.Config.AdminPortalBaseURL, purchaseOrder.ID),
 					"brand_po_url": fmt.Sprintf("%s/samples/%s?open_design_comments=true", cc.App.Config.BrandPortalBaseURL, purchaseOrder.ID),
 				}),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	}
@@ -861,11 +861,11 @@ func AdminPurchaseOrderAssignPIC(c echo.Context) error {
 	}
 
 	for _, userID := range result.AssigneeIDs {
-		tasks.AssignPurchaseOrderPICTask{
+		tasks.AssignPurchaseOrderPICTask.Dispatch(c.Request().Context(), tasks.AssignPurchaseOrderPICPayload{
 			AssignerID:      claims.GetUserID(),
 			AssigneeID:      userID,
 			PurchaseOrderID: params.PurchaseOrderID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 	return cc.Success(result)
 }
@@ -1013,11 +1013,11 @@ func AdminPurchaseOrderApproveRawMaterial(c echo.Context) error {
 
 			data["approved_raw_materials"] = list.GenerateFileURL()
 		}
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoApproveRawMaterial,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -1057,13 +1057,13 @@ func PurchaseOrderAddNote(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.NewPONotesTask{
+	tasks.NewPONotesTask.Dispatch(c.Request().Context(), tasks.NewPONotesPayload{
 		UserID:          claims.GetUserID(),
 		PurchaseOrderID: params.TargetID,
 		MentionUserIDs:  params.MentionUserIDs,
 		Message:         params.Message,
 		Attachments:     params.Attachments,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -1258,7 +1258,7 @@ func AdmiPurchaseOrderStageCommentsCreate(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoNewComment,
 		Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
@@ -1267,7 +1267,7 @@ func AdmiPurchaseOrderStageCommentsCreate(c echo.Context) error {
 				return params.Attachments.GenerateFileURL()
 			}(),
 		}),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
@@ -1306,11 +1306,11 @@ func AdminUpdatePurchaseOrder(c echo.Context) error {
 
 	for _, assigneeID := range order.AssigneeIDs {
 		var data = order.GetCustomerIOMetadata(nil)
-		_, _ = tasks.TrackCustomerIOTask{
+		_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerUpdated,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -1348,27 +1348,27 @@ func AdminCreateBulkPurchaseOrderFromSample(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.CreateChatRoomTask{
+	_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:              claims.GetUserID(),
 		Role:                claims.GetRole(),
 		BulkPurchaseOrderID: result.ID,
 		BuyerID:             result.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	if len(result.AssigneeIDs) > 0 {
 		for _, userID := range result.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: userID,
 				Event:  customerio.EventBulkPoCreated,
 				Data:   result.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	} else {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: cc.App.Config.InflowMerchandiseGroupEmail,
 			Event:  customerio.EventBulkPoCreated,
 			Data:   result.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(result)
@@ -1408,11 +1408,11 @@ func AdminCreatePurchaseOrder(c echo.Context) error {
 
 	for _, assigneeID := range order.AssigneeIDs {
 		var data = order.GetCustomerIOMetadata(nil)
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoCreated,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -1488,13 +1488,13 @@ func AdminPurchaseOrderConfirm(c echo.Context) error {
 		return err
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: resp.UserID,
 		Event:  customerio.EventPoConfirmed,
 		Data: resp.GetCustomerIOMetadata(map[string]interface{}{
 			"confirmed_by": userAdmin.GetCustomerIOMetadata(nil),
 		}),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(resp)
 }
@@ -1535,13 +1535,13 @@ func AdminPurchaseOrderCancel(c echo.Context) error {
 		return err
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: resp.UserID,
 		Event:  customerio.EventAdminPoCanceled,
 		Data: resp.GetCustomerIOMetadata(map[string]interface{}{
 			"canceled_by": userAdmin.GetCustomerIOMetadata(nil),
 		}),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(resp)
 }
@@ -1587,10 +1587,10 @@ func AdminPurchaseOrderMarkAsPaid(c echo.Context) error {
 			return eris.Wrap(err, err.Error())
 		}
 		for _, purchaseOrder := range purchaseOrders {
-			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 				ApprovedByUserID: claims.GetUserID(),
 				PurchaseOrderID:  purchaseOrder.ID,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	} else {
@@ -1598,10 +1598,10 @@ func AdminPurchaseOrderMarkAsPaid(c echo.Context) error {
 		if err != nil {
 			return eris.Wrap(err, err.Error())
 		}
-		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 			ApprovedByUserID: claims.GetUserID(),
 			PurchaseOrderID:  purchaseOrder.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Confirmed")
@@ -1648,10 +1648,10 @@ func AdminPurchaseOrderMarkAsUnpaid(c echo.Context) error {
 			return eris.Wrap(err, err.Error())
 		}
 		for _, purchaseOrder := range purchaseOrders {
-			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+			_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 				ApprovedByUserID: claims.GetUserID(),
 				PurchaseOrderID:  purchaseOrder.ID,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	} else {
@@ -1659,10 +1659,10 @@ func AdminPurchaseOrderMarkAsUnpaid(c echo.Context) error {
 		if err != nil {
 			return eris.Wrap(err, err.Error())
 		}
-		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask{
+		_, _ = tasks.PurchaseOrderBankTransferConfirmedTask.Dispatch(c.Request().Context(), tasks.PurchaseOrderBankTransferConfirmedPayload{
 			ApprovedByUserID: claims.GetUserID(),
 			PurchaseOrderID:  purchaseOrder.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Confirmed")
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// Search
+// @Tags Admin-Search
+// @Summary Search
+// @Description Ranked users, inquiries, purchase orders, bulk purchase orders, products and invoices matching the keyword, grouped by type. The groups are limited to the ones visible to the role and team of the user
+// @Accept  json
+// @Produce  json
+// @Param keyword query string true "Keyword"
+// @Param groups query []string false "Groups" enums(users,inquiries,purchase_orders,bulk_purchase_orders,products,invoices)
+// @Param limit query int false "Hits per group" default(5)
+// @Success 200 {object} models.SearchResponse
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/search [get]
+func Search(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.SearchParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var user models.User
+	err = cc.GetUserFromContext(&user)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	params.Team = user.Team
+	result, err := repo.NewSearchRepo(cc.App.DB).Search(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
@@ -218,21 +218,21 @@ func AdminSellerBulkPurhcaseOrderSendToSeller(c echo.Context) error {
 		})
 		if err == nil {
 			for _, record := range result {
-				tasks.TrackCustomerIOTask{
+				tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 					UserID: record.UserID,
 					Event:  customerio.EventSellerNewBulkPurchaseOrderQuotation,
 					Data: bulk.GetCustomerIOMetadata(map[string]interface{}{
 						"offer_price":  record.OfferPrice,
 						"offer_remark": record.OfferRemark,
 					}),
-				}.Dispatch(cc.Request().Context())
+				})
 
-				tasks.CreateChatRoomTask{
+				tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 					UserID:              claims.GetUserID(),
 					Role:                claims.GetRole(),
 					BulkPurchaseOrderID: bulk.ID,
 					SellerID:            record.UserID,
-				}.Dispatch(c.Request().Context())
+				})
 			}
 		}
 
@@ -678,10 +678,10 @@ func AdminSellerBulkPurchaseOrderApproveQuotation(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.AdminApproveSellerBulkPurchaseOrderQuotation{
+	tasks.AdminApproveSellerBulkPurchaseOrderQuotation.Dispatch(c.Request().Context(), tasks.AdminApproveSellerBulkPurchaseOrderQuotationPayload{
 		AdminID:           claims.GetUserID(),
 		SellerQuotationID: params.SellerQuotationID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Approved")
 }
@@ -718,10 +718,10 @@ func AdminSellerBulkPurchaseOrderRejectQuotation(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.AdminRejectSellerBulkPurchaseOrderQuotation{
+	tasks.AdminRejectSellerBulkPurchaseOrderQuotation.Dispatch(c.Request().Context(), tasks.AdminRejectSellerBulkPurchaseOrderQuotationPayload{
 		AdminID:           claims.GetUserID(),
 		SellerQuotationID: params.SellerQuotationID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Rejected")
 }
@@ -896,11 +896,11 @@ func AdminSellerBulkPurchaseOrderMarkRawMaterial(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.SellerID,
 		Event:  customerio.EventBulkPoMarkRawMaterial,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(bulkPO)
 }
@@ -936,7 +936,7 @@ func AdminSellerBulkPurchaseOrderUpdatePps(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           bulkPO.SellerID,
 		Message:          fmt.Sprintf("Pre-Production info was updated for %s", bulkPO.ReferenceID),
 		NotificationType: enums.UserNotificationTypeBulkPoUpdatePps,
@@ -957,12 +957,12 @@ func AdminSellerBulkPurchaseOrderUpdatePps(c echo.Context) error {
 				return ""
 			}(),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: bulkPO.SellerID,
 		Event:  customerio.EventBulkPoUpdatePps,
 		Data:   bulkPO.GetCustomerIOMetadata(nil),
-	}.Dispatch(c.Request().Context())
+	})
 	return cc.Success(bulkPO)
 }
@@ -299,13 +299,13 @@ func AdminSellerPurchaseOrderDesignCommentCreate(c echo.Context) error {
 	if purchaseOrder, err := repo.NewPurchaseOrderRepo(cc.App.DB).GetPurchaseOrderShortInfo(orderID); err == nil {
 		comment.PurchaseOrder = purchaseOrder
 		for _, assigneeID := range purchaseOrder.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: assigneeID,
 				Event:  customerio.EventSellerNewDesignComment,
 				Data: comment.GetCustomerIOMetadata(map[string]interface{}{
 					"sender": purchaseOrder.SampleMaker,
 				}),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	}
@@ -998,9 +998,9 @@ func UpdateSettingDoc(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
-	_, _ = tasks.DeleteUserDocAgreementTask{
+	_, _ = tasks.DeleteUserDocAgreementTask.Dispatch(c.Request().Context(), tasks.DeleteUserDocAgreementPayload{
 		SettingDocType: params.Type,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
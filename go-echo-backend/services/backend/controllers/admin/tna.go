@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"context"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
 	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
@@ -40,10 +42,11 @@ func AdminCreateTNA(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.TimeAndActionSchedulerTask{
+	tasks.TimeAndActionSchedulerTask.Dispatch(c.Request().Context(), tasks.TimeAndActionSchedulerPayload{
 		ID:         result.ID,
 		ActionType: tasks.TimeAndActionSchedulerActionTypeCreate,
-	}.Dispatch(c.Request().Context())
+	})
+	dispatchTNAPlanTasks(c.Request().Context(), result.Plan)
 
 	return cc.Success(result)
 }
@@ -80,10 +83,11 @@ func AdminUpdateTNA(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.TimeAndActionSchedulerTask{
+	tasks.TimeAndActionSchedulerTask.Dispatch(c.Request().Context(), tasks.TimeAndActionSchedulerPayload{
 		ID:         result.ID,
 		ActionType: tasks.TimeAndActionSchedulerActionTypeUpdate,
-	}.Dispatch(c.Request().Context())
+	})
+	dispatchTNAPlanTasks(c.Request().Context(), result.Plan)
 
 	return cc.Success(result)
 }
@@ -156,10 +160,65 @@ func AdminDeleteTNA(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.TimeAndActionSchedulerTask{
+	tasks.TimeAndActionSchedulerTask.Dispatch(c.Request().Context(), tasks.TimeAndActionSchedulerPayload{
 		ID:         params.ID,
 		ActionType: tasks.TimeAndActionSchedulerActionTypeDelete,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("deleted")
 }
+
+// AdminGetTNAPlan
+// @Tags Admin-TNA
+// @Summary TNA plan
+// @Description TNA of an inquiry, PO or bulk PO with critical path, float and projected ex-factory date
+// @Accept  json
+// @Produce  json
+// @Param reference_id query string true "Inquiry, PO or bulk PO reference ID"
+// @Success 200 {object} models.TNAPlan
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/tnas/plan [get]
+func AdminGetTNAPlan(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetTNAPlanParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewTNARepo(cc.App.DB).GetPlan(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// dispatchTNAPlanTasks re-registers notifications of the auto-shifted tasks and alerts when the plan slipped
+func dispatchTNAPlanTasks(ctx context.Context, plan *models.TNAPlan) {
+	if plan == nil {
+		return
+	}
+
+	for _, id := range plan.ShiftedTNAIDs {
+		_, _ = tasks.TimeAndActionSchedulerTask.Dispatch(ctx, tasks.TimeAndActionSchedulerPayload{
+			ID:         id,
+			ActionType: tasks.TimeAndActionSchedulerActionTypeUpdate,
+		})
+	}
+
+	if plan.NotifySlippage {
+		_, _ = tasks.TimeAndActionSlippageTask.Dispatch(ctx, tasks.TimeAndActionSlippagePayload{
+			ReferenceID: plan.ReferenceID,
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// GetTNATemplates
+// @Tags Admin-TNA
+// @Summary TNA templates
+// @Description Reusable TNA plans keyed by order type and product class
+// @Accept  json
+// @Produce  json
+// @Param order_type query string false "bulk or sample"
+// @Param product_class query string false "Product class"
+// @Success 200 {object} []models.TNATemplate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/tna_templates [get]
+func GetTNATemplates(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetTNATemplatesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewTNATemplateRepo(cc.App.DB).GetTNATemplates(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// CreateTNATemplate
+// @Tags Admin-TNA
+// @Summary Create TNA template
+// @Description Items are positioned by offset days before the delivery date and their dependencies
+// @Accept  json
+// @Produce  json
+// @Param data body repo.CreateTNATemplateParams true "Form"
+// @Success 200 {object} models.TNATemplate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/tna_templates [post]
+func CreateTNATemplate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.CreateTNATemplateParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewTNATemplateRepo(cc.App.DB).CreateTNATemplate(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// UpdateTNATemplate
+// @Tags Admin-TNA
+// @Summary Update TNA template
+// @Description Plans already created from the template are not changed
+// @Accept  json
+// @Produce  json
+// @Param tna_template_id path string true "TNA template ID"
+// @Param data body repo.UpdateTNATemplateParams true "Form"
+// @Success 200 {object} models.TNATemplate
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/tna_templates/{tna_template_id} [put]
+func UpdateTNATemplate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.UpdateTNATemplateParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewTNATemplateRepo(cc.App.DB).UpdateTNATemplate(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success(result)
+}
+
+// DeleteTNATemplate
+// @Tags Admin-TNA
+// @Summary Delete TNA template
+// @Description Plans already created from the template are kept
+// @Accept  json
+// @Produce  json
+// @Param tna_template_id path string true "TNA template ID"
+// @Success 200 {object} string
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/tna_templates/{tna_template_id} [delete]
+func DeleteTNATemplate(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.DeleteTNATemplateParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	err = repo.NewTNATemplateRepo(cc.App.DB).DeleteTNATemplate(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	return cc.Success("Deleted")
+}
+
+// AdminInstantiateBulkPurchaseOrderTNA
+// @Tags Admin-TNA
+// @Summary Create TNA plan from template
+// @Description Backward-schedule a template from the delivery date of the bulk order, the template is matched by product class when tna_template_id is empty
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "Bulk purchase order ID"
+// @Param data body repo.InstantiateBulkPurchaseOrderTNAParams false "Form"
+// @Success 200 {object} []models.TNA
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/admin/bulk_purchase_orders/{bulk_purchase_order_id}/tnas/from_template [post]
+func AdminInstantiateBulkPurchaseOrderTNA(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.InstantiateBulkPurchaseOrderTNAParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewTNATemplateRepo(cc.App.DB).InstantiateBulkPurchaseOrderTNA(params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	for _, tna := range result {
+		_, _ = tasks.TimeAndActionSchedulerTask.Dispatch(c.Request().Context(), tasks.TimeAndActionSchedulerPayload{
+			ID:         tna.ID,
+			ActionType: tasks.TimeAndActionSchedulerActionTypeCreate,
+		})
+	}
+
+	return cc.Success(result)
+}
@@ -188,9 +188,9 @@ func UpdateUser(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.SyncCustomerIOUserTask{
+	tasks.SyncCustomerIOUserTask.Dispatch(cc.Request().Context(), tasks.SyncCustomerIOUserPayload{
 		UserID: u.ID,
-	}.Dispatch(cc.Request().Context())
+	})
 
 	return cc.Success(u)
 }
@@ -297,9 +297,9 @@ func ApproveUser(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.ApproveUserTask{
+	tasks.ApproveUserTask.Dispatch(c.Request().Context(), tasks.ApproveUserPayload{
 		UserID: params.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Approved")
 }
@@ -335,7 +335,7 @@ func InviteUser(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, err = tasks.TrackCustomerIOTask{
+	_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: result.User.ID,
 		Event:  customerio.EventAdminInviteNewUser,
 		Data: map[string]interface{}{
@@ -348,7 +348,7 @@ func InviteUser(c echo.Context) error {
 			"team_display": form.Team.DisplayName(),
 			"link":         result.RedirectURL,
 		},
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -388,21 +388,23 @@ func CreateClient(c echo.Context) error {
 	}
 
 	if result.User.Role == enums.RoleClient && cc.App.Config.IsProd() {
-		tasks.HubspotCreateContactTask{
+		if hubspotTask, err := tasks.HubspotCreateContactTask.Outbox(cc.Request().Context(), tasks.HubspotCreateContactPayload{
 			Data: &hubspot.ContactPropertiesForm{
 				Email:     result.User.Email,
 				Firstname: result.User.FirstName,
 				Lastname:  result.User.LastName,
 				Phone:     result.User.PhoneNumber,
 			},
-		}.Dispatch(cc.Request().Context())
+		}); err == nil {
+			_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(result.User.ID, hubspotTask)
+		}
 	}
 
-	tasks.SyncCustomerIOUserTask{
+	tasks.SyncCustomerIOUserTask.Dispatch(cc.Request().Context(), tasks.SyncCustomerIOUserPayload{
 		UserID: result.User.ID,
-	}.Dispatch(cc.Request().Context())
+	})
 
-	_, err = tasks.TrackCustomerIOTask{
+	_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: result.User.ID,
 		Event:  customerio.EventAdminInviteClient,
 		Data: map[string]interface{}{
@@ -411,7 +413,7 @@ func CreateClient(c echo.Context) error {
 			"last_name":  form.LastName,
 			"link":       result.RedirectURL,
 		},
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -450,10 +452,10 @@ func RejectUser(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.TrackCustomerIOTask{
+	tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: params.UserID,
 		Event:  customerio.EventNotifyUserRejected,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Rejected")
 }
@@ -523,9 +525,9 @@ func DeleteUser(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.SyncCustomerIOUserTask{
+	tasks.SyncCustomerIOUserTask.Dispatch(cc.Request().Context(), tasks.SyncCustomerIOUserPayload{
 		UserID: params.UserID,
-	}.Dispatch(cc.Request().Context())
+	})
 
 	return cc.Success("Deleted")
 }
@@ -51,9 +51,9 @@ func Register(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.OnboardUserTask{
+	tasks.OnboardUserTask.DispatchIn(c.Request().Context(), tasks.OnboardUserPayload{
 		UserID: response.User.ID,
-	}.DispatchIn(c.Request().Context(), time.Second*3)
+	}, time.Second*3)
 
 	return cc.Success(response)
 
@@ -122,14 +122,14 @@ func ForgotPassword(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, err = tasks.TrackCustomerIOTask{
+	_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: response.User.ID,
 		Event:  customerio.EventResetPassword,
 		Data: map[string]interface{}{
 			"email": form.Email,
 			"link":  response.RedirectURL,
 		},
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -256,9 +256,9 @@ func VerifyEmail(c echo.Context) error {
 		return cc.Redirect(http.StatusPermanentRedirect, link)
 	}
 
-	tasks.ApproveUserTask{
+	tasks.ApproveUserTask.Dispatch(c.Request().Context(), tasks.ApproveUserPayload{
 		UserID: claims.GetUserID(),
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Redirect(http.StatusPermanentRedirect, form.RedirectURL)
 }
@@ -393,14 +393,14 @@ func AdminForgotPassword(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, err = tasks.TrackCustomerIOTask{
+	_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: response.User.ID,
 		Event:  customerio.EventResetPassword,
 		Data: map[string]interface{}{
 			"email": form.Email,
 			"link":  response.RedirectURL,
 		},
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -467,9 +467,9 @@ func SellerRegister(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.OnboardSellerTask{
+	_, _ = tasks.OnboardSellerTask.DispatchIn(c.Request().Context(), tasks.OnboardSellerPayload{
 		UserID: response.User.ID,
-	}.DispatchIn(c.Request().Context(), time.Second*3)
+	}, time.Second*3)
 
 	return cc.Success(response)
 
@@ -533,14 +533,14 @@ func SellerForgotPassword(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, err = tasks.TrackCustomerIOTask{
+	_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 		UserID: response.User.ID,
 		Event:  customerio.EventResetPassword,
 		Data: map[string]interface{}{
 			"email": form.Email,
 			"link":  response.RedirectURL,
 		},
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -81,19 +81,19 @@ func UpdateBulkPurchaseOrder(c echo.Context) error {
 	if result.TrackingStatus == enums.BulkPoTrackingStatusWaitingForQuotation {
 		if len(result.AssigneeIDs) > 0 {
 			for _, userID := range result.AssigneeIDs {
-				tasks.TrackCustomerIOTask{
+				tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 					UserID: userID,
 					Event:  customerio.EventBulkPoBuyerWaitingForQuotation,
 					Data:   result.GetCustomerIOMetadata(nil),
-				}.Dispatch(c.Request().Context())
+				})
 			}
 
 		} else {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: cc.App.Config.InflowMerchandiseGroupEmail,
 				Event:  customerio.EventBulkPoBuyerWaitingForQuotation,
 				Data:   result.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	}
@@ -136,18 +136,18 @@ func SubmitBulkPurchaseOrder(c echo.Context) error {
 
 	if len(order.AssigneeIDs) > 0 {
 		for _, assigneeID := range order.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: assigneeID,
 				Event:  customerio.EventBulkPoBuyerSubmitOrder,
 				Data:   order.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	} else {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: cc.App.Config.InflowMerchandiseGroupEmail,
 			Event:  customerio.EventBulkPoBuyerSubmitOrder,
 			Data:   order.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -312,11 +312,11 @@ func BulkPurchaseOrderCheckout(c echo.Context) error {
 
 		}
 		for _, assigneeID := range result.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				Event:  event,
 				UserID: assigneeID,
 				Data:   eventData,
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 	}
@@ -366,6 +366,8 @@ func UpdateBulkPurchaseOrderTrackingStatus(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param purchase_order_id query string true "ID"
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} models.PurchaseOrder
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -427,11 +429,11 @@ func BulkPurchaseBuyerApproveQc(c echo.Context) error {
 	}
 
 	for _, assigneeID := range order.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventBulkPoBuyerApproveQc,
 			Data:   order.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -478,11 +480,11 @@ func BulkPurchaseBuyerApproveRawMaterial(c echo.Context) error {
 
 			data["approved_raw_materials"] = list.GenerateFileURL()
 		}
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventBulkPoBuyerApproveRawMaterial,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -521,11 +523,11 @@ func BuyerBulkPurchaseOrderConfirmDelivered(c echo.Context) error {
 	}
 
 	for _, assigneeID := range order.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventBulkPoBuyerConfirmDelivered,
 			Data:   order.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Confirm Delivered")
@@ -596,10 +598,10 @@ func BulkPurchaseOrderUpdateDesign(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.UpdateUserProductClassesTask{
+	_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 		UserID:              claims.GetUserID(),
 		BulkPurchaseOrderID: result.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -152,7 +152,7 @@ func BuyerMultiCatalogCartCheckout(c echo.Context) error {
 
 	if len(result.Orders) > 0 {
 		for _, purchaseOrder := range result.Orders {
-			tasks.CreateInquiryAuditTask{
+			tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 				Form: models.InquiryAuditCreateForm{
 					InquiryID:       purchaseOrder.InquiryID,
 					ActionType:      enums.AuditActionTypeInquirySamplePoCreated,
@@ -160,9 +160,9 @@ func BuyerMultiCatalogCartCheckout(c echo.Context) error {
 					Description:     fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 					PurchaseOrderID: purchaseOrder.ID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 
-			tasks.CreateUserNotificationTask{
+			tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 				UserID:           purchaseOrder.UserID,
 				Message:          fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 				NotificationType: enums.UserNotificationTypePoCreated,
@@ -178,7 +178,7 @@ func BuyerMultiCatalogCartCheckout(c echo.Context) error {
 					PurchaseOrderID:          purchaseOrder.ID,
 					PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 		if params.PaymentType == enums.PaymentTypeBankTransfer {
@@ -190,11 +190,11 @@ func BuyerMultiCatalogCartCheckout(c echo.Context) error {
 				}
 
 				for _, assigneeID := range assigneeIDs {
-					tasks.TrackCustomerIOTask{
+					tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 						Event:  customerio.EventPoWaitingConfirmBankTransfer,
 						UserID: assigneeID,
 						Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-					}.Dispatch(c.Request().Context())
+					})
 				}
 			} else {
 				var assigneeIDs []string
@@ -205,13 +205,13 @@ func BuyerMultiCatalogCartCheckout(c echo.Context) error {
 				}
 
 				for _, assigneeID := range lo.Uniq(assigneeIDs) {
-					tasks.TrackCustomerIOTask{
+					tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 						Event:  customerio.EventPoMultipleItemsWaitingConfirmBankTransfer,
 						UserID: assigneeID,
 						Data: result.PaymentTransaction.GetCustomerIOMetadata(map[string]interface{}{
 							"purchase_orders": metadata,
 						}),
-					}.Dispatch(c.Request().Context())
+					})
 				}
 			}
 
@@ -110,12 +110,12 @@ func CreateCatalogCart(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, _ = tasks.CreateChatRoomTask{
+	_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:          claims.GetUserID(),
 		Role:            claims.GetRole(),
 		PurchaseOrderID: result.ID,
 		BuyerID:         result.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
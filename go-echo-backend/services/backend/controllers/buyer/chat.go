@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
 	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
 	"github.com/labstack/echo/v4"
@@ -40,9 +41,9 @@ func BuyerCreateChatMessage(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, err = tasks.SendChatMessageTask{
+	_, err = tasks.SendChatMessageTask.Dispatch(cc.Request().Context(), tasks.SendChatMessagePayload{
 		ChatMessage: msg,
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
@@ -59,6 +60,9 @@ func BuyerCreateChatMessage(c echo.Context) error {
 // @Param inquiry_id query string false
 // @Param purchase_order_id query string false
 // @Param bulk_purchase_order_id query string false
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param thread_id query string false "root message of the thread"
 // @Success 200 {object} query.Pagination{Records=[]models.ChatMessage}
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -86,6 +90,169 @@ func BuyerGetChatMessageList(c echo.Context) error {
 	return cc.Success(messageList)
 }
 
+// @Tags Buyer-Chat
+// @Summary Update chat message
+// @Description This API allows buyer to edit their message within the edit window, the previous version is kept
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param data body models.UpdateChatMessageRequest true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/buyer/chat_messages/{chat_message_id} [put]
+func BuyerUpdateChatMessage(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.UpdateChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).UpdateChatMessage(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeMessageUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Buyer-Chat
+// @Summary Delete chat message
+// @Description This API allows buyer to delete their message for everyone within the edit window
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/buyer/chat_messages/{chat_message_id} [delete]
+func BuyerDeleteChatMessage(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.DeleteChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).DeleteChatMessage(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeMessageDeleted,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Buyer-Chat
+// @Summary Add chat message reaction
+// @Description This API allows buyer to react to a message with an emoji
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param data body models.ReactChatMessageRequest true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/buyer/chat_messages/{chat_message_id}/reactions [put]
+func BuyerAddChatMessageReaction(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.ReactChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).AddChatMessageReaction(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeReactionUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
+// @Tags Buyer-Chat
+// @Summary Remove chat message reaction
+// @Description This API allows buyer to remove their emoji from a message
+// @Accept  json
+// @Produce  json
+// @Param chat_message_id path string true "Chat message ID"
+// @Param emoji query string true
+// @Success 200 {object} models.ChatMessage
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/buyer/chat_messages/{chat_message_id}/reactions [delete]
+func BuyerRemoveChatMessageReaction(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+
+	var params models.ReactChatMessageRequest
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	params.JwtClaimsInfo = claims
+
+	msg, err := repo.NewChatRepo(cc.App.DB).RemoveChatMessageReaction(&params)
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	_, err = tasks.ChatMessageEventTask.Dispatch(cc.Request().Context(), tasks.ChatMessageEventPayload{
+		Type:        enums.ChatMessageWsTypeReactionUpdated,
+		ChatMessage: msg,
+	})
+	if err != nil {
+		return eris.Wrap(err, err.Error())
+	}
+	return cc.Success(msg)
+}
+
 // @Tags Buyer-Chat
 // @Summary Get chat user relevant stage
 // @Description This API allows buyer to retrieve chat user relevant stage
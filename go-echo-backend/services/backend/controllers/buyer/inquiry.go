@@ -45,57 +45,59 @@ func BuyerCreateInquiry(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	_, _ = tasks.CreateCmsNotificationTask{
+	_, _ = tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 		Message:          fmt.Sprintf("New inquiry %s was created", result.ReferenceID),
 		NotificationType: enums.CmsNotificationTypeNewInquiry,
 		Metadata: &models.NotificationMetadata{
 			InquiryID: result.ID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	_, _ = tasks.CreateInquiryAuditTask{
+	_, _ = tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:   result.ID,
 			ActionType:  enums.AuditActionTypeInquiryCreated,
 			UserID:      result.User.ID,
 			Description: fmt.Sprintf("%s has been created an inquiry: %s", result.User.Name, result.ReferenceID),
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	_, _ = tasks.CreateChatRoomTask{
+	_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:    claims.GetUserID(),
 		Role:      claims.GetRole(),
 		InquiryID: result.ID,
 		BuyerID:   result.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	if result.User != nil && len(result.User.ContactOwnerIDs) > 0 {
 		for _, contactOwnerID := range result.User.ContactOwnerIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: contactOwnerID,
 				Event:  customerio.EventNewInquiry,
 				Data:   result.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 
 		}
 	} else {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: cc.App.Config.InflowSaleGroupEmail,
 			Event:  customerio.EventNewInquiry,
 			Data:   result.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
-	tasks.HubspotSyncInquiryTask{
+	if hubspotTask, err := tasks.HubspotSyncInquiryTask.Outbox(c.Request().Context(), tasks.HubspotSyncInquiryPayload{
 		InquiryID: result.ID,
 		UserID:    claims.GetUserID(),
 		IsAdmin:   false,
-	}.Dispatch(c.Request().Context())
+	}); err == nil {
+		_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(result.ID, hubspotTask)
+	}
 
-	_, _ = tasks.UpdateUserProductClassesTask{
+	_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 		UserID:    claims.GetUserID(),
 		InquiryID: result.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -133,57 +135,59 @@ func BuyerCreateMultipleInquiries(c echo.Context) error {
 	}
 
 	for _, inquiry := range result {
-		_, _ = tasks.CreateCmsNotificationTask{
+		_, _ = tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 			Message:          fmt.Sprintf("New inquiry %s was created", inquiry.ReferenceID),
 			NotificationType: enums.CmsNotificationTypeNewInquiry,
 			Metadata: &models.NotificationMetadata{
 				InquiryID: inquiry.ID,
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
-		_, _ = tasks.CreateInquiryAuditTask{
+		_, _ = tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   inquiry.ID,
 				ActionType:  enums.AuditActionTypeInquiryCreated,
 				UserID:      inquiry.User.ID,
 				Description: fmt.Sprintf("%s has been created an inquiry: %s", inquiry.User.Name, inquiry.ReferenceID),
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
-		_, _ = tasks.CreateChatRoomTask{
+		_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 			UserID:    claims.GetUserID(),
 			Role:      claims.GetRole(),
 			InquiryID: inquiry.ID,
 			BuyerID:   inquiry.UserID,
-		}.Dispatch(c.Request().Context())
+		})
 
 		if inquiry.User != nil && len(inquiry.User.ContactOwnerIDs) > 0 {
 			for _, contactOwnerID := range inquiry.User.ContactOwnerIDs {
-				tasks.TrackCustomerIOTask{
+				tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 					UserID: contactOwnerID,
 					Event:  customerio.EventNewInquiry,
 					Data:   inquiry.GetCustomerIOMetadata(nil),
-				}.Dispatch(c.Request().Context())
+				})
 
 			}
 		} else {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: cc.App.Config.InflowSaleGroupEmail,
 				Event:  customerio.EventNewInquiry,
 				Data:   inquiry.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
-		tasks.HubspotSyncInquiryTask{
+		if hubspotTask, err := tasks.HubspotSyncInquiryTask.Outbox(c.Request().Context(), tasks.HubspotSyncInquiryPayload{
 			InquiryID: inquiry.ID,
 			UserID:    claims.GetUserID(),
 			IsAdmin:   false,
-		}.Dispatch(c.Request().Context())
+		}); err == nil {
+			_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(inquiry.ID, hubspotTask)
+		}
 
-		_, _ = tasks.UpdateUserProductClassesTask{
+		_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 			UserID:    claims.GetUserID(),
 			InquiryID: inquiry.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(result)
@@ -222,21 +226,23 @@ func BuyerUpdateInquiry(c echo.Context) error {
 	}
 
 	for _, userID := range result.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventBuyerUpdateInquiry,
 			Data: map[string]interface{}{
 				"before": helper.StructToMap(form),
 				"after":  result.GetCustomerIOMetadata(nil),
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
-	tasks.HubspotSyncInquiryTask{
+	if hubspotTask, err := tasks.HubspotSyncInquiryTask.Outbox(c.Request().Context(), tasks.HubspotSyncInquiryPayload{
 		InquiryID: result.ID,
 		UserID:    claims.GetUserID(),
 		IsAdmin:   false,
-	}.Dispatch(c.Request().Context())
+	}); err == nil {
+		_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(result.ID, hubspotTask)
+	}
 
 	return cc.Success(result)
 }
@@ -348,11 +354,11 @@ func BuyerApproveInquiryQuotation(c echo.Context) error {
 	}
 
 	for _, assigneeID := range inquiry.AssigneeIDs {
-		_, _ = tasks.TrackCustomerIOTask{
+		_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventBuyerApproveSkuQuotation,
 			Data:   inquiry.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	lastQuotationRequest, _ := repo.NewInquiryAuditRepo(cc.App.DB).GetLastQuotationLog(repo.GetQuotationLogParams{
@@ -365,7 +371,7 @@ func BuyerApproveInquiryQuotation(c echo.Context) error {
 		quotationLogID = lastQuotationRequest.ID
 	}
 
-	_, _ = tasks.CreateInquiryAuditTask{
+	_, _ = tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:   inquiry.ID,
 			ActionType:  enums.AuditActionTypeInquiryBuyerApproveQuotation,
@@ -380,7 +386,7 @@ func BuyerApproveInquiryQuotation(c echo.Context) error {
 				},
 			},
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Approved")
 }
@@ -427,7 +433,7 @@ func BuyerRejectInquiryQuotation(c echo.Context) error {
 		quotationLogID = lastQuotationRequest.ID
 	}
 
-	tasks.CreateInquiryAuditTask{
+	tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:   inquiry.ID,
 			ActionType:  enums.AuditActionTypeInquiryBuyerRejectQuotation,
@@ -442,14 +448,14 @@ func BuyerRejectInquiryQuotation(c echo.Context) error {
 				},
 			},
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	for _, assigneeID := range inquiry.AssigneeIDs {
-		_, _ = tasks.TrackCustomerIOTask{
+		_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventBuyerRejectSkuQuotation,
 			Data:   inquiry.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	if err != nil {
@@ -851,14 +857,14 @@ func BuyerInquiryCheckout(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, _ = tasks.CreateChatRoomTask{
+	_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:          claims.GetUserID(),
 		Role:            claims.GetRole(),
 		PurchaseOrderID: purchaseOrder.ID,
 		BuyerID:         purchaseOrder.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateInquiryAuditTask{
+	tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:       purchaseOrder.InquiryID,
 			ActionType:      enums.AuditActionTypeInquirySamplePoCreated,
@@ -866,9 +872,9 @@ func BuyerInquiryCheckout(c echo.Context) error {
 			Description:     fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 			PurchaseOrderID: purchaseOrder.ID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
-	tasks.CreateUserNotificationTask{
+	tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 		UserID:           purchaseOrder.UserID,
 		Message:          fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 		NotificationType: enums.UserNotificationTypePoCreated,
@@ -879,15 +885,15 @@ func BuyerInquiryCheckout(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	if params.PaymentType == enums.PaymentTypeBankTransfer {
 		for _, assigneeID := range purchaseOrder.Inquiry.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				Event:  customerio.EventPoWaitingConfirmBankTransfer,
 				UserID: assigneeID,
 				Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
@@ -1133,13 +1139,13 @@ func BuyerApproveMultipleInquiryQuotations(c echo.Context) error {
 
 	for _, iq := range inquiries {
 		for _, assigneeID := range iq.AssigneeIDs {
-			_, _ = tasks.TrackCustomerIOTask{
+			_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: assigneeID,
 				Event:  customerio.EventBuyerApproveSkuQuotation,
 				Data:   iq.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
-		_, _ = tasks.CreateInquiryAuditTask{
+		_, _ = tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   iq.ID,
 				ActionType:  enums.AuditActionTypeInquiryBuyerApproveQuotation,
@@ -1153,7 +1159,7 @@ func BuyerApproveMultipleInquiryQuotations(c echo.Context) error {
 					},
 				},
 			},
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Approved")
@@ -1196,7 +1202,7 @@ func BuyerRejectMultipleInquiryQuotations(c echo.Context) error {
 	}
 
 	for _, iq := range inquiries {
-		tasks.CreateInquiryAuditTask{
+		tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   iq.ID,
 				ActionType:  enums.AuditActionTypeInquiryBuyerRejectQuotation,
@@ -1210,14 +1216,14 @@ func BuyerRejectMultipleInquiryQuotations(c echo.Context) error {
 					},
 				},
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
 		for _, assigneeID := range iq.AssigneeIDs {
-			_, _ = tasks.TrackCustomerIOTask{
+			_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: assigneeID,
 				Event:  customerio.EventBuyerRejectSkuQuotation,
 				Data:   iq.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
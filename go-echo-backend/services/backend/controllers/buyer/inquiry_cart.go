@@ -84,7 +84,7 @@ func BuyerMultiInquiryCheckout(c echo.Context) error {
 
 	if len(result.Orders) > 0 {
 		for _, purchaseOrder := range result.Orders {
-			tasks.CreateInquiryAuditTask{
+			tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 				Form: models.InquiryAuditCreateForm{
 					InquiryID:       purchaseOrder.InquiryID,
 					ActionType:      enums.AuditActionTypeInquirySamplePoCreated,
@@ -92,9 +92,9 @@ func BuyerMultiInquiryCheckout(c echo.Context) error {
 					Description:     fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 					PurchaseOrderID: purchaseOrder.ID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 
-			tasks.CreateUserNotificationTask{
+			tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 				UserID:           purchaseOrder.UserID,
 				Message:          fmt.Sprintf("New sample PO %s has been created for inquiry", purchaseOrder.ReferenceID),
 				NotificationType: enums.UserNotificationTypePoCreated,
@@ -105,18 +105,18 @@ func BuyerMultiInquiryCheckout(c echo.Context) error {
 					PurchaseOrderID:          purchaseOrder.ID,
 					PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 		}
 
 		if params.PaymentType == enums.PaymentTypeBankTransfer {
 			if len(result.Orders) == 1 {
 				var purchaseOrder = result.Orders[0]
 				for _, assigneeID := range purchaseOrder.Inquiry.AssigneeIDs {
-					tasks.TrackCustomerIOTask{
+					tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 						Event:  customerio.EventPoWaitingConfirmBankTransfer,
 						UserID: assigneeID,
 						Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-					}.Dispatch(c.Request().Context())
+					})
 				}
 			} else {
 				var assigneeIDs []string
@@ -127,13 +127,13 @@ func BuyerMultiInquiryCheckout(c echo.Context) error {
 				}
 
 				for _, assigneeID := range lo.Uniq(assigneeIDs) {
-					tasks.TrackCustomerIOTask{
+					tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 						Event:  customerio.EventPoMultipleItemsWaitingConfirmBankTransfer,
 						UserID: assigneeID,
 						Data: result.PaymentTransaction.GetCustomerIOMetadata(map[string]interface{}{
 							"purchase_orders": metadata,
 						}),
-					}.Dispatch(c.Request().Context())
+					})
 				}
 			}
 		}
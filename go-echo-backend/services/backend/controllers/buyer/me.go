@@ -62,10 +62,10 @@ func TrackActivity(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackActivityTask{
+	tasks.TrackActivityTask.Dispatch(c.Request().Context(), tasks.TrackActivityPayload{
 		UserID:                user.ID,
 		UserTrackActivityForm: form,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(user)
 }
@@ -140,9 +140,9 @@ func UpdateMe(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.SyncCustomerIOUserTask{
+	tasks.SyncCustomerIOUserTask.Dispatch(c.Request().Context(), tasks.SyncCustomerIOUserPayload{
 		UserID: u.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(u)
 }
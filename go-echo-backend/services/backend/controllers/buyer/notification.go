@@ -14,6 +14,8 @@ import (
 // @Accept  json
 // @Produce  json
 // @Param page query int false "Page number"
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} query.Pagination{records=[]models.UserNotification}
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -117,7 +117,7 @@ func BuyerOrderCartCheckout(c echo.Context) error {
 
 	if len(result.PurchaseOrders) > 0 {
 		for _, po := range result.PurchaseOrders {
-			tasks.CreateInquiryAuditTask{
+			tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 				Form: models.InquiryAuditCreateForm{
 					InquiryID:       po.InquiryID,
 					ActionType:      enums.AuditActionTypeInquirySamplePoCreated,
@@ -125,14 +125,14 @@ func BuyerOrderCartCheckout(c echo.Context) error {
 					Description:     fmt.Sprintf("New sample PO %s has been created for inquiry", po.ReferenceID),
 					PurchaseOrderID: po.ID,
 				},
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
 	if result.PaymentTransaction.PaymentType == enums.PaymentTypeBankTransfer {
-		tasks.NotifyAdminConfirmPaymentTask{
+		tasks.NotifyAdminConfirmPaymentTask.Dispatch(c.Request().Context(), tasks.NotifyAdminConfirmPaymentPayload{
 			PaymentTransactionID: result.PaymentTransaction.ID,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(result)
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// GetBulkPurchaseOrderPaymentMilestones
+// @Tags Buyer-PO
+// @Summary Get payment schedule of bulk purchase order
+// @Description Get payment schedule of bulk purchase order
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Success 200 {object} []models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones [get]
+func GetBulkPurchaseOrderPaymentMilestones(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params repo.GetPaymentMilestonesParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewPaymentMilestoneRepo(cc.App.DB).GetPaymentMilestones(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(result)
+}
+
+// PaymentMilestoneCheckout
+// @Tags Buyer-PO
+// @Summary Pay a milestone of bulk purchase order
+// @Description Pay a milestone of bulk purchase order by bank transfer or card
+// @Accept  json
+// @Produce  json
+// @Param bulk_purchase_order_id path string true "ID"
+// @Param payment_milestone_id path string true "Milestone ID"
+// @Param data body models.PaymentMilestoneCheckoutParams true "Form"
+// @Success 200 {object} models.PaymentMilestone
+// @Header 200 {string} Bearer YOUR_TOKEN
+// @Security ApiKeyAuth
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/bulk_purchase_orders/{bulk_purchase_order_id}/payment_milestones/{payment_milestone_id}/checkout [post]
+func PaymentMilestoneCheckout(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var params models.PaymentMilestoneCheckoutParams
+
+	claims, err := cc.GetJwtClaimsInfo()
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	err = cc.BindAndValidate(&params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	params.JwtClaimsInfo = claims
+	result, err := repo.NewPaymentMilestoneRepo(cc.App.DB).PaymentMilestoneCheckout(params)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success(result)
+}
@@ -159,6 +159,8 @@ func UpdatePurchaseOrderTrackingStatus(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param purchase_order_id query string true "ID"
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} models.PurchaseOrder
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -218,11 +220,11 @@ func BuyerApprovePurchaseOrderDesign(c echo.Context) error {
 	}
 
 	for _, assigneeID := range purchaseOrder.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerApproveDesign,
 			Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 
 	}
 
@@ -262,11 +264,11 @@ func BuyerRejectPurchaseOrderDesign(c echo.Context) error {
 	}
 
 	for _, assigneeID := range purchaseOrder.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerRejectDesign,
 			Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Rejected")
@@ -305,11 +307,11 @@ func BuyerPurchaseOrderConfirmDelivered(c echo.Context) error {
 	}
 
 	for _, assigneeID := range purchaseOrder.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerConfirmDelivered,
 			Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success("Confirm Delivered")
@@ -356,7 +358,7 @@ func BuyerPurchaseOrderAddDesignComments(c echo.Context) error {
 	}
 
 	// CMS notification
-	tasks.CreateCmsNotificationTask{
+	tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 		Message:          fmt.Sprintf("New comment on sample purchase order %s", purchaseOrder.ReferenceID),
 		NotificationType: enums.CmsNotificationTypePoDesignNewComment,
 		Metadata: &models.NotificationMetadata{
@@ -364,11 +366,11 @@ func BuyerPurchaseOrderAddDesignComments(c echo.Context) error {
 			PurchaseOrderID:          purchaseOrder.ID,
 			PurchaseOrderReferenceID: purchaseOrder.ReferenceID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	if userInfo, err := repo.NewUserRepo(cc.App.DB).GetShortUserInfo(claims.GetUserID()); err == nil {
 		for _, userID := range purchaseOrder.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: userID,
 				Event:  customerio.EventBuyerNewDesignComment,
 				Data: result.GetCustomerIOMetadata(map[string]interface{}{
@@ -376,7 +378,7 @@ func BuyerPurchaseOrderAddDesignComments(c echo.Context) error {
 					"admin_po_url": fmt.Sprintf("%s/samples/%s/customer?open_design_comments=true", cc.App.Config.AdminPortalBaseURL, purchaseOrder.ID),
 					"brand_po_url": fmt.Sprintf("%s/samples/%s?open_design_comments=true", cc.App.Config.BrandPortalBaseURL, purchaseOrder.ID),
 				}),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
@@ -530,11 +532,11 @@ func PurchaseOrderBuyerApproveRawMaterial(c echo.Context) error {
 
 			data["approved_raw_materials"] = list.GenerateFileURL()
 		}
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerApproveRawMaterial,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -575,11 +577,11 @@ func UpdatePurchaseOrder(c echo.Context) error {
 
 	for _, assigneeID := range order.AssigneeIDs {
 		var data = order.GetCustomerIOMetadata(nil)
-		_, _ = tasks.TrackCustomerIOTask{
+		_, _ = tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoBuyerUpdated,
 			Data:   data,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -617,12 +619,12 @@ func BuyerCreateBulkPurchaseOrderFromSample(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, _ = tasks.CreateChatRoomTask{
+	_, _ = tasks.CreateChatRoomTask.Dispatch(c.Request().Context(), tasks.CreateChatRoomPayload{
 		UserID:              claims.GetUserID(),
 		Role:                claims.GetRole(),
 		BulkPurchaseOrderID: result.ID,
 		BuyerID:             result.UserID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(result)
 }
@@ -762,7 +764,7 @@ func BuyerPurchaseOrderUpdateDesign(c echo.Context) error {
 	}
 
 	for _, userID := range purchaseOrder.AssigneeIDs {
-		tasks.CreateUserNotificationTask{
+		tasks.CreateUserNotificationTask.Dispatch(c.Request().Context(), tasks.CreateUserNotificationPayload{
 			UserID:           userID,
 			Message:          fmt.Sprintf("New design was updated for %s", purchaseOrder.ReferenceID),
 			NotificationType: enums.UserNotificationTypePoUpdateDesign,
@@ -783,7 +785,7 @@ func BuyerPurchaseOrderUpdateDesign(c echo.Context) error {
 					return ""
 				}(),
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
 		var eventData = purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
 			"admin_po_url": fmt.Sprintf("%s/samples/%s/customer?open_design_comments=true", cc.App.Config.AdminPortalBaseURL, purchaseOrder.ID),
@@ -797,17 +799,17 @@ func BuyerPurchaseOrderUpdateDesign(c echo.Context) error {
 		if params.Attachments != nil {
 			eventData["updated_attachments"] = params.Attachments.GenerateFileURL()
 		}
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventAdminPoUpdateDesign,
 			Data:   eventData,
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
-	_, _ = tasks.UpdateUserProductClassesTask{
+	_, _ = tasks.UpdateUserProductClassesTask.Dispatch(c.Request().Context(), tasks.UpdateUserProductClassesPayload{
 		UserID:          claims.GetUserID(),
 		PurchaseOrderID: purchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(purchaseOrder)
 }
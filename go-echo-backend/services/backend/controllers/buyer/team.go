@@ -80,7 +80,7 @@ func BrandTeamInvite(c echo.Context) error {
 	}
 
 	if result.User.ID != "" {
-		tasks.HubspotCreateContactTask{
+		if hubspotTask, err := tasks.HubspotCreateContactTask.Outbox(cc.Request().Context(), tasks.HubspotCreateContactPayload{
 			Data: &hubspot.ContactPropertiesForm{
 				Email:          result.User.Email,
 				Firstname:      result.User.FirstName,
@@ -88,12 +88,14 @@ func BrandTeamInvite(c echo.Context) error {
 				Phone:          result.User.PhoneNumber,
 				Lifecyclestage: "lead",
 			},
-		}.Dispatch(cc.Request().Context())
+		}); err == nil {
+			_ = repo.NewOutboxRepo(cc.App.DB).AddTasks(result.User.ID, hubspotTask)
+		}
 
 	}
 
 	if result != nil && result.RedirectURL != "" {
-		_, err = tasks.TrackCustomerIOTask{
+		_, err = tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: result.User.ID,
 			Event:  customerio.EventInviteBrandMember,
 			Data: map[string]interface{}{
@@ -101,7 +103,7 @@ func BrandTeamInvite(c echo.Context) error {
 				"link":       result.RedirectURL,
 				"invited_by": result.InvitedByUser.GetCustomerIOMetadata(nil),
 			},
-		}.Dispatch(cc.Request().Context())
+		})
 	}
 	if err != nil {
 		return eris.Wrap(err, err.Error())
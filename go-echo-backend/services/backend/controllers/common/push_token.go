@@ -70,10 +70,10 @@ func CreatePushToken(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.AddCustomerIOUserDeviceTask{
+	tasks.AddCustomerIOUserDeviceTask.Dispatch(c.Request().Context(), tasks.AddCustomerIOUserDevicePayload{
 		UserID: claim.ID,
 		Device: device,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success(device)
 }
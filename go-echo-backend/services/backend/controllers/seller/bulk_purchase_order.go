@@ -111,11 +111,11 @@ func SellerBulkPurchaseOrderUpdateRawMaterial(c echo.Context) error {
 	}
 
 	for _, userID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventSellerBulkPoUpdateRawMaterial,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -153,11 +153,11 @@ func SellerBulkPurchaseOrderUpdatePps(c echo.Context) error {
 	}
 
 	for _, userID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventSellerBulkPoUpdatePps,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -195,11 +195,11 @@ func SellerBulkPurchaseOrderUpdateProduction(c echo.Context) error {
 	}
 
 	for _, userID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventSellerBulkPoUpdateProduction,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -238,11 +238,11 @@ func SellerBulkPurchaseOrderCreateQcReport(c echo.Context) error {
 	}
 
 	for _, userID := range order.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventBulkPoCreateQcReport,
 			Data:   order.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(order)
@@ -663,6 +663,8 @@ func SellerBulkPurchaseOrderMarkDelivering(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param purchase_order_id query string true "ID"
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} models.PurchaseOrder
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -825,11 +827,11 @@ func SellerBulkPurchaseOrderMarkRawMaterial(c echo.Context) error {
 	}
 
 	for _, assigneeID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventSellerBulkPoMarkRawMaterial,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -867,11 +869,11 @@ func SellerBulkPurchaseOrderMarkProduction(c echo.Context) error {
 	}
 
 	for _, assigneeID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventSellerBulkPoMarkProduction,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -909,11 +911,11 @@ func SellerBulkPurchaseOrderMarkInspection(c echo.Context) error {
 	}
 
 	for _, assigneeID := range bulkPO.AssigneeIDs {
-		tasks.TrackCustomerIOTask{
+		tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventSellerBulkPoMarkInspection,
 			Data:   bulkPO.GetCustomerIOMetadata(nil),
-		}.Dispatch(c.Request().Context())
+		})
 	}
 
 	return cc.Success(bulkPO)
@@ -107,10 +107,10 @@ func SellerMarkSeenChatRoomMessage(c echo.Context) error {
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
-	_, err = tasks.SeenChatRoomTask{
+	_, err = tasks.SeenChatRoomTask.Dispatch(cc.Request().Context(), tasks.SeenChatRoomPayload{
 		RoomID:     params.RoomID,
 		SeenUserID: params.GetUserID(),
-	}.Dispatch(cc.Request().Context())
+	})
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
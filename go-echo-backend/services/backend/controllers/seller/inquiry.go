@@ -119,22 +119,22 @@ func SellerSubmitQuotation(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.CreateInquiryAuditTask{
+	tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 		Form: models.InquiryAuditCreateForm{
 			InquiryID:   result.InquiryID,
 			ActionType:  enums.AuditActionTypeInquirySellerSendQuotation,
 			UserID:      claims.GetUserID(),
 			Description: "Seller has created new quotation",
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	if result.Inquiry != nil && len(result.Inquiry.AssigneeIDs) > 0 {
 		for _, userID := range result.Inquiry.AssigneeIDs {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: userID,
 				Event:  customerio.EventSellerSubmitQuotation,
 				Data:   result.GetCustomerIOMetadata(nil),
-			}.Dispatch(c.Request().Context())
+			})
 		}
 	}
 
@@ -333,7 +333,7 @@ func SellerInquiryCreateComment(c echo.Context) error {
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.CreateCmsNotificationTask{
+	tasks.CreateCmsNotificationTask.Dispatch(c.Request().Context(), tasks.CreateCmsNotificationPayload{
 		Message:          fmt.Sprintf("Seller comment on inquiry design %s", sellerRequest.Inquiry.ReferenceID),
 		NotificationType: enums.CmsNotificationTypeInquirySellerDesignComment,
 		Metadata: &models.NotificationMetadata{
@@ -342,18 +342,18 @@ func SellerInquiryCreateComment(c echo.Context) error {
 			InquiryReferenceID: sellerRequest.Inquiry.ReferenceID,
 			InquirySellerID:    sellerRequest.ID,
 		},
-	}.Dispatch(c.Request().Context())
+	})
 
 	for _, userID := range sellerRequest.Inquiry.AssigneeIDs {
 		if sender, err := repo.NewUserRepo(cc.App.DB).GetShortUserInfo(params.GetUserID()); err == nil {
-			tasks.TrackCustomerIOTask{
+			tasks.TrackCustomerIOTask.Dispatch(cc.Request().Context(), tasks.TrackCustomerIOPayload{
 				UserID: userID,
 				Event:  customerio.EventSellerCommentOnSellerRequest,
 				Data: result.GetCustomerIOMetadata(map[string]interface{}{
 					"sender":  sender,
 					"request": sellerRequest.GetCustomerIOMetadata(nil),
 				}),
-			}.Dispatch(cc.Request().Context())
+			})
 		}
 	}
 
@@ -496,22 +496,22 @@ func SellerSubmitMultipleQuotations(c echo.Context) error {
 	}
 
 	for _, iqSeller := range result {
-		tasks.CreateInquiryAuditTask{
+		tasks.CreateInquiryAuditTask.Dispatch(c.Request().Context(), tasks.CreateInquiryAuditPayload{
 			Form: models.InquiryAuditCreateForm{
 				InquiryID:   iqSeller.InquiryID,
 				ActionType:  enums.AuditActionTypeInquirySellerSendQuotation,
 				UserID:      claims.GetUserID(),
 				Description: "Seller has created new quotation",
 			},
-		}.Dispatch(c.Request().Context())
+		})
 
 		if iqSeller.Inquiry != nil && len(iqSeller.Inquiry.AssigneeIDs) > 0 {
 			for _, userID := range iqSeller.Inquiry.AssigneeIDs {
-				tasks.TrackCustomerIOTask{
+				tasks.TrackCustomerIOTask.Dispatch(c.Request().Context(), tasks.TrackCustomerIOPayload{
 					UserID: userID,
 					Event:  customerio.EventSellerSubmitQuotation,
 					Data:   iqSeller.GetCustomerIOMetadata(nil),
-				}.Dispatch(c.Request().Context())
+				})
 			}
 		}
 	}
@@ -33,10 +33,10 @@ func TrackActivity(c echo.Context) error {
 		return eris.Wrap(err, "")
 	}
 
-	tasks.TrackActivityTask{
+	tasks.TrackActivityTask.Dispatch(c.Request().Context(), tasks.TrackActivityPayload{
 		UserID:                claims.ID,
 		UserTrackActivityForm: form,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return cc.Success("Tracked")
 }
@@ -965,6 +965,8 @@ func SellerPurchaseOrderMarkDelivering(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param purchase_order_id query string true "ID"
+// @Param paging query string false "offset or cursor" Enums(offset, cursor)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} models.PurchaseOrder
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
@@ -1022,10 +1024,10 @@ func PurchaseOrderUpdateRawMaterial(c echo.Context) error {
 	}
 
 	if params.ApproveRawMaterialAt != nil {
-		_, _ = tasks.PurchaseOrderRawMaterialApproveTask{
+		_, _ = tasks.PurchaseOrderRawMaterialApproveTask.DispatchAt(cc.Request().Context(), tasks.PurchaseOrderRawMaterialApprovePayload{
 			JwtClaimsInfo:   claims,
 			PurchaseOrderID: params.PurchaseOrderID,
-		}.DispatchAt(time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
+		}, time.Unix(*params.ApproveRawMaterialAt, 0), asynq.MaxRetry(0))
 	}
 
 	return cc.Success(purchaseOrder)
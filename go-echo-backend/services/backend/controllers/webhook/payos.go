@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/payment_gatway/payos"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
+	"github.com/labstack/echo/v4"
+	"github.com/rotisserie/eris"
+)
+
+// PayosWebhook PayOS payment webhook
+// @Tags Webhook
+// @Summary PayOS payment webhook
+// @Description Marks the payment milestone of the paid payment link
+// @Accept  json
+// @Produce  json
+// @Failure 404 {object} errs.Error
+// @Router /api/v1/webhook/payos [post]
+func PayosWebhook(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	var req payos.WebhookRequest
+
+	err := cc.Bind(&req)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	data, err := payos.New(cc.App.Config).VerifyWebhookData(&req)
+	if err != nil {
+		cc.CustomLogger.Errorf("Verify payos webhook err=%+v", err)
+		return cc.Success("Success")
+	}
+
+	if !req.Success || data.PaymentLinkID == "" {
+		return cc.Success("Success")
+	}
+
+	var milestoneRepo = repo.NewPaymentMilestoneRepo(cc.App.DB)
+	milestone, err := milestoneRepo.FindPaymentMilestoneByPaymentLink(data.PaymentLinkID)
+	if err != nil {
+		// Payment links of other resources are acknowledged as is
+		if eris.Is(err, errs.ErrPaymentMilestoneNotFound) {
+			return cc.Success("Success")
+		}
+		return eris.Wrap(err, "")
+	}
+
+	invoiceTask, err := tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(cc.Request().Context(), tasks.CreateBulkPoMilestoneInvoicePayload{
+		BulkPurchaseOrderID: milestone.BulkPurchaseOrderID,
+		PaymentMilestoneID:  milestone.ID,
+	})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+
+	var params = models.PaymentMilestoneMarkAsPaidParams{
+		BulkPurchaseOrderID: milestone.BulkPurchaseOrderID,
+		PaymentMilestoneID:  milestone.ID,
+		PaymentType:         enums.PaymentTypeBankTransfer,
+		PaymentLinkID:       data.PaymentLinkID,
+		TxnID:               data.Reference,
+		OutboxTasks:         []models.OutboxTask{invoiceTask},
+	}
+	_, err = milestoneRepo.MarkPaymentMilestoneAsPaid(params)
+	if err != nil && !eris.Is(err, errs.ErrPaymentMilestoneAlreadyPaid) {
+		return eris.Wrap(err, "")
+	}
+
+	return cc.Success("Success")
+}
@@ -63,6 +63,16 @@ func StripeWebhook(c echo.Context) error {
 			CheckoutSession: &object,
 		})
 
+	case string(stripehelper.ChargeRefunded):
+		var object stripe.Charge
+		err = json.Unmarshal(event.Data.Raw, &object)
+		if err != nil {
+			cc.CustomLogger.Errorf("Error parsing webhook JSON: %v", err)
+			return err
+		}
+
+		err = handler.HandleChargeRefunded(c, &object)
+
 	}
 
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
@@ -101,17 +102,12 @@ func (hanlder *StripeHandler) HandlePayment(c echo.Context, params *HandlePaymen
 		stripehelper.ActionSourceInquiryPayment:
 		err = hanlder.HandleInquiryPayment(c, params)
 
-	case stripehelper.ActionSourceBulkPODepositPayment:
-		err = hanlder.HandleBulkPODepositPayment(c, params)
-
-	case stripehelper.ActionSourceBulkPOFirstPayment:
-		err = hanlder.HandleBulkPOFirstPayment(c, params)
-
-	case stripehelper.ActionSourceBulkPOSecondPayment:
-		err = hanlder.HandleBulkPOSecondPayment(c, params)
-
-	case stripehelper.ActionSourceBulkPOFinalPayment:
-		err = hanlder.HandleBulkPOFinalPayment(c, params)
+	case stripehelper.ActionSourceBulkPODepositPayment,
+		stripehelper.ActionSourceBulkPOFirstPayment,
+		stripehelper.ActionSourceBulkPOSecondPayment,
+		stripehelper.ActionSourceBulkPOFinalPayment,
+		stripehelper.ActionSourceBulkPOMilestonePayment:
+		err = hanlder.HandleBulkPOMilestonePayment(c, params)
 
 	case stripehelper.ActionSourceMultiInquiryPayment:
 		err = hanlder.HandleMultiInquiryPayment(c, params)
@@ -250,243 +246,23 @@ func (hanlder *StripeHandler) HandleInquiryPayment(c echo.Context, params *Handl
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.CreatePOPaymentInvoiceTask{
+	tasks.CreatePOPaymentInvoiceTask.Dispatch(c.Request().Context(), tasks.CreatePOPaymentInvoicePayload{
 		PurchaseOrderID: purchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
-
-	return err
-}
-
-func (hanlder *StripeHandler) HandleBulkPODepositPayment(c echo.Context, params *HandlePaymentParams) error {
-	var cc = c.(*models.CustomContext)
-	bulkPurchaseOrderReferenceID, found := params.metadata["bulk_purchase_order_reference_id"]
-	if !found || bulkPurchaseOrderReferenceID == "" {
-		return eris.New("Bulk PO Reference ID is empty")
-	}
-
-	var bulkPurchaseOrder models.BulkPurchaseOrder
-	var err = cc.App.DB.First(&bulkPurchaseOrder, "reference_id = ?", bulkPurchaseOrderReferenceID).Error
-	if err != nil {
-		return err
-	}
-
-	var inquiry models.Inquiry
-	err = cc.App.DB.Select("ID", "ReferenceID").First(&inquiry, "id = ?", bulkPurchaseOrder.InquiryID).Error
-	if err != nil {
-		return err
-	}
-
-	cancel, err := cc.App.DB.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_%s", bulkPurchaseOrder.ID), time.Second*30)
-	if err != nil {
-		return err
-	}
-	defer cancel()
-
-	var updates = models.BulkPurchaseOrder{
-		DepositTransferedAt:    values.Int64(time.Now().Unix()),
-		DepositMarkAsPaidAt:    values.Int64(time.Now().Unix()),
-		DepositReceiptURL:      params.latestCharge.ReceiptURL,
-		DepositChargeID:        params.latestCharge.ID,
-		DepositPaymentIntentID: params.paymentIntent.ID,
-		DepositPaidAmount:      bulkPurchaseOrder.DepositAmount,
-	}
-	if params.latestCharge.BalanceTransaction != nil {
-		updates.DepositTxnID = params.latestCharge.BalanceTransaction.ID
-	}
-
-	if len(bulkPurchaseOrder.AdminQuotations) > 0 {
-		var bulkQuotation = bulkPurchaseOrder.GetBulkQuotation()
-		if bulkQuotation != nil {
-			updates.LeadTime = int(values.Int64Value(bulkQuotation.LeadTime))
-			updates.StartDate = updates.DepositMarkAsPaidAt
-			updates.CompletionDate = values.Int64(time.Unix(*updates.StartDate, 0).AddDate(0, 0, updates.LeadTime).Unix())
-		}
-	}
-
-	if params.CheckoutSession != nil && params.CheckoutSession.PaymentLink != nil && params.CheckoutSession.PaymentLink.ID != "" {
-		updates.DepositPaymentLinkID = params.CheckoutSession.PaymentLink.ID
-	}
-
-	err = cc.App.DB.Transaction(func(tx *gorm.DB) error {
-		var transaction = models.PaymentTransaction{
-			BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-			Currency:            bulkPurchaseOrder.Currency,
-			PaidAmount:          bulkPurchaseOrder.DepositAmount,
-			PaymentType:         enums.PaymentTypeCard,
-			Milestone:           enums.PaymentMilestoneDeposit,
-			UserID:              bulkPurchaseOrder.UserID,
-			Status:              enums.PaymentStatusPaid,
-			BalanceAmount:       bulkPurchaseOrder.DepositAmount,
-			PaymentPercentage:   values.Float64(100),
-			TotalAmount:         bulkPurchaseOrder.DepositAmount,
-			PaymentIntentID:     updates.DepositPaymentIntentID,
-			ChargeID:            updates.DepositChargeID,
-			TxnID:               updates.DepositTxnID,
-			MarkAsPaidAt:        values.Int64(time.Now().Unix()),
-			PaymentLinkID:       updates.DepositPaymentLinkID,
-			TransactionType:     enums.TransactionTypeCredit,
-			Metadata: &models.PaymentTransactionMetadata{
-				InquiryID:                    inquiry.ID,
-				InquiryReferenceID:           inquiry.ReferenceID,
-				BulkPurchaseOrderReferenceID: bulkPurchaseOrder.ReferenceID,
-				BulkPurchaseOrderID:          bulkPurchaseOrder.ID,
-			},
-		}
-
-		if params.latestCharge.BalanceTransaction != nil {
-			if stripeCfg, err := stripehelper.GetCurrencyConfig(bulkPurchaseOrder.Currency); err == nil {
-				transaction.Net = price.NewFromInt(params.latestCharge.BalanceTransaction.Net).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-				transaction.Fee = price.NewFromInt(params.latestCharge.BalanceTransaction.Fee).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-			}
-		}
-
-		var sqlResult = tx.Model(&models.PaymentTransaction{}).
-			Where("bulk_purchase_order_id = ? AND milestone = ? AND transaction_type = ?", bulkPurchaseOrder.ID, transaction.Milestone, transaction.TransactionType).
-			Updates(&transaction)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			err = tx.Create(&transaction).Error
-		}
-
-		updates.DepositPaymentTransactionReferenceID = transaction.ReferenceID
-		err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPurchaseOrder.ID).Updates(&updates).Error
-		if err != nil {
-			return err
-		}
-
-		return err
 	})
-	if err != nil {
-		return err
-	}
-
-	tasks.CreateBulkPoDepositPaymentInvoiceTask{
-		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
 
 	return err
 }
 
-func (hanlder *StripeHandler) HandleBulkPOFirstPayment(c echo.Context, params *HandlePaymentParams) error {
-	var cc = c.(*models.CustomContext)
-
-	bulkPurchaseOrderReferenceID, found := params.metadata["bulk_purchase_order_reference_id"]
-	if !found || bulkPurchaseOrderReferenceID == "" {
-		return eris.New("Bulk PO Reference ID is empty")
-	}
-
-	var bulkPurchaseOrder models.BulkPurchaseOrder
-	var err = cc.App.DB.First(&bulkPurchaseOrder, "reference_id = ?", bulkPurchaseOrderReferenceID).Error
-	if err != nil {
-		return err
-	}
-
-	var inquiry models.Inquiry
-	err = cc.App.DB.Select("ID", "ReferenceID").First(&inquiry, "id = ?", bulkPurchaseOrder.InquiryID).Error
-	if err != nil {
-		return err
-	}
-
-	cancel, err := cc.App.DB.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_%s", bulkPurchaseOrder.ID), time.Second*30)
-	if err != nil {
-		return err
-	}
-	defer cancel()
-
-	var updates = models.BulkPurchaseOrder{
-		TrackingStatus:           enums.BulkPoTrackingStatusFirstPaymentConfirmed,
-		FirstPaymentTransferedAt: values.Int64(time.Now().Unix()),
-		FirstPaymentMarkAsPaidAt: values.Int64(time.Now().Unix()),
-		FirstPaymentReceiptURL:   params.latestCharge.ReceiptURL,
-		FirstPaymentChargeID:     params.latestCharge.ID,
-		FirstPaymentIntentID:     params.paymentIntent.ID,
-	}
-	if params.latestCharge.BalanceTransaction != nil {
-		updates.FirstPaymentTxnID = params.latestCharge.BalanceTransaction.ID
-	}
-
-	if len(bulkPurchaseOrder.AdminQuotations) > 0 {
-		sampleQuotation, _ := lo.Find(bulkPurchaseOrder.AdminQuotations, func(item *models.InquiryQuotationItem) bool {
-			return item.Type == enums.InquiryTypeSample
-		})
-		if sampleQuotation != nil {
-			updates.LeadTime = int(values.Int64Value(sampleQuotation.LeadTime))
-			updates.StartDate = updates.FirstPaymentMarkAsPaidAt
-			updates.CompletionDate = values.Int64(time.Unix(*updates.StartDate, 0).AddDate(0, 0, updates.LeadTime).Unix())
-		}
-	}
-
-	if params.CheckoutSession != nil && params.CheckoutSession.PaymentLink != nil && params.CheckoutSession.PaymentLink.ID != "" {
-		updates.FirstPaymentLinkID = params.CheckoutSession.PaymentLink.ID
-	}
-
-	err = cc.App.DB.Transaction(func(tx *gorm.DB) error {
-		var transaction = models.PaymentTransaction{
-			BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-			Currency:            bulkPurchaseOrder.Currency,
-			PaidAmount:          bulkPurchaseOrder.FirstPaymentTotal,
-			PaymentType:         enums.PaymentTypeCard,
-			Milestone:           enums.PaymentMilestoneFirstPayment,
-			UserID:              bulkPurchaseOrder.UserID,
-			Status:              enums.PaymentStatusPaid,
-			BalanceAmount:       bulkPurchaseOrder.FinalPaymentTotal,
-			PaymentPercentage:   bulkPurchaseOrder.FirstPaymentPercentage,
-			TotalAmount:         bulkPurchaseOrder.TotalPrice,
-			PaymentIntentID:     updates.FirstPaymentIntentID,
-			ChargeID:            updates.FirstPaymentChargeID,
-			TxnID:               updates.FirstPaymentTxnID,
-			MarkAsPaidAt:        values.Int64(time.Now().Unix()),
-			PaymentLinkID:       updates.FirstPaymentLinkID,
-			TransactionType:     enums.TransactionTypeCredit,
-			Metadata: &models.PaymentTransactionMetadata{
-				InquiryID:                    inquiry.ID,
-				InquiryReferenceID:           inquiry.ReferenceID,
-				BulkPurchaseOrderReferenceID: bulkPurchaseOrder.ReferenceID,
-				BulkPurchaseOrderID:          bulkPurchaseOrder.ID,
-			},
-		}
-
-		if params.latestCharge.BalanceTransaction != nil {
-			if stripeCfg, err := stripehelper.GetCurrencyConfig(bulkPurchaseOrder.Currency); err == nil {
-				transaction.Net = price.NewFromInt(params.latestCharge.BalanceTransaction.Net).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-				transaction.Fee = price.NewFromInt(params.latestCharge.BalanceTransaction.Fee).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-			}
-		}
-
-		var sqlResult = tx.Model(&models.PaymentTransaction{}).
-			Where("bulk_purchase_order_id = ? AND milestone = ? AND transaction_type = ?", bulkPurchaseOrder.ID, transaction.Milestone, transaction.TransactionType).
-			Updates(&transaction)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			err = tx.Create(&transaction).Error
-		}
-
-		updates.FirstPaymentTransactionReferenceID = transaction.ReferenceID
-		var err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPurchaseOrder.ID).Updates(&updates).Error
-		if err != nil {
-			return err
-		}
-
-		return err
-	})
-	if err != nil {
-		return err
-	}
-
-	tasks.CreateBulkPoFirstPaymentInvoiceTask{
-		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
-
-	return err
+var legacyBulkPOActionSourceMilestones = map[stripehelper.ActionSource]enums.PaymentMilestone{
+	stripehelper.ActionSourceBulkPODepositPayment: enums.PaymentMilestoneDeposit,
+	stripehelper.ActionSourceBulkPOFirstPayment:   enums.PaymentMilestoneFirstPayment,
+	stripehelper.ActionSourceBulkPOSecondPayment:  enums.PaymentMilestoneSecondPayment,
+	stripehelper.ActionSourceBulkPOFinalPayment:   enums.PaymentMilestoneFinalPayment,
 }
 
-func (hanlder *StripeHandler) HandleBulkPOSecondPayment(c echo.Context, params *HandlePaymentParams) error {
+// HandleBulkPOMilestonePayment marks the paid milestone of the payment schedule.
+// Intents created before the schedule existed carry a legacy action source instead of payment_milestone_id.
+func (hanlder *StripeHandler) HandleBulkPOMilestonePayment(c echo.Context, params *HandlePaymentParams) error {
 	var cc = c.(*models.CustomContext)
 
 	bulkPurchaseOrderReferenceID, found := params.metadata["bulk_purchase_order_reference_id"]
@@ -495,229 +271,59 @@ func (hanlder *StripeHandler) HandleBulkPOSecondPayment(c echo.Context, params *
 	}
 
 	var bulkPurchaseOrder models.BulkPurchaseOrder
-	var err = cc.App.DB.First(&bulkPurchaseOrder, "reference_id = ?", bulkPurchaseOrderReferenceID).Error
-	if err != nil {
-		return err
-	}
-
-	var inquiry models.Inquiry
-	err = cc.App.DB.Select("ID", "ReferenceID").First(&inquiry, "id = ?", bulkPurchaseOrder.InquiryID).Error
-	if err != nil {
-		return err
-	}
-
-	cancel, err := cc.App.DB.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_%s", bulkPurchaseOrder.ID), time.Second*30)
+	var err = cc.App.DB.Select("ID", "Currency").First(&bulkPurchaseOrder, "reference_id = ?", bulkPurchaseOrderReferenceID).Error
 	if err != nil {
 		return err
 	}
-	defer cancel()
-
-	var updates = models.BulkPurchaseOrder{
-		TrackingStatus:            enums.BulkPoTrackingStatusSecondPaymentConfirmed,
-		SecondPaymentTransferedAt: values.Int64(time.Now().Unix()),
-		SecondPaymentMarkAsPaidAt: values.Int64(time.Now().Unix()),
-		SecondPaymentReceiptURL:   params.latestCharge.ReceiptURL,
-		SecondPaymentChargeID:     params.latestCharge.ID,
-		SecondPaymentIntentID:     params.paymentIntent.ID,
-	}
-	if params.latestCharge.BalanceTransaction != nil {
-		updates.SecondPaymentTxnID = params.latestCharge.BalanceTransaction.ID
-	}
-
-	if len(bulkPurchaseOrder.AdminQuotations) > 0 {
-		var bulkQuotation = bulkPurchaseOrder.GetBulkQuotation()
-		if bulkQuotation != nil {
-			updates.LeadTime = int(values.Int64Value(bulkQuotation.LeadTime))
-			updates.StartDate = updates.SecondPaymentMarkAsPaidAt
-			updates.CompletionDate = values.Int64(time.Unix(*updates.StartDate, 0).AddDate(0, 0, updates.LeadTime).Unix())
-		}
-	}
-
-	if params.CheckoutSession != nil && params.CheckoutSession.PaymentLink != nil && params.CheckoutSession.PaymentLink.ID != "" {
-		updates.SecondPaymentLinkID = params.CheckoutSession.PaymentLink.ID
-	}
-
-	err = cc.App.DB.Transaction(func(tx *gorm.DB) error {
-		var transaction = models.PaymentTransaction{
-			BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-			Currency:            bulkPurchaseOrder.Currency,
-			PaidAmount:          bulkPurchaseOrder.SecondPaymentTotal,
-			PaymentType:         enums.PaymentTypeCard,
-			Milestone:           enums.PaymentMilestoneSecondPayment,
-			UserID:              bulkPurchaseOrder.UserID,
-			Status:              enums.PaymentStatusPaid,
-			BalanceAmount:       bulkPurchaseOrder.FinalPaymentTotal,
-			PaymentPercentage:   values.Float64(bulkPurchaseOrder.SecondPaymentPercentage),
-			TotalAmount:         bulkPurchaseOrder.TotalPrice,
-			PaymentIntentID:     updates.SecondPaymentIntentID,
-			ChargeID:            updates.SecondPaymentChargeID,
-			TxnID:               updates.SecondPaymentTxnID,
-			MarkAsPaidAt:        values.Int64(time.Now().Unix()),
-			PaymentLinkID:       updates.SecondPaymentLinkID,
-			TransactionType:     enums.TransactionTypeCredit,
-			Metadata: &models.PaymentTransactionMetadata{
-				InquiryID:                    inquiry.ID,
-				InquiryReferenceID:           inquiry.ReferenceID,
-				BulkPurchaseOrderReferenceID: bulkPurchaseOrder.ReferenceID,
-				BulkPurchaseOrderID:          bulkPurchaseOrder.ID,
-			},
-		}
-
-		if params.latestCharge.BalanceTransaction != nil {
-			if stripeCfg, err := stripehelper.GetCurrencyConfig(bulkPurchaseOrder.Currency); err == nil {
-				transaction.Net = price.NewFromInt(params.latestCharge.BalanceTransaction.Net).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-				transaction.Fee = price.NewFromInt(params.latestCharge.BalanceTransaction.Fee).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-			}
-		}
-
-		var sqlResult = tx.Model(&models.PaymentTransaction{}).
-			Where("bulk_purchase_order_id = ? AND milestone = ? AND transaction_type = ?", bulkPurchaseOrder.ID, transaction.Milestone, transaction.TransactionType).
-			Updates(&transaction)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			err = tx.Create(&transaction).Error
-		}
 
-		updates.SecondPaymentTransactionReferenceID = transaction.ReferenceID
-		var err = tx.Model(&models.BulkPurchaseOrder{}).Where("id = ?", bulkPurchaseOrder.ID).Updates(&updates).Error
+	var milestoneRepo = repo.NewPaymentMilestoneRepo(cc.App.DB)
+	var paymentMilestoneID = params.metadata["payment_milestone_id"]
+	if paymentMilestoneID == "" {
+		milestone, err := milestoneRepo.FindPaymentMilestoneByKind(bulkPurchaseOrder.ID, legacyBulkPOActionSourceMilestones[params.actionSource])
 		if err != nil {
 			return err
 		}
-
-		return err
-	})
-	if err != nil {
-		return err
+		paymentMilestoneID = milestone.ID
 	}
 
-	tasks.CreateBulkPoSecondPaymentInvoiceTask{
+	var markParams = models.PaymentMilestoneMarkAsPaidParams{
 		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-	}.Dispatch(c.Request().Context())
-
-	return err
-}
-
-func (hanlder *StripeHandler) HandleBulkPOFinalPayment(c echo.Context, params *HandlePaymentParams) error {
-	var cc = c.(*models.CustomContext)
-
-	bulkPurchaseOrderReferenceID, found := params.metadata["bulk_purchase_order_reference_id"]
-	if !found || bulkPurchaseOrderReferenceID == "" {
-		return eris.New("Bulk PO Reference ID is empty")
-	}
-
-	var bulkPurchaseOrder models.BulkPurchaseOrder
-	var err = cc.App.DB.First(&bulkPurchaseOrder, "reference_id = ?", bulkPurchaseOrderReferenceID).Error
-	if err != nil {
-		return err
-	}
-
-	var inquiry models.Inquiry
-	err = cc.App.DB.Select("ID", "ReferenceID").First(&inquiry, "id = ?", bulkPurchaseOrder.InquiryID).Error
-	if err != nil {
-		return err
-	}
-
-	cancel, err := cc.App.DB.Locker.AcquireLock(fmt.Sprintf("bulk_purchase_order_%s", bulkPurchaseOrder.ID), time.Second*30)
-	if err != nil {
-		return err
-	}
-	defer cancel()
-
-	var updates = models.BulkPurchaseOrder{
-		TrackingStatus:           enums.BulkPoTrackingStatusFinalPaymentConfirmed,
-		FinalPaymentTransferedAt: values.Int64(time.Now().Unix()),
-		FinalPaymentMarkAsPaidAt: values.Int64(time.Now().Unix()),
-		FinalPaymentReceiptURL:   params.latestCharge.ReceiptURL,
-		FinalPaymentChargeID:     params.latestCharge.ID,
-		FinalPaymentIntentID:     params.paymentIntent.ID,
-	}
-	if params.latestCharge.BalanceTransaction != nil {
-		updates.FinalPaymentTxnID = params.latestCharge.BalanceTransaction.ID
-	}
-
-	if len(bulkPurchaseOrder.AdminQuotations) > 0 {
-		sampleQuotation, _ := lo.Find(bulkPurchaseOrder.AdminQuotations, func(item *models.InquiryQuotationItem) bool {
-			return item.Type == enums.InquiryTypeSample
-		})
-		if sampleQuotation != nil {
-			updates.LeadTime = int(values.Int64Value(sampleQuotation.LeadTime))
-			updates.StartDate = updates.FinalPaymentMarkAsPaidAt
-			updates.CompletionDate = values.Int64(time.Unix(*updates.StartDate, 0).AddDate(0, 0, updates.LeadTime).Unix())
-		}
-	}
-
-	if params.CheckoutSession != nil && params.CheckoutSession.PaymentLink != nil && params.CheckoutSession.PaymentLink.ID != "" {
-		updates.FinalPaymentLinkID = params.CheckoutSession.PaymentLink.ID
-	}
-
-	// create transaction
-	var transaction = models.PaymentTransaction{
-		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-		Currency:            bulkPurchaseOrder.Currency,
+		PaymentMilestoneID:  paymentMilestoneID,
 		PaymentType:         enums.PaymentTypeCard,
-		UserID:              bulkPurchaseOrder.UserID,
-		Status:              enums.PaymentStatusPaid,
-		PaymentPercentage:   values.Float64(100 - values.Float64Value(bulkPurchaseOrder.FirstPaymentPercentage) - bulkPurchaseOrder.SecondPaymentPercentage),
-		TotalAmount:         bulkPurchaseOrder.TotalPrice,
-		PaymentIntentID:     updates.FinalPaymentIntentID,
-		ChargeID:            updates.FinalPaymentChargeID,
-		TxnID:               updates.FinalPaymentTxnID,
-		MarkAsPaidAt:        values.Int64(time.Now().Unix()),
-		PaidAmount:          bulkPurchaseOrder.FinalPaymentTotal,
-		BalanceAmount:       price.NewFromFloat(0).ToPtr(),
-		Milestone:           enums.PaymentMilestoneFinalPayment,
-		PaymentLinkID:       updates.FinalPaymentLinkID,
-		TransactionType:     enums.TransactionTypeCredit,
-		Metadata: &models.PaymentTransactionMetadata{
-			InquiryID:                    inquiry.ID,
-			InquiryReferenceID:           inquiry.ReferenceID,
-			BulkPurchaseOrderReferenceID: bulkPurchaseOrder.ReferenceID,
-			BulkPurchaseOrderID:          bulkPurchaseOrder.ID,
-		},
+		PaymentIntentID:     params.paymentIntent.ID,
+		ChargeID:            params.latestCharge.ID,
+		ReceiptURL:          params.latestCharge.ReceiptURL,
 	}
 
 	if params.latestCharge.BalanceTransaction != nil {
+		markParams.TxnID = params.latestCharge.BalanceTransaction.ID
 		if stripeCfg, err := stripehelper.GetCurrencyConfig(bulkPurchaseOrder.Currency); err == nil {
-			transaction.Net = price.NewFromInt(params.latestCharge.BalanceTransaction.Net).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
-			transaction.Fee = price.NewFromInt(params.latestCharge.BalanceTransaction.Fee).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
+			markParams.Net = price.NewFromInt(params.latestCharge.BalanceTransaction.Net).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
+			markParams.Fee = price.NewFromInt(params.latestCharge.BalanceTransaction.Fee).DivInt(stripeCfg.SmallestUnitFactor).ToPtr()
 		}
 	}
 
-	err = cc.App.DB.Transaction(func(tx *gorm.DB) error {
-		var sqlResult = tx.Model(&models.PaymentTransaction{}).
-			Where("bulk_purchase_order_id = ? AND milestone = ? AND transaction_type = ?", bulkPurchaseOrder.ID, transaction.Milestone, transaction.TransactionType).
-			Updates(&transaction)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			err = tx.Create(&transaction).Error
-		}
-
-		updates.FinalPaymentTransactionReferenceID = transaction.ReferenceID
-		sqlResult = tx.Model(&models.BulkPurchaseOrder{}).Where("reference_id = ?", bulkPurchaseOrderReferenceID).Updates(&updates)
-		if sqlResult.Error != nil {
-			return sqlResult.Error
-		}
-
-		if sqlResult.RowsAffected == 0 {
-			err = tx.Create(&transaction).Error
+	if params.CheckoutSession != nil {
+		markParams.CheckoutSessionID = params.CheckoutSession.ID
+		if params.CheckoutSession.PaymentLink != nil && params.CheckoutSession.PaymentLink.ID != "" {
+			markParams.PaymentLinkID = params.CheckoutSession.PaymentLink.ID
 		}
+	}
 
-		return err
+	invoiceTask, err := tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(cc.Request().Context(), tasks.CreateBulkPoMilestoneInvoicePayload{
+		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
+		PaymentMilestoneID:  paymentMilestoneID,
 	})
 	if err != nil {
 		return err
 	}
+	markParams.OutboxTasks = []models.OutboxTask{invoiceTask}
 
-	tasks.CreateBulkPoFinalPaymentInvoiceTask{
-		BulkPurchaseOrderID: bulkPurchaseOrder.ID,
-		ReCreate:            true,
-	}.Dispatch(c.Request().Context())
+	_, err = milestoneRepo.MarkPaymentMilestoneAsPaid(markParams)
+	if eris.Is(err, errs.ErrPaymentMilestoneAlreadyPaid) {
+		// A retried delivery, the invoice task was written in the transaction which marked the milestone paid
+		return nil
+	}
 
 	return err
 }
@@ -902,9 +508,9 @@ func (hanlder *StripeHandler) HandleMultiInquiryPayment(c echo.Context, params *
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.CreatePOPaymentInvoiceForMultipleItemsTask{
+	tasks.CreatePOPaymentInvoiceForMultipleItemsTask.Dispatch(c.Request().Context(), tasks.CreatePOPaymentInvoiceForMultipleItemsPayload{
 		CheckoutSessionID: checkoutSessionID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return err
 }
@@ -1091,9 +697,9 @@ func (hanlder *StripeHandler) HandleMultiPOPayment(c echo.Context, params *Handl
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.CreatePOPaymentInvoiceForMultipleItemsTask{
+	tasks.CreatePOPaymentInvoiceForMultipleItemsTask.Dispatch(c.Request().Context(), tasks.CreatePOPaymentInvoiceForMultipleItemsPayload{
 		CheckoutSessionID: checkoutSessionID,
-	}.Dispatch(c.Request().Context())
+	})
 
 	return err
 }
@@ -1143,6 +749,7 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 	purchaseOrdersToUpdate := make([]*models.PurchaseOrder, 0, len(purchaseOrders))
 	inquiryIDsToUpdate := make([]string, 0, len(purchaseOrders))
 	bulksToUpdate := make([]*models.BulkPurchaseOrder, 0, len(bulks))
+	bulkActions := make(map[string]enums.BulkPoTrackingAction, len(bulks))
 	var orderCartItemIDsToUpdate []string
 	var paymentTransaction models.PaymentTransaction
 	var paymentTransactionReferenceID = helper.GeneratePaymentTransactionReferenceID()
@@ -1175,8 +782,9 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 		orderCartItemIDsToUpdate = append(orderCartItemIDsToUpdate, models.OrderCartItems(po.OrderCartItems).IDs()...)
 	}
 	for _, bpo := range bulks {
+		var action = enums.BulkPoTrackingActionFinalPaymentConfirmed
 		if bpo.TrackingStatus == enums.BulkPoTrackingStatusFirstPayment || bpo.TrackingStatus == enums.BulkPoTrackingStatusFirstPaymentConfirmed {
-			bpo.TrackingStatus = enums.BulkPoTrackingStatusFirstPaymentConfirmed
+			action = enums.BulkPoTrackingActionFirstPaymentConfirmed
 			bpo.FirstPaymentTransferedAt = values.Int64(time.Now().Unix())
 			bpo.FirstPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 			bpo.FirstPaymentTransactionReferenceID = paymentTransactionReferenceID
@@ -1192,7 +800,6 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 				bpo.FirstPaymentLink = params.CheckoutSession.PaymentLink.URL
 			}
 		} else {
-			bpo.TrackingStatus = enums.BulkPoTrackingStatusFinalPaymentConfirmed
 			bpo.FinalPaymentTransferedAt = values.Int64(time.Now().Unix())
 			bpo.FinalPaymentMarkAsPaidAt = values.Int64(time.Now().Unix())
 			bpo.FinalPaymentTransactionReferenceID = paymentTransactionReferenceID
@@ -1210,6 +817,12 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 			}
 			orderCartItemIDsToUpdate = append(orderCartItemIDsToUpdate, models.OrderCartItems(bpo.OrderCartItems).IDs()...)
 		}
+		// The checkout or a redelivered event may have confirmed the order already, the payment is still recorded
+		if _, err := repo.FindBulkPurchaseOrderTransition(bpo.TrackingStatus, action); err == nil {
+			bulkActions[bpo.ID] = action
+		} else if bpo.TrackingStatus != enums.BulkPoTrackingStatusFirstPaymentConfirmed && bpo.TrackingStatus != enums.BulkPoTrackingStatusFinalPaymentConfirmed {
+			cc.CustomLogger.Errorf("Bulk order %s is paid in status %s, %s is skipped", bpo.ID, bpo.TrackingStatus, action)
+		}
 		bulksToUpdate = append(bulksToUpdate, bpo)
 	}
 	paymentTransaction = models.PaymentTransaction{
@@ -1270,12 +883,15 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 			}
 		}
 		if len(bulksToUpdate) > 0 {
-			if err := tx.Clauses(clause.OnConflict{
+			if err := tx.Omit("TrackingStatus").Clauses(clause.OnConflict{
 				Columns:   []clause.Column{{Name: "id"}},
 				UpdateAll: true,
 			}).Create(&bulksToUpdate).Error; err != nil {
 				return err
 			}
+			if err := repo.NewBulkPurchaseOrderRepo(cc.App.DB).TransitionEachTx(tx, models.JwtClaimsInfo{}, bulksToUpdate, bulkActions); err != nil {
+				return err
+			}
 		}
 		if len(inquiryIDsToUpdate) > 0 {
 			if err := tx.Model(&models.Inquiry{}).Where("id IN ?", inquiryIDsToUpdate).
@@ -1294,9 +910,28 @@ func (hanlder *StripeHandler) HandleOrderPayment(c echo.Context, params *HandleP
 		return eris.Wrap(err, err.Error())
 	}
 
-	tasks.CreatePaymentInvoiceTask{
+	tasks.CreatePaymentInvoiceTask.Dispatch(c.Request().Context(), tasks.CreatePaymentInvoicePayload{
 		PaymentTransactionID: paymentTransaction.ID,
-	}.Dispatch(c.Request().Context())
+	})
+
+	return nil
+}
+
+// HandleChargeRefunded settles the credit notes whose refund did not answer in time when it was created
+func (hanlder *StripeHandler) HandleChargeRefunded(c echo.Context, charge *stripe.Charge) error {
+	var cc = c.(*models.CustomContext)
+
+	refunds, err := stripehelper.GetInstance().ListRefundsOfCharge(charge.ID)
+	if err != nil {
+		return err
+	}
+
+	var invoiceRepo = repo.NewInvoiceRepo(cc.App.DB)
+	for _, ref := range refunds {
+		if err = invoiceRepo.SettleCreditNoteRefund(ref); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
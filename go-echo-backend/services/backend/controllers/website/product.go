@@ -11,7 +11,7 @@ import (
 // SearchProduct
 // @Tags Marketplace-Product
 // @Summary Search Product
-// @Description Search Product
+// @Description Search Product, with include_facets the metadata lists the options of each facet with their product counts. The options of a facet are OR-ed and the facets AND-ed
 // @Accept  json
 // @Produce  json
 // @Param keyword query string false "Keyword"
@@ -19,8 +19,14 @@ import (
 // @Param rating_star query int false "Rating start"
 // @Param min_order query int false "Min order"
 // @Param product_type query string false "Product type"
+// @Param category_ids query []string false "Category facet"
+// @Param fabric_ids query []string false "Fabric facet"
+// @Param min_orders query []int false "Minimum order facet" enums(10,100,500)
+// @Param ratings query []int false "Rating facet" enums(1,2,3,4,5)
+// @Param price_tiers query []string false "Price facet" enums(under_5,5_to_10,10_to_20,20_to_50,over_50)
+// @Param include_facets query bool false "Include the facets in the metadata"
 // @Param page query int false "Page number"
-// @Success 200 {object} models.ProductResponse
+// @Success 200 {object} query.Pagination{records=[]models.Product,metadata=[]models.ProductFilter}
 // @Header 200 {string} Bearer YOUR_TOKEN
 // @Security ApiKeyAuth
 // @Failure 404 {object} errs.Error
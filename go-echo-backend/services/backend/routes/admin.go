@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	controllers "github.com/engineeringinflow/inflow-backend/services/backend/controllers/admin"
 	"github.com/labstack/echo/v4"
@@ -11,6 +12,14 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	var authorizedWithRoleGroup = authorizedGroup.Group("", router.Middlewares.CheckRole(enums.RoleSuperAdmin, enums.RoleLeader, enums.RoleStaff))
 	var authorizedWithUserGroup = authorizedWithRoleGroup.Group("", router.Middlewares.CheckTokenExpiredAndAttachUserInfo())
 
+	var purgeCategory = router.Middlewares.PurgeCache(models.CacheTagCategory)
+	var purgeProduct = router.Middlewares.PurgeCache(models.CacheTagProduct)
+	var purgeCollection = router.Middlewares.PurgeCache(models.CacheTagCollection)
+	var purgePage = router.Middlewares.PurgeCache(models.CacheTagPage)
+	var purgePost = router.Middlewares.PurgeCache(models.CacheTagPost)
+	var purgeSEO = router.Middlewares.PurgeCache(models.CacheTagSettingSEO, models.CacheTagSEOTranslation)
+	var idempotent = router.Middlewares.Idempotency()
+
 	authorizedWithUserGroup.POST("/me/track_activity", controllers.TrackActivity)
 
 	authorizedWithRoleGroup.DELETE("/me/logout", controllers.Logout)
@@ -25,6 +34,9 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	// authorizedWithRoleGroup.GET("/inventories", controllers.AdminPaginateInventories)
 	// authorizedWithRoleGroup.POST("/inventories/restock", controllers.AdminInventoryRestock)
 
+	// Search
+	authorizedWithUserGroup.GET("/search", controllers.Search)
+
 	// User
 	authorizedWithRoleGroup.GET("/users/search", controllers.SearchUsers)
 	authorizedWithRoleGroup.GET("/users", controllers.PaginateUsers)
@@ -47,17 +59,17 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.GET("/users/:user_id/activities", controllers.GetActivities)
 
 	// Category
-	authorizedWithRoleGroup.PUT("/categories/:category_id", controllers.UpdateCategory)
-	authorizedWithRoleGroup.PATCH("/categories/slug/generate", controllers.GenerateCategorySlug)
-	authorizedWithRoleGroup.POST("/categories", controllers.CreateCategory)
-	authorizedWithRoleGroup.DELETE("/categories/:category_id/delete", controllers.DeleteCategory)
+	authorizedWithRoleGroup.PUT("/categories/:category_id", controllers.UpdateCategory, purgeCategory)
+	authorizedWithRoleGroup.PATCH("/categories/slug/generate", controllers.GenerateCategorySlug, purgeCategory)
+	authorizedWithRoleGroup.POST("/categories", controllers.CreateCategory, purgeCategory)
+	authorizedWithRoleGroup.DELETE("/categories/:category_id/delete", controllers.DeleteCategory, purgeCategory)
 	authorizedWithRoleGroup.GET("/categories/get_category_tree", controllers.AdminGetCategoryTree)
 
 	// Product
 	authorizedWithRoleGroup.GET("/products", controllers.PaginateProduct)
 	authorizedWithRoleGroup.GET("/products/export", controllers.ExportProducts)
 	authorizedWithRoleGroup.GET("/products/search", controllers.SearchProduct)
-	authorizedWithRoleGroup.PATCH("/products/slug/generate", controllers.GenerateProductSlug)
+	authorizedWithRoleGroup.PATCH("/products/slug/generate", controllers.GenerateProductSlug, purgeProduct)
 	authorizedWithRoleGroup.GET("/products/types/price", controllers.PaginateProductTypesPrice)
 	authorizedWithRoleGroup.PATCH("/products/types/price", controllers.FetchProductTypesPrice)
 	authorizedWithRoleGroup.PATCH("/products/fabric/price", controllers.FetchFabricTypesPrice)
@@ -65,53 +77,53 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.GET("/products/types/price/vine", controllers.PaginateProductTypesPriceVine)
 	authorizedWithRoleGroup.GET("/products/fabric/price/vine", controllers.PaginateRWDFabricPriceVine)
 	authorizedWithRoleGroup.GET("/products/types/price/quote", controllers.PaginateProductTypesPriceQuote)
-	authorizedWithRoleGroup.POST("/products/create", controllers.CreateProduct)
-	authorizedWithRoleGroup.PUT("/products/:product_id", controllers.UpdateProduct)
+	authorizedWithRoleGroup.POST("/products/create", controllers.CreateProduct, purgeProduct)
+	authorizedWithRoleGroup.PUT("/products/:product_id", controllers.UpdateProduct, purgeProduct)
 	authorizedWithRoleGroup.GET("/products/:product_id/get", controllers.GetProduct)
-	authorizedWithRoleGroup.DELETE("/products/:product_id/delete", controllers.AdminDeleteProduct)
+	authorizedWithRoleGroup.DELETE("/products/:product_id/delete", controllers.AdminDeleteProduct, purgeProduct)
 	authorizedWithRoleGroup.GET("/products/qr_code", controllers.GetProductQRCode)
 
 	// Subscriber
 	authorizedWithRoleGroup.GET("/subscribers/search", controllers.SearchSubscribers)
 
 	// Collection product
-	authorizedWithRoleGroup.POST("/collections/create", controllers.AdminCreateCollection)
-	authorizedWithRoleGroup.PUT("/collections/:collection_id", controllers.AdminUpdateCollection)
-	authorizedWithRoleGroup.DELETE("/collections/:collection_id/delete", controllers.AdminDeleteCollection)
+	authorizedWithRoleGroup.POST("/collections/create", controllers.AdminCreateCollection, purgeCollection)
+	authorizedWithRoleGroup.PUT("/collections/:collection_id", controllers.AdminUpdateCollection, purgeCollection)
+	authorizedWithRoleGroup.DELETE("/collections/:collection_id/delete", controllers.AdminDeleteCollection, purgeCollection)
 	authorizedWithRoleGroup.GET("/collections", controllers.AdminCollectionList)
 	authorizedWithRoleGroup.GET("/collections/:collection_id/get", controllers.AdminCollectionDetail)
 	authorizedWithRoleGroup.GET("/collections/get_product", controllers.AdminCollectionList)
-	authorizedWithRoleGroup.POST("/collections/:collection_id/add_product", controllers.AdminCollectionAddProduct)
-	authorizedWithRoleGroup.DELETE("/collections/:collection_id/delete_product", controllers.AdminCollectionDeleteProduct)
+	authorizedWithRoleGroup.POST("/collections/:collection_id/add_product", controllers.AdminCollectionAddProduct, purgeCollection)
+	authorizedWithRoleGroup.DELETE("/collections/:collection_id/delete_product", controllers.AdminCollectionDeleteProduct, purgeCollection)
 	authorizedWithRoleGroup.GET("/collections/:collection_id/get_product", controllers.AdminCollectionGetProduct)
 
 	// Page
 	authorizedWithRoleGroup.GET("/pages", controllers.AdminPageList)
 	authorizedWithRoleGroup.GET("/pages/:page_id", controllers.AdminPageDetail)
-	authorizedWithRoleGroup.PUT("/pages/:page_id", controllers.AdminUpdatePage)
+	authorizedWithRoleGroup.PUT("/pages/:page_id", controllers.AdminUpdatePage, purgePage)
 
-	authorizedWithRoleGroup.POST("/pages/create", controllers.AdminCreatePage)
-	authorizedWithRoleGroup.POST("/pages/:id/add_section", controllers.AdminAddPageSection)
-	authorizedWithRoleGroup.PUT("/pages/sections/:id/update", controllers.AdminUpdatePageSection)
-	authorizedWithRoleGroup.DELETE("/pages/sections/:id/delete", controllers.AdminDeletePageSection)
+	authorizedWithRoleGroup.POST("/pages/create", controllers.AdminCreatePage, purgePage)
+	authorizedWithRoleGroup.POST("/pages/:id/add_section", controllers.AdminAddPageSection, purgePage)
+	authorizedWithRoleGroup.PUT("/pages/sections/:id/update", controllers.AdminUpdatePageSection, purgePage)
+	authorizedWithRoleGroup.DELETE("/pages/sections/:id/delete", controllers.AdminDeletePageSection, purgePage)
 
 	authorizedWithRoleGroup.GET("/stats/products", controllers.StatsProducts)
 	authorizedWithRoleGroup.GET("/stats/buyers", controllers.StatBuyers)
 	authorizedWithRoleGroup.GET("/stats/suppliers", controllers.StatsSuppliers)
 
 	// News on homepage
-	authorizedWithRoleGroup.POST("/posts", controllers.CreatePost)
-	authorizedWithRoleGroup.PUT("/posts/:post_id", controllers.UpdatePost)
+	authorizedWithRoleGroup.POST("/posts", controllers.CreatePost, purgePost)
+	authorizedWithRoleGroup.PUT("/posts/:post_id", controllers.UpdatePost, purgePost)
 	authorizedWithRoleGroup.GET("/posts", controllers.PaginatePost)
 	authorizedWithRoleGroup.GET("/posts/:slug", controllers.GetPost)
-	authorizedWithRoleGroup.DELETE("/posts/:post_id/delete", controllers.DeletePost)
-	authorizedWithRoleGroup.PATCH("/posts/slug/generate", controllers.GeneratePostSlug)
+	authorizedWithRoleGroup.DELETE("/posts/:post_id/delete", controllers.DeletePost, purgePost)
+	authorizedWithRoleGroup.PATCH("/posts/slug/generate", controllers.GeneratePostSlug, purgePost)
 
 	// Blog category
 	authorizedWithRoleGroup.GET("/blog/categories", controllers.PaginateBlogCategory)
-	authorizedWithRoleGroup.POST("/blog/categories", controllers.CreateBlogCategory)
-	authorizedWithRoleGroup.PUT("/blog/categories/:blog_category_id", controllers.UpdateBlogCategory)
-	authorizedWithRoleGroup.DELETE("/blog/categories/:blog_category_id", controllers.DeleteBlogCategory)
+	authorizedWithRoleGroup.POST("/blog/categories", controllers.CreateBlogCategory, purgePost)
+	authorizedWithRoleGroup.PUT("/blog/categories/:blog_category_id", controllers.UpdateBlogCategory, purgePost)
+	authorizedWithRoleGroup.DELETE("/blog/categories/:blog_category_id", controllers.DeleteBlogCategory, purgePost)
 
 	// Inquiries
 	authorizedWithRoleGroup.GET("/inquiries", controllers.AdminPaginateInquiry)
@@ -147,8 +159,8 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/mark_as_unpaid", controllers.AdminInquiryMarkAsUnpaid)
 
 	authorizedWithRoleGroup.PUT("/inquiries/:inquiry_id/assign_pic", controllers.AdminInquiryAssignPIC)
-	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/payment_link", controllers.AdminInquiryCreatePaymentLink) //legacy
-	authorizedWithRoleGroup.POST("/inquiries/payment_link", controllers.AdminCreateBuyerPaymentLink)
+	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/payment_link", controllers.AdminInquiryCreatePaymentLink, idempotent) //legacy
+	authorizedWithRoleGroup.POST("/inquiries/payment_link", controllers.AdminCreateBuyerPaymentLink, idempotent)
 
 	// Sync old inquiry already paid from customer
 	// Will be bypass some steps payment from user
@@ -245,7 +257,7 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.POST("/purchase_orders/:purchase_order_id/design_comments", controllers.AdminPurchaseOrderAddDesignComments)
 	authorizedWithRoleGroup.PUT("/purchase_orders/:purchase_order_id/design_comments/mark_seen", controllers.AdminPurchaseOrderDesignCommentMarkSeen)
 	authorizedWithRoleGroup.GET("/purchase_orders/:purchase_order_id/design_comments/status_count", controllers.AdminPurchaseOrderDesignCommentStatusCount)
-	authorizedWithRoleGroup.POST("/purchase_orders/payment_link", controllers.AdminMultiPurchaseOrderCreatePaymentLink)
+	authorizedWithRoleGroup.POST("/purchase_orders/payment_link", controllers.AdminMultiPurchaseOrderCreatePaymentLink, idempotent)
 
 	authorizedWithRoleGroup.PUT("/purchase_orders/:purchase_order_id/assign_pic", controllers.AdminPurchaseOrderAssignPIC)
 	authorizedWithRoleGroup.DELETE("/purchase_orders/:purchase_order_id/archive", controllers.AdminArchivePurchaseOrder)
@@ -270,6 +282,7 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	// Bulk purchase order
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders", controllers.AdminPaginateBulkPurchaseOrder)
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders/export", controllers.AdminExportBulkPurchaseOrder)
+	authorizedWithRoleGroup.GET("/bulk_purchase_orders/state_machine", controllers.AdminGetBulkPurchaseOrderStateMachine)
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders/:bulk_purchase_order_id", controllers.AdminGetBulkPurchaseOrder)
 	authorizedWithRoleGroup.PUT("/bulk_purchase_orders/:bulk_purchase_order_id", controllers.AdminUpdateBulkPurchaseOrder)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/send_quotation", controllers.AdminSendQuotationToBuyer) //deprecated
@@ -292,6 +305,10 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.PUT("/bulk_purchase_orders/:bulk_purchase_order_id/assign_pic", controllers.AdminBulkPurchaseOrderAssignPIC)
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders/:bulk_purchase_order_id/sample_po", controllers.AdminBulkPurchaseOrderGetSamplePO)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/invoice", controllers.AdminCreateBulkPurchaseInvoice)
+	authorizedWithRoleGroup.GET("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones", controllers.AdminGetBulkPurchaseOrderPaymentMilestones)
+	authorizedWithRoleGroup.PUT("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones", controllers.AdminUpdateBulkPurchaseOrderPaymentMilestones)
+	authorizedWithRoleGroup.PUT("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones/:payment_milestone_id/mark_as_paid", controllers.AdminPaymentMilestoneMarkAsPaid)
+	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones/:payment_milestone_id/invoice", controllers.AdminCreatePaymentMilestoneInvoice)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/preview_checkout", controllers.AdminBulkPurchaseOrderPreviewCheckout)
 	authorizedWithRoleGroup.PUT("/bulk_purchase_orders/:bulk_purchase_order_id/reset", controllers.AdminResetBulkPurchaseOrder)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/notes", controllers.AdminBulkPurchaseOrderAddNote)
@@ -362,10 +379,10 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 
 	authorizedWithRoleGroup.GET("/settings/seo", controllers.PaginateSettingSEO)
 	authorizedWithRoleGroup.GET("/settings/seo/group", controllers.PaginateSettingRouteGroupSEO)
-	authorizedWithRoleGroup.POST("/settings/seo", controllers.CreateSettingSEO)
-	authorizedWithRoleGroup.PUT("/settings/seo/:id", controllers.UpdateSettingSEO)
-	authorizedWithRoleGroup.DELETE("/settings/seo/:id", controllers.DeleteSettingSEO)
-	authorizedWithRoleGroup.PATCH("/settings/seo/translations", controllers.PatchSEOTranslations)
+	authorizedWithRoleGroup.POST("/settings/seo", controllers.CreateSettingSEO, purgeSEO)
+	authorizedWithRoleGroup.PUT("/settings/seo/:id", controllers.UpdateSettingSEO, purgeSEO)
+	authorizedWithRoleGroup.DELETE("/settings/seo/:id", controllers.DeleteSettingSEO, purgeSEO)
+	authorizedWithRoleGroup.PATCH("/settings/seo/translations", controllers.PatchSEOTranslations, purgeSEO)
 
 	authorizedWithRoleGroup.POST("/settings/docs", controllers.CreateSettingDoc)
 	authorizedWithRoleGroup.GET("/settings/docs/:type", controllers.GetSettingDoc)
@@ -408,6 +425,29 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.POST("/invoices", controllers.CreateInvoice)
 	authorizedWithRoleGroup.PUT("/invoices/:invoice_number", controllers.UpdateInvoice)
 	authorizedWithRoleGroup.GET("/invoices/:invoice_number/attachment", controllers.GetInvoiceAttachment)
+	authorizedWithRoleGroup.GET("/invoices/:invoice_number/credit_notes", controllers.GetCreditNotes)
+	authorizedWithRoleGroup.POST("/invoices/:invoice_number/credit_notes", controllers.CreateCreditNote)
+	authorizedWithRoleGroup.PUT("/invoices/:invoice_number/void", controllers.VoidInvoice)
+	authorizedWithRoleGroup.GET("/invoice_series", controllers.GetInvoiceSeriesList)
+	authorizedWithRoleGroup.POST("/invoice_series", controllers.ReserveInvoiceSeries)
+	authorizedWithRoleGroup.GET("/fx_rates", controllers.GetFxRates)
+	authorizedWithRoleGroup.POST("/fx_rates", controllers.CreateFxRate)
+	authorizedWithRoleGroup.POST("/fx_rates/import", controllers.ImportFxRates)
+	authorizedWithRoleGroup.DELETE("/fx_rates/:fx_rate_id", controllers.DeleteFxRate)
+
+	// Ledger
+	authorizedWithRoleGroup.GET("/ledger/journal_entries", controllers.PaginateJournalEntries)
+	authorizedWithRoleGroup.POST("/ledger/journal_entries/post", controllers.PostJournalEntries)
+	authorizedWithRoleGroup.GET("/ledger/journal_entries/export", controllers.ExportJournalEntries)
+	authorizedWithRoleGroup.GET("/ledger/trial_balance", controllers.GetTrialBalance)
+
+	// Bank reconciliation
+	authorizedWithRoleGroup.GET("/bank_statements", controllers.GetBankStatements)
+	authorizedWithRoleGroup.POST("/bank_statements/import", controllers.ImportBankStatement)
+	authorizedWithRoleGroup.POST("/bank_statements/:bank_statement_id/match", controllers.MatchBankStatement)
+	authorizedWithRoleGroup.GET("/bank_statements/lines", controllers.PaginateBankStatementLines)
+	authorizedWithRoleGroup.PUT("/bank_statements/lines/:bank_statement_line_id/approve", controllers.ApproveBankStatementLine)
+	authorizedWithRoleGroup.PUT("/bank_statements/lines/:bank_statement_line_id/ignore", controllers.IgnoreBankStatementLine)
 
 	// Invoice
 	authorizedWithRoleGroup.GET("/analytics/inquiries/potential_overdue", controllers.PaginatePotentialOverdueInquiries)
@@ -479,7 +519,13 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.POST("/tnas", controllers.AdminCreateTNA)
 	authorizedWithRoleGroup.PUT("/tnas/:id", controllers.AdminUpdateTNA)
 	authorizedWithRoleGroup.GET("/tnas", controllers.AdminPaginateTNA)
+	authorizedWithRoleGroup.GET("/tnas/plan", controllers.AdminGetTNAPlan)
 	authorizedWithRoleGroup.DELETE("/tnas/:id", controllers.AdminDeleteTNA)
+	authorizedWithRoleGroup.GET("/tna_templates", controllers.GetTNATemplates)
+	authorizedWithRoleGroup.POST("/tna_templates", controllers.CreateTNATemplate)
+	authorizedWithRoleGroup.PUT("/tna_templates/:tna_template_id", controllers.UpdateTNATemplate)
+	authorizedWithRoleGroup.DELETE("/tna_templates/:tna_template_id", controllers.DeleteTNATemplate)
+	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/tnas/from_template", controllers.AdminInstantiateBulkPurchaseOrderTNA)
 
 	// Release Notes
 	authorizedWithRoleGroup.POST("/release_notes", controllers.CreateReleaseNote)
@@ -506,6 +552,11 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	// Chat
 	authorizedWithRoleGroup.POST("/chat_messages", controllers.AdminCreateChatMessage)
 	authorizedWithRoleGroup.GET("/chat_messages", controllers.AdminGetChatMessageList)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id", controllers.AdminUpdateChatMessage)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id", controllers.AdminDeleteChatMessage)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id/reactions", controllers.AdminAddChatMessageReaction)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id/reactions", controllers.AdminRemoveChatMessageReaction)
+	authorizedWithRoleGroup.GET("/chat_messages/:chat_message_id/edits", controllers.AdminGetChatMessageEdits)
 	authorizedWithRoleGroup.GET("/chat_messages/unseen_messages", controllers.AdminCountUnseenChatMessage)
 	//Chat Room
 	authorizedWithRoleGroup.GET("/chat_rooms/relevant_stage", controllers.AdminGetChatUserRelevantStage)
@@ -520,9 +571,22 @@ func (router *Router) SetupAdminRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.POST("/order_groups/assign", controllers.AdminAssignOrderGroup)
 	// Order Cart
 	authorizedWithRoleGroup.POST("/order_cart/:buyer_id/preview", controllers.GetBuyerOrderCartPreview)
-	authorizedWithRoleGroup.POST("/order_cart/:buyer_id/create_payment_link", controllers.CreateBuyerPaymentLink)
+	authorizedWithRoleGroup.POST("/order_cart/:buyer_id/create_payment_link", controllers.CreateBuyerPaymentLink, idempotent)
 
 	// Product File Upload Info
 	authorizedWithRoleGroup.POST("/product_file_upload_infos/upload", controllers.UploadProductFile)
 	authorizedWithRoleGroup.GET("/product_file_upload_infos", controllers.GetProductFileUploadInfoList)
+
+	// Outbox
+	authorizedWithRoleGroup.GET("/outbox_messages", controllers.PaginateOutboxMessages)
+	authorizedWithRoleGroup.PUT("/outbox_messages/:outbox_message_id/retry", controllers.RetryOutboxMessage)
+
+	// Dead letter
+	authorizedWithRoleGroup.GET("/dead_letter_tasks", controllers.PaginateDeadLetterTasks)
+	authorizedWithRoleGroup.GET("/dead_letter_tasks/rate", controllers.GetDeadLetterTaskRate)
+	authorizedWithRoleGroup.POST("/dead_letter_tasks/replay", controllers.ReplayDeadLetterTasks)
+	authorizedWithRoleGroup.GET("/dead_letter_tasks/:dead_letter_task_id", controllers.GetDeadLetterTask)
+	authorizedWithRoleGroup.PUT("/dead_letter_tasks/:dead_letter_task_id/payload", controllers.UpdateDeadLetterTaskPayload)
+	authorizedWithRoleGroup.PUT("/dead_letter_tasks/:dead_letter_task_id/replay", controllers.ReplayDeadLetterTask)
+	authorizedWithRoleGroup.PUT("/dead_letter_tasks/:dead_letter_task_id/discard", controllers.DiscardDeadLetterTask)
 }
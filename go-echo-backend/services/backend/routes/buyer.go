@@ -11,6 +11,8 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	var authorizedWithRoleGroup = authorizedGroup.Group("", router.Middlewares.CheckRole(enums.RoleClient))
 	var authorizedWithUserGroup = authorizedGroup.Group("", router.Middlewares.CheckTokenExpiredAndAttachUserInfo())
 
+	var idempotent = router.Middlewares.Idempotency()
+
 	authorizedWithUserGroup.POST("/me/track_activity", controllers.TrackActivity)
 
 	authorizedWithRoleGroup.GET("/products/get_category_tree", controllers.GetCategoryTree)
@@ -73,13 +75,13 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.GET("/inquiries/:inquiry_id/cart_items", controllers.BuyerInquiryCartItems)
 	authorizedWithRoleGroup.DELETE("/inquiries/:inquiry_id/remove_items", controllers.BuyerInquiryRemoveItems)
 	authorizedWithRoleGroup.POST("/inquiry_carts/preview_checkout", controllers.BuyerMultiInquiryPreviewCheckout)
-	authorizedWithRoleGroup.POST("/inquiry_carts/checkout", controllers.BuyerMultiInquiryCheckout)
+	authorizedWithRoleGroup.POST("/inquiry_carts/checkout", controllers.BuyerMultiInquiryCheckout, idempotent)
 	authorizedWithRoleGroup.GET("/inquiry_carts/checkout_info", controllers.BuyerMultiInquiryCheckoutInfo)
 	authorizedWithRoleGroup.PUT("/inquiries/:inquiry_id/attachments", controllers.BuyerInquiryUpdateAttachments)
 	authorizedWithRoleGroup.PUT("/inquiries/:inquiry_id/close", controllers.BuyerCloseInquiry)
 	authorizedWithRoleGroup.DELETE("/inquiries/:inquiry_id/cancel", controllers.BuyerCancelInquiry)
 	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/preview_checkout", controllers.BuyerInquiryPreviewCheckout)
-	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/checkout", controllers.BuyerInquiryCheckout)
+	authorizedWithRoleGroup.POST("/inquiries/:inquiry_id/checkout", controllers.BuyerInquiryCheckout, idempotent)
 
 	authorizedWithRoleGroup.PUT("/inquiries/:inquiry_id/logs", controllers.BuyerUpdateInquiryLogs)
 	authorizedWithRoleGroup.DELETE("/inquiries/:inquiry_id/logs", controllers.BuyerDeleteInquiryLogs)
@@ -88,7 +90,7 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.PUT("/catalog_carts", controllers.BuyerUpdateCatalogCarts)
 	authorizedWithRoleGroup.POST("/catalog_carts/place_orders", controllers.BuyerCreateCatalogCartsOrders)
 	authorizedWithRoleGroup.POST("/catalog_carts/checkout_info", controllers.BuyerMultiCatalogCartCheckoutInfo)
-	authorizedWithRoleGroup.POST("/catalog_carts/checkout", controllers.BuyerMultiCatalogCartCheckout)
+	authorizedWithRoleGroup.POST("/catalog_carts/checkout", controllers.BuyerMultiCatalogCartCheckout, idempotent)
 
 	authorizedWithRoleGroup.GET("/purchase_orders", controllers.PaginatePurchaseOrders)
 	authorizedWithRoleGroup.PUT("/purchase_orders/:purchase_order_id", controllers.UpdatePurchaseOrder)
@@ -120,8 +122,10 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders", controllers.PaginateBulkPurchaseOrder)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/preview_checkout", controllers.BulkPurchaseOrdersPreviewCheckout)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/preview_checkout", controllers.BulkPurchaseOrderPreviewCheckout) //deprecated
-	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/checkout", controllers.BulkPurchaseOrderCheckout)                //deprecated
+	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/checkout", controllers.BulkPurchaseOrderCheckout, idempotent)    //deprecated
 	authorizedWithRoleGroup.GET("/bulk_purchase_orders/:bulk_purchase_order_id/logs", controllers.PaginateBulkPurchaseOrderTracking)
+	authorizedWithRoleGroup.GET("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones", controllers.GetBulkPurchaseOrderPaymentMilestones)
+	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/payment_milestones/:payment_milestone_id/checkout", controllers.PaymentMilestoneCheckout, idempotent)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/approve_qc", controllers.BulkPurchaseBuyerApproveQc)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/approve_raw_material", controllers.BulkPurchaseBuyerApproveRawMaterial)
 	authorizedWithRoleGroup.POST("/bulk_purchase_orders/:bulk_purchase_order_id/confirm_delivered", controllers.BuyerBulkPurchaseOrderConfirmDelivered)
@@ -174,6 +178,10 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	// Chat
 	authorizedWithRoleGroup.POST("/chat_messages", controllers.BuyerCreateChatMessage)
 	authorizedWithRoleGroup.GET("/chat_messages", controllers.BuyerGetChatMessageList)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id", controllers.BuyerUpdateChatMessage)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id", controllers.BuyerDeleteChatMessage)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id/reactions", controllers.BuyerAddChatMessageReaction)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id/reactions", controllers.BuyerRemoveChatMessageReaction)
 
 	// Chat Room
 	authorizedWithRoleGroup.GET("/chat_rooms/relevant_stage", controllers.BuyerGetChatUserRelevantStage)
@@ -189,7 +197,7 @@ func (router *Router) SetupBuyerRoutes(g *echo.Group) {
 	// Order Cart
 	authorizedWithRoleGroup.GET("/order_cart", controllers.BuyerGetOrderCart)
 	authorizedWithRoleGroup.POST("/order_cart/preview_checkout", controllers.BuyerGetOrderCartPreviewCheckout)
-	authorizedWithRoleGroup.POST("/order_cart/checkout", controllers.BuyerOrderCartCheckout)
+	authorizedWithRoleGroup.POST("/order_cart/checkout", controllers.BuyerOrderCartCheckout, idempotent)
 	authorizedWithRoleGroup.POST("/order_cart/checkout_info", controllers.BuyerOrderCartGetCheckoutInfo)
 
 	// Analytics
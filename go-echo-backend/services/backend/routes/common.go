@@ -1,7 +1,10 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/middlewares"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	controllers "github.com/engineeringinflow/inflow-backend/services/backend/controllers/common"
 	"github.com/labstack/echo/v4"
@@ -14,6 +17,8 @@ func (router *Router) SetupCommonRoutes(g *echo.Group) {
 
 	var authorizedRSA = g.Group("")
 
+	var cacheSEO = router.Middlewares.CacheResponse(time.Minute*30, models.CacheTagSEOTranslation, models.CacheTagSettingSEO)
+
 	g.GET("/attachments/:file_key", controllers.GetAttachment)
 	g.GET("/blur_attachments/:file_key", controllers.GetBlurAttachment)
 	g.GET("/thumbnail_attachments/:file_key", controllers.GetThumbnailAttachment)
@@ -25,8 +30,8 @@ func (router *Router) SetupCommonRoutes(g *echo.Group) {
 	authorizedRSA.GET("/constants", controllers.GetConstants)
 	authorizedRSA.GET("/register_constants", controllers.GetRegisterConstants)
 	authorizedRSA.POST("/check_exists", controllers.CheckExists)
-	authorizedRSA.GET("/seo/translations", controllers.GetSEOTranslations)
-	authorizedRSA.GET("/seo/translations/by_route", controllers.GetSettingSEOByRouteName)
+	authorizedRSA.GET("/seo/translations", controllers.GetSEOTranslations, cacheSEO)
+	authorizedRSA.GET("/seo/translations/by_route", controllers.GetSettingSEOByRouteName, cacheSEO)
 	authorizedRSA.GET("/buyer/seo/translations", controllers.GetBuyerSEOTranslations)
 	authorizedRSA.GET("/seller/seo/translations", controllers.GetSellerSEOTranslations)
 	authorizedRSA.GET("/admin/seo/translations", controllers.GetAdminSEOTranslations)
@@ -3,12 +3,14 @@ package routes
 import (
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/labstack/echo/v4"
 )
 
 // SetupAppInfoRoute setup root's routes
 func (router *Router) SetupAppInfoRoute(g *echo.Group) {
 	g.GET("/config", configHandler)
+	g.GET("/locker_stats", lockerStatsHandler, router.Middlewares.IsAuthorized(), router.Middlewares.CheckRole(enums.RoleSuperAdmin, enums.RoleLeader, enums.RoleStaff))
 
 }
 
@@ -39,3 +41,9 @@ func configHandler(c echo.Context) error {
 	return cc.Success(cc.App.Config)
 
 }
+
+func lockerStatsHandler(c echo.Context) error {
+	var cc = c.(*models.CustomContext)
+	return cc.Success(cc.App.DB.Locker.Stats())
+
+}
@@ -125,6 +125,10 @@ func (router *Router) SetupSellerRoutes(g *echo.Group) {
 	// Chat
 	authorizedWithRoleGroup.POST("/chat_messages", controllers.SellerCreateChatMessage)
 	authorizedWithRoleGroup.GET("/chat_messages", controllers.SellerGetChatMessageList)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id", controllers.SellerUpdateChatMessage)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id", controllers.SellerDeleteChatMessage)
+	authorizedWithRoleGroup.PUT("/chat_messages/:chat_message_id/reactions", controllers.SellerAddChatMessageReaction)
+	authorizedWithRoleGroup.DELETE("/chat_messages/:chat_message_id/reactions", controllers.SellerRemoveChatMessageReaction)
 
 	// Chat Room
 	authorizedWithRoleGroup.GET("/chat_rooms/relevant_stage", controllers.SellerGetChatUserRelevantStage)
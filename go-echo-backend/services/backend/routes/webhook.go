@@ -15,5 +15,6 @@ func (router *Router) SetupWebhookRoutes(g *echo.Group) {
 
 	g.POST("/stripe", controllers.StripeWebhook)
 	g.POST("/hubspot", controllers.HubspotWebhook)
+	g.POST("/payos", controllers.PayosWebhook)
 
 }
@@ -1,6 +1,9 @@
 package routes
 
 import (
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	controllers "github.com/engineeringinflow/inflow-backend/services/backend/controllers/website"
 	"github.com/labstack/echo/v4"
 )
@@ -9,11 +12,17 @@ func (router *Router) SetupWebsiteRoutes(g *echo.Group) {
 	// var authorizedRSA = g.Group("", router.Middlewares.IsValidSignature())
 	var authorizedRSA = g.Group("")
 
+	var cacheCategory = router.Middlewares.CacheResponse(time.Minute*10, models.CacheTagCategory, models.CacheTagProduct)
+	var cacheProduct = router.Middlewares.CacheResponse(time.Minute, models.CacheTagProduct)
+	var cacheCollection = router.Middlewares.CacheResponse(time.Minute*10, models.CacheTagCollection, models.CacheTagProduct)
+	var cachePage = router.Middlewares.CacheResponse(time.Minute*10, models.CacheTagPage, models.CacheTagCategory, models.CacheTagCollection, models.CacheTagProduct)
+	var cachePost = router.Middlewares.CacheResponse(time.Minute*10, models.CacheTagPost)
+
 	authorizedRSA.POST("/subscribe", controllers.SubscribeUpdates)
 
-	authorizedRSA.GET("/products/get_category_tree", controllers.GetCategoryTree)
-	authorizedRSA.GET("/products/search", controllers.SearchProduct)
-	authorizedRSA.GET("/products/get", controllers.ProductGetDetail)
+	authorizedRSA.GET("/products/get_category_tree", controllers.GetCategoryTree, cacheCategory)
+	authorizedRSA.GET("/products/search", controllers.SearchProduct, cacheProduct)
+	authorizedRSA.GET("/products/get", controllers.ProductGetDetail, cacheProduct)
 	authorizedRSA.GET("/products/get_ratings", controllers.ProductGetRatings)
 	authorizedRSA.GET("/products/best_selling", controllers.ProductGetBestSelling)
 	authorizedRSA.GET("/products/just_for_you", controllers.ProductGetJustForYou)
@@ -24,19 +33,19 @@ func (router *Router) SetupWebsiteRoutes(g *echo.Group) {
 
 	authorizedRSA.POST("/support/factory_tours/create", controllers.CreateFactoryTour)
 
-	authorizedRSA.GET("/collections", controllers.PaginateCollection)
-	authorizedRSA.GET("/collections/ready_design", controllers.CollectionReadyDesign)
-	authorizedRSA.GET("/collections/:collection_id/get_product", controllers.CollectionGetProduct)
-	authorizedRSA.GET("/collections/:collection_id/get", controllers.CollectionDetail)
+	authorizedRSA.GET("/collections", controllers.PaginateCollection, cacheCollection)
+	authorizedRSA.GET("/collections/ready_design", controllers.CollectionReadyDesign, cacheCollection)
+	authorizedRSA.GET("/collections/:collection_id/get_product", controllers.CollectionGetProduct, cacheCollection)
+	authorizedRSA.GET("/collections/:collection_id/get", controllers.CollectionDetail, cacheCollection)
 
-	authorizedRSA.GET("/pages/catalog", controllers.PageCatalog)
-	authorizedRSA.GET("/pages/home", controllers.PageHome)
+	authorizedRSA.GET("/pages/catalog", controllers.PageCatalog, cachePage)
+	authorizedRSA.GET("/pages/home", controllers.PageHome, cachePage)
 
-	authorizedRSA.GET("/posts", controllers.PaginatePost)
-	authorizedRSA.GET("/posts/:slug", controllers.GetPost)
+	authorizedRSA.GET("/posts", controllers.PaginatePost, cachePost)
+	authorizedRSA.GET("/posts/:slug", controllers.GetPost, cachePost)
 	authorizedRSA.GET("/posts/stats", controllers.GetPostStats)
-	authorizedRSA.GET("/blog/categories/:blog_category_id", controllers.GetBlogCategory)
-	authorizedRSA.GET("/blog/categories", controllers.PaginateBlogCategory)
+	authorizedRSA.GET("/blog/categories/:blog_category_id", controllers.GetBlogCategory, cachePost)
+	authorizedRSA.GET("/blog/categories", controllers.PaginateBlogCategory, cachePost)
 
 	authorizedRSA.GET("/documents", controllers.GetDocumentList)
 	authorizedRSA.GET("/documents/:slug", controllers.GetDocument)
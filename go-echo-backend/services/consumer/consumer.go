@@ -2,7 +2,6 @@ package consumer
 
 import (
 	"context"
-	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/app"
 	"github.com/engineeringinflow/inflow-backend/pkg/logger"
@@ -10,7 +9,6 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/ws"
 	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
 	"github.com/rotisserie/eris"
-	"github.com/thaitanloi365/go-utils/values"
 )
 
 var instance *worker.Worker
@@ -37,22 +35,18 @@ func WSMessageHandler(message ws.Message) error {
 	}
 	switch message.Type {
 	case ws.MessageTypePing:
-		tasks.UserPingTask{
-			UserID:       message.UserID,
-			IsOffline:    values.Bool(false),
-			LastOnlineAt: values.Int64(time.Now().Unix()),
-		}.Dispatch(context.Background())
+		// Presence is refreshed by the ws hub, the user status is only persisted when they come online or go offline
 
 	case ws.MessageTypeTyping:
-		tasks.ChatTypingTask{
+		tasks.ChatTypingTask.Dispatch(context.Background(), tasks.ChatTypingPayload{
 			RoomID:       message.Data.ChatRoomID,
 			TypingUserID: message.UserID,
-		}.Dispatch(context.Background())
+		})
 	case ws.MessageTypeCancelTyping:
-		tasks.CancelChatTypingTask{
+		tasks.CancelChatTypingTask.Dispatch(context.Background(), tasks.CancelChatTypingPayload{
 			RoomID:             message.Data.ChatRoomID,
 			CancelTypingUserID: message.UserID,
-		}.Dispatch(context.Background())
+		})
 
 	case ws.MessageTypeSeenMessage:
 
@@ -67,6 +67,9 @@ func (router *Router) startServer() {
 	if err := router.Shutdown(ctx); err != nil {
 		router.Logger.Fatal(err)
 	}
+	if err := ws.GetInstance().Close(); err != nil {
+		router.Logger.Error(err)
+	}
 }
 
 func (router *Router) SetupRoutes() {
@@ -2,52 +2,30 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"strings"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type AddCustomerIOUserDeviceTask struct {
+type AddCustomerIOUserDevicePayload struct {
 	UserID string            `json:"user_id" validate:"required"`
 	Device *models.PushToken `json:"device" validate:"required"`
 }
 
-func (task AddCustomerIOUserDeviceTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task AddCustomerIOUserDeviceTask) TaskName() string {
-	return "add_customerio_user_device"
-}
-
-// Handler handler
-func (task AddCustomerIOUserDeviceTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
-	var platform = strings.ToLower(task.Device.Platform)
+var AddCustomerIOUserDeviceTask = worker.NewTaskType("add_customerio_user_device", 1, func(ctx context.Context, payload AddCustomerIOUserDevicePayload) error {
+	var err error
+	var platform = strings.ToLower(payload.Device.Platform)
 
 	if platform == "web" {
 		platform = "android"
 	}
 
-	err = workerInstance.App.CustomerIOClient.Track.AddDevice(task.UserID, task.Device.Token, platform, map[string]interface{}{
-		"last_used": task.Device.LastUsed,
-		"user_id":   task.Device.UserID,
-		"is_web":    task.Device.Platform == "web",
+	err = workerInstance.App.CustomerIOClient.Track.AddDevice(payload.UserID, payload.Device.Token, platform, map[string]interface{}{
+		"last_used": payload.Device.LastUsed,
+		"user_id":   payload.Device.UserID,
+		"is_web":    payload.Device.Platform == "web",
 	})
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task AddCustomerIOUserDeviceTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(customerIOTaskPolicy))
@@ -2,38 +2,16 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type AdminApproveSellerQuotationTask struct {
+type AdminApproveSellerQuotationPayload struct {
 	AdminID         string `json:"admin_id" validate:"required"`
 	InquirySellerID string `json:"inquiry_seller_id" validate:"required"`
 }
 
-func (task AdminApproveSellerQuotationTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task AdminApproveSellerQuotationTask) TaskName() string {
-	return "admin_approve_seller_quotation"
-}
-
-// Handler handler
-func (task AdminApproveSellerQuotationTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var AdminApproveSellerQuotationTask = worker.NewTaskType("admin_approve_seller_quotation", 1, func(ctx context.Context, payload AdminApproveSellerQuotationPayload) error {
+	var err error
 	return err
-}
-
-// Dispatch dispatch event
-func (task AdminApproveSellerQuotationTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -2,38 +2,16 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type AdminRejectSellerBulkPurchaseOrderQuotation struct {
+type AdminRejectSellerBulkPurchaseOrderQuotationPayload struct {
 	AdminID           string `json:"admin_id" validate:"required"`
 	SellerQuotationID string `json:"seller_quotation_id" validate:"required"`
 }
 
-func (task AdminRejectSellerBulkPurchaseOrderQuotation) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task AdminRejectSellerBulkPurchaseOrderQuotation) TaskName() string {
-	return "admin_reject_seller_bulk_purchase_order_quotation"
-}
-
-// Handler handler
-func (task AdminRejectSellerBulkPurchaseOrderQuotation) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var AdminRejectSellerBulkPurchaseOrderQuotation = worker.NewTaskType("admin_reject_seller_bulk_purchase_order_quotation", 1, func(ctx context.Context, payload AdminRejectSellerBulkPurchaseOrderQuotationPayload) error {
+	var err error
 	return err
-}
-
-// Dispatch dispatch event
-func (task AdminRejectSellerBulkPurchaseOrderQuotation) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
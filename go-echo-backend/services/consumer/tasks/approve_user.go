@@ -4,40 +4,22 @@ import (
 	"context"
 	"fmt"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type ApproveUserTask struct {
+type ApproveUserPayload struct {
 	UserID string `json:"user_id" validate:"required"`
 }
 
-func (task ApproveUserTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task ApproveUserTask) TaskName() string {
-	return "approve_user"
-}
-
-// Handler handler
-func (task ApproveUserTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var ApproveUserTask = worker.NewTaskType("approve_user", 1, func(ctx context.Context, payload ApproveUserPayload) error {
+	var err error
 	var user models.User
-	err = workerInstance.App.DB.Select("Name", "Email", "ID", "Role").First(&user, "id = ?", task.UserID).Error
+	err = workerInstance.App.DB.Select("Name", "Email", "ID", "Role").First(&user, "id = ?", payload.UserID).Error
 	if err != nil {
 		return err
 	}
@@ -58,28 +40,23 @@ func (task ApproveUserTask) Handler(ctx context.Context, t *asynq.Task) error {
 		"user_name": user.Name,
 	}
 
-	TrackCustomerIOTask{
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 		UserID: user.ID,
 		Event:  eventName,
 		Data:   data,
-	}.Dispatch(ctx)
+	})
 
-	TrackCustomerIOTask{
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 		UserID: user.ID,
 		Event:  customerio.EventWelcomeToBoard,
 		Data:   data,
-	}.Dispatch(ctx)
+	})
 
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
 
-	t.ResultWriter().Write(helper.ToJson(&data))
+	worker.WriteResult(ctx, helper.ToJson(&data))
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task ApproveUserTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -2,17 +2,17 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"math"
+	"time"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/jinzhu/copier"
-	"math"
-	"time"
 )
 
-type BulkPurchaseOrderRawMaterialApproveTask struct {
+type BulkPurchaseOrderRawMaterialApprovePayload struct {
 	models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string `json:"purchase_order_id" param:"purchase_order_id" query:"purchase_order_id" validate:"required"`
@@ -20,26 +20,11 @@ type BulkPurchaseOrderRawMaterialApproveTask struct {
 	ItemIDs []string `json:"item_ids" param:"item_ids" query:"item_ids"`
 }
 
-func (task BulkPurchaseOrderRawMaterialApproveTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task BulkPurchaseOrderRawMaterialApproveTask) TaskName() string {
-	return "bulk_purchase_order_raw_material_approve"
-}
-
-// Handler handler
-func (task BulkPurchaseOrderRawMaterialApproveTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var BulkPurchaseOrderRawMaterialApproveTask = worker.NewTaskType("bulk_purchase_order_raw_material_approve", 1, func(ctx context.Context, payload BulkPurchaseOrderRawMaterialApprovePayload) (err error) {
 	now := time.Now().Unix()
 	var bpo models.BulkPurchaseOrder
 	if err = workerInstance.App.DB.Model(&models.BulkPurchaseOrder{}).
-		Select("approve_raw_material_at").Where("id = ?", task.BulkPurchaseOrderID).First(&bpo).Error; err != nil {
+		Select("approve_raw_material_at").Where("id = ?", payload.BulkPurchaseOrderID).First(&bpo).Error; err != nil {
 		return
 	}
 	if bpo.ApproveRawMaterialAt != nil {
@@ -50,19 +35,10 @@ func (task BulkPurchaseOrderRawMaterialApproveTask) Handler(ctx context.Context,
 	}
 
 	var params repo.BulkPurchaseBuyerApproveRawMaterialParams
-	err = copier.Copy(&params, &task)
+	err = copier.Copy(&params, &payload)
 	if err != nil {
 		return err
 	}
 	_, err = repo.NewBulkPurchaseOrderRepo(workerInstance.App.DB).BulkPurchaseOrderBuyerApproveRawMaterial(params)
 	return err
-}
-
-// Dispatch dispatch event
-func (task BulkPurchaseOrderRawMaterialApproveTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
-
-func (task BulkPurchaseOrderRawMaterialApproveTask) DispatchAt(at time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskAt(task, at, opts...)
-}
+})
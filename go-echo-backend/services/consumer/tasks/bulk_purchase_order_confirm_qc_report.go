@@ -2,18 +2,18 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"math"
+	"time"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/jinzhu/copier"
-	"math"
-	"time"
 )
 
-type BulkPurchaseQCApproveTask struct {
+type BulkPurchaseQCApprovePayload struct {
 	models.JwtClaimsInfo
 
 	BulkPurchaseOrderID string                     `json:"bulk_purchase_order_id" param:"bulk_purchase_order_id" query:"bulk_purchase_order_id" validate:"required"`
@@ -23,26 +23,11 @@ type BulkPurchaseQCApproveTask struct {
 	UserID string `json:"-"`
 }
 
-func (task BulkPurchaseQCApproveTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task BulkPurchaseQCApproveTask) TaskName() string {
-	return "bulk_purchase_order_qc_approve"
-}
-
-// Handler handler
-func (task BulkPurchaseQCApproveTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var BulkPurchaseQCApproveTask = worker.NewTaskType("bulk_purchase_order_qc_approve", 1, func(ctx context.Context, payload BulkPurchaseQCApprovePayload) (err error) {
 	now := time.Now().Unix()
 	var bpo models.BulkPurchaseOrder
 	if err = workerInstance.App.DB.Model(&models.BulkPurchaseOrder{}).
-		Select("approve_qc_at").Where("id = ?", task.BulkPurchaseOrderID).First(&bpo).Error; err != nil {
+		Select("approve_qc_at").Where("id = ?", payload.BulkPurchaseOrderID).First(&bpo).Error; err != nil {
 		return
 	}
 	if bpo.ApproveQCAt != nil {
@@ -53,7 +38,7 @@ func (task BulkPurchaseQCApproveTask) Handler(ctx context.Context, t *asynq.Task
 	}
 
 	var params repo.BulkPurchaseOrderUpdateTrackingStatusParams
-	err = copier.Copy(&params, &task)
+	err = copier.Copy(&params, &payload)
 	if err != nil {
 		return err
 	}
@@ -62,13 +47,4 @@ func (task BulkPurchaseQCApproveTask) Handler(ctx context.Context, t *asynq.Task
 	params.TrackingStatus = enums.BulkPoTrackingStatusSubmit
 	_, err = repo.NewBulkPurchaseOrderRepo(workerInstance.App.DB).BulkPurchaseOrderUpdateTrackingStatus(params)
 	return err
-}
-
-// Dispatch dispatch event
-func (task BulkPurchaseQCApproveTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
-
-func (task BulkPurchaseQCApproveTask) DispatchAt(at time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskAt(task, at, opts...)
-}
+})
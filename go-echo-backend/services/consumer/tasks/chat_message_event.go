@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/engineeringinflow/inflow-backend/pkg/ws"
+	"github.com/rotisserie/eris"
+)
+
+type ChatMessageEventPayload struct {
+	Type        enums.ChatMessageWsType `json:"type" validate:"required"`
+	ChatMessage *models.ChatMessage     `json:"chat_message" validate:"required"`
+}
+
+// ChatMessageEventTask broadcasts an edit, deletion or reaction of a message to the room, the actor included for their other devices
+var ChatMessageEventTask = worker.NewTaskType("chat_message_event", 1, func(ctx context.Context, payload ChatMessageEventPayload) error {
+	var chatRoomRepo = repo.NewChatRoomRepo(workerInstance.App.DB)
+	userIDs, err := chatRoomRepo.GetChatRoomUserIDs(payload.ChatMessage.ReceiverID)
+	if err != nil {
+		return err
+	}
+
+	chatRoom, err := chatRoomRepo.GetChatRoomInfo(payload.ChatMessage.ReceiverID)
+	if err != nil {
+		return err
+	}
+	err = ws.GetInstance().BroadcastToUsers(&ws.BroadcastChatMessage{
+		Type:           payload.Type.String(),
+		Message:        payload.ChatMessage,
+		ChatRoom:       chatRoom,
+		ParticipantIDs: userIDs,
+	})
+
+	if err != nil {
+		workerInstance.Logger.Debugf("Send ws event type=%s room=%s message=%s receiver=%s error=%+v", payload.Type, payload.ChatMessage.ReceiverID, payload.ChatMessage.ID, strings.Join(userIDs, ","), err)
+		return eris.Wrapf(err, "Send ws event type=%s room=%s message=%s", payload.Type, payload.ChatMessage.ReceiverID, payload.ChatMessage.ID)
+	}
+
+	return nil
+}, worker.WithPolicy(chatTaskPolicy))
@@ -2,40 +2,23 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/errs"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type CreateBulkPoAttachmentPDFsTask struct {
+type CreateBulkPoAttachmentPDFsPayload struct {
 	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" validate:"required"`
 	ReCreate            bool   `json:"re_create"`
 }
 
-func (task CreateBulkPoAttachmentPDFsTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task CreateBulkPoAttachmentPDFsTask) TaskName() string {
-	return "create_bulk_po_attachment_pdfs"
-}
-
-// Handler handler
-func (task CreateBulkPoAttachmentPDFsTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return
-	}
-
+var CreateBulkPoAttachmentPDFsTask = worker.NewTaskType("create_bulk_po_attachment_pdfs", 1, func(ctx context.Context, payload CreateBulkPoAttachmentPDFsPayload) (err error) {
 	bulk, err := repo.NewInvoiceRepo(workerInstance.App.DB).CreateBulkDebitNotes(repo.CreateBulkDebitNotesParams{
-		BulkPurchaseOrderID: task.BulkPurchaseOrderID,
-		ReCreate:            task.ReCreate,
+		BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
+		ReCreate:            payload.ReCreate,
 	})
 	if err != nil {
 		if eris.Is(err, errs.ErrBulkPoInvoiceAlreadyGenerated) {
@@ -46,12 +29,7 @@ func (task CreateBulkPoAttachmentPDFsTask) Handler(ctx context.Context, t *asynq
 
 	var results = bulk.GetCustomerIOMetadata(nil)
 
-	t.ResultWriter().Write(helper.ToJson(&results))
+	worker.WriteResult(ctx, helper.ToJson(&results))
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task CreateBulkPoAttachmentPDFsTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(pdfTaskPolicy))
@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/rotisserie/eris"
+)
+
+type CreateBulkPoMilestoneInvoicePayload struct {
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" validate:"required"`
+	PaymentMilestoneID  string `json:"payment_milestone_id" validate:"required"`
+	ApprovedByUserID    string `json:"approved_by_user_id"`
+	ReCreate            bool   `json:"re_create"`
+}
+
+// DedupeKey one invoice per milestone unless it's re-created on purpose
+func (payload CreateBulkPoMilestoneInvoicePayload) DedupeKey() string {
+	if payload.ReCreate {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", CreateBulkPoMilestoneInvoiceTask.TaskName(), payload.PaymentMilestoneID)
+}
+
+var CreateBulkPoMilestoneInvoiceTask = worker.NewTaskType("create_bulk_po_milestone_invoice", 1, func(ctx context.Context, payload CreateBulkPoMilestoneInvoicePayload) error {
+	bulkPO, milestone, err := repo.NewInvoiceRepo(workerInstance.App.DB).CreateBulkMilestoneInvoice(repo.CreateBulkMilestoneInvoiceParams{
+		BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
+		PaymentMilestoneID:  payload.PaymentMilestoneID,
+		ReCreate:            payload.ReCreate,
+	})
+	if err != nil {
+		if eris.Is(err, errs.ErrBulkPoInvoiceAlreadyGenerated) {
+			return nil
+		}
+		return err
+	}
+
+	var data = map[string]interface{}{
+		"receipt_url": milestone.ReceiptURL,
+		"milestone":   milestone.GetDisplayName(),
+		"invoice":     milestone.Invoice.GetCustomerIOMetadata(),
+	}
+
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
+		UserID: bulkPO.UserID,
+		Event:  customerio.EventBulkPoBuyerMilestonePaymentSucceeded,
+		Data:   bulkPO.GetCustomerIOMetadata(data),
+	})
+
+	for _, assigneeID := range bulkPO.AssigneeIDs {
+		TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
+			UserID: assigneeID,
+			Event:  customerio.EventBulkPoMilestonePaymentSucceeded,
+			Data:   bulkPO.GetCustomerIOMetadata(data),
+		})
+	}
+
+	var eventData = bulkPO.GetCustomerIOMetadata(nil)
+	worker.WriteResult(ctx, helper.ToJson(&eventData))
+
+	return err
+})
@@ -2,15 +2,14 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type CreateChatRoomTask struct {
+type CreateChatRoomPayload struct {
 	UserID              string     `json:"user_id"`
 	Role                enums.Role `json:"role"`
 	InquiryID           string     `json:"inquiry_id"`
@@ -20,43 +19,22 @@ type CreateChatRoomTask struct {
 	SellerID            string     `json:"seller_id"`
 }
 
-func (task CreateChatRoomTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task CreateChatRoomTask) TaskName() string {
-	return "create_chat_room"
-}
-
-// Handler handler
-func (task CreateChatRoomTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var CreateChatRoomTask = worker.NewTaskType("create_chat_room", 1, func(ctx context.Context, payload CreateChatRoomPayload) error {
 	var claims = models.JwtClaimsInfo{}
-	claims.SetUserID(task.UserID)
-	claims.SetRole(task.Role)
+	claims.SetUserID(payload.UserID)
+	claims.SetRole(payload.Role)
 
 	if _, err := repo.NewChatRoomRepo(workerInstance.App.DB).CreateChatRoom(&models.CreateChatRoomRequest{
 		JwtClaimsInfo:       claims,
-		InquiryID:           task.InquiryID,
-		PurchaseOrderID:     task.PurchaseOrderID,
-		BulkPurchaseOrderID: task.BulkPurchaseOrderID,
-		BuyerID:             task.BuyerID,
-		SellerID:            task.SellerID,
+		InquiryID:           payload.InquiryID,
+		PurchaseOrderID:     payload.PurchaseOrderID,
+		BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
+		BuyerID:             payload.BuyerID,
+		SellerID:            payload.SellerID,
 	}); err != nil {
 		return err
 	}
 
 	return nil
 
-}
-
-// Dispatch dispatch event
-func (task CreateChatRoomTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
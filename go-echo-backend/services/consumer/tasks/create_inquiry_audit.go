@@ -3,46 +3,23 @@ package tasks
 import (
 	"context"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type CreateInquiryAuditTask struct {
+type CreateInquiryAuditPayload struct {
 	Form models.InquiryAuditCreateForm `json:"form" validate:"required"`
 }
 
-func (task CreateInquiryAuditTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task CreateInquiryAuditTask) TaskName() string {
-	return "create_inquiry_audit"
-}
-
-// Handler handler
-func (task CreateInquiryAuditTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var CreateInquiryAuditTask = worker.NewTaskType("create_inquiry_audit", 1, func(ctx context.Context, payload CreateInquiryAuditPayload) error {
+	var err error
 	// InquiryAudit
-	err = repo.NewInquiryAuditRepo(workerInstance.App.DB).CreateInquiryAudit(task.Form)
+	err = repo.NewInquiryAuditRepo(workerInstance.App.DB).CreateInquiryAudit(payload.Form)
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task CreateInquiryAuditTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
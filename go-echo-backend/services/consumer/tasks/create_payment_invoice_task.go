@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,35 +9,21 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type CreatePaymentInvoiceTask struct {
+type CreatePaymentInvoicePayload struct {
 	PaymentTransactionID string `json:"payment_transaction_id" validate:"required"`
 	ApprovedByUserID     string `json:"approved_by_user_id"`
 }
 
-func (task CreatePaymentInvoiceTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task CreatePaymentInvoiceTask) TaskName() string {
-	return "create_payment_invoice_task"
-}
-
-// Handler handler
-func (task CreatePaymentInvoiceTask) Handler(ctx context.Context, t *asynq.Task) error {
-	if err := workerInstance.BindAndValidate(t.Payload(), &task); err != nil {
-		return err
-	}
-	invoice, err := repo.NewInvoiceRepo(workerInstance.App.DB).CreatePaymentInvoice(&repo.CreateOrderInvoiceRequest{PaymentTransactionID: task.PaymentTransactionID})
+var CreatePaymentInvoiceTask = worker.NewTaskType("create_payment_invoice_task", 1, func(ctx context.Context, payload CreatePaymentInvoicePayload) error {
+	invoice, err := repo.NewInvoiceRepo(workerInstance.App.DB).CreatePaymentInvoice(&repo.CreateOrderInvoiceRequest{PaymentTransactionID: payload.PaymentTransactionID})
 	if err != nil {
 		return err
 	}
 	payment, err := repo.NewPaymentTransactionRepo(workerInstance.App.DB).
-		GetPaymentTransaction(repo.GetPaymentTransactionsParams{PaymentTransactionID: task.PaymentTransactionID, IncludeDetails: true})
+		GetPaymentTransaction(repo.GetPaymentTransactionsParams{PaymentTransactionID: payload.PaymentTransactionID, IncludeDetails: true})
 	if err != nil {
 		return err
 	}
@@ -48,23 +33,23 @@ func (task CreatePaymentInvoiceTask) Handler(ctx context.Context, t *asynq.Task)
 		// card
 		var description = "The payment has been received"
 		//bank transfer
-		if task.ApprovedByUserID != "" {
+		if payload.ApprovedByUserID != "" {
 			var admin models.User
-			if err := workerInstance.App.DB.Select("Name").First(&admin, "id = ?", task.ApprovedByUserID).Error; err != nil {
+			if err := workerInstance.App.DB.Select("Name").First(&admin, "id = ?", payload.ApprovedByUserID).Error; err != nil {
 				return err
 			}
 			description = fmt.Sprintf("Admin %s has confirmed the payment", admin.Name)
 		}
 		for _, po := range payment.PurchaseOrders {
 			if po.InquiryID != "" {
-				CreateInquiryAuditTask{
+				CreateInquiryAuditTask.Dispatch(ctx, CreateInquiryAuditPayload{
 					Form: models.InquiryAuditCreateForm{
 						InquiryID:   po.InquiryID,
 						ActionType:  enums.AuditActionTypeInquiryAdminMarkAsPaid,
-						UserID:      task.ApprovedByUserID,
+						UserID:      payload.ApprovedByUserID,
 						Description: description,
 					},
-				}.Dispatch(ctx)
+				})
 			}
 
 			orderReferenceIDs = append(orderReferenceIDs, po.ReferenceID)
@@ -77,19 +62,14 @@ func (task CreatePaymentInvoiceTask) Handler(ctx context.Context, t *asynq.Task)
 		}
 	}
 
-	TrackCustomerIOTask{
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 		Event:  customerio.EventAdminConfirmPaymentReceived,
 		UserID: invoice.UserID,
 		Data: map[string]interface{}{
 			"order_reference_ids": strings.Join(orderReferenceIDs, ", "),
 			"invoice_attachment":  invoice.Document.GenerateFileURL(),
 		},
-	}.Dispatch(ctx)
+	})
 
 	return nil
-}
-
-// Dispatch dispatch event
-func (task CreatePaymentInvoiceTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
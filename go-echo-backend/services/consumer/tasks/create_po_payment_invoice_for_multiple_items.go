@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
@@ -11,37 +10,21 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 	"github.com/samber/lo"
 )
 
-type CreatePOPaymentInvoiceForMultipleItemsTask struct {
+type CreatePOPaymentInvoiceForMultipleItemsPayload struct {
 	ReCreate          bool   `json:"re_create"`
 	CheckoutSessionID string `json:"checkout_session_id" validate:"required"`
 	ApprovedByUserID  string `json:"approved_by_user_id"`
 }
 
-func (task CreatePOPaymentInvoiceForMultipleItemsTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task CreatePOPaymentInvoiceForMultipleItemsTask) TaskName() string {
-	return "create_po_payment_invoice_for_multiple_items"
-}
-
-// Handler handler
-func (task CreatePOPaymentInvoiceForMultipleItemsTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var CreatePOPaymentInvoiceForMultipleItemsTask = worker.NewTaskType("create_po_payment_invoice_for_multiple_items", 1, func(ctx context.Context, payload CreatePOPaymentInvoiceForMultipleItemsPayload) error {
 	resp, err := repo.NewInvoiceRepo(workerInstance.App.DB).CreateMultiplePurchaseOrderInvoice(repo.CreateMultiplePurchaseOrderInvoiceParams{
-		CheckoutSessionID: task.CheckoutSessionID,
-		ReCreate:          task.ReCreate,
+		CheckoutSessionID: payload.CheckoutSessionID,
+		ReCreate:          payload.ReCreate,
 	})
 	if err != nil {
 		if eris.Is(err, errs.ErrPOInvoiceAlreadyGenerated) {
@@ -50,50 +33,50 @@ func (task CreatePOPaymentInvoiceForMultipleItemsTask) Handler(ctx context.Conte
 		return err
 	}
 
-	if task.ApprovedByUserID != "" {
-		userAdmin, err := repo.NewUserRepo(workerInstance.App.DB).GetShortUserInfo(task.ApprovedByUserID)
+	if payload.ApprovedByUserID != "" {
+		userAdmin, err := repo.NewUserRepo(workerInstance.App.DB).GetShortUserInfo(payload.ApprovedByUserID)
 		if err == nil && userAdmin != nil {
 			for _, purchaseOrder := range resp.PurchaseOrders {
-				CreateInquiryAuditTask{
+				CreateInquiryAuditTask.Dispatch(ctx, CreateInquiryAuditPayload{
 					Form: models.InquiryAuditCreateForm{
 						InquiryID:   purchaseOrder.InquiryID,
 						ActionType:  enums.AuditActionTypeInquiryAdminMarkAsPaid,
 						UserID:      userAdmin.ID,
 						Description: fmt.Sprintf("Admin %s has confirmed the payment", userAdmin.Name),
 					},
-				}.Dispatch(ctx)
+				})
 			}
 
 		}
 	}
 
 	if len(resp.PurchaseOrders) > 0 {
-		TrackCustomerIOTask{
+		TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 			UserID: resp.PurchaseOrders[0].UserID,
 			Event:  customerio.EventPoBuyerPaymentSucceeded,
 			Data: resp.PaymentTransaction.GetCustomerIOMetadata(map[string]interface{}{
 				"receipt_url": resp.PurchaseOrders[0].ReceiptURL,
 			}),
-		}.Dispatch(ctx)
+		})
 	}
 
 	for _, purchaseOrder := range resp.PurchaseOrders {
-		TrackCustomerIOTask{
+		TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 			UserID: workerInstance.App.Config.InflowMerchandiseGroupEmail,
 			Event:  customerio.EventPoCreated,
 			Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
 				"receipt_url": purchaseOrder.ReceiptURL,
 			}),
-		}.Dispatch(ctx)
+		})
 
 		for _, assigneeID := range purchaseOrder.Inquiry.AssigneeIDs {
-			TrackCustomerIOTask{
+			TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 				UserID: assigneeID,
 				Event:  customerio.EventPoWorkingOnDesign,
 				Data: purchaseOrder.GetCustomerIOMetadata(map[string]interface{}{
 					"receipt_url": purchaseOrder.ReceiptURL,
 				}),
-			}.Dispatch(ctx)
+			})
 		}
 
 	}
@@ -101,12 +84,7 @@ func (task CreatePOPaymentInvoiceForMultipleItemsTask) Handler(ctx context.Conte
 	var eventData = lo.Map(resp.PurchaseOrders, func(item *models.PurchaseOrder, index int) map[string]interface{} {
 		return item.GetCustomerIOMetadata(nil)
 	})
-	t.ResultWriter().Write(helper.ToJson(&eventData))
+	worker.WriteResult(ctx, helper.ToJson(&eventData))
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task CreatePOPaymentInvoiceForMultipleItemsTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
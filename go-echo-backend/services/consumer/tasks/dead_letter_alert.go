@@ -0,0 +1,15 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+)
+
+type DeadLetterAlertPayload struct{}
+
+// DeadLetterAlertTask alerts when the tasks archived in the last window spike
+var DeadLetterAlertTask = worker.NewTaskType("dead_letter_alert", 1, func(ctx context.Context, payload DeadLetterAlertPayload) error {
+	_, err := workerInstance.CheckDeadLetterRate()
+	return err
+})
@@ -2,40 +2,18 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type DeleteCustomerIOUserTask struct {
+type DeleteCustomerIOUserPayload struct {
 	UserID string `json:"user_id" validate:"required"`
 	IsNew  bool   `json:"is_new"`
 }
 
-func (task DeleteCustomerIOUserTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task DeleteCustomerIOUserTask) TaskName() string {
-	return "delete_customerio_user"
-}
-
-// Handler handler
-func (task DeleteCustomerIOUserTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
-	err = workerInstance.App.CustomerIOClient.Track.Delete(task.UserID)
+var DeleteCustomerIOUserTask = worker.NewTaskType("delete_customerio_user", 1, func(ctx context.Context, payload DeleteCustomerIOUserPayload) error {
+	var err error
+	err = workerInstance.App.CustomerIOClient.Track.Delete(payload.UserID)
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task DeleteCustomerIOUserTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(customerIOTaskPolicy))
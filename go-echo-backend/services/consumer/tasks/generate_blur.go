@@ -2,34 +2,18 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/runner"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type GenerateBlurTask struct {
+type GenerateBlurPayload struct {
 	FileKeys []string `json:"file_keys"`
 }
 
-func (task GenerateBlurTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task GenerateBlurTask) TaskName() string {
-	return "generate_blur"
-}
-
-// Handler handler
-func (task GenerateBlurTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var GenerateBlurTask = worker.NewTaskType("generate_blur", 1, func(ctx context.Context, payload GenerateBlurPayload) error {
+	var err error
 	var products []*models.Product
 	workerInstance.App.DB.Select("ID", "Attachments").Find(&products, "attachments is not null")
 
@@ -74,9 +58,4 @@ func (task GenerateBlurTask) Handler(ctx context.Context, t *asynq.Task) error {
 	runner.Wait()
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task GenerateBlurTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -3,51 +3,28 @@ package tasks
 import (
 	"context"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/hubspot"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type HubspotCreateContactTask struct {
+type HubspotCreateContactPayload struct {
 	Data *hubspot.ContactPropertiesForm `json:"data" validate:"required"`
 }
 
-func (task HubspotCreateContactTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task HubspotCreateContactTask) TaskName() string {
-	return "hubspot_create_contact"
-}
-
-// Handler handler
-func (task HubspotCreateContactTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var HubspotCreateContactTask = worker.NewTaskType("hubspot_create_contact", 1, func(ctx context.Context, payload HubspotCreateContactPayload) error {
 	if !workerInstance.App.Config.IsProd() {
 		return nil
 	}
 
-	contact, err := workerInstance.App.HubspotClient.CreateContact(task.Data)
+	contact, err := workerInstance.App.HubspotClient.CreateContact(payload.Data)
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
 
-	t.ResultWriter().Write(helper.ToJson(contact))
+	worker.WriteResult(ctx, helper.ToJson(contact))
 
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task HubspotCreateContactTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(hubspotTaskPolicy))
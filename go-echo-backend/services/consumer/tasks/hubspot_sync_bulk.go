@@ -3,40 +3,23 @@ package tasks
 import (
 	"context"
 
-	"encoding/json"
-
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type HubspotSyncBulkTask struct {
+type HubspotSyncBulkPayload struct {
 	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" validate:"required"`
 	UserID              string `json:"user_id" validate:"required"`
 	IsAdmin             bool   `json:"is_admin"`
 }
 
-func (task HubspotSyncBulkTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task HubspotSyncBulkTask) TaskName() string {
-	return "hubspot_sync_bulk"
-}
-
-// Handler handler
-func (task HubspotSyncBulkTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var HubspotSyncBulkTask = worker.NewTaskType("hubspot_sync_bulk", 1, func(ctx context.Context, payload HubspotSyncBulkPayload) error {
+	var err error
 	if !workerInstance.App.Config.IsProd() {
 		return nil
 	}
 
 	// bulk, err := repo.NewBulkPurchaseOrderRepo(workerInstance.App.DB).GetBulkPurchaseOrder(repo.GetBulkPurchaseOrderParams{
-	// 	BulkPurchaseOrderID: task.BulkPurchaseOrderID,
+	// 	BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
 	// })
 	// if err != nil {
 	// 	return err
@@ -57,9 +40,4 @@ func (task HubspotSyncBulkTask) Handler(ctx context.Context, t *asynq.Task) erro
 	// }
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task HubspotSyncBulkTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(hubspotTaskPolicy))
@@ -4,47 +4,29 @@ import (
 	"context"
 	"fmt"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/hubspot"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 	"gorm.io/gorm"
 )
 
-type HubspotSyncInquiryTask struct {
+type HubspotSyncInquiryPayload struct {
 	InquiryID string `json:"inquiry_id" validate:"required"`
 	UserID    string `json:"user_id" validate:"required"`
 	IsAdmin   bool   `json:"is_admin"`
 }
 
-func (task HubspotSyncInquiryTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task HubspotSyncInquiryTask) TaskName() string {
-	return "hubspot_sync_inquiry"
-}
-
-// Handler handler
-func (task HubspotSyncInquiryTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var HubspotSyncInquiryTask = worker.NewTaskType("hubspot_sync_inquiry", 1, func(ctx context.Context, payload HubspotSyncInquiryPayload) error {
 	if !workerInstance.App.Config.IsProd() {
 		return nil
 	}
 
 	inquiry, err := repo.NewInquiryRepo(workerInstance.App.DB).GetInquiryByID(repo.GetInquiryByIDParams{
-		InquiryID: task.InquiryID,
+		InquiryID: payload.InquiryID,
 	})
 	if err != nil {
 		return err
@@ -85,7 +67,7 @@ func (task HubspotSyncInquiryTask) Handler(ctx context.Context, t *asynq.Task) e
 			return err
 		}
 
-		t.ResultWriter().Write(helper.ToJson(deal))
+		worker.WriteResult(ctx, helper.ToJson(deal))
 		workerInstance.Logger.Debugf("Create deal %s success", deal.ID)
 
 	} else {
@@ -97,7 +79,7 @@ func (task HubspotSyncInquiryTask) Handler(ctx context.Context, t *asynq.Task) e
 				return eris.Wrap(err, err.Error())
 			}
 
-			t.ResultWriter().Write(helper.ToJson(deal))
+			worker.WriteResult(ctx, helper.ToJson(deal))
 			workerInstance.Logger.Debugf("Update deal %s success", deal.ID)
 		}
 	}
@@ -107,9 +89,4 @@ func (task HubspotSyncInquiryTask) Handler(ctx context.Context, t *asynq.Task) e
 	}
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task HubspotSyncInquiryTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(hubspotTaskPolicy))
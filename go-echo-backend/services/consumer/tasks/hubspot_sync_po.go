@@ -4,47 +4,29 @@ import (
 	"context"
 	"fmt"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/hubspot"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 	"gorm.io/gorm"
 )
 
-type HubspotSyncPOTask struct {
+type HubspotSyncPOPayload struct {
 	PurchaseOrderID string `json:"purchase_order_id" validate:"required"`
 	UserID          string `json:"user_id" validate:"required"`
 	IsAdmin         bool   `json:"is_admin"`
 }
 
-func (task HubspotSyncPOTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task HubspotSyncPOTask) TaskName() string {
-	return "hubspot_sync_po"
-}
-
-// Handler handler
-func (task HubspotSyncPOTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var HubspotSyncPOTask = worker.NewTaskType("hubspot_sync_po", 1, func(ctx context.Context, payload HubspotSyncPOPayload) error {
 	if !workerInstance.App.Config.IsProd() {
 		return nil
 	}
 
 	purchaseOrder, err := repo.NewPurchaseOrderRepo(workerInstance.App.DB).GetPurchaseOrder(repo.GetPurchaseOrderParams{
-		PurchaseOrderID: task.PurchaseOrderID,
+		PurchaseOrderID: payload.PurchaseOrderID,
 	})
 	if err != nil {
 		return err
@@ -92,7 +74,7 @@ func (task HubspotSyncPOTask) Handler(ctx context.Context, t *asynq.Task) error
 			return eris.Wrap(err, err.Error())
 		}
 
-		t.ResultWriter().Write(helper.ToJson(deal))
+		worker.WriteResult(ctx, helper.ToJson(deal))
 		workerInstance.Logger.Debugf("Create deal %s success", deal.ID)
 
 	} else {
@@ -111,7 +93,7 @@ func (task HubspotSyncPOTask) Handler(ctx context.Context, t *asynq.Task) error
 				}
 			}
 
-			t.ResultWriter().Write(helper.ToJson(deal))
+			worker.WriteResult(ctx, helper.ToJson(deal))
 			workerInstance.Logger.Debugf("Update deal %s success", deal.ID)
 		}
 	}
@@ -121,9 +103,4 @@ func (task HubspotSyncPOTask) Handler(ctx context.Context, t *asynq.Task) error
 	}
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task HubspotSyncPOTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(hubspotTaskPolicy))
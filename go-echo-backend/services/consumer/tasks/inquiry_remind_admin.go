@@ -4,36 +4,18 @@ import (
 	"context"
 	"fmt"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type InquiryRemindAdminTask struct {
-}
-
-func (task InquiryRemindAdminTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task InquiryRemindAdminTask) TaskName() string {
-	return "inquiry_remind_admin"
+type InquiryRemindAdminPayload struct {
 }
 
-// Handler handler
-func (task InquiryRemindAdminTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var InquiryRemindAdminTask = worker.NewTaskType("inquiry_remind_admin", 1, func(ctx context.Context, payload InquiryRemindAdminPayload) error {
+	var err error
 	var inquiries = repo.NewInquiryRepo(workerInstance.App.DB).GetInquiryRemindAdmin()
 
 	if len(inquiries) == 0 {
@@ -47,23 +29,18 @@ func (task InquiryRemindAdminTask) Handler(ctx context.Context, t *asynq.Task) e
 		"admin_inquiries_link": fmt.Sprintf("%s/inquiries", workerInstance.App.Config.AdminPortalBaseURL),
 	}
 
-	_, _ = TrackCustomerIOTask{
+	_, _ = TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 		UserID: workerInstance.Config.App.Config.InflowSaleGroupEmail,
 		Event:  customerio.EventNewInquiryRemindAdmin,
 		Data:   eventData,
-	}.Dispatch(ctx)
+	})
 
 	if err != nil {
 		return eris.Wrap(err, err.Error())
 	}
 
-	t.ResultWriter().Write(helper.ToJson(&eventData))
+	worker.WriteResult(ctx, helper.ToJson(&eventData))
 
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task InquiryRemindAdminTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
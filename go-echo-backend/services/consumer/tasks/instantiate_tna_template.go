@@ -0,0 +1,38 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/rotisserie/eris"
+)
+
+type InstantiateTNATemplatePayload struct {
+	BulkPurchaseOrderID string `json:"bulk_purchase_order_id" validate:"required"`
+}
+
+// InstantiateTNATemplateTask creates the TNA plan of a bulk order waiting for PO from the matching template
+var InstantiateTNATemplateTask = worker.NewTaskType("instantiate_tna_template", 1, func(ctx context.Context, payload InstantiateTNATemplatePayload) error {
+	tnas, err := repo.NewTNATemplateRepo(workerInstance.App.DB).InstantiateBulkPurchaseOrderTNA(repo.InstantiateBulkPurchaseOrderTNAParams{
+		BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
+	})
+	if err != nil {
+		// Plan was built by hand or no template configured for the product
+		if eris.Is(err, errs.ErrTNAPlanAlreadyExists) || eris.Is(err, errs.ErrTNATemplateNotFound) {
+			workerInstance.Logger.Debugf("Skip TNA template bulk_purchase_order_id=%s err=%+v", payload.BulkPurchaseOrderID, err)
+			return nil
+		}
+		return err
+	}
+
+	for _, tna := range tnas {
+		_, _ = TimeAndActionSchedulerTask.Dispatch(ctx, TimeAndActionSchedulerPayload{
+			ID:         tna.ID,
+			ActionType: TimeAndActionSchedulerActionTypeCreate,
+		})
+	}
+
+	return nil
+})
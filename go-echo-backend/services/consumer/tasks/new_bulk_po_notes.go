@@ -2,17 +2,16 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type NewBulkPONotesTask struct {
+type NewBulkPONotesPayload struct {
 	UserID              string              `json:"user_id" validate:"required"`
 	BulkPurchaseOrderID string              `json:"bulk_purchase_order_id" validate:"required"`
 	MentionUserIDs      []string            `json:"mention_user_ids" validate:"required"`
@@ -20,33 +19,16 @@ type NewBulkPONotesTask struct {
 	Attachments         *models.Attachments `json:"attachments"`
 }
 
-func (task NewBulkPONotesTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task NewBulkPONotesTask) TaskName() string {
-	return "new_bulk_po_notes"
-}
-
-// Handler handler
-func (task NewBulkPONotesTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var NewBulkPONotesTask = worker.NewTaskType("new_bulk_po_notes", 1, func(ctx context.Context, payload NewBulkPONotesPayload) error {
 	bulkPurchaseOrder, err := repo.NewBulkPurchaseOrderRepo(workerInstance.App.DB).GetBulkPurchaseOrder(repo.GetBulkPurchaseOrderParams{
-		BulkPurchaseOrderID: task.BulkPurchaseOrderID,
+		BulkPurchaseOrderID: payload.BulkPurchaseOrderID,
 	})
 	if err != nil {
 		return err
 	}
 
 	var sender models.User
-	err = workerInstance.App.DB.Select("ID", "Name", "Email").First(&sender, "id = ?", task.UserID).Error
+	err = workerInstance.App.DB.Select("ID", "Name", "Email").First(&sender, "id = ?", payload.UserID).Error
 	if err != nil {
 		return err
 	}
@@ -55,31 +37,26 @@ func (task NewBulkPONotesTask) Handler(ctx context.Context, t *asynq.Task) error
 		"sender":                              sender.GetCustomerIOMetadata(nil),
 		"admin_bulk_purchase_order_notes_url": fmt.Sprintf("%s/bulks/%s/notes", workerInstance.App.Config.AdminPortalBaseURL, bulkPurchaseOrder.ID),
 	}
-	if task.Message != "" {
-		extras["message"] = task.Message
+	if payload.Message != "" {
+		extras["message"] = payload.Message
 	}
 
-	if task.Attachments != nil {
-		extras["attachments"] = task.Attachments
+	if payload.Attachments != nil {
+		extras["attachments"] = payload.Attachments
 	}
 
-	for _, userID := range task.MentionUserIDs {
-		SyncCustomerIOUserTask{
+	for _, userID := range payload.MentionUserIDs {
+		SyncCustomerIOUserTask.Dispatch(ctx, SyncCustomerIOUserPayload{
 			UserID: userID,
-		}.Dispatch(ctx)
+		})
 
-		TrackCustomerIOTask{
+		TrackCustomerIOTask.DispatchIn(ctx, TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventAdminBulkPONewNotes,
 			Data:   bulkPurchaseOrder.GetCustomerIOMetadata(extras),
-		}.DispatchIn(time.Second * 3)
+		}, time.Second*3)
 
 	}
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task NewBulkPONotesTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -10,10 +9,10 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type NewInquiryNotesTask struct {
+type NewInquiryNotesPayload struct {
 	UserID         string              `json:"user_id" validate:"required"`
 	InquiryID      string              `json:"inquiry_id" validate:"required"`
 	MentionUserIDs []string            `json:"mention_user_ids" validate:"required"`
@@ -21,34 +20,17 @@ type NewInquiryNotesTask struct {
 	Attachments    *models.Attachments `json:"attachments"`
 }
 
-func (task NewInquiryNotesTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task NewInquiryNotesTask) TaskName() string {
-	return "new_inquiry_notes"
-}
-
-// Handler handler
-func (task NewInquiryNotesTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var NewInquiryNotesTask = worker.NewTaskType("new_inquiry_notes", 1, func(ctx context.Context, payload NewInquiryNotesPayload) error {
 	inquiry, err := repo.NewInquiryRepo(workerInstance.App.DB).GetInquiryByID(repo.GetInquiryByIDParams{
-		InquiryID:     task.InquiryID,
-		JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleSuperAdmin).SetUserID(task.UserID),
+		InquiryID:     payload.InquiryID,
+		JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleSuperAdmin).SetUserID(payload.UserID),
 	})
 	if err != nil {
 		return err
 	}
 
 	var sender models.User
-	err = workerInstance.App.DB.Select("ID", "Name", "Email").First(&sender, "id = ?", task.UserID).Error
+	err = workerInstance.App.DB.Select("ID", "Name", "Email").First(&sender, "id = ?", payload.UserID).Error
 	if err != nil {
 		return err
 	}
@@ -57,31 +39,26 @@ func (task NewInquiryNotesTask) Handler(ctx context.Context, t *asynq.Task) erro
 		"sender":                  sender.GetCustomerIOMetadata(nil),
 		"admin_inquiry_notes_url": fmt.Sprintf("%s/inquiries/%s/customer/notes", workerInstance.App.Config.AdminPortalBaseURL, inquiry.ID),
 	}
-	if task.Message != "" {
-		extras["message"] = task.Message
+	if payload.Message != "" {
+		extras["message"] = payload.Message
 	}
 
-	if task.Attachments != nil {
-		extras["attachments"] = task.Attachments
+	if payload.Attachments != nil {
+		extras["attachments"] = payload.Attachments
 	}
 
-	for _, userID := range task.MentionUserIDs {
-		SyncCustomerIOUserTask{
+	for _, userID := range payload.MentionUserIDs {
+		SyncCustomerIOUserTask.Dispatch(ctx, SyncCustomerIOUserPayload{
 			UserID: userID,
-		}.Dispatch(ctx)
+		})
 
-		TrackCustomerIOTask{
+		TrackCustomerIOTask.DispatchIn(ctx, TrackCustomerIOPayload{
 			UserID: userID,
 			Event:  customerio.EventAdminInquiryNewNotes,
 			Data:   inquiry.GetCustomerIOMetadata(extras),
-		}.DispatchIn(time.Second * 3)
+		}, time.Second*3)
 
 	}
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task NewInquiryNotesTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -10,31 +9,17 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/samber/lo"
 )
 
-type NotifyAdminConfirmPaymentTask struct {
+type NotifyAdminConfirmPaymentPayload struct {
 	PaymentTransactionID string `json:"payment_transaction_id" validate:"required"`
 }
 
-func (task NotifyAdminConfirmPaymentTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task NotifyAdminConfirmPaymentTask) TaskName() string {
-	return "notify_admin_confirm_payment_task"
-}
-
-// Handler handler
-func (task NotifyAdminConfirmPaymentTask) Handler(ctx context.Context, t *asynq.Task) error {
-	if err := workerInstance.BindAndValidate(t.Payload(), &task); err != nil {
-		return err
-	}
+var NotifyAdminConfirmPaymentTask = worker.NewTaskType("notify_admin_confirm_payment_task", 1, func(ctx context.Context, payload NotifyAdminConfirmPaymentPayload) error {
 	payment, err := repo.NewPaymentTransactionRepo(workerInstance.App.DB).
-		GetPaymentTransaction(repo.GetPaymentTransactionsParams{PaymentTransactionID: task.PaymentTransactionID, IncludeDetails: true})
+		GetPaymentTransaction(repo.GetPaymentTransactionsParams{PaymentTransactionID: payload.PaymentTransactionID, IncludeDetails: true})
 	if err != nil {
 		return err
 	}
@@ -63,7 +48,7 @@ func (task NotifyAdminConfirmPaymentTask) Handler(ctx context.Context, t *asynq.
 	}
 
 	for _, assigneeID := range assigneeIDs {
-		TrackCustomerIOTask{
+		TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 			Event:  customerio.EventBuyerCheckoutThroughBankTransfer,
 			UserID: assigneeID,
 			Data: map[string]interface{}{
@@ -74,13 +59,8 @@ func (task NotifyAdminConfirmPaymentTask) Handler(ctx context.Context, t *asynq.
 				"order_items":          strings.Join(orderReferenceIDs, ", "),
 				"payment_attachment":   attachment.GenerateFileURL(),
 			},
-		}.Dispatch(ctx)
+		})
 	}
 
 	return nil
-}
-
-// Dispatch dispatch event
-func (task NotifyAdminConfirmPaymentTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -3,39 +3,19 @@ package tasks
 import (
 	"context"
 	"fmt"
-	"time"
-
-	"encoding/json"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/helper"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type OnboardSellerTask struct {
+type OnboardSellerPayload struct {
 	UserID string `json:"user_id" validate:"required"`
 }
 
-func (task OnboardSellerTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task OnboardSellerTask) TaskName() string {
-	return "onboard_seller"
-}
-
-// Handler handler
-func (task OnboardSellerTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
-	user, err := repo.NewUserRepo(workerInstance.App.DB).GetCustomerIOUser(task.UserID)
+var OnboardSellerTask = worker.NewTaskType("onboard_seller", 1, func(ctx context.Context, payload OnboardSellerPayload) error {
+	user, err := repo.NewUserRepo(workerInstance.App.DB).GetCustomerIOUser(payload.UserID)
 	if err != nil {
 		return err
 	}
@@ -43,28 +23,19 @@ func (task OnboardSellerTask) Handler(ctx context.Context, t *asynq.Task) error
 	var data = user.GetCustomerIOMetadata(map[string]interface{}{
 		"login_url": fmt.Sprintf("%s/login", workerInstance.App.Config.SellerPortalBaseURL),
 	})
-	err = workerInstance.App.CustomerIOClient.Track.Identify(task.UserID, data)
+	err = workerInstance.App.CustomerIOClient.Track.Identify(payload.UserID, data)
 	if err != nil {
 		return err
 	}
 
-	TrackCustomerIOTask{
-		UserID: task.UserID,
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
+		UserID: payload.UserID,
 		Event:  customerio.EventOnboardSeller,
 		Data:   data,
-	}.Dispatch(ctx)
+	})
 
-	t.ResultWriter().Write(helper.ToJson(&data))
+	worker.WriteResult(ctx, helper.ToJson(&data))
 
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task OnboardSellerTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
-
-func (task OnboardSellerTask) DispatchIn(ctx context.Context, duration time.Duration, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskIn(task, duration, opts...)
-}
+})
@@ -0,0 +1,61 @@
+package tasks
+
+import (
+	"errors"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/hubspot"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+)
+
+// hubspotRateLimit HubSpot allows 100 requests per 10 seconds, a sync task makes up to 3 of them
+var hubspotRateLimit = &worker.RateLimit{
+	Key:      "hubspot",
+	Limit:    30,
+	Interval: time.Second * 10,
+}
+
+// customerIORateLimit Customer.io allows 100 requests per second on the track API
+var customerIORateLimit = &worker.RateLimit{
+	Key:      "customerio",
+	Limit:    50,
+	Interval: time.Second,
+	Burst:    100,
+}
+
+var hubspotTaskPolicy = worker.TaskPolicy{
+	Queue:     worker.QueueNameLow,
+	RateLimit: hubspotRateLimit,
+	Retry: &worker.RetryPolicy{
+		MaxRetry: 5,
+		Backoff:  worker.ExponentialBackoff(time.Second*30, time.Hour),
+		IsNonRetryable: func(err error) bool {
+			var apiErr *hubspot.ApiError
+			return errors.As(err, &apiErr) && (apiErr.Category == "VALIDATION_ERROR" || apiErr.Category == "OBJECT_NOT_FOUND")
+		},
+	},
+	Timeout: time.Minute,
+	Unique:  time.Minute,
+}
+
+var customerIOTaskPolicy = worker.TaskPolicy{
+	RateLimit: customerIORateLimit,
+	Retry: &worker.RetryPolicy{
+		MaxRetry: 5,
+		Backoff:  worker.ExponentialBackoff(time.Second*10, time.Minute*30),
+	},
+	Timeout: time.Minute,
+}
+
+// pdfTaskPolicy the headless browser is slow and heavy, a few renders at once leave room for the other tasks
+var pdfTaskPolicy = worker.TaskPolicy{
+	Queue:          worker.QueueNameLow,
+	MaxConcurrency: 4,
+	Timeout:        time.Minute * 10,
+}
+
+// chatTaskPolicy chat events are delivered before the background work
+var chatTaskPolicy = worker.TaskPolicy{
+	Queue:   worker.QueueNameCritical,
+	Timeout: time.Second * 30,
+}
@@ -0,0 +1,15 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/engineeringinflow/inflow-backend/pkg/ws"
+)
+
+// PublishTopicEventTask pushes a domain event to the websocket clients subscribed to its topic, usually written to the outbox by the repositories
+var PublishTopicEventTask = worker.NewTaskType(models.TopicEvent{}.TaskName(), 1, func(ctx context.Context, payload models.TopicEvent) error {
+	_, err := ws.GetInstance().PublishTopicEvent(ctx, payload)
+	return err
+})
@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"math"
 	"time"
@@ -10,37 +9,22 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/jinzhu/copier"
 	"github.com/rotisserie/eris"
 )
 
-type PurchaseOrderDesignApproveTask struct {
+type PurchaseOrderDesignApprovePayload struct {
 	models.JwtClaimsInfo
 	PurchaseOrderID   string                           `json:"purchase_order_id" param:"purchase_order_id" query:"purchase_order_id" validate:"required"`
 	ApproveRejectMeta *models.InquiryApproveRejectMeta `json:"approve_reject_meta" param:"approve_reject_meta" query:"approve_reject_meta"`
 }
 
-func (task PurchaseOrderDesignApproveTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task PurchaseOrderDesignApproveTask) TaskName() string {
-	return "purchase_order_design_approve"
-}
-
-// Handler handler
-func (task PurchaseOrderDesignApproveTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var PurchaseOrderDesignApproveTask = worker.NewTaskType("purchase_order_design_approve", 1, func(ctx context.Context, payload PurchaseOrderDesignApprovePayload) (err error) {
 	now := time.Now().Unix()
 	var po models.PurchaseOrder
 	if err = workerInstance.App.DB.Model(&models.PurchaseOrder{}).
-		Select("approve_design_at").Where("id = ?", task.PurchaseOrderID).First(&po).Error; err != nil {
+		Select("approve_design_at").Where("id = ?", payload.PurchaseOrderID).First(&po).Error; err != nil {
 		return
 	}
 	if po.ApproveDesignAt == nil {
@@ -55,7 +39,7 @@ func (task PurchaseOrderDesignApproveTask) Handler(ctx context.Context, t *asynq
 	}
 
 	var params repo.BuyerApproveDesignParams
-	err = copier.Copy(&params, &task)
+	err = copier.Copy(&params, &payload)
 	if err != nil {
 		return err
 	}
@@ -64,20 +48,11 @@ func (task PurchaseOrderDesignApproveTask) Handler(ctx context.Context, t *asynq
 		return eris.Wrap(err, err.Error())
 	}
 
-	TrackCustomerIOTask{
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 		UserID: purchaseOrder.UserID,
 		Event:  customerio.EventPoApproveDesign,
 		Data:   purchaseOrder.GetCustomerIOMetadata(nil),
-	}.Dispatch(ctx)
+	})
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task PurchaseOrderDesignApproveTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
-
-func (task PurchaseOrderDesignApproveTask) DispatchAt(at time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskAt(task, at, opts...)
-}
+})
@@ -2,20 +2,20 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"math"
+	"time"
+
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/jinzhu/copier"
 	"github.com/rotisserie/eris"
 	"github.com/samber/lo"
-	"math"
-	"time"
 )
 
-type PurchaseOrderRawMaterialApproveTask struct {
+type PurchaseOrderRawMaterialApprovePayload struct {
 	models.JwtClaimsInfo
 
 	PurchaseOrderID string `json:"purchase_order_id" param:"purchase_order_id" query:"purchase_order_id" validate:"required"`
@@ -23,26 +23,11 @@ type PurchaseOrderRawMaterialApproveTask struct {
 	ItemIDs []string `json:"item_ids" param:"item_ids" query:"item_ids"`
 }
 
-func (task PurchaseOrderRawMaterialApproveTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task PurchaseOrderRawMaterialApproveTask) TaskName() string {
-	return "purchase_order_raw_material_approve"
-}
-
-// Handler handler
-func (task PurchaseOrderRawMaterialApproveTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+var PurchaseOrderRawMaterialApproveTask = worker.NewTaskType("purchase_order_raw_material_approve", 1, func(ctx context.Context, payload PurchaseOrderRawMaterialApprovePayload) (err error) {
 	now := time.Now().Unix()
 	var po models.PurchaseOrder
 	if err = workerInstance.App.DB.Model(&models.PurchaseOrder{}).
-		Select("approve_raw_material_at").Where("id = ?", task.PurchaseOrderID).First(&po).Error; err != nil {
+		Select("approve_raw_material_at").Where("id = ?", payload.PurchaseOrderID).First(&po).Error; err != nil {
 		return
 	}
 	if po.ApproveRawMaterialAt != nil {
@@ -53,7 +38,7 @@ func (task PurchaseOrderRawMaterialApproveTask) Handler(ctx context.Context, t *
 	}
 
 	var params repo.PurchaseApproveRawMaterialParams
-	err = copier.Copy(&params, &task)
+	err = copier.Copy(&params, &payload)
 	if err != nil {
 		return err
 	}
@@ -71,21 +56,12 @@ func (task PurchaseOrderRawMaterialApproveTask) Handler(ctx context.Context, t *
 
 			data["approved_raw_materials"] = list.GenerateFileURL()
 		}
-		_, _ = TrackCustomerIOTask{
+		_, _ = TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 			UserID: assigneeID,
 			Event:  customerio.EventPoApproveRawMaterial,
 			Data:   data,
-		}.Dispatch(ctx)
+		})
 	}
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task PurchaseOrderRawMaterialApproveTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
-
-func (task PurchaseOrderRawMaterialApproveTask) DispatchAt(at time.Time, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskAt(task, at, opts...)
-}
+})
@@ -0,0 +1,21 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+)
+
+type PurgeIdempotencyKeysPayload struct{}
+
+// PurgeIdempotencyKeysTask deletes the idempotency keys past their window
+var PurgeIdempotencyKeysTask = worker.NewTaskType("purge_idempotency_keys", 1, func(ctx context.Context, payload PurgeIdempotencyKeysPayload) error {
+	deleted, err := repo.NewIdempotencyKeyRepo(workerInstance.App.DB).DeleteExpiredIdempotencyKeys()
+	if err != nil {
+		return err
+	}
+
+	workerInstance.Logger.Debugf("Purged %d expired idempotency keys", deleted)
+	return nil
+})
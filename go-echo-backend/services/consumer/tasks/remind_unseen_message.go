@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
@@ -10,30 +9,14 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 
-	"github.com/hibiken/asynq"
 	"github.com/samber/lo"
 )
 
-type RemindUnseenMessageTask struct{}
-
-func (task RemindUnseenMessageTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task RemindUnseenMessageTask) TaskName() string {
-	return "remind_unseen_message"
-}
-
-func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
+type RemindUnseenMessagePayload struct{}
 
+var RemindUnseenMessageTask = worker.NewTaskType("remind_unseen_message", 1, func(ctx context.Context, payload RemindUnseenMessagePayload) error {
 	unseenRooms, err := repo.NewChatRoomRepo(workerInstance.App.DB).GetUnseenChatRoomsOver6Hours()
 	if err != nil {
 		return err
@@ -74,7 +57,7 @@ func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task)
 					pathName = "rfqs"
 				}
 			}
-			_, err := TrackCustomerIOTask{
+			_, err := TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 				UserID: receiver.ID,
 				Event:  customerio.EventRemindUnseenChatMessage,
 				Data: map[string]interface{}{
@@ -88,7 +71,7 @@ func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task)
 					"attachments":        attachments,
 					"chat_url":           fmt.Sprintf("%s/%s/%s?open_chat=true", baseURL, pathName, room.ChatRoomStatus.StageID),
 				},
-			}.Dispatch(ctx)
+			})
 			if err != nil {
 				return err
 			}
@@ -97,7 +80,7 @@ func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task)
 				return !user.Role.IsBuyer() && !user.Role.IsSeller()
 			})
 			for _, admin := range admins {
-				_, err := TrackCustomerIOTask{
+				_, err := TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 					UserID: admin.ID,
 					Event:  customerio.EventRemindUnseenChatMessage,
 					Data: map[string]interface{}{
@@ -111,7 +94,7 @@ func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task)
 						"attachments":        attachments,
 						"chat_url":           fmt.Sprintf("%s/%s/%s/customer?open_chat=true", config.GetInstance().AdminPortalBaseURL, pathName, room.ChatRoomStatus.StageID),
 					},
-				}.Dispatch(ctx)
+				})
 				if err != nil {
 					return err
 				}
@@ -119,8 +102,4 @@ func (task RemindUnseenMessageTask) Handler(ctx context.Context, t *asynq.Task)
 		}
 	}
 	return nil
-}
-
-func (task RemindUnseenMessageTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -2,37 +2,15 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type SellerRejectPOTask struct {
+type SellerRejectPOPayload struct {
 	InquirySellerID string `json:"inquiry_seller_id" validate:"required"`
 }
 
-func (task SellerRejectPOTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task SellerRejectPOTask) TaskName() string {
-	return "seller_reject_po"
-}
-
-// Handler handler
-func (task SellerRejectPOTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var SellerRejectPOTask = worker.NewTaskType("seller_reject_po", 1, func(ctx context.Context, payload SellerRejectPOPayload) error {
+	var err error
 	return err
-}
-
-// Dispatch dispatch event
-func (task SellerRejectPOTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
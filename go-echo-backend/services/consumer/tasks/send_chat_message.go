@@ -4,59 +4,40 @@ import (
 	"context"
 	"fmt"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/config"
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/samber/lo"
 )
 
-type SendChatMessageTask struct {
+type SendChatMessagePayload struct {
 	ChatMessage *models.ChatMessage `json:"chat_message" validate:"required"`
 }
 
-func (task SendChatMessageTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task SendChatMessageTask) TaskName() string {
-	return "send_chat_message"
-}
-
-// Handler handler
-func (task SendChatMessageTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var SendChatMessageTask = worker.NewTaskType("send_chat_message", 1, func(ctx context.Context, payload SendChatMessagePayload) error {
 	var roomUserIDs []string
 
 	workerInstance.App.DB.Model(&models.ChatRoomUser{}).
 		Select("UserID").
-		Find(&roomUserIDs, "room_id = ? AND user_id <> ?", task.ChatMessage.ReceiverID, task.ChatMessage.SenderID)
+		Find(&roomUserIDs, "room_id = ? AND user_id <> ?", payload.ChatMessage.ReceiverID, payload.ChatMessage.SenderID)
 
-	chatRoom, err := repo.NewChatRoomRepo(workerInstance.App.DB).GetChatRoomInfo(task.ChatMessage.ReceiverID)
+	chatRoom, err := repo.NewChatRoomRepo(workerInstance.App.DB).GetChatRoomInfo(payload.ChatMessage.ReceiverID)
 	if err != nil {
 		return err
 	}
 
-	workerInstance.Logger.Debugf("Message receiver_id=%s type=%s total_users=%d err=%+v", task.ChatMessage.ReceiverID, task.ChatMessage.MessageType, len(roomUserIDs), err)
-	_, err = SendWSEventTask{
-		ChatMessage:           task.ChatMessage,
+	workerInstance.Logger.Debugf("Message receiver_id=%s type=%s total_users=%d err=%+v", payload.ChatMessage.ReceiverID, payload.ChatMessage.MessageType, len(roomUserIDs), err)
+	_, err = SendWSEventTask.Dispatch(ctx, SendWSEventPayload{
+		ChatMessage:           payload.ChatMessage,
 		ChatRoom:              chatRoom,
 		ChannelParticipantIDs: roomUserIDs,
-	}.Dispatch(ctx)
+	})
 
 	var senderInfo models.User
-	if err := workerInstance.App.DB.Select("ID", "Name", "Avatar", "Role", "ContactOwnerIDs").First(&senderInfo, "id = ?", task.ChatMessage.SenderID).Error; err != nil {
+	if err := workerInstance.App.DB.Select("ID", "Name", "Avatar", "Role", "ContactOwnerIDs").First(&senderInfo, "id = ?", payload.ChatMessage.SenderID).Error; err != nil {
 		return err
 	}
 
@@ -66,8 +47,8 @@ func (task SendChatMessageTask) Handler(ctx context.Context, t *asynq.Task) erro
 	}
 
 	var attachments models.Attachments
-	if task.ChatMessage.Attachments != nil {
-		attachments = task.ChatMessage.Attachments.GenerateFileURL()
+	if payload.ChatMessage.Attachments != nil {
+		attachments = payload.ChatMessage.Attachments.GenerateFileURL()
 	}
 	var pathName = "inquiries"
 	if chatRoom.ChatRoomStatus.Stage == enums.ChatRoomStageSample {
@@ -76,7 +57,7 @@ func (task SendChatMessageTask) Handler(ctx context.Context, t *asynq.Task) erro
 		pathName = "bulks"
 	}
 	var recentMessage models.ChatMessage
-	if err := workerInstance.App.DB.Select("ID").First(&recentMessage, "id != ? AND receiver_id = ? AND created_at > ? - 3600", task.ChatMessage.ID, task.ChatMessage.ReceiverID, task.ChatMessage.CreatedAt).Error; err != nil {
+	if err := workerInstance.App.DB.Select("ID").First(&recentMessage, "id != ? AND receiver_id = ? AND created_at > ? - 3600", payload.ChatMessage.ID, payload.ChatMessage.ReceiverID, payload.ChatMessage.CreatedAt).Error; err != nil {
 		if !workerInstance.App.DB.IsRecordNotFoundError(err) {
 			return err
 		}
@@ -94,51 +75,46 @@ func (task SendChatMessageTask) Handler(ctx context.Context, t *asynq.Task) erro
 					pathName = "rfqs"
 				}
 			}
-			_, err = TrackCustomerIOTask{
+			_, err = TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 				UserID: receiveUser.ID,
 				Event:  customerio.EventNewChatMessage,
 				Data: map[string]interface{}{
-					"room_id":            task.ChatMessage.ReceiverID,
-					"message_id":         task.ChatMessage.ID,
+					"room_id":            payload.ChatMessage.ReceiverID,
+					"message_id":         payload.ChatMessage.ID,
 					"sender":             senderInfo.GetCustomerIOMetadata(nil),
 					"receiver":           receiveUser.GetCustomerIOMetadata(nil),
 					"order_type":         chatRoom.ChatRoomStatus.Stage,
 					"order_reference_id": chatRoom.ChatRoomStatus.ReferenceID,
-					"message":            task.ChatMessage.Message,
+					"message":            payload.ChatMessage.Message,
 					"attachments":        attachments.GenerateFileURL(),
 					"is_send_email":      recentMessage.ID == "",
 					"chat_url":           fmt.Sprintf("%s/%s/%s?open_chat=true", baseURL, pathName, chatRoom.ChatRoomStatus.StageID),
 				},
-			}.Dispatch(ctx)
+			})
 		}
 
 	} else {
 		// send email to admins
 		for _, user := range roomUsers {
-			_, err = TrackCustomerIOTask{
+			_, err = TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
 				UserID: user.ID,
 				Event:  customerio.EventNewChatMessage,
 				Data: map[string]interface{}{
-					"room_id":            task.ChatMessage.ReceiverID,
-					"message_id":         task.ChatMessage.ID,
+					"room_id":            payload.ChatMessage.ReceiverID,
+					"message_id":         payload.ChatMessage.ID,
 					"sender":             senderInfo.GetCustomerIOMetadata(nil),
 					"receiver":           user.GetCustomerIOMetadata(nil),
 					"order_type":         chatRoom.ChatRoomStatus.Stage,
 					"order_reference_id": chatRoom.ChatRoomStatus.ReferenceID,
-					"message":            task.ChatMessage.Message,
+					"message":            payload.ChatMessage.Message,
 					"attachments":        attachments,
 					"is_send_email":      recentMessage.ID == "",
 					"chat_url":           fmt.Sprintf("%s/%s/%s/customer?open_chat=true", config.GetInstance().AdminPortalBaseURL, pathName, chatRoom.ChatRoomStatus.StageID),
 				},
-			}.Dispatch(ctx)
+			})
 		}
 	}
 
 	return err
 
-}
-
-// Dispatch dispatch event
-func (task SendChatMessageTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+}, worker.WithPolicy(chatTaskPolicy))
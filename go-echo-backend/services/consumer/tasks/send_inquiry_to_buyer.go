@@ -2,7 +2,6 @@ package tasks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
@@ -10,33 +9,16 @@ import (
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type SendInquiryToBuyerTask struct {
+type SendInquiryToBuyerPayload struct {
 	UserID string                 `json:"user_id" validate:"required"`
 	Event  customerio.Event       `json:"event" validate:"required"`
 	Data   map[string]interface{} `json:"data" validate:"required"`
 }
 
-func (task SendInquiryToBuyerTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task SendInquiryToBuyerTask) TaskName() string {
-	return "send_inquiry_to_buyer"
-}
-
-// Handler handler
-func (task SendInquiryToBuyerTask) Handler(ctx context.Context, t *asynq.Task) (err error) {
-	err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var SendInquiryToBuyerTask = worker.NewTaskType("send_inquiry_to_buyer", 1, func(ctx context.Context, payload SendInquiryToBuyerPayload) (err error) {
 	var products []*models.Product
 	products, _ = repo.NewProductRepo(workerInstance.App.DB).
 		GetProductByPageSectionType(string(enums.PageSectionTypeCatalogDrop), queryfunc.ProductBuilderOptions{})
@@ -46,18 +28,13 @@ func (task SendInquiryToBuyerTask) Handler(ctx context.Context, t *asynq.Task) (
 		for _, p := range products {
 			if p.Attachments != nil && len(*p.Attachments) > 0 {
 				att := *p.Attachments
-				task.Data[fmt.Sprintf("img_%d", imgInc)] = att[0].GenerateFileURL().FileURL
+				payload.Data[fmt.Sprintf("img_%d", imgInc)] = att[0].GenerateFileURL().FileURL
 				imgInc += 1
 			}
 		}
 	}
 
-	err = workerInstance.App.CustomerIOClient.Track.Track(task.UserID, string(task.Event), task.Data)
+	err = workerInstance.App.CustomerIOClient.Track.Track(payload.UserID, string(payload.Event), payload.Data)
 
 	return
-}
-
-// Dispatch dispatch event
-func (task SendInquiryToBuyerTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
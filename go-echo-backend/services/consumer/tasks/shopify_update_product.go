@@ -3,48 +3,25 @@ package tasks
 import (
 	"context"
 
-	"encoding/json"
-
-	"github.com/hibiken/asynq"
 	"github.com/thaitanloi365/go-sendgrid"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/mailer"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type ShopifyUpdateProductTask struct {
+type ShopifyUpdateProductPayload struct {
 	Email      string                 `json:"email" validate:"email"`
 	TemplateID mailer.TemplateID      `json:"template_id" validate:"required"`
 	Data       map[string]interface{} `json:"data"`
 }
 
-func (task ShopifyUpdateProductTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task ShopifyUpdateProductTask) TaskName() string {
-	return "shopify_update_product"
-}
-
-// Handler handler
-func (task ShopifyUpdateProductTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
-	if err != nil {
-		return err
-	}
-
+var ShopifyUpdateProductTask = worker.NewTaskType("shopify_update_product", 1, func(ctx context.Context, payload ShopifyUpdateProductPayload) error {
+	var err error
 	err = workerInstance.App.Mailer.Send(sendgrid.SendMailParams{
-		Email:      task.Email,
-		TemplateID: string(task.TemplateID),
-		Data:       task.Data,
+		Email:      payload.Email,
+		TemplateID: string(payload.TemplateID),
+		Data:       payload.Data,
 	})
 
 	return err
-}
-
-// Dispatch dispatch event
-func (task ShopifyUpdateProductTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
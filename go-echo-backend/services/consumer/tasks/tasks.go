@@ -10,103 +10,131 @@ var workerInstance *worker.Worker
 func Register(w *worker.Worker, IsConsumer bool) {
 	workerInstance = w
 
+	workerInstance.RegisterTasks(
+		UserPingTask,
+
+		TrackActivityTask,
+		SendMailTask,
+		SendChatMessageTask,
+		SendWSEventTask,
+		PublishTopicEventTask,
+		SeenChatRoomTask,
+		ChatTypingTask,
+		CancelChatTypingTask,
+		CreateChatRoomTask,
+		ChatMessageEventTask,
+
+		ShopifyCreateWebhooksTask,
+		ShopifyUpdateProductTask,
+		// ShopifySyncProductTask,
+		// ShopifySyncProductsTask,
+		// ShopifySyncChannelProductsTask,
+
+		HubspotCreateContactTask,
+		HubspotCreateDealTask,
+		HubspotUpdateDealTask,
+		HubspotSyncPOTask,
+		HubspotSyncBulkTask,
+		HubspotSyncInquiryTask,
+
+		SyncCustomerIOUserTask,
+		DeleteCustomerIOUserTask,
+		TrackCustomerIOTask,
+		CreateInquiryAuditTask,
+		CreateCmsNotificationTask,
+		CreateUserNotificationTask,
+		OnboardUserTask,
+		OnboardSellerTask,
+		ApproveUserTask,
+		SendInquiryToBuyerTask,
+		AssignInquiryPICTask,
+		AssignPurchaseOrderPICTask,
+		AssignBulkPurchaseOrderPICTask,
+		InquiryRemindAdminTask,
+		AddCustomerIOUserDeviceTask,
+
+		PurchaseOrderBankTransferConfirmedTask,
+		PurchaseOrderBankTransferRejectedTask,
+		BulkPurchaseOrderBankTransferConfirmedTask,
+		BulkPurchaseOrderBankTransferRejectedTask,
+		PoDesignNewCommentTask,
+		GeneratePDFTask,
+		// CreatePOPaymentInvoiceTask,
+		CreateBulkPoDepositPaymentInvoiceTask,
+		// CreateBulkPoFirstPaymentInvoiceTask,
+		// CreateBulkPoSecondPaymentInvoiceTask,
+		// CreateBulkPoFinalPaymentInvoiceTask,
+		CreateBulkPoMilestoneInvoiceTask,
+		CreatePaymentInvoiceTask,
+		NotifyAdminConfirmPaymentTask,
+
+		CreateBulkPoAttachmentPDFsTask,
+		NewInquiryNotesTask,
+		NewBulkPONotesTask,
+		NewPONotesTask,
+		CreatePOPaymentInvoiceForMultipleItemsTask,
+		CreateSysNotificationTask,
+		RefreshTokenZaloTask,
+		TimeAndActionNotificationTask,
+		TimeAndActionSchedulerTask,
+		TimeAndActionSlippageTask,
+		InstantiateTNATemplateTask,
+		PurchaseOrderDesignApproveTask,
+		BulkPurchaseQCApproveTask,
+		BulkPurchaseOrderRawMaterialApproveTask,
+		PurchaseOrderRawMaterialApproveTask,
+		UpdateUserProductClassesTask,
+
+		SellerApprovePOTask,
+		SellerRejectPOTask,
+
+		AdminApproveSellerQuotationTask,
+		AdminRejectSellerQuotationTask,
+		AdminApproveSellerBulkPurchaseOrderQuotation,
+		AdminRejectSellerBulkPurchaseOrderQuotation,
+		RemindUnseenMessageTask,
+
+		GenerateBlurTask,
+		UploadProductFileTask,
+		PurgeIdempotencyKeysTask,
+		DeadLetterAlertTask,
+	)
+
 	if IsConsumer {
-		workerInstance.CreateTaskHandler(
-			UserPingTask{},
-
-			TrackActivityTask{},
-			SendMailTask{},
-			SendChatMessageTask{},
-			SendWSEventTask{},
-			SeenChatRoomTask{},
-			ChatTypingTask{},
-			CancelChatTypingTask{},
-			CreateChatRoomTask{},
-
-			ShopifyCreateWebhooksTask{},
-			ShopifyUpdateProductTask{},
-			// ShopifySyncProductTask{},
-			// ShopifySyncProductsTask{},
-			// ShopifySyncChannelProductsTask{},
-
-			HubspotCreateContactTask{},
-			HubspotCreateDealTask{},
-			HubspotUpdateDealTask{},
-			HubspotSyncPOTask{},
-			HubspotSyncBulkTask{},
-			HubspotSyncInquiryTask{},
-
-			SyncCustomerIOUserTask{},
-			DeleteCustomerIOUserTask{},
-			TrackCustomerIOTask{},
-			CreateInquiryAuditTask{},
-			CreateCmsNotificationTask{},
-			CreateUserNotificationTask{},
-			OnboardUserTask{},
-			OnboardSellerTask{},
-			ApproveUserTask{},
-			SendInquiryToBuyerTask{},
-			AssignInquiryPICTask{},
-			AssignPurchaseOrderPICTask{},
-			AssignBulkPurchaseOrderPICTask{},
-			InquiryRemindAdminTask{},
-			AddCustomerIOUserDeviceTask{},
-
-			PurchaseOrderBankTransferConfirmedTask{},
-			PurchaseOrderBankTransferRejectedTask{},
-			BulkPurchaseOrderBankTransferConfirmedTask{},
-			BulkPurchaseOrderBankTransferRejectedTask{},
-			PoDesignNewCommentTask{},
-			GeneratePDFTask{},
-			// CreatePOPaymentInvoiceTask{},
-			CreateBulkPoDepositPaymentInvoiceTask{},
-			// CreateBulkPoFirstPaymentInvoiceTask{},
-			// CreateBulkPoSecondPaymentInvoiceTask{},
-			// CreateBulkPoFinalPaymentInvoiceTask{},
-			CreatePaymentInvoiceTask{},
-			NotifyAdminConfirmPaymentTask{},
-
-			CreateBulkPoAttachmentPDFsTask{},
-			NewInquiryNotesTask{},
-			NewBulkPONotesTask{},
-			NewPONotesTask{},
-			CreatePOPaymentInvoiceForMultipleItemsTask{},
-			CreateSysNotificationTask{},
-			RefreshTokenZaloTask{},
-			TimeAndActionNotificationTask{},
-			TimeAndActionSchedulerTask{},
-			PurchaseOrderDesignApproveTask{},
-			BulkPurchaseQCApproveTask{},
-			BulkPurchaseOrderRawMaterialApproveTask{},
-			PurchaseOrderRawMaterialApproveTask{},
-			UpdateUserProductClassesTask{},
-
-			SellerApprovePOTask{},
-			SellerRejectPOTask{},
-
-			AdminApproveSellerQuotationTask{},
-			AdminRejectSellerQuotationTask{},
-			AdminApproveSellerBulkPurchaseOrderQuotation{},
-			AdminRejectSellerBulkPurchaseOrderQuotation{},
-			RemindUnseenMessageTask{},
-
-			GenerateBlurTask{},
-			UploadProductFileTask{},
-		)
+		workerInstance.CreateTaskHandler()
 
-		_, _ = w.ScheduleTask(
+		// TNA notifications are dispatched as dynamic tasks named after the plan, they are still served after a restart
+		workerInstance.HandleTaskPrefix("start_", TimeAndActionNotificationTask)
+		workerInstance.HandleTaskPrefix("end_", TimeAndActionNotificationTask)
+
+		_, _ = InquiryRemindAdminTask.Schedule(
+			w,
 			"CRON_TZ=Asia/Saigon 0 08 * * *",
-			InquiryRemindAdminTask{},
+			InquiryRemindAdminPayload{},
 		)
 
-		_, _ = w.ScheduleTask(
+		_, _ = RefreshTokenZaloTask.Schedule(
+			w,
 			"CRON_TZ=Asia/Saigon */30 * * * *",
-			RefreshTokenZaloTask{},
+			RefreshTokenZaloPayload{},
 		)
 
-		_, _ = w.ScheduleTask(
+		_, _ = RemindUnseenMessageTask.Schedule(
+			w,
 			"CRON_TZ=Asia/Saigon 0 */4 * * *",
-			RemindUnseenMessageTask{},
+			RemindUnseenMessagePayload{},
+		)
+
+		_, _ = PurgeIdempotencyKeysTask.Schedule(
+			w,
+			"CRON_TZ=Asia/Saigon 0 * * * *",
+			PurgeIdempotencyKeysPayload{},
+		)
+
+		_, _ = DeadLetterAlertTask.Schedule(
+			w,
+			"CRON_TZ=Asia/Saigon */10 * * * *",
+			DeadLetterAlertPayload{},
 		)
 	}
 
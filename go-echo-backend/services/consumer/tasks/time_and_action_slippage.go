@@ -0,0 +1,38 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+)
+
+type TimeAndActionSlippagePayload struct {
+	ReferenceID string `json:"reference_id" validate:"required"`
+}
+
+// TimeAndActionSlippageTask alerts the assignees and PICs when the projected ex-factory date of a TNA plan passes the requested date
+var TimeAndActionSlippageTask = worker.NewTaskType("time_and_action_slippage", 1, func(ctx context.Context, payload TimeAndActionSlippagePayload) error {
+	plan, err := repo.NewTNARepo(workerInstance.App.DB).GetPlan(repo.GetTNAPlanParams{
+		ReferenceID: payload.ReferenceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rescheduled back in time before the payload ran
+	if !plan.IsSlipped() {
+		return nil
+	}
+
+	for _, userID := range plan.GetAssigneeIDs() {
+		_, _ = TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
+			UserID: userID,
+			Event:  customerio.EventAdminTNASlippage,
+			Data:   plan.GetCustomerIOMetadata(nil),
+		})
+	}
+
+	return nil
+})
@@ -3,58 +3,35 @@ package tasks
 import (
 	"context"
 
-	"encoding/json"
-
 	"github.com/engineeringinflow/inflow-backend/pkg/customerio"
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
 	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
 	"github.com/engineeringinflow/inflow-backend/pkg/repo"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 )
 
-type TrackActivityTask struct {
+type TrackActivityPayload struct {
 	UserID                string                       `json:"user_id" validate:"required"`
 	CountryCode           enums.CountryCode            `json:"country_code"`
 	UserTrackActivityForm models.UserTrackActivityForm `json:"user_track_activity_form"`
 }
 
-func (task TrackActivityTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-
-	return data
-}
-
-// TaskName task name
-func (task TrackActivityTask) TaskName() string {
-	return "track_activity"
-}
-
-// Handler handler
-func (task TrackActivityTask) Handler(ctx context.Context, t *asynq.Task) error {
-	var err = workerInstance.BindAndValidate(t.Payload(), &task)
+// TrackActivityTask records the activity of the user and syncs it to customer.io
+var TrackActivityTask = worker.NewTaskType("track_activity", 1, func(ctx context.Context, payload TrackActivityPayload) error {
+	updates, err := repo.NewUserRepo(workerInstance.App.DB).TrackActivity(payload.UserID, payload.UserTrackActivityForm)
 	if err != nil {
 		return err
 	}
 
-	updates, err := repo.NewUserRepo(workerInstance.App.DB).TrackActivity(task.UserID, task.UserTrackActivityForm)
-	if err != nil {
-		return err
-	}
-
-	SyncCustomerIOUserTask{
-		UserID: task.UserID,
-	}.Dispatch(ctx)
+	SyncCustomerIOUserTask.Dispatch(ctx, SyncCustomerIOUserPayload{
+		UserID: payload.UserID,
+	})
 
-	TrackCustomerIOTask{
-		UserID: task.UserID,
+	TrackCustomerIOTask.Dispatch(ctx, TrackCustomerIOPayload{
+		UserID: payload.UserID,
 		Event:  customerio.EventTrackActivity,
 		Data:   updates,
-	}.Dispatch(ctx)
+	})
 
 	return nil
-}
-
-// Dispatch dispatch event
-func (task TrackActivityTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -10,39 +10,25 @@ import (
 	"time"
 
 	"github.com/engineeringinflow/inflow-backend/pkg/models"
-	"github.com/hibiken/asynq"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
 	"github.com/rotisserie/eris"
 )
 
-type UploadProductFileTask struct {
+type UploadProductFilePayload struct {
 	UploadID   string `json:"upload_id" validate:"required"`
 	SiteName   string `json:"site_name" validate:"required"`
 	FileKey    string `json:"file_key" validate:"required"`
 	ScrapeDate int64  `json:"scrape_date" validate:"required"`
 }
 
-func (task UploadProductFileTask) GetPayload() []byte {
-	data, _ := json.Marshal(&task)
-	return data
-}
-
-// TaskName task name
-func (task UploadProductFileTask) TaskName() string {
-	return "upload_product_file_task"
-}
-
-// Handler handler
-func (task UploadProductFileTask) Handler(ctx context.Context, t *asynq.Task) error {
-	if err := workerInstance.BindAndValidate(t.Payload(), &task); err != nil {
-		return err
-	}
-	var pathArr = strings.Split(task.FileKey, fmt.Sprintf("%s/", task.SiteName))
+var UploadProductFileTask = worker.NewTaskType("upload_product_file_task", 1, func(ctx context.Context, payload UploadProductFilePayload) error {
+	var pathArr = strings.Split(payload.FileKey, fmt.Sprintf("%s/", payload.SiteName))
 	if len(pathArr) != 2 {
-		return eris.Errorf("invalid file path, site_name:%s, file_key:%s", task.SiteName, task.FileKey)
+		return eris.Errorf("invalid file path, site_name:%s, file_key:%s", payload.SiteName, payload.FileKey)
 	}
-	var scapeDate = time.Unix(task.ScrapeDate, 0).Format("2006-01-02")
+	var scapeDate = time.Unix(payload.ScrapeDate, 0).Format("2006-01-02")
 	reqBody, err := json.Marshal(map[string]string{
-		"site_name":           task.SiteName,
+		"site_name":           payload.SiteName,
 		"file_name":           pathArr[1],
 		"default_scrape_date": scapeDate,
 	})
@@ -64,7 +50,7 @@ func (task UploadProductFileTask) Handler(ctx context.Context, t *asynq.Task) er
 			Status:     "failed",
 			FailReason: err.Error(),
 		}
-		if err := workerInstance.App.DB.Model(&updateInfo).Where("id = ?", task.UploadID).Updates(&updateInfo).Error; err != nil {
+		if err := workerInstance.App.DB.Model(&updateInfo).Where("id = ?", payload.UploadID).Updates(&updateInfo).Error; err != nil {
 			return err
 		}
 	} else {
@@ -77,14 +63,9 @@ func (task UploadProductFileTask) Handler(ctx context.Context, t *asynq.Task) er
 			Status:   "finished",
 			Metadata: &respMetadata,
 		}
-		if err := workerInstance.App.DB.Model(&updateInfo).Where("id = ?", task.UploadID).Updates(&updateInfo).Error; err != nil {
+		if err := workerInstance.App.DB.Model(&updateInfo).Where("id = ?", payload.UploadID).Updates(&updateInfo).Error; err != nil {
 			return err
 		}
 	}
 	return nil
-}
-
-// Dispatch dispatch event
-func (task UploadProductFileTask) Dispatch(ctx context.Context, opts ...asynq.Option) (*asynq.TaskInfo, error) {
-	return workerInstance.SendTaskWithContext(ctx, task, opts...)
-}
+})
@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBankStatement_ParseCSV(t *testing.T) {
+	var content = []byte("Date,Amount,Currency,Reference,Counterparty\n2026-10-01,\"1,250.50\",usd,PAY-ABC123,ACME Ltd\n02/10/2026,-30,USD,Bank fee,\n")
+	assert.Equal(t, enums.BankStatementFormatCSV, models.DetectBankStatementFormat(content))
+
+	lines, _, err := models.ParseBankStatement(enums.BankStatementFormatCSV, content)
+	assert.NoError(t, err)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 1250.5, lines[0].Amount.ToFloat64())
+	assert.Equal(t, enums.USD, lines[0].Currency)
+	assert.Equal(t, "PAY-ABC123", lines[0].Reference)
+	assert.Equal(t, "ACME Ltd", lines[0].Counterparty)
+	assert.Equal(t, enums.BankStatementLineStatusUnmatched, lines[0].Status)
+	assert.NotEmpty(t, lines[0].Fingerprint)
+	assert.Equal(t, enums.BankStatementLineStatusIgnored, lines[1].Status)
+
+	_, err = models.ParseBankStatementCSV([]byte("amount,reference\n10,PAY-1\n"))
+	assert.Error(t, err)
+}
+
+func TestBankStatement_ParseMT940(t *testing.T) {
+	var content = []byte(`:20:STMT001
+:25:123456789
+:28C:1/1
+:60F:C261001USD1000,00
+:61:2610021002C1500,00NTRFPAY-ABC123//BANK001
+:86:Transfer from ACME Ltd inv
+oice PAY-ABC123
+:61:2610031003D20,00NCHGNONREF//BANK002
+:62F:C261003USD2480,00
+-`)
+	assert.Equal(t, enums.BankStatementFormatMT940, models.DetectBankStatementFormat(content))
+
+	lines, accountNumber, err := models.ParseBankStatement(enums.BankStatementFormatMT940, content)
+	assert.NoError(t, err)
+	assert.Equal(t, "123456789", accountNumber)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 1500.0, lines[0].Amount.ToFloat64())
+	assert.Equal(t, enums.USD, lines[0].Currency)
+	assert.Equal(t, "PAY-ABC123", lines[0].Reference)
+	assert.Equal(t, "BANK001", lines[0].BankRef)
+	assert.Equal(t, "Transfer from ACME Ltd invoice PAY-ABC123", lines[0].Description)
+	assert.Equal(t, -20.0, lines[1].Amount.ToFloat64())
+	assert.Equal(t, "", lines[1].Reference)
+}
+
+func TestBankStatement_ParseCAMT053(t *testing.T) {
+	var content = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Id><IBAN>SG00BANK0001</IBAN></Id></Acct>
+      <Ntry>
+        <Amt Ccy="SGD">980.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2026-10-05</Dt></BookgDt>
+        <AcctSvcrRef>SVC-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+          <RltdPties><Dbtr><Nm>Buyer Pte</Nm></Dbtr></RltdPties>
+          <RmtInf><Ustrd>Payment TXN 778899</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="SGD">5.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2026-10-05</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`)
+	assert.Equal(t, enums.BankStatementFormatCAMT053, models.DetectBankStatementFormat(content))
+
+	lines, accountNumber, err := models.ParseBankStatement(enums.BankStatementFormatCAMT053, content)
+	assert.NoError(t, err)
+	assert.Equal(t, "SG00BANK0001", accountNumber)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, 980.0, lines[0].Amount.ToFloat64())
+	assert.Equal(t, enums.SGD, lines[0].Currency)
+	assert.Equal(t, "Payment TXN 778899", lines[0].Reference)
+	assert.Equal(t, "Buyer Pte", lines[0].Counterparty)
+	assert.Equal(t, "SVC-1", lines[0].BankRef)
+	assert.Equal(t, -5.0, lines[1].Amount.ToFloat64())
+	assert.Equal(t, enums.BankStatementLineStatusIgnored, lines[1].Status)
+}
+
+func TestBankStatement_Match(t *testing.T) {
+	var candidates = models.PaymentTransactions{
+		{Model: models.Model{ID: "tx1"}, ReferenceID: "PAY-ABC123", Currency: enums.USD, PaidAmount: price.NewFromFloat(1500).ToPtr()},
+		{Model: models.Model{ID: "tx2"}, ReferenceID: "PAY-XYZ999", TransactionRefID: "778899", Currency: enums.SGD, PaidAmount: price.NewFromFloat(1000).ToPtr()},
+		{Model: models.Model{ID: "tx3"}, ReferenceID: "PAY-QQQ111", Currency: enums.USD, PaidAmount: price.NewFromFloat(200).ToPtr()},
+		{Model: models.Model{ID: "tx4"}, ReferenceID: "PAY-QQQ222", Currency: enums.USD, PaidAmount: price.NewFromFloat(200).ToPtr()},
+		{Model: models.Model{ID: "tx5"}, ReferenceID: "PAY-RRR333", Currency: enums.VND, PaidAmount: price.NewFromFloat(300).ToPtr()},
+	}
+
+	var exact = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(1500), Currency: enums.USD, Description: "invoice payabc123"}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusMatched, exact.Status)
+	assert.Equal(t, "tx1", exact.PaymentTransaction.ID)
+	assert.Equal(t, 100, exact.Score)
+
+	var referenceOnly = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(980), Currency: enums.SGD, Reference: "Payment TXN 778899"}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusSuggested, referenceOnly.Status)
+	assert.Equal(t, "tx2", referenceOnly.PaymentTransaction.ID)
+
+	var amountOnly = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(300), Currency: enums.VND}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusSuggested, amountOnly.Status)
+	assert.Equal(t, "tx5", amountOnly.PaymentTransaction.ID)
+
+	var ambiguous = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(200), Currency: enums.USD}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusUnmatched, ambiguous.Status)
+	assert.Nil(t, ambiguous.PaymentTransaction)
+
+	var wrongCurrency = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(300), Currency: enums.USD}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusUnmatched, wrongCurrency.Status)
+
+	var debit = models.MatchBankStatementLine(&models.BankStatementLine{Amount: price.NewFromFloat(-1500), Currency: enums.USD, Reference: "PAY-ABC123"}, candidates)
+	assert.Equal(t, enums.BankStatementLineStatusUnmatched, debit.Status)
+}
+
+func TestBankStatement_Fingerprint(t *testing.T) {
+	var content = []byte(":20:STMT001\n:25:123456789\n:61:2610021002C100,00NTRFNONREF\n:86:Top up\n:61:2610021002C100,00NTRFNONREF\n:86:Top up\n-")
+
+	lines, accountNumber, err := models.ParseBankStatement(enums.BankStatementFormatMT940, content)
+	assert.NoError(t, err)
+	assert.Len(t, lines, 2)
+	assert.NotEqual(t, lines[0].Fingerprint, lines[1].Fingerprint, "identical transfers of the same day are both kept")
+
+	// An overlapping statement of the same account lists the same lines in the same order
+	overlapping, _, err := models.ParseBankStatement(enums.BankStatementFormatMT940, content)
+	assert.NoError(t, err)
+	assert.Equal(t, lines[0].Fingerprint, overlapping[0].Fingerprint)
+	assert.Equal(t, lines[1].Fingerprint, overlapping[1].Fingerprint)
+
+	assert.NotEqual(t, lines[0].Fingerprint, lines[0].GetFingerprint("987654321", 0), "another account books its own lines")
+	assert.Equal(t, lines[0].Fingerprint, lines[0].GetFingerprint(accountNumber, 0))
+}
+
+func TestBankStatement_MarkReconciledIsConditional(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	_, err := repo.NewBankReconciliationRepo(adb).MarkBankStatementLineReconciled(repo.BankStatementLineParams{
+		JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleSuperAdmin).SetUserID("admin_1"),
+	}, &models.BankStatementLine{Model: models.Model{ID: "line_1"}, PaymentTransactionID: "txn_1"})
+	// The fake driver affects no rows, as if a concurrent approval reconciled the line first
+	assert.ErrorIs(t, err, errs.ErrBankStatementLineNotApprovable)
+
+	var update, found = lo.Find(sqlRecorder.reset(), func(query string) bool {
+		return strings.HasPrefix(query, "UPDATE \"bank_statement_lines\"")
+	})
+	assert.True(t, found)
+	assert.Contains(t, update, "status NOT IN")
+}
+
+func TestBankStatement_ManualOverrideChecksAmount(t *testing.T) {
+	var adb = newSQLStubDB(t)
+	var params = repo.BankStatementLineParams{
+		JwtClaimsInfo:        *models.NewJwtClaimsInfo().SetRole(enums.RoleSuperAdmin).SetUserID("admin_1"),
+		BankStatementLineID:  "line_1",
+		PaymentTransactionID: "txn_2",
+	}
+	var stub = func(paidAmount string) {
+		sqlStub.reset()
+		sqlStub.stub(`FROM "bank_statement_lines"`, []string{"id", "amount", "currency", "status", "payment_transaction_id"},
+			[]driver.Value{"line_1", "100.00", "USD", string(enums.BankStatementLineStatusSuggested), "txn_1"})
+		sqlStub.stub(`FROM "payment_transactions"`, []string{"id", "paid_amount", "currency", "status"},
+			[]driver.Value{"txn_2", paidAmount, "USD", string(enums.PaymentStatusWaitingConfirm)})
+	}
+
+	stub("80.00")
+	_, err := repo.NewBankReconciliationRepo(adb).GetApprovableBankStatementLine(params)
+	assert.ErrorIs(t, err, errs.ErrBankStatementLineAmountMismatch)
+
+	// Forcing needs the reason
+	params.Force = true
+	stub("80.00")
+	_, err = repo.NewBankReconciliationRepo(adb).GetApprovableBankStatementLine(params)
+	assert.ErrorIs(t, err, errs.ErrBankStatementLineAmountMismatch)
+
+	params.Note = "Buyer paid the rest in cash"
+	stub("80.00")
+	line, err := repo.NewBankReconciliationRepo(adb).GetApprovableBankStatementLine(params)
+	assert.NoError(t, err)
+	assert.Equal(t, "txn_2", line.PaymentTransactionID)
+
+	params.Force = false
+	params.Note = ""
+	stub("100.00")
+	line, err = repo.NewBankReconciliationRepo(adb).GetApprovableBankStatementLine(params)
+	assert.NoError(t, err)
+	assert.Equal(t, "txn_2", line.PaymentTransactionID)
+}
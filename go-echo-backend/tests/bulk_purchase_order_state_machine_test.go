@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/go-utils/values"
+	"gorm.io/gorm"
+)
+
+func TestBulkPurchaseOrderStateMachine_FindTransition(t *testing.T) {
+	transition, err := repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusFirstPayment, enums.BulkPoTrackingActionMakeFirstPayment)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.BulkPoTrackingStatusFirstPaymentConfirm, transition.ToStatus(enums.BulkPoTrackingStatusFirstPayment))
+
+	_, err = repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusDelivered, enums.BulkPoTrackingActionMakeFirstPayment)
+	assert.ErrorIs(t, err, errs.ErrBulkPoInvalidTransition)
+
+	transition, err = repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusProduction, enums.BulkPoTrackingActionUpdatePps)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.BulkPoTrackingStatusProduction, transition.ToStatus(enums.BulkPoTrackingStatusProduction))
+}
+
+func TestBulkPurchaseOrderStateMachine_QcRequiresRawMaterialApproved(t *testing.T) {
+	var order = models.BulkPurchaseOrder{
+		TrackingStatus: enums.BulkPoTrackingStatusProduction,
+		PoRawMaterials: &models.PoRawMaterialMetas{
+			{ReferenceID: "RM-1", BuyerApproved: values.Bool(true)},
+			{ReferenceID: "RM-2"},
+		},
+	}
+
+	transition, err := repo.FindBulkPurchaseOrderTransition(order.TrackingStatus, enums.BulkPoTrackingActionMarkQc)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transition.Validate(&order, &models.BulkPurchaseOrder{}, enums.RoleStaff), errs.ErrBulkPoRawMaterialNotApproved)
+
+	(*order.PoRawMaterials)[1].BuyerApproved = values.Bool(true)
+	assert.NoError(t, transition.Validate(&order, &models.BulkPurchaseOrder{}, enums.RoleStaff))
+	assert.ErrorIs(t, transition.Validate(&order, &models.BulkPurchaseOrder{}, enums.RoleClient), errs.ErrBulkPoTransitionForbidden)
+}
+
+func TestBulkPurchaseOrderStateMachine_Diagram(t *testing.T) {
+	var diagram = repo.BulkPurchaseOrderStateDiagram()
+	assert.Contains(t, diagram, "stateDiagram-v2")
+	assert.Contains(t, diagram, fmt.Sprintf("%s --> %s", enums.BulkPoTrackingStatusQc, enums.BulkPoTrackingStatusSubmit))
+}
+
+func TestBulkPurchaseOrderStateMachine_QcRequiresRawMaterials(t *testing.T) {
+	transition, err := repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusProduction, enums.BulkPoTrackingActionMarkQc)
+	assert.NoError(t, err)
+
+	var order = models.BulkPurchaseOrder{TrackingStatus: enums.BulkPoTrackingStatusProduction}
+	assert.ErrorIs(t, transition.Validate(&order, &models.BulkPurchaseOrder{}, enums.RoleStaff), errs.ErrBulkPoRawMaterialNotApproved)
+
+	order.PoRawMaterials = &models.PoRawMaterialMetas{}
+	assert.ErrorIs(t, transition.Validate(&order, &models.BulkPurchaseOrder{}, enums.RoleStaff), errs.ErrBulkPoRawMaterialNotApproved)
+}
+
+func TestBulkPurchaseOrderStateMachine_ResetAndSkipFirstPayment(t *testing.T) {
+	transition, err := repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusFirstPayment, enums.BulkPoTrackingActionResetOrder)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transition.Validate(&models.BulkPurchaseOrder{FirstPaymentIntentID: "pi_1"}, &models.BulkPurchaseOrder{}, enums.RoleStaff), errs.ErrBulkPoFirstPaymentAlreaydPaid)
+
+	_, err = repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusRawMaterial, enums.BulkPoTrackingActionResetOrder)
+	assert.ErrorIs(t, err, errs.ErrBulkPoInvalidTransition)
+
+	transition, err = repo.FindBulkPurchaseOrderTransition(enums.BulkPoTrackingStatusFirstPayment, enums.BulkPoTrackingActionSkipFirstPayment)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transition.Validate(&models.BulkPurchaseOrder{FirstPaymentPercentage: values.Float64(30)}, &models.BulkPurchaseOrder{}, enums.RoleStaff), errs.ErrBulkPoInvalidTransition)
+	assert.NoError(t, transition.Validate(&models.BulkPurchaseOrder{FirstPaymentPercentage: values.Float64(0)}, &models.BulkPurchaseOrder{}, enums.RoleStaff))
+}
+
+func TestBulkPurchaseOrderStateMachine_SellerTransitions(t *testing.T) {
+	transition, err := repo.FindSellerBulkPurchaseOrderTransition("", enums.BulkPoTrackingActionAdminApproveSellerQuotation)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SellerBulkPoTrackingStatusPO, transition.ToStatus(""))
+	assert.ErrorIs(t, transition.Validate(&models.BulkPurchaseOrder{}, &models.BulkPurchaseOrder{}, enums.RoleSeller), errs.ErrBulkPoTransitionForbidden)
+
+	_, err = repo.FindSellerBulkPurchaseOrderTransition(enums.SellerBulkPoTrackingStatusPO, enums.BulkPoTrackingActionStartWithoutFirstPayment)
+	assert.ErrorIs(t, err, errs.ErrBulkPoInvalidTransition)
+
+	transition, err = repo.FindSellerBulkPurchaseOrderTransition(enums.SellerBulkPoTrackingStatusProduction, enums.BulkPoTrackingActionUpdateMaterial)
+	assert.NoError(t, err)
+	assert.Equal(t, enums.SellerBulkPoTrackingStatusProduction, transition.ToStatus(enums.SellerBulkPoTrackingStatusProduction), "a late raw material update keeps the status")
+
+	var diagram = repo.SellerBulkPurchaseOrderStateDiagram()
+	assert.Contains(t, diagram, fmt.Sprintf("[*] --> %s", enums.SellerBulkPoTrackingStatusPO))
+}
+
+func TestBulkPurchaseOrderStateMachine_SellerTransitionIsConditional(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+	var order = models.BulkPurchaseOrder{
+		Model:                models.Model{ID: "bpo_1"},
+		SellerID:             "seller_1",
+		SellerTrackingStatus: enums.SellerBulkPoTrackingStatusPO,
+	}
+
+	sqlRecorder.reset()
+	err := adb.Transaction(func(tx *gorm.DB) error {
+		return repo.NewSellerBulkPurchaseOrderRepo(adb).TransitionTx(tx, repo.SellerBulkPurchaseOrderTransitionParams{
+			JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleSeller).SetUserID("seller_1"),
+			Order:         &order,
+			Action:        enums.BulkPoTrackingActionSellerApprovePO,
+		})
+	})
+	// The fake driver affects no rows, as if another request moved the status first
+	assert.ErrorIs(t, err, errs.ErrBulkPoTrackingStatusChanged)
+
+	var queries = sqlRecorder.reset()
+	var update, found = lo.Find(queries, func(query string) bool {
+		return strings.HasPrefix(query, "UPDATE \"bulk_purchase_orders\"")
+	})
+	assert.True(t, found)
+	assert.Contains(t, update, "COALESCE(seller_tracking_status, '') =")
+	assert.Equal(t, enums.SellerBulkPoTrackingStatusPO, order.SellerTrackingStatus)
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/caching"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUnreachableCacheClient redis is down, GetOrLoad falls back to the loader
+func newUnreachableCacheClient() *caching.Client {
+	logger.Init(logger.WithDebug(false))
+
+	return caching.NewClient(redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		MaxRetries:  -1,
+		DialTimeout: time.Millisecond * 100,
+	}), "test")
+}
+
+func TestCaching_GetOrLoadSingleflight(t *testing.T) {
+	var client = newUnreachableCacheClient()
+	var calls int32
+
+	var wg sync.WaitGroup
+	var results = make([][]string, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = caching.GetOrLoad(context.Background(), client, "category_tree", func() ([]string, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(time.Millisecond * 300)
+				return []string{"shirts", "pants"}, nil
+			}, caching.WithTags(models.CacheTagCategory))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	for _, result := range results {
+		assert.Equal(t, []string{"shirts", "pants"}, result)
+	}
+}
+
+func TestCaching_GetOrLoadError(t *testing.T) {
+	var client = newUnreachableCacheClient()
+	var errLoad = errors.New("load failed")
+
+	_, err := caching.GetOrLoad(context.Background(), client, "page", func() (*models.Page, error) {
+		return nil, errLoad
+	})
+	assert.ErrorIs(t, err, errLoad)
+
+	// Nothing is cached without a client
+	var calls int
+	for i := 0; i < 2; i++ {
+		result, err := caching.GetOrLoad(context.Background(), nil, "page", func() (int, error) {
+			calls++
+			return 7, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestCaching_StatementTags(t *testing.T) {
+	var categories = []*models.Category{{ID: "c1"}, {ID: "c2"}}
+	var tags = caching.GetStatementTags(reflect.TypeOf(models.Category{}), reflect.ValueOf(&categories).Elem())
+	assert.ElementsMatch(t, []string{"category", "category:c1", "category:c2"}, lo.Uniq(tags))
+
+	var section = models.PageSection{PageID: "p1"}
+	tags = caching.GetStatementTags(reflect.TypeOf(models.PageSection{}), reflect.ValueOf(&section).Elem())
+	assert.ElementsMatch(t, []string{"page", "page:p1"}, lo.Uniq(tags))
+
+	// Updates through Model(&Page{}).Where(...) only know the kind of the rows
+	tags = caching.GetStatementTags(reflect.TypeOf(models.Page{}), reflect.ValueOf(&models.Page{}).Elem())
+	assert.Equal(t, []string{"page"}, lo.Uniq(tags))
+
+	assert.Empty(t, caching.GetStatementTags(reflect.TypeOf(models.User{}), reflect.ValueOf(&models.User{}).Elem()))
+}
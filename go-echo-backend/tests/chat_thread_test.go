@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatThread_CheckChange(t *testing.T) {
+	var now = time.Now()
+	var message = models.ChatMessage{
+		Model:    models.Model{ID: "msg_1", CreatedAt: now.Add(-time.Minute).Unix()},
+		SenderID: "buyer_1",
+	}
+
+	assert.NoError(t, message.CheckChange("buyer_1", enums.RoleClient, enums.ChatMessageEditActionEdit, now))
+	assert.NoError(t, message.CheckChange("buyer_1", enums.RoleClient, enums.ChatMessageEditActionDelete, now))
+	assert.Equal(t, errs.ErrChatMessageNotSender, message.CheckChange("seller_1", enums.RoleSeller, enums.ChatMessageEditActionDelete, now))
+	assert.Equal(t, errs.ErrChatMessageNotSender, message.CheckChange("admin_1", enums.RoleSuperAdmin, enums.ChatMessageEditActionEdit, now))
+	assert.NoError(t, message.CheckChange("admin_1", enums.RoleStaff, enums.ChatMessageEditActionDelete, now))
+
+	var later = now.Add(models.ChatMessageEditWindow)
+	assert.Equal(t, errs.ErrChatMessageEditWindowExpired, message.CheckChange("buyer_1", enums.RoleClient, enums.ChatMessageEditActionEdit, later))
+	assert.Equal(t, errs.ErrChatMessageEditWindowExpired, message.CheckChange("buyer_1", enums.RoleClient, enums.ChatMessageEditActionDelete, later))
+	assert.NoError(t, message.CheckChange("admin_1", enums.RoleLeader, enums.ChatMessageEditActionDelete, later))
+
+	var removedAt = now.Unix()
+	message.RemovedAt = &removedAt
+	assert.Equal(t, errs.ErrChatMessageRemoved, message.CheckChange("buyer_1", enums.RoleClient, enums.ChatMessageEditActionEdit, now))
+	assert.Equal(t, errs.ErrChatMessageRemoved, message.CheckChange("admin_1", enums.RoleSuperAdmin, enums.ChatMessageEditActionDelete, now))
+}
+
+func TestChatThread_ThreadID(t *testing.T) {
+	var root = models.ChatMessage{Model: models.Model{ID: "msg_1"}}
+	var reply = models.ChatMessage{Model: models.Model{ID: "msg_2"}, ReplyToID: "msg_1", ThreadID: "msg_1"}
+
+	assert.Equal(t, "msg_1", root.GetThreadID())
+	assert.Equal(t, "msg_1", reply.GetThreadID(), "a reply to a reply stays in the thread of the root")
+}
+
+func TestChatThread_ReactionSummaries(t *testing.T) {
+	var reactions = models.ChatMessageReactions{
+		{MessageID: "msg_1", UserID: "buyer_1", Emoji: "👍"},
+		{MessageID: "msg_1", UserID: "seller_1", Emoji: "🎉"},
+		{MessageID: "msg_2", UserID: "buyer_1", Emoji: "👍"},
+		{MessageID: "msg_1", UserID: "admin_1", Emoji: "👍"},
+	}
+
+	assert.Equal(t, []*models.ChatMessageReactionSummary{
+		{Emoji: "👍", Count: 3, UserIDs: []string{"buyer_1", "buyer_1", "admin_1"}},
+		{Emoji: "🎉", Count: 1, UserIDs: []string{"seller_1"}},
+	}, reactions.Summaries())
+
+	var groups = reactions.GroupByMessageID()
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []*models.ChatMessageReactionSummary{
+		{Emoji: "👍", Count: 2, UserIDs: []string{"buyer_1", "admin_1"}},
+		{Emoji: "🎉", Count: 1, UserIDs: []string{"seller_1"}},
+	}, groups["msg_1"])
+	assert.Equal(t, []*models.ChatMessageReactionSummary{
+		{Emoji: "👍", Count: 1, UserIDs: []string{"buyer_1"}},
+	}, groups["msg_2"])
+
+	assert.Empty(t, models.ChatMessageReactions{}.Summaries())
+}
+
+func TestChatThread_UpdateEmptyMessage(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	_, err := repo.NewChatRepo(adb).UpdateChatMessage(&models.UpdateChatMessageRequest{ChatMessageID: "msg_1"})
+	assert.Equal(t, errs.ErrChatMessageEmpty, err)
+	assert.Empty(t, sqlRecorder.reset())
+}
+
+// stubChatMessage the message returned by the chat message queries of the stub driver
+func stubChatMessage(id string, threadID string, removedAt interface{}) {
+	sqlStub.stub(`FROM "chat_messages"`,
+		[]string{"id", "created_at", "sender_id", "receiver_id", "thread_id", "message", "attachments", "removed_at"},
+		[]driver.Value{id, time.Now().Add(-time.Minute).Unix(), "buyer_1", "room_1", threadID, "hello", []byte(`[{"file_key":"a.png"}]`), removedAt},
+	)
+}
+
+func TestChatThread_UpdateKeepsAttachments(t *testing.T) {
+	var adb = newSQLStubDB(t)
+
+	sqlStub.reset()
+	stubChatMessage("msg_1", "", nil)
+	message, err := repo.NewChatRepo(adb).UpdateChatMessage(&models.UpdateChatMessageRequest{
+		JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleClient).SetUserID("buyer_1"),
+		ChatMessageID: "msg_1",
+		Message:       "hello again",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello again", message.Message)
+	assert.NotNil(t, message.Attachments)
+
+	var update, found = lo.Find(sqlStub.reset(), func(query string) bool {
+		return strings.HasPrefix(query, `UPDATE "chat_messages"`)
+	})
+	assert.True(t, found)
+	assert.Contains(t, update, `"message"=`)
+	assert.NotContains(t, update, `"attachments"`, "a text edit keeps the attachments")
+
+	assert.Equal(t, []string{"EditedAt", "Attachments"}, (&models.UpdateChatMessageRequest{Attachments: &models.Attachments{}}).GetUpdatedFields())
+}
+
+func TestChatThread_DeleteReplyDecrementsReplyCount(t *testing.T) {
+	var adb = newSQLStubDB(t)
+
+	sqlStub.reset()
+	stubChatMessage("msg_2", "msg_1", nil)
+	_, err := repo.NewChatRepo(adb).DeleteChatMessage(&models.DeleteChatMessageRequest{
+		JwtClaimsInfo: *models.NewJwtClaimsInfo().SetRole(enums.RoleClient).SetUserID("buyer_1"),
+		ChatMessageID: "msg_2",
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, lo.SomeBy(sqlStub.reset(), func(query string) bool {
+		return strings.HasPrefix(query, `UPDATE "chat_messages"`) && strings.Contains(query, "GREATEST(reply_count - 1, 0)")
+	}))
+}
+
+func TestChatThread_ReplyToDeletedMessage(t *testing.T) {
+	var adb = newSQLStubDB(t)
+
+	sqlStub.reset()
+	sqlStub.stub(`FROM "chat_rooms"`, []string{"id"}, []driver.Value{"room_1"})
+	sqlStub.stub(`FROM "users"`, []string{"id"}, []driver.Value{"buyer_1"})
+	stubChatMessage("msg_1", "", time.Now().Unix())
+	_, err := repo.NewChatRepo(adb).CreateChatMessage(&models.CreateChatMessageRequest{
+		ReceiverID: "room_1",
+		SenderID:   "buyer_1",
+		Message:    "reply",
+		ReplyToID:  "msg_1",
+	})
+	assert.Equal(t, errs.ErrChatMessageRemoved, err)
+
+	assert.False(t, lo.SomeBy(sqlStub.reset(), func(query string) bool {
+		return strings.HasPrefix(query, `INSERT INTO "chat_messages"`)
+	}))
+}
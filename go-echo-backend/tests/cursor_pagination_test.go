@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+var chatMessageCursorKeys = []query.CursorKey{
+	{Column: "c.created_at", Field: "CreatedAt", Desc: true},
+	{Column: "c.id", Field: "ID", Desc: true},
+}
+
+func newCursorTestBuilder(records ...*models.ChatMessage) *queryfunc.Builder {
+	return queryfunc.NewBuilder("SELECT c.* FROM chat_messages c", "SELECT 1 FROM chat_messages c").
+		WithOrderBy("c.created_at DESC").
+		WithPaginationFunc(func(db, rawSQL *db.DB) (interface{}, error) {
+			rows, err := rawSQL.Rows()
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			var result = append([]*models.ChatMessage{}, records...)
+			return &result, nil
+		})
+}
+
+func TestCursorPagination_Cursor(t *testing.T) {
+	var cursor = query.EncodeCursor(int64(1700000000), "msg_1")
+
+	values, err := query.DecodeCursor(cursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1700000000), "msg_1"}, values)
+
+	_, err = query.DecodeCursor(cursor, 1)
+	assert.Error(t, err)
+
+	_, err = query.DecodeCursor("not a cursor", 2)
+	assert.Error(t, err)
+
+	_, err = query.DecodeCursor(query.EncodeCursor(map[string]interface{}{"a": 1}, "msg_1"), 2)
+	assert.Error(t, err)
+}
+
+func TestCursorPagination_NextPage(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+	var records = []*models.ChatMessage{
+		{Model: models.Model{ID: "msg_3", CreatedAt: 300}},
+		{Model: models.Model{ID: "msg_2", CreatedAt: 200}},
+		{Model: models.Model{ID: "msg_1", CreatedAt: 200}},
+	}
+
+	sqlRecorder.reset()
+	var pagination = query.New(adb, newCursorTestBuilder(records...)).
+		Where("c.receiver_id = ?", "room_1").
+		Cursor("", chatMessageCursorKeys...).
+		WithCursorPaging(true).
+		WithCountMode(query.CountModeNone).
+		Limit(2).
+		PagingFunc()
+
+	var queries = sqlRecorder.reset()
+	assert.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "ORDER BY c.created_at DESC, c.id DESC LIMIT 3")
+	assert.NotContains(t, queries[0], "OFFSET")
+
+	assert.True(t, pagination.HasNext)
+	assert.False(t, pagination.HasPrev)
+	assert.Equal(t, 2, pagination.TotalCurrentRecord)
+	assert.Len(t, *pagination.Records.(*[]*models.ChatMessage), 2)
+	assert.Equal(t, query.EncodeCursor(int64(200), "msg_2"), pagination.NextCursor)
+
+	pagination = query.New(adb, newCursorTestBuilder(records[2])).
+		Where("c.receiver_id = ?", "room_1").
+		Cursor(pagination.NextCursor, chatMessageCursorKeys...).
+		Limit(2).
+		PagingFunc()
+
+	queries = sqlRecorder.reset()
+	assert.Len(t, queries, 1, "the total is only counted for the first page")
+	assert.Contains(t, queries[0], "((c.created_at < $2) OR (c.created_at = $3 AND c.id < $4))")
+
+	assert.False(t, pagination.HasNext)
+	assert.True(t, pagination.HasPrev)
+	assert.Empty(t, pagination.NextCursor)
+}
+
+func TestCursorPagination_LegacyPage(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	var pagination = query.New(adb, newCursorTestBuilder()).
+		Cursor("", chatMessageCursorKeys...).
+		WithCountMode(query.CountModeNone).
+		Page(3).
+		Limit(10).
+		PagingFunc()
+
+	var queries = sqlRecorder.reset()
+	assert.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "ORDER BY c.created_at DESC, c.id DESC LIMIT 10 OFFSET 20", "the unique key breaks the ties of the offset pages")
+	assert.Equal(t, 3, pagination.Page)
+	assert.Empty(t, pagination.NextCursor)
+}
+
+func TestCursorPagination_EstimateCount(t *testing.T) {
+	assert.Equal(t, 1234, query.GetPlanRows(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234}}]`))
+	assert.Equal(t, 0, query.GetPlanRows("not a plan"))
+
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	var pagination = query.New(adb, newCursorTestBuilder()).
+		Cursor("", chatMessageCursorKeys...).
+		WithCursorPaging(true).
+		WithCountMode(query.CountModeEstimate).
+		Limit(10).
+		PagingFunc()
+
+	var queries = sqlRecorder.reset()
+	assert.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "EXPLAIN (FORMAT JSON) SELECT 1 FROM chat_messages c")
+	assert.True(t, pagination.IsEstimatedTotal)
+}
+
+func TestCursorPagination_OptIn(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	var pagination = query.New(adb, newCursorTestBuilder()).
+		Cursor("", chatMessageCursorKeys...).
+		Page(1).
+		Limit(10).
+		PagingFunc()
+
+	var queries = sqlRecorder.reset()
+	assert.Len(t, queries, 2, "the first page without paging=cursor keeps the offset and the exact count")
+	assert.True(t, lo.SomeBy(queries, func(query string) bool {
+		return strings.Contains(query, "ORDER BY c.created_at DESC, c.id DESC LIMIT 10")
+	}))
+	assert.Equal(t, 1, pagination.Page)
+	assert.Empty(t, pagination.NextCursor)
+
+	assert.False(t, models.CursorParams{}.IsCursorPaging())
+	assert.True(t, models.CursorParams{Paging: "cursor"}.IsCursorPaging())
+	assert.True(t, models.CursorParams{Cursor: query.EncodeCursor(int64(1), "msg_1")}.IsCursorPaging())
+}
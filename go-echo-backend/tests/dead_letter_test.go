@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter_Reference(t *testing.T) {
+	referenceType, referenceID := worker.GetDeadLetterReference([]byte(`{"purchase_order_id":"po1","user_id":"u1","is_admin":true}`))
+	assert.Equal(t, "purchase_order", referenceType)
+	assert.Equal(t, "po1", referenceID)
+
+	referenceType, referenceID = worker.GetDeadLetterReference([]byte(`{"bulk_purchase_order_id":"bpo1","purchase_order_id":""}`))
+	assert.Equal(t, "bulk_purchase_order", referenceType)
+	assert.Equal(t, "bpo1", referenceID)
+
+	referenceType, referenceID = worker.GetDeadLetterReference([]byte(`{"name":"x"}`))
+	assert.Empty(t, referenceType)
+	assert.Empty(t, referenceID)
+
+	referenceType, referenceID = worker.GetDeadLetterReference([]byte(`not json`))
+	assert.Empty(t, referenceType)
+	assert.Empty(t, referenceID)
+}
+
+func TestDeadLetter_ReferenceEnvelope(t *testing.T) {
+	var task = worker.NewTaskType("dead_letter_test", 1, func(ctx context.Context, payload map[string]string) error {
+		return nil
+	})
+	data, err := task.NewPayload(context.Background(), map[string]string{"inquiry_id": "iq1"})
+	assert.NoError(t, err)
+
+	referenceType, referenceID := worker.GetDeadLetterReference(data)
+	assert.Equal(t, "inquiry", referenceType)
+	assert.Equal(t, "iq1", referenceID)
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFxSnapshot_ApplyTo(t *testing.T) {
+	var rate = 25000.0
+	var snapshot = &models.FxSnapshot{FxBaseCurrency: enums.USD, FxRate: &rate}
+
+	var order models.PurchaseOrder
+	order.SubTotal = price.NewFromInt(2_000_000).ToPtr()
+	order.TotalPrice = price.NewFromInt(2_500_000).ToPtr()
+	snapshot.ApplyTo(&order.Pricing, order.Pricing)
+
+	assert.Equal(t, 80.0, order.BaseSubTotal.ToFloat64())
+	assert.Equal(t, 100.0, order.BaseTotalPrice.ToFloat64())
+	assert.Equal(t, "25000", order.FxSnapshot.FormatRate())
+
+	var empty *models.FxSnapshot
+	var other models.PurchaseOrder
+	other.TotalPrice = price.NewFromInt(10).ToPtr()
+	empty.ApplyTo(&other.Pricing, other.Pricing)
+	assert.Nil(t, other.BaseTotalPrice)
+	assert.Equal(t, "", other.FxSnapshot.FormatRate())
+}
+
+func TestFxRate_ParseCSV(t *testing.T) {
+	rates, err := models.ParseFxRatesCSV(strings.NewReader("base_currency,quote_currency,rate,effective_date\nusd,VND,25350.5,2026-10-01\nUSD,SGD,1.35,1790000000\n"))
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.Equal(t, enums.USD, rates[0].BaseCurrency)
+	assert.Equal(t, enums.VND, rates[0].QuoteCurrency)
+	assert.Equal(t, 25350.5, rates[0].Rate)
+	assert.Equal(t, int64(1790812800), rates[0].EffectiveAt)
+	assert.Equal(t, enums.FxRateSourceCSV, rates[0].Source)
+	assert.Equal(t, int64(1790000000), rates[1].EffectiveAt)
+
+	_, err = models.ParseFxRatesCSV(strings.NewReader("USD,VND,abc,2026-10-01\n"))
+	assert.Error(t, err)
+
+	_, err = models.ParseFxRatesCSV(strings.NewReader("USD,USD,1,2026-10-01\n"))
+	assert.Error(t, err)
+}
+
+func TestCurrency_GetCountryCode(t *testing.T) {
+	assert.Equal(t, enums.CountryCodeVN, enums.VND.GetCountryCode())
+	assert.Equal(t, enums.CountryCodeSG, enums.SGD.GetCountryCode())
+	assert.Equal(t, enums.CountryCodeUS, enums.USD.GetCountryCode())
+}
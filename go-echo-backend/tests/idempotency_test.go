@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/app"
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/middlewares"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency_ValidateKey(t *testing.T) {
+	assert.NoError(t, middlewares.ValidateIdempotencyKey("8e03978e-40d5-43e8-bc93-6894a57f9324"))
+	assert.ErrorIs(t, middlewares.ValidateIdempotencyKey(""), errs.ErrIdempotencyKeyInvalid)
+	assert.ErrorIs(t, middlewares.ValidateIdempotencyKey("has space"), errs.ErrIdempotencyKeyInvalid)
+	assert.ErrorIs(t, middlewares.ValidateIdempotencyKey(strings.Repeat("a", 256)), errs.ErrIdempotencyKeyInvalid)
+}
+
+func TestIdempotency_Fingerprint(t *testing.T) {
+	var fingerprint = middlewares.GetRequestFingerprint(http.MethodPost, "/api/v1/buyer/order_cart/checkout", []byte(`{"a":1}`))
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, middlewares.GetRequestFingerprint(http.MethodPost, "/api/v1/buyer/order_cart/checkout", []byte(`{"a":1}`)))
+	assert.NotEqual(t, fingerprint, middlewares.GetRequestFingerprint(http.MethodPost, "/api/v1/buyer/order_cart/checkout", []byte(`{"a":2}`)))
+	assert.NotEqual(t, fingerprint, middlewares.GetRequestFingerprint(http.MethodPut, "/api/v1/buyer/order_cart/checkout", []byte(`{"a":1}`)))
+}
+
+func TestIdempotency_SkipWithoutKey(t *testing.T) {
+	var e = echo.New()
+	var m = &middlewares.Middleware{Echo: e, App: &app.App{}}
+	var calls int
+	e.POST("/checkout", func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusOK)
+	}, m.Idempotency())
+
+	for i := 0; i < 2; i++ {
+		var rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/checkout", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(middlewares.HeaderIdempotentReplayed))
+	}
+	assert.Equal(t, 2, calls)
+
+	var req = httptest.NewRequest(http.MethodPost, "/checkout", nil)
+	req.Header.Set(middlewares.HeaderIdempotencyKey, strings.Repeat("a", 256))
+	var err = m.Idempotency()(func(c echo.Context) error {
+		calls++
+		return nil
+	})(e.NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, errs.ErrIdempotencyKeyInvalid)
+	assert.Equal(t, 2, calls)
+}
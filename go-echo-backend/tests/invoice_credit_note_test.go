@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoice_CreditNote(t *testing.T) {
+	var invoice = models.Invoice{
+		InvoiceType:    enums.InvoiceTypeInquiry,
+		Status:         enums.InvoiceStatusPaid,
+		RefundedAmount: price.NewFromFloat(30).ToPtr(),
+	}
+	invoice.TotalPrice = price.NewFromFloat(100).ToPtr()
+
+	assert.Equal(t, 70.0, invoice.GetRefundableAmount().ToFloat64())
+	assert.Equal(t, "", invoice.GetPDFMarker())
+
+	var creditNote = models.Invoice{InvoiceType: enums.InvoiceTypeCreditNote}
+	assert.True(t, creditNote.IsCreditNote())
+	assert.Equal(t, "CREDIT NOTE", creditNote.GetPDFMarker())
+	assert.Equal(t, "CN-VN-2026-00001", models.NewInvoiceSeries(creditNote.InvoiceType, enums.VND, 2026).Format(1))
+
+	invoice.Status = enums.InvoiceStatusVoid
+	assert.Equal(t, "VOID", invoice.GetPDFMarker())
+}
+
+func TestInvoice_CreditNoteStatus(t *testing.T) {
+	var err = validator.New().Struct(models.Invoice{Status: enums.InvoiceStatusCredited})
+	assert.NoError(t, err)
+
+	err = validator.New().Struct(models.Invoice{Status: enums.InvoiceStatusRefunded})
+	assert.NoError(t, err)
+
+	err = validator.New().Struct(models.Invoice{Status: "reversed"})
+	assert.Error(t, err)
+	assert.Equal(t, "Credited", enums.InvoiceStatusCredited.DisplayName())
+}
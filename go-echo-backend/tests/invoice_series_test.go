@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestInvoiceSeries_Format(t *testing.T) {
+	var series = models.NewInvoiceSeries(enums.InvoiceTypeInquiry, enums.VND, 2026)
+	assert.Equal(t, "INV-VN-2026-00042", series.Format(42))
+
+	series = models.NewInvoiceSeries(enums.InvoiceTypeBulkPOMilestonePayment, "", 2026)
+	assert.Equal(t, enums.USD, series.Currency)
+	assert.Equal(t, "DN-US-2026-00001", series.Format(1))
+
+	series.Padding = 3
+	assert.Equal(t, "DN-US-2026-1234", series.Format(1234))
+}
+
+func TestInvoiceSeries_DisplayNumberFallback(t *testing.T) {
+	var invoice = models.Invoice{InvoiceNumber: 17}
+	assert.Equal(t, "17", invoice.GetDisplayNumber())
+
+	invoice.DisplayNumber = "INV-US-2026-00001"
+	assert.Equal(t, "INV-US-2026-00001", invoice.GetDisplayNumber())
+}
+
+func TestInvoiceSeries_NextNumberRequiresTransaction(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	sqlRecorder.reset()
+	_, _, err := models.NextInvoiceSeriesNumberTx(adb.DB, enums.InvoiceTypeInquiry, enums.USD, 2026)
+	assert.Error(t, err)
+	assert.Empty(t, sqlRecorder.reset(), "no number is taken outside of a transaction")
+
+	_ = adb.Transaction(func(tx *gorm.DB) error {
+		_, _, err = models.NextInvoiceSeriesNumberTx(tx, enums.InvoiceTypeInquiry, enums.USD, 2026)
+		return err
+	})
+
+	var queries = sqlRecorder.reset()
+	assert.True(t, len(queries) >= 2)
+	assert.Contains(t, queries[1], "FOR UPDATE")
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedger_InvoiceJournalEntry(t *testing.T) {
+	var invoice = models.Invoice{
+		InvoiceNumber: 12,
+		InvoiceType:   enums.InvoiceTypeInquiry,
+		IssuedDate:    1790812800,
+		Currency:      enums.USD,
+	}
+	invoice.TotalPrice = price.NewFromFloat(118.5).ToPtr()
+	invoice.ShippingFee = price.NewFromInt(10).ToPtr()
+	invoice.TransactionFee = price.NewFromFloat(3.5).ToPtr()
+	invoice.Tax = price.NewFromInt(5).ToPtr()
+
+	var entry = models.NewInvoiceJournalEntry(&invoice)
+	assert.True(t, entry.IsBalanced())
+	assert.Equal(t, enums.JournalSourceTypeInvoice, entry.SourceType)
+	assert.Equal(t, "12", entry.SourceID)
+	assert.Len(t, entry.Lines, 5)
+	assert.Equal(t, enums.LedgerAccountSalesRevenue, entry.Lines[1].AccountCode)
+	assert.Equal(t, 100.0, entry.Lines[1].Credit.ToFloat64())
+}
+
+func TestLedger_PaymentTransactionJournalEntries(t *testing.T) {
+	var transaction = models.PaymentTransaction{
+		ReferenceID: "PAY-1",
+		PaymentType: enums.PaymentTypeCard,
+		Currency:    enums.USD,
+		PaidAmount:  price.NewFromInt(100).ToPtr(),
+		Fee:         price.NewFromFloat(3.2).ToPtr(),
+		Status:      enums.PaymentStatusPaid,
+	}
+	transaction.ID = "tx1"
+
+	var entries = models.NewPaymentTransactionJournalEntries(&transaction)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, enums.JournalSourceTypePayment, entries[0].SourceType)
+	assert.Equal(t, enums.LedgerAccountStripeClearing, entries[0].Lines[0].AccountCode)
+	assert.Equal(t, enums.JournalSourceTypeProcessingFee, entries[1].SourceType)
+	for _, entry := range entries {
+		assert.True(t, entry.IsBalanced())
+	}
+
+	transaction.TransactionType = enums.TransactionTypeDebit
+	transaction.PaymentType = enums.PaymentTypeBankTransfer
+	entries = models.NewPaymentTransactionJournalEntries(&transaction)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, enums.JournalSourceTypeSellerPayout, entries[0].SourceType)
+	assert.Equal(t, enums.LedgerAccountCostOfGoodsSold, entries[0].Lines[0].AccountCode)
+	assert.Equal(t, enums.LedgerAccountBank, entries[0].Lines[1].AccountCode)
+}
+
+func TestLedger_BulkDeductionJournalEntries(t *testing.T) {
+	var order = models.BulkPurchaseOrder{
+		ReferenceID:                 "BPO-1",
+		SampleDeductionAmount:       price.NewFromInt(50).ToPtr(),
+		FinalPaymentDeductionAmount: price.NewFromInt(0).ToPtr(),
+	}
+	order.ID = "bpo1"
+
+	var entries = models.NewBulkDeductionJournalEntries(&order)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, enums.JournalSourceTypeSampleDeduction, entries[0].SourceType)
+	assert.True(t, entries[0].IsBalanced())
+}
+
+func TestLedger_ExportCSV(t *testing.T) {
+	var entry = &models.JournalEntry{
+		SourceType: enums.JournalSourceTypeRefund,
+		EntryDate:  1790812800,
+		Reference:  "PAY-2",
+		Currency:   enums.USD,
+	}
+	entry.ID = "je1"
+	entry.Debit(enums.LedgerAccountAccountsReceivable, price.NewFromInt(20)).
+		Credit(enums.LedgerAccountBank, price.NewFromInt(20))
+
+	xero, err := models.JournalEntries{entry}.ToXeroCSV()
+	assert.NoError(t, err)
+	var xeroLines = strings.Split(strings.TrimSpace(string(xero)), "\n")
+	assert.Len(t, xeroLines, 3)
+	assert.Contains(t, xeroLines[1], ",01/10/2026,")
+	assert.Contains(t, xeroLines[1], ",20.00,")
+	assert.Contains(t, xeroLines[2], ",-20.00,")
+
+	quickBooks, err := models.JournalEntries{entry}.ToQuickBooksCSV()
+	assert.NoError(t, err)
+	var quickBooksLines = strings.Split(strings.TrimSpace(string(quickBooks)), "\n")
+	assert.Len(t, quickBooksLines, 3)
+	assert.True(t, strings.HasPrefix(quickBooksLines[2], "je1,10/01/2026,USD,"))
+	assert.True(t, strings.HasSuffix(quickBooksLines[2], ",,20.00,"))
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/locker"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUnreachableLocker redis is down, every attempt fails
+func newUnreachableLocker() *locker.Locker {
+	logger.Init(logger.WithDebug(false))
+
+	return locker.NewLocker(redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		MaxRetries:  -1,
+		DialTimeout: time.Millisecond * 100,
+	}))
+}
+
+func TestLocker_AcquireContext(t *testing.T) {
+	var l = newUnreachableLocker()
+
+	_, err := l.TryAcquire(context.Background(), "inquiry_1")
+	assert.Error(t, err)
+	assert.False(t, eris.Is(err, locker.ErrLockNotAcquired))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*300)
+	defer cancel()
+
+	var start = time.Now()
+	_, err = l.Acquire(ctx, "inquiry_1", locker.WithTTL(time.Second*10))
+	assert.True(t, eris.Is(err, locker.ErrLockNotAcquired))
+	assert.Less(t, time.Since(start), time.Second*2)
+
+	var stats = l.Stats()
+	assert.Equal(t, int64(1), stats.Timeouts)
+	assert.GreaterOrEqual(t, stats.Errors, int64(2))
+	assert.Equal(t, int64(0), stats.Acquired)
+	assert.Equal(t, int64(0), stats.Held)
+}
+
+// newRedisLocker locker over the redis of REDIS_ADDRESS, the test is skipped when it is not running
+func newRedisLocker(t *testing.T) (*locker.Locker, *redis.Client) {
+	logger.Init(logger.WithDebug(false))
+
+	var addr = os.Getenv("REDIS_ADDRESS")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+
+	var client = redis.NewClient(&redis.Options{Addr: addr, DialTimeout: time.Millisecond * 200})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("redis %s is not reachable: %v", addr, err)
+	}
+
+	return locker.NewLocker(client), client
+}
+
+func TestLocker_WatchdogExtendsLease(t *testing.T) {
+	var l, _ = newRedisLocker(t)
+	var key = fmt.Sprintf("test_watchdog_%d", time.Now().UnixNano())
+
+	lease, err := l.TryAcquire(context.Background(), key, locker.WithTTL(time.Millisecond*300))
+	assert.NoError(t, err)
+
+	// Held for several TTLs, the watchdog keeps it
+	time.Sleep(time.Second)
+	assert.NoError(t, lease.Context().Err())
+
+	_, err = l.TryAcquire(context.Background(), key)
+	assert.True(t, eris.Is(err, locker.ErrLockNotAcquired))
+	assert.Greater(t, l.Stats().Extended, int64(0))
+
+	assert.NoError(t, lease.Release())
+	assert.Error(t, lease.Context().Err())
+
+	next, err := l.TryAcquire(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NoError(t, next.Release())
+}
+
+func TestLocker_LeaseLost(t *testing.T) {
+	var l, _ = newRedisLocker(t)
+	var key = fmt.Sprintf("test_lost_%d", time.Now().UnixNano())
+
+	lease, err := l.TryAcquire(context.Background(), key, locker.WithTTL(time.Millisecond*300), locker.WithoutWatchdog())
+	assert.NoError(t, err)
+
+	// Without the watchdog the lease expires and another holder takes the key
+	time.Sleep(time.Millisecond * 500)
+	next, err := l.TryAcquire(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Greater(t, next.Token(), lease.Token())
+
+	assert.True(t, eris.Is(lease.Extend(context.Background()), locker.ErrLockLost))
+	assert.True(t, eris.Is(lease.Release(), locker.ErrLockLost))
+	assert.NoError(t, next.Release())
+}
+
+func TestLocker_FencingToken(t *testing.T) {
+	var l, client = newRedisLocker(t)
+	var key = fmt.Sprintf("test_fence_%d", time.Now().UnixNano())
+
+	first, err := l.TryAcquire(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NoError(t, first.Release())
+
+	second, err := l.TryAcquire(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NoError(t, second.Release())
+	assert.Greater(t, second.Token(), first.Token())
+
+	var fenceKey = fmt.Sprintf("inflow_locker_%s_fence", key)
+	ttl, err := client.PTTL(context.Background(), fenceKey).Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0), "the token key expires")
+
+	// An expired token key is seeded from the clock, the tokens keep increasing
+	assert.NoError(t, client.Del(context.Background(), fenceKey).Err())
+	third, err := l.TryAcquire(context.Background(), key)
+	assert.NoError(t, err)
+	assert.NoError(t, third.Release())
+	assert.Greater(t, third.Token(), second.Token())
+
+	// Writes of an older lease are fenced out of rows written by a newer one
+	var adb = newSQLRecorderDB(t)
+	sqlRecorder.reset()
+	adb.Model(&models.Invoice{}).Scopes(second.Fence("document_fence")).Where("invoice_number = ?", 1).
+		UpdateColumns(&models.Invoice{DocumentFence: second.Token()})
+	var queries = sqlRecorder.reset()
+	assert.Len(t, queries, 1)
+	assert.Contains(t, queries[0], "COALESCE(document_fence, 0) <=")
+}
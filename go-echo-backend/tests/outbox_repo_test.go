@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/app"
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/engineeringinflow/inflow-backend/services/consumer/tasks"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOutboxRepo_AddTasksTx(t *testing.T) {
+	var app = initApp("dev")
+
+	outboxTask, err := tasks.HubspotSyncBulkTask.Outbox(context.Background(), tasks.HubspotSyncBulkPayload{
+		BulkPurchaseOrderID: "cn6s883kl4pkf4p8bnm1",
+		UserID:              "cn6s883kl4pkf4p8bnm2",
+	})
+	assert.NoError(t, err)
+
+	err = app.DB.Transaction(func(tx *gorm.DB) error {
+		return repo.NewOutboxRepo(app.DB).AddTasksTx(tx, "cn6s883kl4pkf4p8bnm1", outboxTask)
+	})
+	assert.NoError(t, err)
+
+	var result = repo.NewOutboxRepo(app.DB).PaginateOutboxMessages(repo.PaginateOutboxMessagesParams{
+		ReferenceID: "cn6s883kl4pkf4p8bnm1",
+		Statuses:    []enums.OutboxStatus{enums.OutboxStatusPending},
+	})
+
+	var messages, _ = result.Records.([]*models.OutboxMessage)
+	assert.True(t, lo.SomeBy(messages, func(message *models.OutboxMessage) bool {
+		return message.TaskName == "hubspot_sync_bulk" && message.DedupeKey == message.ID
+	}))
+}
+
+func TestOutboxRepo_DedupeOnlyPending(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	outboxTask, err := tasks.CreateBulkPoMilestoneInvoiceTask.Outbox(context.Background(), tasks.CreateBulkPoMilestoneInvoicePayload{
+		BulkPurchaseOrderID: "bpo_1",
+		PaymentMilestoneID:  "milestone_1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "create_bulk_po_milestone_invoice:milestone_1", outboxTask.DedupeKey())
+
+	sqlRecorder.reset()
+	err = adb.Transaction(func(tx *gorm.DB) error {
+		return repo.NewOutboxRepo(adb).AddTasksTx(tx, "bpo_1", outboxTask)
+	})
+	assert.NoError(t, err)
+
+	var insert, found = lo.Find(sqlRecorder.reset(), func(query string) bool {
+		return strings.HasPrefix(query, "INSERT INTO \"outbox_messages\"")
+	})
+	assert.True(t, found)
+	// A dispatched row with the same key does not block the write
+	assert.Contains(t, insert, "ON CONFLICT (\"dedupe_key\")")
+	assert.Contains(t, insert, "WHERE \"status\" =")
+}
+
+// newOutboxRelayWorker worker relaying to the redis at addr, nothing is served
+func newOutboxRelayWorker(addr string) *worker.Worker {
+	logger.Init(logger.WithDebug(false))
+
+	return &worker.Worker{
+		Config: &worker.Config{
+			Logger: logger.New("test/OutboxRelay"),
+			App:    &app.App{},
+		},
+		Client: asynq.NewClient(asynq.RedisClientOpt{Addr: addr, DialTimeout: time.Millisecond * 100}),
+	}
+}
+
+func TestOutboxRelay_Retry(t *testing.T) {
+	var w = newOutboxRelayWorker("127.0.0.1:1")
+	var message = &models.OutboxMessage{
+		Model:    models.Model{ID: helper.GenerateXID()},
+		TaskName: "hubspot_sync_bulk",
+		Payload:  []byte(`{}`),
+		Status:   enums.OutboxStatusPending,
+	}
+
+	// Redis is down, the row stays pending with a backoff
+	var now = time.Now().Unix()
+	var updates = w.RelayOutboxMessage(context.Background(), message)
+	assert.Equal(t, enums.OutboxStatusPending, updates["status"])
+	assert.Equal(t, 1, updates["attempts"])
+	assert.NotEmpty(t, updates["last_error"])
+	assert.GreaterOrEqual(t, updates["next_attempt_at"], now+2)
+
+	// The last attempt gives up
+	message.Attempts = 19
+	updates = w.RelayOutboxMessage(context.Background(), message)
+	assert.Equal(t, enums.OutboxStatusFailed, updates["status"])
+	assert.Equal(t, 20, updates["attempts"])
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	var addr = os.Getenv("REDIS_ADDRESS")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+
+	var client = redis.NewClient(&redis.Options{Addr: addr, DialTimeout: time.Millisecond * 200})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("redis %s is not reachable: %v", addr, err)
+	}
+
+	var w = newOutboxRelayWorker(addr)
+	var message = &models.OutboxMessage{
+		Model:     models.Model{ID: helper.GenerateXID()},
+		TaskName:  "hubspot_sync_bulk",
+		Payload:   []byte(`{}`),
+		Queue:     worker.QueueNameLow,
+		DedupeKey: "hubspot_sync_bulk:bpo_1",
+		Status:    enums.OutboxStatusPending,
+		ProcessAt: time.Now().Add(time.Hour).Unix(),
+	}
+
+	var updates = w.RelayOutboxMessage(context.Background(), message)
+	assert.Equal(t, enums.OutboxStatusDispatched, updates["status"])
+	assert.Equal(t, message.ID, updates["task_id"])
+
+	// A row relayed again after a crash does not enqueue a second task
+	updates = w.RelayOutboxMessage(context.Background(), message)
+	assert.Equal(t, enums.OutboxStatusDispatched, updates["status"])
+	assert.Equal(t, message.ID, updates["task_id"])
+
+	var inspector = asynq.NewInspector(asynq.RedisClientOpt{Addr: addr})
+	info, err := inspector.GetTaskInfo(worker.QueueNameLow, message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, asynq.TaskStateScheduled, info.State)
+	assert.NoError(t, inspector.DeleteTask(worker.QueueNameLow, message.ID))
+}
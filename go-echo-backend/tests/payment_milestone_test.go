@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/price"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/go-utils/values"
+)
+
+func TestPaymentMilestone_BuildSchedule(t *testing.T) {
+	var order models.BulkPurchaseOrder
+	order.TotalPrice = price.NewFromFloat(1000.01).ToPtr()
+
+	milestones, err := repo.BuildPaymentMilestoneSchedule(&order, []*models.PaymentMilestoneForm{
+		{Name: "Deposit", Percentage: values.Float64(30), DueTrigger: enums.PaymentMilestoneDueTriggerOnQuotation},
+		{Name: "Materials", Percentage: values.Float64(30), DueTrigger: enums.PaymentMilestoneDueTriggerBeforeProduction},
+		{Name: "After QC", Percentage: values.Float64(30), DueTrigger: enums.PaymentMilestoneDueTriggerAfterQc},
+		{Name: "Net 60", Percentage: values.Float64(10), DueTrigger: enums.PaymentMilestoneDueTriggerAfterDelivery, DueDays: 60},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 4)
+	assert.Equal(t, enums.PaymentMilestoneFirstPayment, milestones[0].Milestone)
+	assert.Equal(t, enums.PaymentMilestone(""), milestones[1].Milestone)
+	assert.Equal(t, enums.PaymentMilestoneFinalPayment, milestones[3].Milestone)
+
+	var sum price.Price
+	for _, m := range milestones {
+		sum = sum.AddPtr(m.Total)
+	}
+	assert.Equal(t, order.TotalPrice.ToFloat64(), sum.ToFloat64())
+
+	_, err = repo.BuildPaymentMilestoneSchedule(&order, []*models.PaymentMilestoneForm{
+		{Name: "First", Percentage: values.Float64(50)},
+		{Name: "Final", Percentage: values.Float64(40)},
+	})
+	assert.ErrorIs(t, err, errs.ErrPaymentMilestoneInvalidSchedule)
+
+	milestones, err = repo.BuildPaymentMilestoneSchedule(&order, []*models.PaymentMilestoneForm{
+		{Name: "Deposit", Amount: price.NewFromFloat(200.01).ToPtr()},
+		{Name: "Final", Percentage: values.Float64(80)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0008, values.Float64Value(milestones[0].Percentage))
+	assert.Equal(t, 800.0, milestones[1].Total.ToFloat64())
+}
+
+func TestPaymentMilestone_Legacy(t *testing.T) {
+	var paidAt int64 = 1700000000
+	var order = models.BulkPurchaseOrder{
+		FirstPaymentPercentage:   values.Float64(40),
+		FirstPaymentTotal:        price.NewFromFloat(400).ToPtr(),
+		FirstPaymentMarkAsPaidAt: &paidAt,
+		FinalPaymentTotal:        price.NewFromFloat(600).ToPtr(),
+	}
+
+	var milestones = order.LegacyPaymentMilestones()
+	assert.Len(t, milestones, 2)
+	assert.Equal(t, enums.PaymentStatusPaid, milestones[0].Status)
+	assert.Equal(t, enums.PaymentStatusPending, milestones[1].Status)
+	assert.Equal(t, 60.0, values.Float64Value(milestones[1].Percentage))
+
+	var updates = milestones[0].LegacyColumnUpdates()
+	assert.Equal(t, paidAt, updates["first_payment_mark_as_paid_at"])
+
+	milestones[1].Milestone = ""
+	assert.Nil(t, milestones[1].LegacyColumnUpdates())
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductFacet_Matches(t *testing.T) {
+	assert.Empty(t, repo.GetProductFacetMatches(repo.PaginateProductParams{}))
+
+	var matches = repo.GetProductFacetMatches(repo.PaginateProductParams{
+		CategoryIDs: []string{"cate_1", "cate_2"},
+		MinOrders:   []enums.FilterMinOrder{enums.FilterRating500, enums.FilterRating100},
+		Ratings:     []enums.FilterRating{enums.FilterRating4},
+		PriceTiers:  []enums.FilterPriceTier{enums.FilterPriceTierUnder5, enums.FilterPriceTierOver50},
+	})
+	assert.Len(t, matches, 4)
+	assert.NotContains(t, matches, enums.ProductFacetFabric)
+
+	assert.Equal(t, "p.category_id IN @facet_category_ids", matches[enums.ProductFacetCategory].Condition)
+	assert.Equal(t, []string{"cate_1", "cate_2"}, matches[enums.ProductFacetCategory].Args[0].Value)
+
+	// The lowest threshold covers the other selected ones
+	assert.Equal(t, 100, matches[enums.ProductFacetMinOrder].Args[0].Value)
+	assert.Equal(t, 4, matches[enums.ProductFacetRating].Args[0].Value)
+
+	assert.Equal(t, "(p.price >= 0 AND p.price < 5) OR p.price >= 50", matches[enums.ProductFacetPriceTier].Condition)
+	assert.Empty(t, matches[enums.ProductFacetPriceTier].Args)
+}
+
+func TestProductFacet_UnknownOptions(t *testing.T) {
+	var matches = repo.GetProductFacetMatches(repo.PaginateProductParams{
+		MinOrders:  []enums.FilterMinOrder{7},
+		Ratings:    []enums.FilterRating{9},
+		PriceTiers: []enums.FilterPriceTier{"1; DROP TABLE products"},
+	})
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/app"
+	"github.com/engineeringinflow/inflow-backend/pkg/middlewares"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResponseCacheEcho() *echo.Echo {
+	var e = echo.New()
+	var m = &middlewares.Middleware{Echo: e, App: &app.App{}}
+	var cache = m.CacheResponse(time.Minute, models.CacheTagPost)
+
+	e.GET("/posts/:slug", func(c echo.Context) error {
+		if c.Param("slug") == "missing" {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "not found"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"slug": c.Param("slug")})
+	}, cache)
+
+	return e
+}
+
+func TestResponseCache_ETag(t *testing.T) {
+	var e = newResponseCacheEcho()
+
+	var rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts/hello", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"slug":"hello"}`, rec.Body.String())
+	assert.Equal(t, "MISS", rec.Header().Get(middlewares.HeaderXCache))
+	assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderLastModified))
+
+	var etag = rec.Header().Get("ETag")
+	assert.Equal(t, middlewares.NewResponseETag(rec.Body.Bytes()), etag)
+
+	var req = httptest.NewRequest(http.MethodGet, "/posts/hello", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/posts/hello", nil)
+	req.Header.Set(echo.HeaderIfModifiedSince, time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	// Other statuses are passed through without validators
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/posts/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"message":"not found"}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get("ETag"))
+}
+
+func TestResponseCache_MatchETag(t *testing.T) {
+	assert.True(t, middlewares.MatchETag(`W/"abc"`, `W/"abc"`))
+	assert.True(t, middlewares.MatchETag(`"abc"`, `W/"abc"`))
+	assert.True(t, middlewares.MatchETag(`"x", W/"abc"`, `W/"abc"`))
+	assert.True(t, middlewares.MatchETag(`*`, `W/"abc"`))
+	assert.False(t, middlewares.MatchETag(`W/"abd"`, `W/"abc"`))
+}
+
+func TestResponseCache_Key(t *testing.T) {
+	var e = echo.New()
+	var key = func(target string, language string) string {
+		var req = httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(models.LanguageKey, language)
+		var c = e.NewContext(req, httptest.NewRecorder())
+		c.SetPath("/posts")
+		return middlewares.GetResponseCacheKey(c)
+	}
+
+	assert.Equal(t, key("/posts?page=1&limit=10", "en"), key("/posts?limit=10&page=1", "en"))
+	assert.NotEqual(t, key("/posts?page=1", "en"), key("/posts?page=2", "en"))
+	assert.NotEqual(t, key("/posts?page=1", "en"), key("/posts?page=1", "vi"))
+}
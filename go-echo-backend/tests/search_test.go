@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch_TsQuery(t *testing.T) {
+	assert.Equal(t, "áo:*", repo.GetSearchTsQuery("Áo"))
+	assert.Equal(t, "iq & 123:*", repo.GetSearchTsQuery("IQ-123"))
+	assert.Equal(t, "hoodie & cotton & đen:*", repo.GetSearchTsQuery("  hoodie, cotton 'đen' "))
+	assert.Equal(t, "a & b & c & d & e & f & g & h:*", repo.GetSearchTsQuery("a b c d e f g h i j"))
+	assert.Equal(t, "", repo.GetSearchTsQuery("&|!:*()"))
+}
+
+func TestSearch_Groups(t *testing.T) {
+	var all = []enums.SearchGroup{
+		enums.SearchGroupUsers,
+		enums.SearchGroupInquiries,
+		enums.SearchGroupPurchaseOrders,
+		enums.SearchGroupBulkPurchaseOrders,
+		enums.SearchGroupProducts,
+		enums.SearchGroupInvoices,
+	}
+
+	assert.Equal(t, all, repo.GetSearchGroups(enums.RoleSuperAdmin, ""))
+	assert.Equal(t, all, repo.GetSearchGroups(enums.RoleLeader, enums.TeamSales))
+	assert.Equal(t, all, repo.GetSearchGroups(enums.RoleStaff, enums.TeamDev))
+
+	assert.NotContains(t, repo.GetSearchGroups(enums.RoleStaff, enums.TeamSales), enums.SearchGroupInvoices)
+	assert.Contains(t, repo.GetSearchGroups(enums.RoleStaff, enums.Finance), enums.SearchGroupInvoices)
+	assert.NotContains(t, repo.GetSearchGroups(enums.RoleStaff, enums.TeamDesigner), enums.SearchGroupUsers)
+
+	assert.Empty(t, repo.GetSearchGroups(enums.RoleStaff, ""))
+	assert.Empty(t, repo.GetSearchGroups(enums.RoleClient, ""))
+	assert.Empty(t, repo.GetSearchGroups(enums.RoleSeller, ""))
+}
+
+func TestSearch_ListKeywordUsesSearchIndexes(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+	var claims = *models.NewJwtClaimsInfo().SetRole(enums.RoleSuperAdmin).SetUserID("admin_1")
+	var pagination = models.PaginationParams{Keyword: "áo thun"}
+
+	var lists = map[string]func(){
+		"PaginateInquiry": func() {
+			repo.NewInquiryRepo(adb).PaginateInquiry(repo.PaginateInquiryParams{PaginationParams: pagination, JwtClaimsInfo: claims})
+		},
+		"PaginateProducts": func() {
+			repo.NewProductRepo(adb).PaginateProducts(repo.PaginateProductParams{PaginationParams: pagination, JwtClaimsInfo: claims})
+		},
+		"InquirySellerAllocationSearchSeller": func() {
+			repo.NewInquirySellerRepo(adb).InquirySellerAllocationSearchSeller(repo.InquirySellerAllocationSearchSellerParams{PaginationParams: pagination, JwtClaimsInfo: claims})
+		},
+		"PaginatePaymentTransactions": func() {
+			repo.NewPaymentTransactionRepo(adb).PaginatePaymentTransactions(repo.PaginatePaymentTransactionsParams{PaginationParams: pagination, JwtClaimsInfo: claims})
+		},
+	}
+
+	for name, list := range lists {
+		sqlRecorder.reset()
+		list()
+
+		var queries = sqlRecorder.reset()
+		assert.NotEmpty(t, queries, name)
+		for _, q := range queries {
+			assert.NotContains(t, q, "ILIKE", name)
+		}
+		assert.True(t, lo.SomeBy(queries, func(q string) bool {
+			return strings.Contains(q, "to_tsvector('inflow_search', search_text(") && strings.Contains(q, "<%")
+		}), name)
+	}
+}
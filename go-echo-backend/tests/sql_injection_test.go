@@ -0,0 +1,303 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo"
+	"github.com/engineeringinflow/inflow-backend/pkg/repo/query/queryfunc"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+const sqlInjectionMarker = "zzinj"
+
+var sqlInjectionPayloads = []string{
+	sqlInjectionMarker + "' OR '1'='1",
+	sqlInjectionMarker + "%' OR 1=1 --",
+	sqlInjectionMarker + "'; DROP TABLE users; --",
+	sqlInjectionMarker + " */ SELECT pg_sleep(10) /*",
+	sqlInjectionMarker + `\' UNION SELECT password FROM users --`,
+	sqlInjectionMarker + "{{.Role}}",
+}
+
+// sqlInjectionListMethods list endpoints which are not named Paginate*
+var sqlInjectionListMethods = map[string]bool{
+	"GetChatRoomList": true,
+	"TopCategories":   true,
+}
+
+// recorderDriver records the statements sent to the database and returns no rows
+type recorderDriver struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+type recorderConn struct {
+	driver *recorderDriver
+}
+
+type recorderRows struct{}
+
+var sqlRecorder = &recorderDriver{}
+var registerSQLRecorder sync.Once
+
+func (d *recorderDriver) Open(name string) (driver.Conn, error) {
+	return &recorderConn{driver: d}, nil
+}
+
+func (d *recorderDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+}
+
+func (d *recorderDriver) reset() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var queries = d.queries
+	d.queries = nil
+	return queries
+}
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *recorderConn) Close() error {
+	return nil
+}
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *recorderConn) Commit() error {
+	return nil
+}
+
+func (c *recorderConn) Rollback() error {
+	return nil
+}
+
+func (c *recorderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query)
+	return &recorderRows{}, nil
+}
+
+func (c *recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query)
+	return driver.RowsAffected(0), nil
+}
+
+func (r *recorderRows) Columns() []string {
+	return []string{}
+}
+
+func (r *recorderRows) Close() error {
+	return nil
+}
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+func newSQLRecorderDB(t *testing.T) *db.DB {
+	registerSQLRecorder.Do(func() {
+		logger.Init(logger.WithDebug(false))
+		sql.Register("sql_recorder", sqlRecorder)
+	})
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DriverName: "sql_recorder", DSN: "recorder"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormLogger.Discard,
+	})
+	assert.NoError(t, err)
+
+	return &db.DB{DB: gormDB, CustomLogger: logger.New("tests/sql_injection")}
+}
+
+// setKeyword sets the keyword fields of the params, embedded structs included
+func setKeyword(value reflect.Value, keyword string) bool {
+	var found bool
+	for i := 0; i < value.NumField(); i++ {
+		var field = value.Field(i)
+		var structField = value.Type().Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			found = setKeyword(field, keyword) || found
+		case field.Kind() == reflect.String && strings.Contains(strings.ToLower(structField.Name), "keyword"):
+			field.SetString(keyword)
+			found = true
+		}
+	}
+
+	return found
+}
+
+// callListMethod calls the method with params holding the keyword, the panics of the missing services are ignored
+func callListMethod(method reflect.Value, keyword string) (called bool) {
+	var paramType = method.Type().In(0)
+	var params = reflect.New(paramType).Elem()
+	if paramType.Kind() == reflect.Ptr {
+		params = reflect.New(paramType.Elem())
+	}
+
+	var structValue = params
+	if paramType.Kind() == reflect.Ptr {
+		structValue = params.Elem()
+	}
+	if structValue.Kind() != reflect.Struct || !setKeyword(structValue, keyword) {
+		return false
+	}
+
+	defer func() {
+		_ = recover()
+	}()
+
+	method.Call([]reflect.Value{params})
+	return true
+}
+
+func TestSQLInjection_PaginateKeyword(t *testing.T) {
+	var adb = newSQLRecorderDB(t)
+
+	var repos = []interface{}{
+		repo.NewAdsVideoRepo(adb),
+		repo.NewAnalyticsRepo(adb),
+		repo.NewAsFeaturedInRepo(adb),
+		repo.NewBankReconciliationRepo(adb),
+		repo.NewBlogCategoryRepo(adb),
+		repo.NewBulkPurchaseOrderRepo(adb),
+		repo.NewBulkPurchaseOrderTrackingRepo(adb),
+		repo.NewCatalogCartRepo(adb),
+		repo.NewCategoryRepo(adb),
+		repo.NewChatRoomRepo(adb),
+		repo.NewCmsNotificationRepo(adb),
+		repo.NewCollectionRepo(adb),
+		repo.NewCommentRepo(adb),
+		repo.NewDataAnalyticRepo(adb).WithDB(adb),
+		repo.NewDeadLetterTaskRepo(adb),
+		repo.NewFabricCollectionRepo(adb),
+		repo.NewFabricRepo(adb),
+		repo.NewFactoryTourRepo(adb),
+		repo.NewAnalyticGrowingTagRepo(adb),
+		repo.NewInquiryBuyerRepo(adb),
+		repo.NewInquiryRepo(adb),
+		repo.NewInquirySellerRepo(adb),
+		repo.NewInvoiceRepo(adb),
+		repo.NewLedgerRepo(adb),
+		repo.NewOutboxRepo(adb),
+		repo.NewPaymentTransactionRepo(adb),
+		repo.NewPostRepo(adb),
+		repo.NewProductAttributeRepo(adb),
+		repo.NewProductRepo(adb),
+		repo.NewProductReviewRepo(adb),
+		repo.NewProductTrending(adb).WithDB(adb),
+		repo.NewProductTypesPriceRepo(adb),
+		repo.NewPurchaseOrderRepo(adb),
+		repo.NewPurchaseOrderTrackingRepo(adb),
+		repo.NewPushTokenRepo(adb),
+		repo.NewRWDFabricPriceRepo(adb),
+		repo.NewReleaseNoteRepo(adb),
+		repo.NewSellerBulkPurchaseOrderRepo(adb),
+		repo.NewSettingSEORepo(adb),
+		repo.NewSettingSizeRepo(adb),
+		repo.NewSettingTaxRepo(adb),
+		repo.NewSubscriberRepo(adb),
+		repo.NewSysNotificationRepo(adb),
+		repo.NewTNARepo(adb),
+		repo.NewTrendingRepo(adb),
+		repo.NewUserNotificationRepo(adb),
+		repo.NewUserRepo(adb),
+		repo.NewVariantRepo(adb),
+	}
+
+	var exercised int
+	for _, r := range repos {
+		var value = reflect.ValueOf(r)
+		for i := 0; i < value.NumMethod(); i++ {
+			var method = value.Type().Method(i)
+			if !strings.HasPrefix(method.Name, "Paginate") && !sqlInjectionListMethods[method.Name] {
+				continue
+			}
+			if method.Type.NumIn() != 2 {
+				continue
+			}
+
+			var name = value.Type().Elem().Name() + "." + method.Name
+			var queried bool
+			for _, payload := range sqlInjectionPayloads {
+				sqlRecorder.reset()
+				if !callListMethod(value.Method(i), payload) {
+					break
+				}
+
+				var queries = sqlRecorder.reset()
+				queried = queried || len(queries) > 0
+				for _, q := range queries {
+					assert.NotContains(t, q, sqlInjectionMarker, "%s interpolates the keyword %q", name, payload)
+				}
+			}
+			if queried {
+				exercised++
+			}
+		}
+	}
+
+	assert.GreaterOrEqual(t, exercised, 50)
+}
+
+func TestSQLInjection_TemplateParam(t *testing.T) {
+	var builder = queryfunc.NewChatRoomBuilder(queryfunc.ChatRoomBuilderOptions{
+		ReferenceIDKeyWord: "50%_off' --",
+	})
+
+	assert.NotContains(t, builder.GetRawSQL(), "50%")
+	assert.Contains(t, builder.GetRawSQL(), "ILIKE @reference_id_keyword")
+	assert.Equal(t, `%50\%\_off' --%`, builder.GetNamedArgs()["reference_id_keyword"])
+}
+
+func TestSQLInjection_UnsafeTemplate(t *testing.T) {
+	var unsafe = []string{
+		`SELECT * FROM users WHERE name = '{{ .Keyword }}'`,
+		`SELECT * FROM users WHERE name ILIKE '{{ Contains .Keyword }}'`,
+		`SELECT * FROM users {{ if .Keyword }}WHERE name = '{{ .Keyword }}'{{ end }}`,
+		`SELECT * FROM users {{ range .Keywords }}{{ . }}{{ end }}`,
+		`SELECT * FROM users WHERE name = '{{ printf "%s" .Keyword }}'`,
+	}
+	for _, rawSQL := range unsafe {
+		assert.Panics(t, func() {
+			queryfunc.NewBuilder(rawSQL).WithOptions(map[string]interface{}{"Keyword": "x", "Keywords": []string{"x"}})
+		}, rawSQL)
+	}
+
+	var builder = queryfunc.NewBuilder(`SELECT /* {{Description}} */ * FROM users {{ if .Keyword }}WHERE name = {{ Param "name" .Keyword }}{{ end }}`).
+		WithOptions(map[string]interface{}{"Keyword": "' OR 1=1"}, template.FuncMap{
+			"Description": func() string {
+				return queryfunc.SQLComment("comment */ DROP TABLE users /*")
+			},
+		})
+	assert.Equal(t, "SELECT /* comment  DROP TABLE users  */ * FROM users WHERE name = @name", builder.GetRawSQL())
+	assert.Equal(t, map[string]interface{}{"name": "' OR 1=1"}, builder.GetNamedArgs())
+}
+
+func TestSQLInjection_SafeIdentifiers(t *testing.T) {
+	assert.Equal(t, []string{"category"}, queryfunc.SafeIdentifiers([]string{"category", "1; DROP TABLE products", "sub_category--"}, queryfunc.RankDACategoryColumns...))
+	assert.Empty(t, queryfunc.SafeIdentifiers([]string{"rev desc; --"}, "sold", "rev"))
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/db"
+	"github.com/engineeringinflow/inflow-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// stubDriver a recorderDriver which returns the stubbed rows to the queries containing their match
+type stubDriver struct {
+	recorderDriver
+	stubs []sqlStubRows
+}
+
+// sqlStubRows rows returned to the queries containing match
+type sqlStubRows struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+type stubConn struct {
+	recorderConn
+	driver *stubDriver
+}
+
+type stubRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var sqlStub = &stubDriver{}
+var registerSQLStub sync.Once
+
+func (d *stubDriver) Open(name string) (driver.Conn, error) {
+	return &stubConn{recorderConn: recorderConn{driver: &d.recorderDriver}, driver: d}, nil
+}
+
+// reset returns the recorded queries and drops the stubs
+func (d *stubDriver) reset() []string {
+	var queries = d.recorderDriver.reset()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stubs = nil
+	return queries
+}
+
+// stub returns the rows to the next queries containing match, the first matching stub wins
+func (d *stubDriver) stub(match string, columns []string, rows ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stubs = append(d.stubs, sqlStubRows{match: match, columns: columns, rows: rows})
+}
+
+func (d *stubDriver) stubbedRows(query string) *stubRows {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, stub := range d.stubs {
+		if strings.Contains(query, stub.match) {
+			return &stubRows{columns: stub.columns, rows: append([][]driver.Value{}, stub.rows...)}
+		}
+	}
+	return &stubRows{}
+}
+
+func (c *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query)
+	return c.driver.stubbedRows(query), nil
+}
+
+func (r *stubRows) Columns() []string {
+	return r.columns
+}
+
+func (r *stubRows) Close() error {
+	return nil
+}
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// newSQLStubDB db recording the statements like newSQLRecorderDB, with the rows stubbed through sqlStub
+func newSQLStubDB(t *testing.T) *db.DB {
+	registerSQLStub.Do(func() {
+		logger.Init(logger.WithDebug(false))
+		sql.Register("sql_stub", sqlStub)
+	})
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DriverName: "sql_stub", DSN: "stub"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormLogger.Discard,
+	})
+	assert.NoError(t, err)
+
+	return &db.DB{DB: gormDB, CustomLogger: logger.New("tests/sql_stub")}
+}
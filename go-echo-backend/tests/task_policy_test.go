@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/helper"
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+)
+
+type policyTestTask struct{}
+
+func (task policyTestTask) Policy() worker.TaskPolicy {
+	return worker.TaskPolicy{
+		Queue:   worker.QueueNameLow,
+		Timeout: time.Minute,
+		Unique:  time.Minute * 5,
+		Retry: &worker.RetryPolicy{
+			MaxRetry: 7,
+		},
+	}
+}
+
+func TestTaskPolicy_EnqueueOptions(t *testing.T) {
+	var types = func(options []asynq.Option) map[asynq.OptionType]interface{} {
+		var result = map[asynq.OptionType]interface{}{}
+		for _, option := range options {
+			result[option.Type()] = option.Value()
+		}
+		return result
+	}
+
+	var defaults = types(worker.GetTaskPolicy(struct{}{}).EnqueueOptions())
+	assert.Equal(t, 3, defaults[asynq.MaxRetryOpt])
+	assert.Equal(t, time.Hour*24, defaults[asynq.RetentionOpt])
+	assert.NotContains(t, defaults, asynq.QueueOpt)
+
+	var options = types(worker.GetTaskPolicy(policyTestTask{}).EnqueueOptions())
+	assert.Equal(t, 7, options[asynq.MaxRetryOpt])
+	assert.Equal(t, "low", options[asynq.QueueOpt])
+	assert.Equal(t, time.Minute, options[asynq.TimeoutOpt])
+	assert.NotContains(t, options, asynq.UniqueOpt, "the envelope changes on every enqueue, the task ID dedupes instead")
+}
+
+func TestTaskPolicy_Unique(t *testing.T) {
+	var addr = os.Getenv("REDIS_ADDRESS")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+
+	var rdb = redis.NewClient(&redis.Options{Addr: addr, DialTimeout: time.Millisecond * 200})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("redis %s is not reachable: %v", addr, err)
+	}
+
+	var client = asynq.NewClient(asynq.RedisClientOpt{Addr: addr})
+	defer client.Close()
+
+	var task = worker.NewTaskType("task_policy_unique_test", 1, func(ctx context.Context, payload map[string]string) error {
+		return nil
+	}, worker.WithPolicy(policyTestTask{}.Policy()))
+	var payload = map[string]string{"id": helper.GenerateXID()}
+
+	var enqueue = func(payload map[string]string) error {
+		data, err := task.NewPayload(context.Background(), payload)
+		if err != nil {
+			return err
+		}
+		_, err = client.Enqueue(asynq.NewTask(task.TaskName(), data), append(task.EnqueueOptions(task.TaskName(), data), asynq.ProcessIn(time.Hour))...)
+		return err
+	}
+
+	// The envelopes differ by trace ID and enqueue time, the same payload is still rejected
+	assert.NoError(t, enqueue(payload))
+	assert.ErrorIs(t, enqueue(payload), asynq.ErrTaskIDConflict)
+	assert.NoError(t, enqueue(map[string]string{"id": helper.GenerateXID()}))
+}
+
+func TestTaskPolicy_NonRetryable(t *testing.T) {
+	var errPermanent = errors.New("permanent")
+	var policy = worker.TaskPolicy{
+		Retry: &worker.RetryPolicy{
+			NonRetryable: []error{errPermanent},
+			IsNonRetryable: func(err error) bool {
+				return err.Error() == "bad request"
+			},
+		},
+	}
+
+	assert.True(t, policy.IsNonRetryable(eris.Wrap(errPermanent, "sync")))
+	assert.True(t, policy.IsNonRetryable(errors.New("bad request")))
+	assert.True(t, policy.IsNonRetryable(fmt.Errorf("%w: %w", worker.ErrNonRetryable, errors.New("invalid payload"))))
+	assert.False(t, policy.IsNonRetryable(errors.New("timeout")))
+	assert.False(t, worker.TaskPolicy{}.IsNonRetryable(errPermanent))
+	assert.False(t, worker.IsThrottled(errPermanent))
+}
+
+func TestTaskPolicy_Backoff(t *testing.T) {
+	var backoff = worker.ExponentialBackoff(time.Second, time.Minute)
+	for n := 0; n < 20; n++ {
+		var delay = backoff(n)
+		assert.LessOrEqual(t, delay, time.Minute)
+		assert.GreaterOrEqual(t, delay, time.Millisecond*500)
+	}
+
+	assert.Equal(t, time.Second*5, worker.FixedBackoff(time.Second*5)(3))
+}
+
+func TestTaskPolicy_TaskEnqueueOptions(t *testing.T) {
+	var task = worker.NewTaskType("task_policy_test", 1, func(ctx context.Context, payload map[string]string) error {
+		return nil
+	}, worker.WithPolicy(policyTestTask{}.Policy()))
+
+	var w = &worker.Worker{}
+	w.RegisterTasks(task)
+
+	// Relayed and replayed tasks are enqueued by name with the policy of the registered task
+	var options = map[asynq.OptionType]interface{}{}
+	for _, option := range w.TaskEnqueueOptions(task.TaskName(), asynq.TaskID("outbox_1"), asynq.Queue(worker.QueueNameHigh)) {
+		options[option.Type()] = option.Value()
+	}
+	assert.Equal(t, 7, options[asynq.MaxRetryOpt])
+	assert.Equal(t, time.Minute, options[asynq.TimeoutOpt])
+	assert.Equal(t, "outbox_1", options[asynq.TaskIDOpt])
+	assert.Equal(t, "high", options[asynq.QueueOpt], "the options of the caller win")
+
+	options = map[asynq.OptionType]interface{}{}
+	for _, option := range w.TaskEnqueueOptions("unregistered_task") {
+		options[option.Type()] = option.Value()
+	}
+	assert.Equal(t, 3, options[asynq.MaxRetryOpt])
+}
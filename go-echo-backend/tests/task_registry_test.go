@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryTestPayloadV2 struct {
+	BuyerID string `json:"buyer_id"`
+	Note    string `json:"note"`
+}
+
+func newRegistryTestTask() *worker.TaskType[registryTestPayloadV2] {
+	return worker.NewTaskType("registry_test", 2, func(ctx context.Context, payload registryTestPayloadV2) error {
+		return nil
+	}, worker.WithUpcaster(1, worker.RenameField("user_id", "buyer_id")))
+}
+
+func TestTaskRegistry_LegacyPayload(t *testing.T) {
+	var task = newRegistryTestTask()
+
+	envelope, payload, err := task.Decode([]byte(`{"user_id":"u1","note":"hello"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, envelope.Version)
+	assert.Equal(t, registryTestPayloadV2{BuyerID: "u1", Note: "hello"}, payload)
+}
+
+func TestTaskRegistry_Envelope(t *testing.T) {
+	var task = newRegistryTestTask()
+
+	data, err := task.NewPayload(worker.WithTraceID(context.Background(), "trace-1"), registryTestPayloadV2{BuyerID: "b1"})
+	assert.NoError(t, err)
+
+	envelope, payload, err := task.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, envelope.Version)
+	assert.Equal(t, "trace-1", envelope.TraceID)
+	assert.NotZero(t, envelope.EnqueuedAt)
+	assert.Equal(t, "b1", payload.BuyerID)
+}
+
+func TestTaskRegistry_UpcastEnvelope(t *testing.T) {
+	var v1 = worker.NewTaskType("registry_test", 1, func(ctx context.Context, payload map[string]string) error {
+		return nil
+	})
+	data, err := v1.NewPayload(context.Background(), map[string]string{"user_id": "u2"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, worker.DecodeEnvelope(data).TraceID)
+
+	_, payload, err := newRegistryTestTask().Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "u2", payload.BuyerID)
+
+	// A consumer of an older version must not drop the newer payloads
+	data, err = newRegistryTestTask().NewPayload(context.Background(), registryTestPayloadV2{BuyerID: "b2"})
+	assert.NoError(t, err)
+	_, _, err = v1.Decode(data)
+	assert.Error(t, err)
+}
+
+func TestTaskRegistry_MissingUpcaster(t *testing.T) {
+	assert.Panics(t, func() {
+		worker.NewTaskType("registry_test", 3, func(ctx context.Context, payload registryTestPayloadV2) error {
+			return nil
+		}, worker.WithUpcaster(1, worker.RenameField("user_id", "buyer_id")))
+	})
+}
+
+func TestTaskRegistry_RenameField(t *testing.T) {
+	data, err := worker.RenameField("a", "b")(json.RawMessage(`{"a":1,"c":2}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"b":1,"c":2}`, string(data))
+
+	data, err = worker.RenameField("a", "b")(json.RawMessage(`{"a":1,"b":3}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"b":3}`, string(data))
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/go-utils/values"
+)
+
+const tnaDay = int64(86400)
+
+func newTNA(id string, from, to int64, dependencies ...string) *models.TNA {
+	return &models.TNA{
+		Model:        models.Model{ID: id},
+		ReferenceID:  "BPO-1",
+		DateFrom:     from * tnaDay,
+		DateTo:       to * tnaDay,
+		Dependencies: dependencies,
+	}
+}
+
+func TestTNAPlan_CriticalPath(t *testing.T) {
+	// fabric(0-10) -> cutting(10-12) -> sewing(12-20) -> packing(20-22)
+	// trims(0-5) -> sewing, labels(0-3) has no successor
+	var tnas = models.TNAs{
+		newTNA("sewing", 12, 20, "cutting", "trims"),
+		newTNA("fabric", 0, 10),
+		newTNA("packing", 20, 22, "sewing"),
+		newTNA("cutting", 10, 12, "fabric"),
+		newTNA("trims", 0, 5),
+		newTNA("labels", 0, 3),
+	}
+
+	plan, err := models.NewTNAPlan("BPO-1", tnas, values.Int64(25*tnaDay))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fabric", "cutting", "sewing", "packing"}, plan.CriticalPath)
+	assert.Equal(t, 22*tnaDay, plan.ProjectedFinishAt)
+	assert.Empty(t, plan.ShiftedTNAIDs)
+	assert.False(t, plan.IsSlipped())
+
+	var float = map[string]int64{}
+	for _, item := range plan.Items {
+		float[item.ID] = item.TotalFloat / tnaDay
+	}
+	assert.Equal(t, int64(7), float["trims"])
+	assert.Equal(t, int64(19), float["labels"])
+	assert.Equal(t, int64(0), float["sewing"])
+}
+
+func TestTNAPlan_ShiftDependents(t *testing.T) {
+	// fabric slipped 5 days past cutting's start
+	var tnas = models.TNAs{
+		newTNA("fabric", 0, 15),
+		newTNA("cutting", 10, 12, "fabric"),
+		newTNA("sewing", 14, 20, "cutting"),
+		newTNA("packing", 20, 22, "sewing"),
+	}
+
+	plan, err := models.NewTNAPlan("BPO-1", tnas, values.Int64(25*tnaDay))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cutting", "sewing", "packing"}, plan.ShiftedTNAIDs)
+
+	var dates = map[string][2]int64{}
+	for _, item := range plan.Items {
+		dates[item.ID] = [2]int64{item.DateFrom / tnaDay, item.DateTo / tnaDay}
+	}
+	assert.Equal(t, [2]int64{15, 17}, dates["cutting"])
+	// The 2 days gap planned before sewing absorbs part of the delay
+	assert.Equal(t, [2]int64{17, 23}, dates["sewing"])
+	assert.Equal(t, [2]int64{23, 25}, dates["packing"])
+	assert.Equal(t, 25*tnaDay, plan.ProjectedFinishAt)
+	assert.False(t, plan.IsSlipped())
+
+	plan, err = models.NewTNAPlan("BPO-1", models.TNAs{newTNA("fabric", 0, 27)}, values.Int64(25*tnaDay))
+	assert.NoError(t, err)
+	assert.True(t, plan.IsSlipped())
+	assert.Equal(t, 2, plan.SlippageDays)
+}
+
+func TestTNAPlan_Validate(t *testing.T) {
+	_, err := models.TNAs{
+		newTNA("a", 0, 1, "c"),
+		newTNA("b", 1, 2, "a"),
+		newTNA("c", 2, 3, "b"),
+		newTNA("d", 0, 1),
+	}.SortTopologically()
+	assert.True(t, eris.Is(err, errs.ErrTNADependencyCycle))
+
+	_, err = models.TNAs{newTNA("a", 0, 1, "a")}.SortTopologically()
+	assert.True(t, eris.Is(err, errs.ErrTNADependencyCycle))
+
+	_, err = models.TNAs{newTNA("a", 0, 1, "missing")}.SortTopologically()
+	assert.True(t, eris.Is(err, errs.ErrTNADependencyInvalid))
+}
+
+func TestTNAPlan_AssigneeIDs(t *testing.T) {
+	var plan = models.TNAPlan{
+		PicIDs: []string{"pic"},
+		Items: models.TNAs{
+			{AssigneeIDs: []string{"u1", "pic"}},
+			{AssigneeIDs: []string{"u2", ""}},
+		},
+	}
+	assert.Equal(t, []string{"pic", "u1", "u2"}, plan.GetAssigneeIDs())
+}
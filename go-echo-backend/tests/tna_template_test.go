@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+)
+
+func knitTeeTemplate() *models.TNATemplate {
+	return &models.TNATemplate{
+		Name:      "Knit tee bulk, 45 days",
+		OrderType: enums.InquiryTypeBulk,
+		Items: models.TNATemplateItems{
+			{Key: "fabric", Title: "Fabric booking", DurationDays: 20},
+			{Key: "trims", Title: "Trims", DurationDays: 10},
+			{Key: "pps", Title: "PPS approval", DurationDays: 5, Dependencies: []string{"fabric"}},
+			{Key: "sewing", Title: "Sewing", DurationDays: 14, Dependencies: []string{"pps", "trims"}},
+			{Key: "final_qc", Title: "Final QC", OffsetDays: 3, DurationDays: 3, Dependencies: []string{"sewing"}},
+			{Key: "ex_factory", Title: "Ex-factory", DurationDays: 0, Dependencies: []string{"final_qc"}},
+		},
+	}
+}
+
+func TestTNATemplate_NewTNAs(t *testing.T) {
+	var anchor = 100 * tnaDay
+	tnas, err := knitTeeTemplate().NewTNAs("BPO-1", anchor)
+	assert.NoError(t, err)
+	assert.Len(t, tnas, 6)
+
+	var byTitle = map[string]*models.TNA{}
+	for _, tna := range tnas {
+		assert.Equal(t, "BPO-1", tna.ReferenceID)
+		assert.Equal(t, enums.InquiryTypeBulk, tna.OrderType)
+		assert.NotEmpty(t, tna.ID)
+		byTitle[tna.Title] = tna
+	}
+
+	var days = func(title string) [2]int64 {
+		return [2]int64{byTitle[title].DateFrom / tnaDay, byTitle[title].DateTo / tnaDay}
+	}
+	assert.Equal(t, [2]int64{100, 100}, days("Ex-factory"))
+	assert.Equal(t, [2]int64{94, 97}, days("Final QC"))
+	assert.Equal(t, [2]int64{80, 94}, days("Sewing"))
+	assert.Equal(t, [2]int64{75, 80}, days("PPS approval"))
+	assert.Equal(t, [2]int64{55, 75}, days("Fabric booking"))
+	assert.Equal(t, [2]int64{70, 80}, days("Trims"))
+
+	// Dependencies point at the generated IDs
+	assert.Equal(t, []string{byTitle["PPS approval"].ID, byTitle["Trims"].ID}, []string(byTitle["Sewing"].Dependencies))
+
+	plan, err := models.NewTNAPlan("BPO-1", tnas, &anchor)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.ShiftedTNAIDs)
+	assert.Equal(t, anchor, plan.ProjectedFinishAt)
+
+	spanDays, err := knitTeeTemplate().GetSpanDays()
+	assert.NoError(t, err)
+	assert.Equal(t, 45, spanDays)
+}
+
+func TestTNATemplate_Validate(t *testing.T) {
+	var template = knitTeeTemplate()
+	template.Items[0].Dependencies = []string{"ex_factory"}
+	_, err := template.NewTNAs("BPO-1", 0)
+	assert.True(t, eris.Is(err, errs.ErrTNATemplateInvalid))
+
+	template = knitTeeTemplate()
+	template.Items[1].Key = "fabric"
+	_, err = template.NewTNAs("BPO-1", 0)
+	assert.True(t, eris.Is(err, errs.ErrTNATemplateInvalid))
+
+	template = knitTeeTemplate()
+	template.Items[2].Dependencies = []string{"unknown"}
+	_, err = template.NewTNAs("BPO-1", 0)
+	assert.True(t, eris.Is(err, errs.ErrTNATemplateInvalid))
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/ws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWSHub_Envelope(t *testing.T) {
+	var message = ws.Message{Type: ws.MessageTypeChat, UserID: "u1"}
+	var envelope = ws.NewEnvelope("node1", []string{"u1", "", "u2", "u1"}, message.ToJSONRaw())
+	assert.Equal(t, []string{"u1", "u2"}, envelope.UserIDs)
+
+	var decoded ws.Envelope
+	assert.NoError(t, json.Unmarshal(envelope.ToJSONRaw(), &decoded))
+	assert.Equal(t, "node1", decoded.NodeID)
+	assert.Equal(t, envelope.UserIDs, decoded.UserIDs)
+	// Delivered to the sessions as is
+	assert.JSONEq(t, string(message.ToJSONRaw()), string(decoded.Data))
+}
+
+func TestWSHub_PresenceConnection(t *testing.T) {
+	var conn = ws.PresenceConnection{UserID: "u1", ConnectionID: "node1__conn1"}
+
+	parsed, ok := ws.ParsePresenceConnection(conn.String())
+	assert.True(t, ok)
+	assert.Equal(t, conn, parsed)
+
+	_, ok = ws.ParsePresenceConnection("u1")
+	assert.False(t, ok)
+	_, ok = ws.ParsePresenceConnection("__conn1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/ws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWSSSE_Cursor(t *testing.T) {
+	var cursor = map[string]int64{
+		"notifications:u1": 4,
+		"inbox:u1":         12,
+		"inquiry:iq1":      0,
+	}
+	var id = ws.EncodeSSECursor(cursor)
+	assert.Equal(t, "inbox:u1=12;inquiry:iq1=0;notifications:u1=4", id)
+	assert.Equal(t, cursor, ws.ParseSSECursor(id))
+
+	assert.Empty(t, ws.ParseSSECursor(""))
+	assert.Equal(t, map[string]int64{"inbox:u1": 3}, ws.ParseSSECursor("inbox:u1=3;broken;=5;inquiry:iq1=x;po:1=-1"))
+}
+
+func TestWSSSE_FormatEvent(t *testing.T) {
+	assert.Equal(t, "id: inbox:u1=1\ndata: {\"type\":\"receive_message\"}\n\n", string(ws.FormatSSEEvent("inbox:u1=1", []byte(`{"type":"receive_message"}`))))
+	assert.Equal(t, "data: a\ndata: b\n\n", string(ws.FormatSSEEvent("", []byte("a\nb"))))
+}
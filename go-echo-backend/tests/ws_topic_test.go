@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/engineeringinflow/inflow-backend/pkg/errs"
+	"github.com/engineeringinflow/inflow-backend/pkg/models"
+	"github.com/engineeringinflow/inflow-backend/pkg/models/enums"
+	"github.com/engineeringinflow/inflow-backend/pkg/ws"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWSTopic_Parse(t *testing.T) {
+	topic, err := models.ParseTopic("bulk_purchase_order:bpo1", "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, enums.TopicKindBulkPurchaseOrder, topic.Kind)
+	assert.Equal(t, "bpo1", topic.ID)
+	assert.Equal(t, "bulk_purchase_order:bpo1", topic.String())
+
+	topic, err = models.ParseTopic("notifications", "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications:u1", topic.String())
+
+	_, err = models.ParseTopic("inquiry:", "u1")
+	assert.True(t, eris.Is(err, errs.ErrTopicInvalid))
+
+	_, err = models.ParseTopic("user:u2", "u1")
+	assert.True(t, eris.Is(err, errs.ErrTopicInvalid))
+}
+
+func TestWSTopic_Event(t *testing.T) {
+	var event = models.NewTopicEvent(models.NewTopic(enums.TopicKindInquiry, "iq1"), enums.TopicEventTypeAuditCreated, map[string]string{"inquiry_id": "iq1"})
+	assert.Equal(t, "publish_topic_event", event.TaskName())
+
+	var decoded models.TopicEvent
+	assert.NoError(t, json.Unmarshal(event.GetPayload(), &decoded))
+	assert.Equal(t, "inquiry:iq1", decoded.Topic)
+	assert.JSONEq(t, `{"inquiry_id":"iq1"}`, string(decoded.Data))
+}
+
+func TestWSTopic_CheckResume(t *testing.T) {
+	// Up to date
+	assert.True(t, ws.CheckResume(10, 10, 0))
+	// Missed events 11 and 12, both retained
+	assert.True(t, ws.CheckResume(10, 12, 11))
+	// Event 11 was trimmed
+	assert.False(t, ws.CheckResume(10, 12, 12))
+	// Events expired
+	assert.False(t, ws.CheckResume(10, 12, 0))
+	// The sequence was reset
+	assert.False(t, ws.CheckResume(10, 3, 1))
+}